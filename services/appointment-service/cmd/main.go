@@ -0,0 +1,185 @@
+// Entry point aplikasi
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/joho/godotenv"
+
+	"appointment-service/internal/config"
+	"appointment-service/internal/database"
+	"appointment-service/internal/handler"
+	"appointment-service/internal/middleware"
+	"appointment-service/internal/patients"
+	"appointment-service/internal/reminder"
+	"appointment-service/internal/repository"
+	"appointment-service/internal/service"
+	"appointment-service/internal/tenant"
+)
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	// Load configuration
+	cfg := config.Load()
+
+	loc, err := time.LoadLocation(cfg.Schedules.Timezone)
+	if err != nil {
+		log.Fatalf("Invalid SCHEDULE_TIMEZONE: %v", err)
+	}
+
+	// Initialize database
+	db, err := database.NewConnection(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	tenants, err := tenant.Load(cfg.Tenants.File, cfg.Tenants.Default)
+	if err != nil {
+		log.Fatalf("Failed to load tenants: %v", err)
+	}
+
+	// Initialize validator
+	validate := validator.New()
+
+	// Initialize repositories
+	scheduleRepo := repository.NewScheduleRepository(db)
+	slotRepo := repository.NewSlotRepository(db)
+	appointmentRepo := repository.NewAppointmentRepository(db)
+
+	// Initialize services
+	patientClient := patients.NewClient(cfg.Patients.URL, cfg.Patients.Timeout)
+	scheduleService := service.NewScheduleService(scheduleRepo, slotRepo, loc, cfg.Schedules.MaxGenerateDays)
+	appointmentService := service.NewAppointmentService(appointmentRepo, slotRepo, patientClient)
+
+	// Background workers stop when ctx is cancelled on shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notifier, err := newReminderNotifier(cfg.Reminders)
+	if err != nil {
+		log.Fatalf("Failed to configure reminders: %v", err)
+	}
+	if notifier != nil && len(cfg.Reminders.LeadTimes) > 0 {
+		worker := reminder.NewWorker(appointmentRepo, notifier, cfg.Reminders.LeadTimes, cfg.Reminders.PollInterval)
+		go worker.Run(ctx)
+	}
+
+	// Initialize Fiber app
+	app := fiber.New(fiber.Config{
+		ErrorHandler: customErrorHandler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	})
+
+	// Global middleware
+	app.Use(recover.New())
+	app.Use(middleware.Logger())
+	app.Use(middleware.CORS())
+	app.Use(middleware.Metrics())
+
+	// Health check endpoint
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"status":  "healthy",
+			"service": "appointment-service",
+			"version": cfg.App.Version,
+		})
+	})
+
+	// API routes
+	api := app.Group("/api/v1")
+
+	// Protected routes
+	protected := api.Group("/", middleware.JWTAuth(cfg.JWT.Secret, tenants))
+
+	// Schedule and slot routes; practitioners' hours are managed by admins
+	scheduleHandler := handler.NewScheduleHandler(scheduleService, validate, loc)
+	protected.Post("/schedules", middleware.RequireRole("admin"), scheduleHandler.CreateSchedule)
+	protected.Get("/schedules", scheduleHandler.ListSchedules)
+	protected.Get("/schedules/:id", scheduleHandler.GetSchedule)
+	protected.Delete("/schedules/:id", middleware.RequireRole("admin"), scheduleHandler.DeleteSchedule)
+	protected.Post("/slots/generate", middleware.RequireRole("admin"), scheduleHandler.GenerateSlots)
+	protected.Get("/slots", scheduleHandler.ListSlots)
+	protected.Post("/slots/:id/block", middleware.RequireRole("admin"), scheduleHandler.BlockSlot)
+	protected.Post("/slots/:id/unblock", middleware.RequireRole("admin"), scheduleHandler.UnblockSlot)
+
+	// Appointment routes
+	appointmentHandler := handler.NewAppointmentHandler(appointmentService, validate, loc)
+	protected.Post("/appointments", appointmentHandler.BookAppointment)
+	protected.Get("/appointments", appointmentHandler.ListAppointments)
+	protected.Get("/appointments/:id", appointmentHandler.GetAppointment)
+	protected.Post("/appointments/:id/cancel", appointmentHandler.CancelAppointment)
+	protected.Post("/appointments/:id/reschedule", appointmentHandler.RescheduleAppointment)
+	protected.Post("/appointments/:id/no-show", middleware.RequireRole("admin", "doctor", "nurse", "registration"), appointmentHandler.MarkNoShow)
+
+	// Metrics endpoint (untuk Prometheus)
+	app.Get("/metrics", middleware.PrometheusHandler())
+
+	// Graceful shutdown
+	go func() {
+		if err := app.Listen(":" + cfg.App.Port); err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+	cancel()
+	if err := app.Shutdown(); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+
+	log.Println("Server exited")
+}
+
+// newReminderNotifier returns the configured notifier, or nil for "none".
+func newReminderNotifier(cfg config.ReminderConfig) (reminder.Notifier, error) {
+	switch cfg.Notifier {
+	case "none", "":
+		return nil, nil
+	case "log":
+		return reminder.LogNotifier{}, nil
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("REMINDER_WEBHOOK_URL is required for webhook reminders")
+		}
+		return reminder.NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookSecret, cfg.Timeout), nil
+	}
+	return nil, fmt.Errorf("unknown REMINDER_NOTIFIER %q", cfg.Notifier)
+}
+
+func customErrorHandler(c *fiber.Ctx, err error) error {
+	code := fiber.StatusInternalServerError
+	message := "Internal Server Error"
+
+	if e, ok := err.(*fiber.Error); ok {
+		code = e.Code
+		message = e.Message
+	}
+
+	return c.Status(code).JSON(fiber.Map{
+		"error": fiber.Map{
+			"message": message,
+			"code":    code,
+		},
+	})
+}
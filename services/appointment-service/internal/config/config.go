@@ -0,0 +1,146 @@
+// Konfigurasi aplikasi
+// internal/config/config.go
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	App       AppConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Patients  PatientServiceConfig
+	Schedules ScheduleConfig
+	Reminders ReminderConfig
+	Tenants   TenantConfig
+}
+
+type AppConfig struct {
+	Name    string
+	Version string
+	Port    string
+	Env     string
+}
+
+type DatabaseConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+}
+
+type JWTConfig struct {
+	Secret     string // shared with patient-service, whose tokens are accepted
+	ExpireTime int    // in hours
+}
+
+// PatientServiceConfig locates patient-service, which is asked whether a
+// patient exists before an appointment is booked
+type PatientServiceConfig struct {
+	URL     string
+	Timeout time.Duration
+}
+
+type ScheduleConfig struct {
+	Timezone        string // of schedule hours, e.g. Asia/Jakarta
+	MaxGenerateDays int    // longest range slots are generated for in one request
+}
+
+type ReminderConfig struct {
+	Notifier      string          // none, log or webhook
+	LeadTimes     []time.Duration // before the start of an appointment, e.g. 24h,2h
+	PollInterval  time.Duration
+	WebhookURL    string
+	WebhookSecret string // signs webhook reminders; see the README
+	Timeout       time.Duration
+}
+
+// TenantConfig configures the hospitals and clinics sharing the service
+type TenantConfig struct {
+	File    string // JSON list of tenants; empty configures the default tenant only
+	Default string // tenant of tokens without a tenant_id claim
+}
+
+func Load() *Config {
+	return &Config{
+		App: AppConfig{
+			Name:    getEnv("APP_NAME", "appointment-service"),
+			Version: getEnv("APP_VERSION", "1.0.0"),
+			Port:    getEnv("APP_PORT", "3002"),
+			Env:     getEnv("APP_ENV", "development"),
+		},
+		Database: DatabaseConfig{
+			Host:     getEnv("DB_HOST", "localhost"),
+			Port:     getEnv("DB_PORT", "1433"),
+			User:     getEnv("DB_USER", "sa"),
+			Password: getEnv("DB_PASSWORD", "YourStrong@Passw0rd"),
+			DBName:   getEnv("DB_NAME", "hospital_appointment_db"),
+			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+		},
+		JWT: JWTConfig{
+			Secret:     getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			ExpireTime: getEnvAsInt("JWT_EXPIRE_HOURS", 24),
+		},
+		Patients: PatientServiceConfig{
+			URL:     strings.TrimRight(getEnv("PATIENT_SERVICE_URL", "http://localhost:3001"), "/"),
+			Timeout: time.Duration(getEnvAsInt("PATIENT_SERVICE_TIMEOUT_SECONDS", 5)) * time.Second,
+		},
+		Schedules: ScheduleConfig{
+			Timezone:        getEnv("SCHEDULE_TIMEZONE", "Asia/Jakarta"),
+			MaxGenerateDays: getEnvAsInt("SLOT_MAX_GENERATE_DAYS", 90),
+		},
+		Reminders: ReminderConfig{
+			Notifier:      getEnv("REMINDER_NOTIFIER", "log"),
+			LeadTimes:     getEnvAsDurations("REMINDER_LEAD_TIMES", []time.Duration{24 * time.Hour, 2 * time.Hour}),
+			PollInterval:  time.Duration(getEnvAsInt("REMINDER_POLL_SECONDS", 60)) * time.Second,
+			WebhookURL:    getEnv("REMINDER_WEBHOOK_URL", ""),
+			WebhookSecret: getEnv("REMINDER_WEBHOOK_SECRET", ""),
+			Timeout:       time.Duration(getEnvAsInt("REMINDER_TIMEOUT_SECONDS", 10)) * time.Second,
+		},
+		Tenants: TenantConfig{
+			File:    getEnv("TENANT_CONFIG_FILE", ""),
+			Default: getEnv("TENANT_DEFAULT", "default"),
+		},
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsDurations reads a comma separated list of durations such as
+// "24h,2h"; an invalid list falls back to the default.
+func getEnvAsDurations(key string, defaultValue []time.Duration) []time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var durations []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || d <= 0 {
+			return defaultValue
+		}
+		durations = append(durations, d)
+	}
+	return durations
+}
@@ -0,0 +1,154 @@
+// Database connection
+// internal/database/connection.go
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"appointment-service/internal/config"
+
+	_ "github.com/denisenkom/go-mssqldb"
+)
+
+func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
+	// Connection string untuk SQL Server
+	connString := fmt.Sprintf("server=%s;port=%s;user id=%s;password=%s;database=%s;encrypt=disable",
+		cfg.Host,
+		cfg.Port,
+		cfg.User,
+		cfg.Password,
+		cfg.DBName,
+	)
+
+	db, err := sql.Open("sqlserver", connString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Configure connection pool
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	// Test connection
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	// Create tables if not exists
+	if err := createTables(db); err != nil {
+		return nil, fmt.Errorf("failed to create tables: %w", err)
+	}
+
+	return db, nil
+}
+
+func createTables(db *sql.DB) error {
+	// Each statement runs in its own batch so later statements can reference
+	// columns added by earlier ones.
+	for _, query := range migrations {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var migrations = []string{
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='schedules' AND xtype='U')
+	CREATE TABLE schedules (
+		id NVARCHAR(50) PRIMARY KEY,
+		tenant_id NVARCHAR(50) NOT NULL,
+		practitioner_id NVARCHAR(50) NOT NULL,
+		day_of_week TINYINT NOT NULL,
+		start_time NCHAR(5) NOT NULL,
+		end_time NCHAR(5) NOT NULL,
+		slot_minutes INT NOT NULL,
+		location NVARCHAR(100),
+		valid_from DATE NOT NULL,
+		valid_until DATE,
+		is_active BIT DEFAULT 1,
+		created_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE(),
+		updated_at DATETIME2 DEFAULT GETDATE()
+	)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='idx_schedules_practitioner')
+	CREATE INDEX idx_schedules_practitioner ON schedules(tenant_id, practitioner_id, day_of_week)
+	`,
+	// Slots of a practitioner do not overlap, which makes generating slots
+	// for a range again harmless; the unique start time backs that up
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='slots' AND xtype='U')
+	CREATE TABLE slots (
+		id NVARCHAR(50) PRIMARY KEY,
+		tenant_id NVARCHAR(50) NOT NULL,
+		practitioner_id NVARCHAR(50) NOT NULL,
+		schedule_id NVARCHAR(50),
+		start_at DATETIMEOFFSET NOT NULL,
+		end_at DATETIMEOFFSET NOT NULL,
+		location NVARCHAR(100),
+		status NVARCHAR(20) NOT NULL DEFAULT 'free',
+		created_at DATETIME2 DEFAULT GETDATE(),
+		updated_at DATETIME2 DEFAULT GETDATE(),
+		CONSTRAINT uq_slots_practitioner_start UNIQUE (tenant_id, practitioner_id, start_at)
+	)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='appointments' AND xtype='U')
+	CREATE TABLE appointments (
+		id NVARCHAR(50) PRIMARY KEY,
+		tenant_id NVARCHAR(50) NOT NULL,
+		patient_id NVARCHAR(50) NOT NULL,
+		practitioner_id NVARCHAR(50) NOT NULL,
+		slot_id NVARCHAR(50) NOT NULL,
+		start_at DATETIMEOFFSET NOT NULL,
+		end_at DATETIMEOFFSET NOT NULL,
+		location NVARCHAR(100),
+		reason NVARCHAR(500),
+		status NVARCHAR(20) NOT NULL,
+		booked_at DATETIMEOFFSET NOT NULL,
+		reschedule_count INT NOT NULL DEFAULT 0,
+		cancelled_at DATETIMEOFFSET,
+		cancelled_by NVARCHAR(50),
+		cancellation_reason NVARCHAR(500),
+		no_show_at DATETIMEOFFSET,
+		no_show_recorded_by NVARCHAR(50),
+		created_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE(),
+		updated_by NVARCHAR(50),
+		updated_at DATETIME2 DEFAULT GETDATE()
+	)
+	`,
+	// Slots are claimed with a conditional update before the appointment is
+	// written; the index backs that up so a slot never has two bookings
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='uq_appointments_booked_slot')
+	CREATE UNIQUE INDEX uq_appointments_booked_slot ON appointments(slot_id) WHERE status = 'booked'
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='idx_appointments_patient')
+	CREATE INDEX idx_appointments_patient ON appointments(tenant_id, patient_id, start_at)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='idx_appointments_practitioner')
+	CREATE INDEX idx_appointments_practitioner ON appointments(tenant_id, practitioner_id, start_at)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='idx_appointments_status_start')
+	CREATE INDEX idx_appointments_status_start ON appointments(status, start_at)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='appointment_reminders' AND xtype='U')
+	CREATE TABLE appointment_reminders (
+		appointment_id NVARCHAR(50) NOT NULL,
+		kind NVARCHAR(20) NOT NULL,
+		sent_at DATETIME2 DEFAULT GETDATE(),
+		CONSTRAINT pk_appointment_reminders PRIMARY KEY (appointment_id, kind)
+	)
+	`,
+}
@@ -0,0 +1,78 @@
+// Appointments
+// internal/domain/appointment.go
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// Appointment statuses
+const (
+	AppointmentBooked    = "booked"
+	AppointmentCancelled = "cancelled"
+	AppointmentNoShow    = "no_show"
+)
+
+// Appointment is a patient's booking of a practitioner's slot. Cancelling
+// frees the slot; rescheduling moves the appointment to another slot.
+type Appointment struct {
+	ID             string
+	TenantID       string
+	PatientID      string // in patient-service
+	PractitionerID string
+	SlotID         string
+	StartAt        time.Time // copied from the slot
+	EndAt          time.Time
+	Location       string
+	Reason         string
+	Status         string
+
+	// BookedAt is when the appointment was booked or last rescheduled;
+	// reminders due before it are not sent
+	BookedAt        time.Time
+	RescheduleCount int
+
+	CancelledAt        *time.Time
+	CancelledBy        string
+	CancellationReason string
+	NoShowAt           *time.Time
+	NoShowRecordedBy   string
+
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+// IsOpen reports whether the appointment is still booked.
+func (a *Appointment) IsOpen() bool {
+	return a.Status == AppointmentBooked
+}
+
+// AppointmentFilter selects appointments. Empty fields match all.
+type AppointmentFilter struct {
+	PatientID      string
+	PractitionerID string
+	Status         string
+	From           *time.Time // start_at on or after
+	To             *time.Time // start_at before
+	Page           int
+	Limit          int
+}
+
+// Reminder is a notice of an upcoming appointment, sent once per lead time.
+type Reminder struct {
+	Appointment *Appointment
+	Kind        string        // see ReminderKind
+	LeadTime    time.Duration // before the appointment's start
+}
+
+// ReminderKind names the reminder sent lead before an appointment, e.g.
+// "24h" or "90m".
+func ReminderKind(lead time.Duration) string {
+	if lead%time.Hour == 0 {
+		return strconv.Itoa(int(lead/time.Hour)) + "h"
+	}
+	return strconv.Itoa(int(lead/time.Minute)) + "m"
+}
@@ -0,0 +1,54 @@
+// Custom error types
+// internal/domain/errors.go
+package domain
+
+import "errors"
+
+var (
+	// Schedule and slot errors
+	ErrScheduleNotFound = errors.New("schedule not found")
+	ErrScheduleOverlap  = errors.New("schedule overlaps an active schedule")
+	ErrSlotNotFound     = errors.New("slot not found")
+	ErrSlotUnavailable  = errors.New("slot is not free")
+	ErrSlotBooked       = errors.New("slot is booked")
+
+	// Appointment errors
+	ErrAppointmentNotFound = errors.New("appointment not found")
+	ErrAppointmentClosed   = errors.New("appointment is cancelled or marked no-show")
+	ErrAppointmentStarted  = errors.New("appointment has already started")
+	ErrAppointmentUpcoming = errors.New("appointment has not started yet")
+	ErrPatientDoubleBooked = errors.New("patient has another appointment at that time")
+
+	// Patient errors, as reported by patient-service
+	ErrPatientNotFound       = errors.New("patient not found")
+	ErrPatientDeceased       = errors.New("patient is deceased")
+	ErrPatientServiceFailure = errors.New("patient-service is unavailable")
+
+	// Tenant errors
+	ErrTenantRequired = errors.New("no tenant in context")
+
+	// General errors
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrInternalServerError = errors.New("internal server error")
+)
+
+// CustomError untuk error yang lebih detail
+type CustomError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
+}
+
+func (e *CustomError) Error() string {
+	return e.Message
+}
+
+func NewCustomError(code, message, details string) *CustomError {
+	return &CustomError{
+		Code:    code,
+		Message: message,
+		Details: details,
+	}
+}
@@ -0,0 +1,161 @@
+// Practitioner schedules and slots
+// internal/domain/schedule.go
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// clockLayout is the layout of schedule hours.
+const clockLayout = "15:04"
+
+// Schedule is the hours a practitioner sees patients on a day of the week,
+// divided into slots of equal length.
+type Schedule struct {
+	ID             string
+	TenantID       string
+	PractitionerID string
+	DayOfWeek      time.Weekday
+	StartTime      string // HH:MM, local time of the configured timezone
+	EndTime        string
+	SlotMinutes    int
+	Location       string // e.g. the clinic or room
+
+	ValidFrom  time.Time  // date
+	ValidUntil *time.Time // date, inclusive; nil when open-ended
+	IsActive   bool
+
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Validate checks the schedule's values.
+func (s *Schedule) Validate() error {
+	start, err := time.Parse(clockLayout, s.StartTime)
+	if err != nil {
+		return NewCustomError("INVALID_TIME", "start_time must be in HH:MM format", "")
+	}
+	end, err := time.Parse(clockLayout, s.EndTime)
+	if err != nil {
+		return NewCustomError("INVALID_TIME", "end_time must be in HH:MM format", "")
+	}
+	if !end.After(start) {
+		return NewCustomError("INVALID_TIME", "end_time must be after start_time", "")
+	}
+	if s.SlotMinutes <= 0 || end.Sub(start) < time.Duration(s.SlotMinutes)*time.Minute {
+		return NewCustomError("INVALID_SLOT_LENGTH", "slot_minutes must fit between start_time and end_time", "")
+	}
+	if s.ValidUntil != nil && s.ValidUntil.Before(s.ValidFrom) {
+		return NewCustomError("INVALID_VALIDITY", "valid_until must not be before valid_from", "")
+	}
+	return nil
+}
+
+// Overlaps reports whether both schedules can give slots at the same time:
+// they fall on the same day of the week, their hours overlap and so do their
+// validity periods.
+func (s *Schedule) Overlaps(other *Schedule) bool {
+	if s.PractitionerID != other.PractitionerID || s.DayOfWeek != other.DayOfWeek {
+		return false
+	}
+	start, _ := time.Parse(clockLayout, s.StartTime)
+	end, _ := time.Parse(clockLayout, s.EndTime)
+	otherStart, _ := time.Parse(clockLayout, other.StartTime)
+	otherEnd, _ := time.Parse(clockLayout, other.EndTime)
+	if !start.Before(otherEnd) || !otherStart.Before(end) {
+		return false
+	}
+	if s.ValidUntil != nil && civilDate(*s.ValidUntil).Before(civilDate(other.ValidFrom)) {
+		return false
+	}
+	return other.ValidUntil == nil || !civilDate(*other.ValidUntil).Before(civilDate(s.ValidFrom))
+}
+
+// appliesOn reports whether the schedule covers the date.
+func (s *Schedule) appliesOn(date time.Time) bool {
+	day := civilDate(date)
+	if !s.IsActive || date.Weekday() != s.DayOfWeek || day.Before(civilDate(s.ValidFrom)) {
+		return false
+	}
+	return s.ValidUntil == nil || !day.After(civilDate(*s.ValidUntil))
+}
+
+// Slots returns the schedule's slots on the dates from from to to, both
+// included, in the location's local time. A trailing period shorter than a
+// slot is left out.
+func (s *Schedule) Slots(from, to time.Time, loc *time.Location) []*Slot {
+	start, _ := time.Parse(clockLayout, s.StartTime)
+	end, _ := time.Parse(clockLayout, s.EndTime)
+	length := time.Duration(s.SlotMinutes) * time.Minute
+
+	var slots []*Slot
+	for day := civilDate(from); !day.After(civilDate(to)); day = day.AddDate(0, 0, 1) {
+		if !s.appliesOn(day) {
+			continue
+		}
+		open := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+		closing := time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+		for at := open; !at.Add(length).After(closing); at = at.Add(length) {
+			slots = append(slots, &Slot{
+				TenantID:       s.TenantID,
+				PractitionerID: s.PractitionerID,
+				ScheduleID:     s.ID,
+				StartAt:        at,
+				EndAt:          at.Add(length),
+				Location:       s.Location,
+				Status:         SlotFree,
+			})
+		}
+	}
+	return slots
+}
+
+// civilDate drops the time of day, keeping the calendar date.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Slot statuses
+const (
+	SlotFree    = "free"
+	SlotBooked  = "booked"
+	SlotBlocked = "blocked" // taken out of booking, e.g. for leave
+)
+
+// Slot is a bookable period of a practitioner. A practitioner's slots do not
+// overlap, so generating slots twice is harmless.
+type Slot struct {
+	ID             string
+	TenantID       string
+	PractitionerID string
+	ScheduleID     string
+	StartAt        time.Time
+	EndAt          time.Time
+	Location       string
+	Status         string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// SlotFilter selects slots of a practitioner.
+type SlotFilter struct {
+	PractitionerID string
+	From           time.Time
+	To             time.Time
+	Status         string // empty for all statuses
+	Limit          int
+}
+
+// ParseWeekday parses a day name such as "monday" or "Mon".
+func ParseWeekday(name string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := d.String()
+		if strings.EqualFold(name, full) || strings.EqualFold(name, full[:3]) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day of week %q", name)
+}
@@ -0,0 +1,50 @@
+// internal/dto/request.go
+package dto
+
+type CreateScheduleRequest struct {
+	PractitionerID string `json:"practitioner_id" validate:"required,max=50"`
+	DayOfWeek      string `json:"day_of_week" validate:"required,oneof=sunday monday tuesday wednesday thursday friday saturday"`
+	StartTime      string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime        string `json:"end_time" validate:"required,datetime=15:04"`
+	SlotMinutes    int    `json:"slot_minutes" validate:"required,min=5,max=480"`
+	Location       string `json:"location" validate:"max=100"`
+	ValidFrom      string `json:"valid_from" validate:"omitempty,datetime=2006-01-02"`
+	ValidUntil     string `json:"valid_until" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type GenerateSlotsRequest struct {
+	PractitionerID string `json:"practitioner_id" validate:"required,max=50"`
+	From           string `json:"from" validate:"required,datetime=2006-01-02"`
+	To             string `json:"to" validate:"required,datetime=2006-01-02"`
+}
+
+type ListSlotsRequest struct {
+	PractitionerID string `query:"practitioner_id" validate:"required,max=50"`
+	From           string `query:"from" validate:"required,datetime=2006-01-02"`
+	To             string `query:"to" validate:"required,datetime=2006-01-02"`
+	Status         string `query:"status" validate:"omitempty,oneof=free booked blocked"`
+}
+
+type BookAppointmentRequest struct {
+	PatientID string `json:"patient_id" validate:"required,max=50"`
+	SlotID    string `json:"slot_id" validate:"required,max=50"`
+	Reason    string `json:"reason" validate:"max=500"`
+}
+
+type ListAppointmentsRequest struct {
+	PatientID      string `query:"patient_id" validate:"max=50"`
+	PractitionerID string `query:"practitioner_id" validate:"max=50"`
+	Status         string `query:"status" validate:"omitempty,oneof=booked cancelled no_show"`
+	From           string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To             string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Page           int    `query:"page" validate:"omitempty,min=1"`
+	Limit          int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type CancelAppointmentRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+type RescheduleAppointmentRequest struct {
+	SlotID string `json:"slot_id" validate:"required,max=50"`
+}
@@ -0,0 +1,190 @@
+// internal/dto/response.go
+package dto
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"appointment-service/internal/domain"
+)
+
+type ScheduleResponse struct {
+	ID             string    `json:"id"`
+	PractitionerID string    `json:"practitioner_id"`
+	DayOfWeek      string    `json:"day_of_week"`
+	StartTime      string    `json:"start_time"`
+	EndTime        string    `json:"end_time"`
+	SlotMinutes    int       `json:"slot_minutes"`
+	Location       string    `json:"location"`
+	ValidFrom      string    `json:"valid_from"`
+	ValidUntil     string    `json:"valid_until,omitempty"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type ListSchedulesResponse struct {
+	Data []*ScheduleResponse `json:"data"`
+}
+
+type SlotResponse struct {
+	ID             string    `json:"id"`
+	PractitionerID string    `json:"practitioner_id"`
+	ScheduleID     string    `json:"schedule_id,omitempty"`
+	StartAt        time.Time `json:"start_at"`
+	EndAt          time.Time `json:"end_at"`
+	Location       string    `json:"location"`
+	Status         string    `json:"status"`
+}
+
+type ListSlotsResponse struct {
+	Data []*SlotResponse `json:"data"`
+}
+
+type GenerateSlotsResponse struct {
+	Created int `json:"created"` // slots that did not exist yet
+}
+
+type AppointmentResponse struct {
+	ID                 string     `json:"id"`
+	PatientID          string     `json:"patient_id"`
+	PractitionerID     string     `json:"practitioner_id"`
+	SlotID             string     `json:"slot_id"`
+	StartAt            time.Time  `json:"start_at"`
+	EndAt              time.Time  `json:"end_at"`
+	Location           string     `json:"location"`
+	Reason             string     `json:"reason"`
+	Status             string     `json:"status"`
+	RescheduleCount    int        `json:"reschedule_count"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty"`
+	CancelledBy        string     `json:"cancelled_by,omitempty"`
+	CancellationReason string     `json:"cancellation_reason,omitempty"`
+	NoShowAt           *time.Time `json:"no_show_at,omitempty"`
+	NoShowRecordedBy   string     `json:"no_show_recorded_by,omitempty"`
+	CreatedBy          string     `json:"created_by"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+type ListAppointmentsResponse struct {
+	Data       []*AppointmentResponse `json:"data"`
+	Pagination PaginationResponse     `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Page       int `json:"page"`
+	Limit      int `json:"limit"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
+}
+
+type SuccessResponse struct {
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Converter functions
+func ToScheduleDomain(req *CreateScheduleRequest) *domain.Schedule {
+	day, _ := domain.ParseWeekday(req.DayOfWeek)
+	schedule := &domain.Schedule{
+		PractitionerID: req.PractitionerID,
+		DayOfWeek:      day,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		SlotMinutes:    req.SlotMinutes,
+		Location:       req.Location,
+	}
+	if from, err := time.Parse("2006-01-02", req.ValidFrom); err == nil {
+		schedule.ValidFrom = from
+	} else {
+		now := time.Now()
+		schedule.ValidFrom = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if until, err := time.Parse("2006-01-02", req.ValidUntil); err == nil {
+		schedule.ValidUntil = &until
+	}
+	return schedule
+}
+
+func ToScheduleResponse(schedule *domain.Schedule) *ScheduleResponse {
+	resp := &ScheduleResponse{
+		ID:             schedule.ID,
+		PractitionerID: schedule.PractitionerID,
+		DayOfWeek:      strings.ToLower(schedule.DayOfWeek.String()),
+		StartTime:      schedule.StartTime,
+		EndTime:        schedule.EndTime,
+		SlotMinutes:    schedule.SlotMinutes,
+		Location:       schedule.Location,
+		ValidFrom:      schedule.ValidFrom.Format("2006-01-02"),
+		IsActive:       schedule.IsActive,
+		CreatedAt:      schedule.CreatedAt,
+		UpdatedAt:      schedule.UpdatedAt,
+	}
+	if schedule.ValidUntil != nil {
+		resp.ValidUntil = schedule.ValidUntil.Format("2006-01-02")
+	}
+	return resp
+}
+
+func ToSlotResponse(slot *domain.Slot) *SlotResponse {
+	return &SlotResponse{
+		ID:             slot.ID,
+		PractitionerID: slot.PractitionerID,
+		ScheduleID:     slot.ScheduleID,
+		StartAt:        slot.StartAt,
+		EndAt:          slot.EndAt,
+		Location:       slot.Location,
+		Status:         slot.Status,
+	}
+}
+
+func ToAppointmentDomain(req *BookAppointmentRequest) *domain.Appointment {
+	return &domain.Appointment{
+		PatientID: req.PatientID,
+		SlotID:    req.SlotID,
+		Reason:    req.Reason,
+	}
+}
+
+func ToAppointmentResponse(appointment *domain.Appointment) *AppointmentResponse {
+	return &AppointmentResponse{
+		ID:                 appointment.ID,
+		PatientID:          appointment.PatientID,
+		PractitionerID:     appointment.PractitionerID,
+		SlotID:             appointment.SlotID,
+		StartAt:            appointment.StartAt,
+		EndAt:              appointment.EndAt,
+		Location:           appointment.Location,
+		Reason:             appointment.Reason,
+		Status:             appointment.Status,
+		RescheduleCount:    appointment.RescheduleCount,
+		CancelledAt:        appointment.CancelledAt,
+		CancelledBy:        appointment.CancelledBy,
+		CancellationReason: appointment.CancellationReason,
+		NoShowAt:           appointment.NoShowAt,
+		NoShowRecordedBy:   appointment.NoShowRecordedBy,
+		CreatedBy:          appointment.CreatedBy,
+		CreatedAt:          appointment.CreatedAt,
+		UpdatedAt:          appointment.UpdatedAt,
+	}
+}
+
+func NewPaginationResponse(page, limit, total int) PaginationResponse {
+	return PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}
+}
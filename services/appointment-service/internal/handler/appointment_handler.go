@@ -0,0 +1,280 @@
+// Appointment handlers
+// internal/handler/appointment_handler.go
+package handler
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"appointment-service/internal/domain"
+	"appointment-service/internal/dto"
+	"appointment-service/internal/service"
+	"appointment-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AppointmentHandler struct {
+	appointmentService service.AppointmentService
+	validator          *validator.Validate
+	loc                *time.Location
+}
+
+// NewAppointmentHandler creates the handler; date filters are dates of loc.
+func NewAppointmentHandler(appointmentService service.AppointmentService, validator *validator.Validate, loc *time.Location) *AppointmentHandler {
+	return &AppointmentHandler{
+		appointmentService: appointmentService,
+		validator:          validator,
+		loc:                loc,
+	}
+}
+
+// BookAppointment godoc
+// @Summary Book an appointment
+// @Description Book a free slot for a patient. The patient is looked up in patient-service with the caller's token; unknown, deleted and deceased patients cannot be booked. Of concurrent bookings of a slot, one succeeds and the others get 409.
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.BookAppointmentRequest true "Patient and slot"
+// @Success 201 {object} dto.AppointmentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 502 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/appointments [post]
+func (h *AppointmentHandler) BookAppointment(c *fiber.Ctx) error {
+	var req dto.BookAppointmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	appointment := dto.ToAppointmentDomain(&req)
+	appointment.CreatedBy = c.Locals("userID").(string)
+
+	booked, err := h.appointmentService.BookAppointment(c.Context(), appointment)
+	if err != nil {
+		return h.error(c, err, "BOOK_FAILED", "Failed to book appointment")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToAppointmentResponse(booked))
+}
+
+// ListAppointments godoc
+// @Summary List appointments
+// @Description Appointments in start order, filtered by patient, practitioner, status and start date.
+// @Tags appointments
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param patient_id query string false "Patient ID"
+// @Param practitioner_id query string false "Practitioner ID"
+// @Param status query string false "booked, cancelled or no_show"
+// @Param from query string false "First start date (YYYY-MM-DD)"
+// @Param to query string false "Last start date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} dto.ListAppointmentsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/appointments [get]
+func (h *AppointmentHandler) ListAppointments(c *fiber.Ctx) error {
+	var req dto.ListAppointmentsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	filter := domain.AppointmentFilter{
+		PatientID:      req.PatientID,
+		PractitionerID: req.PractitionerID,
+		Status:         req.Status,
+		Page:           req.Page,
+		Limit:          req.Limit,
+	}
+	if req.From != "" {
+		from, _ := time.ParseInLocation("2006-01-02", req.From, h.loc)
+		filter.From = &from
+	}
+	if req.To != "" {
+		to, _ := time.ParseInLocation("2006-01-02", req.To, h.loc)
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	appointments, total, err := h.appointmentService.ListAppointments(c.Context(), filter)
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list appointments")
+	}
+
+	page, limit := filter.Page, filter.Limit
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	resp := dto.ListAppointmentsResponse{
+		Data:       make([]*dto.AppointmentResponse, 0, len(appointments)),
+		Pagination: dto.NewPaginationResponse(page, limit, total),
+	}
+	for _, appointment := range appointments {
+		resp.Data = append(resp.Data, dto.ToAppointmentResponse(appointment))
+	}
+	return c.JSON(resp)
+}
+
+// GetAppointment godoc
+// @Summary Get an appointment
+// @Tags appointments
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Appointment ID"
+// @Success 200 {object} dto.AppointmentResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/appointments/{id} [get]
+func (h *AppointmentHandler) GetAppointment(c *fiber.Ctx) error {
+	appointment, err := h.appointmentService.GetAppointment(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get appointment")
+	}
+	return c.JSON(dto.ToAppointmentResponse(appointment))
+}
+
+// CancelAppointment godoc
+// @Summary Cancel an appointment
+// @Description Cancel an appointment that has not started. Its slot becomes free again.
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Appointment ID"
+// @Param request body dto.CancelAppointmentRequest false "Reason"
+// @Success 200 {object} dto.AppointmentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/appointments/{id}/cancel [post]
+func (h *AppointmentHandler) CancelAppointment(c *fiber.Ctx) error {
+	var req dto.CancelAppointmentRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+		}
+	}
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	appointment, err := h.appointmentService.CancelAppointment(c.Context(), c.Params("id"),
+		c.Locals("userID").(string), req.Reason)
+	if err != nil {
+		return h.error(c, err, "CANCEL_FAILED", "Failed to cancel appointment")
+	}
+	return c.JSON(dto.ToAppointmentResponse(appointment))
+}
+
+// RescheduleAppointment godoc
+// @Summary Reschedule an appointment
+// @Description Move an appointment that has not started to another free slot, possibly of another practitioner. The old slot becomes free and reminders are sent again for the new time.
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Appointment ID"
+// @Param request body dto.RescheduleAppointmentRequest true "New slot"
+// @Success 200 {object} dto.AppointmentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/appointments/{id}/reschedule [post]
+func (h *AppointmentHandler) RescheduleAppointment(c *fiber.Ctx) error {
+	var req dto.RescheduleAppointmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	appointment, err := h.appointmentService.RescheduleAppointment(c.Context(), c.Params("id"), req.SlotID,
+		c.Locals("userID").(string))
+	if err != nil {
+		return h.error(c, err, "RESCHEDULE_FAILED", "Failed to reschedule appointment")
+	}
+	return c.JSON(dto.ToAppointmentResponse(appointment))
+}
+
+// MarkNoShow godoc
+// @Summary Mark an appointment no-show
+// @Description Record that the patient did not come. Only appointments that have started can be marked.
+// @Tags appointments
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Appointment ID"
+// @Success 200 {object} dto.AppointmentResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/appointments/{id}/no-show [post]
+func (h *AppointmentHandler) MarkNoShow(c *fiber.Ctx) error {
+	appointment, err := h.appointmentService.MarkNoShow(c.Context(), c.Params("id"), c.Locals("userID").(string))
+	if err != nil {
+		return h.error(c, err, "NO_SHOW_FAILED", "Failed to mark appointment no-show")
+	}
+	return c.JSON(dto.ToAppointmentResponse(appointment))
+}
+
+func (h *AppointmentHandler) error(c *fiber.Ctx, err error, code, message string) error {
+	switch err {
+	case domain.ErrAppointmentNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Appointment not found", "")
+	case domain.ErrSlotNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "SLOT_NOT_FOUND", "Slot not found", "")
+	case domain.ErrPatientNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "PATIENT_NOT_FOUND", "Patient not found", "")
+	case domain.ErrPatientDeceased:
+		return utils.ErrorResponse(c, fiber.StatusUnprocessableEntity, "PATIENT_DECEASED", "Patient is recorded as deceased", "")
+	case domain.ErrSlotUnavailable:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "SLOT_UNAVAILABLE", "Slot is booked, blocked or has started", "")
+	case domain.ErrPatientDoubleBooked:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "PATIENT_DOUBLE_BOOKED", "Patient has another appointment at that time", "")
+	case domain.ErrAppointmentClosed:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "APPOINTMENT_CLOSED", "Appointment is cancelled or marked no-show", "")
+	case domain.ErrAppointmentStarted:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "APPOINTMENT_STARTED", "Appointment has already started", "")
+	case domain.ErrAppointmentUpcoming:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "APPOINTMENT_UPCOMING", "Appointment has not started yet", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Appointment, patient and slot IDs are required", "")
+	}
+	if errors.Is(err, domain.ErrPatientServiceFailure) {
+		log.Printf("Patient lookup failed: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusBadGateway, "PATIENT_SERVICE_UNAVAILABLE", "Could not verify the patient", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, code, message, err.Error())
+}
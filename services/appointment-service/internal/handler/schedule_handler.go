@@ -0,0 +1,269 @@
+// Practitioner schedule and slot handlers
+// internal/handler/schedule_handler.go
+package handler
+
+import (
+	"time"
+
+	"appointment-service/internal/domain"
+	"appointment-service/internal/dto"
+	"appointment-service/internal/service"
+	"appointment-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ScheduleHandler struct {
+	scheduleService service.ScheduleService
+	validator       *validator.Validate
+	loc             *time.Location
+}
+
+// NewScheduleHandler creates the handler; dates in requests are dates of
+// loc, the timezone of schedule hours.
+func NewScheduleHandler(scheduleService service.ScheduleService, validator *validator.Validate, loc *time.Location) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+		validator:       validator,
+		loc:             loc,
+	}
+}
+
+// CreateSchedule godoc
+// @Summary Create a practitioner schedule
+// @Description Weekly hours a practitioner sees patients, divided into slots of slot_minutes. Slots are created with the generate endpoint. A schedule may not overlap an active schedule of the practitioner.
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.CreateScheduleRequest true "Schedule"
+// @Success 201 {object} dto.ScheduleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/schedules [post]
+func (h *ScheduleHandler) CreateSchedule(c *fiber.Ctx) error {
+	var req dto.CreateScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	schedule := dto.ToScheduleDomain(&req)
+	schedule.CreatedBy = c.Locals("userID").(string)
+
+	created, err := h.scheduleService.CreateSchedule(c.Context(), schedule)
+	if err != nil {
+		return h.error(c, err, "CREATE_FAILED", "Failed to create schedule")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToScheduleResponse(created))
+}
+
+// ListSchedules godoc
+// @Summary List a practitioner's schedules
+// @Tags schedules
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param practitioner_id query string true "Practitioner ID"
+// @Success 200 {object} dto.ListSchedulesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/schedules [get]
+func (h *ScheduleHandler) ListSchedules(c *fiber.Ctx) error {
+	schedules, err := h.scheduleService.ListSchedules(c.Context(), c.Query("practitioner_id"))
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list schedules")
+	}
+
+	resp := dto.ListSchedulesResponse{Data: make([]*dto.ScheduleResponse, 0, len(schedules))}
+	for _, schedule := range schedules {
+		resp.Data = append(resp.Data, dto.ToScheduleResponse(schedule))
+	}
+	return c.JSON(resp)
+}
+
+// GetSchedule godoc
+// @Summary Get a practitioner schedule
+// @Tags schedules
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} dto.ScheduleResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/schedules/{id} [get]
+func (h *ScheduleHandler) GetSchedule(c *fiber.Ctx) error {
+	schedule, err := h.scheduleService.GetSchedule(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get schedule")
+	}
+	return c.JSON(dto.ToScheduleResponse(schedule))
+}
+
+// DeleteSchedule godoc
+// @Summary Deactivate a practitioner schedule
+// @Description The schedule no longer generates slots. Slots generated before, and their appointments, are kept.
+// @Tags schedules
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/schedules/{id} [delete]
+func (h *ScheduleHandler) DeleteSchedule(c *fiber.Ctx) error {
+	if err := h.scheduleService.DeleteSchedule(c.Context(), c.Params("id")); err != nil {
+		return h.error(c, err, "DELETE_FAILED", "Failed to delete schedule")
+	}
+	return utils.SuccessResponse(c, "Schedule deactivated successfully", nil)
+}
+
+// GenerateSlots godoc
+// @Summary Generate a practitioner's slots
+// @Description Create the slots of the practitioner's active schedules on the dates from from to to, both included. Slots that exist already are kept, so a range may be generated again.
+// @Tags slots
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.GenerateSlotsRequest true "Practitioner and dates"
+// @Success 200 {object} dto.GenerateSlotsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/slots/generate [post]
+func (h *ScheduleHandler) GenerateSlots(c *fiber.Ctx) error {
+	var req dto.GenerateSlotsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	from, _ := time.ParseInLocation("2006-01-02", req.From, h.loc)
+	to, _ := time.ParseInLocation("2006-01-02", req.To, h.loc)
+	created, err := h.scheduleService.GenerateSlots(c.Context(), req.PractitionerID, from, to)
+	if err != nil {
+		return h.error(c, err, "GENERATE_FAILED", "Failed to generate slots")
+	}
+	return c.JSON(dto.GenerateSlotsResponse{Created: created})
+}
+
+// ListSlots godoc
+// @Summary List a practitioner's slots
+// @Description Slots starting on the dates from from to to, both included, in start order.
+// @Tags slots
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param practitioner_id query string true "Practitioner ID"
+// @Param from query string true "First date (YYYY-MM-DD)"
+// @Param to query string true "Last date (YYYY-MM-DD)"
+// @Param status query string false "free, booked or blocked"
+// @Success 200 {object} dto.ListSlotsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/slots [get]
+func (h *ScheduleHandler) ListSlots(c *fiber.Ctx) error {
+	var req dto.ListSlotsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	from, _ := time.ParseInLocation("2006-01-02", req.From, h.loc)
+	to, _ := time.ParseInLocation("2006-01-02", req.To, h.loc)
+	slots, err := h.scheduleService.ListSlots(c.Context(), domain.SlotFilter{
+		PractitionerID: req.PractitionerID,
+		From:           from,
+		To:             to.AddDate(0, 0, 1),
+		Status:         req.Status,
+	})
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list slots")
+	}
+
+	resp := dto.ListSlotsResponse{Data: make([]*dto.SlotResponse, 0, len(slots))}
+	for _, slot := range slots {
+		resp.Data = append(resp.Data, dto.ToSlotResponse(slot))
+	}
+	return c.JSON(resp)
+}
+
+// BlockSlot godoc
+// @Summary Block a slot
+// @Description Take a free slot out of booking, e.g. for leave.
+// @Tags slots
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Slot ID"
+// @Success 200 {object} dto.SlotResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/slots/{id}/block [post]
+func (h *ScheduleHandler) BlockSlot(c *fiber.Ctx) error {
+	slot, err := h.scheduleService.BlockSlot(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "BLOCK_FAILED", "Failed to block slot")
+	}
+	return c.JSON(dto.ToSlotResponse(slot))
+}
+
+// UnblockSlot godoc
+// @Summary Unblock a slot
+// @Tags slots
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Slot ID"
+// @Success 200 {object} dto.SlotResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/slots/{id}/unblock [post]
+func (h *ScheduleHandler) UnblockSlot(c *fiber.Ctx) error {
+	slot, err := h.scheduleService.UnblockSlot(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "UNBLOCK_FAILED", "Failed to unblock slot")
+	}
+	return c.JSON(dto.ToSlotResponse(slot))
+}
+
+func (h *ScheduleHandler) error(c *fiber.Ctx, err error, code, message string) error {
+	switch err {
+	case domain.ErrScheduleNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Schedule not found", "")
+	case domain.ErrScheduleOverlap:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "SCHEDULE_OVERLAP", "Schedule overlaps an active schedule of the practitioner", "")
+	case domain.ErrSlotNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Slot not found", "")
+	case domain.ErrSlotBooked:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "SLOT_BOOKED", "Slot is booked", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Schedule, slot or practitioner ID is required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, code, message, err.Error())
+}
@@ -0,0 +1,19 @@
+// CORS middleware
+// internal/middleware/cors.go
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+func CORS() fiber.Handler {
+	return cors.New(cors.Config{
+		AllowOrigins:     "http://localhost:3000, http://localhost:3001, http://localhost:3002, https://yourdomain.com",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowMethods:     "GET, POST, PUT, DELETE, OPTIONS",
+		AllowCredentials: true,
+		ExposeHeaders:    "Content-Length",
+		MaxAge:           86400,
+	})
+}
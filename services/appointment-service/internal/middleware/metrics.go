@@ -0,0 +1,68 @@
+// Prometheus metrics
+// internal/middleware/metrics.go
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/adaptor/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests",
+		},
+		[]string{"method", "endpoint", "status"},
+	)
+
+	httpRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of HTTP requests in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "endpoint"},
+	)
+
+	activeConnections = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "http_active_connections",
+			Help: "Number of active HTTP connections",
+		},
+	)
+)
+
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		// Increment active connections
+		activeConnections.Inc()
+		defer activeConnections.Dec()
+
+		// Process request
+		err := c.Next()
+
+		// Record metrics
+		duration := time.Since(start).Seconds()
+		status := strconv.Itoa(c.Response().StatusCode())
+		endpoint := c.Route().Path
+		method := c.Method()
+
+		httpRequestsTotal.WithLabelValues(method, endpoint, status).Inc()
+		httpRequestDuration.WithLabelValues(method, endpoint).Observe(duration)
+
+		return err
+	}
+}
+
+func PrometheusHandler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.Handler())
+}
@@ -0,0 +1,90 @@
+// patient-service client
+// internal/patients/client.go
+package patients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"appointment-service/internal/domain"
+)
+
+// tokenKey is the type of TokenKey.
+type tokenKey struct{}
+
+// TokenKey holds the caller's bearer token. Requests to patient-service are
+// made with it, so they are authorized for, and scoped to the tenant of,
+// the caller. The REST middleware stores it as a request local.
+var TokenKey = tokenKey{}
+
+// NewContext returns a context calling patient-service with the token.
+func NewContext(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, TokenKey, token)
+}
+
+// Client looks up patients in patient-service.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a client of the patient-service at baseURL, e.g.
+// http://patient-service:3001.
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// patient holds the fields of a patient the client asks for.
+type patient struct {
+	ID         string     `json:"id"`
+	IsActive   bool       `json:"is_active"`
+	DeceasedAt *time.Time `json:"deceased_at"`
+}
+
+// VerifyPatient returns nil if the patient exists and can be booked:
+// ErrPatientNotFound for unknown and deleted patients, ErrPatientDeceased
+// for deceased ones. Other failures wrap ErrPatientServiceFailure.
+func (c *Client) VerifyPatient(ctx context.Context, patientID string) error {
+	endpoint := c.baseURL + "/api/v1/patients/" + url.PathEscape(patientID) + "?fields=id,is_active,deceased_at"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrPatientServiceFailure, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if token, _ := ctx.Value(TokenKey).(string); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrPatientServiceFailure, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return domain.ErrPatientNotFound
+	default:
+		return fmt.Errorf("%w: status %d", domain.ErrPatientServiceFailure, resp.StatusCode)
+	}
+
+	var p patient
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return fmt.Errorf("%w: invalid response: %v", domain.ErrPatientServiceFailure, err)
+	}
+	if !p.IsActive {
+		return domain.ErrPatientNotFound
+	}
+	if p.DeceasedAt != nil {
+		return domain.ErrPatientDeceased
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package patients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"appointment-service/internal/domain"
+)
+
+func TestVerifyPatient(t *testing.T) {
+	var gotAuth, gotFields string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotFields = r.URL.Query().Get("fields")
+		switch r.URL.Path {
+		case "/api/v1/patients/p1":
+			w.Write([]byte(`{"id":"p1","is_active":true,"deceased_at":null}`))
+		case "/api/v1/patients/p2":
+			w.Write([]byte(`{"id":"p2","is_active":false,"deceased_at":null}`))
+		case "/api/v1/patients/p3":
+			w.Write([]byte(`{"id":"p3","is_active":true,"deceased_at":"2024-01-02T03:04:05Z"}`))
+		case "/api/v1/patients/p4":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Second)
+	ctx := NewContext(context.Background(), "token-1")
+
+	if err := client.VerifyPatient(ctx, "p1"); err != nil {
+		t.Fatalf("Expected p1 to be bookable, got %v", err)
+	}
+	if gotAuth != "Bearer token-1" {
+		t.Errorf("Expected the caller's token to be forwarded, got %q", gotAuth)
+	}
+	if gotFields != "id,is_active,deceased_at" {
+		t.Errorf("Unexpected fields %q", gotFields)
+	}
+
+	tests := []struct {
+		id   string
+		want error
+	}{
+		{"p2", domain.ErrPatientNotFound},
+		{"p3", domain.ErrPatientDeceased},
+		{"p4", domain.ErrPatientServiceFailure},
+		{"unknown", domain.ErrPatientNotFound},
+	}
+	for _, tt := range tests {
+		if err := client.VerifyPatient(ctx, tt.id); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.id, err, tt.want)
+		}
+	}
+}
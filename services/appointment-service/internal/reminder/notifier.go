@@ -0,0 +1,128 @@
+// Reminder notifiers
+// internal/reminder/notifier.go
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"appointment-service/internal/domain"
+)
+
+// Notifier sends appointment reminders, e.g. by SMS or to a messaging
+// service. A reminder that fails is sent again on a later poll while it is
+// still due, so notifiers may see a reminder more than once.
+type Notifier interface {
+	Notify(ctx context.Context, reminder *domain.Reminder) error
+}
+
+// LogNotifier writes reminders to the log, for development and for
+// deployments without a messaging service.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, reminder *domain.Reminder) error {
+	a := reminder.Appointment
+	log.Printf("Reminder %s: appointment %s of patient %s with practitioner %s at %s",
+		reminder.Kind, a.ID, a.PatientID, a.PractitionerID, a.StartAt.Format(time.RFC3339))
+	return nil
+}
+
+// Headers of webhook reminders. The signature is an HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the secret, as in patient-service's
+// webhooks, so receivers can verify both the same way.
+const (
+	HeaderID        = "Webhook-Id"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+)
+
+// WebhookNotifier posts reminders as JSON to a URL.
+type WebhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url, secret string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// webhookPayload is the body of a webhook reminder.
+type webhookPayload struct {
+	Type        string             `json:"type"`
+	Kind        string             `json:"kind"`
+	Appointment webhookAppointment `json:"appointment"`
+}
+
+type webhookAppointment struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	PatientID      string    `json:"patient_id"`
+	PractitionerID string    `json:"practitioner_id"`
+	StartAt        time.Time `json:"start_at"`
+	EndAt          time.Time `json:"end_at"`
+	Location       string    `json:"location"`
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, reminder *domain.Reminder) error {
+	a := reminder.Appointment
+	body, err := json.Marshal(webhookPayload{
+		Type: "appointment.reminder",
+		Kind: reminder.Kind,
+		Appointment: webhookAppointment{
+			ID:             a.ID,
+			TenantID:       a.TenantID,
+			PatientID:      a.PatientID,
+			PractitionerID: a.PractitionerID,
+			StartAt:        a.StartAt,
+			EndAt:          a.EndAt,
+			Location:       a.Location,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, a.ID+":"+reminder.Kind)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(n.secret, timestamp, body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("reminder webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the Webhook-Signature value of a body sent at timestamp
+// (Unix seconds).
+func Sign(secret, timestamp string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return "sha256=" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,84 @@
+// Reminder worker
+// internal/reminder/worker.go
+package reminder
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"appointment-service/internal/domain"
+	"appointment-service/internal/repository"
+	"appointment-service/internal/tenant"
+)
+
+// batchSize is the number of due reminders of a kind sent per poll.
+const batchSize = 100
+
+// Worker sends a reminder of each booked appointment at every lead time
+// before its start. Appointments booked or rescheduled after a reminder
+// was due skip it, so a booking made two hours ahead does not get the
+// day-before reminder.
+type Worker struct {
+	appointments repository.AppointmentRepository
+	notifier     Notifier
+	leadTimes    []time.Duration
+	interval     time.Duration
+}
+
+// NewWorker creates a worker polling every interval.
+func NewWorker(appointments repository.AppointmentRepository, notifier Notifier, leadTimes []time.Duration, interval time.Duration) *Worker {
+	// Longest lead first, so the reminders of an appointment go out in order
+	leads := append([]time.Duration(nil), leadTimes...)
+	sort.Slice(leads, func(i, j int) bool { return leads[i] > leads[j] })
+
+	return &Worker{
+		appointments: appointments,
+		notifier:     notifier,
+		leadTimes:    leads,
+		interval:     interval,
+	}
+}
+
+// Run sends due reminders until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.SendDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("Appointment reminders failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends the reminders due at now. A reminder that fails is logged
+// and tried again on the next call.
+func (w *Worker) SendDue(ctx context.Context, now time.Time) error {
+	for _, lead := range w.leadTimes {
+		kind := domain.ReminderKind(lead)
+		appointments, err := w.appointments.DueReminders(ctx, kind, lead, now, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, appointment := range appointments {
+			reminder := &domain.Reminder{Appointment: appointment, Kind: kind, LeadTime: lead}
+			if err := w.notifier.Notify(tenant.NewContext(ctx, appointment.TenantID), reminder); err != nil {
+				log.Printf("Reminder %s of appointment %s failed: %v", kind, appointment.ID, err)
+				continue
+			}
+			if err := w.appointments.RecordReminder(ctx, appointment.ID, kind); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"appointment-service/internal/domain"
+	"appointment-service/internal/repository"
+)
+
+// fakeAppointments selects due reminders in memory like the SQL query.
+type fakeAppointments struct {
+	repository.AppointmentRepository
+	appointments []*domain.Appointment
+	sent         map[string]bool
+}
+
+func (f *fakeAppointments) DueReminders(ctx context.Context, kind string, lead time.Duration, now time.Time, limit int) ([]*domain.Appointment, error) {
+	var due []*domain.Appointment
+	for _, a := range f.appointments {
+		if a.IsOpen() && a.StartAt.After(now) && !a.StartAt.After(now.Add(lead)) &&
+			!a.BookedAt.After(a.StartAt.Add(-lead)) && !f.sent[a.ID+"/"+kind] {
+			due = append(due, a)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeAppointments) RecordReminder(ctx context.Context, appointmentID, kind string) error {
+	f.sent[appointmentID+"/"+kind] = true
+	return nil
+}
+
+type recordingNotifier struct {
+	sent []string
+	err  error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, reminder *domain.Reminder) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, reminder.Appointment.ID+"/"+reminder.Kind)
+	return nil
+}
+
+func TestSendDue(t *testing.T) {
+	now := time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC)
+	repo := &fakeAppointments{
+		appointments: []*domain.Appointment{
+			// booked well ahead, starting tomorrow
+			{ID: "a1", Status: domain.AppointmentBooked, StartAt: now.Add(20 * time.Hour), BookedAt: now.Add(-48 * time.Hour)},
+			// booked three hours before it starts
+			{ID: "a2", Status: domain.AppointmentBooked, StartAt: now.Add(time.Hour), BookedAt: now.Add(-2 * time.Hour)},
+			{ID: "a3", Status: domain.AppointmentCancelled, StartAt: now.Add(time.Hour), BookedAt: now.Add(-48 * time.Hour)},
+		},
+		sent: make(map[string]bool),
+	}
+
+	failing := &recordingNotifier{err: errors.New("gateway down")}
+	worker := NewWorker(repo, failing, []time.Duration{2 * time.Hour, 24 * time.Hour}, time.Minute)
+	if err := worker.SendDue(context.Background(), now); err != nil {
+		t.Fatalf("SendDue failed: %v", err)
+	}
+	if len(repo.sent) != 0 {
+		t.Fatalf("Expected failed reminders not to be recorded, got %v", repo.sent)
+	}
+
+	notifier := &recordingNotifier{}
+	worker.notifier = notifier
+	for i := 0; i < 2; i++ {
+		if err := worker.SendDue(context.Background(), now); err != nil {
+			t.Fatalf("SendDue failed: %v", err)
+		}
+	}
+
+	want := []string{"a1/24h", "a2/2h"}
+	if len(notifier.sent) != len(want) {
+		t.Fatalf("Expected reminders %v, got %v", want, notifier.sent)
+	}
+	for i := range want {
+		if notifier.sent[i] != want[i] {
+			t.Errorf("Reminder %d: got %s, want %s", i, notifier.sent[i], want[i])
+		}
+	}
+}
+
+func TestReminderKind(t *testing.T) {
+	tests := map[time.Duration]string{
+		24 * time.Hour:   "24h",
+		2 * time.Hour:    "2h",
+		90 * time.Minute: "90m",
+	}
+	for lead, want := range tests {
+		if got := domain.ReminderKind(lead); got != want {
+			t.Errorf("ReminderKind(%v) = %s, want %s", lead, got, want)
+		}
+	}
+}
@@ -0,0 +1,407 @@
+// Appointment repository
+// internal/repository/appointment_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"appointment-service/internal/domain"
+)
+
+type appointmentRepository struct {
+	db *sql.DB
+}
+
+func NewAppointmentRepository(db *sql.DB) AppointmentRepository {
+	return &appointmentRepository{db: db}
+}
+
+// claimSlot books a free slot that has not started and returns it. The
+// status check and the update are one statement, so only one of several
+// concurrent claims of a slot affects a row.
+func claimSlot(ctx context.Context, tx *sql.Tx, tenantID, slotID string) (*domain.Slot, error) {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE slots SET status = 'booked', updated_at = GETDATE()
+		WHERE id = @p1 AND tenant_id = @p2 AND status = 'free' AND start_at > SYSDATETIMEOFFSET()
+	`, slotID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, domain.ErrSlotUnavailable
+	}
+
+	query := `SELECT ` + slotColumns + ` FROM slots WHERE id = @p1`
+	return scanSlot(tx.QueryRowContext(ctx, query, slotID))
+}
+
+// freeSlot makes a booked slot bookable again.
+func freeSlot(ctx context.Context, tx *sql.Tx, slotID string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE slots SET status = 'free', updated_at = GETDATE()
+		WHERE id = @p1 AND status = 'booked'
+	`, slotID)
+	return err
+}
+
+// patientBookedQuery counts the patient's booked appointments, other than
+// one, overlapping a period.
+const patientBookedQuery = `
+	SELECT COUNT(*) FROM appointments %s
+	WHERE tenant_id = @p1 AND patient_id = @p2 AND status = 'booked'
+		AND start_at < @p4 AND end_at > @p3 AND id <> @p5
+`
+
+// checkPatientFree returns ErrPatientDoubleBooked if the patient has another
+// appointment overlapping the slot. The range lock keeps a concurrent
+// booking of the patient from inserting before the transaction commits.
+func checkPatientFree(ctx context.Context, tx *sql.Tx, tenantID, patientID, appointmentID string, slot *domain.Slot) error {
+	var booked int
+	query := fmt.Sprintf(patientBookedQuery, "WITH (UPDLOCK, HOLDLOCK)")
+	if err := tx.QueryRowContext(ctx, query, tenantID, patientID, slot.StartAt, slot.EndAt, appointmentID).Scan(&booked); err != nil {
+		return err
+	}
+	if booked > 0 {
+		return domain.ErrPatientDoubleBooked
+	}
+	return nil
+}
+
+func (r *appointmentRepository) Book(ctx context.Context, appointment *domain.Appointment) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		slot, err := claimSlot(ctx, tx, tenantID, appointment.SlotID)
+		if err != nil {
+			return err
+		}
+		if err := checkPatientFree(ctx, tx, tenantID, appointment.PatientID, "", slot); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		appointment.ID = uuid.New().String()
+		appointment.TenantID = tenantID
+		appointment.PractitionerID = slot.PractitionerID
+		appointment.StartAt = slot.StartAt
+		appointment.EndAt = slot.EndAt
+		appointment.Location = slot.Location
+		appointment.Status = domain.AppointmentBooked
+		appointment.BookedAt = now
+		appointment.CreatedAt = now
+		appointment.UpdatedAt = now
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO appointments (
+				id, tenant_id, patient_id, practitioner_id, slot_id, start_at, end_at, location,
+				reason, status, booked_at, reschedule_count, created_by, created_at, updated_at
+			) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10, @p11, 0, @p12, @p11, @p11)
+		`, appointment.ID, appointment.TenantID, appointment.PatientID, appointment.PractitionerID,
+			appointment.SlotID, appointment.StartAt, appointment.EndAt, nullString(appointment.Location),
+			nullString(appointment.Reason), appointment.Status, now, appointment.CreatedBy,
+		)
+		return err
+	})
+}
+
+const appointmentColumns = `id, tenant_id, patient_id, practitioner_id, slot_id, start_at, end_at,
+	location, reason, status, booked_at, reschedule_count, cancelled_at, cancelled_by,
+	cancellation_reason, no_show_at, no_show_recorded_by, created_by, created_at, updated_by, updated_at`
+
+func scanAppointment(row rowScanner) (*domain.Appointment, error) {
+	var (
+		appointment        domain.Appointment
+		location           sql.NullString
+		reason             sql.NullString
+		cancelledAt        sql.NullTime
+		cancelledBy        sql.NullString
+		cancellationReason sql.NullString
+		noShowAt           sql.NullTime
+		noShowRecordedBy   sql.NullString
+		createdBy          sql.NullString
+		updatedBy          sql.NullString
+	)
+
+	err := row.Scan(&appointment.ID, &appointment.TenantID, &appointment.PatientID,
+		&appointment.PractitionerID, &appointment.SlotID, &appointment.StartAt, &appointment.EndAt,
+		&location, &reason, &appointment.Status, &appointment.BookedAt, &appointment.RescheduleCount,
+		&cancelledAt, &cancelledBy, &cancellationReason, &noShowAt, &noShowRecordedBy,
+		&createdBy, &appointment.CreatedAt, &updatedBy, &appointment.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAppointmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	appointment.Location = location.String
+	appointment.Reason = reason.String
+	if cancelledAt.Valid {
+		appointment.CancelledAt = &cancelledAt.Time
+	}
+	appointment.CancelledBy = cancelledBy.String
+	appointment.CancellationReason = cancellationReason.String
+	if noShowAt.Valid {
+		appointment.NoShowAt = &noShowAt.Time
+	}
+	appointment.NoShowRecordedBy = noShowRecordedBy.String
+	appointment.CreatedBy = createdBy.String
+	appointment.UpdatedBy = updatedBy.String
+	return &appointment, nil
+}
+
+func (r *appointmentRepository) GetByID(ctx context.Context, id string) (*domain.Appointment, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + appointmentColumns + ` FROM appointments WHERE id = @p1 AND tenant_id = @p2`
+	return scanAppointment(r.db.QueryRowContext(ctx, query, id, tenantID))
+}
+
+func (r *appointmentRepository) List(ctx context.Context, filter domain.AppointmentFilter) ([]*domain.Appointment, int, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	conditions := []string{"tenant_id = @p1"}
+	args := []interface{}{tenantID}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, condition+" @p"+strconv.Itoa(len(args)))
+	}
+	if filter.PatientID != "" {
+		add("patient_id =", filter.PatientID)
+	}
+	if filter.PractitionerID != "" {
+		add("practitioner_id =", filter.PractitionerID)
+	}
+	if filter.Status != "" {
+		add("status =", filter.Status)
+	}
+	if filter.From != nil {
+		add("start_at >=", *filter.From)
+	}
+	if filter.To != nil {
+		add("start_at <", *filter.To)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM appointments`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query := `SELECT ` + appointmentColumns + ` FROM appointments` + where +
+		` ORDER BY start_at, id OFFSET ` + strconv.Itoa(offset) + ` ROWS FETCH NEXT ` +
+		strconv.Itoa(filter.Limit) + ` ROWS ONLY`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var appointments []*domain.Appointment
+	for rows.Next() {
+		appointment, err := scanAppointment(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		appointments = append(appointments, appointment)
+	}
+	return appointments, total, rows.Err()
+}
+
+func (r *appointmentRepository) PatientBooked(ctx context.Context, patientID string, start, end time.Time, excludeID string) (bool, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var booked int
+	query := fmt.Sprintf(patientBookedQuery, "")
+	if err := r.db.QueryRowContext(ctx, query, tenantID, patientID, start, end, excludeID).Scan(&booked); err != nil {
+		return false, err
+	}
+	return booked > 0, nil
+}
+
+func (r *appointmentRepository) Cancel(ctx context.Context, appointment *domain.Appointment) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = withTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE appointments SET
+				status = 'cancelled', cancelled_at = @p3, cancelled_by = @p4,
+				cancellation_reason = @p5, updated_by = @p4, updated_at = @p3
+			WHERE id = @p1 AND tenant_id = @p2 AND status = 'booked'
+		`, appointment.ID, tenantID, now, appointment.CancelledBy, nullString(appointment.CancellationReason))
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return domain.ErrAppointmentClosed
+		}
+		return freeSlot(ctx, tx, appointment.SlotID)
+	})
+	if err != nil {
+		return err
+	}
+
+	appointment.Status = domain.AppointmentCancelled
+	appointment.CancelledAt = &now
+	appointment.UpdatedBy = appointment.CancelledBy
+	appointment.UpdatedAt = now
+	return nil
+}
+
+func (r *appointmentRepository) Reschedule(ctx context.Context, appointment *domain.Appointment, slot *domain.Slot) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		claimed, err := claimSlot(ctx, tx, tenantID, slot.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkPatientFree(ctx, tx, tenantID, appointment.PatientID, appointment.ID, claimed); err != nil {
+			return err
+		}
+
+		// The old slot is part of the condition so a concurrent reschedule
+		// of the same appointment fails instead of leaking a slot
+		result, err := tx.ExecContext(ctx, `
+			UPDATE appointments SET
+				practitioner_id = @p4, slot_id = @p5, start_at = @p6, end_at = @p7, location = @p8,
+				booked_at = @p9, reschedule_count = reschedule_count + 1, updated_by = @p10, updated_at = @p9
+			WHERE id = @p1 AND tenant_id = @p2 AND slot_id = @p3 AND status = 'booked'
+		`, appointment.ID, tenantID, appointment.SlotID, claimed.PractitionerID, claimed.ID,
+			claimed.StartAt, claimed.EndAt, nullString(claimed.Location), now, appointment.UpdatedBy)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return domain.ErrAppointmentClosed
+		}
+
+		if err := freeSlot(ctx, tx, appointment.SlotID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM appointment_reminders WHERE appointment_id = @p1`, appointment.ID); err != nil {
+			return err
+		}
+
+		appointment.PractitionerID = claimed.PractitionerID
+		appointment.SlotID = claimed.ID
+		appointment.StartAt = claimed.StartAt
+		appointment.EndAt = claimed.EndAt
+		appointment.Location = claimed.Location
+		appointment.BookedAt = now
+		appointment.RescheduleCount++
+		appointment.UpdatedAt = now
+		return nil
+	})
+}
+
+func (r *appointmentRepository) MarkNoShow(ctx context.Context, appointment *domain.Appointment) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE appointments SET
+			status = 'no_show', no_show_at = @p3, no_show_recorded_by = @p4, updated_by = @p4, updated_at = @p3
+		WHERE id = @p1 AND tenant_id = @p2 AND status = 'booked'
+	`, appointment.ID, tenantID, now, appointment.NoShowRecordedBy)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrAppointmentClosed
+	}
+
+	appointment.Status = domain.AppointmentNoShow
+	appointment.NoShowAt = &now
+	appointment.UpdatedBy = appointment.NoShowRecordedBy
+	appointment.UpdatedAt = now
+	return nil
+}
+
+func (r *appointmentRepository) DueReminders(ctx context.Context, kind string, lead time.Duration, now time.Time, limit int) ([]*domain.Appointment, error) {
+	seconds := int(lead / time.Second)
+	query := `
+		SELECT TOP (@p4) ` + appointmentColumns + ` FROM appointments a
+		WHERE status = 'booked' AND start_at > @p2 AND start_at <= DATEADD(second, @p3, @p2)
+			AND booked_at <= DATEADD(second, -@p3, start_at)
+			AND NOT EXISTS (
+				SELECT 1 FROM appointment_reminders r WHERE r.appointment_id = a.id AND r.kind = @p1
+			)
+		ORDER BY start_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, kind, now, seconds, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var appointments []*domain.Appointment
+	for rows.Next() {
+		appointment, err := scanAppointment(rows)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, appointment)
+	}
+	return appointments, rows.Err()
+}
+
+func (r *appointmentRepository) RecordReminder(ctx context.Context, appointmentID, kind string) error {
+	_, err := r.db.ExecContext(ctx, `
+		IF NOT EXISTS (SELECT 1 FROM appointment_reminders WHERE appointment_id = @p1 AND kind = @p2)
+		INSERT INTO appointment_reminders (appointment_id, kind, sent_at) VALUES (@p1, @p2, GETDATE())
+	`, appointmentID, kind)
+	return err
+}
@@ -0,0 +1,69 @@
+// Repository interfaces
+// internal/repository/interfaces.go
+package repository
+
+import (
+	"appointment-service/internal/domain"
+	"context"
+	"time"
+)
+
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *domain.Schedule) error
+	GetByID(ctx context.Context, id string) (*domain.Schedule, error)
+
+	// ListByPractitioner returns the practitioner's active schedules
+	ListByPractitioner(ctx context.Context, practitionerID string) ([]*domain.Schedule, error)
+
+	// Deactivate stops the schedule from generating slots; slots generated
+	// before are kept
+	Deactivate(ctx context.Context, id string) error
+}
+
+type SlotRepository interface {
+	// CreateMany inserts the slots that overlap none of their
+	// practitioner's slots and returns the number inserted
+	CreateMany(ctx context.Context, slots []*domain.Slot) (int, error)
+	GetByID(ctx context.Context, id string) (*domain.Slot, error)
+	List(ctx context.Context, filter domain.SlotFilter) ([]*domain.Slot, error)
+
+	// SetBlocked blocks a free slot or frees a blocked one; ErrSlotBooked
+	// if the slot is booked
+	SetBlocked(ctx context.Context, id string, blocked bool) (*domain.Slot, error)
+}
+
+type AppointmentRepository interface {
+	// Book claims the appointment's slot and stores the appointment in one
+	// transaction. The slot is claimed with a conditional update, so of
+	// concurrent bookings of a slot only one succeeds; the others get
+	// ErrSlotUnavailable, as do bookings of slots that are not free or have
+	// started. ErrPatientDoubleBooked if the patient has another booked
+	// appointment overlapping the slot.
+	Book(ctx context.Context, appointment *domain.Appointment) error
+	GetByID(ctx context.Context, id string) (*domain.Appointment, error)
+	List(ctx context.Context, filter domain.AppointmentFilter) ([]*domain.Appointment, int, error)
+
+	// PatientBooked reports whether the patient has a booked appointment
+	// other than excludeID overlapping start to end
+	PatientBooked(ctx context.Context, patientID string, start, end time.Time, excludeID string) (bool, error)
+
+	// Cancel marks the appointment cancelled and frees its slot;
+	// ErrAppointmentClosed if it is no longer booked
+	Cancel(ctx context.Context, appointment *domain.Appointment) error
+
+	// Reschedule claims the slot like Book, moves the appointment to it and
+	// frees the old slot, with the patient checked like Book. Reminders
+	// already sent are forgotten.
+	Reschedule(ctx context.Context, appointment *domain.Appointment, slot *domain.Slot) error
+
+	// MarkNoShow marks the appointment no-show; the slot stays booked
+	MarkNoShow(ctx context.Context, appointment *domain.Appointment) error
+
+	// DueReminders returns booked appointments of all tenants starting
+	// within lead of now that were booked before the reminder was due and
+	// have not had the reminder of kind
+	DueReminders(ctx context.Context, kind string, lead time.Duration, now time.Time, limit int) ([]*domain.Appointment, error)
+
+	// RecordReminder remembers that the reminder of kind was sent
+	RecordReminder(ctx context.Context, appointmentID, kind string) error
+}
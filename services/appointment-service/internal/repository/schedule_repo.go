@@ -0,0 +1,149 @@
+// Practitioner schedule repository
+// internal/repository/schedule_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"appointment-service/internal/domain"
+)
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+type scheduleRepository struct {
+	db *sql.DB
+}
+
+func NewScheduleRepository(db *sql.DB) ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+func (r *scheduleRepository) Create(ctx context.Context, schedule *domain.Schedule) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	schedule.ID = uuid.New().String()
+	schedule.TenantID = tenantID
+	schedule.IsActive = true
+	schedule.CreatedAt = time.Now()
+	schedule.UpdatedAt = schedule.CreatedAt
+
+	query := `
+		INSERT INTO schedules (
+			id, tenant_id, practitioner_id, day_of_week, start_time, end_time, slot_minutes,
+			location, valid_from, valid_until, is_active, created_by, created_at, updated_at
+		) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10, 1, @p11, @p12, @p12)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		schedule.ID, schedule.TenantID, schedule.PractitionerID, int(schedule.DayOfWeek),
+		schedule.StartTime, schedule.EndTime, schedule.SlotMinutes, nullString(schedule.Location),
+		schedule.ValidFrom, schedule.ValidUntil, schedule.CreatedBy, schedule.CreatedAt,
+	)
+	return err
+}
+
+const scheduleColumns = `id, tenant_id, practitioner_id, day_of_week, start_time, end_time, slot_minutes,
+	location, valid_from, valid_until, is_active, created_by, created_at, updated_at`
+
+func scanSchedule(row rowScanner) (*domain.Schedule, error) {
+	var (
+		schedule   domain.Schedule
+		dayOfWeek  int
+		location   sql.NullString
+		validUntil sql.NullTime
+		createdBy  sql.NullString
+	)
+
+	err := row.Scan(&schedule.ID, &schedule.TenantID, &schedule.PractitionerID, &dayOfWeek,
+		&schedule.StartTime, &schedule.EndTime, &schedule.SlotMinutes, &location, &schedule.ValidFrom,
+		&validUntil, &schedule.IsActive, &createdBy, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.DayOfWeek = time.Weekday(dayOfWeek)
+	schedule.Location = location.String
+	if validUntil.Valid {
+		schedule.ValidUntil = &validUntil.Time
+	}
+	schedule.CreatedBy = createdBy.String
+	return &schedule, nil
+}
+
+func (r *scheduleRepository) GetByID(ctx context.Context, id string) (*domain.Schedule, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + scheduleColumns + ` FROM schedules WHERE id = @p1 AND tenant_id = @p2`
+	return scanSchedule(r.db.QueryRowContext(ctx, query, id, tenantID))
+}
+
+func (r *scheduleRepository) ListByPractitioner(ctx context.Context, practitionerID string) ([]*domain.Schedule, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + scheduleColumns + ` FROM schedules
+		WHERE tenant_id = @p1 AND practitioner_id = @p2 AND is_active = 1
+		ORDER BY day_of_week, start_time
+	`
+	rows, err := r.db.QueryContext(ctx, query, tenantID, practitionerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*domain.Schedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (r *scheduleRepository) Deactivate(ctx context.Context, id string) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE schedules SET is_active = 0, updated_at = GETDATE()
+		WHERE id = @p1 AND tenant_id = @p2 AND is_active = 1
+	`, id, tenantID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrScheduleNotFound
+	}
+	return nil
+}
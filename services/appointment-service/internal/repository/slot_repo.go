@@ -0,0 +1,189 @@
+// Slot repository
+// internal/repository/slot_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"appointment-service/internal/domain"
+)
+
+// withTx runs fn in a transaction, committed if fn succeeds.
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type slotRepository struct {
+	db *sql.DB
+}
+
+func NewSlotRepository(db *sql.DB) SlotRepository {
+	return &slotRepository{db: db}
+}
+
+func (r *slotRepository) CreateMany(ctx context.Context, slots []*domain.Slot) (int, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// A slot overlapping one of the practitioner's slots is skipped. The
+	// range lock keeps a concurrent generation from inserting between the
+	// check and the insert
+	query := `
+		INSERT INTO slots (
+			id, tenant_id, practitioner_id, schedule_id, start_at, end_at, location, status,
+			created_at, updated_at
+		)
+		SELECT @p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p9
+		WHERE NOT EXISTS (
+			SELECT 1 FROM slots WITH (UPDLOCK, HOLDLOCK)
+			WHERE tenant_id = @p2 AND practitioner_id = @p3 AND start_at < @p6 AND end_at > @p5
+		)
+	`
+
+	created := 0
+	err = withTx(ctx, r.db, func(tx *sql.Tx) error {
+		now := time.Now()
+		for _, slot := range slots {
+			slot.ID = uuid.New().String()
+			slot.TenantID = tenantID
+			slot.CreatedAt = now
+			slot.UpdatedAt = now
+
+			result, err := tx.ExecContext(ctx, query,
+				slot.ID, slot.TenantID, slot.PractitionerID, nullString(slot.ScheduleID),
+				slot.StartAt, slot.EndAt, nullString(slot.Location), slot.Status, now,
+			)
+			if err != nil {
+				return err
+			}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			created += int(rowsAffected)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+const slotColumns = `id, tenant_id, practitioner_id, schedule_id, start_at, end_at, location, status,
+	created_at, updated_at`
+
+func scanSlot(row rowScanner) (*domain.Slot, error) {
+	var (
+		slot       domain.Slot
+		scheduleID sql.NullString
+		location   sql.NullString
+	)
+
+	err := row.Scan(&slot.ID, &slot.TenantID, &slot.PractitionerID, &scheduleID, &slot.StartAt,
+		&slot.EndAt, &location, &slot.Status, &slot.CreatedAt, &slot.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrSlotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slot.ScheduleID = scheduleID.String
+	slot.Location = location.String
+	return &slot, nil
+}
+
+func (r *slotRepository) GetByID(ctx context.Context, id string) (*domain.Slot, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + slotColumns + ` FROM slots WHERE id = @p1 AND tenant_id = @p2`
+	return scanSlot(r.db.QueryRowContext(ctx, query, id, tenantID))
+}
+
+func (r *slotRepository) List(ctx context.Context, filter domain.SlotFilter) ([]*domain.Slot, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT TOP (@p5) ` + slotColumns + ` FROM slots
+		WHERE tenant_id = @p1 AND practitioner_id = @p2 AND start_at >= @p3 AND start_at < @p4
+	`
+	args := []interface{}{tenantID, filter.PractitionerID, filter.From, filter.To, filter.Limit}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += ` AND status = @p` + strconv.Itoa(len(args))
+	}
+	query += ` ORDER BY start_at`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var slots []*domain.Slot
+	for rows.Next() {
+		slot, err := scanSlot(rows)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+	return slots, rows.Err()
+}
+
+func (r *slotRepository) SetBlocked(ctx context.Context, id string, blocked bool) (*domain.Slot, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := domain.SlotBlocked, domain.SlotFree
+	if blocked {
+		from, to = domain.SlotFree, domain.SlotBlocked
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE slots SET status = @p4, updated_at = GETDATE()
+		WHERE id = @p1 AND tenant_id = @p2 AND status IN (@p3, @p4)
+	`, id, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, domain.ErrSlotBooked
+	}
+	return slot, nil
+}
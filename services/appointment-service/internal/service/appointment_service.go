@@ -0,0 +1,181 @@
+// Appointment booking
+// internal/service/appointment_service.go
+package service
+
+import (
+	"context"
+	"time"
+
+	"appointment-service/internal/domain"
+	"appointment-service/internal/repository"
+)
+
+type appointmentService struct {
+	appointmentRepo repository.AppointmentRepository
+	slotRepo        repository.SlotRepository
+	patients        PatientVerifier
+}
+
+// NewAppointmentService creates the service; patients asks patient-service
+// whether a patient can be booked.
+func NewAppointmentService(appointmentRepo repository.AppointmentRepository, slotRepo repository.SlotRepository, patients PatientVerifier) AppointmentService {
+	return &appointmentService{
+		appointmentRepo: appointmentRepo,
+		slotRepo:        slotRepo,
+		patients:        patients,
+	}
+}
+
+func (s *appointmentService) BookAppointment(ctx context.Context, appointment *domain.Appointment) (*domain.Appointment, error) {
+	if appointment.PatientID == "" || appointment.SlotID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	// Checked first so unknown and taken slots fail without a call to
+	// patient-service; the booking itself checks the slot and the patient's
+	// other appointments again
+	slot, err := s.freeSlot(ctx, appointment.SlotID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPatientFree(ctx, appointment.PatientID, slot, ""); err != nil {
+		return nil, err
+	}
+	if err := s.patients.VerifyPatient(ctx, appointment.PatientID); err != nil {
+		return nil, err
+	}
+
+	if err := s.appointmentRepo.Book(ctx, appointment); err != nil {
+		return nil, err
+	}
+	return appointment, nil
+}
+
+func (s *appointmentService) GetAppointment(ctx context.Context, id string) (*domain.Appointment, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.appointmentRepo.GetByID(ctx, id)
+}
+
+func (s *appointmentService) ListAppointments(ctx context.Context, filter domain.AppointmentFilter) ([]*domain.Appointment, int, error) {
+	// Set default pagination
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100 // Max limit
+	}
+	return s.appointmentRepo.List(ctx, filter)
+}
+
+func (s *appointmentService) CancelAppointment(ctx context.Context, id, cancelledBy, reason string) (*domain.Appointment, error) {
+	appointment, err := s.openAppointment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !appointment.StartAt.After(time.Now()) {
+		return nil, domain.ErrAppointmentStarted
+	}
+
+	appointment.CancelledBy = cancelledBy
+	appointment.CancellationReason = reason
+	if err := s.appointmentRepo.Cancel(ctx, appointment); err != nil {
+		return nil, err
+	}
+	return appointment, nil
+}
+
+func (s *appointmentService) RescheduleAppointment(ctx context.Context, id, slotID, updatedBy string) (*domain.Appointment, error) {
+	if slotID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	appointment, err := s.openAppointment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !appointment.StartAt.After(time.Now()) {
+		return nil, domain.ErrAppointmentStarted
+	}
+	if slotID == appointment.SlotID {
+		return appointment, nil
+	}
+
+	slot, err := s.freeSlot(ctx, slotID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPatientFree(ctx, appointment.PatientID, slot, appointment.ID); err != nil {
+		return nil, err
+	}
+
+	appointment.UpdatedBy = updatedBy
+	if err := s.appointmentRepo.Reschedule(ctx, appointment, slot); err != nil {
+		return nil, err
+	}
+	return appointment, nil
+}
+
+func (s *appointmentService) MarkNoShow(ctx context.Context, id, recordedBy string) (*domain.Appointment, error) {
+	appointment, err := s.openAppointment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.StartAt.After(time.Now()) {
+		return nil, domain.ErrAppointmentUpcoming
+	}
+
+	appointment.NoShowRecordedBy = recordedBy
+	if err := s.appointmentRepo.MarkNoShow(ctx, appointment); err != nil {
+		return nil, err
+	}
+	return appointment, nil
+}
+
+// Helper methods
+
+// openAppointment loads an appointment that is still booked.
+func (s *appointmentService) openAppointment(ctx context.Context, id string) (*domain.Appointment, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !appointment.IsOpen() {
+		return nil, domain.ErrAppointmentClosed
+	}
+	return appointment, nil
+}
+
+// freeSlot loads a slot that is free and has not started;
+// ErrSlotUnavailable for other slots.
+func (s *appointmentService) freeSlot(ctx context.Context, slotID string) (*domain.Slot, error) {
+	slot, err := s.slotRepo.GetByID(ctx, slotID)
+	if err != nil {
+		return nil, err
+	}
+	if slot.Status != domain.SlotFree || !slot.StartAt.After(time.Now()) {
+		return nil, domain.ErrSlotUnavailable
+	}
+	return slot, nil
+}
+
+// checkPatientFree returns ErrPatientDoubleBooked if the patient has a
+// booked appointment, other than excludeID, overlapping the slot.
+func (s *appointmentService) checkPatientFree(ctx context.Context, patientID string, slot *domain.Slot, excludeID string) error {
+	booked, err := s.appointmentRepo.PatientBooked(ctx, patientID, slot.StartAt, slot.EndAt, excludeID)
+	if err != nil {
+		return err
+	}
+	if booked {
+		return domain.ErrPatientDoubleBooked
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"appointment-service/internal/domain"
+)
+
+// mockStore keeps slots and appointments in memory. Claiming a slot is
+// atomic, like the conditional update of the SQL repository.
+type mockStore struct {
+	mu           sync.Mutex
+	slots        map[string]*domain.Slot
+	appointments map[string]*domain.Appointment
+	nextID       int
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{
+		slots:        make(map[string]*domain.Slot),
+		appointments: make(map[string]*domain.Appointment),
+	}
+}
+
+func (m *mockStore) addSlot(id string, start time.Time) {
+	m.slots[id] = &domain.Slot{
+		ID:             id,
+		PractitionerID: "dr1",
+		StartAt:        start,
+		EndAt:          start.Add(15 * time.Minute),
+		Status:         domain.SlotFree,
+	}
+}
+
+func (m *mockStore) claim(slotID string) (*domain.Slot, error) {
+	slot, ok := m.slots[slotID]
+	if !ok || slot.Status != domain.SlotFree || !slot.StartAt.After(time.Now()) {
+		return nil, domain.ErrSlotUnavailable
+	}
+	slot.Status = domain.SlotBooked
+	return slot, nil
+}
+
+// SlotRepository
+
+func (m *mockStore) CreateMany(ctx context.Context, slots []*domain.Slot) (int, error) {
+	return 0, nil
+}
+
+func (m *mockStore) GetByID(ctx context.Context, id string) (*domain.Slot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	slot, ok := m.slots[id]
+	if !ok {
+		return nil, domain.ErrSlotNotFound
+	}
+	copied := *slot
+	return &copied, nil
+}
+
+func (m *mockStore) List(ctx context.Context, filter domain.SlotFilter) ([]*domain.Slot, error) {
+	return nil, nil
+}
+
+func (m *mockStore) SetBlocked(ctx context.Context, id string, blocked bool) (*domain.Slot, error) {
+	return nil, nil
+}
+
+// mockAppointments is the AppointmentRepository view of the store.
+type mockAppointments struct {
+	*mockStore
+}
+
+func (m mockAppointments) Book(ctx context.Context, appointment *domain.Appointment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	slot, err := m.claim(appointment.SlotID)
+	if err != nil {
+		return err
+	}
+	m.nextID++
+	appointment.ID = fmt.Sprintf("a%d", m.nextID)
+	appointment.PractitionerID = slot.PractitionerID
+	appointment.StartAt = slot.StartAt
+	appointment.EndAt = slot.EndAt
+	appointment.Status = domain.AppointmentBooked
+	copied := *appointment
+	m.appointments[appointment.ID] = &copied
+	return nil
+}
+
+func (m mockAppointments) GetByID(ctx context.Context, id string) (*domain.Appointment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	appointment, ok := m.appointments[id]
+	if !ok {
+		return nil, domain.ErrAppointmentNotFound
+	}
+	copied := *appointment
+	return &copied, nil
+}
+
+func (m mockAppointments) List(ctx context.Context, filter domain.AppointmentFilter) ([]*domain.Appointment, int, error) {
+	return nil, 0, nil
+}
+
+func (m mockAppointments) PatientBooked(ctx context.Context, patientID string, start, end time.Time, excludeID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, appointment := range m.appointments {
+		if appointment.PatientID == patientID && appointment.ID != excludeID && appointment.IsOpen() &&
+			appointment.StartAt.Before(end) && appointment.EndAt.After(start) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m mockAppointments) Cancel(ctx context.Context, appointment *domain.Appointment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := m.appointments[appointment.ID]
+	if !stored.IsOpen() {
+		return domain.ErrAppointmentClosed
+	}
+	stored.Status = domain.AppointmentCancelled
+	m.slots[stored.SlotID].Status = domain.SlotFree
+	appointment.Status = stored.Status
+	return nil
+}
+
+func (m mockAppointments) Reschedule(ctx context.Context, appointment *domain.Appointment, slot *domain.Slot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	claimed, err := m.claim(slot.ID)
+	if err != nil {
+		return err
+	}
+	stored := m.appointments[appointment.ID]
+	m.slots[stored.SlotID].Status = domain.SlotFree
+	stored.SlotID = claimed.ID
+	stored.StartAt = claimed.StartAt
+	stored.RescheduleCount++
+	*appointment = *stored
+	return nil
+}
+
+func (m mockAppointments) MarkNoShow(ctx context.Context, appointment *domain.Appointment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.appointments[appointment.ID].Status = domain.AppointmentNoShow
+	appointment.Status = domain.AppointmentNoShow
+	return nil
+}
+
+func (m mockAppointments) DueReminders(ctx context.Context, kind string, lead time.Duration, now time.Time, limit int) ([]*domain.Appointment, error) {
+	return nil, nil
+}
+
+func (m mockAppointments) RecordReminder(ctx context.Context, appointmentID, kind string) error {
+	return nil
+}
+
+// fakePatients verifies patients against a fixed list of results.
+type fakePatients map[string]error
+
+func (f fakePatients) VerifyPatient(ctx context.Context, patientID string) error {
+	if err, ok := f[patientID]; ok {
+		return err
+	}
+	return domain.ErrPatientNotFound
+}
+
+func newTestAppointmentService() (AppointmentService, *mockStore) {
+	store := newMockStore()
+	patients := fakePatients{"p1": nil, "p2": nil, "dead": domain.ErrPatientDeceased}
+	return NewAppointmentService(mockAppointments{store}, store, patients), store
+}
+
+func TestBookAppointmentConcurrently(t *testing.T) {
+	svc, store := newTestAppointmentService()
+	store.addSlot("s1", time.Now().Add(time.Hour))
+
+	const bookings = 20
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < bookings; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.BookAppointment(context.Background(), &domain.Appointment{PatientID: "p1", SlotID: "s1"})
+			if err != nil && err != domain.ErrSlotUnavailable {
+				t.Errorf("Unexpected error %v", err)
+			}
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Fatalf("Expected exactly one booking of the slot, got %d", succeeded)
+	}
+	if len(store.appointments) != 1 {
+		t.Errorf("Expected one stored appointment, got %d", len(store.appointments))
+	}
+}
+
+func TestBookAppointmentVerifiesPatient(t *testing.T) {
+	svc, store := newTestAppointmentService()
+	store.addSlot("s1", time.Now().Add(time.Hour))
+	store.addSlot("past", time.Now().Add(-time.Hour))
+	ctx := context.Background()
+
+	tests := []struct {
+		patientID, slotID string
+		want              error
+	}{
+		{"unknown", "s1", domain.ErrPatientNotFound},
+		{"dead", "s1", domain.ErrPatientDeceased},
+		{"p1", "missing", domain.ErrSlotNotFound},
+		{"p1", "past", domain.ErrSlotUnavailable},
+	}
+	for _, tt := range tests {
+		_, err := svc.BookAppointment(ctx, &domain.Appointment{PatientID: tt.patientID, SlotID: tt.slotID})
+		if err != tt.want {
+			t.Errorf("%s/%s: got %v, want %v", tt.patientID, tt.slotID, err, tt.want)
+		}
+	}
+	if store.slots["s1"].Status != domain.SlotFree {
+		t.Errorf("Expected rejected bookings to leave the slot free")
+	}
+}
+
+func TestBookAppointmentRejectsDoubleBookedPatient(t *testing.T) {
+	svc, store := newTestAppointmentService()
+	start := time.Now().Add(time.Hour)
+	store.addSlot("s1", start)
+	store.addSlot("s2", start.Add(15*time.Minute))
+	ctx := context.Background()
+
+	// Another practitioner's slot starting during s1
+	store.addSlot("other", start.Add(5*time.Minute))
+	store.slots["other"].PractitionerID = "dr2"
+
+	booked, err := svc.BookAppointment(ctx, &domain.Appointment{PatientID: "p1", SlotID: "s1"})
+	if err != nil {
+		t.Fatalf("BookAppointment failed: %v", err)
+	}
+	if _, err := svc.BookAppointment(ctx, &domain.Appointment{PatientID: "p1", SlotID: "other"}); err != domain.ErrPatientDoubleBooked {
+		t.Errorf("Expected an overlapping booking of the patient to fail, got %v", err)
+	}
+	if store.slots["other"].Status != domain.SlotFree {
+		t.Errorf("Expected the rejected booking to leave the slot free")
+	}
+
+	// Back to back appointments and other patients are fine
+	if _, err := svc.BookAppointment(ctx, &domain.Appointment{PatientID: "p1", SlotID: "s2"}); err != nil {
+		t.Errorf("Expected the following slot to be bookable, got %v", err)
+	}
+	if _, err := svc.BookAppointment(ctx, &domain.Appointment{PatientID: "p2", SlotID: "other"}); err != nil {
+		t.Errorf("Expected another patient to book the slot, got %v", err)
+	}
+
+	// Rescheduling does not conflict with the appointment being moved
+	store.addSlot("s3", start.Add(10*time.Minute))
+	if _, err := svc.RescheduleAppointment(ctx, booked.ID, "s3", "u1"); err != domain.ErrPatientDoubleBooked {
+		t.Errorf("Expected rescheduling into the patient's other appointment to fail, got %v", err)
+	}
+	store.addSlot("s4", start.Add(-10*time.Minute))
+	if _, err := svc.RescheduleAppointment(ctx, booked.ID, "s4", "u1"); err != nil {
+		t.Errorf("Expected rescheduling over the appointment's own time to succeed, got %v", err)
+	}
+}
+
+func TestCancelAndRescheduleFreeSlots(t *testing.T) {
+	svc, store := newTestAppointmentService()
+	store.addSlot("s1", time.Now().Add(time.Hour))
+	store.addSlot("s2", time.Now().Add(2*time.Hour))
+	ctx := context.Background()
+
+	booked, err := svc.BookAppointment(ctx, &domain.Appointment{PatientID: "p1", SlotID: "s1"})
+	if err != nil {
+		t.Fatalf("BookAppointment failed: %v", err)
+	}
+
+	moved, err := svc.RescheduleAppointment(ctx, booked.ID, "s2", "u1")
+	if err != nil {
+		t.Fatalf("RescheduleAppointment failed: %v", err)
+	}
+	if moved.SlotID != "s2" || moved.RescheduleCount != 1 {
+		t.Errorf("Expected the appointment to move to s2, got %+v", moved)
+	}
+	if store.slots["s1"].Status != domain.SlotFree || store.slots["s2"].Status != domain.SlotBooked {
+		t.Errorf("Expected s1 free and s2 booked, got %s and %s", store.slots["s1"].Status, store.slots["s2"].Status)
+	}
+
+	// The freed slot can be booked by another patient
+	if _, err := svc.BookAppointment(ctx, &domain.Appointment{PatientID: "p2", SlotID: "s1"}); err != nil {
+		t.Fatalf("Expected the freed slot to be bookable, got %v", err)
+	}
+	if _, err := svc.RescheduleAppointment(ctx, booked.ID, "s1", "u1"); err != domain.ErrSlotUnavailable {
+		t.Errorf("Expected rescheduling to a booked slot to fail, got %v", err)
+	}
+
+	if _, err := svc.CancelAppointment(ctx, booked.ID, "u1", "patient request"); err != nil {
+		t.Fatalf("CancelAppointment failed: %v", err)
+	}
+	if store.slots["s2"].Status != domain.SlotFree {
+		t.Errorf("Expected cancelling to free s2")
+	}
+	if _, err := svc.CancelAppointment(ctx, booked.ID, "u1", ""); err != domain.ErrAppointmentClosed {
+		t.Errorf("Expected a second cancel to fail, got %v", err)
+	}
+}
+
+func TestMarkNoShow(t *testing.T) {
+	svc, store := newTestAppointmentService()
+	store.addSlot("s1", time.Now().Add(time.Hour))
+	ctx := context.Background()
+
+	booked, err := svc.BookAppointment(ctx, &domain.Appointment{PatientID: "p1", SlotID: "s1"})
+	if err != nil {
+		t.Fatalf("BookAppointment failed: %v", err)
+	}
+	if _, err := svc.MarkNoShow(ctx, booked.ID, "u1"); err != domain.ErrAppointmentUpcoming {
+		t.Errorf("Expected an upcoming appointment not to be marked no-show, got %v", err)
+	}
+
+	store.appointments[booked.ID].StartAt = time.Now().Add(-10 * time.Minute)
+	marked, err := svc.MarkNoShow(ctx, booked.ID, "u1")
+	if err != nil || marked.Status != domain.AppointmentNoShow {
+		t.Fatalf("Expected the appointment to be marked no-show, got %v, %v", marked, err)
+	}
+	if _, err := svc.CancelAppointment(ctx, booked.ID, "u1", ""); err != domain.ErrAppointmentClosed {
+		t.Errorf("Expected a no-show appointment not to be cancelled, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+// Service interfaces
+// internal/service/interfaces.go
+package service
+
+import (
+	"appointment-service/internal/domain"
+	"context"
+	"time"
+)
+
+// PatientVerifier checks with patient-service that a patient can be booked.
+type PatientVerifier interface {
+	// VerifyPatient returns ErrPatientNotFound or ErrPatientDeceased for
+	// patients who cannot be booked
+	VerifyPatient(ctx context.Context, patientID string) error
+}
+
+type ScheduleService interface {
+	CreateSchedule(ctx context.Context, schedule *domain.Schedule) (*domain.Schedule, error)
+	GetSchedule(ctx context.Context, id string) (*domain.Schedule, error)
+	ListSchedules(ctx context.Context, practitionerID string) ([]*domain.Schedule, error)
+	DeleteSchedule(ctx context.Context, id string) error
+
+	// GenerateSlots creates the slots of the practitioner's schedules on
+	// the dates from from to to, both included. Existing slots are kept, so
+	// a range can be generated again after a schedule is added; the number
+	// of new slots is returned.
+	GenerateSlots(ctx context.Context, practitionerID string, from, to time.Time) (int, error)
+	ListSlots(ctx context.Context, filter domain.SlotFilter) ([]*domain.Slot, error)
+
+	// BlockSlot takes a free slot out of booking; UnblockSlot frees it again
+	BlockSlot(ctx context.Context, id string) (*domain.Slot, error)
+	UnblockSlot(ctx context.Context, id string) (*domain.Slot, error)
+}
+
+type AppointmentService interface {
+	// BookAppointment books the appointment's slot for the patient, who
+	// must exist in patient-service and not be deceased
+	BookAppointment(ctx context.Context, appointment *domain.Appointment) (*domain.Appointment, error)
+	GetAppointment(ctx context.Context, id string) (*domain.Appointment, error)
+	ListAppointments(ctx context.Context, filter domain.AppointmentFilter) ([]*domain.Appointment, int, error)
+
+	// CancelAppointment cancels an appointment that has not started and
+	// frees its slot
+	CancelAppointment(ctx context.Context, id, cancelledBy, reason string) (*domain.Appointment, error)
+
+	// RescheduleAppointment moves an appointment that has not started to
+	// another free slot
+	RescheduleAppointment(ctx context.Context, id, slotID, updatedBy string) (*domain.Appointment, error)
+
+	// MarkNoShow records that the patient did not come to an appointment
+	// that has started
+	MarkNoShow(ctx context.Context, id, recordedBy string) (*domain.Appointment, error)
+}
@@ -0,0 +1,137 @@
+// Practitioner schedules and slots
+// internal/service/schedule_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"appointment-service/internal/domain"
+	"appointment-service/internal/repository"
+)
+
+// maxListSlots caps the slots ListSlots returns.
+const maxListSlots = 500
+
+type scheduleService struct {
+	scheduleRepo repository.ScheduleRepository
+	slotRepo     repository.SlotRepository
+	loc          *time.Location
+	maxDays      int
+}
+
+// NewScheduleService creates the service; schedule hours are local times of
+// loc, and slots are generated for at most maxDays dates at once.
+func NewScheduleService(scheduleRepo repository.ScheduleRepository, slotRepo repository.SlotRepository, loc *time.Location, maxDays int) ScheduleService {
+	return &scheduleService{
+		scheduleRepo: scheduleRepo,
+		slotRepo:     slotRepo,
+		loc:          loc,
+		maxDays:      maxDays,
+	}
+}
+
+func (s *scheduleService) CreateSchedule(ctx context.Context, schedule *domain.Schedule) (*domain.Schedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Overlapping schedules would give free slots over the same time, each
+	// bookable on its own
+	existing, err := s.scheduleRepo.ListByPractitioner(ctx, schedule.PractitionerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range existing {
+		if schedule.Overlaps(other) {
+			return nil, domain.ErrScheduleOverlap
+		}
+	}
+
+	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to create schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+func (s *scheduleService) GetSchedule(ctx context.Context, id string) (*domain.Schedule, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.scheduleRepo.GetByID(ctx, id)
+}
+
+func (s *scheduleService) ListSchedules(ctx context.Context, practitionerID string) ([]*domain.Schedule, error) {
+	if practitionerID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.scheduleRepo.ListByPractitioner(ctx, practitionerID)
+}
+
+func (s *scheduleService) DeleteSchedule(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.ErrInvalidInput
+	}
+	return s.scheduleRepo.Deactivate(ctx, id)
+}
+
+func (s *scheduleService) GenerateSlots(ctx context.Context, practitionerID string, from, to time.Time) (int, error) {
+	if practitionerID == "" {
+		return 0, domain.ErrInvalidInput
+	}
+	if to.Before(from) {
+		return 0, domain.NewCustomError("INVALID_RANGE", "to must not be before from", "")
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > s.maxDays {
+		return 0, domain.NewCustomError("RANGE_TOO_LONG", fmt.Sprintf("Slots can be generated for at most %d days at once", s.maxDays), "")
+	}
+
+	schedules, err := s.scheduleRepo.ListByPractitioner(ctx, practitionerID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Slots that have already started are not bookable and are left out
+	now := time.Now()
+	var slots []*domain.Slot
+	for _, schedule := range schedules {
+		for _, slot := range schedule.Slots(from, to, s.loc) {
+			if slot.StartAt.After(now) {
+				slots = append(slots, slot)
+			}
+		}
+	}
+	if len(slots) == 0 {
+		return 0, nil
+	}
+
+	return s.slotRepo.CreateMany(ctx, slots)
+}
+
+func (s *scheduleService) ListSlots(ctx context.Context, filter domain.SlotFilter) ([]*domain.Slot, error) {
+	if filter.PractitionerID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if !filter.To.After(filter.From) {
+		return nil, domain.NewCustomError("INVALID_RANGE", "to must be after from", "")
+	}
+	if filter.Limit <= 0 || filter.Limit > maxListSlots {
+		filter.Limit = maxListSlots
+	}
+	return s.slotRepo.List(ctx, filter)
+}
+
+func (s *scheduleService) BlockSlot(ctx context.Context, id string) (*domain.Slot, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.slotRepo.SetBlocked(ctx, id, true)
+}
+
+func (s *scheduleService) UnblockSlot(ctx context.Context, id string) (*domain.Slot, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.slotRepo.SetBlocked(ctx, id, false)
+}
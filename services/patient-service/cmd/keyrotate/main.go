@@ -0,0 +1,122 @@
+// Key rotation command
+// Re-encrypts patient identifiers under the active key-encryption key.
+//
+//	go run ./cmd/keyrotate -new-key k2 -batch 500
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"patient-service/internal/config"
+	"patient-service/internal/database"
+	"patient-service/internal/repository"
+	"patient-service/pkg/envelope"
+)
+
+func main() {
+	initKeyring := flag.Bool("init", false, "create the keyring file with a first key if it does not exist, then exit")
+	newKey := flag.String("new-key", "", "generate a new key-encryption key with this ID and make it active before rotating")
+	batchSize := flag.Int("batch", 500, "number of rows re-encrypted per transaction")
+	pause := flag.Duration("pause", 100*time.Millisecond, "pause between batches to limit database load")
+	dryRun := flag.Bool("dry-run", false, "only report how many rows need re-encryption")
+	wait := flag.Duration("wait", -1, "wait after -new-key for running services to load the key; defaults to twice ENCRYPTION_KEYRING_REFRESH_SECONDS")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	cfg := config.Load()
+
+	if *initKeyring {
+		if _, err := os.Stat(cfg.Encryption.KeyringFile); err == nil {
+			log.Printf("Keyring %s already exists", cfg.Encryption.KeyringFile)
+			return
+		}
+		if err := envelope.GenerateKeyring(cfg.Encryption.KeyringFile, "k1"); err != nil {
+			log.Fatalf("Failed to create keyring: %v", err)
+		}
+		log.Printf("Keyring created at %s", cfg.Encryption.KeyringFile)
+		return
+	}
+
+	kms, err := envelope.NewLocalKMS(cfg.Encryption.KeyringFile)
+	if err != nil {
+		log.Fatalf("Failed to load keyring: %v", err)
+	}
+
+	if *newKey != "" && !*dryRun {
+		if err := kms.Rotate(*newKey); err != nil {
+			log.Fatalf("Failed to generate key %s: %v", *newKey, err)
+		}
+		log.Printf("Key %s generated and activated", *newKey)
+
+		// Running services wrap new writes with the key they know as active
+		// until they reload the keyring; rows they write before then would
+		// be missed by this rotation
+		if *wait < 0 {
+			*wait = 2 * cfg.Encryption.KeyringRefresh
+		}
+		log.Printf("Waiting %s for running services to load key %s", *wait, *newKey)
+		time.Sleep(*wait)
+	}
+
+	blindIndexKey, err := base64.StdEncoding.DecodeString(cfg.Encryption.BlindIndexKey)
+	if err != nil {
+		log.Fatalf("Invalid BLIND_INDEX_KEY: %v", err)
+	}
+
+	cipher, err := envelope.NewCipher(kms, blindIndexKey)
+	if err != nil {
+		log.Fatalf("Failed to initialize encryption: %v", err)
+	}
+
+	db, err := database.NewConnection(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	rotator := repository.NewKeyRotator(db, cipher)
+
+	pending, err := rotator.Pending(ctx)
+	if err != nil {
+		log.Fatalf("Failed to count rows: %v", err)
+	}
+	log.Printf("%d rows to re-encrypt under key %s", pending, cipher.ActiveKeyID())
+
+	if *dryRun || pending == 0 {
+		return
+	}
+
+	var (
+		lastID string
+		total  int
+	)
+
+	for {
+		next, rotated, err := rotator.RotateBatch(ctx, lastID, *batchSize)
+		if err != nil {
+			log.Fatalf("Rotation stopped after %d rows (resume is safe): %v", total, err)
+		}
+		if next == "" {
+			break
+		}
+
+		lastID = next
+		total += rotated
+		log.Printf("Re-encrypted %d/%d rows", total, pending)
+
+		time.Sleep(*pause)
+	}
+
+	log.Printf("Key rotation completed: %d rows re-encrypted", total)
+}
@@ -2,22 +2,53 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/joho/godotenv"
+	"google.golang.org/grpc"
 
+	"patient-service/internal/access"
+	"patient-service/internal/changes"
 	"patient-service/internal/config"
+	"patient-service/internal/consent"
 	"patient-service/internal/database"
+	"patient-service/internal/deident"
+	"patient-service/internal/document"
+	"patient-service/internal/domain"
+	"patient-service/internal/events"
+	"patient-service/internal/export"
+	"patient-service/internal/fhir"
+	"patient-service/internal/grpcserver"
 	"patient-service/internal/handler"
+	"patient-service/internal/hl7"
+	"patient-service/internal/importer"
+	"patient-service/internal/integration/satusehat"
+	"patient-service/internal/label"
 	"patient-service/internal/middleware"
+	"patient-service/internal/mllp"
+	"patient-service/internal/practitioners"
+	"patient-service/internal/qrtoken"
 	"patient-service/internal/repository"
+	"patient-service/internal/search"
 	"patient-service/internal/service"
+	"patient-service/internal/tenant"
+	"patient-service/internal/webhook"
+	"patient-service/pkg/blobstore"
+	"patient-service/pkg/envelope"
+	"patient-service/pkg/filestore"
+	"patient-service/pkg/utils"
 	"patient-service/pkg/validator"
 )
 
@@ -37,23 +68,228 @@ func main() {
 	}
 	defer db.Close()
 
+	// Initialize field encryption
+	kms, err := envelope.NewLocalKMS(cfg.Encryption.KeyringFile)
+	if err != nil {
+		log.Fatalf("Failed to load encryption keyring: %v", err)
+	}
+
+	blindIndexKey, err := base64.StdEncoding.DecodeString(cfg.Encryption.BlindIndexKey)
+	if err != nil {
+		log.Fatalf("Invalid BLIND_INDEX_KEY: %v", err)
+	}
+
+	cipher, err := envelope.NewCipher(kms, blindIndexKey)
+	if err != nil {
+		log.Fatalf("Failed to initialize encryption: %v", err)
+	}
+
+	// Rows from before encryption have no NIK blind index, so NIK lookups
+	// and the duplicate check on create would miss them; they are encrypted
+	// before any request is served
+	if err := encryptPlaintextPatients(context.Background(), repository.NewKeyRotator(db, cipher)); err != nil {
+		log.Fatalf("Failed to encrypt existing patients: %v", err)
+	}
+
 	// Initialize validator
 	validate := validator.New()
 
+	// Facilities sharing the service; tokens act for one of them
+	tenants, err := tenant.Load(cfg.Tenants.File, cfg.Tenants.Default)
+	if err != nil {
+		log.Fatalf("Failed to load tenants: %v", err)
+	}
+
 	// Initialize repositories
-	patientRepo := repository.NewPatientRepository(db)
+	patientRepo := repository.NewPatientRepository(db, cipher)
+	identifierRepo := repository.NewIdentifierRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db, cipher)
+	consentRepo := repository.NewConsentRepository(db)
+
+	// Exports, webhooks and integrations check consent before releasing
+	// a patient's data
+	consentChecker := consent.NewChecker(consentRepo, cfg.Consent.OptOut)
+
+	// Background workers stop when the server shuts down
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Pick up keys added and activated by cmd/keyrotate
+	go kms.Watch(ctx, cfg.Encryption.KeyringRefresh)
+
+	// Initialize search index
+	searchSyncer := search.NewSyncer(patientRepo, cfg.Search.RefreshInterval)
+	go searchSyncer.Run(ctx)
+
+	// Deliver events to webhook subscriptions
+	webhookDeliverer := webhook.NewDeliverer(webhookRepo, webhook.DelivererConfig{
+		Interval:     cfg.Webhooks.PollInterval,
+		Timeout:      cfg.Webhooks.Timeout,
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		DisableAfter: cfg.Webhooks.DisableAfter,
+		Retention:    cfg.Webhooks.Retention,
+	})
+	go webhookDeliverer.Run(ctx)
+
+	// Relay patient events from the outbox to the broker and to webhook
+	// subscriptions
+	eventPublisher, err := newEventPublisher(cfg.Events)
+	if err != nil {
+		log.Fatalf("Failed to initialize event publisher: %v", err)
+	}
+	if !domain.IsConsentType(cfg.Webhooks.ConsentType) {
+		log.Fatalf("Invalid WEBHOOK_DEFAULT_CONSENT_TYPE %q", cfg.Webhooks.ConsentType)
+	}
+	var relayPublisher events.EventPublisher = webhook.NewDispatcher(webhookRepo, webhookDeliverer, consentChecker, cfg.Webhooks.ConsentType)
+	if eventPublisher != nil {
+		relayPublisher = events.Fanout(eventPublisher, relayPublisher)
+	}
+	defer relayPublisher.Close()
+	relay := events.NewRelay(repository.NewOutboxRepository(db), relayPublisher, cfg.Events.Source, cfg.Events.RelayInterval, cfg.Events.Retention)
+	go relay.Run(ctx)
+
+	// Bulk imports run in the background
+	importRepo := repository.NewImportRepository(db, cipher)
+	importWorker := importer.NewWorker(importRepo, patientRepo, tenants, validate, importer.WorkerConfig{
+		Interval:  cfg.Import.PollInterval,
+		BatchSize: cfg.Import.BatchSize,
+		Lease:     cfg.Import.Lease,
+		Retention: cfg.Import.Retention,
+	})
+	go importWorker.Run(ctx)
+
+	// Large exports are written to the file store in the background
+	exportStore, err := filestore.NewLocal(cfg.Export.Dir)
+	if err != nil {
+		log.Fatalf("Failed to initialize export file store: %v", err)
+	}
+	pseudonymKey, err := base64.StdEncoding.DecodeString(cfg.Deident.PseudonymKey)
+	if err != nil {
+		log.Fatalf("Invalid DEIDENT_PSEUDONYM_KEY: %v", err)
+	}
+	if len(pseudonymKey) > 0 && len(pseudonymKey) < 32 {
+		log.Fatalf("Invalid DEIDENT_PSEUDONYM_KEY: must be at least 32 bytes")
+	}
+	deidentCfg := deident.Config{
+		Key: pseudonymKey,
+		Defaults: domain.DeidentRuleset{
+			DateOfBirth:           cfg.Deident.DateOfBirth,
+			AgeBandYears:          cfg.Deident.AgeBandYears,
+			TopAge:                cfg.Deident.TopAge,
+			PostalDigits:          cfg.Deident.PostalDigits,
+			QuasiIdentifiers:      cfg.Deident.QuasiIdentifiers,
+			K:                     cfg.Deident.K,
+			MaxSuppressionPercent: float64(cfg.Deident.MaxSuppressionPercent),
+		},
+	}
+
+	exportRepo := repository.NewExportRepository(db)
+	exportWorker := export.NewWorker(exportRepo, patientRepo, consentChecker, exportStore, export.WorkerConfig{
+		Interval:     cfg.Export.PollInterval,
+		Lease:        cfg.Export.Lease,
+		Retention:    cfg.Export.Retention,
+		PseudonymKey: pseudonymKey,
+	})
+	go exportWorker.Run(ctx)
+
+	// Patient documents in blob storage
+	documentStore, err := newBlobStore(cfg.Documents)
+	if err != nil {
+		log.Fatalf("Failed to initialize document storage: %v", err)
+	}
+	// Links signed with a random key only work on this instance until it restarts
+	documentURLKey := signingKey("DOCUMENT_URL_KEY", cfg.Documents.URLKey, "document download links")
+	var documentScanner document.Scanner
+	if cfg.Documents.ClamAVAddr != "" {
+		documentScanner = document.NewClamAV(cfg.Documents.ClamAVAddr, cfg.Documents.ScanTimeout)
+	}
+	documentService := service.NewDocumentService(patientRepo, repository.NewDocumentRepository(db), documentStore,
+		documentScanner, document.NewURLSigner(documentURLKey, cfg.Documents.URLTTL))
+
+	// Patient cards and wristbands
+	labelTemplates, err := label.LoadTemplates(cfg.Labels.TemplateFile, cfg.Labels.HospitalName)
+	if err != nil {
+		log.Fatalf("Failed to load label templates: %v", err)
+	}
+	labelHandler := handler.NewLabelHandler(service.NewLabelService(patientRepo, labelTemplates, tenants))
+
+	// Public patient profiles resolved from signed tokens
+	var qrTokenHandler *handler.QRTokenHandler
+	if cfg.QRTokens.Enabled {
+		qrTokenKey := signingKey("QR_TOKEN_KEY", cfg.QRTokens.Key, "public profile tokens")
+		qrTokenService := service.NewQRTokenService(patientRepo, repository.NewQRTokenRepository(db),
+			qrtoken.NewSigner(qrTokenKey), cfg.QRTokens.TTL, cfg.QRTokens.MaxTTL)
+		qrTokenHandler = handler.NewQRTokenHandler(qrTokenService, validate)
+	}
+
+	// Live change feed for SSE subscribers
+	changeRepo := repository.NewChangeRepository(db)
+	changeHub := changes.NewHub(changeRepo, cfg.Events.FeedInterval)
+	go changeHub.Run(ctx)
 
 	// Initialize services
-	patientService := service.NewPatientService(patientRepo)
+	var patientService service.PatientService = service.NewPatientService(patientRepo, tenants)
+	searchService := service.NewPatientSearchService(patientRepo, searchSyncer)
+
+	// SATUSEHAT IHS number lookups for new patients and changed NIKs
+	if cfg.SatuSehat.ClientID != "" {
+		satusehatClient := satusehat.NewClient(satusehat.Config{
+			AuthURL:      cfg.SatuSehat.AuthURL,
+			BaseURL:      cfg.SatuSehat.BaseURL,
+			ClientID:     cfg.SatuSehat.ClientID,
+			ClientSecret: cfg.SatuSehat.ClientSecret,
+		})
+		identitySyncer := satusehat.NewSyncer(satusehatClient, patientRepo, identifierRepo,
+			repository.NewIdentitySyncQueue(db), consentChecker, cfg.SatuSehat.RetryInterval, cfg.SatuSehat.MaxAttempts)
+		go identitySyncer.Run(ctx)
+		patientService = satusehat.NewTrigger(patientService, identitySyncer)
+	}
 
-	// Initialize Fiber app
+	// HL7 v2 over MLLP. Inbound messages are applied to the service
+	// without the emitter so they are not echoed back downstream.
+	hl7App := hl7.Application{
+		SendingApplication:   cfg.HL7.SendingApplication,
+		SendingFacility:      cfg.HL7.SendingFacility,
+		ReceivingApplication: cfg.HL7.ReceivingApplication,
+		ReceivingFacility:    cfg.HL7.ReceivingFacility,
+	}
+	if cfg.HL7.ListenAddr != "" {
+		mllpServer := &mllp.Server{
+			Addr:    cfg.HL7.ListenAddr,
+			Handler: hl7.NewProcessor(patientService, hl7App, tenants, "hl7"),
+		}
+		go func() {
+			if err := mllpServer.ListenAndServe(ctx); err != nil {
+				log.Fatalf("Failed to start MLLP listener: %v", err)
+			}
+		}()
+	}
+	if cfg.HL7.EmitAddr != "" {
+		emitter := hl7.NewEmitter(patientService, mllp.NewClient(cfg.HL7.EmitAddr), hl7App, tenants, 1000)
+		go emitter.Run(ctx)
+		patientService = emitter
+	}
+
+	// Initialize Fiber app; the body limit leaves room for import and
+	// document uploads
+	maxUpload := int64(cfg.Import.MaxUploadMB) << 20
+	maxDocument := int64(cfg.Documents.MaxUploadMB) << 20
+	bodyLimit := 4 << 20
+	for _, limit := range []int64{maxUpload, maxDocument} {
+		if int(limit)+(1<<20) > bodyLimit {
+			bodyLimit = int(limit) + (1 << 20)
+		}
+	}
 	app := fiber.New(fiber.Config{
 		ErrorHandler: customErrorHandler,
+		BodyLimit:    bodyLimit,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	})
 
+	app.Server().HeaderReceived = handler.StreamRequestConfig
+
 	// Global middleware
 	app.Use(recover.New())
 	app.Use(middleware.Logger())
@@ -72,23 +308,139 @@ func main() {
 	// API routes
 	api := app.Group("/api/v1")
 
-	// Public routes
-	api.Get("/patients/:id/public", handler.GetPatientPublicInfo(patientService))
+	// Public routes; document downloads and patient profiles are authorized
+	// by a signed link or token
+	if qrTokenHandler != nil {
+		api.Get("/public/patients/:token", limiter.New(limiter.Config{
+			Max:        cfg.QRTokens.RateLimit,
+			Expiration: time.Minute,
+			LimitReached: func(c *fiber.Ctx) error {
+				return utils.ErrorResponse(c, fiber.StatusTooManyRequests, "RATE_LIMITED", "Too many requests", "")
+			},
+		}), qrTokenHandler.ResolveToken)
+	}
+	documentHandler := handler.NewDocumentHandler(documentService, validate, maxDocument)
+	api.Get("/patients/:id/documents/:documentId/content", documentHandler.DownloadDocument)
 
 	// Protected routes
-	protected := api.Group("/", middleware.JWTAuth(cfg.JWT.Secret))
+	protected := api.Group("/", middleware.JWTAuth(cfg.JWT.Secret, tenants))
 
 	// Patient routes
 	patientHandler := handler.NewPatientHandler(patientService, validate)
+	patientHandler.RegisterExpansion("identifiers", handler.IdentifierExpansion(identifierRepo))
+	if cfg.Practitioners.URL != "" {
+		// Audit user IDs are resolved to practitioner names
+		practitionerClient := practitioners.NewClient(cfg.Practitioners.URL, cfg.Practitioners.Timeout, cfg.Practitioners.CacheTTL)
+		patientHandler.RegisterExpansion("audit", handler.AuditExpansion(practitionerClient))
+	}
+	searchHandler := handler.NewSearchHandler(searchService, validate)
 	protected.Post("/patients", patientHandler.CreatePatient)
+
+	// Bulk imports
+	importHandler := handler.NewImportHandler(service.NewImportService(importRepo, importWorker), maxUpload)
+	imports := protected.Group("/patients/imports", middleware.RequireRole("admin", "registration"))
+	imports.Post("/", importHandler.StartImport)
+	imports.Get("/:id", importHandler.GetImport)
+	imports.Get("/:id/errors", importHandler.GetErrorReport)
+
+	protected.Post("/patients\\:batchGet", patientHandler.BatchGetPatients)
+	protected.Get("/patients/search", searchHandler.SearchPatients)
+	exportHandler := handler.NewExportHandler(
+		service.NewExportService(patientRepo, exportRepo, exportStore, exportWorker, consentChecker, deidentCfg), access.DefaultPolicy(), validate)
+	protected.Get("/patients/export", exportHandler.ExportPatients)
+	protected.Post("/patients/exports", exportHandler.StartExport)
+	protected.Post("/patients/exports/deidentified", middleware.RequireRole("admin", access.RoleResearch), exportHandler.StartDeidentifiedExport)
+	protected.Get("/patients/exports/:id", exportHandler.GetExport)
+	protected.Get("/patients/exports/:id/download", exportHandler.DownloadExport)
+	changeService := service.NewChangeService(changeRepo)
+	changeHandler := handler.NewChangeHandler(changeService, changeHub, access.DefaultPolicy(), validate)
+	protected.Get("/patients/changes", changeHandler.ListChanges)
+	protected.Get("/patients/changes/stream", changeHandler.StreamChanges)
 	protected.Get("/patients/:id", patientHandler.GetPatient)
+
+	// Patient documents
+	protected.Get("/patients/:id/documents", documentHandler.ListDocuments)
+	protected.Post("/patients/:id/documents", middleware.RequireRole("admin", "registration"), documentHandler.UploadDocument)
+	protected.Get("/patients/:id/documents/:documentId", documentHandler.GetDocument)
+	protected.Delete("/patients/:id/documents/:documentId", middleware.RequireRole("admin", "registration"), documentHandler.DeleteDocument)
+
+	// Patient cards and wristbands; wristbands are also printed on wards
+	protected.Get("/patients/:id/card", middleware.RequireRole("admin", "registration"), labelHandler.GetCard)
+	protected.Get("/patients/:id/wristband", middleware.RequireRole("admin", "registration", "nurse"), labelHandler.GetWristband)
+
+	// Deceased status; deaths are recorded by clinicians and cleared by admins
+	protected.Post("/patients/:id/deceased", middleware.RequireRole("admin", "doctor"), patientHandler.RecordDeath)
+	protected.Delete("/patients/:id/deceased", middleware.RequireRole("admin"), patientHandler.ClearDeath)
+
+	// The patient's records at other facilities
+	protected.Get("/patients/:id/links", middleware.RequireRole("admin", "doctor", "nurse", "registration"), patientHandler.GetPatientLinks)
+
+	// Public profile tokens; scans are audited and listed to admins only
+	if qrTokenHandler != nil {
+		protected.Get("/patients/:id/qr-tokens", qrTokenHandler.ListTokens)
+		protected.Post("/patients/:id/qr-tokens", middleware.RequireRole("admin", "registration", "nurse"), qrTokenHandler.IssueToken)
+		protected.Post("/patients/:id/qr-tokens/:tokenId/revoke", middleware.RequireRole("admin", "registration", "nurse"), qrTokenHandler.RevokeToken)
+		protected.Get("/patients/:id/qr-tokens/:tokenId/scans", middleware.RequireRole("admin"), qrTokenHandler.ListScans)
+	}
+
+	// Patient consents; recording and revoking is limited to registration
+	consentHandler := handler.NewConsentHandler(service.NewConsentService(patientRepo, consentRepo, consentChecker), validate)
+	protected.Get("/patients/:id/consents", consentHandler.ListConsents)
+	protected.Post("/patients/:id/consents", middleware.RequireRole("admin", "registration"), consentHandler.CreateConsent)
+	protected.Get("/patients/:id/consents/status", consentHandler.GetConsentStatus)
+	protected.Get("/patients/:id/consents/:consentId", consentHandler.GetConsent)
+	protected.Post("/patients/:id/consents/:consentId/revoke", middleware.RequireRole("admin", "registration"), consentHandler.RevokeConsent)
+
 	protected.Put("/patients/:id", patientHandler.UpdatePatient)
+	protected.Patch("/patients/:id", patientHandler.PatchPatient)
 	protected.Delete("/patients/:id", patientHandler.DeletePatient)
 	protected.Get("/patients", patientHandler.ListPatients)
 
+	// Webhook subscriptions (admin only)
+	webhookHandler := handler.NewWebhookHandler(service.NewWebhookService(webhookRepo), validate)
+	webhooks := protected.Group("/webhooks", middleware.RequireRole("admin"))
+	webhooks.Post("/", webhookHandler.CreateWebhook)
+	webhooks.Get("/", webhookHandler.ListWebhooks)
+	webhooks.Get("/dead-letters", webhookHandler.ListDeadLetters)
+	webhooks.Get("/:id", webhookHandler.GetWebhook)
+	webhooks.Put("/:id", webhookHandler.UpdateWebhook)
+	webhooks.Delete("/:id", webhookHandler.DeleteWebhook)
+	webhooks.Post("/:id/rotate-secret", webhookHandler.RotateWebhookSecret)
+	webhooks.Get("/:id/deliveries", webhookHandler.ListDeliveries)
+	webhooks.Get("/:id/deliveries/:deliveryId", webhookHandler.GetDelivery)
+	webhooks.Post("/:id/deliveries/:deliveryId/replay", webhookHandler.ReplayDelivery)
+
+	// FHIR R4 facade; the CapabilityStatement is public
+	fhirHandler := handler.NewFHIRHandler(patientService, searchService, fhir.Mapper{MRNSystem: cfg.FHIR.MRNSystem}, tenants, cfg.FHIR.BaseURL, cfg.App.Version)
+	fhirAPI := app.Group("/fhir/R4")
+	fhirAPI.Get("/metadata", fhirHandler.Capabilities)
+	fhirProtected := fhirAPI.Group("/", middleware.JWTAuth(cfg.JWT.Secret, tenants))
+	fhirProtected.Post("/Patient/$match", fhirHandler.MatchPatient)
+	fhirProtected.Get("/Patient", fhirHandler.SearchPatients)
+	fhirProtected.Post("/Patient", fhirHandler.CreatePatient)
+	fhirProtected.Get("/Patient/:id", fhirHandler.ReadPatient)
+	fhirProtected.Put("/Patient/:id", fhirHandler.UpdatePatient)
+
 	// Metrics endpoint (untuk Prometheus)
 	app.Get("/metrics", middleware.PrometheusHandler())
 
+	// gRPC API on its own port, served by the same services
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Port != "" {
+		grpcServer = grpcserver.NewGRPCServer(
+			grpcserver.NewServer(patientService, changeService, changeHub, access.DefaultPolicy(), validate),
+			cfg.JWT.Secret, tenants)
+		lis, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
 	// Graceful shutdown
 	go func() {
 		if err := app.Listen(":" + cfg.App.Port); err != nil {
@@ -102,6 +454,11 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	cancel()
+	if grpcServer != nil {
+		// Watch streams end when the change hub stops
+		grpcServer.GracefulStop()
+	}
 	if err := app.Shutdown(); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
@@ -109,6 +466,98 @@ func main() {
 	log.Println("Server exited")
 }
 
+// encryptPlaintextPatients encrypts the patient rows still stored in
+// plaintext. Instances starting together share the work through the row
+// locks of the batches.
+func encryptPlaintextPatients(ctx context.Context, rotator *repository.KeyRotator) error {
+	pending, err := rotator.Unencrypted(ctx)
+	if err != nil || pending == 0 {
+		return err
+	}
+	log.Printf("Encrypting %d plaintext patient rows", pending)
+
+	var (
+		lastID string
+		total  int
+	)
+	for {
+		next, encrypted, err := rotator.EncryptBatch(ctx, lastID, 500)
+		if err != nil {
+			return fmt.Errorf("stopped after %d rows: %w", total, err)
+		}
+		if next == "" {
+			break
+		}
+		lastID = next
+		total += encrypted
+	}
+
+	log.Printf("Encrypted %d plaintext patient rows", total)
+	return nil
+}
+
+// signingKey decodes a base64 HMAC key of at least 32 bytes, or returns a
+// random one when the variable is not set.
+func signingKey(name, value, purpose string) []byte {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) > 0 && len(key) < 32 {
+		log.Fatalf("Invalid %s: must be base64 of at least 32 bytes", name)
+	}
+	if len(key) == 0 {
+		log.Printf("%s is not set; using a random key for %s", name, purpose)
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			log.Fatalf("Failed to generate %s: %v", name, err)
+		}
+	}
+	return key
+}
+
+// newBlobStore returns the configured document storage.
+func newBlobStore(cfg config.DocumentConfig) (blobstore.BlobStore, error) {
+	switch cfg.Storage {
+	case "local", "":
+		store, err := blobstore.NewLocal(cfg.Dir)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case "s3":
+		store, err := blobstore.NewS3(blobstore.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Prefix:    cfg.S3Prefix,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+	return nil, fmt.Errorf("unknown DOCUMENT_STORAGE %q", cfg.Storage)
+}
+
+// newEventPublisher returns the configured publisher, or nil for "none".
+func newEventPublisher(cfg config.EventsConfig) (events.EventPublisher, error) {
+	switch cfg.Publisher {
+	case "none", "":
+		return nil, nil
+	case "webhook":
+		headers := map[string]string{}
+		if cfg.WebhookToken != "" {
+			headers["Authorization"] = "Bearer " + cfg.WebhookToken
+		}
+		return events.NewWebhookPublisher(cfg.WebhookURL, headers), nil
+	case "nats":
+		return events.NewNATSPublisher(cfg.NATSURL, cfg.NATSSubject)
+	case "kafka":
+		return events.NewKafkaPublisher(cfg.KafkaBrokers, cfg.KafkaTopic), nil
+	}
+	return nil, fmt.Errorf("unknown EVENTS_PUBLISHER %q", cfg.Publisher)
+}
+
 func customErrorHandler(c *fiber.Ctx, err error) error {
 	code := fiber.StatusInternalServerError
 	message := "Internal Server Error"
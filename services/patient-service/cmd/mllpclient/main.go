@@ -0,0 +1,162 @@
+// MLLP test client
+// Sends HL7 v2 messages to the patient-service MLLP listener and prints the
+// ACK, or receives the ADT messages the service emits.
+//
+//	go run ./cmd/mllpclient -sample a04 -nik 3171234567890001 -mrn MR-TEST-1
+//	go run ./cmd/mllpclient -file message.hl7
+//	go run ./cmd/mllpclient -listen :2576
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/hl7"
+	"patient-service/internal/mllp"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:2575", "address of the MLLP listener")
+	file := flag.String("file", "", "send the HL7 message in this file (\"-\" reads stdin); LF line ends are accepted")
+	sample := flag.String("sample", "", "send a sample message: a04, a08 or a40")
+	nik := flag.String("nik", "3171234567890001", "NIK used in sample messages")
+	mrn := flag.String("mrn", "MR-TEST-0001", "medical record number used in sample messages")
+	mergedMRN := flag.String("merged-mrn", "MR-TEST-0002", "medical record number retired by the a40 sample")
+	listen := flag.String("listen", "", "instead of sending, accept messages on this address, print and acknowledge them")
+	flag.Parse()
+
+	if *listen != "" {
+		receive(*listen)
+		return
+	}
+
+	var message []byte
+	switch {
+	case *file == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatalf("Failed to read stdin: %v", err)
+		}
+		message = data
+	case *file != "":
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", *file, err)
+		}
+		message = data
+	case *sample != "":
+		msg, err := sampleMessage(*sample, *nik, *mrn, *mergedMRN)
+		if err != nil {
+			log.Fatal(err)
+		}
+		message = msg.Bytes()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Normalize line ends and validate before sending
+	msg, err := hl7.Parse(message)
+	if err != nil {
+		log.Fatalf("Invalid message: %v", err)
+	}
+	fmt.Printf("--> %s\n%s\n", *addr, msg)
+
+	client := mllp.NewClient(*addr)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	reply, err := client.Send(ctx, msg.Bytes())
+	if err != nil {
+		log.Fatalf("Send failed: %v", err)
+	}
+
+	ack, err := hl7.Parse(reply)
+	if err != nil {
+		log.Fatalf("Invalid ACK: %v", err)
+	}
+	fmt.Printf("<-- ACK\n%s\n", ack)
+
+	if code, _ := hl7.AckCode(ack); code != hl7.AckAccept {
+		os.Exit(1)
+	}
+}
+
+func sampleMessage(kind, nik, mrn, mergedMRN string) (*hl7.Message, error) {
+	patient := &domain.Patient{
+		MedicalRecordNo:  mrn,
+		NIK:              nik,
+		FirstName:        "Siti",
+		LastName:         "Aminah",
+		DateOfBirth:      time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Gender:           "FEMALE",
+		Phone:            "081234567890",
+		Email:            "siti.aminah@example.com",
+		Address:          "Jl. Sudirman No. 1",
+		City:             "Jakarta",
+		Province:         "DKI Jakarta",
+		PostalCode:       "10220",
+		EmergencyContact: "Budi Santoso",
+		EmergencyPhone:   "081298765432",
+		Allergies:        "Penicillin",
+	}
+
+	opts := hl7.ADTOptions{
+		Application: hl7.Application{
+			SendingApplication:   "MLLPCLIENT",
+			SendingFacility:      "TEST",
+			ReceivingApplication: "PATIENT-SERVICE",
+			ReceivingFacility:    "HOSPITAL",
+		},
+		ControlID: hl7.NewControlID(),
+	}
+
+	switch kind {
+	case "a04":
+		return hl7.BuildADT("A04", patient, opts), nil
+	case "a08":
+		patient.Phone = "081311112222"
+		patient.City = "Bandung"
+		patient.Province = "Jawa Barat"
+		return hl7.BuildADT("A08", patient, opts), nil
+	case "a40":
+		opts.MergedMRN = mergedMRN
+		return hl7.BuildADT("A40", patient, opts), nil
+	}
+	return nil, fmt.Errorf("unknown sample %q, use a04, a08 or a40", kind)
+}
+
+// receive acts as a downstream system for HL7_EMIT_ADDR.
+func receive(addr string) {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	app := hl7.Application{SendingApplication: "MLLPCLIENT", SendingFacility: "TEST"}
+	server := &mllp.Server{
+		Addr: addr,
+		Handler: mllp.HandlerFunc(func(ctx context.Context, data []byte) []byte {
+			msg, err := hl7.Parse(data)
+			if err != nil {
+				log.Printf("Invalid message: %v", err)
+				return nil
+			}
+			fmt.Printf("<-- %s\n%s\n", time.Now().Format(time.RFC3339), msg)
+			return hl7.BuildACK(msg, app, hl7.NewControlID(), nil).Bytes()
+		}),
+	}
+
+	log.Printf("Listening for MLLP on %s", addr)
+	if err := server.ListenAndServe(ctx); err != nil {
+		log.Fatalf("Listener failed: %v", err)
+	}
+}
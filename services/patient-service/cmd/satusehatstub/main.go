@@ -0,0 +1,56 @@
+// Local SATUSEHAT stub
+// Serves the fake SATUSEHAT token and Patient search endpoints so the IHS
+// number sync can be exercised without sandbox credentials.
+//
+//	go run ./cmd/satusehatstub -addr :8089 -patient 3171234567890001=P02478375538
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"patient-service/internal/integration/satusehat/satusehattest"
+)
+
+type patientFlags map[string]string
+
+func (p patientFlags) String() string { return fmt.Sprint(map[string]string(p)) }
+
+func (p patientFlags) Set(value string) error {
+	nik, ihs, ok := strings.Cut(value, "=")
+	if !ok || nik == "" || ihs == "" {
+		return fmt.Errorf("expected NIK=IHS_NUMBER, got %q", value)
+	}
+	p[nik] = ihs
+	return nil
+}
+
+func main() {
+	addr := flag.String("addr", ":8089", "listen address")
+	clientID := flag.String("client-id", "client-id", "accepted client ID")
+	clientSecret := flag.String("client-secret", "client-secret", "accepted client secret")
+	patients := patientFlags{}
+	flag.Var(patients, "patient", "register a patient as NIK=IHS_NUMBER (repeatable)")
+	flag.Parse()
+
+	fake := satusehattest.NewFake(*clientID, *clientSecret)
+	for nik, ihs := range patients {
+		fake.AddPatient(nik, ihs)
+	}
+
+	host := *addr
+	if strings.HasPrefix(host, ":") {
+		host = "localhost" + host
+	}
+	log.Printf("SATUSEHAT stub listening on %s with %d patient(s)", *addr, len(patients))
+	log.Printf("SATUSEHAT_AUTH_URL=http://%s%s", host, satusehattest.AuthPath)
+	log.Printf("SATUSEHAT_BASE_URL=http://%s%s", host, satusehattest.FHIRPath)
+	log.Printf("SATUSEHAT_CLIENT_ID=%s SATUSEHAT_CLIENT_SECRET=%s", *clientID, *clientSecret)
+
+	if err := http.ListenAndServe(*addr, fake); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+}
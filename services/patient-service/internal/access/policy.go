@@ -0,0 +1,83 @@
+// Role-based field access
+// internal/access/policy.go
+package access
+
+// Roles carried in the JWT role claim
+const (
+	RoleAdmin        = "admin"
+	RoleDoctor       = "doctor"
+	RoleNurse        = "nurse"
+	RoleRegistration = "registration"
+	RoleWard         = "ward"     // ward dashboards and bed boards
+	RoleResearch     = "research" // de-identified datasets only
+)
+
+// Field groups, by JSON field name
+var (
+	IdentityFields = []string{"medical_record_no", "first_name", "last_name", "date_of_birth", "gender"}
+	ContactFields  = []string{
+		"nik", "phone", "email", "address", "city", "province", "postal_code",
+		"emergency_contact", "emergency_phone", "insurance_provider", "insurance_number",
+	}
+	ClinicalFields = []string{"blood_type", "allergies", "chronic_conditions", "cause_of_death", "death_recorded_by"}
+	AuditFields    = []string{"actor", "created_by", "updated_by"}
+)
+
+// Policy lists the patient fields each role may read. Fields outside the
+// groups above (IDs, timestamps, status) are readable by every role;
+// grouped fields only by roles granted them.
+type Policy struct {
+	governed map[string]bool
+	grants   map[string]map[string]bool
+}
+
+// NewPolicy creates a policy from role grants; roles without a grant can
+// read no grouped field.
+func NewPolicy(grants map[string][]string) *Policy {
+	p := &Policy{governed: make(map[string]bool), grants: make(map[string]map[string]bool)}
+	for _, group := range [][]string{IdentityFields, ContactFields, ClinicalFields, AuditFields} {
+		for _, field := range group {
+			p.governed[field] = true
+		}
+	}
+	for role, fields := range grants {
+		p.grants[role] = make(map[string]bool)
+		for _, field := range fields {
+			p.grants[role][field] = true
+		}
+	}
+	return p
+}
+
+// DefaultPolicy returns the hospital's standard grants.
+func DefaultPolicy() *Policy {
+	return NewPolicy(map[string][]string{
+		RoleAdmin:        fields(IdentityFields, ContactFields, ClinicalFields, AuditFields),
+		RoleDoctor:       fields(IdentityFields, ContactFields, ClinicalFields),
+		RoleNurse:        fields(IdentityFields, ContactFields, ClinicalFields),
+		RoleRegistration: fields(IdentityFields, ContactFields),
+		RoleWard:         fields(IdentityFields),
+	})
+}
+
+// CanRead reports whether the role may read the field.
+func (p *Policy) CanRead(role, field string) bool {
+	return !p.governed[field] || p.grants[role][field]
+}
+
+// Redact removes the fields the role may not read.
+func (p *Policy) Redact(role string, fields map[string]interface{}) {
+	for field := range fields {
+		if !p.CanRead(role, field) {
+			delete(fields, field)
+		}
+	}
+}
+
+func fields(groups ...[]string) []string {
+	var all []string
+	for _, group := range groups {
+		all = append(all, group...)
+	}
+	return all
+}
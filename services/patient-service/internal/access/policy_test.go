@@ -0,0 +1,46 @@
+package access
+
+import "testing"
+
+func TestDefaultPolicy(t *testing.T) {
+	p := DefaultPolicy()
+
+	cases := []struct {
+		role  string
+		field string
+		want  bool
+	}{
+		{RoleAdmin, "actor", true},
+		{RoleDoctor, "allergies", true},
+		{RoleDoctor, "actor", false},
+		{RoleRegistration, "phone", true},
+		{RoleRegistration, "chronic_conditions", false},
+		{RoleWard, "first_name", true},
+		{RoleWard, "city", false},
+		{"unknown", "first_name", false},
+		{"unknown", "id", true},
+		{"", "is_active", true},
+	}
+	for _, tc := range cases {
+		if got := p.CanRead(tc.role, tc.field); got != tc.want {
+			t.Errorf("CanRead(%q, %q) = %v, want %v", tc.role, tc.field, got, tc.want)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	fields := map[string]interface{}{
+		"id":         "p1",
+		"first_name": "Budi",
+		"city":       "Bandung",
+		"version":    3,
+	}
+	DefaultPolicy().Redact(RoleWard, fields)
+
+	if _, ok := fields["city"]; ok {
+		t.Error("Expected city to be redacted for the ward role")
+	}
+	if fields["id"] != "p1" || fields["first_name"] != "Budi" || fields["version"] != 3 {
+		t.Errorf("Unexpected fields after redaction: %v", fields)
+	}
+}
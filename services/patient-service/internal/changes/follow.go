@@ -0,0 +1,114 @@
+// Following the feed from a position
+// internal/changes/follow.go
+package changes
+
+import (
+	"context"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/tenant"
+)
+
+const followBatch = 500
+
+// Feed reads the change feed from the database; service.ChangeService
+// implements it.
+type Feed interface {
+	ListChanges(ctx context.Context, since string, limit int) (*domain.ChangePage, error)
+}
+
+// Follow sends first, a page already read from feed, then the rest of the
+// feed from the database and then live changes from the hub, in order and
+// without duplicates. Live changes are limited to the tenant of ctx, as
+// feed limits the pages it reads. A subscriber dropped for falling behind catches up
+// from the database again.
+//
+// When idle is set it is called every heartbeat. Follow returns nil when
+// the hub stops, ctx's error when ctx is done, or the first error of feed,
+// send or idle.
+func (h *Hub) Follow(ctx context.Context, feed Feed, first *domain.ChangePage, send func([]*domain.PatientChange) error, heartbeat time.Duration, idle func() error) error {
+	// first was read before subscribing; reading on from it covers what
+	// committed in between
+	sub := h.Subscribe()
+	defer func() { h.Unsubscribe(sub) }()
+
+	tenantID, _ := tenant.FromContext(ctx)
+
+	if err := sendNonEmpty(send, first.Changes); err != nil {
+		return err
+	}
+	last, err := catchUp(ctx, feed, first.Next, send)
+	if err != nil {
+		return err
+	}
+
+	var tick <-chan time.Time
+	if idle != nil {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case batch, ok := <-sub.C:
+			if !ok {
+				if h.Stopped() {
+					return nil
+				}
+				sub = h.Subscribe()
+				if last, err = catchUp(ctx, feed, domain.ChangeToken(last), send); err != nil {
+					return err
+				}
+				continue
+			}
+
+			var fresh []*domain.PatientChange
+			for _, change := range batch {
+				if change.Sequence > last && change.TenantID == tenantID {
+					fresh = append(fresh, change)
+				}
+			}
+			if err := sendNonEmpty(send, fresh); err != nil {
+				return err
+			}
+			if n := len(batch); n > 0 && batch[n-1].Sequence > last {
+				last = batch[n-1].Sequence
+			}
+
+		case <-tick:
+			if err := idle(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// catchUp sends the changes after since from the database and returns the
+// position of the last one.
+func catchUp(ctx context.Context, feed Feed, since string, send func([]*domain.PatientChange) error) (int64, error) {
+	for {
+		page, err := feed.ListChanges(ctx, since, followBatch)
+		if err != nil {
+			return 0, err
+		}
+		if err := sendNonEmpty(send, page.Changes); err != nil {
+			return 0, err
+		}
+		if !page.HasMore {
+			return domain.ParseChangeToken(page.Next)
+		}
+		since = page.Next
+	}
+}
+
+func sendNonEmpty(send func([]*domain.PatientChange) error, batch []*domain.PatientChange) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	return send(batch)
+}
@@ -0,0 +1,161 @@
+// Live change fan-out
+// internal/changes/hub.go
+package changes
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+)
+
+const (
+	pollBatch = 500
+
+	// subscriberBuffer batches may queue for a subscriber before it is
+	// dropped as too slow
+	subscriberBuffer = 64
+)
+
+// Hub polls the outbox once for all live subscribers, so the number of
+// open streams does not multiply database load. Subscribers resume from
+// the database themselves after being dropped.
+type Hub struct {
+	repo     repository.ChangeRepository
+	interval time.Duration
+
+	mu      sync.Mutex
+	subs    map[*Subscription]struct{}
+	stopped bool
+}
+
+// Subscription receives batches of changes in feed order. C is closed when
+// the subscriber falls behind or the hub stops.
+type Subscription struct {
+	C <-chan []*domain.PatientChange
+	c chan []*domain.PatientChange
+}
+
+func NewHub(repo repository.ChangeRepository, interval time.Duration) *Hub {
+	return &Hub{repo: repo, interval: interval, subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe starts receiving changes committed from now on. Changes read
+// before Subscribe must be loaded from the database; a subscriber reading
+// both skips changes at or below the last position it has seen.
+func (h *Hub) Subscribe() *Subscription {
+	c := make(chan []*domain.PatientChange, subscriberBuffer)
+	sub := &Subscription{C: c, c: c}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.stopped {
+		close(c)
+		return sub
+	}
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe stops delivery to the subscription.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.c)
+	}
+}
+
+// Stopped reports whether Run has returned.
+func (h *Hub) Stopped() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.stopped
+}
+
+// Run polls for changes until ctx is cancelled, then closes every
+// subscription.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.stop()
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	var cursor int64 = -1
+	for {
+		if cursor < 0 {
+			_, latest, err := h.repo.Bounds(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Change feed start failed: %v", err)
+			}
+			if err == nil {
+				cursor = latest
+			}
+		}
+
+		for cursor >= 0 {
+			n, err := h.poll(ctx, &cursor)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Change feed poll failed: %v", err)
+			}
+			if err != nil || n < pollBatch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Hub) poll(ctx context.Context, cursor *int64) (int, error) {
+	events, err := h.repo.Since(ctx, *cursor, pollBatch)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	batch := make([]*domain.PatientChange, 0, len(events))
+	for _, event := range events {
+		change, err := domain.NewPatientChange(event)
+		if err != nil {
+			return 0, err
+		}
+		batch = append(batch, change)
+	}
+	*cursor = events[len(events)-1].ID
+
+	h.broadcast(batch)
+	return len(events), nil
+}
+
+func (h *Hub) broadcast(batch []*domain.PatientChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs {
+		select {
+		case sub.c <- batch:
+		default:
+			delete(h.subs, sub)
+			close(sub.c)
+		}
+	}
+}
+
+func (h *Hub) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stopped = true
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		close(sub.c)
+	}
+}
@@ -0,0 +1,188 @@
+package changes
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+// memoryChanges implements repository.ChangeRepository.
+type memoryChanges struct {
+	mu     sync.Mutex
+	events []*domain.OutboxEvent
+}
+
+func (m *memoryChanges) add(eventType, patientID string, version int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event, _ := domain.NewOutboxEvent(eventType, domain.PatientEventData{ID: patientID, Version: version})
+	event.ID = int64(len(m.events) + 1)
+	m.events = append(m.events, event)
+}
+
+func (m *memoryChanges) Since(ctx context.Context, after int64, limit int) ([]*domain.OutboxEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []*domain.OutboxEvent
+	for _, e := range m.events {
+		if e.ID > after && len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (m *memoryChanges) Bounds(ctx context.Context) (int64, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.events) == 0 {
+		return 0, 0, nil
+	}
+	return m.events[0].ID, m.events[len(m.events)-1].ID, nil
+}
+
+func receive(t *testing.T, sub *Subscription) []*domain.PatientChange {
+	t.Helper()
+	select {
+	case batch, ok := <-sub.C:
+		if !ok {
+			t.Fatal("Subscription closed")
+		}
+		return batch
+	case <-time.After(time.Second):
+		t.Fatal("No changes received")
+	}
+	return nil
+}
+
+func TestHubBroadcastsNewChanges(t *testing.T) {
+	repo := &memoryChanges{}
+	repo.add(domain.EventPatientCreated, "old", 1)
+
+	hub := NewHub(repo, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		hub.Run(ctx)
+		close(done)
+	}()
+
+	a, b := hub.Subscribe(), hub.Subscribe()
+	time.Sleep(30 * time.Millisecond)
+	repo.add(domain.EventPatientUpdated, "p1", 2)
+
+	for _, sub := range []*Subscription{a, b} {
+		batch := receive(t, sub)
+		if len(batch) != 1 || batch[0].PatientID != "p1" || batch[0].Operation != "updated" || batch[0].Version != 2 || batch[0].Sequence != 2 {
+			t.Errorf("Expected only the new change, got %+v", batch[0])
+		}
+	}
+
+	hub.Unsubscribe(b)
+	if _, ok := <-b.C; ok {
+		t.Error("Expected an unsubscribed channel to be closed")
+	}
+
+	cancel()
+	<-done
+	if _, ok := <-a.C; ok || !hub.Stopped() {
+		t.Error("Expected subscriptions to close when the hub stops")
+	}
+	if _, ok := <-hub.Subscribe().C; ok {
+		t.Error("Expected Subscribe on a stopped hub to return a closed subscription")
+	}
+}
+
+func TestHubDropsSlowSubscribers(t *testing.T) {
+	hub := NewHub(&memoryChanges{}, time.Second)
+	slow := hub.Subscribe()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.broadcast([]*domain.PatientChange{{Sequence: int64(i + 1)}})
+	}
+
+	n := 0
+	for range slow.C {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Errorf("Expected %d buffered batches before the drop, got %d", subscriberBuffer, n)
+	}
+}
+
+// ListChanges makes memoryChanges a Feed.
+func (m *memoryChanges) ListChanges(ctx context.Context, since string, limit int) (*domain.ChangePage, error) {
+	after, err := domain.ParseChangeToken(since)
+	if err != nil {
+		return nil, err
+	}
+	events, _ := m.Since(ctx, after, limit+1)
+
+	page := &domain.ChangePage{Next: since, HasMore: len(events) > limit}
+	if page.HasMore {
+		events = events[:limit]
+	}
+	for _, event := range events {
+		change, _ := domain.NewPatientChange(event)
+		page.Changes = append(page.Changes, change)
+		page.Next = domain.ChangeToken(change.Sequence)
+	}
+	return page, nil
+}
+
+func TestFollowCatchesUpThenStreams(t *testing.T) {
+	repo := &memoryChanges{}
+	repo.add(domain.EventPatientCreated, "p1", 1)
+
+	hub := NewHub(repo, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	// The first page is read, then more changes commit before Follow
+	// subscribes
+	first, _ := repo.ListChanges(ctx, "0", 10)
+	repo.add(domain.EventPatientUpdated, "p1", 2)
+
+	received := make(chan int64, 10)
+	send := func(batch []*domain.PatientChange) error {
+		for _, change := range batch {
+			received <- change.Sequence
+		}
+		return nil
+	}
+
+	followCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- hub.Follow(followCtx, repo, first, send, time.Hour, nil) }()
+
+	time.Sleep(50 * time.Millisecond)
+	repo.add(domain.EventPatientDeleted, "p1", 3)
+
+	for want := int64(1); want <= 3; want++ {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Fatalf("Expected change %d, got %d", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Change %d not received", want)
+		}
+	}
+
+	stop()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected Follow to end with context.Canceled, got %v", err)
+	}
+	select {
+	case extra := <-received:
+		t.Errorf("Unexpected duplicate change %d", extra)
+	default:
+	}
+}
@@ -4,12 +4,32 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	App           AppConfig
+	GRPC          GRPCConfig
+	Database      DatabaseConfig
+	JWT           JWTConfig
+	Encryption    EncryptionConfig
+	Search        SearchConfig
+	FHIR          FHIRConfig
+	HL7           HL7Config
+	SatuSehat     SatuSehatConfig
+	Events        EventsConfig
+	Webhooks      WebhooksConfig
+	Import        ImportConfig
+	Export        ExportConfig
+	Deident       DeidentConfig
+	Consent       ConsentConfig
+	Documents     DocumentConfig
+	Labels        LabelConfig
+	QRTokens      QRTokenConfig
+	Practitioners PractitionerConfig
+	Tenants       TenantConfig
 }
 
 type AppConfig struct {
@@ -19,6 +39,10 @@ type AppConfig struct {
 	Env     string
 }
 
+type GRPCConfig struct {
+	Port string // gRPC listener, separate from the HTTP port; empty disables it
+}
+
 type DatabaseConfig struct {
 	Host     string
 	Port     string
@@ -33,6 +57,145 @@ type JWTConfig struct {
 	ExpireTime int // in hours
 }
 
+type SearchConfig struct {
+	RefreshInterval time.Duration
+}
+
+type FHIRConfig struct {
+	BaseURL   string // public base of /fhir/R4, derived from the request when empty
+	MRNSystem string // identifier system of this facility's medical record numbers
+}
+
+type HL7Config struct {
+	ListenAddr           string // MLLP listener for inbound ADT, e.g. ":2575"; empty disables it
+	EmitAddr             string // downstream MLLP receiver for outbound ADT; empty disables emission
+	SendingApplication   string
+	SendingFacility      string
+	ReceivingApplication string
+	ReceivingFacility    string
+}
+
+type SatuSehatConfig struct {
+	AuthURL       string
+	BaseURL       string
+	ClientID      string // empty disables IHS number lookups
+	ClientSecret  string
+	RetryInterval time.Duration // how often the retry queue is polled
+	MaxAttempts   int
+}
+
+type EventsConfig struct {
+	Publisher     string // none, webhook, nats or kafka
+	Source        string // CloudEvents source attribute
+	WebhookURL    string
+	WebhookToken  string // sent as a bearer token when set
+	NATSURL       string
+	NATSSubject   string // subject prefix; the event type is appended
+	KafkaBrokers  []string
+	KafkaTopic    string
+	RelayInterval time.Duration
+	Retention     time.Duration // how long events stay in the outbox; also the change feed's reach
+	FeedInterval  time.Duration // change feed poll interval
+}
+
+// WebhooksConfig configures delivery to webhook subscriptions
+type WebhooksConfig struct {
+	PollInterval time.Duration
+	Timeout      time.Duration
+	MaxAttempts  int // then the delivery is dead-lettered
+	DisableAfter int // consecutive failures before a subscription is disabled
+	Retention    time.Duration
+	ConsentType  string // checked for subscriptions without a consent type of their own
+}
+
+// ImportConfig configures bulk patient imports
+type ImportConfig struct {
+	MaxUploadMB  int
+	BatchSize    int // rows inserted per transaction
+	PollInterval time.Duration
+	Lease        time.Duration // a job is resumed elsewhere after this long without progress
+	Retention    time.Duration // finished jobs and error reports
+}
+
+// ExportConfig configures asynchronous patient exports
+type ExportConfig struct {
+	Dir          string // file store directory for export files
+	PollInterval time.Duration
+	Lease        time.Duration // a job is started over elsewhere after this long without progress
+	Retention    time.Duration // finished jobs and their files
+}
+
+// DeidentConfig configures de-identified research exports and the
+// defaults of their ruleset
+type DeidentConfig struct {
+	PseudonymKey          string // base64, at least 32 bytes; empty disables de-identified exports
+	DateOfBirth           string // year or age_band
+	AgeBandYears          int
+	TopAge                int // older patients are reported as one band
+	PostalDigits          int // leading postal code digits kept
+	QuasiIdentifiers      []string
+	K                     int
+	MaxSuppressionPercent int
+}
+
+// ConsentConfig configures consent checks on data release
+type ConsentConfig struct {
+	OptOut []string // consent types allowed unless the patient denies them
+}
+
+// DocumentConfig configures patient document storage
+type DocumentConfig struct {
+	Storage     string // local or s3
+	Dir         string // local storage directory
+	S3Endpoint  string // e.g. http://minio:9000
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3Prefix    string
+	MaxUploadMB int
+	URLKey      string        // base64, at least 32 bytes; empty uses a random key per process
+	URLTTL      time.Duration // lifetime of signed download links
+	ClamAVAddr  string        // clamd host:port; empty disables virus scanning
+	ScanTimeout time.Duration
+}
+
+// LabelConfig configures patient card and wristband printing
+type LabelConfig struct {
+	TemplateFile string // JSON templates per kind and name; empty uses the built-in ones
+	HospitalName string // printed on the built-in templates
+}
+
+// QRTokenConfig configures the public patient profile resolved from
+// signed tokens, e.g. on wristband QR codes
+type QRTokenConfig struct {
+	Enabled   bool          // false removes the public endpoint and token management
+	Key       string        // base64, at least 32 bytes; empty uses a random key per process
+	TTL       time.Duration // validity of tokens issued without one
+	MaxTTL    time.Duration
+	RateLimit int // public lookups per client IP and minute
+}
+
+// PractitionerConfig locates practitioner-service, which resolves the user
+// IDs of the audit expansion to names
+type PractitionerConfig struct {
+	URL      string // e.g. http://practitioner-service:3003; empty disables name lookups
+	Timeout  time.Duration
+	CacheTTL time.Duration // how long resolved names are kept
+}
+
+// TenantConfig configures the hospitals and clinics sharing the service
+type TenantConfig struct {
+	File    string // JSON list of tenants with their settings; empty configures the default tenant only
+	Default string // tenant of tokens without a tenant_id claim and of patients stored before tenants
+}
+
+type EncryptionConfig struct {
+	KeyringFile    string        // local KMS keyring (JSON)
+	BlindIndexKey  string        // base64, at least 32 bytes
+	KeyringRefresh time.Duration // how often the keyring file is reloaded
+}
+
 func Load() *Config {
 	return &Config{
 		App: AppConfig{
@@ -41,6 +204,9 @@ func Load() *Config {
 			Port:    getEnv("APP_PORT", "3001"),
 			Env:     getEnv("APP_ENV", "development"),
 		},
+		GRPC: GRPCConfig{
+			Port: getEnv("GRPC_PORT", "50051"),
+		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "1433"),
@@ -53,6 +219,116 @@ func Load() *Config {
 			Secret:     getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
 			ExpireTime: getEnvAsInt("JWT_EXPIRE_HOURS", 24),
 		},
+		Encryption: EncryptionConfig{
+			KeyringFile:    getEnv("ENCRYPTION_KEYRING_FILE", "./keys/keyring.json"),
+			BlindIndexKey:  getEnv("BLIND_INDEX_KEY", ""),
+			KeyringRefresh: time.Duration(getEnvAsInt("ENCRYPTION_KEYRING_REFRESH_SECONDS", 30)) * time.Second,
+		},
+		Search: SearchConfig{
+			RefreshInterval: time.Duration(getEnvAsInt("SEARCH_REFRESH_SECONDS", 10)) * time.Second,
+		},
+		FHIR: FHIRConfig{
+			BaseURL:   getEnv("FHIR_BASE_URL", ""),
+			MRNSystem: getEnv("FHIR_MRN_SYSTEM", "urn:patient-service:mrn"),
+		},
+		HL7: HL7Config{
+			ListenAddr:           getEnv("HL7_MLLP_ADDR", ""),
+			EmitAddr:             getEnv("HL7_EMIT_ADDR", ""),
+			SendingApplication:   getEnv("HL7_SENDING_APPLICATION", "PATIENT-SERVICE"),
+			SendingFacility:      getEnv("HL7_SENDING_FACILITY", "HOSPITAL"),
+			ReceivingApplication: getEnv("HL7_RECEIVING_APPLICATION", ""),
+			ReceivingFacility:    getEnv("HL7_RECEIVING_FACILITY", ""),
+		},
+		SatuSehat: SatuSehatConfig{
+			AuthURL:       getEnv("SATUSEHAT_AUTH_URL", "https://api-satusehat-stg.dto.kemkes.go.id/oauth2/v1"),
+			BaseURL:       getEnv("SATUSEHAT_BASE_URL", "https://api-satusehat-stg.dto.kemkes.go.id/fhir-r4/v1"),
+			ClientID:      getEnv("SATUSEHAT_CLIENT_ID", ""),
+			ClientSecret:  getEnv("SATUSEHAT_CLIENT_SECRET", ""),
+			RetryInterval: time.Duration(getEnvAsInt("SATUSEHAT_RETRY_SECONDS", 60)) * time.Second,
+			MaxAttempts:   getEnvAsInt("SATUSEHAT_MAX_ATTEMPTS", 10),
+		},
+		Events: EventsConfig{
+			Publisher:     getEnv("EVENTS_PUBLISHER", "none"),
+			Source:        getEnv("EVENTS_SOURCE", "/patient-service"),
+			WebhookURL:    getEnv("EVENTS_WEBHOOK_URL", ""),
+			WebhookToken:  getEnv("EVENTS_WEBHOOK_TOKEN", ""),
+			NATSURL:       getEnv("EVENTS_NATS_URL", "nats://localhost:4222"),
+			NATSSubject:   getEnv("EVENTS_NATS_SUBJECT", "patients"),
+			KafkaBrokers:  strings.Split(getEnv("EVENTS_KAFKA_BROKERS", "localhost:9092"), ","),
+			KafkaTopic:    getEnv("EVENTS_KAFKA_TOPIC", "patient-events"),
+			RelayInterval: time.Duration(getEnvAsInt("EVENTS_RELAY_INTERVAL_MS", 1000)) * time.Millisecond,
+			Retention:     time.Duration(getEnvAsInt("EVENTS_RETENTION_HOURS", 168)) * time.Hour,
+			FeedInterval:  time.Duration(getEnvAsInt("EVENTS_FEED_INTERVAL_MS", 1000)) * time.Millisecond,
+		},
+		Webhooks: WebhooksConfig{
+			PollInterval: time.Duration(getEnvAsInt("WEBHOOK_POLL_SECONDS", 5)) * time.Second,
+			Timeout:      time.Duration(getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second,
+			MaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 12),
+			DisableAfter: getEnvAsInt("WEBHOOK_DISABLE_AFTER", 20),
+			Retention:    time.Duration(getEnvAsInt("WEBHOOK_RETENTION_HOURS", 720)) * time.Hour,
+			ConsentType:  getEnv("WEBHOOK_DEFAULT_CONSENT_TYPE", "satusehat"),
+		},
+		Import: ImportConfig{
+			MaxUploadMB:  getEnvAsInt("IMPORT_MAX_UPLOAD_MB", 20),
+			BatchSize:    getEnvAsInt("IMPORT_BATCH_SIZE", 500),
+			PollInterval: time.Duration(getEnvAsInt("IMPORT_POLL_SECONDS", 5)) * time.Second,
+			Lease:        time.Duration(getEnvAsInt("IMPORT_LEASE_SECONDS", 300)) * time.Second,
+			Retention:    time.Duration(getEnvAsInt("IMPORT_RETENTION_HOURS", 168)) * time.Hour,
+		},
+		Export: ExportConfig{
+			Dir:          getEnv("EXPORT_DIR", "./exports"),
+			PollInterval: time.Duration(getEnvAsInt("EXPORT_POLL_SECONDS", 5)) * time.Second,
+			Lease:        time.Duration(getEnvAsInt("EXPORT_LEASE_SECONDS", 300)) * time.Second,
+			Retention:    time.Duration(getEnvAsInt("EXPORT_RETENTION_HOURS", 24)) * time.Hour,
+		},
+		Deident: DeidentConfig{
+			PseudonymKey:          getEnv("DEIDENT_PSEUDONYM_KEY", ""),
+			DateOfBirth:           getEnv("DEIDENT_DOB", "age_band"),
+			AgeBandYears:          getEnvAsInt("DEIDENT_AGE_BAND_YEARS", 5),
+			TopAge:                getEnvAsInt("DEIDENT_TOP_AGE", 90),
+			PostalDigits:          getEnvAsInt("DEIDENT_POSTAL_DIGITS", 3),
+			QuasiIdentifiers:      strings.Split(getEnv("DEIDENT_QUASI_IDENTIFIERS", "date_of_birth,gender,province,postal_region"), ","),
+			K:                     getEnvAsInt("DEIDENT_K", 5),
+			MaxSuppressionPercent: getEnvAsInt("DEIDENT_MAX_SUPPRESSION_PERCENT", 5),
+		},
+		Consent: ConsentConfig{
+			OptOut: strings.Split(getEnv("CONSENT_OPT_OUT_TYPES", "satusehat"), ","),
+		},
+		Documents: DocumentConfig{
+			Storage:     getEnv("DOCUMENT_STORAGE", "local"),
+			Dir:         getEnv("DOCUMENT_DIR", "./documents"),
+			S3Endpoint:  getEnv("DOCUMENT_S3_ENDPOINT", ""),
+			S3Region:    getEnv("DOCUMENT_S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("DOCUMENT_S3_BUCKET", "patient-documents"),
+			S3AccessKey: getEnv("DOCUMENT_S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("DOCUMENT_S3_SECRET_KEY", ""),
+			S3Prefix:    getEnv("DOCUMENT_S3_PREFIX", ""),
+			MaxUploadMB: getEnvAsInt("DOCUMENT_MAX_UPLOAD_MB", 10),
+			URLKey:      getEnv("DOCUMENT_URL_KEY", ""),
+			URLTTL:      time.Duration(getEnvAsInt("DOCUMENT_URL_TTL_SECONDS", 300)) * time.Second,
+			ClamAVAddr:  getEnv("CLAMAV_ADDR", ""),
+			ScanTimeout: time.Duration(getEnvAsInt("CLAMAV_TIMEOUT_SECONDS", 60)) * time.Second,
+		},
+		Labels: LabelConfig{
+			TemplateFile: getEnv("LABEL_TEMPLATE_FILE", ""),
+			HospitalName: getEnv("LABEL_HOSPITAL_NAME", ""),
+		},
+		QRTokens: QRTokenConfig{
+			Enabled:   getEnvAsBool("QR_TOKEN_ENABLED", true),
+			Key:       getEnv("QR_TOKEN_KEY", ""),
+			TTL:       time.Duration(getEnvAsInt("QR_TOKEN_TTL_HOURS", 720)) * time.Hour,
+			MaxTTL:    time.Duration(getEnvAsInt("QR_TOKEN_MAX_TTL_HOURS", 8760)) * time.Hour,
+			RateLimit: getEnvAsInt("QR_TOKEN_RATE_LIMIT", 30),
+		},
+		Practitioners: PractitionerConfig{
+			URL:      strings.TrimRight(getEnv("PRACTITIONER_SERVICE_URL", ""), "/"),
+			Timeout:  time.Duration(getEnvAsInt("PRACTITIONER_SERVICE_TIMEOUT_SECONDS", 3)) * time.Second,
+			CacheTTL: time.Duration(getEnvAsInt("PRACTITIONER_CACHE_SECONDS", 300)) * time.Second,
+		},
+		Tenants: TenantConfig{
+			File:    getEnv("TENANT_CONFIG_FILE", ""),
+			Default: getEnv("TENANT_DEFAULT", "default"),
+		},
 	}
 }
 
@@ -65,8 +341,18 @@ func getEnv(key, defaultValue string) string {
 
 func getEnvAsInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
-		// Simple conversion, in production use strconv.Atoi with error handling
-		return defaultValue // Simplified for demo
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
 	}
 	return defaultValue
 }
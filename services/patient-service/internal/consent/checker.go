@@ -0,0 +1,106 @@
+// Consent checks before patient data is released
+// internal/consent/checker.go
+package consent
+
+import (
+	"context"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+)
+
+// batchSize is how many patients Filter checks in one query.
+const batchSize = 500
+
+// ConsentChecker decides whether a patient's data may be released for a
+// consent type. Exports, webhook deliveries and integrations consult it
+// before data leaves the service.
+type ConsentChecker interface {
+	Allowed(ctx context.Context, patientID, consentType string) (bool, error)
+
+	// AllowedAll returns the decision for each of the patients
+	AllowedAll(ctx context.Context, patientIDs []string, consentType string) (map[string]bool, error)
+}
+
+type checker struct {
+	repo   repository.ConsentRepository
+	optOut map[string]bool
+}
+
+// NewChecker creates a checker. The consent in effect decides; without
+// one, release is allowed for the optOut types, where the patient has to
+// deny it, and refused for the others, where the patient has to permit it.
+func NewChecker(repo repository.ConsentRepository, optOut []string) ConsentChecker {
+	c := &checker{repo: repo, optOut: make(map[string]bool)}
+	for _, t := range optOut {
+		c.optOut[t] = true
+	}
+	return c
+}
+
+func (c *checker) Allowed(ctx context.Context, patientID, consentType string) (bool, error) {
+	allowed, err := c.AllowedAll(ctx, []string{patientID}, consentType)
+	if err != nil {
+		return false, err
+	}
+	return allowed[patientID], nil
+}
+
+func (c *checker) AllowedAll(ctx context.Context, patientIDs []string, consentType string) (map[string]bool, error) {
+	decisions, err := c.repo.Decisions(ctx, patientIDs, consentType, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[string]bool, len(patientIDs))
+	for _, id := range patientIDs {
+		decision, ok := decisions[id]
+		if !ok {
+			allowed[id] = c.optOut[consentType]
+			continue
+		}
+		allowed[id] = decision == domain.ConsentPermit
+	}
+	return allowed, nil
+}
+
+// Filter wraps a patient callback, such as a repository scan's, so that
+// only patients allowed the consent type reach fn. Patients are checked in
+// batches; flush must be called after the last one.
+func Filter(ctx context.Context, checker ConsentChecker, consentType string, fn func(*domain.Patient) error) (add func(*domain.Patient) error, flush func() error) {
+	var batch []*domain.Patient
+
+	flush = func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		ids := make([]string, len(batch))
+		for i, patient := range batch {
+			ids[i] = patient.ID
+		}
+		allowed, err := checker.AllowedAll(ctx, ids, consentType)
+		if err != nil {
+			return err
+		}
+		for _, patient := range batch {
+			if !allowed[patient.ID] {
+				continue
+			}
+			if err := fn(patient); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	add = func(patient *domain.Patient) error {
+		batch = append(batch, patient)
+		if len(batch) >= batchSize {
+			return flush()
+		}
+		return nil
+	}
+	return add, flush
+}
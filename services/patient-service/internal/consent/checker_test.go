@@ -0,0 +1,93 @@
+package consent
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+)
+
+// memoryRepo implements the decisions of repository.ConsentRepository.
+type memoryRepo struct {
+	repository.ConsentRepository
+	decisions map[string]string // patient ID to decision
+	queries   int
+}
+
+func (m *memoryRepo) Decisions(ctx context.Context, patientIDs []string, consentType string, at time.Time) (map[string]string, error) {
+	m.queries++
+	decisions := make(map[string]string)
+	for _, id := range patientIDs {
+		if decision, ok := m.decisions[id]; ok {
+			decisions[id] = decision
+		}
+	}
+	return decisions, nil
+}
+
+func TestCheckerDefaults(t *testing.T) {
+	repo := &memoryRepo{decisions: map[string]string{"permitted": domain.ConsentPermit, "denied": domain.ConsentDeny}}
+	checker := NewChecker(repo, []string{domain.ConsentSatuSehat})
+	ctx := context.Background()
+
+	cases := []struct {
+		patientID, consentType string
+		want                   bool
+	}{
+		{"permitted", domain.ConsentResearch, true},
+		{"denied", domain.ConsentResearch, false},
+		{"unknown", domain.ConsentResearch, false},
+		{"denied", domain.ConsentSatuSehat, false},
+		{"unknown", domain.ConsentSatuSehat, true},
+	}
+	for _, tc := range cases {
+		got, err := checker.Allowed(ctx, tc.patientID, tc.consentType)
+		if err != nil {
+			t.Fatalf("Allowed failed: %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("Allowed(%s, %s) = %v, want %v", tc.patientID, tc.consentType, got, tc.want)
+		}
+	}
+}
+
+func TestFilterBatches(t *testing.T) {
+	repo := &memoryRepo{decisions: make(map[string]string)}
+	var patients []*domain.Patient
+	for i := 0; i < batchSize+10; i++ {
+		patient := &domain.Patient{ID: fmt.Sprintf("p%d", i)}
+		if i%2 == 0 {
+			repo.decisions[patient.ID] = domain.ConsentPermit
+		}
+		patients = append(patients, patient)
+	}
+
+	var got []string
+	add, flush := Filter(context.Background(), NewChecker(repo, nil), domain.ConsentResearch, func(patient *domain.Patient) error {
+		got = append(got, patient.ID)
+		return nil
+	})
+	for _, patient := range patients {
+		if err := add(patient); err != nil {
+			t.Fatalf("add failed: %v", err)
+		}
+	}
+	if err := flush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	var want []string
+	for i := 0; i < len(patients); i += 2 {
+		want = append(want, patients[i].ID)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the permitted patients in order, got %d of %d", len(got), len(want))
+	}
+	if repo.queries != 2 {
+		t.Errorf("Expected 2 batched queries, got %d", repo.queries)
+	}
+}
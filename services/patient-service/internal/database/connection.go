@@ -46,19 +46,31 @@ func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
 }
 
 func createTables(db *sql.DB) error {
-	query := `
+	// Each statement runs in its own batch so later statements can reference
+	// columns added by earlier ones.
+	for _, query := range migrations {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var migrations = []string{
+	`
 	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='patients' AND xtype='U')
 	CREATE TABLE patients (
 		id NVARCHAR(50) PRIMARY KEY,
 		medical_record_no NVARCHAR(50) UNIQUE NOT NULL,
-		nik NVARCHAR(16) UNIQUE NOT NULL,
+		nik NVARCHAR(512) NOT NULL,
+		nik_bidx NVARCHAR(64),
 		first_name NVARCHAR(100) NOT NULL,
 		last_name NVARCHAR(100),
 		date_of_birth DATE NOT NULL,
 		gender NVARCHAR(10) NOT NULL,
 		blood_type NVARCHAR(5),
-		phone NVARCHAR(20) NOT NULL,
-		email NVARCHAR(100),
+		phone NVARCHAR(512) NOT NULL,
+		email NVARCHAR(512),
 		address NVARCHAR(255),
 		city NVARCHAR(100),
 		province NVARCHAR(100),
@@ -66,27 +78,474 @@ func createTables(db *sql.DB) error {
 		emergency_contact NVARCHAR(100),
 		emergency_phone NVARCHAR(20),
 		insurance_provider NVARCHAR(100),
-		insurance_number NVARCHAR(50),
+		insurance_number NVARCHAR(512),
 		allergies NVARCHAR(MAX),
 		chronic_conditions NVARCHAR(MAX),
+		data_key VARBINARY(256),
+		data_key_id NVARCHAR(100),
 		is_active BIT DEFAULT 1,
 		created_at DATETIME2 DEFAULT GETDATE(),
 		updated_at DATETIME2 DEFAULT GETDATE(),
 		created_by NVARCHAR(50),
 		updated_by NVARCHAR(50)
 	);
+	`,
+
+	// Encrypted identifiers: existing installations store NIK, phone, email
+	// and insurance number in narrow plaintext columns. Widen them for the
+	// ciphertext and add the blind index / wrapped data key columns.
+	// Existing rows are encrypted, and given a blind index, when the service
+	// starts (see encryptPlaintextPatients in cmd/main.go).
+	`
+	IF COL_LENGTH('patients', 'nik_bidx') IS NULL
+		ALTER TABLE patients ADD nik_bidx NVARCHAR(64) NULL;
+	IF COL_LENGTH('patients', 'data_key') IS NULL
+		ALTER TABLE patients ADD data_key VARBINARY(256) NULL;
+	IF COL_LENGTH('patients', 'data_key_id') IS NULL
+		ALTER TABLE patients ADD data_key_id NVARCHAR(100) NULL;
+	`,
+	`
+	DECLARE @constraint NVARCHAR(256);
+	SELECT @constraint = kc.name
+	FROM sys.key_constraints kc
+	JOIN sys.index_columns ic ON ic.object_id = kc.parent_object_id AND ic.index_id = kc.unique_index_id
+	JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+	WHERE kc.parent_object_id = OBJECT_ID('patients') AND kc.type = 'UQ' AND c.name = 'nik';
+	IF @constraint IS NOT NULL
+		EXEC('ALTER TABLE patients DROP CONSTRAINT ' + @constraint);
 
-	-- Create indexes
-	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_nik')
-		CREATE INDEX idx_patients_nik ON patients(nik);
-	
+	IF EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_nik')
+		DROP INDEX idx_patients_nik ON patients;
+
+	IF COL_LENGTH('patients', 'nik') < 1024
+		ALTER TABLE patients ALTER COLUMN nik NVARCHAR(512) NOT NULL;
+	IF COL_LENGTH('patients', 'phone') < 1024
+		ALTER TABLE patients ALTER COLUMN phone NVARCHAR(512) NOT NULL;
+	IF COL_LENGTH('patients', 'email') < 1024
+		ALTER TABLE patients ALTER COLUMN email NVARCHAR(512) NULL;
+	IF COL_LENGTH('patients', 'insurance_number') < 1024
+		ALTER TABLE patients ALTER COLUMN insurance_number NVARCHAR(512) NULL;
+	`,
+
+	// Create indexes
+	`
 	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_medical_record')
 		CREATE INDEX idx_patients_medical_record ON patients(medical_record_no);
-	
+
 	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_name')
 		CREATE INDEX idx_patients_name ON patients(first_name, last_name);
+	`,
+
+	// last_name is optional; keyset pagination orders by a non-NULL copy,
+	// since NULL matches no comparison with the cursor
+	`
+	IF COL_LENGTH('patients', 'last_name_key') IS NULL
+		ALTER TABLE patients ADD last_name_key AS ISNULL(last_name, N'') PERSISTED;
+	`,
+
+	// Keyset pagination indexes (sort column + id tie-breaker)
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_created_keyset')
+		CREATE INDEX idx_patients_created_keyset ON patients(is_active, created_at, id);
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_updated_keyset')
+		CREATE INDEX idx_patients_updated_keyset ON patients(is_active, updated_at, id);
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_first_name_keyset')
+		CREATE INDEX idx_patients_first_name_keyset ON patients(is_active, first_name, id);
+
+	IF EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_last_name_keyset')
+		DROP INDEX idx_patients_last_name_keyset ON patients;
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_last_name_key_keyset')
+		CREATE INDEX idx_patients_last_name_key_keyset ON patients(is_active, last_name_key, id);
+	`,
+
+	// Filter indexes for ListPatients
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_dob')
+		CREATE INDEX idx_patients_dob ON patients(date_of_birth) INCLUDE (gender, is_active);
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_location')
+		CREATE INDEX idx_patients_location ON patients(province, city) INCLUDE (is_active);
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_insurance')
+		CREATE INDEX idx_patients_insurance ON patients(insurance_provider) INCLUDE (is_active);
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_created_by')
+		CREATE INDEX idx_patients_created_by ON patients(created_by, created_at);
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_demographics')
+		CREATE INDEX idx_patients_demographics ON patients(gender, blood_type) INCLUDE (date_of_birth, is_active);
+	`,
+
+	// Patient merges (HL7 ADT^A40): the retired record points at the survivor
 	`
+	IF COL_LENGTH('patients', 'merged_into') IS NULL
+		ALTER TABLE patients ADD merged_into NVARCHAR(36) NULL;
+	`,
 
-	_, err := db.Exec(query)
-	return err
+	// Identifiers issued by external registries (SATUSEHAT IHS number) and
+	// the queue of pending lookups
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='patient_identifiers' AND xtype='U')
+	CREATE TABLE patient_identifiers (
+		patient_id NVARCHAR(50) NOT NULL,
+		system_uri NVARCHAR(255) NOT NULL,
+		value NVARCHAR(100) NOT NULL,
+		created_at DATETIME2 DEFAULT GETDATE(),
+		updated_at DATETIME2 DEFAULT GETDATE(),
+		CONSTRAINT pk_patient_identifiers PRIMARY KEY (patient_id, system_uri)
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patient_identifiers_value')
+		CREATE INDEX idx_patient_identifiers_value ON patient_identifiers(system_uri, value);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='satusehat_sync_queue' AND xtype='U')
+	CREATE TABLE satusehat_sync_queue (
+		patient_id NVARCHAR(50) PRIMARY KEY,
+		attempts INT NOT NULL DEFAULT 0,
+		last_error NVARCHAR(1000),
+		enqueued_at DATETIME2 NOT NULL,
+		next_attempt_at DATETIME2 NOT NULL
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_satusehat_sync_queue_due')
+		CREATE INDEX idx_satusehat_sync_queue_due ON satusehat_sync_queue(next_attempt_at);
+	`,
+
+	// Transactional outbox of patient events
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='outbox' AND xtype='U')
+	CREATE TABLE outbox (
+		id BIGINT IDENTITY(1,1) PRIMARY KEY,
+		event_id NVARCHAR(36) NOT NULL,
+		event_type NVARCHAR(100) NOT NULL,
+		aggregate_id NVARCHAR(50) NOT NULL,
+		data NVARCHAR(MAX) NOT NULL,
+		occurred_at DATETIME2 NOT NULL,
+		published_at DATETIME2 NULL,
+		attempts INT NOT NULL DEFAULT 0,
+		last_error NVARCHAR(1000) NULL,
+		next_attempt_at DATETIME2 NULL
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_outbox_pending')
+		CREATE INDEX idx_outbox_pending ON outbox(id) WHERE published_at IS NULL;
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_outbox_aggregate_pending')
+		CREATE INDEX idx_outbox_aggregate_pending ON outbox(aggregate_id, next_attempt_at) WHERE published_at IS NULL;
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_outbox_occurred')
+		CREATE INDEX idx_outbox_occurred ON outbox(occurred_at);
+	`,
+
+	// Per-patient change counter, bumped with every outbox event
+	`
+	IF COL_LENGTH('patients', 'version') IS NULL
+		ALTER TABLE patients ADD version BIGINT NOT NULL DEFAULT 0;
+	`,
+
+	// Webhook subscriptions, their deliveries and the attempt log
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='webhook_subscriptions' AND xtype='U')
+	CREATE TABLE webhook_subscriptions (
+		id NVARCHAR(36) PRIMARY KEY,
+		url NVARCHAR(2000) NOT NULL,
+		description NVARCHAR(500),
+		event_types NVARCHAR(500) NOT NULL DEFAULT '',
+		secret NVARCHAR(500) NOT NULL,
+		data_key VARBINARY(512) NOT NULL,
+		data_key_id NVARCHAR(100) NOT NULL,
+		is_active BIT NOT NULL DEFAULT 1,
+		consecutive_failures INT NOT NULL DEFAULT 0,
+		disabled_at DATETIME2 NULL,
+		disabled_reason NVARCHAR(500) NULL,
+		created_at DATETIME2 DEFAULT GETDATE(),
+		updated_at DATETIME2 DEFAULT GETDATE(),
+		created_by NVARCHAR(50),
+		updated_by NVARCHAR(50)
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='webhook_deliveries' AND xtype='U')
+	CREATE TABLE webhook_deliveries (
+		id NVARCHAR(36) PRIMARY KEY,
+		subscription_id NVARCHAR(36) NOT NULL,
+		event_id NVARCHAR(36) NOT NULL,
+		event_type NVARCHAR(100) NOT NULL,
+		payload NVARCHAR(MAX) NOT NULL,
+		status NVARCHAR(20) NOT NULL,
+		attempts INT NOT NULL DEFAULT 0,
+		next_attempt_at DATETIME2 NOT NULL,
+		last_status_code INT NULL,
+		last_error NVARCHAR(1000) NULL,
+		created_at DATETIME2 NOT NULL,
+		delivered_at DATETIME2 NULL,
+		updated_at DATETIME2 NOT NULL
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ux_webhook_deliveries_event')
+		CREATE UNIQUE INDEX ux_webhook_deliveries_event ON webhook_deliveries(subscription_id, event_id);
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_webhook_deliveries_due')
+		CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_webhook_deliveries_status')
+		CREATE INDEX idx_webhook_deliveries_status ON webhook_deliveries(status, created_at);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='webhook_delivery_attempts' AND xtype='U')
+	CREATE TABLE webhook_delivery_attempts (
+		id BIGINT IDENTITY(1,1) PRIMARY KEY,
+		delivery_id NVARCHAR(36) NOT NULL,
+		attempted_at DATETIME2 NOT NULL,
+		status_code INT NULL,
+		error NVARCHAR(1000) NULL,
+		duration_ms INT NOT NULL DEFAULT 0
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_webhook_delivery_attempts_delivery')
+		CREATE INDEX idx_webhook_delivery_attempts_delivery ON webhook_delivery_attempts(delivery_id);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='import_jobs' AND xtype='U')
+	CREATE TABLE import_jobs (
+		id NVARCHAR(36) PRIMARY KEY,
+		file_name NVARCHAR(255) NOT NULL,
+		format NVARCHAR(10) NOT NULL,
+		status NVARCHAR(20) NOT NULL,
+		dry_run BIT NOT NULL DEFAULT 0,
+		mapping NVARCHAR(MAX) NOT NULL,
+		columns NVARCHAR(MAX) NULL,
+		content VARCHAR(MAX) NULL,
+		data_key VARBINARY(512) NOT NULL,
+		data_key_id NVARCHAR(100) NOT NULL,
+		total_rows INT NOT NULL DEFAULT 0,
+		processed_rows INT NOT NULL DEFAULT 0,
+		valid_rows INT NOT NULL DEFAULT 0,
+		imported_rows INT NOT NULL DEFAULT 0,
+		rejected_rows INT NOT NULL DEFAULT 0,
+		error NVARCHAR(1000) NULL,
+		lease_until DATETIME2 NULL,
+		created_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE(),
+		started_at DATETIME2 NULL,
+		finished_at DATETIME2 NULL
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_import_jobs_status')
+		CREATE INDEX idx_import_jobs_status ON import_jobs(status, created_at);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='import_row_errors' AND xtype='U')
+	CREATE TABLE import_row_errors (
+		id BIGINT IDENTITY(1,1) PRIMARY KEY,
+		job_id NVARCHAR(36) NOT NULL,
+		row_no INT NOT NULL,
+		field NVARCHAR(50) NULL,
+		code NVARCHAR(50) NOT NULL,
+		message NVARCHAR(500) NOT NULL,
+		row_values NVARCHAR(MAX) NULL
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_import_row_errors_job')
+		CREATE INDEX idx_import_row_errors_job ON import_row_errors(job_id, row_no);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='export_jobs' AND xtype='U')
+	CREATE TABLE export_jobs (
+		id NVARCHAR(36) PRIMARY KEY,
+		format NVARCHAR(10) NOT NULL,
+		status NVARCHAR(20) NOT NULL,
+		filter NVARCHAR(MAX) NOT NULL,
+		row_count INT NOT NULL DEFAULT 0,
+		file_size BIGINT NOT NULL DEFAULT 0,
+		error NVARCHAR(1000) NULL,
+		lease_until DATETIME2 NULL,
+		created_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE(),
+		started_at DATETIME2 NULL,
+		finished_at DATETIME2 NULL
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_export_jobs_status')
+		CREATE INDEX idx_export_jobs_status ON export_jobs(status, created_at);
+	`,
+	`
+	IF COL_LENGTH('export_jobs', 'consent_type') IS NULL
+		ALTER TABLE export_jobs ADD consent_type NVARCHAR(30) NULL;
+	IF COL_LENGTH('export_jobs', 'ruleset') IS NULL
+		ALTER TABLE export_jobs ADD ruleset NVARCHAR(MAX) NULL;
+	IF COL_LENGTH('export_jobs', 'suppressed_rows') IS NULL
+		ALTER TABLE export_jobs ADD suppressed_rows INT NOT NULL DEFAULT 0;
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='patient_consents' AND xtype='U')
+	CREATE TABLE patient_consents (
+		id NVARCHAR(36) PRIMARY KEY,
+		patient_id NVARCHAR(50) NOT NULL,
+		type NVARCHAR(30) NOT NULL,
+		decision NVARCHAR(10) NOT NULL,
+		scope NVARCHAR(500),
+		grantor_name NVARCHAR(200),
+		grantor_relationship NVARCHAR(20) NOT NULL,
+		valid_from DATETIME2 NOT NULL,
+		valid_until DATETIME2 NULL,
+		document_ref NVARCHAR(500),
+		revoked_at DATETIME2 NULL,
+		revoked_by NVARCHAR(50),
+		revocation_reason NVARCHAR(500),
+		created_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE(),
+		updated_at DATETIME2 DEFAULT GETDATE()
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patient_consents_patient')
+		CREATE INDEX idx_patient_consents_patient ON patient_consents(patient_id, type, valid_from);
+	`,
+	`
+	IF COL_LENGTH('webhook_subscriptions', 'consent_type') IS NULL
+		ALTER TABLE webhook_subscriptions ADD consent_type NVARCHAR(30) NULL;
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='patient_documents' AND xtype='U')
+	CREATE TABLE patient_documents (
+		id NVARCHAR(36) PRIMARY KEY,
+		patient_id NVARCHAR(50) NOT NULL,
+		type NVARCHAR(30) NOT NULL,
+		description NVARCHAR(500),
+		issued_date DATE NULL,
+		file_name NVARCHAR(255) NOT NULL,
+		content_type NVARCHAR(100) NOT NULL,
+		size BIGINT NOT NULL,
+		sha256 NVARCHAR(64) NOT NULL,
+		storage_key NVARCHAR(200) NOT NULL,
+		scan_status NVARCHAR(20) NOT NULL,
+		uploaded_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE()
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patient_documents_patient')
+		CREATE INDEX idx_patient_documents_patient ON patient_documents(patient_id, created_at);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='patient_qr_tokens' AND xtype='U')
+	CREATE TABLE patient_qr_tokens (
+		id NVARCHAR(32) PRIMARY KEY,
+		patient_id NVARCHAR(50) NOT NULL,
+		expires_at DATETIME2 NOT NULL,
+		revoked_at DATETIME2 NULL,
+		revoked_by NVARCHAR(50),
+		last_scanned_at DATETIME2 NULL,
+		scan_count INT NOT NULL DEFAULT 0,
+		created_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE()
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patient_qr_tokens_patient')
+		CREATE INDEX idx_patient_qr_tokens_patient ON patient_qr_tokens(patient_id, created_at);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='patient_qr_scans' AND xtype='U')
+	CREATE TABLE patient_qr_scans (
+		id BIGINT IDENTITY(1,1) PRIMARY KEY,
+		token_id NVARCHAR(32) NULL,
+		patient_id NVARCHAR(50) NULL,
+		result NVARCHAR(20) NOT NULL,
+		ip NVARCHAR(45),
+		user_agent NVARCHAR(255),
+		scanned_at DATETIME2 DEFAULT GETDATE()
+	);
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patient_qr_scans_token')
+		CREATE INDEX idx_patient_qr_scans_token ON patient_qr_scans(token_id, scanned_at);
+	`,
+
+	// Deceased status: deceased patients stay active. The text columns are
+	// NOT NULL so existing rows scan as empty strings.
+	`
+	IF COL_LENGTH('patients', 'deceased_at') IS NULL
+		ALTER TABLE patients ADD deceased_at DATETIME2 NULL;
+	IF COL_LENGTH('patients', 'cause_of_death') IS NULL
+		ALTER TABLE patients ADD cause_of_death NVARCHAR(255) NOT NULL CONSTRAINT df_patients_cause_of_death DEFAULT '';
+	IF COL_LENGTH('patients', 'death_recorded_by') IS NULL
+		ALTER TABLE patients ADD death_recorded_by NVARCHAR(50) NOT NULL CONSTRAINT df_patients_death_recorded_by DEFAULT '';
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_deceased')
+		CREATE INDEX idx_patients_deceased ON patients(deceased_at) INCLUDE (is_active) WHERE deceased_at IS NOT NULL;
+	`,
+
+	// Tenants: existing patients belong to the default tenant and are their
+	// own enterprise identity. Medical record numbers and NIKs become unique
+	// per tenant; one person's records at several tenants share the
+	// enterprise ID.
+	`
+	IF COL_LENGTH('patients', 'tenant_id') IS NULL
+		ALTER TABLE patients ADD tenant_id NVARCHAR(50) NOT NULL CONSTRAINT df_patients_tenant_id DEFAULT 'default';
+	IF COL_LENGTH('patients', 'enterprise_id') IS NULL
+		ALTER TABLE patients ADD enterprise_id NVARCHAR(50) NULL;
+	`,
+	`
+	IF EXISTS (SELECT * FROM sys.columns WHERE object_id = OBJECT_ID('patients') AND name = 'enterprise_id' AND is_nullable = 1)
+	BEGIN
+		UPDATE patients SET enterprise_id = id WHERE enterprise_id IS NULL;
+		ALTER TABLE patients ALTER COLUMN enterprise_id NVARCHAR(50) NOT NULL;
+	END
+	`,
+	`
+	DECLARE @constraint NVARCHAR(256);
+	SELECT @constraint = kc.name
+	FROM sys.key_constraints kc
+	JOIN sys.index_columns ic ON ic.object_id = kc.parent_object_id AND ic.index_id = kc.unique_index_id
+	JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+	WHERE kc.parent_object_id = OBJECT_ID('patients') AND kc.type = 'UQ' AND c.name = 'medical_record_no';
+	IF @constraint IS NOT NULL
+		EXEC('ALTER TABLE patients DROP CONSTRAINT ' + @constraint);
+
+	IF EXISTS (SELECT * FROM sys.indexes WHERE name = 'ux_patients_nik_bidx')
+		DROP INDEX ux_patients_nik_bidx ON patients;
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ux_patients_tenant_mrn')
+		CREATE UNIQUE INDEX ux_patients_tenant_mrn ON patients(tenant_id, medical_record_no);
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'ux_patients_tenant_nik_bidx')
+		CREATE UNIQUE INDEX ux_patients_tenant_nik_bidx ON patients(tenant_id, nik_bidx) WHERE nik_bidx IS NOT NULL;
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_nik_bidx')
+		CREATE INDEX idx_patients_nik_bidx ON patients(nik_bidx) INCLUDE (tenant_id, enterprise_id, is_active, created_at);
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_enterprise')
+		CREATE INDEX idx_patients_enterprise ON patients(enterprise_id) INCLUDE (tenant_id, is_active);
+
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = 'idx_patients_tenant_created_keyset')
+		CREATE INDEX idx_patients_tenant_created_keyset ON patients(tenant_id, is_active, created_at, id);
+	`,
+	`
+	IF COL_LENGTH('outbox', 'tenant_id') IS NULL
+		ALTER TABLE outbox ADD tenant_id NVARCHAR(50) NOT NULL CONSTRAINT df_outbox_tenant_id DEFAULT 'default';
+	IF COL_LENGTH('satusehat_sync_queue', 'tenant_id') IS NULL
+		ALTER TABLE satusehat_sync_queue ADD tenant_id NVARCHAR(50) NOT NULL CONSTRAINT df_satusehat_sync_queue_tenant_id DEFAULT 'default';
+	IF COL_LENGTH('webhook_subscriptions', 'tenant_id') IS NULL
+		ALTER TABLE webhook_subscriptions ADD tenant_id NVARCHAR(50) NOT NULL CONSTRAINT df_webhook_subscriptions_tenant_id DEFAULT 'default';
+	IF COL_LENGTH('import_jobs', 'tenant_id') IS NULL
+		ALTER TABLE import_jobs ADD tenant_id NVARCHAR(50) NOT NULL CONSTRAINT df_import_jobs_tenant_id DEFAULT 'default';
+	IF COL_LENGTH('export_jobs', 'tenant_id') IS NULL
+		ALTER TABLE export_jobs ADD tenant_id NVARCHAR(50) NOT NULL CONSTRAINT df_export_jobs_tenant_id DEFAULT 'default';
+	`,
 }
@@ -0,0 +1,231 @@
+// De-identification of patients for research datasets
+// internal/deident/deident.go
+package deident
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+// Version identifies the de-identification rules below; it is bumped when
+// the same ruleset would produce different output.
+const Version = 1
+
+// Date of birth generalizations
+const (
+	DOBYear    = "year"
+	DOBAgeBand = "age_band"
+)
+
+// QuasiIdentifiers are the columns k-anonymity may be checked over;
+// date_of_birth stands for its generalized column.
+var QuasiIdentifiers = []string{"date_of_birth", "gender", "province", "postal_region", "blood_type"}
+
+// SourceFields are the patient fields read to build a record. Names,
+// NIK, contact details, addresses and free text never leave the database.
+var SourceFields = []string{"id", "date_of_birth", "gender", "province", "postal_code", "blood_type"}
+
+// Config holds the pseudonym key and the ruleset defaults.
+type Config struct {
+	Key      []byte // HMAC key of the pseudonyms; nil disables de-identification
+	Defaults domain.DeidentRuleset
+}
+
+// Ruleset completes a requested ruleset with the defaults, stamps it with
+// the rules version, key fingerprint and reference date, and validates it.
+func (c Config) Ruleset(req domain.DeidentRuleset, now time.Time) (*domain.DeidentRuleset, error) {
+	rules := c.Defaults
+	if req.DateOfBirth != "" {
+		rules.DateOfBirth = req.DateOfBirth
+	}
+	if req.AgeBandYears != 0 {
+		rules.AgeBandYears = req.AgeBandYears
+	}
+	if req.PostalDigits != 0 {
+		rules.PostalDigits = req.PostalDigits
+	}
+	if req.QuasiIdentifiers != nil {
+		rules.QuasiIdentifiers = req.QuasiIdentifiers
+	}
+	if req.K != 0 {
+		rules.K = req.K
+	}
+	if req.MaxSuppressionPercent != 0 {
+		rules.MaxSuppressionPercent = req.MaxSuppressionPercent
+	}
+
+	rules.Version = Version
+	rules.KeyID = KeyID(c.Key)
+	rules.ReferenceDate = now.UTC().Format("2006-01-02")
+
+	if _, err := New(rules, c.Key); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// KeyID fingerprints a pseudonym key, so that datasets pseudonymized with
+// the same key can be told apart from others without revealing it.
+func KeyID(key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("deident-key-id"))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// Columns returns the columns of a de-identified record.
+func Columns(rules domain.DeidentRuleset) []string {
+	dob := "age_band"
+	if rules.DateOfBirth == DOBYear {
+		dob = "birth_year"
+	}
+	return []string{"pseudo_id", dob, "gender", "province", "postal_region", "blood_type"}
+}
+
+// Deidentifier turns patients into de-identified records under a ruleset.
+type Deidentifier struct {
+	rules     domain.DeidentRuleset
+	key       []byte
+	reference time.Time
+	qi        []int // record columns of the quasi-identifiers
+}
+
+// New validates the ruleset and creates a Deidentifier; the key must be
+// the one the ruleset was stamped with.
+func New(rules domain.DeidentRuleset, key []byte) (*Deidentifier, error) {
+	if len(key) < 32 {
+		return nil, domain.ErrDeidentOff
+	}
+	if rules.KeyID != KeyID(key) {
+		return nil, domain.NewCustomError("INVALID_RULESET", "Ruleset was made with another pseudonym key", rules.KeyID)
+	}
+
+	invalid := func(details string) error {
+		return domain.NewCustomError("INVALID_RULESET", "Invalid de-identification ruleset", details)
+	}
+	if rules.Version != Version {
+		return nil, invalid(fmt.Sprintf("version %d is not supported", rules.Version))
+	}
+	reference, err := time.Parse("2006-01-02", rules.ReferenceDate)
+	if err != nil {
+		return nil, invalid("reference_date must be YYYY-MM-DD")
+	}
+	switch {
+	case rules.DateOfBirth != DOBYear && rules.DateOfBirth != DOBAgeBand:
+		return nil, invalid("date_of_birth must be year or age_band")
+	case rules.AgeBandYears < 1 || rules.AgeBandYears > 20:
+		return nil, invalid("age_band_years must be between 1 and 20")
+	case rules.TopAge < 1 || rules.TopAge > 150:
+		return nil, invalid("top_age must be between 1 and 150")
+	case rules.PostalDigits < 1 || rules.PostalDigits > 4:
+		return nil, invalid("postal_digits must be between 1 and 4")
+	case rules.K < 2:
+		return nil, invalid("k must be at least 2")
+	case rules.MaxSuppressionPercent < 0 || rules.MaxSuppressionPercent > 100:
+		return nil, invalid("max_suppression_percent must be between 0 and 100")
+	case len(rules.QuasiIdentifiers) == 0:
+		return nil, invalid("quasi_identifiers must not be empty")
+	}
+
+	d := &Deidentifier{rules: rules, key: key, reference: reference}
+	seen := make(map[string]bool)
+	for _, name := range rules.QuasiIdentifiers {
+		column := -1
+		for i, qi := range QuasiIdentifiers {
+			if qi == name {
+				column = i + 1 // after pseudo_id, in the order of Columns
+			}
+		}
+		if column < 0 || seen[name] {
+			return nil, invalid("unknown or repeated quasi-identifier " + name)
+		}
+		seen[name] = true
+		d.qi = append(d.qi, column)
+	}
+	return d, nil
+}
+
+// Record de-identifies a patient, with values in the order of Columns.
+func (d *Deidentifier) Record(patient *domain.Patient) []string {
+	return []string{
+		d.pseudonym(patient.ID),
+		d.dateOfBirth(patient.DateOfBirth),
+		patient.Gender,
+		patient.Province,
+		d.postalRegion(patient.PostalCode),
+		patient.BloodType,
+	}
+}
+
+// pseudonym is the same for a patient in every dataset made with the key,
+// so that datasets can be linked without identifying anyone.
+func (d *Deidentifier) pseudonym(id string) string {
+	mac := hmac.New(sha256.New, d.key)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+func (d *Deidentifier) dateOfBirth(dob time.Time) string {
+	if dob.IsZero() {
+		return ""
+	}
+
+	if d.rules.DateOfBirth == DOBYear {
+		if top := d.reference.Year() - d.rules.TopAge; dob.Year() <= top {
+			return "<=" + strconv.Itoa(top)
+		}
+		return strconv.Itoa(dob.Year())
+	}
+
+	age := d.reference.Year() - dob.Year()
+	if d.reference.Month() < dob.Month() || (d.reference.Month() == dob.Month() && d.reference.Day() < dob.Day()) {
+		age--
+	}
+	if age < 0 {
+		age = 0
+	}
+
+	if age >= d.rules.TopAge {
+		return strconv.Itoa(d.rules.TopAge) + "+"
+	}
+	low := age / d.rules.AgeBandYears * d.rules.AgeBandYears
+	high := low + d.rules.AgeBandYears - 1
+	if high >= d.rules.TopAge {
+		high = d.rules.TopAge - 1
+	}
+	if low == high {
+		return strconv.Itoa(low)
+	}
+	return fmt.Sprintf("%d-%d", low, high)
+}
+
+// postalRegion keeps the leading digits of a five digit postal code, the
+// region it lies in.
+func (d *Deidentifier) postalRegion(code string) string {
+	code = strings.TrimSpace(code)
+	if len(code) != 5 {
+		return ""
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return code[:d.rules.PostalDigits] + strings.Repeat("*", 5-d.rules.PostalDigits)
+}
+
+// class is the equivalence class of a record: its quasi-identifiers.
+func (d *Deidentifier) class(record []string) string {
+	var b strings.Builder
+	for _, column := range d.qi {
+		b.WriteString(record[column])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
@@ -0,0 +1,184 @@
+package deident
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+var testConfig = Config{
+	Key: testKey,
+	Defaults: domain.DeidentRuleset{
+		DateOfBirth:           DOBAgeBand,
+		AgeBandYears:          5,
+		TopAge:                90,
+		PostalDigits:          3,
+		QuasiIdentifiers:      []string{"date_of_birth", "gender", "postal_region"},
+		K:                     2,
+		MaxSuppressionPercent: 50,
+	},
+}
+
+var reference = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+func newDeidentifier(t *testing.T, req domain.DeidentRuleset) *Deidentifier {
+	t.Helper()
+	rules, err := testConfig.Ruleset(req, reference)
+	if err != nil {
+		t.Fatalf("Ruleset failed: %v", err)
+	}
+	d, err := New(*rules, testKey)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return d
+}
+
+func patient(id string, dob time.Time, gender, postalCode string) *domain.Patient {
+	return &domain.Patient{
+		ID: id, NIK: "3171234567890001", FirstName: "Budi", Phone: "081234567890", Address: "Jl. Merdeka 1",
+		DateOfBirth: dob, Gender: gender, Province: "DKI Jakarta", PostalCode: postalCode, BloodType: "O+",
+	}
+}
+
+func TestRulesetStampsAndValidates(t *testing.T) {
+	rules, err := testConfig.Ruleset(domain.DeidentRuleset{K: 10}, reference)
+	if err != nil {
+		t.Fatalf("Ruleset failed: %v", err)
+	}
+	if rules.K != 10 || rules.AgeBandYears != 5 || rules.Version != Version {
+		t.Errorf("Expected the override over the defaults, got %+v", rules)
+	}
+	if rules.ReferenceDate != "2026-10-19" || rules.KeyID != KeyID(testKey) {
+		t.Errorf("Expected reference date and key ID stamped, got %+v", rules)
+	}
+
+	_, err = testConfig.Ruleset(domain.DeidentRuleset{QuasiIdentifiers: []string{"nik"}}, reference)
+	var customErr *domain.CustomError
+	if !errors.As(err, &customErr) || customErr.Code != "INVALID_RULESET" {
+		t.Errorf("Expected INVALID_RULESET, got %v", err)
+	}
+
+	if _, err := (Config{Defaults: testConfig.Defaults}).Ruleset(domain.DeidentRuleset{}, reference); err != domain.ErrDeidentOff {
+		t.Errorf("Expected ErrDeidentOff without a key, got %v", err)
+	}
+
+	if _, err := New(*rules, []byte("another key of at least 32 bytes!")); err == nil {
+		t.Error("Expected a ruleset stamped with another key to be rejected")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	d := newDeidentifier(t, domain.DeidentRuleset{})
+
+	record := d.Record(patient("p1", time.Date(1984, 10, 20, 0, 0, 0, 0, time.UTC), "MALE", "10110"))
+	if len(record[0]) != 32 || record[0] == "p1" {
+		t.Errorf("Unexpected pseudonym %q", record[0])
+	}
+	// 41 the day before the birthday
+	want := []string{record[0], "40-44", "MALE", "DKI Jakarta", "101**", "O+"}
+	if !reflect.DeepEqual(record, want) {
+		t.Errorf("Expected %q, got %q", want, record)
+	}
+	for _, value := range record {
+		if value == "Budi" || value == "3171234567890001" || value == "081234567890" {
+			t.Errorf("Identifier %q in record", value)
+		}
+	}
+
+	// The pseudonym only depends on the patient and the key
+	again := newDeidentifier(t, domain.DeidentRuleset{DateOfBirth: DOBYear})
+	if again.Record(patient("p1", time.Time{}, "", ""))[0] != record[0] {
+		t.Error("Expected the same pseudonym under another ruleset")
+	}
+}
+
+func TestDateOfBirth(t *testing.T) {
+	band := newDeidentifier(t, domain.DeidentRuleset{})
+	year := newDeidentifier(t, domain.DeidentRuleset{DateOfBirth: DOBYear})
+
+	cases := []struct {
+		dob        time.Time
+		band, year string
+	}{
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), "0-4", "2026"},
+		{time.Date(1986, 10, 19, 0, 0, 0, 0, time.UTC), "40-44", "1986"},
+		{time.Date(1937, 1, 1, 0, 0, 0, 0, time.UTC), "85-89", "1937"},
+		{time.Date(1936, 1, 1, 0, 0, 0, 0, time.UTC), "90+", "<=1936"},
+		{time.Time{}, "", ""},
+	}
+	for _, tc := range cases {
+		if got := band.dateOfBirth(tc.dob); got != tc.band {
+			t.Errorf("age band of %v = %q, want %q", tc.dob, got, tc.band)
+		}
+		if got := year.dateOfBirth(tc.dob); got != tc.year {
+			t.Errorf("birth year of %v = %q, want %q", tc.dob, got, tc.year)
+		}
+	}
+}
+
+func scanOf(patients []*domain.Patient) func(fn func(*domain.Patient) error) error {
+	return func(fn func(*domain.Patient) error) error {
+		for _, p := range patients {
+			if err := fn(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestApplySuppressesSmallGroups(t *testing.T) {
+	d := newDeidentifier(t, domain.DeidentRuleset{})
+	dob := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	patients := []*domain.Patient{
+		patient("p1", dob, "MALE", "10110"),
+		patient("p2", dob.AddDate(1, 0, 0), "MALE", "10120"), // same band and region
+		patient("p3", dob, "FEMALE", "10110"),                // alone in its group
+		patient("p4", dob, "MALE", "10199"),
+	}
+
+	var emitted []string
+	result, err := d.Apply(scanOf(patients), func(record []string) error {
+		emitted = append(emitted, record[0])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result.Rows != 3 || result.Suppressed != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	want := []string{d.pseudonym("p1"), d.pseudonym("p2"), d.pseudonym("p4")}
+	if !reflect.DeepEqual(emitted, want) {
+		t.Errorf("Expected p1, p2 and p4 in scan order, got %v", emitted)
+	}
+}
+
+func TestApplyFailsWhenTooManySuppressed(t *testing.T) {
+	d := newDeidentifier(t, domain.DeidentRuleset{K: 3})
+	dob := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	patients := []*domain.Patient{
+		patient("p1", dob, "MALE", "10110"),
+		patient("p2", dob, "MALE", "10110"),
+		patient("p3", dob, "FEMALE", "10110"),
+	}
+
+	emitted := 0
+	_, err := d.Apply(scanOf(patients), func(record []string) error {
+		emitted++
+		return nil
+	})
+	var customErr *domain.CustomError
+	if !errors.As(err, &customErr) || customErr.Code != "K_ANONYMITY_NOT_MET" {
+		t.Fatalf("Expected K_ANONYMITY_NOT_MET, got %v", err)
+	}
+	if emitted != 0 {
+		t.Errorf("Expected nothing released, got %d rows", emitted)
+	}
+}
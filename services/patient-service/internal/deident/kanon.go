@@ -0,0 +1,80 @@
+// k-anonymity with suppression
+// internal/deident/kanon.go
+package deident
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+
+	"patient-service/internal/domain"
+)
+
+// Result summarizes a de-identified dataset.
+type Result struct {
+	Rows       int // records released
+	Suppressed int // records withheld in groups smaller than k
+}
+
+// Apply de-identifies the patients scan yields and passes the records of
+// groups of at least k to emit. Group sizes are only known after the last
+// patient, so the records are spooled to a temporary file in between
+// rather than read twice from a database that may change meanwhile.
+func (d *Deidentifier) Apply(scan func(fn func(*domain.Patient) error) error, emit func(record []string) error) (Result, error) {
+	spool, err := os.CreateTemp("", "deident-*.spool")
+	if err != nil {
+		return Result{}, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	buf := bufio.NewWriter(spool)
+	enc := gob.NewEncoder(buf)
+	classes := make(map[string]int)
+	total := 0
+
+	err = scan(func(patient *domain.Patient) error {
+		record := d.Record(patient)
+		classes[d.class(record)]++
+		total++
+		return enc.Encode(record)
+	})
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, size := range classes {
+		if size < d.rules.K {
+			result.Suppressed += size
+		}
+	}
+	if total > 0 && float64(result.Suppressed)*100/float64(total) > d.rules.MaxSuppressionPercent {
+		return result, domain.NewCustomError("K_ANONYMITY_NOT_MET", "Too many rows would be suppressed; generalize further or lower k",
+			fmt.Sprintf("%d of %d rows are in groups smaller than k=%d", result.Suppressed, total, d.rules.K))
+	}
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return Result{}, err
+	}
+	dec := gob.NewDecoder(bufio.NewReader(spool))
+	for i := 0; i < total; i++ {
+		var record []string
+		if err := dec.Decode(&record); err != nil {
+			return Result{}, err
+		}
+		if classes[d.class(record)] < d.rules.K {
+			continue
+		}
+		if err := emit(record); err != nil {
+			return Result{}, err
+		}
+		result.Rows++
+	}
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package document
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+func TestSniffAndAccepts(t *testing.T) {
+	pdf := Sniff([]byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"))
+	png := Sniff([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+	html := Sniff([]byte("<html><script>alert(1)</script></html>"))
+
+	cases := []struct {
+		documentType, contentType string
+		want                      bool
+	}{
+		{domain.DocumentKTP, pdf, true},
+		{domain.DocumentKTP, png, true},
+		{domain.DocumentPhoto, png, true},
+		{domain.DocumentPhoto, pdf, false},
+		{domain.DocumentOther, html, false},
+	}
+	for _, tc := range cases {
+		if got := Accepts(tc.documentType, tc.contentType); got != tc.want {
+			t.Errorf("Accepts(%s, %s) = %v, want %v", tc.documentType, tc.contentType, got, tc.want)
+		}
+	}
+}
+
+// fakeClamd answers one INSTREAM scan, reporting a signature when the
+// stream contains "EICAR".
+func fakeClamd(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			command, _ := r.ReadString(0)
+			if command != "zINSTREAM\x00" {
+				conn.Close()
+				continue
+			}
+			var stream strings.Builder
+			for {
+				var size uint32
+				if binary.Read(r, binary.BigEndian, &size) != nil || size == 0 {
+					break
+				}
+				io.CopyN(&stream, r, int64(size))
+			}
+			if strings.Contains(stream.String(), "EICAR") {
+				io.WriteString(conn, "stream: Eicar-Test-Signature FOUND\x00")
+			} else {
+				io.WriteString(conn, "stream: OK\x00")
+			}
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestClamAVScan(t *testing.T) {
+	scanner := NewClamAV(fakeClamd(t), 5*time.Second)
+	ctx := context.Background()
+
+	// Larger than a chunk, so the stream is split
+	clean := strings.Repeat("a", chunkSize+100)
+	if err := scanner.Scan(ctx, strings.NewReader(clean)); err != nil {
+		t.Errorf("Expected a clean scan, got %v", err)
+	}
+
+	var infected *Infected
+	err := scanner.Scan(ctx, strings.NewReader(clean+"EICAR"))
+	if !errors.As(err, &infected) || infected.Signature != "Eicar-Test-Signature" {
+		t.Errorf("Expected Eicar-Test-Signature, got %v", err)
+	}
+}
+
+func TestURLSigner(t *testing.T) {
+	signer := NewURLSigner([]byte("0123456789abcdef0123456789abcdef"), 5*time.Minute)
+	now := time.Unix(1700000000, 0)
+	expires, signature := signer.Sign("p1", "d1", now)
+
+	if !signer.Verify("p1", "d1", expires, signature, now.Add(time.Minute)) {
+		t.Error("Expected a valid link")
+	}
+	if signer.Verify("p1", "d1", expires, signature, now.Add(6*time.Minute)) {
+		t.Error("Expected an expired link to be rejected")
+	}
+	if signer.Verify("p1", "d2", expires, signature, now) {
+		t.Error("Expected a link for another document to be rejected")
+	}
+	if signer.Verify("p1", "d1", expires+3600, signature, now) {
+		t.Error("Expected an extended expiry to be rejected")
+	}
+}
@@ -0,0 +1,102 @@
+// Virus scanning of uploads
+// internal/document/scan.go
+package document
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Scanner checks uploads for malware before they are stored.
+type Scanner interface {
+	// Scan reads r to the end. It returns an *Infected error when malware
+	// was found and other errors when the content could not be scanned.
+	Scan(ctx context.Context, r io.Reader) error
+}
+
+// Infected is the error of a scan that found malware.
+type Infected struct {
+	Signature string
+}
+
+func (e *Infected) Error() string {
+	return "malware found: " + e.Signature
+}
+
+// chunkSize is the size of the INSTREAM chunks sent to clamd, well below
+// its default StreamMaxLength.
+const chunkSize = 64 << 10
+
+// ClamAV scans with a clamd daemon over TCP using the INSTREAM command.
+type ClamAV struct {
+	addr    string
+	timeout time.Duration
+}
+
+// NewClamAV creates a scanner for the clamd at addr (host:port); a scan
+// fails when it takes longer than timeout.
+func NewClamAV(addr string, timeout time.Duration) *ClamAV {
+	return &ClamAV{addr: addr, timeout: timeout}
+}
+
+func (c *ClamAV) Scan(ctx context.Context, r io.Reader) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("clamd: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(c.timeout))
+
+	w := bufio.NewWriter(conn)
+	if _, err := w.WriteString("zINSTREAM\x00"); err != nil {
+		return fmt.Errorf("clamd: %w", err)
+	}
+
+	// Chunks are prefixed with their length; an empty chunk ends the stream
+	buf := make([]byte, chunkSize)
+	var size [4]byte
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			w.Write(size[:])
+			if _, err := w.Write(buf[:n]); err != nil {
+				return fmt.Errorf("clamd: %w", err)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	binary.BigEndian.PutUint32(size[:], 0)
+	w.Write(size[:])
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("clamd: %w", err)
+	}
+	reply = strings.TrimRight(reply, "\x00\n")
+
+	// "stream: OK", "stream: <signature> FOUND" or "<message> ERROR"
+	switch {
+	case strings.HasSuffix(reply, " OK"):
+		return nil
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(strings.TrimPrefix(reply, "stream: "), " FOUND")
+		return &Infected{Signature: signature}
+	}
+	return fmt.Errorf("clamd: %s", reply)
+}
@@ -0,0 +1,44 @@
+// Content type detection for uploads
+// internal/document/sniff.go
+package document
+
+import (
+	"net/http"
+	"strings"
+
+	"patient-service/internal/domain"
+)
+
+// SniffLen is how many leading bytes Sniff looks at.
+const SniffLen = 512
+
+// Accepted content types. The type claimed by the upload is ignored.
+var (
+	imageTypes    = []string{"image/jpeg", "image/png", "image/webp"}
+	documentTypes = append([]string{"application/pdf"}, imageTypes...)
+)
+
+// Sniff returns the content type of a file from its first SniffLen bytes.
+func Sniff(head []byte) string {
+	contentType := http.DetectContentType(head)
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType
+}
+
+// Accepts reports whether files of the content type may be attached as
+// the document type: photos must be images, other documents may also be
+// PDFs.
+func Accepts(documentType, contentType string) bool {
+	accepted := documentTypes
+	if documentType == domain.DocumentPhoto {
+		accepted = imageTypes
+	}
+	for _, t := range accepted {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+// Patient change feed
+// internal/domain/change.go
+package domain
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PatientChange is a change feed record read from the outbox. Sequence is
+// the outbox position and orders the feed.
+type PatientChange struct {
+	Sequence  int64
+	PatientID string
+	TenantID  string // the patient's; only its subscribers see the change
+	Operation string // created, updated, deleted, merged or deceased
+	Version   int64
+	Timestamp time.Time
+	Data      PatientEventData
+}
+
+// NewPatientChange reads a change from an outbox event.
+func NewPatientChange(event *OutboxEvent) (*PatientChange, error) {
+	change := &PatientChange{
+		Sequence:  event.ID,
+		PatientID: event.PatientID,
+		TenantID:  event.TenantID,
+		Operation: strings.TrimPrefix(event.Type, "patient."),
+		Timestamp: event.OccurredAt,
+	}
+	if err := json.Unmarshal(event.Data, &change.Data); err != nil {
+		return nil, err
+	}
+	change.Version = change.Data.Version
+	return change, nil
+}
+
+// ChangePage is a page of the change feed. Next resumes after the last
+// change, or at the same position when the page is empty.
+type ChangePage struct {
+	Changes []*PatientChange
+	Next    string
+	HasMore bool
+}
+
+// ChangeToken returns the token resuming the feed after the position.
+func ChangeToken(sequence int64) string {
+	return strconv.FormatInt(sequence, 10)
+}
+
+// ParseChangeToken returns the position a token resumes after.
+func ParseChangeToken(token string) (int64, error) {
+	return strconv.ParseInt(token, 10, 64)
+}
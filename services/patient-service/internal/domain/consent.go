@@ -0,0 +1,92 @@
+// Patient consents
+// internal/domain/consent.go
+package domain
+
+import "time"
+
+// Consent types, the purposes a patient's data may be released for
+const (
+	ConsentSatuSehat     = "satusehat"      // sharing with the national SATUSEHAT platform
+	ConsentResearch      = "research"       // research datasets, de-identified
+	ConsentSMSReminder   = "sms_reminder"   // appointment and medication reminders by SMS
+	ConsentFamilySharing = "family_sharing" // disclosure to family members
+)
+
+// ConsentTypes lists the consent types.
+var ConsentTypes = []string{ConsentSatuSehat, ConsentResearch, ConsentSMSReminder, ConsentFamilySharing}
+
+// IsConsentType reports whether name is one of ConsentTypes.
+func IsConsentType(name string) bool {
+	for _, t := range ConsentTypes {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Consent decisions
+const (
+	ConsentPermit = "permit"
+	ConsentDeny   = "deny"
+)
+
+// Consent statuses, derived from the validity period and revocation
+const (
+	ConsentActive  = "active"
+	ConsentPending = "pending" // valid from a later date
+	ConsentExpired = "expired"
+	ConsentRevoked = "revoked"
+)
+
+// Consent is a patient's decision on releasing their data for a purpose.
+// Of the consents of a type in effect, the one valid from the latest date
+// applies.
+type Consent struct {
+	ID        string
+	PatientID string
+	Type      string
+	Decision  string // permit or deny
+	Scope     string // what the consent covers, e.g. a study or a family member
+
+	// Grantor is who gave the consent: the patient, or a guardian or
+	// relative for patients who cannot
+	GrantorName         string
+	GrantorRelationship string
+
+	ValidFrom   time.Time
+	ValidUntil  *time.Time // nil when open-ended
+	DocumentRef string     // reference to the signed consent form
+
+	RevokedAt        *time.Time
+	RevokedBy        string
+	RevocationReason string
+
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Status returns the consent's status at the given time.
+func (c *Consent) Status(at time.Time) string {
+	switch {
+	case c.RevokedAt != nil:
+		return ConsentRevoked
+	case at.Before(c.ValidFrom):
+		return ConsentPending
+	case c.ValidUntil != nil && !at.Before(*c.ValidUntil):
+		return ConsentExpired
+	}
+	return ConsentActive
+}
+
+// Validate checks the consent's values.
+func (c *Consent) Validate() error {
+	if c.ValidUntil != nil && !c.ValidUntil.After(c.ValidFrom) {
+		return NewCustomError("INVALID_VALIDITY", "valid_until must be after valid_from", "")
+	}
+	if c.GrantorRelationship != "self" && c.GrantorName == "" {
+		return NewCustomError("INVALID_GRANTOR", "grantor_name is required when consent is given on the patient's behalf", "")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Keyset pagination cursor
+// internal/domain/cursor.go
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a sorted patient list: the value of the sort
+// column and the ID of the row at that position. Backward cursors page
+// towards the start of the list.
+type Cursor struct {
+	Sort     string `json:"s"`
+	Order    string `json:"o"`
+	Value    string `json:"v"`
+	ID       string `json:"id"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// Encode returns the opaque token handed to API clients.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if c.Sort == "" || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// NewCursor builds a cursor positioned at patient for the given sort.
+func NewCursor(patient *Patient, sort, order string, backward bool) Cursor {
+	var value string
+	switch sort {
+	case "created_at":
+		value = patient.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		value = patient.UpdatedAt.Format(time.RFC3339Nano)
+	case "first_name":
+		value = patient.FirstName
+	case "last_name":
+		value = patient.LastName
+	}
+
+	return Cursor{
+		Sort:     sort,
+		Order:    order,
+		Value:    value,
+		ID:       patient.ID,
+		Backward: backward,
+	}
+}
+
+// PatientPage is one page of a patient listing.
+type PatientPage struct {
+	Patients   []*Patient
+	Total      *int // nil when the total was not requested
+	NextCursor string
+	PrevCursor string
+}
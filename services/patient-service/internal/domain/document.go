@@ -0,0 +1,44 @@
+// Patient documents
+// internal/domain/document.go
+package domain
+
+import "time"
+
+// Document types
+const (
+	DocumentKTP           = "ktp"            // scanned identity card
+	DocumentInsuranceCard = "insurance_card" // BPJS or private insurance card
+	DocumentConsentForm   = "consent_form"   // signed consent form
+	DocumentPhoto         = "photo"          // photo for identification
+	DocumentOther         = "other"
+)
+
+// DocumentTypes lists the document types.
+var DocumentTypes = []string{DocumentKTP, DocumentInsuranceCard, DocumentConsentForm, DocumentPhoto, DocumentOther}
+
+// Virus scan results
+const (
+	ScanClean      = "clean"
+	ScanNotScanned = "not_scanned" // no scanner configured
+)
+
+// Document is a file attached to a patient, such as a scanned KTP or a
+// signed consent form. The content is kept in blob storage under
+// StorageKey.
+type Document struct {
+	ID          string
+	PatientID   string
+	Type        string
+	Description string
+	IssuedDate  *time.Time // date printed on the document, when it has one
+
+	FileName    string // as uploaded
+	ContentType string // sniffed from the content, not taken from the upload
+	Size        int64
+	SHA256      string // hex
+	StorageKey  string
+	ScanStatus  string
+
+	UploadedBy string
+	CreatedAt  time.Time
+}
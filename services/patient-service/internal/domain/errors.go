@@ -9,6 +9,44 @@ var (
 	ErrPatientNotFound      = errors.New("patient not found")
 	ErrPatientAlreadyExists = errors.New("patient already exists")
 	ErrInvalidPatientData   = errors.New("invalid patient data")
+	ErrPatientDeceased      = errors.New("patient is deceased")
+	ErrPatientNotDeceased   = errors.New("patient is not recorded as deceased")
+
+	// Webhook errors
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+
+	// Import errors
+	ErrImportNotFound = errors.New("import job not found")
+
+	// Export errors
+	ErrExportNotFound = errors.New("export job not found")
+	ErrExportNotReady = errors.New("export file is not ready")
+	ErrDeidentOff     = errors.New("de-identified exports are not configured")
+
+	// Consent errors
+	ErrConsentNotFound = errors.New("consent not found")
+	ErrConsentRevoked  = errors.New("consent is already revoked")
+
+	// Document errors
+	ErrDocumentNotFound     = errors.New("document not found")
+	ErrDocumentInfected     = errors.New("document failed the virus scan")
+	ErrUnsupportedMediaType = errors.New("unsupported file type")
+	ErrInvalidSignature     = errors.New("invalid or expired download link")
+
+	// Public profile token errors
+	ErrQRTokenNotFound = errors.New("public profile token not found")
+	ErrQRTokenRevoked  = errors.New("public profile token already revoked")
+	ErrInvalidQRToken  = errors.New("invalid, expired or revoked public profile token")
+
+	// Change feed errors
+	ErrChangesExpired = errors.New("change feed position is no longer retained")
+
+	// Search errors
+	ErrSearchUnavailable = errors.New("search index is not ready")
+
+	// Tenant errors
+	ErrTenantRequired = errors.New("no tenant in context")
 
 	// General errors
 	ErrInvalidInput        = errors.New("invalid input")
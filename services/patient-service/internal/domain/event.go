@@ -0,0 +1,97 @@
+// Patient domain events
+// internal/domain/event.go
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types, written to the outbox in the same transaction as the change
+const (
+	EventPatientCreated  = "patient.created"
+	EventPatientUpdated  = "patient.updated"
+	EventPatientDeleted  = "patient.deleted"
+	EventPatientMerged   = "patient.merged"
+	EventPatientDeceased = "patient.deceased"
+)
+
+// OutboxEvent is a stored event. ID is the outbox position; events of one
+// patient are stored in the order their transactions committed.
+type OutboxEvent struct {
+	ID            int64
+	EventID       string
+	Type          string
+	PatientID     string
+	TenantID      string // the patient's
+	Data          json.RawMessage
+	OccurredAt    time.Time
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time // zero until a publish attempt fails
+}
+
+// PatientEventData is the event payload. Encrypted identifiers (NIK, phone,
+// email, insurance number) and clinical fields are left out because the
+// outbox is stored in plaintext; consumers read them through the API.
+type PatientEventData struct {
+	ID              string   `json:"id"`
+	TenantID        string   `json:"tenant_id,omitempty"`
+	EnterpriseID    string   `json:"enterprise_id,omitempty"`
+	MedicalRecordNo string   `json:"medical_record_no,omitempty"`
+	FirstName       string   `json:"first_name,omitempty"`
+	LastName        string   `json:"last_name,omitempty"`
+	DateOfBirth     string   `json:"date_of_birth,omitempty"`
+	Gender          string   `json:"gender,omitempty"`
+	City            string   `json:"city,omitempty"`
+	Province        string   `json:"province,omitempty"`
+	IsActive        bool     `json:"is_active"`
+	DeceasedAt      string   `json:"deceased_at,omitempty"` // RFC 3339; the cause is clinical data
+	Version         int64    `json:"version"`               // bumped by every change
+	ChangedFields   []string `json:"changed_fields,omitempty"`
+	MergedInto      string   `json:"merged_into,omitempty"`
+	Actor           string   `json:"actor,omitempty"`
+}
+
+// NewPatientEventData returns the payload describing the patient's state.
+func NewPatientEventData(patient *Patient) PatientEventData {
+	data := PatientEventData{
+		ID:              patient.ID,
+		TenantID:        patient.TenantID,
+		EnterpriseID:    patient.EnterpriseID,
+		MedicalRecordNo: patient.MedicalRecordNo,
+		FirstName:       patient.FirstName,
+		LastName:        patient.LastName,
+		Gender:          patient.Gender,
+		City:            patient.City,
+		Province:        patient.Province,
+		IsActive:        patient.IsActive,
+		Actor:           patient.UpdatedBy,
+	}
+	if !patient.DateOfBirth.IsZero() {
+		data.DateOfBirth = patient.DateOfBirth.Format("2006-01-02")
+	}
+	if patient.DeceasedAt != nil {
+		data.DeceasedAt = patient.DeceasedAt.UTC().Format(time.RFC3339)
+	}
+	return data
+}
+
+// NewOutboxEvent creates an event about the patient in data.
+func NewOutboxEvent(eventType string, data PatientEventData) (*OutboxEvent, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		EventID:    uuid.New().String(),
+		Type:       eventType,
+		PatientID:  data.ID,
+		TenantID:   data.TenantID,
+		Data:       encoded,
+		OccurredAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,83 @@
+// Patient exports
+// internal/domain/export.go
+package domain
+
+import "time"
+
+// Export job statuses
+const (
+	ExportPending   = "pending"
+	ExportRunning   = "running"
+	ExportCompleted = "completed"
+	ExportFailed    = "failed"
+)
+
+// Export file formats
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatNDJSON  = "ndjson"
+	ExportFormatParquet = "parquet"
+)
+
+// ExportFormats lists the supported formats.
+var ExportFormats = []string{ExportFormatCSV, ExportFormatNDJSON, ExportFormatParquet}
+
+// ExportJob is an export written to the file store in the background, for
+// extracts too large to stream in one request.
+type ExportJob struct {
+	ID       string
+	TenantID string // patients are exported from the requester's tenant
+	Format   string
+	Status   string
+
+	// Filter selects the patients; its Fields are the exported columns,
+	// already limited to what the requesting role may read
+	Filter PatientFilter
+
+	// ConsentType, when set, limits the export to patients whose consent
+	// allows it
+	ConsentType string
+
+	// Ruleset is set for de-identified exports, whose Filter.Fields are
+	// the de-identified columns
+	Ruleset *DeidentRuleset
+
+	RowCount   int   // rows written so far
+	Suppressed int   // rows withheld for k-anonymity
+	FileSize   int64 // set once completed
+	Error      string
+	CreatedBy  string
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// Finished reports whether the job has stopped processing.
+func (j *ExportJob) Finished() bool {
+	return j.Status == ExportCompleted || j.Status == ExportFailed
+}
+
+// FileName is the name of the job's file in the file store.
+func (j *ExportJob) FileName() string {
+	return "patients-" + j.ID + "." + j.Format
+}
+
+// DeidentRuleset records how a de-identified export was produced, so that
+// the dataset can be reproduced from the same data and pseudonym key.
+type DeidentRuleset struct {
+	Version       int    `json:"version"`
+	KeyID         string `json:"key_id"`         // fingerprint of the pseudonym key
+	ReferenceDate string `json:"reference_date"` // YYYY-MM-DD; ages are computed on this day
+
+	DateOfBirth  string `json:"date_of_birth"`  // generalized to "year" or "age_band"
+	AgeBandYears int    `json:"age_band_years"` // width of an age band
+	TopAge       int    `json:"top_age"`        // older patients share one band and birth year
+	PostalDigits int    `json:"postal_digits"`  // leading postal code digits kept
+
+	// K is the smallest group of rows sharing the quasi-identifiers that
+	// is released; rows of smaller groups are suppressed. The job fails
+	// when more than MaxSuppressionPercent of the rows would be.
+	QuasiIdentifiers      []string `json:"quasi_identifiers"`
+	K                     int      `json:"k"`
+	MaxSuppressionPercent float64  `json:"max_suppression_percent"`
+}
@@ -0,0 +1,99 @@
+// Selectable patient fields
+// internal/domain/fields.go
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// PatientFields is the allow-list of fields that can be requested with
+// ?fields=, in response order. Names match the JSON field names.
+var PatientFields = []string{
+	"id", "tenant_id", "enterprise_id", "medical_record_no", "nik", "first_name", "last_name",
+	"date_of_birth", "gender", "blood_type", "phone", "email",
+	"address", "city", "province", "postal_code",
+	"emergency_contact", "emergency_phone",
+	"insurance_provider", "insurance_number",
+	"allergies", "chronic_conditions",
+	"is_active", "created_at", "updated_at",
+	"deceased_at", "cause_of_death", "death_recorded_by",
+}
+
+var patientFieldSet = func() map[string]bool {
+	set := make(map[string]bool, len(PatientFields))
+	for _, f := range PatientFields {
+		set[f] = true
+	}
+	return set
+}()
+
+// ParseFields validates a comma separated field list against PatientFields.
+// It always includes "id" and returns nil for an empty list (all fields).
+func ParseFields(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	fields := []string{"id"}
+	seen := map[string]bool{"id": true}
+
+	for _, f := range strings.Split(value, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" || seen[f] {
+			continue
+		}
+		if !patientFieldSet[f] {
+			return nil, NewCustomError("INVALID_FIELDS", "Unknown field: "+f, "Allowed fields: "+strings.Join(PatientFields, ", "))
+		}
+		seen[f] = true
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// writableFields are the fields a client can change, with their values.
+var writableFields = []struct {
+	name  string
+	value func(p *Patient) interface{}
+}{
+	{"nik", func(p *Patient) interface{} { return p.NIK }},
+	{"first_name", func(p *Patient) interface{} { return p.FirstName }},
+	{"last_name", func(p *Patient) interface{} { return p.LastName }},
+	{"date_of_birth", func(p *Patient) interface{} { return p.DateOfBirth }},
+	{"gender", func(p *Patient) interface{} { return p.Gender }},
+	{"blood_type", func(p *Patient) interface{} { return p.BloodType }},
+	{"phone", func(p *Patient) interface{} { return p.Phone }},
+	{"email", func(p *Patient) interface{} { return p.Email }},
+	{"address", func(p *Patient) interface{} { return p.Address }},
+	{"city", func(p *Patient) interface{} { return p.City }},
+	{"province", func(p *Patient) interface{} { return p.Province }},
+	{"postal_code", func(p *Patient) interface{} { return p.PostalCode }},
+	{"emergency_contact", func(p *Patient) interface{} { return p.EmergencyContact }},
+	{"emergency_phone", func(p *Patient) interface{} { return p.EmergencyPhone }},
+	{"insurance_provider", func(p *Patient) interface{} { return p.InsuranceProvider }},
+	{"insurance_number", func(p *Patient) interface{} { return p.InsuranceNumber }},
+	{"allergies", func(p *Patient) interface{} { return p.Allergies }},
+	{"chronic_conditions", func(p *Patient) interface{} { return p.ChronicConditions }},
+}
+
+// ChangedFields returns the writable fields that differ between two versions
+// of a patient.
+func ChangedFields(before, after *Patient) []string {
+	var changed []string
+	for _, f := range writableFields {
+		a, b := f.value(before), f.value(after)
+		if t, ok := a.(time.Time); ok {
+			if !t.Equal(b.(time.Time)) {
+				changed = append(changed, f.name)
+			}
+			continue
+		}
+		if a != b {
+			changed = append(changed, f.name)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{"  ", nil},
+		{"first_name", []string{"id", "first_name"}},
+		{"id", []string{"id"}},
+		{" last_name , first_name,,", []string{"id", "last_name", "first_name"}},
+		{"first_name,first_name,id,nik", []string{"id", "first_name", "nik"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseFields(tt.value)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseFieldsRejectsUnknownNames(t *testing.T) {
+	// Field names end up in the SELECT list, so anything outside the
+	// allow-list, including internal columns, must be rejected
+	for _, value := range []string{
+		"password",
+		"id;drop",
+		"first_name,id;drop table patients--",
+		"nik_bidx",
+		"data_key",
+		"data_key_id",
+		"last_name_key",
+		"created_by",
+		"FIRST_NAME",
+		"first name",
+		"*",
+	} {
+		fields, err := ParseFields(value)
+		if err == nil {
+			t.Errorf("%q: expected an error, got %v", value, fields)
+			continue
+		}
+		if customErr, ok := err.(*CustomError); !ok || customErr.Code != "INVALID_FIELDS" {
+			t.Errorf("%q: expected INVALID_FIELDS, got %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Identifiers issued to patients by external registries
+// internal/domain/identifier.go
+package domain
+
+import "time"
+
+// Identifier is an additional patient identifier, such as the SATUSEHAT
+// IHS number. A patient has at most one value per system.
+type Identifier struct {
+	PatientID string    `json:"patient_id"`
+	System    string    `json:"system"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IdentitySyncTask is a queued identifier lookup for a patient.
+type IdentitySyncTask struct {
+	PatientID     string
+	TenantID      string // the patient's
+	Attempts      int
+	LastError     string
+	EnqueuedAt    time.Time // distinguishes re-enqueued tasks from the claimed one
+	NextAttemptAt time.Time
+}
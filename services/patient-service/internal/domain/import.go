@@ -0,0 +1,61 @@
+// Bulk patient import jobs
+// internal/domain/import.go
+package domain
+
+import "time"
+
+// Import job statuses
+const (
+	ImportPending   = "pending"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed" // the file could not be processed at all
+)
+
+// Import file formats
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatXLSX = "xlsx"
+)
+
+// ImportJob is an uploaded file of patients processed in the background.
+// Counters cover data rows; blank rows are skipped and not counted. In a
+// dry run nothing is inserted and ValidRows is what would be imported.
+type ImportJob struct {
+	ID       string
+	TenantID string // patients are imported into the uploader's tenant
+	FileName string
+	Format   string
+	Status   string
+	DryRun   bool
+
+	// Mapping maps patient fields (JSON names) to source column headers
+	Mapping map[string]string
+	Columns []string // source header row, for the error report
+
+	TotalRows     int
+	ProcessedRows int
+	ValidRows     int
+	ImportedRows  int
+	RejectedRows  int
+
+	Error      string
+	CreatedBy  string
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// Finished reports whether the job has stopped processing.
+func (j *ImportJob) Finished() bool {
+	return j.Status == ImportCompleted || j.Status == ImportFailed
+}
+
+// ImportRowError is a rejected row with the first problem found in it.
+type ImportRowError struct {
+	Row     int    // spreadsheet row number, the header being row 1
+	Field   string // patient field, empty when the row as a whole is rejected
+	Code    string
+	Message string
+	Values  []string // the row as uploaded
+}
@@ -2,11 +2,14 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 )
 
 type Patient struct {
 	ID                string    `json:"id"`
+	TenantID          string    `json:"tenant_id"`     // registering facility; MRN and NIK are unique per tenant
+	EnterpriseID      string    `json:"enterprise_id"` // shared by the person's records across tenants
 	MedicalRecordNo   string    `json:"medical_record_no"`
 	NIK               string    `json:"nik"`
 	FirstName         string    `json:"first_name"`
@@ -31,16 +34,156 @@ type Patient struct {
 	UpdatedAt         time.Time `json:"updated_at"`
 	CreatedBy         string    `json:"created_by"`
 	UpdatedBy         string    `json:"updated_by"`
+
+	// Set once the patient's death is recorded; deceased patients stay
+	// active and readable
+	DeceasedAt      *time.Time `json:"deceased_at"`
+	CauseOfDeath    string     `json:"cause_of_death"`    // coded reference, e.g. ICD-10 I21.9
+	DeathRecordedBy string     `json:"death_recorded_by"` // recording clinician
+}
+
+// IsDeceased reports whether the patient's death is recorded.
+func (p *Patient) IsDeceased() bool {
+	return p.DeceasedAt != nil
+}
+
+// RecordDeath sets the patient's death; the time of death cannot be in
+// the future or before the patient's birth.
+func (p *Patient) RecordDeath(deceasedAt time.Time, cause, clinician string) error {
+	if deceasedAt.After(time.Now()) {
+		return NewCustomError("INVALID_DECEASED_AT", "Time of death cannot be in the future", "")
+	}
+	if !p.DateOfBirth.IsZero() && deceasedAt.Before(p.DateOfBirth) {
+		return NewCustomError("INVALID_DECEASED_AT", "Time of death cannot be before the date of birth", "")
+	}
+	p.DeceasedAt = &deceasedAt
+	p.CauseOfDeath = cause
+	p.DeathRecordedBy = clinician
+	return nil
+}
+
+// DefaultMRNPrefix prefixes medical record numbers of tenants without one.
+const DefaultMRNPrefix = "RM"
+
+// NewMedicalRecordNo generates a medical record number with the format
+// PREFIX-YYYYMMDD-XXXXX, e.g. RM-20240101-00042 for an empty prefix. The
+// database keeps numbers unique within a tenant.
+func NewMedicalRecordNo(prefix string) string {
+	if prefix == "" {
+		prefix = DefaultMRNPrefix
+	}
+	now := time.Now()
+	return fmt.Sprintf("%s-%s-%05d", prefix, now.Format("20060102"), now.UnixNano()%100000)
+}
+
+// Validate checks the rules every stored patient must satisfy, whichever
+// API or import created it.
+func (p *Patient) Validate() error {
+	if p.NIK == "" || len(p.NIK) != 16 {
+		return NewCustomError("INVALID_NIK", "NIK must be 16 characters", "")
+	}
+
+	if p.FirstName == "" {
+		return NewCustomError("INVALID_NAME", "First name is required", "")
+	}
+
+	if p.DateOfBirth.IsZero() {
+		return NewCustomError("INVALID_DOB", "Date of birth is required", "")
+	}
+
+	if p.DateOfBirth.After(time.Now()) {
+		return NewCustomError("INVALID_DOB", "Date of birth cannot be in the future", "")
+	}
+
+	if p.Gender != "MALE" && p.Gender != "FEMALE" {
+		return NewCustomError("INVALID_GENDER", "Gender must be MALE or FEMALE", "")
+	}
+
+	if p.Phone == "" {
+		return NewCustomError("INVALID_PHONE", "Phone number is required", "")
+	}
+
+	// Validate blood type if provided
+	if p.BloodType != "" {
+		validBloodTypes := map[string]bool{
+			"A+": true, "A-": true,
+			"B+": true, "B-": true,
+			"AB+": true, "AB-": true,
+			"O+": true, "O-": true,
+		}
+
+		if !validBloodTypes[p.BloodType] {
+			return NewCustomError("INVALID_BLOOD_TYPE", "Invalid blood type", "")
+		}
+	}
+
+	return nil
 }
 
-// PatientFilter untuk query filtering
+// PatientPatch applies a partial update to a copy of the stored patient.
+type PatientPatch func(patient *Patient) error
+
+// PatientFilter untuk query filtering.
+// Multi-value fields match any of the given values; all fields combine with AND.
 type PatientFilter struct {
-	Search   string
-	City     string
-	Province string
-	IsActive *bool
-	Page     int
-	Limit    int
-	Sort     string
-	Order    string // ASC or DESC
+	Search             string
+	GivenName          string // a word of first_name starts with it
+	FamilyName         string // a word of last_name starts with it
+	Cities             []string
+	Provinces          []string
+	Genders            []string
+	BloodTypes         []string
+	InsuranceProviders []string
+	CreatedBy          []string
+	DateOfBirth        *time.Time
+	AgeMin             *int
+	AgeMax             *int
+	CreatedFrom        *time.Time // inclusive
+	CreatedTo          *time.Time // exclusive
+	UpdatedFrom        *time.Time // inclusive
+	UpdatedTo          *time.Time // exclusive
+	IsActive           *bool
+	Deceased           *bool
+	DeceasedFrom       *time.Time // inclusive
+	DeceasedTo         *time.Time // exclusive
+	EnterpriseID       string
+	Page               int
+	Limit              int
+	Sort               string
+	Order              string   // ASC or DESC
+	Fields             []string // nil selects all fields, see PatientFields
+
+	// Cursor switches to keyset pagination; Page is ignored when set
+	Cursor    *Cursor
+	SkipTotal bool
+}
+
+// PatientMatch is a search hit with its relevance score in (0, 1]
+type PatientMatch struct {
+	Patient *Patient
+	Score   float64
+}
+
+// PatientKey is the identifier a batch lookup matches patients on. Values
+// are the JSON field names.
+type PatientKey string
+
+// MaxBatchGet is the most keys a batch lookup accepts.
+const MaxBatchGet = 100
+
+const (
+	KeyID              PatientKey = "id"
+	KeyNIK             PatientKey = "nik"
+	KeyMedicalRecordNo PatientKey = "medical_record_no"
+)
+
+// Key returns the patient's value of key.
+func (p *Patient) Key(key PatientKey) string {
+	switch key {
+	case KeyNIK:
+		return p.NIK
+	case KeyMedicalRecordNo:
+		return p.MedicalRecordNo
+	}
+	return p.ID
 }
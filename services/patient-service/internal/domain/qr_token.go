@@ -0,0 +1,60 @@
+// Public profile tokens
+// internal/domain/qr_token.go
+package domain
+
+import "time"
+
+// QRToken grants access to a patient's minimal public profile, e.g. from
+// the QR code on a wristband. Tokens expire and can be revoked.
+type QRToken struct {
+	ID        string
+	PatientID string
+	TenantID  string // the patient's; set by lookups
+	ExpiresAt time.Time
+
+	RevokedAt *time.Time
+	RevokedBy string
+
+	LastScannedAt *time.Time
+	ScanCount     int
+
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// Token statuses
+const (
+	QRTokenActive  = "active"
+	QRTokenExpired = "expired"
+	QRTokenRevoked = "revoked"
+)
+
+// Status returns the token's status at the given time.
+func (t *QRToken) Status(now time.Time) string {
+	switch {
+	case t.RevokedAt != nil:
+		return QRTokenRevoked
+	case now.After(t.ExpiresAt):
+		return QRTokenExpired
+	}
+	return QRTokenActive
+}
+
+// Scan results
+const (
+	QRScanOK      = "ok"
+	QRScanInvalid = "invalid" // bad signature or unknown token
+	QRScanExpired = "expired"
+	QRScanRevoked = "revoked"
+)
+
+// QRScan is the audit record of a scan of a public profile token.
+type QRScan struct {
+	ID        int64
+	TokenID   string // empty for tokens that could not be verified
+	PatientID string
+	Result    string
+	IP        string
+	UserAgent string
+	ScannedAt time.Time
+}
@@ -0,0 +1,86 @@
+// Webhook subscriptions and deliveries
+// internal/domain/webhook.go
+package domain
+
+import "time"
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead" // gave up after the maximum attempts
+)
+
+// EventTypes lists the event types webhooks can subscribe to.
+var EventTypes = []string{EventPatientCreated, EventPatientUpdated, EventPatientDeleted, EventPatientMerged, EventPatientDeceased}
+
+// WebhookSubscription sends events of the given types to URL. An empty
+// EventTypes subscribes to all events.
+type WebhookSubscription struct {
+	ID                  string     `json:"id"`
+	TenantID            string     `json:"tenant_id"` // only the tenant's patient events are delivered
+	URL                 string     `json:"url"`
+	Description         string     `json:"description"`
+	EventTypes          []string   `json:"event_types"`
+	ConsentType         string     `json:"consent_type"` // when set, only events of patients who allow it are delivered
+	Secret              string     `json:"-"`
+	IsActive            bool       `json:"is_active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at"`
+	DisabledReason      string     `json:"disabled_reason"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	CreatedBy           string     `json:"created_by"`
+	UpdatedBy           string     `json:"updated_by"`
+}
+
+// Accepts reports whether the subscription wants events of the type.
+func (s *WebhookSubscription) Accepts(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent to one subscription.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventType      string // outbox event type, e.g. patient.created
+	Payload        []byte // CloudEvent JSON
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+	Log            []*WebhookAttempt // filled by GetDelivery
+}
+
+// WebhookAttempt is a delivery log entry.
+type WebhookAttempt struct {
+	AttemptedAt time.Time
+	StatusCode  int // 0 when no response was received
+	Error       string
+	Duration    time.Duration
+}
+
+// Succeeded reports whether the receiver acknowledged the delivery.
+func (a *WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode <= 299
+}
+
+// DeliveryFilter selects deliveries; empty fields match everything.
+type DeliveryFilter struct {
+	SubscriptionID string
+	Status         string
+	Page           int
+	Limit          int
+}
@@ -47,11 +47,146 @@ type UpdatePatientRequest struct {
 
 type ListPatientsRequest struct {
 	Search   string `query:"search"`
-	City     string `query:"city"`
-	Province string `query:"province"`
+	City     string `query:"city" validate:"max=1000,csvmax=20"`
+	Province string `query:"province" validate:"max=1000,csvmax=20"`
 	IsActive *bool  `query:"is_active"`
 	Page     int    `query:"page" validate:"min=1"`
 	Limit    int    `query:"limit" validate:"min=1,max=100"`
-	Sort     string `query:"sort" validate:"omitempty,oneof=created_at updated_at first_name last_name nik"`
+	Sort     string `query:"sort" validate:"omitempty,oneof=created_at updated_at first_name last_name"`
 	Order    string `query:"order" validate:"omitempty,oneof=ASC DESC asc desc"`
+
+	// Demographic filters, multi-value as comma separated lists
+	Gender            string `query:"gender" validate:"omitempty,csvoneof=MALE FEMALE"`
+	BloodType         string `query:"blood_type" validate:"omitempty,csvoneof=A+ A- B+ B- AB+ AB- O+ O-"`
+	InsuranceProvider string `query:"insurance_provider" validate:"max=1000,csvmax=20"`
+	CreatedBy         string `query:"created_by" validate:"max=1000,csvmax=20"`
+	DateOfBirth       string `query:"dob" validate:"omitempty,datetime=2006-01-02"`
+	AgeMin            *int   `query:"age_min" validate:"omitempty,min=0,max=150"`
+	AgeMax            *int   `query:"age_max" validate:"omitempty,min=0,max=150"`
+
+	// Date ranges, YYYY-MM-DD (whole day) or RFC 3339
+	CreatedFrom string `query:"created_from" validate:"omitempty,isodate"`
+	CreatedTo   string `query:"created_to" validate:"omitempty,isodate"`
+	UpdatedFrom string `query:"updated_from" validate:"omitempty,isodate"`
+	UpdatedTo   string `query:"updated_to" validate:"omitempty,isodate"`
+
+	// Deceased status and date of death range
+	Deceased     *bool  `query:"deceased"`
+	DeceasedFrom string `query:"deceased_from" validate:"omitempty,isodate"`
+	DeceasedTo   string `query:"deceased_to" validate:"omitempty,isodate"`
+
+	// Records of one person across facilities
+	EnterpriseID string `query:"enterprise_id" validate:"omitempty,max=36"`
+
+	// Keyset pagination
+	Cursor       string `query:"cursor" validate:"max=1024"`
+	IncludeTotal *bool  `query:"include_total"`
+
+	// Sparse fieldsets and sub-resources
+	Fields string `query:"fields" validate:"max=500"`
+	Expand string `query:"expand" validate:"max=200"`
+}
+
+// RecordDeathRequest records a patient's death; the authenticated user is
+// recorded as the clinician.
+type RecordDeathRequest struct {
+	DeceasedAt   time.Time `json:"deceased_at" validate:"required"`
+	CauseOfDeath string    `json:"cause_of_death" validate:"max=255"`
+}
+
+// ExportPatientsRequest takes the ListPatientsRequest filters, parsed
+// separately; paging parameters do not apply.
+type ExportPatientsRequest struct {
+	Format string `query:"format" validate:"required,oneof=csv ndjson parquet"`
+
+	// Consent limits the export to patients whose consent allows the type
+	Consent string `query:"consent" validate:"omitempty,oneof=satusehat research sms_reminder family_sharing"`
+}
+
+// DeidentifiedExportRequest overrides the configured de-identification
+// ruleset; zero values keep the defaults.
+type DeidentifiedExportRequest struct {
+	DateOfBirth           string   `json:"date_of_birth" validate:"omitempty,oneof=year age_band"`
+	AgeBandYears          int      `json:"age_band_years" validate:"omitempty,min=1,max=20"`
+	PostalDigits          int      `json:"postal_digits" validate:"omitempty,min=1,max=4"`
+	QuasiIdentifiers      []string `json:"quasi_identifiers" validate:"omitempty,min=1,unique,dive,oneof=date_of_birth gender province postal_region blood_type"`
+	K                     int      `json:"k" validate:"omitempty,min=2,max=1000"`
+	MaxSuppressionPercent float64  `json:"max_suppression_percent" validate:"omitempty,gt=0,max=100"`
+}
+
+// CreateConsentRequest records a consent. Decision defaults to permit,
+// grantor_relationship to self and valid_from to now.
+type CreateConsentRequest struct {
+	Type                string     `json:"type" validate:"required,oneof=satusehat research sms_reminder family_sharing"`
+	Decision            string     `json:"decision" validate:"omitempty,oneof=permit deny"`
+	Scope               string     `json:"scope" validate:"max=500"`
+	GrantorName         string     `json:"grantor_name" validate:"max=200"`
+	GrantorRelationship string     `json:"grantor_relationship" validate:"omitempty,oneof=self parent guardian spouse child sibling other"`
+	ValidFrom           *time.Time `json:"valid_from"`
+	ValidUntil          *time.Time `json:"valid_until"`
+	DocumentRef         string     `json:"document_ref" validate:"max=500"`
+}
+
+type RevokeConsentRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// IssueQRTokenRequest sets how long a public profile token is valid; the
+// configured default applies when omitted.
+type IssueQRTokenRequest struct {
+	TTLHours int `json:"ttl_hours" validate:"omitempty,min=1"`
+}
+
+// UploadDocumentRequest is the form sent with a document's file.
+type UploadDocumentRequest struct {
+	Type        string `form:"type" validate:"required,oneof=ktp insurance_card consent_form photo other"`
+	Description string `form:"description" validate:"max=500"`
+	IssuedDate  string `form:"issued_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type GetPatientRequest struct {
+	Fields string `query:"fields" validate:"max=500"`
+	Expand string `query:"expand" validate:"max=200"`
+}
+
+// BatchGetPatientsRequest looks patients up by exactly one kind of key.
+// ?fields= and ?expand= apply as for a single read.
+type BatchGetPatientsRequest struct {
+	IDs              []string `json:"ids" validate:"max=100,dive,required,max=50"`
+	NIKs             []string `json:"niks" validate:"max=100,dive,len=16"`
+	MedicalRecordNos []string `json:"medical_record_nos" validate:"max=100,dive,required,max=50"`
+}
+
+type SearchPatientsRequest struct {
+	Query string `query:"q" validate:"required,min=2,max=100"`
+	Limit int    `query:"limit" validate:"min=1,max=50"`
+}
+
+type CreateWebhookRequest struct {
+	URL         string `json:"url" validate:"required,url,max=2000"`
+	Description string `json:"description" validate:"max=500"`
+	// Empty subscribes to all event types
+	EventTypes []string `json:"event_types" validate:"max=10,dive,oneof=patient.created patient.updated patient.deleted patient.merged patient.deceased"`
+	// Only events of patients who allow the consent type are delivered; when
+	// empty, the WEBHOOK_DEFAULT_CONSENT_TYPE is checked instead
+	ConsentType string `json:"consent_type" validate:"omitempty,oneof=satusehat research sms_reminder family_sharing"`
+}
+
+type UpdateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=2000"`
+	Description string   `json:"description" validate:"max=500"`
+	EventTypes  []string `json:"event_types" validate:"max=10,dive,oneof=patient.created patient.updated patient.deleted patient.merged patient.deceased"`
+	ConsentType string   `json:"consent_type" validate:"omitempty,oneof=satusehat research sms_reminder family_sharing"`
+	IsActive    *bool    `json:"is_active" validate:"required"`
+}
+
+type ListDeliveriesRequest struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending delivered dead"`
+	Page   int    `query:"page" validate:"min=1"`
+	Limit  int    `query:"limit" validate:"min=1,max=100"`
+}
+
+type ListChangesRequest struct {
+	Since string `query:"since" validate:"max=20"`
+	Limit int    `query:"limit" validate:"min=1,max=1000"`
 }
@@ -2,12 +2,19 @@
 package dto
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
 	"patient-service/internal/domain"
+	"patient-service/pkg/validator"
+	"strings"
 	"time"
 )
 
 type PatientResponse struct {
 	ID                string    `json:"id"`
+	TenantID          string    `json:"tenant_id"`
+	EnterpriseID      string    `json:"enterprise_id"` // shared by the person's records at all tenants
 	MedicalRecordNo   string    `json:"medical_record_no"`
 	NIK               string    `json:"nik"`
 	FirstName         string    `json:"first_name"`
@@ -30,18 +37,292 @@ type PatientResponse struct {
 	IsActive          bool      `json:"is_active"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
+
+	// Null unless the patient's death is recorded
+	DeceasedAt      *time.Time `json:"deceased_at"`
+	CauseOfDeath    string     `json:"cause_of_death,omitempty"`
+	DeathRecordedBy string     `json:"death_recorded_by,omitempty"`
+}
+
+type BatchGetPatientsResponse struct {
+	// *PatientResponse, or a field map when ?fields= or ?expand= is used
+	Data     []interface{} `json:"data"`
+	NotFound []string      `json:"not_found"` // requested keys without an active patient
 }
 
 type ListPatientsResponse struct {
-	Data       []*PatientResponse `json:"data"`
+	// *PatientResponse, or a field map when ?fields= or ?expand= is used
+	Data       []interface{}      `json:"data"`
 	Pagination PaginationResponse `json:"pagination"`
 }
 
+// AuditResponse is the "audit" sub-resource of a patient
+type AuditResponse struct {
+	CreatedBy     string    `json:"created_by"`
+	CreatedByName string    `json:"created_by_name,omitempty"` // when practitioner-service knows the user
+	UpdatedBy     string    `json:"updated_by"`
+	UpdatedByName string    `json:"updated_by_name,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// IdentifierResponse is an entry of the "identifiers" sub-resource
+type IdentifierResponse struct {
+	System    string    `json:"system"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PatientLinkResponse is the record of the same person at another tenant
+type PatientLinkResponse struct {
+	ID              string     `json:"id"`
+	TenantID        string     `json:"tenant_id"`
+	MedicalRecordNo string     `json:"medical_record_no"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	DateOfBirth     time.Time  `json:"date_of_birth"`
+	Gender          string     `json:"gender"`
+	CreatedAt       time.Time  `json:"created_at"`
+	DeceasedAt      *time.Time `json:"deceased_at"`
+}
+
+type ListPatientLinksResponse struct {
+	Data []*PatientLinkResponse `json:"data"`
+}
+
+// ChangeResponse is a change feed record. Patient holds the patient's
+// state after the change, limited to the fields the caller may read.
+type ChangeResponse struct {
+	ID            string                 `json:"id"` // patient ID
+	Operation     string                 `json:"operation"`
+	Version       int64                  `json:"version"`
+	Timestamp     time.Time              `json:"timestamp"`
+	Token         string                 `json:"token"` // resumes the feed after this change
+	ChangedFields []string               `json:"changed_fields,omitempty"`
+	MergedInto    string                 `json:"merged_into,omitempty"`
+	Patient       map[string]interface{} `json:"patient"`
+}
+
+type ListChangesResponse struct {
+	Data      []*ChangeResponse `json:"data"`
+	NextToken string            `json:"next_token"`
+	HasMore   bool              `json:"has_more"`
+}
+
+// WebhookResponse is a webhook subscription. Secret is only set when the
+// secret is created or rotated.
+type WebhookResponse struct {
+	ID                  string     `json:"id"`
+	URL                 string     `json:"url"`
+	Description         string     `json:"description"`
+	EventTypes          []string   `json:"event_types"`
+	ConsentType         string     `json:"consent_type,omitempty"`
+	Secret              string     `json:"secret,omitempty"`
+	IsActive            bool       `json:"is_active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type ListWebhooksResponse struct {
+	Data []*WebhookResponse `json:"data"`
+}
+
+// WebhookDeliveryResponse is a delivery; Payload and Attempts are only
+// included for a single delivery.
+type WebhookDeliveryResponse struct {
+	ID             string                    `json:"id"`
+	WebhookID      string                    `json:"webhook_id"`
+	EventID        string                    `json:"event_id"`
+	EventType      string                    `json:"event_type"`
+	Status         string                    `json:"status"`
+	AttemptCount   int                       `json:"attempt_count"`
+	NextAttemptAt  *time.Time                `json:"next_attempt_at,omitempty"`
+	LastStatusCode int                       `json:"last_status_code,omitempty"`
+	LastError      string                    `json:"last_error,omitempty"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	DeliveredAt    *time.Time                `json:"delivered_at,omitempty"`
+	Payload        json.RawMessage           `json:"payload,omitempty"`
+	Attempts       []*WebhookAttemptResponse `json:"attempts,omitempty"`
+}
+
+type WebhookAttemptResponse struct {
+	AttemptedAt time.Time `json:"attempted_at"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMS  int64     `json:"duration_ms"`
+}
+
+type ListDeliveriesResponse struct {
+	Data       []*WebhookDeliveryResponse `json:"data"`
+	Pagination PaginationResponse         `json:"pagination"`
+}
+
+// ImportJobResponse is a bulk import job. Progress is the percentage of
+// rows processed.
+type ImportJobResponse struct {
+	ID             string            `json:"id"`
+	FileName       string            `json:"file_name"`
+	Format         string            `json:"format"`
+	Status         string            `json:"status"`
+	DryRun         bool              `json:"dry_run"`
+	Mapping        map[string]string `json:"mapping"`
+	TotalRows      int               `json:"total_rows"`
+	ProcessedRows  int               `json:"processed_rows"`
+	ValidRows      int               `json:"valid_rows"`
+	ImportedRows   int               `json:"imported_rows"`
+	RejectedRows   int               `json:"rejected_rows"`
+	Progress       float64           `json:"progress"`
+	Error          string            `json:"error,omitempty"`
+	ErrorReportURL string            `json:"error_report_url,omitempty"`
+	CreatedBy      string            `json:"created_by"`
+	CreatedAt      time.Time         `json:"created_at"`
+	StartedAt      *time.Time        `json:"started_at,omitempty"`
+	FinishedAt     *time.Time        `json:"finished_at,omitempty"`
+}
+
+// ExportJobResponse is an asynchronous export; DownloadURL is set once the
+// file is ready.
+type ExportJobResponse struct {
+	ID          string     `json:"id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	Columns     []string   `json:"columns"`
+	RowCount    int        `json:"row_count"`
+	FileSize    int64      `json:"file_size,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+
+	// De-identified exports: the ruleset used, for reproducing the
+	// dataset, and the rows suppressed for k-anonymity
+	Ruleset        *domain.DeidentRuleset `json:"ruleset,omitempty"`
+	SuppressedRows *int                   `json:"suppressed_rows,omitempty"`
+
+	// Only patients who allow the consent type are exported
+	ConsentType string `json:"consent_type,omitempty"`
+}
+
+// ConsentResponse is a patient consent with its status at the time of the
+// request.
+type ConsentResponse struct {
+	ID                  string     `json:"id"`
+	PatientID           string     `json:"patient_id"`
+	Type                string     `json:"type"`
+	Decision            string     `json:"decision"`
+	Status              string     `json:"status"`
+	Scope               string     `json:"scope,omitempty"`
+	GrantorName         string     `json:"grantor_name,omitempty"`
+	GrantorRelationship string     `json:"grantor_relationship"`
+	ValidFrom           time.Time  `json:"valid_from"`
+	ValidUntil          *time.Time `json:"valid_until,omitempty"`
+	DocumentRef         string     `json:"document_ref,omitempty"`
+	RevokedAt           *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy           string     `json:"revoked_by,omitempty"`
+	RevocationReason    string     `json:"revocation_reason,omitempty"`
+	CreatedBy           string     `json:"created_by"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type ListConsentsResponse struct {
+	Data []*ConsentResponse `json:"data"`
+}
+
+// QRTokenResponse is a public profile token with the URL to print in a
+// QR code.
+type QRTokenResponse struct {
+	ID            string     `json:"id"`
+	PatientID     string     `json:"patient_id"`
+	Token         string     `json:"token"`
+	URL           string     `json:"url"`
+	Status        string     `json:"status"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy     string     `json:"revoked_by,omitempty"`
+	LastScannedAt *time.Time `json:"last_scanned_at,omitempty"`
+	ScanCount     int        `json:"scan_count"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type ListQRTokensResponse struct {
+	Data []*QRTokenResponse `json:"data"`
+}
+
+// QRScanResponse is the audit record of a token scan.
+type QRScanResponse struct {
+	ID        int64     `json:"id"`
+	Result    string    `json:"result"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+type ListQRScansResponse struct {
+	Data []*QRScanResponse `json:"data"`
+}
+
+// PublicPatientResponse is the minimal profile a public token resolves to.
+type PublicPatientResponse struct {
+	MedicalRecordNo string `json:"medical_record_no"`
+	FirstName       string `json:"first_name"`
+	LastName        string `json:"last_name,omitempty"`
+	Gender          string `json:"gender"`
+}
+
+// ConsentStatusResponse tells, per consent type, whether the patient's
+// data may currently be released for it.
+type ConsentStatusResponse struct {
+	PatientID string          `json:"patient_id"`
+	Allowed   map[string]bool `json:"allowed"`
+}
+
+// DocumentResponse is a patient document's metadata. DownloadURL is a
+// signed link that works without the bearer token until
+// DownloadExpiresAt.
+type DocumentResponse struct {
+	ID                string    `json:"id"`
+	PatientID         string    `json:"patient_id"`
+	Type              string    `json:"type"`
+	Description       string    `json:"description,omitempty"`
+	IssuedDate        string    `json:"issued_date,omitempty"`
+	FileName          string    `json:"file_name"`
+	ContentType       string    `json:"content_type"`
+	Size              int64     `json:"size"`
+	SHA256            string    `json:"sha256"`
+	ScanStatus        string    `json:"scan_status"`
+	UploadedBy        string    `json:"uploaded_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	DownloadURL       string    `json:"download_url"`
+	DownloadExpiresAt time.Time `json:"download_expires_at"`
+}
+
+type ListDocumentsResponse struct {
+	Data []*DocumentResponse `json:"data"`
+}
+
+type SearchPatientsResponse struct {
+	Data []*PatientSearchResult `json:"data"`
+}
+
+// PatientSearchResult is a patient with its relevance score (0-1)
+type PatientSearchResult struct {
+	Score float64 `json:"score"`
+	*PatientResponse
+}
+
 type PaginationResponse struct {
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int `json:"total"`
-	TotalPages int `json:"total_pages"`
+	Page       int    `json:"page,omitempty"` // omitted for cursor pagination
+	Limit      int    `json:"limit"`
+	Total      *int   `json:"total,omitempty"`
+	TotalPages *int   `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -63,6 +344,8 @@ type SuccessResponse struct {
 func ToPatientResponse(patient *domain.Patient) *PatientResponse {
 	return &PatientResponse{
 		ID:                patient.ID,
+		TenantID:          patient.TenantID,
+		EnterpriseID:      patient.EnterpriseID,
 		MedicalRecordNo:   patient.MedicalRecordNo,
 		NIK:               patient.NIK,
 		FirstName:         patient.FirstName,
@@ -85,9 +368,75 @@ func ToPatientResponse(patient *domain.Patient) *PatientResponse {
 		IsActive:          patient.IsActive,
 		CreatedAt:         patient.CreatedAt,
 		UpdatedAt:         patient.UpdatedAt,
+		DeceasedAt:        patient.DeceasedAt,
+		CauseOfDeath:      patient.CauseOfDeath,
+		DeathRecordedBy:   patient.DeathRecordedBy,
+	}
+}
+
+func ToPatientLinkResponse(patient *domain.Patient) *PatientLinkResponse {
+	return &PatientLinkResponse{
+		ID:              patient.ID,
+		TenantID:        patient.TenantID,
+		MedicalRecordNo: patient.MedicalRecordNo,
+		FirstName:       patient.FirstName,
+		LastName:        patient.LastName,
+		DateOfBirth:     patient.DateOfBirth,
+		Gender:          patient.Gender,
+		CreatedAt:       patient.CreatedAt,
+		DeceasedAt:      patient.DeceasedAt,
 	}
 }
 
+// patientResponseFields maps domain.PatientFields to response values
+var patientResponseFields = map[string]func(r *PatientResponse) interface{}{
+	"id":                 func(r *PatientResponse) interface{} { return r.ID },
+	"tenant_id":          func(r *PatientResponse) interface{} { return r.TenantID },
+	"enterprise_id":      func(r *PatientResponse) interface{} { return r.EnterpriseID },
+	"medical_record_no":  func(r *PatientResponse) interface{} { return r.MedicalRecordNo },
+	"nik":                func(r *PatientResponse) interface{} { return r.NIK },
+	"first_name":         func(r *PatientResponse) interface{} { return r.FirstName },
+	"last_name":          func(r *PatientResponse) interface{} { return r.LastName },
+	"date_of_birth":      func(r *PatientResponse) interface{} { return r.DateOfBirth },
+	"gender":             func(r *PatientResponse) interface{} { return r.Gender },
+	"blood_type":         func(r *PatientResponse) interface{} { return r.BloodType },
+	"phone":              func(r *PatientResponse) interface{} { return r.Phone },
+	"email":              func(r *PatientResponse) interface{} { return r.Email },
+	"address":            func(r *PatientResponse) interface{} { return r.Address },
+	"city":               func(r *PatientResponse) interface{} { return r.City },
+	"province":           func(r *PatientResponse) interface{} { return r.Province },
+	"postal_code":        func(r *PatientResponse) interface{} { return r.PostalCode },
+	"emergency_contact":  func(r *PatientResponse) interface{} { return r.EmergencyContact },
+	"emergency_phone":    func(r *PatientResponse) interface{} { return r.EmergencyPhone },
+	"insurance_provider": func(r *PatientResponse) interface{} { return r.InsuranceProvider },
+	"insurance_number":   func(r *PatientResponse) interface{} { return r.InsuranceNumber },
+	"allergies":          func(r *PatientResponse) interface{} { return r.Allergies },
+	"chronic_conditions": func(r *PatientResponse) interface{} { return r.ChronicConditions },
+	"is_active":          func(r *PatientResponse) interface{} { return r.IsActive },
+	"created_at":         func(r *PatientResponse) interface{} { return r.CreatedAt },
+	"updated_at":         func(r *PatientResponse) interface{} { return r.UpdatedAt },
+	"deceased_at":        func(r *PatientResponse) interface{} { return r.DeceasedAt },
+	"cause_of_death":     func(r *PatientResponse) interface{} { return r.CauseOfDeath },
+	"death_recorded_by":  func(r *PatientResponse) interface{} { return r.DeathRecordedBy },
+}
+
+// ToPatientFieldMap returns only the given fields of a patient; nil
+// returns all of them.
+func ToPatientFieldMap(patient *domain.Patient, fields []string) map[string]interface{} {
+	if fields == nil {
+		fields = domain.PatientFields
+	}
+
+	resp := ToPatientResponse(patient)
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		if value, ok := patientResponseFields[f]; ok {
+			m[f] = value(resp)
+		}
+	}
+	return m
+}
+
 func ToPatientDomain(req *CreatePatientRequest) *domain.Patient {
 	return &domain.Patient{
 		NIK:               req.NIK,
@@ -135,3 +484,409 @@ func ToUpdatePatientDomain(id string, req *UpdatePatientRequest) *domain.Patient
 		ChronicConditions: req.ChronicConditions,
 	}
 }
+
+// ToPatchDocument returns the patchable fields of a patient, the document a
+// merge patch or JSON Patch is applied to.
+func ToPatchDocument(patient *domain.Patient) *UpdatePatientRequest {
+	return &UpdatePatientRequest{
+		NIK:               patient.NIK,
+		FirstName:         patient.FirstName,
+		LastName:          patient.LastName,
+		DateOfBirth:       patient.DateOfBirth,
+		Gender:            patient.Gender,
+		BloodType:         patient.BloodType,
+		Phone:             patient.Phone,
+		Email:             patient.Email,
+		Address:           patient.Address,
+		City:              patient.City,
+		Province:          patient.Province,
+		PostalCode:        patient.PostalCode,
+		EmergencyContact:  patient.EmergencyContact,
+		EmergencyPhone:    patient.EmergencyPhone,
+		InsuranceProvider: patient.InsuranceProvider,
+		InsuranceNumber:   patient.InsuranceNumber,
+		Allergies:         patient.Allergies,
+		ChronicConditions: patient.ChronicConditions,
+	}
+}
+
+// ApplyPatchDocument copies a patched document back onto the patient,
+// leaving fields the document does not carry untouched.
+func ApplyPatchDocument(patient *domain.Patient, doc *UpdatePatientRequest) {
+	updated := ToUpdatePatientDomain(patient.ID, doc)
+	updated.MedicalRecordNo = patient.MedicalRecordNo
+	updated.IsActive = patient.IsActive
+	updated.CreatedAt = patient.CreatedAt
+	updated.UpdatedAt = patient.UpdatedAt
+	updated.CreatedBy = patient.CreatedBy
+	updated.UpdatedBy = patient.UpdatedBy
+	*patient = *updated
+}
+
+// ToPatientFilter converts validated list query parameters to a filter.
+// Cursor and pagination defaults are handled by the caller.
+func ToPatientFilter(req *ListPatientsRequest) (domain.PatientFilter, error) {
+	filter := domain.PatientFilter{
+		Search:             req.Search,
+		Cities:             validator.SplitCSV(req.City),
+		Provinces:          validator.SplitCSV(req.Province),
+		Genders:            validator.SplitCSV(strings.ToUpper(req.Gender)),
+		BloodTypes:         validator.SplitCSV(strings.ToUpper(req.BloodType)),
+		InsuranceProviders: validator.SplitCSV(req.InsuranceProvider),
+		CreatedBy:          validator.SplitCSV(req.CreatedBy),
+		AgeMin:             req.AgeMin,
+		AgeMax:             req.AgeMax,
+		IsActive:           req.IsActive,
+		Deceased:           req.Deceased,
+		EnterpriseID:       req.EnterpriseID,
+		Page:               req.Page,
+		Limit:              req.Limit,
+		Sort:               req.Sort,
+		Order:              req.Order,
+		SkipTotal:          req.IncludeTotal != nil && !*req.IncludeTotal,
+	}
+
+	if req.DateOfBirth != "" {
+		dob, _, err := validator.ParseISODate(req.DateOfBirth)
+		if err != nil {
+			return filter, domain.NewCustomError("INVALID_FILTER", "Invalid date of birth", err.Error())
+		}
+		filter.DateOfBirth = &dob
+	}
+
+	if req.AgeMin != nil && req.AgeMax != nil && *req.AgeMin > *req.AgeMax {
+		return filter, domain.NewCustomError("INVALID_FILTER", "age_min must not be greater than age_max", "")
+	}
+
+	var err error
+	if filter.CreatedFrom, filter.CreatedTo, err = parseDateRange(req.CreatedFrom, req.CreatedTo); err != nil {
+		return filter, domain.NewCustomError("INVALID_FILTER", "Invalid created date range", err.Error())
+	}
+	if filter.UpdatedFrom, filter.UpdatedTo, err = parseDateRange(req.UpdatedFrom, req.UpdatedTo); err != nil {
+		return filter, domain.NewCustomError("INVALID_FILTER", "Invalid updated date range", err.Error())
+	}
+	if filter.DeceasedFrom, filter.DeceasedTo, err = parseDateRange(req.DeceasedFrom, req.DeceasedTo); err != nil {
+		return filter, domain.NewCustomError("INVALID_FILTER", "Invalid date of death range", err.Error())
+	}
+
+	return filter, nil
+}
+
+// parseDateRange returns an inclusive start and exclusive end. A date-only
+// end covers that whole day.
+func parseDateRange(from, to string) (*time.Time, *time.Time, error) {
+	var start, end *time.Time
+
+	if from != "" {
+		t, _, err := validator.ParseISODate(from)
+		if err != nil {
+			return nil, nil, err
+		}
+		start = &t
+	}
+
+	if to != "" {
+		t, dateOnly, err := validator.ParseISODate(to)
+		if err != nil {
+			return nil, nil, err
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		end = &t
+	}
+
+	if start != nil && end != nil && !start.Before(*end) {
+		return nil, nil, fmt.Errorf("start must be before end")
+	}
+
+	return start, end, nil
+}
+
+func ToWebhookResponse(sub *domain.WebhookSubscription, withSecret bool) *WebhookResponse {
+	resp := &WebhookResponse{
+		ID:                  sub.ID,
+		URL:                 sub.URL,
+		Description:         sub.Description,
+		EventTypes:          sub.EventTypes,
+		ConsentType:         sub.ConsentType,
+		IsActive:            sub.IsActive,
+		ConsecutiveFailures: sub.ConsecutiveFailures,
+		DisabledAt:          sub.DisabledAt,
+		DisabledReason:      sub.DisabledReason,
+		CreatedAt:           sub.CreatedAt,
+		UpdatedAt:           sub.UpdatedAt,
+	}
+	if resp.EventTypes == nil {
+		resp.EventTypes = []string{}
+	}
+	if withSecret {
+		resp.Secret = sub.Secret
+	}
+	return resp
+}
+
+func ToWebhookDomain(req *CreateWebhookRequest) *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		URL:         req.URL,
+		Description: req.Description,
+		EventTypes:  req.EventTypes,
+		ConsentType: req.ConsentType,
+	}
+}
+
+func ToUpdateWebhookDomain(id string, req *UpdateWebhookRequest) *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		ID:          id,
+		URL:         req.URL,
+		Description: req.Description,
+		EventTypes:  req.EventTypes,
+		ConsentType: req.ConsentType,
+		IsActive:    *req.IsActive,
+	}
+}
+
+// ToImportJobResponse converts a job; reportURL is set when the job has
+// rejected rows.
+func ToImportJobResponse(job *domain.ImportJob, reportURL string) *ImportJobResponse {
+	resp := &ImportJobResponse{
+		ID:            job.ID,
+		FileName:      job.FileName,
+		Format:        job.Format,
+		Status:        job.Status,
+		DryRun:        job.DryRun,
+		Mapping:       job.Mapping,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		ValidRows:     job.ValidRows,
+		ImportedRows:  job.ImportedRows,
+		RejectedRows:  job.RejectedRows,
+		Error:         job.Error,
+		CreatedBy:     job.CreatedBy,
+		CreatedAt:     job.CreatedAt,
+		StartedAt:     job.StartedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+	switch {
+	case job.Status == domain.ImportCompleted:
+		resp.Progress = 100
+	case job.TotalRows > 0:
+		resp.Progress = math.Round(float64(job.ProcessedRows)*1000/float64(job.TotalRows)) / 10
+	}
+	if job.RejectedRows > 0 {
+		resp.ErrorReportURL = reportURL
+	}
+	return resp
+}
+
+func ToExportJobResponse(job *domain.ExportJob, downloadURL string) *ExportJobResponse {
+	resp := &ExportJobResponse{
+		ID:          job.ID,
+		Format:      job.Format,
+		Status:      job.Status,
+		Columns:     job.Filter.Fields,
+		RowCount:    job.RowCount,
+		FileSize:    job.FileSize,
+		Ruleset:     job.Ruleset,
+		ConsentType: job.ConsentType,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		StartedAt:   job.StartedAt,
+		FinishedAt:  job.FinishedAt,
+	}
+	if job.Ruleset != nil {
+		resp.SuppressedRows = &job.Suppressed
+	}
+	if job.Status == domain.ExportCompleted {
+		resp.DownloadURL = downloadURL
+	}
+	return resp
+}
+
+func ToDeidentRuleset(req *DeidentifiedExportRequest) domain.DeidentRuleset {
+	return domain.DeidentRuleset{
+		DateOfBirth:           req.DateOfBirth,
+		AgeBandYears:          req.AgeBandYears,
+		PostalDigits:          req.PostalDigits,
+		QuasiIdentifiers:      req.QuasiIdentifiers,
+		K:                     req.K,
+		MaxSuppressionPercent: req.MaxSuppressionPercent,
+	}
+}
+
+func ToConsentDomain(patientID string, req *CreateConsentRequest) *domain.Consent {
+	consent := &domain.Consent{
+		PatientID:           patientID,
+		Type:                req.Type,
+		Decision:            req.Decision,
+		Scope:               req.Scope,
+		GrantorName:         req.GrantorName,
+		GrantorRelationship: req.GrantorRelationship,
+		ValidUntil:          req.ValidUntil,
+		DocumentRef:         req.DocumentRef,
+	}
+	if req.ValidFrom != nil {
+		consent.ValidFrom = *req.ValidFrom
+	}
+	return consent
+}
+
+func ToConsentResponse(consent *domain.Consent, now time.Time) *ConsentResponse {
+	return &ConsentResponse{
+		ID:                  consent.ID,
+		PatientID:           consent.PatientID,
+		Type:                consent.Type,
+		Decision:            consent.Decision,
+		Status:              consent.Status(now),
+		Scope:               consent.Scope,
+		GrantorName:         consent.GrantorName,
+		GrantorRelationship: consent.GrantorRelationship,
+		ValidFrom:           consent.ValidFrom,
+		ValidUntil:          consent.ValidUntil,
+		DocumentRef:         consent.DocumentRef,
+		RevokedAt:           consent.RevokedAt,
+		RevokedBy:           consent.RevokedBy,
+		RevocationReason:    consent.RevocationReason,
+		CreatedBy:           consent.CreatedBy,
+		CreatedAt:           consent.CreatedAt,
+		UpdatedAt:           consent.UpdatedAt,
+	}
+}
+
+func ToQRTokenResponse(token *domain.QRToken, tokenString, url string, now time.Time) *QRTokenResponse {
+	return &QRTokenResponse{
+		ID:            token.ID,
+		PatientID:     token.PatientID,
+		Token:         tokenString,
+		URL:           url,
+		Status:        token.Status(now),
+		ExpiresAt:     token.ExpiresAt,
+		RevokedAt:     token.RevokedAt,
+		RevokedBy:     token.RevokedBy,
+		LastScannedAt: token.LastScannedAt,
+		ScanCount:     token.ScanCount,
+		CreatedBy:     token.CreatedBy,
+		CreatedAt:     token.CreatedAt,
+	}
+}
+
+func ToQRScanResponse(scan *domain.QRScan) *QRScanResponse {
+	return &QRScanResponse{
+		ID:        scan.ID,
+		Result:    scan.Result,
+		IP:        scan.IP,
+		UserAgent: scan.UserAgent,
+		ScannedAt: scan.ScannedAt,
+	}
+}
+
+func ToPublicPatientResponse(patient *domain.Patient) *PublicPatientResponse {
+	return &PublicPatientResponse{
+		MedicalRecordNo: patient.MedicalRecordNo,
+		FirstName:       patient.FirstName,
+		LastName:        patient.LastName,
+		Gender:          patient.Gender,
+	}
+}
+
+// ToDocumentDomain converts the upload form; the issued date was
+// validated as YYYY-MM-DD.
+func ToDocumentDomain(patientID string, req *UploadDocumentRequest) *domain.Document {
+	doc := &domain.Document{
+		PatientID:   patientID,
+		Type:        req.Type,
+		Description: req.Description,
+	}
+	if issued, err := time.Parse("2006-01-02", req.IssuedDate); err == nil {
+		doc.IssuedDate = &issued
+	}
+	return doc
+}
+
+func ToDocumentResponse(doc *domain.Document, downloadURL string, expires time.Time) *DocumentResponse {
+	resp := &DocumentResponse{
+		ID:                doc.ID,
+		PatientID:         doc.PatientID,
+		Type:              doc.Type,
+		Description:       doc.Description,
+		FileName:          doc.FileName,
+		ContentType:       doc.ContentType,
+		Size:              doc.Size,
+		SHA256:            doc.SHA256,
+		ScanStatus:        doc.ScanStatus,
+		UploadedBy:        doc.UploadedBy,
+		CreatedAt:         doc.CreatedAt,
+		DownloadURL:       downloadURL,
+		DownloadExpiresAt: expires,
+	}
+	if doc.IssuedDate != nil {
+		resp.IssuedDate = doc.IssuedDate.Format("2006-01-02")
+	}
+	return resp
+}
+
+// ToDeliveryResponse converts a delivery; detail adds the payload and the
+// attempt log.
+func ToDeliveryResponse(d *domain.WebhookDelivery, detail bool) *WebhookDeliveryResponse {
+	resp := &WebhookDeliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		AttemptCount:   d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	if d.Status == domain.DeliveryPending {
+		next := d.NextAttemptAt
+		resp.NextAttemptAt = &next
+	}
+
+	if detail {
+		resp.Payload = json.RawMessage(d.Payload)
+		for _, a := range d.Log {
+			resp.Attempts = append(resp.Attempts, &WebhookAttemptResponse{
+				AttemptedAt: a.AttemptedAt,
+				StatusCode:  a.StatusCode,
+				Error:       a.Error,
+				DurationMS:  a.Duration.Milliseconds(),
+			})
+		}
+	}
+	return resp
+}
+
+func ToChangeResponse(change *domain.PatientChange, token string) *ChangeResponse {
+	d := change.Data
+	patient := map[string]interface{}{
+		"medical_record_no": d.MedicalRecordNo,
+		"first_name":        d.FirstName,
+		"last_name":         d.LastName,
+		"date_of_birth":     d.DateOfBirth,
+		"gender":            d.Gender,
+		"city":              d.City,
+		"province":          d.Province,
+		"is_active":         d.IsActive,
+		"actor":             d.Actor,
+	}
+	// Deletes and merges only carry the record number
+	for field, value := range patient {
+		if value == "" {
+			delete(patient, field)
+		}
+	}
+
+	return &ChangeResponse{
+		ID:            change.PatientID,
+		Operation:     change.Operation,
+		Version:       change.Version,
+		Timestamp:     change.Timestamp,
+		Token:         token,
+		ChangedFields: d.ChangedFields,
+		MergedInto:    d.MergedInto,
+		Patient:       patient,
+	}
+}
@@ -0,0 +1,52 @@
+// CloudEvents envelope
+// internal/events/cloudevent.go
+package events
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+const (
+	SpecVersion = "1.0"
+
+	// ContentType is the structured-mode CloudEvents media type
+	ContentType = "application/cloudevents+json"
+
+	// TypePrefix namespaces outbox event types, e.g. hospital.patient.created
+	TypePrefix = "hospital."
+)
+
+// CloudEvent is a CloudEvents 1.0 event in structured JSON form.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Sequence        string          `json:"sequence,omitempty"` // sequence extension: outbox position
+	TenantID        string          `json:"tenantid,omitempty"` // tenantid extension: the patient's tenant
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// FromOutbox wraps an outbox event. The subject is the patient ID, which
+// publishers use as the ordering key.
+func FromOutbox(event *domain.OutboxEvent, source string) *CloudEvent {
+	return &CloudEvent{
+		SpecVersion:     SpecVersion,
+		ID:              event.EventID,
+		Source:          source,
+		Type:            TypePrefix + event.Type,
+		Subject:         event.PatientID,
+		Time:            event.OccurredAt.UTC(),
+		DataContentType: "application/json",
+		Sequence:        strconv.FormatInt(event.ID, 10),
+		TenantID:        event.TenantID,
+		Data:            event.Data,
+	}
+}
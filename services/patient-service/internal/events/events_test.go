@@ -0,0 +1,172 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+)
+
+// memoryOutbox implements repository.OutboxRepository.
+type memoryOutbox struct {
+	events    []*domain.OutboxEvent
+	published map[int64]bool
+}
+
+func (m *memoryOutbox) add(eventType, patientID string) {
+	event, _ := domain.NewOutboxEvent(eventType, domain.PatientEventData{ID: patientID})
+	event.ID = int64(len(m.events) + 1)
+	m.events = append(m.events, event)
+}
+
+func (m *memoryOutbox) Pending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	var pending []*domain.OutboxEvent
+	for _, e := range m.events {
+		if !m.published[e.ID] && len(pending) < limit {
+			copied := *e
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memoryOutbox) MarkPublished(ctx context.Context, ids []int64) error {
+	for _, id := range ids {
+		m.published[id] = true
+	}
+	return nil
+}
+
+func (m *memoryOutbox) MarkFailed(ctx context.Context, event *domain.OutboxEvent) error {
+	*m.events[event.ID-1] = *event
+	return nil
+}
+
+func (m *memoryOutbox) Prune(ctx context.Context, before time.Time, unpublished bool) (int64, error) {
+	return 0, nil
+}
+
+func (m *memoryOutbox) TryLock(ctx context.Context, resource string) (repository.Lease, error) {
+	return nil, nil
+}
+
+func subjects(events []*CloudEvent) string {
+	var s []string
+	for _, e := range events {
+		s = append(s, e.Subject+":"+e.Sequence)
+	}
+	return strings.Join(s, " ")
+}
+
+func TestRelayKeepsPerPatientOrder(t *testing.T) {
+	outbox := &memoryOutbox{published: make(map[int64]bool)}
+	outbox.add(domain.EventPatientCreated, "a")
+	outbox.add(domain.EventPatientCreated, "b")
+	outbox.add(domain.EventPatientUpdated, "a")
+	outbox.add(domain.EventPatientUpdated, "b")
+
+	publisher := NewMemoryPublisher()
+	publisher.FailNext("a", 1)
+
+	relay := NewRelay(outbox, publisher, "/test", time.Second, time.Hour)
+	ctx := context.Background()
+
+	n, err := relay.PublishPending(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n != 2 || subjects(publisher.Events()) != "b:2 b:4" {
+		t.Fatalf("Expected only b's events while a is failing, got %q", subjects(publisher.Events()))
+	}
+
+	failed := outbox.events[0]
+	if failed.Attempts != 1 || failed.LastError == "" || !failed.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("Expected the failure to be recorded, got %+v", failed)
+	}
+
+	// Not yet due: a stays blocked
+	relay.PublishPending(ctx)
+	if len(publisher.Events()) != 2 {
+		t.Fatal("Expected a to wait for its retry time")
+	}
+
+	failed.NextAttemptAt = time.Now()
+	relay.PublishPending(ctx)
+	if got := subjects(publisher.Events()); got != "b:2 b:4 a:1 a:3" {
+		t.Errorf("Expected a's events in order after the retry, got %q", got)
+	}
+}
+
+func TestFromOutbox(t *testing.T) {
+	patient := &domain.Patient{
+		ID:              "p1",
+		MedicalRecordNo: "MR1",
+		NIK:             "1234567890123456",
+		Phone:           "081234567890",
+		FirstName:       "Siti",
+		DateOfBirth:     time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		IsActive:        true,
+	}
+	event, err := domain.NewOutboxEvent(domain.EventPatientCreated, domain.NewPatientEventData(patient))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	event.ID = 42
+
+	ce := FromOutbox(event, "/patient-service")
+	body, _ := json.Marshal(ce)
+
+	var decoded map[string]interface{}
+	json.Unmarshal(body, &decoded)
+	for attr, want := range map[string]string{
+		"specversion": "1.0",
+		"type":        "hospital.patient.created",
+		"source":      "/patient-service",
+		"subject":     "p1",
+		"sequence":    "42",
+		"id":          event.EventID,
+	} {
+		if decoded[attr] != want {
+			t.Errorf("Expected %s %q, got %v", attr, want, decoded[attr])
+		}
+	}
+
+	data := decoded["data"].(map[string]interface{})
+	if data["date_of_birth"] != "1990-05-17" || data["medical_record_no"] != "MR1" {
+		t.Errorf("Unexpected data %v", data)
+	}
+	if strings.Contains(string(body), patient.NIK) || strings.Contains(string(body), patient.Phone) {
+		t.Error("Expected encrypted identifiers to be left out of the event")
+	}
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	var received *http.Request
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	publisher := NewWebhookPublisher(server.URL, map[string]string{"Authorization": "Bearer token"})
+	event := &CloudEvent{SpecVersion: SpecVersion, ID: "e1", Type: "hospital.patient.created"}
+
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if received.Header.Get("Content-Type") != ContentType || received.Header.Get("Authorization") != "Bearer token" {
+		t.Errorf("Unexpected headers %v", received.Header)
+	}
+
+	status = http.StatusInternalServerError
+	if err := publisher.Publish(context.Background(), event); err == nil {
+		t.Error("Expected an error for a 500 response")
+	}
+}
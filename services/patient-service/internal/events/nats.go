@@ -0,0 +1,54 @@
+// NATS JetStream event publisher
+// internal/events/nats.go
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes to JetStream subjects <prefix>.<event type>, e.g.
+// patients.patient.created. A stream must capture the subjects; the event
+// ID is the message ID so redeliveries within the stream's duplicate
+// window are dropped.
+type NATSPublisher struct {
+	conn   *nats.Conn
+	js     nats.JetStreamContext
+	prefix string
+}
+
+func NewNATSPublisher(url, subjectPrefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("patient-service"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &NATSPublisher{conn: conn, js: js, prefix: subjectPrefix}, nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event *CloudEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(p.prefix + "." + strings.TrimPrefix(event.Type, TypePrefix))
+	msg.Data = body
+	msg.Header.Set("Content-Type", ContentType)
+
+	_, err = p.js.PublishMsg(msg, nats.MsgId(event.ID), nats.Context(ctx))
+	return err
+}
+
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,86 @@
+// Event publishers
+// internal/events/publisher.go
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// EventPublisher delivers events to a broker or endpoint. Publish returns
+// nil only once the event is durably accepted; it may be called again for
+// an event that was already delivered, so consumers deduplicate by ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *CloudEvent) error
+	Close() error
+}
+
+// ErrPublishFailed is returned by MemoryPublisher for injected failures.
+var ErrPublishFailed = errors.New("events: publish failed")
+
+// MemoryPublisher records events in memory, for tests.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	events   []*CloudEvent
+	failures map[string]int
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{failures: make(map[string]int)}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event *CloudEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failures[event.Subject] > 0 {
+		p.failures[event.Subject]--
+		return ErrPublishFailed
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+// FailNext makes the next n publishes of events with the subject fail.
+func (p *MemoryPublisher) FailNext(subject string, n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures[subject] += n
+}
+
+// Events returns the published events in order.
+func (p *MemoryPublisher) Events() []*CloudEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*CloudEvent(nil), p.events...)
+}
+
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
+
+type fanout []EventPublisher
+
+// Fanout publishes each event to all publishers in order. It fails when
+// any of them fails, so a retry republishes to those that had succeeded.
+func Fanout(publishers ...EventPublisher) EventPublisher {
+	return fanout(publishers)
+}
+
+func (f fanout) Publish(ctx context.Context, event *CloudEvent) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f fanout) Close() error {
+	var errs []error
+	for _, p := range f {
+		errs = append(errs, p.Close())
+	}
+	return errors.Join(errs...)
+}
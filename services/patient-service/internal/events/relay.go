@@ -0,0 +1,162 @@
+// Outbox relay
+// internal/events/relay.go
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+)
+
+const (
+	relayLock      = "patient-service:outbox-relay"
+	relayBatchSize = 100
+	pruneInterval  = time.Hour
+
+	maxPublishBackoff = 5 * time.Minute
+)
+
+// Relay publishes outbox events. Delivery is at least once: an event is
+// marked published only after the publisher accepts it. Events of a patient
+// are published in outbox order; when one fails, that patient's later
+// events wait while other patients' events continue.
+//
+// One instance relays at a time, holding a database lock.
+type Relay struct {
+	outbox    repository.OutboxRepository
+	publisher EventPublisher // nil keeps events for the change feed only
+	source    string
+	interval  time.Duration
+	retention time.Duration
+}
+
+// NewRelay creates a relay polling the outbox every interval and deleting
+// events older than retention.
+func NewRelay(outbox repository.OutboxRepository, publisher EventPublisher, source string, interval, retention time.Duration) *Relay {
+	return &Relay{
+		outbox:    outbox,
+		publisher: publisher,
+		source:    source,
+		interval:  interval,
+		retention: retention,
+	}
+}
+
+// Run relays events until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var (
+		lease     repository.Lease
+		lastPrune time.Time
+	)
+	defer func() {
+		if lease != nil {
+			lease.Release()
+		}
+	}()
+
+	for {
+		if lease != nil && !lease.Valid(ctx) {
+			lease.Release()
+			lease = nil
+		}
+		if lease == nil {
+			var err error
+			if lease, err = r.outbox.TryLock(ctx, relayLock); err != nil && ctx.Err() == nil {
+				log.Printf("Outbox relay lock failed: %v", err)
+			}
+		}
+
+		if lease != nil {
+			for {
+				n, err := r.PublishPending(ctx)
+				if err != nil {
+					log.Printf("Outbox relay failed: %v", err)
+				}
+				if err != nil || n < relayBatchSize {
+					break
+				}
+			}
+
+			if time.Since(lastPrune) >= pruneInterval {
+				lastPrune = time.Now()
+				if n, err := r.outbox.Prune(ctx, time.Now().Add(-r.retention), r.publisher == nil); err != nil {
+					log.Printf("Outbox prune failed: %v", err)
+				} else if n > 0 {
+					log.Printf("Pruned %d outbox events", n)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publishes one batch of pending events and returns the
+// number of events that were published.
+func (r *Relay) PublishPending(ctx context.Context) (int, error) {
+	if r.publisher == nil {
+		return 0, nil
+	}
+
+	pending, err := r.outbox.Pending(ctx, relayBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		published []int64
+		blocked   = make(map[string]bool)
+		now       = time.Now()
+	)
+	for _, event := range pending {
+		if blocked[event.PatientID] {
+			continue
+		}
+		if event.NextAttemptAt.After(now) {
+			blocked[event.PatientID] = true
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, FromOutbox(event, r.source)); err != nil {
+			blocked[event.PatientID] = true
+			if err := r.markFailed(ctx, event, err); err != nil {
+				return len(published), err
+			}
+			continue
+		}
+		published = append(published, event.ID)
+	}
+
+	if err := r.outbox.MarkPublished(ctx, published); err != nil {
+		return 0, err
+	}
+	return len(published), nil
+}
+
+func (r *Relay) markFailed(ctx context.Context, event *domain.OutboxEvent, cause error) error {
+	event.Attempts++
+	event.LastError = cause.Error()
+	if len(event.LastError) > 1000 {
+		event.LastError = event.LastError[:1000]
+	}
+
+	delay := time.Second << uint(min(event.Attempts-1, 16))
+	if delay > maxPublishBackoff {
+		delay = maxPublishBackoff
+	}
+	event.NextAttemptAt = time.Now().Add(delay)
+
+	log.Printf("Publishing %s event %d for patient %s failed (attempt %d), retrying in %s: %v",
+		event.Type, event.ID, event.PatientID, event.Attempts, delay, cause)
+	return r.outbox.MarkFailed(ctx, event)
+}
@@ -0,0 +1,343 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"patient-service/internal/access"
+	"patient-service/internal/deident"
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+	"patient-service/pkg/filestore"
+)
+
+func testPatients() []*domain.Patient {
+	created := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	deceased := time.Date(2024, 6, 9, 23, 15, 0, 0, time.UTC)
+	return []*domain.Patient{
+		{
+			ID: "p1", MedicalRecordNo: "RM-1", NIK: "3171234567890001", FirstName: "Budi", LastName: "Santoso",
+			DateOfBirth: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC), Gender: "MALE", Phone: "081234567890",
+			IsActive: true, CreatedAt: created, UpdatedAt: created,
+		},
+		{
+			ID: "p2", MedicalRecordNo: "RM-2", NIK: "3171234567890002", FirstName: "Siti, Aminah",
+			DateOfBirth: time.Date(1985, 1, 2, 0, 0, 0, 0, time.UTC), Gender: "FEMALE", Phone: "081234567891",
+			CreatedAt: created, UpdatedAt: created, DeceasedAt: &deceased,
+		},
+	}
+}
+
+func write(t *testing.T, format string, columns []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, columns)
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	for _, patient := range testPatients() {
+		if err := w.Write(patient); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestColumns(t *testing.T) {
+	policy := access.DefaultPolicy()
+
+	got := Columns([]string{"id", "nik", "first_name", "allergies"}, access.RoleRegistration, policy)
+	if want := []string{"id", "nik", "first_name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	for _, column := range Columns(nil, access.RoleWard, policy) {
+		if !policy.CanRead(access.RoleWard, column) {
+			t.Errorf("Column %q is not readable by the ward role", column)
+		}
+	}
+	if len(Columns(nil, access.RoleAdmin, policy)) != len(domain.PatientFields) {
+		t.Error("Expected every field for admin")
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	out := write(t, domain.ExportFormatCSV, []string{"id", "first_name", "date_of_birth", "is_active", "created_at", "deceased_at"})
+
+	want := "id,first_name,date_of_birth,is_active,created_at,deceased_at\n" +
+		"p1,Budi,1990-05-17,true,2024-03-01T08:30:00Z,\n" +
+		"p2,\"Siti, Aminah\",1985-01-02,false,2024-03-01T08:30:00Z,2024-06-09T23:15:00Z\n"
+	if string(out) != want {
+		t.Errorf("Unexpected CSV:\n%s", out)
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	out := write(t, domain.ExportFormatNDJSON, []string{"id", "nik"})
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	var lines []map[string]interface{}
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Invalid line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	if want := map[string]interface{}{"id": "p2", "nik": "3171234567890002"}; !reflect.DeepEqual(lines[1], want) {
+		t.Errorf("Expected %v, got %v", want, lines[1])
+	}
+}
+
+type parquetPatient struct {
+	ID          string    `parquet:"id"`
+	LastName    string    `parquet:"last_name"`
+	DateOfBirth int32     `parquet:"date_of_birth,date"` // days since 1970-01-01
+	IsActive    bool      `parquet:"is_active"`
+	CreatedAt   time.Time `parquet:"created_at,timestamp(microsecond)"`
+	DeceasedAt  time.Time `parquet:"deceased_at,optional,timestamp(microsecond)"` // zero when null
+}
+
+func TestParquetWriter(t *testing.T) {
+	// Out of name order, to check values land in the right schema column
+	out := write(t, domain.ExportFormatParquet, []string{"last_name", "is_active", "id", "date_of_birth", "created_at", "deceased_at"})
+
+	rows, err := parquet.Read[parquetPatient](bytes.NewReader(out), int64(len(out)))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+
+	got := rows[0]
+	if got.ID != "p1" || got.LastName != "Santoso" || !got.IsActive || !got.DeceasedAt.IsZero() {
+		t.Errorf("Unexpected row: %+v", got)
+	}
+	if dob := time.Unix(int64(got.DateOfBirth)*86400, 0).UTC(); !dob.Equal(time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected date of birth %v", dob)
+	}
+	if !got.CreatedAt.Equal(time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected created_at %v", got.CreatedAt)
+	}
+	if rows[1].LastName != "" || rows[1].IsActive {
+		t.Errorf("Unexpected second row: %+v", rows[1])
+	}
+	if !rows[1].DeceasedAt.Equal(time.Date(2024, 6, 9, 23, 15, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected deceased_at %v", rows[1].DeceasedAt)
+	}
+}
+
+type memoryJobs struct {
+	repository.ExportRepository
+	job      *domain.ExportJob
+	claimed  bool
+	finished bool
+}
+
+func (m *memoryJobs) ClaimJob(ctx context.Context, lease time.Duration) (*domain.ExportJob, error) {
+	if m.claimed {
+		return nil, nil
+	}
+	m.claimed = true
+	m.job.Status = domain.ExportRunning
+	return m.job, nil
+}
+
+func (m *memoryJobs) FinishJob(ctx context.Context, job *domain.ExportJob) error {
+	m.finished = true
+	return nil
+}
+
+type memoryPatients struct {
+	repository.PatientRepository
+	filter domain.PatientFilter
+}
+
+func (m *memoryPatients) Scan(ctx context.Context, filter domain.PatientFilter, fn func(*domain.Patient) error) error {
+	m.filter = filter
+	for _, patient := range testPatients() {
+		if err := fn(patient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestWorkerWritesFile(t *testing.T) {
+	store, err := filestore.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	job := &domain.ExportJob{
+		ID:     "job1",
+		Format: domain.ExportFormatCSV,
+		Filter: domain.PatientFilter{Search: "budi", Fields: []string{"id", "nik"}},
+	}
+	jobs := &memoryJobs{job: job}
+	patients := &memoryPatients{}
+	worker := NewWorker(jobs, patients, nil, store, WorkerConfig{})
+
+	claimed, err := worker.ProcessNext(context.Background())
+	if !claimed || err != nil {
+		t.Fatalf("ProcessNext = %v, %v", claimed, err)
+	}
+	if !jobs.finished || job.Status != domain.ExportCompleted || job.RowCount != 2 {
+		t.Errorf("Unexpected job: %+v", job)
+	}
+	if patients.filter.Search != "budi" {
+		t.Errorf("Expected the job's filter to be scanned, got %+v", patients.filter)
+	}
+
+	file, size, err := store.Open(context.Background(), job.FileName())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer file.Close()
+	content, _ := io.ReadAll(file)
+	if size != job.FileSize || int64(len(content)) != size {
+		t.Errorf("Expected %d bytes, job has %d and file %d", len(content), job.FileSize, size)
+	}
+	if !strings.HasPrefix(string(content), "id,nik\np1,3171234567890001\n") {
+		t.Errorf("Unexpected file:\n%s", content)
+	}
+}
+
+// consents implements consent.ConsentChecker with a fixed set of
+// patients allowed.
+type consents map[string]bool
+
+func (c consents) Allowed(ctx context.Context, patientID, consentType string) (bool, error) {
+	return c[patientID], nil
+}
+
+func (c consents) AllowedAll(ctx context.Context, patientIDs []string, consentType string) (map[string]bool, error) {
+	allowed := make(map[string]bool)
+	for _, id := range patientIDs {
+		allowed[id] = c[id]
+	}
+	return allowed, nil
+}
+
+func TestWorkerChecksConsent(t *testing.T) {
+	store, _ := filestore.NewLocal(t.TempDir())
+	job := &domain.ExportJob{
+		ID:          "job1",
+		Format:      domain.ExportFormatCSV,
+		Filter:      domain.PatientFilter{Fields: []string{"id"}},
+		ConsentType: domain.ConsentFamilySharing,
+	}
+	jobs := &memoryJobs{job: job}
+	worker := NewWorker(jobs, &memoryPatients{}, consents{"p2": true}, store, WorkerConfig{})
+
+	if _, err := worker.ProcessNext(context.Background()); err != nil {
+		t.Fatalf("ProcessNext failed: %v", err)
+	}
+	if job.Status != domain.ExportCompleted || job.RowCount != 1 {
+		t.Fatalf("Unexpected job: %+v", job)
+	}
+
+	file, _, err := store.Open(context.Background(), job.FileName())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer file.Close()
+	content, _ := io.ReadAll(file)
+	if string(content) != "id\np2\n" {
+		t.Errorf("Expected only p2 exported, got:\n%s", content)
+	}
+}
+
+func TestWorkerWritesDeidentifiedFile(t *testing.T) {
+	store, _ := filestore.NewLocal(t.TempDir())
+	key := []byte("0123456789abcdef0123456789abcdef")
+	cfg := deident.Config{Key: key, Defaults: domain.DeidentRuleset{
+		DateOfBirth: deident.DOBYear, AgeBandYears: 5, TopAge: 90, PostalDigits: 3,
+		QuasiIdentifiers: []string{"gender"}, K: 2, MaxSuppressionPercent: 50,
+	}}
+	rules, err := cfg.Ruleset(domain.DeidentRuleset{}, time.Now())
+	if err != nil {
+		t.Fatalf("Ruleset failed: %v", err)
+	}
+
+	job := &domain.ExportJob{
+		ID:      "job1",
+		Format:  domain.ExportFormatCSV,
+		Filter:  domain.PatientFilter{Fields: deident.Columns(*rules)},
+		Ruleset: rules,
+	}
+	jobs := &memoryJobs{job: job}
+	patients := &memoryPatients{}
+	worker := NewWorker(jobs, patients, nil, store, WorkerConfig{PseudonymKey: key})
+
+	if _, err := worker.ProcessNext(context.Background()); err != nil {
+		t.Fatalf("ProcessNext failed: %v", err)
+	}
+	// Each test patient is the only one of its gender
+	if job.Status != domain.ExportFailed || job.Suppressed != 2 || job.RowCount != 0 {
+		t.Errorf("Expected the job failed on k-anonymity, got %+v", job)
+	}
+	if !reflect.DeepEqual(patients.filter.Fields, deident.SourceFields) {
+		t.Errorf("Expected only the source fields read, got %v", patients.filter.Fields)
+	}
+
+	// Gender is no longer a quasi-identifier
+	rules.QuasiIdentifiers = []string{"province"}
+	job.Status, job.Suppressed = domain.ExportPending, 0
+	jobs.claimed = false
+	if _, err := worker.ProcessNext(context.Background()); err != nil {
+		t.Fatalf("ProcessNext failed: %v", err)
+	}
+	if job.Status != domain.ExportCompleted || job.RowCount != 2 || job.Suppressed != 0 {
+		t.Fatalf("Unexpected job: %+v", job)
+	}
+
+	file, _, err := store.Open(context.Background(), job.FileName())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer file.Close()
+	content, _ := io.ReadAll(file)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if lines[0] != "pseudo_id,birth_year,gender,province,postal_region,blood_type" || len(lines) != 3 {
+		t.Fatalf("Unexpected file:\n%s", content)
+	}
+	if strings.Contains(string(content), "Budi") || strings.Contains(string(content), "3171234567890001") {
+		t.Errorf("Identifiers in file:\n%s", content)
+	}
+	if !strings.HasSuffix(lines[1], ",1990,MALE,,,") {
+		t.Errorf("Unexpected first row %q", lines[1])
+	}
+}
+
+func TestWorkerFailsUnknownFormat(t *testing.T) {
+	store, _ := filestore.NewLocal(t.TempDir())
+	job := &domain.ExportJob{ID: "job1", Format: "xml"}
+	jobs := &memoryJobs{job: job}
+	worker := NewWorker(jobs, &memoryPatients{}, nil, store, WorkerConfig{})
+
+	if _, err := worker.ProcessNext(context.Background()); err != nil {
+		t.Fatalf("ProcessNext failed: %v", err)
+	}
+	if job.Status != domain.ExportFailed || job.Error == "" {
+		t.Errorf("Expected a failed job with an error, got %+v", job)
+	}
+	if _, _, err := store.Open(context.Background(), job.FileName()); err != filestore.ErrNotFound {
+		t.Errorf("Expected no file left behind, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+// Parquet export writer
+// internal/export/parquet.go
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+)
+
+// parquetRowGroup is the number of rows buffered in memory before they
+// are written out as a row group.
+const parquetRowGroup = 10000
+
+// parquetWriter writes typed columns: date_of_birth as DATE, timestamps in
+// microseconds (UTC), is_active as BOOLEAN and the other fields as
+// strings. Every column but deceased_at is required; missing text is an
+// empty string.
+type parquetWriter struct {
+	w       *parquet.Writer
+	columns []string
+	index   []int // schema column of each export column
+	row     parquet.Row
+	rows    int
+}
+
+func parquetNode(column string) parquet.Node {
+	switch column {
+	case "date_of_birth":
+		return parquet.Date()
+	case "created_at", "updated_at":
+		return parquet.Timestamp(parquet.Microsecond)
+	case "deceased_at":
+		return parquet.Optional(parquet.Timestamp(parquet.Microsecond))
+	case "is_active":
+		return parquet.Leaf(parquet.BooleanType)
+	}
+	return parquet.String()
+}
+
+func newParquetWriter(w io.Writer, columns []string) *parquetWriter {
+	group := parquet.Group{}
+	for _, column := range columns {
+		group[column] = parquetNode(column)
+	}
+	schema := parquet.NewSchema("patient", group)
+
+	// Schema columns are ordered by name
+	position := make(map[string]int, len(columns))
+	for i, path := range schema.Columns() {
+		position[path[0]] = i
+	}
+	index := make([]int, len(columns))
+	for i, column := range columns {
+		index[i] = position[column]
+	}
+
+	return &parquetWriter{
+		w: parquet.NewWriter(w, schema,
+			parquet.Compression(&parquet.Snappy),
+			parquet.CreatedBy("patient-service", "", "")),
+		columns: columns,
+		index:   index,
+		row:     make(parquet.Row, len(columns)),
+	}
+}
+
+func (pw *parquetWriter) Write(patient *domain.Patient) error {
+	return pw.WriteValues(dto.ToPatientFieldMap(patient, pw.columns))
+}
+
+func (pw *parquetWriter) WriteValues(values map[string]interface{}) error {
+	for i, column := range pw.columns {
+		value, definition := parquetValue(column, values[column]), 0
+		if column == "deceased_at" && !value.IsNull() {
+			definition = 1
+		}
+		pw.row[pw.index[i]] = value.Level(0, definition, pw.index[i])
+	}
+	if _, err := pw.w.WriteRows([]parquet.Row{pw.row}); err != nil {
+		return err
+	}
+
+	pw.rows++
+	if pw.rows%parquetRowGroup == 0 {
+		return pw.w.Flush()
+	}
+	return nil
+}
+
+func (pw *parquetWriter) Close() error {
+	return pw.w.Close()
+}
+
+func parquetValue(column string, value interface{}) parquet.Value {
+	switch v := value.(type) {
+	case string:
+		return parquet.ByteArrayValue([]byte(v))
+	case bool:
+		return parquet.BooleanValue(v)
+	case time.Time:
+		if column == "date_of_birth" {
+			date := time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC)
+			return parquet.Int32Value(int32(date.Unix() / 86400))
+		}
+		return parquet.Int64Value(v.UnixMicro())
+	case *time.Time:
+		if v == nil {
+			return parquet.NullValue()
+		}
+		return parquet.Int64Value(v.UnixMicro())
+	}
+	return parquet.ByteArrayValue(nil)
+}
@@ -0,0 +1,247 @@
+// Export worker
+// internal/export/worker.go
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"patient-service/internal/consent"
+	"patient-service/internal/deident"
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+	"patient-service/internal/tenant"
+	"patient-service/pkg/filestore"
+)
+
+const (
+	pruneInterval = time.Hour
+
+	// progressEvery is how many rows are written between progress saves,
+	// which also extend the lease
+	progressEvery = 5000
+)
+
+// WorkerConfig configures a Worker.
+type WorkerConfig struct {
+	Interval  time.Duration // poll interval
+	Lease     time.Duration // how long a job is held without progress
+	Retention time.Duration // how long finished jobs and their files are kept
+
+	// PseudonymKey is the de-identification key of jobs with a ruleset
+	PseudonymKey []byte
+}
+
+// Worker writes export jobs to the file store. Each instance claims its own
+// jobs, so several may run at once. A job whose worker stopped is started
+// over once its lease expires.
+type Worker struct {
+	jobs     repository.ExportRepository
+	patients repository.PatientRepository
+	consents consent.ConsentChecker
+	store    filestore.Store
+	cfg      WorkerConfig
+	wake     chan struct{}
+}
+
+func NewWorker(jobs repository.ExportRepository, patients repository.PatientRepository, consents consent.ConsentChecker, store filestore.Store, cfg WorkerConfig) *Worker {
+	return &Worker{
+		jobs:     jobs,
+		patients: patients,
+		consents: consents,
+		store:    store,
+		cfg:      cfg,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Wake makes Run check for pending jobs now.
+func (w *Worker) Wake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes jobs until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+
+	for {
+		for {
+			claimed, err := w.ProcessNext(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Patient export failed: %v", err)
+			}
+			if err != nil || !claimed {
+				break
+			}
+		}
+
+		if time.Since(lastPrune) >= pruneInterval {
+			lastPrune = time.Now()
+			if n, err := w.prune(ctx); err != nil {
+				log.Printf("Export job prune failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Pruned %d export jobs", n)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+// ProcessNext claims a job and writes its file, reporting whether there was
+// one. A failed export is recorded on the job; only storage errors of the
+// job itself are returned.
+func (w *Worker) ProcessNext(ctx context.Context) (bool, error) {
+	job, err := w.jobs.ClaimJob(ctx, w.cfg.Lease)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	size, err := w.store.Put(ctx, job.FileName(), func(out io.Writer) error {
+		// Patients are read as the tenant that started the export
+		return w.write(tenant.NewContext(ctx, job.TenantID), job, out)
+	})
+	if err != nil && ctx.Err() != nil {
+		return true, err // resumed after the lease expires
+	}
+
+	var customErr *domain.CustomError
+	switch {
+	case errors.As(err, &customErr):
+		job.Status = domain.ExportFailed
+		job.Error = customErr.Message
+		if customErr.Details != "" {
+			job.Error += ": " + customErr.Details
+		}
+	case err != nil:
+		job.Status = domain.ExportFailed
+		job.Error = err.Error()
+	default:
+		job.Status = domain.ExportCompleted
+		job.FileSize = size
+	}
+
+	if err := w.jobs.FinishJob(ctx, job); err != nil {
+		return true, fmt.Errorf("export %s: %w", job.ID, err)
+	}
+	log.Printf("Export %s %s: %d rows, %d suppressed, %d bytes", job.ID, job.Status, job.RowCount, job.Suppressed, job.FileSize)
+	return true, nil
+}
+
+func (w *Worker) write(ctx context.Context, job *domain.ExportJob, out io.Writer) error {
+	writer, err := NewWriter(job.Format, out, job.Filter.Fields)
+	if err != nil {
+		return err
+	}
+	if job.Ruleset != nil {
+		return w.writeDeidentified(ctx, job, writer)
+	}
+
+	err = w.scan(ctx, job, job.Filter, func(patient *domain.Patient) error {
+		if err := writer.Write(patient); err != nil {
+			return err
+		}
+		job.RowCount++
+		if job.RowCount%progressEvery == 0 {
+			return w.jobs.SaveProgress(ctx, job, w.cfg.Lease)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// scan reads the patients of the filter, less those whose consent does
+// not allow the job's consent type.
+func (w *Worker) scan(ctx context.Context, job *domain.ExportJob, filter domain.PatientFilter, fn func(*domain.Patient) error) error {
+	if job.ConsentType == "" {
+		return w.patients.Scan(ctx, filter, fn)
+	}
+	add, flush := consent.Filter(ctx, w.consents, job.ConsentType, fn)
+	if err := w.patients.Scan(ctx, filter, add); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// writeDeidentified writes the job's patients de-identified under its
+// ruleset, less the rows k-anonymity suppresses.
+func (w *Worker) writeDeidentified(ctx context.Context, job *domain.ExportJob, writer Writer) error {
+	d, err := deident.New(*job.Ruleset, w.cfg.PseudonymKey)
+	if err != nil {
+		return err
+	}
+
+	filter := job.Filter
+	filter.Fields = deident.SourceFields
+
+	// Nothing is written until every patient was read; the lease is
+	// extended meanwhile
+	scanned := 0
+	scan := func(fn func(*domain.Patient) error) error {
+		return w.scan(ctx, job, filter, func(patient *domain.Patient) error {
+			if err := fn(patient); err != nil {
+				return err
+			}
+			scanned++
+			if scanned%progressEvery == 0 {
+				return w.jobs.SaveProgress(ctx, job, w.cfg.Lease)
+			}
+			return nil
+		})
+	}
+
+	values := make(map[string]interface{}, len(job.Filter.Fields))
+	result, err := d.Apply(scan, func(record []string) error {
+		for i, column := range job.Filter.Fields {
+			values[column] = record[i]
+		}
+		if err := writer.WriteValues(values); err != nil {
+			return err
+		}
+		job.RowCount++
+		if job.RowCount%progressEvery == 0 {
+			return w.jobs.SaveProgress(ctx, job, w.cfg.Lease)
+		}
+		return nil
+	})
+	job.Suppressed = result.Suppressed
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// prune deletes finished jobs past retention with their files.
+func (w *Worker) prune(ctx context.Context) (int, error) {
+	jobs, err := w.jobs.ListFinishedBefore(ctx, time.Now().Add(-w.cfg.Retention))
+	if err != nil {
+		return 0, err
+	}
+	for i, job := range jobs {
+		if err := w.store.Delete(ctx, job.FileName()); err != nil {
+			return i, err
+		}
+		if err := w.jobs.DeleteJob(ctx, job.ID); err != nil {
+			return i, err
+		}
+	}
+	return len(jobs), nil
+}
@@ -0,0 +1,139 @@
+// Export file writers
+// internal/export/writer.go
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"patient-service/internal/access"
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+)
+
+// Writer writes patients in an export format. Close completes the file;
+// it does not close the underlying writer.
+type Writer interface {
+	Write(patient *domain.Patient) error
+
+	// WriteValues writes a row given by column, for rows that are not
+	// patients as stored
+	WriteValues(values map[string]interface{}) error
+	Close() error
+}
+
+// NewWriter creates a writer of the given columns, see Columns.
+func NewWriter(format string, w io.Writer, columns []string) (Writer, error) {
+	switch format {
+	case domain.ExportFormatCSV:
+		return newCSVWriter(w, columns)
+	case domain.ExportFormatNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w), columns: columns}, nil
+	case domain.ExportFormatParquet:
+		return newParquetWriter(w, columns), nil
+	}
+	return nil, domain.NewCustomError("INVALID_FORMAT", "Format must be csv, ndjson or parquet", format)
+}
+
+// ContentType returns the media type of an export format.
+func ContentType(format string) string {
+	switch format {
+	case domain.ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case domain.ExportFormatNDJSON:
+		return "application/x-ndjson"
+	}
+	return "application/vnd.apache.parquet"
+}
+
+// Columns returns the exported columns: the requested fields, or all
+// patient fields when nil, less those the role may not read.
+func Columns(fields []string, role string, policy *access.Policy) []string {
+	if fields == nil {
+		fields = domain.PatientFields
+	}
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if policy.CanRead(role, field) {
+			columns = append(columns, field)
+		}
+	}
+	return columns
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []string
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+	if err := cw.w.Write(columns); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) Write(patient *domain.Patient) error {
+	return cw.WriteValues(dto.ToPatientFieldMap(patient, cw.columns))
+}
+
+func (cw *csvWriter) WriteValues(values map[string]interface{}) error {
+	for i, column := range cw.columns {
+		cw.record[i] = formatCSV(column, values[column])
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func formatCSV(column string, value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if column == "date_of_birth" {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v != nil {
+			return v.Format(time.RFC3339)
+		}
+	}
+	return ""
+}
+
+// ndjsonWriter writes a JSON object per line, as the list endpoint renders
+// patients.
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	columns []string
+}
+
+func (nw *ndjsonWriter) Write(patient *domain.Patient) error {
+	return nw.WriteValues(dto.ToPatientFieldMap(patient, nw.columns))
+}
+
+func (nw *ndjsonWriter) WriteValues(values map[string]interface{}) error {
+	line, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	nw.w.Write(line)
+	return nw.w.WriteByte('\n')
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nw.w.Flush()
+}
@@ -0,0 +1,105 @@
+// CapabilityStatement of the FHIR facade
+// internal/fhir/capability.go
+package fhir
+
+import "time"
+
+type CapabilityStatement struct {
+	ResourceType   string           `json:"resourceType"`
+	Status         string           `json:"status"`
+	Date           string           `json:"date"`
+	Kind           string           `json:"kind"`
+	Software       *Software        `json:"software,omitempty"`
+	Implementation *Implementation  `json:"implementation,omitempty"`
+	FHIRVersion    string           `json:"fhirVersion"`
+	Format         []string         `json:"format"`
+	Rest           []CapabilityRest `json:"rest"`
+}
+
+type Software struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type Implementation struct {
+	Description string `json:"description"`
+	URL         string `json:"url,omitempty"`
+}
+
+type CapabilityRest struct {
+	Mode     string               `json:"mode"`
+	Security *CapabilitySecurity  `json:"security,omitempty"`
+	Resource []CapabilityResource `json:"resource"`
+}
+
+type CapabilitySecurity struct {
+	Description string `json:"description"`
+}
+
+type CapabilityResource struct {
+	Type         string                  `json:"type"`
+	Profile      string                  `json:"profile,omitempty"`
+	Interaction  []CapabilityCode        `json:"interaction"`
+	UpdateCreate bool                    `json:"updateCreate"`
+	SearchParam  []CapabilitySearchParam `json:"searchParam,omitempty"`
+	Operation    []CapabilityOperation   `json:"operation,omitempty"`
+}
+
+type CapabilityCode struct {
+	Code string `json:"code"`
+}
+
+type CapabilitySearchParam struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Documentation string `json:"documentation,omitempty"`
+}
+
+type CapabilityOperation struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// NewCapabilityStatement describes the Patient interactions served at baseURL.
+func NewCapabilityStatement(software, version, baseURL string, mapper Mapper) *CapabilityStatement {
+	return &CapabilityStatement{
+		ResourceType: "CapabilityStatement",
+		Status:       "active",
+		Date:         time.Now().UTC().Format("2006-01-02"),
+		Kind:         "instance",
+		Software:     &Software{Name: software, Version: version},
+		Implementation: &Implementation{
+			Description: "Patient service FHIR R4 facade",
+			URL:         baseURL,
+		},
+		FHIRVersion: Version,
+		Format:      []string{"json", ContentType},
+		Rest: []CapabilityRest{{
+			Mode: "server",
+			Security: &CapabilitySecurity{
+				Description: "Bearer JWT in the Authorization header",
+			},
+			Resource: []CapabilityResource{{
+				Type:    "Patient",
+				Profile: "http://hl7.org/fhir/StructureDefinition/Patient",
+				Interaction: []CapabilityCode{
+					{Code: "read"}, {Code: "search-type"}, {Code: "create"}, {Code: "update"},
+				},
+				SearchParam: []CapabilitySearchParam{
+					{Name: "_id", Type: "token"},
+					{Name: "identifier", Type: "token", Documentation: "System " + SystemNIK + " (NIK) or " + mapper.MRNSystem + " (MRN)"},
+					{Name: "name", Type: "string", Documentation: "Contained in the given or family name, or the MRN"},
+					{Name: "family", Type: "string", Documentation: "A word of the family name starts with the value"},
+					{Name: "given", Type: "string", Documentation: "A given name starts with the value"},
+					{Name: "birthdate", Type: "date", Documentation: "Exact date only (YYYY-MM-DD or eqYYYY-MM-DD)"},
+					{Name: "gender", Type: "token"},
+					{Name: "_count", Type: "number"},
+				},
+				Operation: []CapabilityOperation{{
+					Name:       "match",
+					Definition: "http://hl7.org/fhir/OperationDefinition/Patient-match",
+				}},
+			}},
+		}},
+	}
+}
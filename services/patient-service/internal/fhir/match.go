@@ -0,0 +1,53 @@
+// Patient/$match scoring
+// internal/fhir/match.go
+package fhir
+
+import (
+	"patient-service/internal/domain"
+)
+
+// ExtensionMatchGrade is the search extension carrying the $match grade.
+const ExtensionMatchGrade = "http://hl7.org/fhir/StructureDefinition/match-grade"
+
+// Match grades, http://terminology.hl7.org/CodeSystem/match-grade
+const (
+	GradeCertain  = "certain"
+	GradeProbable = "probable"
+	GradePossible = "possible"
+)
+
+// MatchScore scores a candidate found for a $match input. nameScore is the
+// relevance of the candidate's name (0..1, from the search index) or 0 when
+// the candidate was found by identifier only. Candidates below the possible
+// threshold return an empty grade.
+func MatchScore(input, candidate *domain.Patient, nameScore float64) (float64, string) {
+	if input.NIK != "" && input.NIK == candidate.NIK {
+		return 1, GradeCertain
+	}
+	if input.MedicalRecordNo != "" && input.MedicalRecordNo == candidate.MedicalRecordNo {
+		return 1, GradeCertain
+	}
+
+	// Weights of the name, date of birth and gender
+	score := 0.6 * nameScore
+	if !input.DateOfBirth.IsZero() && sameDate(input, candidate) {
+		score += 0.3
+	}
+	if input.Gender != "" && input.Gender == candidate.Gender {
+		score += 0.1
+	}
+
+	switch {
+	case score >= 0.99:
+		return score, GradeCertain
+	case score >= 0.8:
+		return score, GradeProbable
+	case score >= 0.6:
+		return score, GradePossible
+	}
+	return score, ""
+}
+
+func sameDate(a, b *domain.Patient) bool {
+	return a.DateOfBirth.Format("2006-01-02") == b.DateOfBirth.Format("2006-01-02")
+}
@@ -0,0 +1,253 @@
+// Mapping between domain.Patient and the FHIR Patient resource
+// internal/fhir/patient.go
+package fhir
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+// Identifier systems registered for Indonesia (SATUSEHAT)
+const (
+	SystemNIK       = "https://fhir.kemkes.go.id/id/nik"
+	SystemIHSNumber = "https://fhir.kemkes.go.id/id/ihs-number"
+)
+
+const (
+	systemIdentifierType = "http://terminology.hl7.org/CodeSystem/v2-0203"
+	systemRelationship   = "http://terminology.hl7.org/CodeSystem/v2-0131"
+)
+
+// ErrInvalidResource is returned for resources that cannot be mapped.
+var ErrInvalidResource = errors.New("invalid resource")
+
+// Mapper converts patients to and from FHIR. MRNSystem is the identifier
+// system of this facility's medical record numbers.
+type Mapper struct {
+	MRNSystem string
+}
+
+// FromDomain maps a patient to a FHIR Patient resource. Allergies, chronic
+// conditions, blood type and insurance are separate resources in FHIR
+// (AllergyIntolerance, Condition, Observation, Coverage) and are not mapped.
+func (m Mapper) FromDomain(patient *domain.Patient) *Patient {
+	active := patient.IsActive
+	resource := &Patient{
+		ResourceType: "Patient",
+		ID:           patient.ID,
+		Active:       &active,
+		Gender:       strings.ToLower(patient.Gender),
+	}
+
+	if !patient.UpdatedAt.IsZero() {
+		resource.Meta = &Meta{LastUpdated: patient.UpdatedAt.UTC().Format(time.RFC3339)}
+	}
+
+	if patient.NIK != "" {
+		resource.Identifier = append(resource.Identifier, Identifier{
+			Use:    "official",
+			System: SystemNIK,
+			Value:  patient.NIK,
+		})
+	}
+	if patient.MedicalRecordNo != "" {
+		resource.Identifier = append(resource.Identifier, Identifier{
+			Use: "usual",
+			Type: &CodeableConcept{
+				Coding: []Coding{{System: systemIdentifierType, Code: "MR", Display: "Medical record number"}},
+			},
+			System: m.MRNSystem,
+			Value:  patient.MedicalRecordNo,
+		})
+	}
+
+	name := HumanName{
+		Use:    "official",
+		Text:   strings.TrimSpace(patient.FirstName + " " + patient.LastName),
+		Family: patient.LastName,
+	}
+	if patient.FirstName != "" {
+		name.Given = []string{patient.FirstName}
+	}
+	resource.Name = []HumanName{name}
+
+	if patient.Phone != "" {
+		resource.Telecom = append(resource.Telecom, ContactPoint{System: "phone", Value: patient.Phone, Use: "mobile"})
+	}
+	if patient.Email != "" {
+		resource.Telecom = append(resource.Telecom, ContactPoint{System: "email", Value: patient.Email, Use: "home"})
+	}
+
+	if !patient.DateOfBirth.IsZero() {
+		resource.BirthDate = patient.DateOfBirth.Format("2006-01-02")
+	}
+	if patient.IsDeceased() {
+		resource.Deceased = patient.DeceasedAt.Format(time.RFC3339)
+	}
+
+	if patient.Address != "" || patient.City != "" || patient.Province != "" || patient.PostalCode != "" {
+		address := Address{
+			Use:        "home",
+			City:       patient.City,
+			State:      patient.Province,
+			PostalCode: patient.PostalCode,
+			Country:    "ID",
+		}
+		if patient.Address != "" {
+			address.Line = []string{patient.Address}
+		}
+		resource.Address = []Address{address}
+	}
+
+	if patient.EmergencyContact != "" || patient.EmergencyPhone != "" {
+		contact := PatientContact{
+			Relationship: []CodeableConcept{{
+				Coding: []Coding{{System: systemRelationship, Code: "C", Display: "Emergency Contact"}},
+			}},
+		}
+		if patient.EmergencyContact != "" {
+			contact.Name = &HumanName{Text: patient.EmergencyContact}
+		}
+		if patient.EmergencyPhone != "" {
+			contact.Telecom = []ContactPoint{{System: "phone", Value: patient.EmergencyPhone}}
+		}
+		resource.Contact = []PatientContact{contact}
+	}
+
+	return resource
+}
+
+// ToDomain maps a FHIR Patient resource to a new patient.
+func (m Mapper) ToDomain(resource *Patient) (*domain.Patient, error) {
+	patient := &domain.Patient{IsActive: true}
+	if err := m.Apply(resource, patient); err != nil {
+		return nil, err
+	}
+
+	// The medical record number can only be supplied on create
+	for _, id := range resource.Identifier {
+		if id.System == m.MRNSystem && id.Value != "" {
+			patient.MedicalRecordNo = id.Value
+		}
+	}
+
+	return patient, nil
+}
+
+// Apply overwrites the fields of patient that the FHIR Patient resource
+// represents. Fields FHIR does not carry (see FromDomain) are kept.
+func (m Mapper) Apply(resource *Patient, patient *domain.Patient) error {
+	if resource.ResourceType != "Patient" {
+		return fmt.Errorf("%w: expected resourceType Patient, got %q", ErrInvalidResource, resource.ResourceType)
+	}
+
+	patient.NIK = ""
+	for _, id := range resource.Identifier {
+		if id.System == SystemNIK {
+			patient.NIK = id.Value
+		}
+	}
+
+	patient.FirstName, patient.LastName = "", ""
+	if name := preferredName(resource.Name); name != nil {
+		patient.FirstName = strings.Join(name.Given, " ")
+		patient.LastName = name.Family
+		if patient.FirstName == "" && patient.LastName == "" && name.Text != "" {
+			patient.FirstName, patient.LastName = splitName(name.Text)
+		}
+	}
+
+	switch resource.Gender {
+	case "male", "female":
+		patient.Gender = strings.ToUpper(resource.Gender)
+	case "":
+		patient.Gender = ""
+	default:
+		return fmt.Errorf("%w: gender must be male or female", ErrInvalidResource)
+	}
+
+	patient.DateOfBirth = time.Time{}
+	if resource.BirthDate != "" {
+		dob, err := time.Parse("2006-01-02", resource.BirthDate)
+		if err != nil {
+			return fmt.Errorf("%w: birthDate must be a full date (YYYY-MM-DD)", ErrInvalidResource)
+		}
+		patient.DateOfBirth = dob
+	}
+
+	patient.Phone = firstTelecom(resource.Telecom, "phone")
+	patient.Email = firstTelecom(resource.Telecom, "email")
+
+	patient.Address, patient.City, patient.Province, patient.PostalCode = "", "", "", ""
+	if address := preferredAddress(resource.Address); address != nil {
+		patient.Address = strings.Join(address.Line, ", ")
+		if patient.Address == "" {
+			patient.Address = address.Text
+		}
+		patient.City = address.City
+		patient.Province = address.State
+		patient.PostalCode = address.PostalCode
+	}
+
+	patient.EmergencyContact, patient.EmergencyPhone = "", ""
+	if len(resource.Contact) > 0 {
+		contact := resource.Contact[0]
+		if contact.Name != nil {
+			patient.EmergencyContact = contact.Name.Text
+			if patient.EmergencyContact == "" {
+				patient.EmergencyContact = strings.TrimSpace(strings.Join(contact.Name.Given, " ") + " " + contact.Name.Family)
+			}
+		}
+		patient.EmergencyPhone = firstTelecom(contact.Telecom, "phone")
+	}
+
+	return nil
+}
+
+// preferredName returns the official name, else the first one.
+func preferredName(names []HumanName) *HumanName {
+	for i := range names {
+		if names[i].Use == "official" {
+			return &names[i]
+		}
+	}
+	if len(names) > 0 {
+		return &names[0]
+	}
+	return nil
+}
+
+// preferredAddress returns the home address, else the first one.
+func preferredAddress(addresses []Address) *Address {
+	for i := range addresses {
+		if addresses[i].Use == "home" {
+			return &addresses[i]
+		}
+	}
+	if len(addresses) > 0 {
+		return &addresses[0]
+	}
+	return nil
+}
+
+func firstTelecom(telecom []ContactPoint, system string) string {
+	for _, t := range telecom {
+		if t.System == system && t.Value != "" {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// splitName splits a full name into first name and the last word.
+func splitName(text string) (string, string) {
+	words := strings.Fields(text)
+	if len(words) < 2 {
+		return text, ""
+	}
+	return strings.Join(words[:len(words)-1], " "), words[len(words)-1]
+}
@@ -0,0 +1,111 @@
+package fhir
+
+import (
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+func testPatient() *domain.Patient {
+	return &domain.Patient{
+		ID:               "p1",
+		MedicalRecordNo:  "MR202401010001",
+		NIK:              "3171234567890001",
+		FirstName:        "Siti",
+		LastName:         "Aminah",
+		DateOfBirth:      time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Gender:           "FEMALE",
+		BloodType:        "O+",
+		Phone:            "081234567890",
+		Email:            "siti@example.com",
+		Address:          "Jl. Sudirman No. 1",
+		City:             "Jakarta",
+		Province:         "DKI Jakarta",
+		PostalCode:       "10220",
+		EmergencyContact: "Budi",
+		EmergencyPhone:   "081298765432",
+		Allergies:        "Penicillin",
+		IsActive:         true,
+	}
+}
+
+func TestPatientRoundTrip(t *testing.T) {
+	m := Mapper{MRNSystem: "urn:test:mrn"}
+	original := testPatient()
+
+	resource := m.FromDomain(original)
+	if resource.Gender != "female" || resource.BirthDate != "1990-05-17" {
+		t.Errorf("Unexpected gender or birthDate: %q, %q", resource.Gender, resource.BirthDate)
+	}
+	if len(resource.Identifier) != 2 || resource.Identifier[0].System != SystemNIK || resource.Identifier[1].System != "urn:test:mrn" {
+		t.Fatalf("Expected NIK and MRN identifiers, got %+v", resource.Identifier)
+	}
+
+	patient, err := m.ToDomain(resource)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Fields not represented in FHIR are not carried
+	original.ID, original.BloodType, original.Allergies = "", "", ""
+	if *patient != *original {
+		t.Errorf("Round trip mismatch:\n got %+v\nwant %+v", patient, original)
+	}
+}
+
+func TestApplyKeepsUnmappedFields(t *testing.T) {
+	m := Mapper{MRNSystem: "urn:test:mrn"}
+	patient := testPatient()
+
+	resource := m.FromDomain(patient)
+	resource.Telecom = nil
+	resource.Identifier[1].Value = "OTHER"
+
+	if err := m.Apply(resource, patient); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if patient.Phone != "" || patient.Email != "" {
+		t.Error("Expected telecom to be cleared")
+	}
+	if patient.MedicalRecordNo != "MR202401010001" || patient.Allergies != "Penicillin" {
+		t.Error("Expected medical record number and allergies to be kept")
+	}
+}
+
+func TestToDomainRejectsUnsupportedValues(t *testing.T) {
+	m := Mapper{}
+
+	for _, resource := range []*Patient{
+		{ResourceType: "Observation"},
+		{ResourceType: "Patient", Gender: "unknown"},
+		{ResourceType: "Patient", BirthDate: "1990"},
+	} {
+		if _, err := m.ToDomain(resource); err == nil {
+			t.Errorf("Expected error for %+v", resource)
+		}
+	}
+}
+
+func TestMatchScore(t *testing.T) {
+	input := testPatient()
+	input.NIK, input.MedicalRecordNo = "", ""
+
+	candidate := testPatient()
+
+	if _, grade := MatchScore(&domain.Patient{NIK: candidate.NIK}, candidate, 0); grade != GradeCertain {
+		t.Errorf("Expected NIK match to be certain, got %q", grade)
+	}
+	if _, grade := MatchScore(input, candidate, 1); grade != GradeCertain {
+		t.Errorf("Expected exact name, birth date and gender to be certain, got %q", grade)
+	}
+	if _, grade := MatchScore(input, candidate, 0.75); grade != GradeProbable {
+		t.Errorf("Expected close name with birth date to be probable, got %q", grade)
+	}
+
+	input.DateOfBirth = input.DateOfBirth.AddDate(1, 0, 0)
+	if _, grade := MatchScore(input, candidate, 0.5); grade != "" {
+		t.Errorf("Expected weak name only match to be dropped, got %q", grade)
+	}
+}
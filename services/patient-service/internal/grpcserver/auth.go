@@ -0,0 +1,97 @@
+// JWT authentication interceptors
+// internal/grpcserver/auth.go
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"patient-service/internal/middleware"
+	"patient-service/internal/tenant"
+)
+
+type claimsKey struct{}
+
+// ClaimsFromContext returns the claims of the authenticated caller.
+func ClaimsFromContext(ctx context.Context) (*middleware.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*middleware.Claims)
+	return claims, ok
+}
+
+// publicMethod reports whether a method is served without a token: the
+// health checking protocol and server reflection.
+func publicMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/grpc.health.v1.Health/") ||
+		strings.HasPrefix(fullMethod, "/grpc.reflection.")
+}
+
+func authenticate(ctx context.Context, secret string, tenants *tenant.Registry) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization token required")
+	}
+
+	tokenParts := strings.Split(values[0], " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		return nil, status.Error(codes.Unauthenticated, "invalid token format")
+	}
+
+	claims, err := middleware.ParseToken(tokenParts[1], secret)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+
+	tenantID, err := middleware.ResolveTenant(claims, tenants)
+	if err == middleware.ErrNoTenant {
+		return nil, status.Error(codes.Unauthenticated, "token has no tenant")
+	}
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, "token acts for an unknown tenant")
+	}
+	ctx = tenant.NewContext(ctx, tenantID)
+	return context.WithValue(ctx, claimsKey{}, claims), nil
+}
+
+// UnaryAuth validates the bearer token in the "authorization" metadata,
+// like middleware.JWTAuth does for the REST API.
+func UnaryAuth(secret string, tenants *tenant.Registry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if publicMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, secret, tenants)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth is UnaryAuth for streaming calls.
+func StreamAuth(secret string, tenants *tenant.Registry) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if publicMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), secret, tenants)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,168 @@
+// Conversion between the domain model and protobuf messages
+// internal/grpcserver/convert.go
+package grpcserver
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	patientv1 "patient-service/pkg/api/patient/v1"
+)
+
+const dateLayout = "2006-01-02"
+
+func toProtoGender(gender string) patientv1.Gender {
+	return patientv1.Gender(patientv1.Gender_value[gender])
+}
+
+func fromProtoGender(gender patientv1.Gender) string {
+	if gender == patientv1.Gender_GENDER_UNSPECIFIED {
+		return ""
+	}
+	return gender.String()
+}
+
+func toProtoPatient(patient *domain.Patient) *patientv1.Patient {
+	return &patientv1.Patient{
+		Id:                patient.ID,
+		MedicalRecordNo:   patient.MedicalRecordNo,
+		Nik:               patient.NIK,
+		FirstName:         patient.FirstName,
+		LastName:          patient.LastName,
+		DateOfBirth:       patient.DateOfBirth.Format(dateLayout),
+		Gender:            toProtoGender(patient.Gender),
+		BloodType:         patient.BloodType,
+		Phone:             patient.Phone,
+		Email:             patient.Email,
+		Address:           patient.Address,
+		City:              patient.City,
+		Province:          patient.Province,
+		PostalCode:        patient.PostalCode,
+		EmergencyContact:  patient.EmergencyContact,
+		EmergencyPhone:    patient.EmergencyPhone,
+		InsuranceProvider: patient.InsuranceProvider,
+		InsuranceNumber:   patient.InsuranceNumber,
+		Allergies:         patient.Allergies,
+		ChronicConditions: patient.ChronicConditions,
+		IsActive:          patient.IsActive,
+		CreatedAt:         timestamppb.New(patient.CreatedAt),
+		UpdatedAt:         timestamppb.New(patient.UpdatedAt),
+	}
+}
+
+func toProtoPatients(patients []*domain.Patient) []*patientv1.Patient {
+	result := make([]*patientv1.Patient, len(patients))
+	for i, patient := range patients {
+		result[i] = toProtoPatient(patient)
+	}
+	return result
+}
+
+// parseDateOfBirth parses a YYYY-MM-DD date; an empty date is left for
+// validation to reject.
+func parseDateOfBirth(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	dob, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, domain.NewCustomError("INVALID_DOB", "Date of birth must be YYYY-MM-DD", err.Error())
+	}
+	return dob, nil
+}
+
+// toCreateRequest maps a patient message to the REST request so both APIs
+// share validation rules.
+func toCreateRequest(p *patientv1.Patient) (*dto.CreatePatientRequest, error) {
+	dob, err := parseDateOfBirth(p.GetDateOfBirth())
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CreatePatientRequest{
+		NIK:               p.GetNik(),
+		FirstName:         p.GetFirstName(),
+		LastName:          p.GetLastName(),
+		DateOfBirth:       dob,
+		Gender:            fromProtoGender(p.GetGender()),
+		BloodType:         p.GetBloodType(),
+		Phone:             p.GetPhone(),
+		Email:             p.GetEmail(),
+		Address:           p.GetAddress(),
+		City:              p.GetCity(),
+		Province:          p.GetProvince(),
+		PostalCode:        p.GetPostalCode(),
+		EmergencyContact:  p.GetEmergencyContact(),
+		EmergencyPhone:    p.GetEmergencyPhone(),
+		InsuranceProvider: p.GetInsuranceProvider(),
+		InsuranceNumber:   p.GetInsuranceNumber(),
+		Allergies:         p.GetAllergies(),
+		ChronicConditions: p.GetChronicConditions(),
+	}, nil
+}
+
+func toUpdateRequest(p *patientv1.Patient) (*dto.UpdatePatientRequest, error) {
+	req, err := toCreateRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	update := dto.UpdatePatientRequest(*req)
+	return &update, nil
+}
+
+func toListRequest(req *patientv1.ListPatientsRequest) *dto.ListPatientsRequest {
+	list := &dto.ListPatientsRequest{
+		Search:   req.GetSearch(),
+		City:     strings.Join(req.GetCities(), ","),
+		Province: strings.Join(req.GetProvinces(), ","),
+		IsActive: req.IsActive,
+		Page:     int(req.GetPage()),
+		Limit:    int(req.GetLimit()),
+		Sort:     req.GetSort(),
+		Order:    req.GetOrder(),
+		Cursor:   req.GetPageToken(),
+	}
+	if list.Page == 0 {
+		list.Page = 1
+	}
+	if list.Limit == 0 {
+		list.Limit = 10
+	}
+	return list
+}
+
+// toProtoChange renders a change with the summary fields the role may
+// read, as the REST change feed does.
+func (s *Server) toProtoChange(role string, change *domain.PatientChange) *patientv1.PatientChange {
+	resp := dto.ToChangeResponse(change, domain.ChangeToken(change.Sequence))
+	s.policy.Redact(role, resp.Patient)
+
+	str := func(field string) string {
+		value, _ := resp.Patient[field].(string)
+		return value
+	}
+	isActive, _ := resp.Patient["is_active"].(bool)
+
+	return &patientv1.PatientChange{
+		Id:            resp.ID,
+		Operation:     resp.Operation,
+		Version:       resp.Version,
+		Timestamp:     timestamppb.New(resp.Timestamp),
+		Token:         resp.Token,
+		ChangedFields: resp.ChangedFields,
+		MergedInto:    resp.MergedInto,
+		Patient: &patientv1.PatientSummary{
+			MedicalRecordNo: str("medical_record_no"),
+			FirstName:       str("first_name"),
+			LastName:        str("last_name"),
+			DateOfBirth:     str("date_of_birth"),
+			Gender:          toProtoGender(str("gender")),
+			City:            str("city"),
+			Province:        str("province"),
+			IsActive:        isActive,
+		},
+	}
+}
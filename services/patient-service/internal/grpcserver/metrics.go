@@ -0,0 +1,62 @@
+// Prometheus metrics for gRPC calls
+// internal/grpcserver/metrics.go
+package grpcserver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of gRPC calls completed on the server",
+		},
+		[]string{"grpc_service", "grpc_method", "grpc_code"},
+	)
+
+	grpcRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Duration of gRPC calls in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"grpc_service", "grpc_method"},
+	)
+)
+
+func observe(fullMethod string, start time.Time, err error) {
+	// "/hospital.patient.v1.PatientService/GetPatient"
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	code := status.Code(err).String()
+
+	grpcRequestsTotal.WithLabelValues(service, method, code).Inc()
+	grpcRequestDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+}
+
+// UnaryMetrics records the count and duration of unary calls.
+func UnaryMetrics() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamMetrics records the count and duration of streaming calls.
+func StreamMetrics() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observe(info.FullMethod, start, err)
+		return err
+	}
+}
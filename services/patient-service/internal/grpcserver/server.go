@@ -0,0 +1,265 @@
+// gRPC PatientService
+// internal/grpcserver/server.go
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"patient-service/internal/access"
+	"patient-service/internal/changes"
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/internal/service"
+	"patient-service/internal/tenant"
+	patientv1 "patient-service/pkg/api/patient/v1"
+)
+
+const watchBatch = 500
+
+// Server implements the gRPC PatientService on the same services as the
+// REST API.
+type Server struct {
+	patientv1.UnimplementedPatientServiceServer
+
+	patientService service.PatientService
+	changeService  service.ChangeService
+	hub            *changes.Hub
+	policy         *access.Policy
+	validator      *validator.Validate
+}
+
+func NewServer(patientService service.PatientService, changeService service.ChangeService, hub *changes.Hub, policy *access.Policy, validator *validator.Validate) *Server {
+	return &Server{
+		patientService: patientService,
+		changeService:  changeService,
+		hub:            hub,
+		policy:         policy,
+		validator:      validator,
+	}
+}
+
+// NewGRPCServer returns a gRPC server with the PatientService, the health
+// checking protocol and server reflection registered. Every call is
+// counted in Prometheus; all but health checks and reflection need a JWT
+// acting for one of tenants.
+func NewGRPCServer(srv *Server, jwtSecret string, tenants *tenant.Registry) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryMetrics(), UnaryAuth(jwtSecret, tenants)),
+		grpc.ChainStreamInterceptor(StreamMetrics(), StreamAuth(jwtSecret, tenants)),
+	)
+
+	patientv1.RegisterPatientServiceServer(server, srv)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(patientv1.PatientService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server
+}
+
+func (s *Server) GetPatient(ctx context.Context, req *patientv1.GetPatientRequest) (*patientv1.Patient, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "patient ID is required")
+	}
+	patient, err := s.patientService.GetPatient(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoPatient(patient), nil
+}
+
+func (s *Server) GetPatientByNIK(ctx context.Context, req *patientv1.GetPatientByNIKRequest) (*patientv1.Patient, error) {
+	if req.GetNik() == "" {
+		return nil, status.Error(codes.InvalidArgument, "NIK is required")
+	}
+	patient, err := s.patientService.GetPatientByNIK(ctx, req.GetNik())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoPatient(patient), nil
+}
+
+func (s *Server) GetPatientByMRN(ctx context.Context, req *patientv1.GetPatientByMRNRequest) (*patientv1.Patient, error) {
+	if req.GetMedicalRecordNo() == "" {
+		return nil, status.Error(codes.InvalidArgument, "medical record number is required")
+	}
+	patient, err := s.patientService.GetPatientByMedicalRecordNo(ctx, req.GetMedicalRecordNo())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoPatient(patient), nil
+}
+
+func (s *Server) BatchGetPatients(ctx context.Context, req *patientv1.BatchGetPatientsRequest) (*patientv1.BatchGetPatientsResponse, error) {
+	patients, err := s.patientService.GetPatientsByKeys(ctx, domain.KeyID, req.GetIds(), nil)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	found := make(map[string]bool, len(patients))
+	for _, patient := range patients {
+		found[patient.ID] = true
+	}
+	resp := &patientv1.BatchGetPatientsResponse{Patients: toProtoPatients(patients)}
+	for _, id := range req.GetIds() {
+		if !found[id] {
+			resp.NotFoundIds = append(resp.NotFoundIds, id)
+			found[id] = true
+		}
+	}
+	return resp, nil
+}
+
+func (s *Server) ListPatients(ctx context.Context, req *patientv1.ListPatientsRequest) (*patientv1.ListPatientsResponse, error) {
+	listReq := toListRequest(req)
+	if err := s.validator.Struct(listReq); err != nil {
+		return nil, toStatus(err)
+	}
+
+	filter, err := dto.ToPatientFilter(listReq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if listReq.Cursor != "" {
+		cursor, err := domain.DecodeCursor(listReq.Cursor)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		filter.Cursor = cursor
+	}
+
+	page, err := s.patientService.ListPatients(ctx, filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &patientv1.ListPatientsResponse{
+		Patients:      toProtoPatients(page.Patients),
+		NextPageToken: page.NextCursor,
+	}
+	if page.Total != nil {
+		resp.Total = int32(*page.Total)
+	}
+	return resp, nil
+}
+
+func (s *Server) CreatePatient(ctx context.Context, req *patientv1.CreatePatientRequest) (*patientv1.Patient, error) {
+	createReq, err := toCreateRequest(req.GetPatient())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := s.validator.Struct(createReq); err != nil {
+		return nil, toStatus(err)
+	}
+
+	claims, _ := ClaimsFromContext(ctx)
+	patient := dto.ToPatientDomain(createReq)
+	if claims != nil {
+		patient.CreatedBy = claims.UserID
+		patient.UpdatedBy = claims.UserID
+	}
+
+	created, err := s.patientService.CreatePatient(ctx, patient)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoPatient(created), nil
+}
+
+func (s *Server) UpdatePatient(ctx context.Context, req *patientv1.UpdatePatientRequest) (*patientv1.Patient, error) {
+	if req.GetPatient().GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "patient ID is required")
+	}
+	updateReq, err := toUpdateRequest(req.GetPatient())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := s.validator.Struct(updateReq); err != nil {
+		return nil, toStatus(err)
+	}
+
+	claims, _ := ClaimsFromContext(ctx)
+	patient := dto.ToUpdatePatientDomain(req.GetPatient().GetId(), updateReq)
+	if claims != nil {
+		patient.UpdatedBy = claims.UserID
+	}
+
+	updated, err := s.patientService.UpdatePatient(ctx, patient)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoPatient(updated), nil
+}
+
+// WatchPatients streams changes until the client cancels or the server
+// shuts down.
+func (s *Server) WatchPatients(req *patientv1.WatchPatientsRequest, stream patientv1.PatientService_WatchPatientsServer) error {
+	ctx := stream.Context()
+
+	page, err := s.changeService.ListChanges(ctx, req.GetSince(), watchBatch)
+	if err != nil {
+		return toStatus(err)
+	}
+
+	var role string
+	if claims, ok := ClaimsFromContext(ctx); ok {
+		role = claims.Role
+	}
+
+	send := func(batch []*domain.PatientChange) error {
+		for _, change := range batch {
+			if err := stream.Send(s.toProtoChange(role, change)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Transport keepalives stand in for SSE heartbeats
+	err = s.hub.Follow(ctx, s.changeService, page, send, 0, nil)
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, "watch cancelled")
+	}
+	if err != nil {
+		return toStatus(err)
+	}
+	return nil
+}
+
+// toStatus maps service errors to gRPC status codes along the lines of the
+// REST handlers' HTTP statuses.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var customErr *domain.CustomError
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.Is(err, domain.ErrPatientNotFound):
+		return status.Error(codes.NotFound, "patient not found")
+	case errors.Is(err, domain.ErrChangesExpired):
+		return status.Error(codes.OutOfRange, "token is older than the retained change feed; start from a new token")
+	case errors.Is(err, domain.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &validationErrs):
+		return status.Error(codes.InvalidArgument, validationErrs.Error())
+	case errors.As(err, &customErr):
+		if customErr.Code == "PATIENT_EXISTS" || customErr.Code == "NIK_EXISTS" {
+			return status.Error(codes.AlreadyExists, customErr.Message)
+		}
+		return status.Errorf(codes.InvalidArgument, "%s: %s", customErr.Code, customErr.Message)
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,224 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"patient-service/internal/access"
+	"patient-service/internal/domain"
+	"patient-service/internal/middleware"
+	"patient-service/internal/service"
+	"patient-service/internal/tenant"
+	patientv1 "patient-service/pkg/api/patient/v1"
+	"patient-service/pkg/validator"
+)
+
+const testSecret = "test-secret"
+
+// fakePatientService serves the patients it holds; calls the tests do not
+// make panic on the nil embedded interface.
+type fakePatientService struct {
+	service.PatientService
+	patients map[string]*domain.Patient
+	created  *domain.Patient
+	tenantID string // of the last GetPatient
+}
+
+func (f *fakePatientService) GetPatient(ctx context.Context, id string) (*domain.Patient, error) {
+	f.tenantID, _ = tenant.FromContext(ctx)
+	if patient, ok := f.patients[id]; ok {
+		return patient, nil
+	}
+	return nil, domain.ErrPatientNotFound
+}
+
+func (f *fakePatientService) GetPatientsByKeys(ctx context.Context, key domain.PatientKey, ids []string, fields []string) ([]*domain.Patient, error) {
+	var patients []*domain.Patient
+	for _, id := range ids {
+		if patient, ok := f.patients[id]; ok {
+			patients = append(patients, patient)
+		}
+	}
+	return patients, nil
+}
+
+func (f *fakePatientService) CreatePatient(ctx context.Context, patient *domain.Patient) (*domain.Patient, error) {
+	f.created = patient
+	patient.ID = "new"
+	return patient, nil
+}
+
+func startServer(t *testing.T, patients *fakePatientService) *grpc.ClientConn {
+	t.Helper()
+
+	tenants, err := tenant.NewRegistry(tenant.Default, &tenant.Tenant{ID: "north"})
+	if err != nil {
+		t.Fatalf("NewRegistry failed: %v", err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	srv := NewGRPCServer(NewServer(patients, nil, nil, access.DefaultPolicy(), validator.New()), testSecret, tenants)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func withToken(t *testing.T, userID string) context.Context {
+	t.Helper()
+	token, err := middleware.GenerateToken(userID, "tester", access.RoleDoctor, testSecret, 1)
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func testPatients() *fakePatientService {
+	return &fakePatientService{patients: map[string]*domain.Patient{
+		"p1": {
+			ID:          "p1",
+			NIK:         "3171234567890123",
+			FirstName:   "Budi",
+			DateOfBirth: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+			Gender:      "MALE",
+			IsActive:    true,
+		},
+	}}
+}
+
+func TestRequiresToken(t *testing.T) {
+	client := patientv1.NewPatientServiceClient(startServer(t, testPatients()))
+
+	_, err := client.GetPatient(context.Background(), &patientv1.GetPatientRequest{Id: "p1"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without a token, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-jwt")
+	_, err = client.GetPatient(ctx, &patientv1.GetPatientRequest{Id: "p1"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated with a bad token, got %v", err)
+	}
+}
+
+func TestResolvesTenant(t *testing.T) {
+	patients := testPatients()
+	client := patientv1.NewPatientServiceClient(startServer(t, patients))
+
+	if _, err := client.GetPatient(withToken(t, "u1"), &patientv1.GetPatientRequest{Id: "p1"}); err != nil {
+		t.Fatalf("GetPatient failed: %v", err)
+	}
+	if patients.tenantID != tenant.Default {
+		t.Errorf("Expected a token without a tenant to act for the default tenant, got %q", patients.tenantID)
+	}
+
+	for claim, want := range map[string]codes.Code{"north": codes.OK, "south": codes.PermissionDenied} {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{
+			UserID:   "u1",
+			Role:     access.RoleDoctor,
+			TenantID: claim,
+		}).SignedString([]byte(testSecret))
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+
+		_, err = client.GetPatient(ctx, &patientv1.GetPatientRequest{Id: "p1"})
+		if status.Code(err) != want {
+			t.Errorf("Expected %v for tenant %q, got %v", want, claim, err)
+		}
+	}
+	if patients.tenantID != "north" {
+		t.Errorf("Expected the claimed tenant, got %q", patients.tenantID)
+	}
+}
+
+func TestGetPatient(t *testing.T) {
+	client := patientv1.NewPatientServiceClient(startServer(t, testPatients()))
+	ctx := withToken(t, "u1")
+
+	patient, err := client.GetPatient(ctx, &patientv1.GetPatientRequest{Id: "p1"})
+	if err != nil {
+		t.Fatalf("GetPatient failed: %v", err)
+	}
+	if patient.FirstName != "Budi" || patient.Gender != patientv1.Gender_MALE || patient.DateOfBirth != "1990-05-17" {
+		t.Errorf("Unexpected patient: %v", patient)
+	}
+
+	_, err = client.GetPatient(ctx, &patientv1.GetPatientRequest{Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestBatchGetPatients(t *testing.T) {
+	client := patientv1.NewPatientServiceClient(startServer(t, testPatients()))
+
+	resp, err := client.BatchGetPatients(withToken(t, "u1"), &patientv1.BatchGetPatientsRequest{Ids: []string{"p1", "p2", "p2"}})
+	if err != nil {
+		t.Fatalf("BatchGetPatients failed: %v", err)
+	}
+	if len(resp.Patients) != 1 || resp.Patients[0].Id != "p1" {
+		t.Errorf("Unexpected patients: %v", resp.Patients)
+	}
+	if len(resp.NotFoundIds) != 1 || resp.NotFoundIds[0] != "p2" {
+		t.Errorf("Expected p2 not found once, got %v", resp.NotFoundIds)
+	}
+}
+
+func TestCreatePatient(t *testing.T) {
+	patients := testPatients()
+	client := patientv1.NewPatientServiceClient(startServer(t, patients))
+	ctx := withToken(t, "u1")
+
+	_, err := client.CreatePatient(ctx, &patientv1.CreatePatientRequest{Patient: &patientv1.Patient{FirstName: "Siti"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an incomplete patient, got %v", err)
+	}
+
+	created, err := client.CreatePatient(ctx, &patientv1.CreatePatientRequest{Patient: &patientv1.Patient{
+		Nik:         "3171234567890124",
+		FirstName:   "Siti",
+		DateOfBirth: "1985-01-02",
+		Gender:      patientv1.Gender_FEMALE,
+		Phone:       "081234567890",
+	}})
+	if err != nil {
+		t.Fatalf("CreatePatient failed: %v", err)
+	}
+	if created.Id != "new" || patients.created.CreatedBy != "u1" || patients.created.Gender != "FEMALE" {
+		t.Errorf("Unexpected created patient: %v, %+v", created, patients.created)
+	}
+}
+
+func TestHealthNeedsNoToken(t *testing.T) {
+	client := healthpb.NewHealthClient(startServer(t, testPatients()))
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: patientv1.PatientService_ServiceDesc.ServiceName})
+	if err != nil {
+		t.Fatalf("Health check failed: %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING, got %v", resp.Status)
+	}
+}
@@ -0,0 +1,212 @@
+// Patient change feed handlers
+// internal/handler/change_handler.go
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"patient-service/internal/access"
+	"patient-service/internal/changes"
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/internal/service"
+	"patient-service/internal/tenant"
+	"patient-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// StreamPath is the SSE endpoint, see StreamRequestConfig
+	StreamPath = "/api/v1/patients/changes/stream"
+
+	streamBatch       = 500
+	streamRetry       = 3 * time.Second  // EventSource reconnect delay
+	streamHeartbeat   = 15 * time.Second // keeps proxies from closing idle streams
+	streamWriteWindow = 24 * time.Hour
+)
+
+type ChangeHandler struct {
+	changeService service.ChangeService
+	hub           *changes.Hub
+	policy        *access.Policy
+	validator     *validator.Validate
+}
+
+func NewChangeHandler(changeService service.ChangeService, hub *changes.Hub, policy *access.Policy, validator *validator.Validate) *ChangeHandler {
+	return &ChangeHandler{
+		changeService: changeService,
+		hub:           hub,
+		policy:        policy,
+		validator:     validator,
+	}
+}
+
+// ListChanges godoc
+// @Summary List patient changes
+// @Description Ordered change records after a token. Without since, returns no changes and the token of the current end of the feed.
+// @Tags patients
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param since query string false "Token from next_token, or the token of a change"
+// @Param limit query int false "Maximum changes (default: 100, max: 1000)"
+// @Success 200 {object} dto.ListChangesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 410 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/changes [get]
+func (h *ChangeHandler) ListChanges(c *fiber.Ctx) error {
+	var req dto.ListChangesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters", err.Error())
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	page, err := h.changeService.ListChanges(c.Context(), req.Since, req.Limit)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	role, _ := c.Locals("role").(string)
+	resp := dto.ListChangesResponse{
+		Data:      h.render(role, page.Changes),
+		NextToken: page.Next,
+		HasMore:   page.HasMore,
+	}
+	return c.JSON(resp)
+}
+
+// StreamChanges godoc
+// @Summary Stream patient changes
+// @Description Server-Sent Events stream of change records. Each event's id is its token; reconnecting with Last-Event-ID (or since) resumes after it. Without either, the stream starts at the current end of the feed.
+// @Tags patients
+// @Produce text/event-stream
+// @Param Authorization header string true "Bearer token"
+// @Param Last-Event-ID header string false "Token of the last change received"
+// @Param since query string false "Token to start after"
+// @Success 200 {object} dto.ChangeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 410 {object} dto.ErrorResponse
+// @Router /api/v1/patients/changes/stream [get]
+func (h *ChangeHandler) StreamChanges(c *fiber.Ctx) error {
+	since := c.Get("Last-Event-ID")
+	if since == "" {
+		since = c.Query("since")
+	}
+
+	// Resolve the position before streaming so bad or expired tokens get a
+	// proper error status
+	page, err := h.changeService.ListChanges(c.Context(), since, streamBatch)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	role, _ := c.Locals("role").(string)
+	tenantID, _ := c.Locals(tenant.ContextKey).(string)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		h.stream(w, tenantID, role, page)
+	})
+	return nil
+}
+
+// stream writes the catch-up pages and then live changes until the client
+// goes away or the server shuts down.
+func (h *ChangeHandler) stream(w *bufio.Writer, tenantID, role string, page *domain.ChangePage) {
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	send := func(batch []*domain.PatientChange) error {
+		return h.send(w, role, batch)
+	}
+	keepAlive := func() error {
+		fmt.Fprint(w, ": keep-alive\n\n")
+		return w.Flush()
+	}
+
+	if err := h.hub.Follow(tenant.NewContext(context.Background(), tenantID), h.changeService, page, send, streamHeartbeat, keepAlive); err != nil {
+		h.streamError(w, err)
+	}
+}
+
+func (h *ChangeHandler) send(w *bufio.Writer, role string, batch []*domain.PatientChange) error {
+	for i, change := range h.render(role, batch) {
+		data, err := json.Marshal(change)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "id: %d\ndata: %s\n\n", batch[i].Sequence, data)
+	}
+	return w.Flush()
+}
+
+// streamError ends the stream with an error event carrying the same code as
+// the JSON endpoint would. It is harmless when the client has gone away.
+func (h *ChangeHandler) streamError(w *bufio.Writer, err error) {
+	code := "STREAM_FAILED"
+	if err == domain.ErrChangesExpired {
+		code = "CHANGES_EXPIRED"
+	}
+	data, _ := json.Marshal(dto.ErrorDetail{Code: code, Message: err.Error()})
+	fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+	w.Flush()
+}
+
+func (h *ChangeHandler) render(role string, batch []*domain.PatientChange) []*dto.ChangeResponse {
+	data := make([]*dto.ChangeResponse, 0, len(batch))
+	for _, change := range batch {
+		resp := dto.ToChangeResponse(change, domain.ChangeToken(change.Sequence))
+		h.policy.Redact(role, resp.Patient)
+		data = append(data, resp)
+	}
+	return data
+}
+
+func (h *ChangeHandler) error(c *fiber.Ctx, err error) error {
+	if err == domain.ErrChangesExpired {
+		return utils.ErrorResponse(c, fiber.StatusGone, "CHANGES_EXPIRED", "Token is older than the retained change feed; reload and start from a new token", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "CHANGES_FAILED", "Failed to read changes", err.Error())
+}
+
+// StreamRequestConfig is a fasthttp HeaderReceived hook lifting the server
+// write timeout, which bounds the whole response, for the SSE stream and
+// for export and document downloads.
+func StreamRequestConfig(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+	if string(header.Method()) != fiber.MethodGet {
+		return fasthttp.RequestConfig{}
+	}
+	path, _, _ := strings.Cut(string(header.RequestURI()), "?")
+	switch {
+	case path == StreamPath:
+		return fasthttp.RequestConfig{WriteTimeout: streamWriteWindow}
+	case path == ExportPath, strings.HasPrefix(path, exportsPath) && strings.HasSuffix(path, "/download"):
+		return fasthttp.RequestConfig{WriteTimeout: exportWriteWindow}
+	case strings.Contains(path, "/documents/") && strings.HasSuffix(path, "/content"):
+		return fasthttp.RequestConfig{WriteTimeout: documentWriteWindow}
+	}
+	return fasthttp.RequestConfig{}
+}
@@ -0,0 +1,184 @@
+// Patient consent handlers
+// internal/handler/consent_handler.go
+package handler
+
+import (
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/internal/service"
+	"patient-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ConsentHandler struct {
+	consentService service.ConsentService
+	validator      *validator.Validate
+}
+
+func NewConsentHandler(consentService service.ConsentService, validator *validator.Validate) *ConsentHandler {
+	return &ConsentHandler{
+		consentService: consentService,
+		validator:      validator,
+	}
+}
+
+// CreateConsent godoc
+// @Summary Record a patient consent
+// @Description Record a consent to release the patient's data for a purpose. Of the consents of a type in effect, the one valid from the latest date applies.
+// @Tags consents
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param request body dto.CreateConsentRequest true "Consent"
+// @Success 201 {object} dto.ConsentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/consents [post]
+func (h *ConsentHandler) CreateConsent(c *fiber.Ctx) error {
+	var req dto.CreateConsentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	consent := dto.ToConsentDomain(c.Params("id"), &req)
+	consent.CreatedBy = c.Locals("userID").(string)
+
+	created, err := h.consentService.CreateConsent(c.Context(), consent)
+	if err != nil {
+		return h.error(c, err, "CREATE_FAILED", "Failed to create consent")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToConsentResponse(created, time.Now()))
+}
+
+// ListConsents godoc
+// @Summary List a patient's consents
+// @Description List current and past consents, revoked and expired ones included.
+// @Tags consents
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Success 200 {object} dto.ListConsentsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/consents [get]
+func (h *ConsentHandler) ListConsents(c *fiber.Ctx) error {
+	consents, err := h.consentService.ListConsents(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list consents")
+	}
+
+	now := time.Now()
+	resp := dto.ListConsentsResponse{Data: make([]*dto.ConsentResponse, 0, len(consents))}
+	for _, consent := range consents {
+		resp.Data = append(resp.Data, dto.ToConsentResponse(consent, now))
+	}
+	return c.JSON(resp)
+}
+
+// GetConsentStatus godoc
+// @Summary Get a patient's consent status
+// @Description Whether the patient's data may currently be released, per consent type. Without a consent in effect, the configured opt-out types are allowed.
+// @Tags consents
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Success 200 {object} dto.ConsentStatusResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/consents/status [get]
+func (h *ConsentHandler) GetConsentStatus(c *fiber.Ctx) error {
+	allowed, err := h.consentService.ConsentStatus(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get consent status")
+	}
+	return c.JSON(dto.ConsentStatusResponse{PatientID: c.Params("id"), Allowed: allowed})
+}
+
+// GetConsent godoc
+// @Summary Get a patient consent
+// @Tags consents
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param consentId path string true "Consent ID"
+// @Success 200 {object} dto.ConsentResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/consents/{consentId} [get]
+func (h *ConsentHandler) GetConsent(c *fiber.Ctx) error {
+	consent, err := h.consentService.GetConsent(c.Context(), c.Params("id"), c.Params("consentId"))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get consent")
+	}
+	return c.JSON(dto.ToConsentResponse(consent, time.Now()))
+}
+
+// RevokeConsent godoc
+// @Summary Revoke a patient consent
+// @Description End the consent from now on. The record is kept for audit.
+// @Tags consents
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param consentId path string true "Consent ID"
+// @Param request body dto.RevokeConsentRequest false "Reason"
+// @Success 200 {object} dto.ConsentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/consents/{consentId}/revoke [post]
+func (h *ConsentHandler) RevokeConsent(c *fiber.Ctx) error {
+	var req dto.RevokeConsentRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+		}
+	}
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	consent, err := h.consentService.RevokeConsent(c.Context(), c.Params("id"), c.Params("consentId"),
+		c.Locals("userID").(string), req.Reason)
+	if err != nil {
+		return h.error(c, err, "REVOKE_FAILED", "Failed to revoke consent")
+	}
+	return c.JSON(dto.ToConsentResponse(consent, time.Now()))
+}
+
+func (h *ConsentHandler) error(c *fiber.Ctx, err error, code, message string) error {
+	switch err {
+	case domain.ErrPatientNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Patient not found", "")
+	case domain.ErrConsentNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Consent not found", "")
+	case domain.ErrConsentRevoked:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "ALREADY_REVOKED", "Consent is already revoked", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Patient and consent IDs are required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, code, message, err.Error())
+}
@@ -0,0 +1,83 @@
+// Deceased status recording
+// internal/handler/deceased.go
+package handler
+
+import (
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RecordDeath godoc
+// @Summary Record a patient's death
+// @Description Record the date and time of death and a coded cause reference, with the authenticated clinician as recorder. The patient stays readable, but cannot be registered again or issued cards, wristbands or public profile tokens. Emits patient.deceased.
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param request body dto.RecordDeathRequest true "Time and cause of death"
+// @Success 200 {object} dto.PatientResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/deceased [post]
+func (h *PatientHandler) RecordDeath(c *fiber.Ctx) error {
+	var req dto.RecordDeathRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	patient, err := h.patientService.RecordDeath(c.Context(), c.Params("id"), req.DeceasedAt, req.CauseOfDeath, c.Locals("userID").(string))
+	if err != nil {
+		return deathError(c, err)
+	}
+	return c.JSON(dto.ToPatientResponse(patient))
+}
+
+// ClearDeath godoc
+// @Summary Remove a death recorded in error
+// @Description Clear the patient's deceased status. Emits patient.updated.
+// @Tags patients
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Success 200 {object} dto.PatientResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/deceased [delete]
+func (h *PatientHandler) ClearDeath(c *fiber.Ctx) error {
+	patient, err := h.patientService.ClearDeath(c.Context(), c.Params("id"), c.Locals("userID").(string))
+	if err != nil {
+		return deathError(c, err)
+	}
+	return c.JSON(dto.ToPatientResponse(patient))
+}
+
+func deathError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrPatientNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Patient not found", "")
+	case domain.ErrPatientDeceased:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "PATIENT_DECEASED", "Patient is already recorded as deceased", "")
+	case domain.ErrPatientNotDeceased:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "NOT_DECEASED", "Patient is not recorded as deceased", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Patient ID is required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "UPDATE_FAILED", "Failed to update patient", err.Error())
+}
@@ -0,0 +1,237 @@
+// Patient document handlers
+// internal/handler/document_handler.go
+package handler
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/internal/service"
+	"patient-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// documentWriteWindow bounds a document download, which may be slow on
+// mobile connections; see StreamRequestConfig
+const documentWriteWindow = 5 * time.Minute
+
+type DocumentHandler struct {
+	documentService service.DocumentService
+	validator       *validator.Validate
+	maxUpload       int64
+}
+
+// NewDocumentHandler creates the handler; maxUpload is the largest file
+// accepted, in bytes.
+func NewDocumentHandler(documentService service.DocumentService, validator *validator.Validate, maxUpload int64) *DocumentHandler {
+	return &DocumentHandler{
+		documentService: documentService,
+		validator:       validator,
+		maxUpload:       maxUpload,
+	}
+}
+
+// UploadDocument godoc
+// @Summary Attach a document to a patient
+// @Description Upload a scanned KTP, insurance card, signed consent form, photo or other document. The file type is detected from its content: PDF, JPEG, PNG or WebP, images only for photos. Files are scanned for malware when a scanner is configured.
+// @Tags documents
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param file formData file true "Document file"
+// @Param type formData string true "ktp, insurance_card, consent_form, photo or other"
+// @Param description formData string false "Description"
+// @Param issued_date formData string false "Date printed on the document, YYYY-MM-DD"
+// @Success 201 {object} dto.DocumentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Failure 415 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/documents [post]
+func (h *DocumentHandler) UploadDocument(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "A file is required", err.Error())
+	}
+	if fileHeader.Size > h.maxUpload {
+		return utils.ErrorResponse(c, fiber.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "File is too large",
+			fmt.Sprintf("maximum is %d MB", h.maxUpload>>20))
+	}
+
+	var req dto.UploadDocumentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid form", err.Error())
+	}
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	doc := dto.ToDocumentDomain(c.Params("id"), &req)
+	doc.FileName = truncateName(filepath.Base(fileHeader.Filename), 255)
+	doc.Size = fileHeader.Size
+	doc.UploadedBy = c.Locals("userID").(string)
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Failed to read file", err.Error())
+	}
+	defer file.Close()
+
+	created, err := h.documentService.UploadDocument(c.Context(), doc, file)
+	if err != nil {
+		return h.error(c, err, "UPLOAD_FAILED", "Failed to upload document")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.response(c, created))
+}
+
+// ListDocuments godoc
+// @Summary List a patient's documents
+// @Description Metadata of the patient's documents, newest first, each with a short-lived download link.
+// @Tags documents
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Success 200 {object} dto.ListDocumentsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/documents [get]
+func (h *DocumentHandler) ListDocuments(c *fiber.Ctx) error {
+	docs, err := h.documentService.ListDocuments(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list documents")
+	}
+
+	resp := dto.ListDocumentsResponse{Data: make([]*dto.DocumentResponse, 0, len(docs))}
+	for _, doc := range docs {
+		resp.Data = append(resp.Data, h.response(c, doc))
+	}
+	return c.JSON(resp)
+}
+
+// GetDocument godoc
+// @Summary Get a patient document
+// @Description Metadata of the document with a short-lived download link.
+// @Tags documents
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param documentId path string true "Document ID"
+// @Success 200 {object} dto.DocumentResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/documents/{documentId} [get]
+func (h *DocumentHandler) GetDocument(c *fiber.Ctx) error {
+	doc, err := h.documentService.GetDocument(c.Context(), c.Params("id"), c.Params("documentId"))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get document")
+	}
+	return c.JSON(h.response(c, doc))
+}
+
+// DeleteDocument godoc
+// @Summary Delete a patient document
+// @Tags documents
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param documentId path string true "Document ID"
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/documents/{documentId} [delete]
+func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
+	if err := h.documentService.DeleteDocument(c.Context(), c.Params("id"), c.Params("documentId")); err != nil {
+		return h.error(c, err, "DELETE_FAILED", "Failed to delete document")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DownloadDocument godoc
+// @Summary Download a patient document
+// @Description Open the content through a signed link from the document's download_url. No bearer token is needed; the link expires after a few minutes.
+// @Tags documents
+// @Produce application/pdf,image/jpeg,image/png,image/webp
+// @Param id path string true "Patient ID"
+// @Param documentId path string true "Document ID"
+// @Param expires query int true "Link expiry, unix seconds"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/documents/{documentId}/content [get]
+func (h *DocumentHandler) DownloadDocument(c *fiber.Ctx) error {
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+	doc, content, err := h.documentService.OpenDocument(c.Context(), c.Params("id"), c.Params("documentId"), expires, c.Query("signature"))
+	if err != nil {
+		return h.error(c, err, "DOWNLOAD_FAILED", "Failed to download document")
+	}
+
+	c.Set(fiber.HeaderContentType, doc.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": doc.FileName}))
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	// fasthttp closes the content once it is sent
+	c.Context().SetBodyStream(content, int(doc.Size))
+	return nil
+}
+
+// response adds a signed download link to the document.
+func (h *DocumentHandler) response(c *fiber.Ctx, doc *domain.Document) *dto.DocumentResponse {
+	expires, signature := h.documentService.SignDownload(doc)
+	downloadURL := fmt.Sprintf("%s/api/v1/patients/%s/documents/%s/content?expires=%d&signature=%s",
+		c.BaseURL(), doc.PatientID, doc.ID, expires, signature)
+	return dto.ToDocumentResponse(doc, downloadURL, time.Unix(expires, 0))
+}
+
+func (h *DocumentHandler) error(c *fiber.Ctx, err error, code, message string) error {
+	switch err {
+	case domain.ErrPatientNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Patient not found", "")
+	case domain.ErrDocumentNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Document not found", "")
+	case domain.ErrUnsupportedMediaType:
+		return utils.ErrorResponse(c, fiber.StatusUnsupportedMediaType, "UNSUPPORTED_FILE_TYPE", "File must be a PDF, JPEG, PNG or WebP; photos must be images", "")
+	case domain.ErrDocumentInfected:
+		return utils.ErrorResponse(c, fiber.StatusUnprocessableEntity, "FILE_INFECTED", "File failed the virus scan", "")
+	case domain.ErrInvalidSignature:
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "INVALID_SIGNATURE", "Download link is invalid or has expired", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Patient and document IDs are required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, code, message, err.Error())
+}
+
+// truncateName shortens a file name to at most n bytes, keeping its
+// extension and whole characters.
+func truncateName(name string, n int) string {
+	if len(name) <= n {
+		return name
+	}
+	ext := filepath.Ext(name)
+	if len(ext) >= n {
+		ext = ""
+	}
+	return strings.ToValidUTF8(name[:n-len(ext)], "") + ext
+}
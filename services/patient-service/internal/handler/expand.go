@@ -0,0 +1,203 @@
+// Sparse fieldsets and expandable sub-resources
+// internal/handler/expand.go
+package handler
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+)
+
+// Expansion loads a related sub-resource for a batch of patients, so list
+// responses cost one query per expansion instead of one per patient.
+type Expansion struct {
+	// Fields the loader reads from the patient; they are added to the
+	// SELECT of sparse requests but not to the response
+	Fields []string
+
+	// Load returns the sub-resource keyed by patient ID
+	Load func(ctx context.Context, patients []*domain.Patient) (map[string]interface{}, error)
+}
+
+// readOptions are the parsed ?fields= and ?expand= parameters.
+type readOptions struct {
+	fields []string // nil means all fields
+	expand []string
+}
+
+func (o readOptions) sparse() bool {
+	return o.fields != nil || len(o.expand) > 0
+}
+
+// selectFields returns the fields to load: the requested ones plus those
+// needed by expansions.
+func (o readOptions) selectFields(expansions map[string]Expansion) []string {
+	if o.fields == nil {
+		return nil
+	}
+
+	fields := append([]string(nil), o.fields...)
+	for _, name := range o.expand {
+		fields = append(fields, expansions[name].Fields...)
+	}
+	return fields
+}
+
+// RegisterExpansion makes name available to ?expand=.
+func (h *PatientHandler) RegisterExpansion(name string, expansion Expansion) {
+	h.expansions[name] = expansion
+}
+
+// parseReadOptions validates fields and expand against their allow-lists.
+func (h *PatientHandler) parseReadOptions(fields, expand string) (readOptions, error) {
+	var opts readOptions
+
+	parsed, err := domain.ParseFields(fields)
+	if err != nil {
+		return opts, err
+	}
+	opts.fields = parsed
+
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(expand, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := h.expansions[name]; !ok {
+			return opts, domain.NewCustomError("INVALID_EXPAND", "Unknown expansion: "+name, "Allowed: "+strings.Join(h.expansionNames(), ", "))
+		}
+		seen[name] = true
+		opts.expand = append(opts.expand, name)
+	}
+
+	return opts, nil
+}
+
+func (h *PatientHandler) expansionNames() []string {
+	names := make([]string, 0, len(h.expansions))
+	for name := range h.expansions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// renderPatients converts patients to their response form: the full
+// PatientResponse by default, or a map with the requested fields and
+// expansions.
+func (h *PatientHandler) renderPatients(ctx context.Context, patients []*domain.Patient, opts readOptions) ([]interface{}, error) {
+	out := make([]interface{}, len(patients))
+
+	if !opts.sparse() {
+		for i, p := range patients {
+			out[i] = dto.ToPatientResponse(p)
+		}
+		return out, nil
+	}
+
+	maps := make([]map[string]interface{}, len(patients))
+	for i, p := range patients {
+		maps[i] = dto.ToPatientFieldMap(p, opts.fields)
+		out[i] = maps[i]
+	}
+
+	for _, name := range opts.expand {
+		loaded, err := h.expansions[name].Load(ctx, patients)
+		if err != nil {
+			return nil, err
+		}
+		for i, p := range patients {
+			maps[i][name] = loaded[p.ID]
+		}
+	}
+
+	return out, nil
+}
+
+// NameResolver resolves user IDs to names, e.g. from practitioner-service.
+type NameResolver interface {
+	// Names returns the names of the user IDs it knows
+	Names(ctx context.Context, userIDs []string) (map[string]string, error)
+}
+
+// AuditExpansion exposes who created and last updated a patient. With
+// names, the user IDs are resolved to names; patients are still returned
+// without them when the lookup fails.
+func AuditExpansion(names NameResolver) Expansion {
+	return Expansion{
+		Fields: []string{"created_by", "updated_by", "created_at", "updated_at"},
+		Load: func(ctx context.Context, patients []*domain.Patient) (map[string]interface{}, error) {
+			var resolved map[string]string
+			if names != nil {
+				userIDs := make([]string, 0, 2*len(patients))
+				for _, p := range patients {
+					userIDs = append(userIDs, p.CreatedBy, p.UpdatedBy)
+				}
+				var err error
+				if resolved, err = names.Names(ctx, userIDs); err != nil {
+					log.Printf("Failed to resolve audit user names: %v", err)
+				}
+			}
+
+			out := make(map[string]interface{}, len(patients))
+			for _, p := range patients {
+				out[p.ID] = dto.AuditResponse{
+					CreatedBy:     p.CreatedBy,
+					CreatedByName: resolved[p.CreatedBy],
+					UpdatedBy:     p.UpdatedBy,
+					UpdatedByName: resolved[p.UpdatedBy],
+					CreatedAt:     p.CreatedAt,
+					UpdatedAt:     p.UpdatedAt,
+				}
+			}
+			return out, nil
+		},
+	}
+}
+
+// IdentifierSource loads the additional identifiers of patients.
+type IdentifierSource interface {
+	ListByPatients(ctx context.Context, patientIDs []string) ([]*domain.Identifier, error)
+}
+
+// IdentifierExpansion exposes identifiers issued by external registries,
+// such as the SATUSEHAT IHS number.
+func IdentifierExpansion(source IdentifierSource) Expansion {
+	return Expansion{
+		Load: func(ctx context.Context, patients []*domain.Patient) (map[string]interface{}, error) {
+			ids := make([]string, len(patients))
+			for i, p := range patients {
+				ids[i] = p.ID
+			}
+
+			identifiers, err := source.ListByPatients(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			grouped := make(map[string][]dto.IdentifierResponse, len(patients))
+			for _, id := range identifiers {
+				grouped[id.PatientID] = append(grouped[id.PatientID], dto.IdentifierResponse{
+					System:    id.System,
+					Value:     id.Value,
+					UpdatedAt: id.UpdatedAt,
+				})
+			}
+
+			out := make(map[string]interface{}, len(patients))
+			for _, p := range patients {
+				list := grouped[p.ID]
+				if list == nil {
+					list = []dto.IdentifierResponse{}
+				}
+				out[p.ID] = list
+			}
+			return out, nil
+		},
+	}
+}
@@ -0,0 +1,301 @@
+// Patient export handlers
+// internal/handler/export_handler.go
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"patient-service/internal/access"
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/internal/export"
+	"patient-service/internal/service"
+	"patient-service/internal/tenant"
+	"patient-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// ExportPath is the streaming export endpoint, see StreamRequestConfig
+	ExportPath = "/api/v1/patients/export"
+
+	// exportsPath prefixes asynchronous exports; their downloads are
+	// long responses too
+	exportsPath = "/api/v1/patients/exports/"
+
+	exportWriteWindow = 6 * time.Hour
+)
+
+type ExportHandler struct {
+	exportService service.ExportService
+	policy        *access.Policy
+	validator     *validator.Validate
+}
+
+func NewExportHandler(exportService service.ExportService, policy *access.Policy, validator *validator.Validate) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		policy:        policy,
+		validator:     validator,
+	}
+}
+
+// ExportPatients godoc
+// @Summary Export patients
+// @Description Stream every patient matching the ListPatients filters as CSV, NDJSON or Parquet. Columns are ?fields= (default all) less those the caller's role may not read. Paging parameters are ignored.
+// @Tags patients
+// @Produce text/csv,application/x-ndjson,application/vnd.apache.parquet
+// @Param Authorization header string true "Bearer token"
+// @Param format query string true "csv, ndjson or parquet"
+// @Param fields query string false "Comma separated fields"
+// @Param consent query string false "Only patients who allow release for this consent type: satusehat, research, sms_reminder or family_sharing"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/v1/patients/export [get]
+func (h *ExportHandler) ExportPatients(c *fiber.Ctx) error {
+	req, filter, err := h.parseRequest(c)
+	if err != nil {
+		return h.error(c, err, "EXPORT_FAILED", "Failed to export patients")
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	tenantID, _ := c.Locals(tenant.ContextKey).(string)
+
+	c.Set(fiber.HeaderContentType, export.ContentType(req.Format))
+	c.Attachment(fmt.Sprintf("patients-%s.%s", time.Now().Format("20060102-150405"), req.Format))
+
+	// The response is written as rows are read; an error past this point
+	// can only cut the file short, so it is logged
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writer, err := export.NewWriter(req.Format, w, filter.Fields)
+		if err == nil {
+			err = h.exportService.ExportPatients(tenant.NewContext(context.Background(), tenantID), filter, req.Consent, writer.Write)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			log.Printf("Patient export for user %s stopped: %v", userID, err)
+		}
+	})
+	return nil
+}
+
+// StartExport godoc
+// @Summary Start an asynchronous patient export
+// @Description Same parameters as GET /patients/export. The file is written in the background and downloaded once the job completed; only the requesting user can see the job.
+// @Tags patients
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param format query string true "csv, ndjson or parquet"
+// @Param fields query string false "Comma separated fields"
+// @Param consent query string false "Only patients who allow release for this consent type: satusehat, research, sms_reminder or family_sharing"
+// @Success 202 {object} dto.ExportJobResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/exports [post]
+func (h *ExportHandler) StartExport(c *fiber.Ctx) error {
+	req, filter, err := h.parseRequest(c)
+	if err != nil {
+		return h.error(c, err, "EXPORT_FAILED", "Failed to start export")
+	}
+
+	job := &domain.ExportJob{
+		Format:      req.Format,
+		Filter:      filter,
+		ConsentType: req.Consent,
+		CreatedBy:   c.Locals("userID").(string),
+	}
+	created, err := h.exportService.StartExport(c.Context(), job)
+	if err != nil {
+		return h.error(c, err, "EXPORT_FAILED", "Failed to start export")
+	}
+
+	c.Location(h.jobURL(c, created.ID))
+	return c.Status(fiber.StatusAccepted).JSON(dto.ToExportJobResponse(created, h.downloadURL(c, created.ID)))
+}
+
+// StartDeidentifiedExport godoc
+// @Summary Start a de-identified research export
+// @Description Export of pseudonymous demographics for research: names, NIK, contact details and addresses are dropped, the date of birth is generalized to a birth year or age band and the postal code to its region, and rows in groups of fewer than k patients sharing the quasi-identifiers are suppressed. The body overrides the configured ruleset; the job records the ruleset used. Only patients who consent to research are included. Takes the ListPatients filters except search, dob, created_by, fields and consent.
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param format query string true "csv, ndjson or parquet"
+// @Param request body dto.DeidentifiedExportRequest false "Ruleset overrides"
+// @Success 202 {object} dto.ExportJobResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/patients/exports/deidentified [post]
+func (h *ExportHandler) StartDeidentifiedExport(c *fiber.Ctx) error {
+	// Filters that single out patients by identifier would defeat the
+	// de-identification
+	for _, param := range []string{"search", "dob", "created_by", "fields", "consent"} {
+		if c.Query(param) != "" {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST",
+				param+" is not supported for de-identified exports", "")
+		}
+	}
+
+	exportReq, filter, err := h.parseRequest(c)
+	if err != nil {
+		return h.error(c, err, "EXPORT_FAILED", "Failed to start export")
+	}
+
+	var req dto.DeidentifiedExportRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+		}
+	}
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	job := &domain.ExportJob{
+		Format:    exportReq.Format,
+		Filter:    filter,
+		CreatedBy: c.Locals("userID").(string),
+	}
+	created, err := h.exportService.StartDeidentifiedExport(c.Context(), job, dto.ToDeidentRuleset(&req))
+	if err != nil {
+		return h.error(c, err, "EXPORT_FAILED", "Failed to start export")
+	}
+
+	c.Location(h.jobURL(c, created.ID))
+	return c.Status(fiber.StatusAccepted).JSON(dto.ToExportJobResponse(created, h.downloadURL(c, created.ID)))
+}
+
+// GetExport godoc
+// @Summary Get an asynchronous export
+// @Tags patients
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Export job ID"
+// @Success 200 {object} dto.ExportJobResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/exports/{id} [get]
+func (h *ExportHandler) GetExport(c *fiber.Ctx) error {
+	job, err := h.exportService.GetExport(c.Context(), c.Params("id"), c.Locals("userID").(string))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get export")
+	}
+	return c.JSON(dto.ToExportJobResponse(job, h.downloadURL(c, job.ID)))
+}
+
+// DownloadExport godoc
+// @Summary Download a completed export
+// @Tags patients
+// @Produce text/csv,application/x-ndjson,application/vnd.apache.parquet
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Export job ID"
+// @Success 200 {file} file
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(c *fiber.Ctx) error {
+	job, file, err := h.exportService.OpenExport(c.Context(), c.Params("id"), c.Locals("userID").(string))
+	if err != nil {
+		return h.error(c, err, "DOWNLOAD_FAILED", "Failed to download export")
+	}
+
+	c.Set(fiber.HeaderContentType, export.ContentType(job.Format))
+	c.Attachment(job.FileName())
+	// fasthttp closes the file once it is sent
+	c.Context().SetBodyStream(file, int(job.FileSize))
+	return nil
+}
+
+// parseRequest reads the export parameters and the ListPatients filters.
+// The filter's fields are the columns the caller's role may read.
+func (h *ExportHandler) parseRequest(c *fiber.Ctx) (*dto.ExportPatientsRequest, domain.PatientFilter, error) {
+	var req dto.ExportPatientsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return nil, domain.PatientFilter{}, domain.NewCustomError("INVALID_REQUEST", "Invalid query parameters", err.Error())
+	}
+	if err := h.validator.Struct(&req); err != nil {
+		return nil, domain.PatientFilter{}, err
+	}
+
+	listReq := dto.ListPatientsRequest{Page: 1, Limit: 1}
+	if err := c.QueryParser(&listReq); err != nil {
+		return nil, domain.PatientFilter{}, domain.NewCustomError("INVALID_REQUEST", "Invalid query parameters", err.Error())
+	}
+	listReq.Page, listReq.Limit, listReq.Cursor = 1, 1, ""
+	listReq.BloodType = strings.ReplaceAll(listReq.BloodType, " ", "+")
+	if err := h.validator.Struct(&listReq); err != nil {
+		return nil, domain.PatientFilter{}, err
+	}
+	if listReq.Expand != "" {
+		return nil, domain.PatientFilter{}, domain.NewCustomError("INVALID_REQUEST", "expand is not supported for exports", "")
+	}
+
+	filter, err := dto.ToPatientFilter(&listReq)
+	if err != nil {
+		return nil, domain.PatientFilter{}, err
+	}
+	fields, err := domain.ParseFields(listReq.Fields)
+	if err != nil {
+		return nil, domain.PatientFilter{}, err
+	}
+
+	role, _ := c.Locals("role").(string)
+	filter.Fields = export.Columns(fields, role, h.policy)
+	filter.Page, filter.Limit, filter.SkipTotal = 0, 0, true
+	if len(filter.Fields) == 0 {
+		return nil, domain.PatientFilter{}, domain.NewCustomError("INVALID_FIELDS", "None of the requested fields may be exported by your role", "")
+	}
+
+	return &req, filter, nil
+}
+
+func (h *ExportHandler) jobURL(c *fiber.Ctx, id string) string {
+	return c.BaseURL() + exportsPath + id
+}
+
+func (h *ExportHandler) downloadURL(c *fiber.Ctx, id string) string {
+	return h.jobURL(c, id) + "/download"
+}
+
+func (h *ExportHandler) error(c *fiber.Ctx, err error, code, message string) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	switch err {
+	case domain.ErrExportNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Export not found", "")
+	case domain.ErrExportNotReady:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "NOT_READY", "Export has not completed", "")
+	case domain.ErrDeidentOff:
+		return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "DEIDENT_DISABLED", "De-identified exports are not configured", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Export ID is required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, code, message, err.Error())
+}
@@ -0,0 +1,497 @@
+// FHIR R4 Patient handlers
+// internal/handler/fhir_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/fhir"
+	"patient-service/internal/service"
+	"patient-service/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// fhirSearchParams are the Patient search parameters this server supports.
+var fhirSearchParams = map[string]bool{
+	"_id": true, "identifier": true, "name": true, "family": true, "given": true,
+	"birthdate": true, "gender": true, "_count": true, "_cursor": true, "_format": true,
+}
+
+type FHIRHandler struct {
+	patientService service.PatientService
+	searchService  service.PatientSearchService
+	mapper         fhir.Mapper
+	tenants        *tenant.Registry
+	baseURL        string
+	version        string
+}
+
+// NewFHIRHandler creates the FHIR facade. baseURL is the public URL of
+// /fhir/R4; when empty it is derived from each request. Tenants with an MRN
+// system of their own use it instead of the mapper's.
+func NewFHIRHandler(patientService service.PatientService, searchService service.PatientSearchService, mapper fhir.Mapper, tenants *tenant.Registry, baseURL, version string) *FHIRHandler {
+	return &FHIRHandler{
+		patientService: patientService,
+		searchService:  searchService,
+		mapper:         mapper,
+		tenants:        tenants,
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		version:        version,
+	}
+}
+
+// Capabilities godoc
+// @Summary FHIR CapabilityStatement
+// @Tags fhir
+// @Produce json
+// @Success 200 {object} fhir.CapabilityStatement
+// @Router /fhir/R4/metadata [get]
+func (h *FHIRHandler) Capabilities(c *fiber.Ctx) error {
+	return c.JSON(fhir.NewCapabilityStatement("patient-service", h.version, h.base(c), h.mapper), fhir.ContentType)
+}
+
+// ReadPatient godoc
+// @Summary Read FHIR Patient
+// @Tags fhir
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Success 200 {object} fhir.Patient
+// @Failure 404 {object} fhir.OperationOutcome
+// @Router /fhir/R4/Patient/{id} [get]
+func (h *FHIRHandler) ReadPatient(c *fiber.Ctx) error {
+	patient, err := h.patientService.GetPatient(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	h.setVersionHeaders(c, patient)
+	return c.JSON(h.mapperFor(c).FromDomain(patient), fhir.ContentType)
+}
+
+// SearchPatients godoc
+// @Summary Search FHIR Patients
+// @Description Supports _id, identifier (system|value), name, family, given, birthdate (exact date), gender and _count. Returns a searchset Bundle; follow the next link for more results.
+// @Tags fhir
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} fhir.Bundle
+// @Failure 400 {object} fhir.OperationOutcome
+// @Router /fhir/R4/Patient [get]
+func (h *FHIRHandler) SearchPatients(c *fiber.Ctx) error {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return h.outcome(c, fiber.StatusBadRequest, "invalid", "Invalid query string")
+	}
+
+	// Unknown parameters are ignored unless the client asks for strict handling
+	if strings.Contains(c.Get("Prefer"), "handling=strict") {
+		for name := range query {
+			if !fhirSearchParams[name] {
+				return h.outcome(c, fiber.StatusBadRequest, "not-supported", "Unsupported search parameter: "+name)
+			}
+		}
+	}
+
+	filter := domain.PatientFilter{}
+
+	if gender := query.Get("gender"); gender != "" {
+		if gender != "male" && gender != "female" {
+			// Only male and female are recorded, so nothing else can match
+			return c.JSON(h.searchSet(c, query, nil, nil, ""), fhir.ContentType)
+		}
+		filter.Genders = []string{strings.ToUpper(gender)}
+	}
+
+	if birthdate := query.Get("birthdate"); birthdate != "" {
+		dob, err := time.Parse("2006-01-02", strings.TrimPrefix(birthdate, "eq"))
+		if err != nil {
+			return h.outcome(c, fiber.StatusBadRequest, "not-supported", "birthdate supports an exact date (YYYY-MM-DD or eqYYYY-MM-DD) only")
+		}
+		filter.DateOfBirth = &dob
+	}
+
+	// name matches any part of the name or the MRN; family and given match
+	// the start of a word of the last or first name, like FHIR string search
+	filter.Search = query.Get("name")
+	filter.FamilyName = query.Get("family")
+	filter.GivenName = query.Get("given")
+
+	// Lookups by logical id or identifier return at most one patient
+	if id, identifier := query.Get("_id"), query.Get("identifier"); id != "" || identifier != "" {
+		patient, err := h.lookup(c, id, identifier)
+		if err != nil && err != domain.ErrPatientNotFound {
+			return h.error(c, err)
+		}
+
+		var patients []*domain.Patient
+		if patient != nil && matchesFilter(patient, filter) {
+			patients = append(patients, patient)
+		}
+		total := len(patients)
+		return c.JSON(h.searchSet(c, query, patients, &total, ""), fhir.ContentType)
+	}
+
+	if count := query.Get("_count"); count != "" {
+		limit, err := strconv.Atoi(count)
+		if err != nil || limit < 1 {
+			return h.outcome(c, fiber.StatusBadRequest, "invalid", "_count must be a positive number")
+		}
+		filter.Limit = limit
+	}
+
+	if token := query.Get("_cursor"); token != "" {
+		cursor, err := domain.DecodeCursor(token)
+		if err != nil {
+			return h.outcome(c, fiber.StatusBadRequest, "invalid", "Invalid _cursor")
+		}
+		filter.Cursor = cursor
+	}
+
+	page, err := h.patientService.ListPatients(c.Context(), filter)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	return c.JSON(h.searchSet(c, query, page.Patients, page.Total, page.NextCursor), fhir.ContentType)
+}
+
+// CreatePatient godoc
+// @Summary Create FHIR Patient
+// @Tags fhir
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param resource body fhir.Patient true "Patient resource"
+// @Success 201 {object} fhir.Patient
+// @Failure 400 {object} fhir.OperationOutcome
+// @Failure 409 {object} fhir.OperationOutcome
+// @Router /fhir/R4/Patient [post]
+func (h *FHIRHandler) CreatePatient(c *fiber.Ctx) error {
+	var resource fhir.Patient
+	if err := json.Unmarshal(c.Body(), &resource); err != nil {
+		return h.outcome(c, fiber.StatusBadRequest, "structure", "Invalid JSON: "+err.Error())
+	}
+
+	patient, err := h.mapperFor(c).ToDomain(&resource)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	userID := c.Locals("userID").(string)
+	patient.CreatedBy = userID
+	patient.UpdatedBy = userID
+
+	created, err := h.patientService.CreatePatient(c.Context(), patient)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	h.setVersionHeaders(c, created)
+	c.Location(h.base(c) + "/Patient/" + created.ID)
+	return c.Status(fiber.StatusCreated).JSON(h.mapperFor(c).FromDomain(created), fhir.ContentType)
+}
+
+// UpdatePatient godoc
+// @Summary Update FHIR Patient
+// @Description Replaces the fields represented in the FHIR Patient resource. Allergies, conditions, blood type and insurance are kept.
+// @Tags fhir
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param resource body fhir.Patient true "Patient resource"
+// @Success 200 {object} fhir.Patient
+// @Failure 400 {object} fhir.OperationOutcome
+// @Failure 404 {object} fhir.OperationOutcome
+// @Router /fhir/R4/Patient/{id} [put]
+func (h *FHIRHandler) UpdatePatient(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var resource fhir.Patient
+	if err := json.Unmarshal(c.Body(), &resource); err != nil {
+		return h.outcome(c, fiber.StatusBadRequest, "structure", "Invalid JSON: "+err.Error())
+	}
+	if resource.ID != id {
+		return h.outcome(c, fiber.StatusBadRequest, "invalid", "Resource id must match the id in the URL")
+	}
+
+	userID := c.Locals("userID").(string)
+
+	updated, err := h.patientService.PatchPatient(c.Context(), id, func(patient *domain.Patient) error {
+		if err := h.mapperFor(c).Apply(&resource, patient); err != nil {
+			return err
+		}
+		patient.UpdatedBy = userID
+		return nil
+	})
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	h.setVersionHeaders(c, updated)
+	return c.JSON(h.mapperFor(c).FromDomain(updated), fhir.ContentType)
+}
+
+// MatchPatient godoc
+// @Summary FHIR Patient $match
+// @Description Finds likely matches for a Patient resource by NIK/MRN identifier, name, birth date and gender. Results carry search.score and a match-grade extension.
+// @Tags fhir
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param parameters body fhir.Parameters true "resource, onlyCertainMatches, count"
+// @Success 200 {object} fhir.Bundle
+// @Failure 400 {object} fhir.OperationOutcome
+// @Failure 503 {object} fhir.OperationOutcome
+// @Router /fhir/R4/Patient/$match [post]
+func (h *FHIRHandler) MatchPatient(c *fiber.Ctx) error {
+	var params fhir.Parameters
+	if err := json.Unmarshal(c.Body(), &params); err != nil || params.ResourceType != "Parameters" {
+		return h.outcome(c, fiber.StatusBadRequest, "structure", "Body must be a Parameters resource")
+	}
+
+	var (
+		resource    *fhir.Patient
+		onlyCertain bool
+		count       = 10
+	)
+	for _, p := range params.Parameter {
+		switch p.Name {
+		case "resource":
+			resource = &fhir.Patient{}
+			if err := json.Unmarshal(p.Resource, resource); err != nil {
+				return h.outcome(c, fiber.StatusBadRequest, "structure", "Invalid resource parameter")
+			}
+		case "onlyCertainMatches":
+			onlyCertain = p.ValueBoolean != nil && *p.ValueBoolean
+		case "count":
+			if p.ValueInteger != nil && *p.ValueInteger > 0 {
+				count = *p.ValueInteger
+			}
+		}
+	}
+	if resource == nil {
+		return h.outcome(c, fiber.StatusBadRequest, "required", "The resource parameter is required")
+	}
+
+	input, err := h.mapperFor(c).ToDomain(resource)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	name := strings.TrimSpace(input.FirstName + " " + input.LastName)
+	if input.NIK == "" && input.MedicalRecordNo == "" && name == "" {
+		return h.outcome(c, fiber.StatusBadRequest, "required", "The resource must contain a NIK or MRN identifier or a name")
+	}
+
+	type match struct {
+		patient *domain.Patient
+		score   float64
+		grade   string
+	}
+	matches := make(map[string]*match)
+	add := func(patient *domain.Patient, nameScore float64) {
+		score, grade := fhir.MatchScore(input, patient, nameScore)
+		if grade == "" {
+			return
+		}
+		if m, ok := matches[patient.ID]; !ok || score > m.score {
+			matches[patient.ID] = &match{patient: patient, score: score, grade: grade}
+		}
+	}
+
+	if input.NIK != "" {
+		if patient, err := h.patientService.GetPatientByNIK(c.Context(), input.NIK); err == nil {
+			add(patient, 0)
+		}
+	}
+	if input.MedicalRecordNo != "" {
+		if patient, err := h.patientService.GetPatientByMedicalRecordNo(c.Context(), input.MedicalRecordNo); err == nil {
+			add(patient, 0)
+		}
+	}
+
+	if len([]rune(name)) >= 2 {
+		found, err := h.searchService.SearchPatients(c.Context(), name, 50)
+		if err == domain.ErrSearchUnavailable && len(matches) == 0 {
+			return h.outcome(c, fiber.StatusServiceUnavailable, "transient", "Search index is loading, try again shortly")
+		}
+		if err != nil && err != domain.ErrSearchUnavailable {
+			return h.error(c, err)
+		}
+		for _, m := range found {
+			add(m.Patient, m.Score)
+		}
+	}
+
+	ranked := make([]*match, 0, len(matches))
+	for _, m := range matches {
+		if onlyCertain && m.grade != fhir.GradeCertain {
+			continue
+		}
+		ranked = append(ranked, m)
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+	if len(ranked) > count {
+		ranked = ranked[:count]
+	}
+
+	bundle := fhir.NewSearchSet()
+	total := len(ranked)
+	bundle.Total = &total
+	for _, m := range ranked {
+		score := m.score
+		bundle.Entry = append(bundle.Entry, fhir.BundleEntry{
+			FullURL:  h.base(c) + "/Patient/" + m.patient.ID,
+			Resource: h.mapperFor(c).FromDomain(m.patient),
+			Search: &fhir.BundleSearch{
+				Mode:      "match",
+				Score:     &score,
+				Extension: []fhir.Extension{{URL: fhir.ExtensionMatchGrade, ValueCode: m.grade}},
+			},
+		})
+	}
+
+	return c.JSON(bundle, fhir.ContentType)
+}
+
+// mapperFor returns the mapper for the tenant of the request.
+func (h *FHIRHandler) mapperFor(c *fiber.Ctx) fhir.Mapper {
+	mapper := h.mapper
+	tenantID, _ := c.Locals(tenant.ContextKey).(string)
+	if t, ok := h.tenants.Get(tenantID); ok && t.MRNSystem != "" {
+		mapper.MRNSystem = t.MRNSystem
+	}
+	return mapper
+}
+
+// lookup finds a patient by logical id or by a "system|value" identifier.
+// An identifier without system matches NIK or MRN.
+func (h *FHIRHandler) lookup(c *fiber.Ctx, id, identifier string) (*domain.Patient, error) {
+	if id != "" {
+		return h.patientService.GetPatient(c.Context(), id)
+	}
+
+	system, value := "", identifier
+	if i := strings.Index(identifier, "|"); i >= 0 {
+		system, value = identifier[:i], identifier[i+1:]
+	}
+	if value == "" {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	switch system {
+	case fhir.SystemNIK:
+		return h.patientService.GetPatientByNIK(c.Context(), value)
+	case h.mapperFor(c).MRNSystem:
+		return h.patientService.GetPatientByMedicalRecordNo(c.Context(), value)
+	case "":
+		patient, err := h.patientService.GetPatientByNIK(c.Context(), value)
+		if err == domain.ErrPatientNotFound {
+			return h.patientService.GetPatientByMedicalRecordNo(c.Context(), value)
+		}
+		return patient, err
+	}
+	return nil, domain.ErrPatientNotFound
+}
+
+// matchesFilter applies the gender and birthdate parameters to a lookup result.
+func matchesFilter(patient *domain.Patient, filter domain.PatientFilter) bool {
+	if len(filter.Genders) > 0 && patient.Gender != filter.Genders[0] {
+		return false
+	}
+	if filter.DateOfBirth != nil && patient.DateOfBirth.Format("2006-01-02") != filter.DateOfBirth.Format("2006-01-02") {
+		return false
+	}
+	if filter.GivenName != "" && !hasWordPrefix(patient.FirstName, filter.GivenName) {
+		return false
+	}
+	if filter.FamilyName != "" && !hasWordPrefix(patient.LastName, filter.FamilyName) {
+		return false
+	}
+	return true
+}
+
+// hasWordPrefix reports whether a word of s starts with prefix, ignoring
+// case.
+func hasWordPrefix(s, prefix string) bool {
+	prefix = strings.ToLower(prefix)
+	for _, word := range strings.Fields(strings.ToLower(s)) {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *FHIRHandler) searchSet(c *fiber.Ctx, query url.Values, patients []*domain.Patient, total *int, nextCursor string) *fhir.Bundle {
+	base := h.base(c)
+
+	bundle := fhir.NewSearchSet()
+	bundle.Total = total
+	bundle.Link = []fhir.BundleLink{{Relation: "self", URL: base + "/Patient?" + query.Encode()}}
+
+	if nextCursor != "" {
+		next := url.Values{}
+		for k, v := range query {
+			next[k] = v
+		}
+		next.Set("_cursor", nextCursor)
+		bundle.Link = append(bundle.Link, fhir.BundleLink{Relation: "next", URL: base + "/Patient?" + next.Encode()})
+	}
+
+	for _, p := range patients {
+		bundle.Entry = append(bundle.Entry, fhir.BundleEntry{
+			FullURL:  base + "/Patient/" + p.ID,
+			Resource: h.mapperFor(c).FromDomain(p),
+			Search:   &fhir.BundleSearch{Mode: "match"},
+		})
+	}
+	return bundle
+}
+
+func (h *FHIRHandler) base(c *fiber.Ctx) string {
+	if h.baseURL != "" {
+		return h.baseURL
+	}
+	return c.BaseURL() + "/fhir/R4"
+}
+
+func (h *FHIRHandler) setVersionHeaders(c *fiber.Ctx, patient *domain.Patient) {
+	if !patient.UpdatedAt.IsZero() {
+		c.Set(fiber.HeaderLastModified, patient.UpdatedAt.UTC().Format(time.RFC1123))
+	}
+}
+
+// error maps service and mapping errors to an OperationOutcome.
+func (h *FHIRHandler) error(c *fiber.Ctx, err error) error {
+	if err == domain.ErrPatientNotFound {
+		return h.outcome(c, fiber.StatusNotFound, "not-found", "Patient not found")
+	}
+	if err == domain.ErrInvalidInput || errors.Is(err, fhir.ErrInvalidResource) {
+		return h.outcome(c, fiber.StatusBadRequest, "invalid", err.Error())
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		switch customErr.Code {
+		case "PATIENT_EXISTS", "NIK_EXISTS":
+			return h.outcome(c, fiber.StatusConflict, "duplicate", customErr.Message)
+		case "INVALID_CURSOR":
+			return h.outcome(c, fiber.StatusBadRequest, "invalid", customErr.Message)
+		}
+		return h.outcome(c, fiber.StatusUnprocessableEntity, "business-rule", customErr.Message)
+	}
+	return h.outcome(c, fiber.StatusInternalServerError, "exception", err.Error())
+}
+
+func (h *FHIRHandler) outcome(c *fiber.Ctx, status int, code, diagnostics string) error {
+	return c.Status(status).JSON(fhir.NewOperationOutcome(code, diagnostics), fhir.ContentType)
+}
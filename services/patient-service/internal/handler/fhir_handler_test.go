@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"testing"
+
+	"patient-service/internal/domain"
+)
+
+func TestMatchesFilterNameParts(t *testing.T) {
+	patient := &domain.Patient{FirstName: "Budi Agus", LastName: "Santoso"}
+
+	tests := []struct {
+		filter domain.PatientFilter
+		want   bool
+	}{
+		{domain.PatientFilter{GivenName: "budi"}, true},
+		{domain.PatientFilter{GivenName: "Agu"}, true},
+		{domain.PatientFilter{GivenName: "Santoso"}, false},
+		{domain.PatientFilter{FamilyName: "santo"}, true},
+		{domain.PatientFilter{FamilyName: "Budi"}, false},
+		{domain.PatientFilter{FamilyName: "toso"}, false},
+		{domain.PatientFilter{GivenName: "Budi", FamilyName: "Santoso"}, true},
+	}
+	for _, tt := range tests {
+		if got := matchesFilter(patient, tt.filter); got != tt.want {
+			t.Errorf("given=%q family=%q: got %v, want %v", tt.filter.GivenName, tt.filter.FamilyName, got, tt.want)
+		}
+	}
+}
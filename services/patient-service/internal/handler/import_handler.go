@@ -0,0 +1,161 @@
+// Bulk patient import handlers
+// internal/handler/import_handler.go
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/internal/service"
+	"patient-service/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImportHandler struct {
+	importService service.ImportService
+	maxUpload     int64
+}
+
+// NewImportHandler creates the handler; maxUpload is the largest file
+// accepted, in bytes.
+func NewImportHandler(importService service.ImportService, maxUpload int64) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+		maxUpload:     maxUpload,
+	}
+}
+
+// StartImport godoc
+// @Summary Import patients from a CSV or XLSX file
+// @Description Queue a bulk import. Columns are matched to patient fields by header name unless mapped explicitly. Rows are validated like POST /patients; rows whose NIK is already registered or repeated in the file are rejected. A dry run only validates.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param file formData file true "CSV or XLSX file, header in the first row"
+// @Param mapping formData string false "JSON object of patient field to column header, e.g. {\"nik\":\"No KTP\"}"
+// @Param dry_run formData bool false "Validate only"
+// @Success 202 {object} dto.ImportJobResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/imports [post]
+func (h *ImportHandler) StartImport(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "A file is required", err.Error())
+	}
+	if fileHeader.Size > h.maxUpload {
+		return utils.ErrorResponse(c, fiber.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "File is too large",
+			fmt.Sprintf("maximum is %d MB", h.maxUpload>>20))
+	}
+
+	job := &domain.ImportJob{FileName: fileHeader.Filename}
+
+	if mapping := c.FormValue("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &job.Mapping); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_MAPPING", "Mapping must be a JSON object of field to column", err.Error())
+		}
+	}
+	if dryRun := c.FormValue("dry_run"); dryRun != "" {
+		if job.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "dry_run must be true or false", "")
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Failed to read file", err.Error())
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Failed to read file", err.Error())
+	}
+
+	job.CreatedBy = c.Locals("userID").(string)
+
+	created, err := h.importService.StartImport(c.Context(), job, content)
+	if err != nil {
+		return h.error(c, err, "IMPORT_FAILED", "Failed to start import")
+	}
+
+	c.Location(h.jobURL(c, created.ID))
+	return c.Status(fiber.StatusAccepted).JSON(dto.ToImportJobResponse(created, h.reportURL(c, created.ID)))
+}
+
+// GetImport godoc
+// @Summary Get an import job
+// @Description Status and progress of a bulk import, with the link to its error report once rows were rejected.
+// @Tags imports
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Import job ID"
+// @Success 200 {object} dto.ImportJobResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/imports/{id} [get]
+func (h *ImportHandler) GetImport(c *fiber.Ctx) error {
+	job, err := h.importService.GetImport(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get import")
+	}
+	return c.JSON(dto.ToImportJobResponse(job, h.reportURL(c, job.ID)))
+}
+
+// GetErrorReport godoc
+// @Summary Download the rejected rows of an import
+// @Description CSV of the rejected rows as uploaded, followed by the row number, error code, field and message. Rows rejected so far are included while the job runs.
+// @Tags imports
+// @Produce text/csv
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Import job ID"
+// @Success 200 {file} file
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/imports/{id}/errors [get]
+func (h *ImportHandler) GetErrorReport(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var buf bytes.Buffer
+	if err := h.importService.WriteErrorReport(c.Context(), id, &buf); err != nil {
+		return h.error(c, err, "REPORT_FAILED", "Failed to build error report")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Attachment(fmt.Sprintf("import-%s-errors.csv", id))
+	return c.Send(buf.Bytes())
+}
+
+func (h *ImportHandler) jobURL(c *fiber.Ctx, id string) string {
+	return c.BaseURL() + "/api/v1/patients/imports/" + id
+}
+
+func (h *ImportHandler) reportURL(c *fiber.Ctx, id string) string {
+	return h.jobURL(c, id) + "/errors"
+}
+
+func (h *ImportHandler) error(c *fiber.Ctx, err error, code, message string) error {
+	switch err {
+	case domain.ErrImportNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Import not found", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Import ID is required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, code, message, err.Error())
+}
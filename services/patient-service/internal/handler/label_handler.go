@@ -0,0 +1,98 @@
+// Patient card and wristband handlers
+// internal/handler/label_handler.go
+package handler
+
+import (
+	"fmt"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/label"
+	"patient-service/internal/service"
+	"patient-service/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LabelHandler struct {
+	labelService service.LabelService
+}
+
+func NewLabelHandler(labelService service.LabelService) *LabelHandler {
+	return &LabelHandler{labelService: labelService}
+}
+
+// GetCard godoc
+// @Summary Render a patient card
+// @Description Patient identification card with name, MRN, date of birth, gender, blood type and a QR code or barcode of the MRN, laid out by the hospital's template.
+// @Tags labels
+// @Produce application/pdf,image/png
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param format query string false "pdf (default) or png"
+// @Param template query string false "Template name, default when empty"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/card [get]
+func (h *LabelHandler) GetCard(c *fiber.Ctx) error {
+	return h.render(c, label.KindCard)
+}
+
+// GetWristband godoc
+// @Summary Render a patient wristband
+// @Description Wristband label with the patient's identifiers and a QR code or barcode of the MRN, as ZPL for Zebra printers or as PDF.
+// @Tags labels
+// @Produce application/x-zpl,application/pdf
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param format query string false "zpl (default) or pdf"
+// @Param template query string false "Template name, default when empty"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/wristband [get]
+func (h *LabelHandler) GetWristband(c *fiber.Ctx) error {
+	return h.render(c, label.KindWristband)
+}
+
+// render sends the label in the requested format, the kind's first
+// format by default.
+func (h *LabelHandler) render(c *fiber.Ctx, kind string) error {
+	format := c.Query("format", label.Formats[kind][0])
+	data, err := h.labelService.RenderLabel(c.Context(), c.Params("id"), kind, c.Query("template"), format)
+	if err != nil {
+		return h.error(c, err)
+	}
+
+	disposition := "inline"
+	if format == label.FormatZPL {
+		disposition = "attachment"
+	}
+	c.Set(fiber.HeaderContentType, label.ContentTypes[format])
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`%s; filename="%s-%s.%s"`, disposition, kind, c.Params("id"), format))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.Send(data)
+}
+
+func (h *LabelHandler) error(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrPatientNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Patient not found", "")
+	case domain.ErrPatientDeceased:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "PATIENT_DECEASED", "Patient is recorded as deceased", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Patient ID is required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "RENDER_FAILED", "Failed to render label", err.Error())
+}
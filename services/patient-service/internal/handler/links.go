@@ -0,0 +1,43 @@
+// Cross-facility patient links
+// internal/handler/links.go
+package handler
+
+import (
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetPatientLinks godoc
+// @Summary List the patient's records at other facilities
+// @Description Records of the same person at other tenants, linked by the enterprise ID assigned from the NIK. Only identifying fields of the other records are returned.
+// @Tags patients
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Success 200 {object} dto.ListPatientLinksResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/links [get]
+func (h *PatientHandler) GetPatientLinks(c *fiber.Ctx) error {
+	links, err := h.patientService.GetPatientLinks(c.Context(), c.Params("id"))
+	if err != nil {
+		switch err {
+		case domain.ErrPatientNotFound:
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Patient not found", "")
+		case domain.ErrInvalidInput:
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Patient ID is required", "")
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "GET_FAILED", "Failed to get patient links", err.Error())
+	}
+
+	resp := dto.ListPatientLinksResponse{Data: make([]*dto.PatientLinkResponse, 0, len(links))}
+	for _, link := range links {
+		resp.Data = append(resp.Data, dto.ToPatientLinkResponse(link))
+	}
+	return c.JSON(resp)
+}
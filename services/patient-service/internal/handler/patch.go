@@ -0,0 +1,111 @@
+// Partial updates with JSON Merge Patch and JSON Patch
+// internal/handler/patch.go
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/pkg/jsonpatch"
+	"patient-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// patchFormats maps the supported PATCH media types to their algorithm.
+// Plain application/json is treated as a merge patch.
+var patchFormats = map[string]func(doc, patch []byte) ([]byte, error){
+	"application/merge-patch+json": jsonpatch.MergePatch,
+	"application/json":             jsonpatch.MergePatch,
+	"application/json-patch+json":  jsonpatch.Apply,
+}
+
+// PatchPatient godoc
+// @Summary Partially update patient
+// @Description Apply an RFC 7396 merge patch (application/merge-patch+json) or an RFC 6902 JSON Patch (application/json-patch+json) to the patient. Only supplied fields are changed; the result is validated like a full update.
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param request body dto.UpdatePatientRequest true "Fields to change; null clears an optional field"
+// @Success 200 {object} dto.PatientResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 415 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id} [patch]
+func (h *PatientHandler) PatchPatient(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Patient ID is required", "")
+	}
+
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(c.Get(fiber.HeaderContentType), ";")[0]))
+	if mediaType == "" {
+		mediaType = "application/merge-patch+json"
+	}
+	applyPatch, ok := patchFormats[mediaType]
+	if !ok {
+		return utils.ErrorResponse(c, fiber.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE",
+			"Unsupported patch format", "Use application/merge-patch+json or application/json-patch+json")
+	}
+
+	body := c.Body()
+	userID := c.Locals("userID").(string)
+
+	patched, err := h.patientService.PatchPatient(c.Context(), id, func(patient *domain.Patient) error {
+		doc, err := json.Marshal(dto.ToPatchDocument(patient))
+		if err != nil {
+			return err
+		}
+
+		merged, err := applyPatch(doc, body)
+		if err != nil {
+			if errors.Is(err, jsonpatch.ErrTestFailed) {
+				return err
+			}
+			return domain.NewCustomError("INVALID_PATCH", "Invalid patch document", err.Error())
+		}
+
+		// Fields outside the document (id, medical_record_no, ...) are read-only
+		var req dto.UpdatePatientRequest
+		decoder := json.NewDecoder(bytes.NewReader(merged))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			return domain.NewCustomError("INVALID_PATCH", "Invalid patch document", err.Error())
+		}
+
+		if err := h.validator.Struct(&req); err != nil {
+			return err
+		}
+
+		dto.ApplyPatchDocument(patient, &req)
+		patient.UpdatedBy = userID
+		return nil
+	})
+	if err != nil {
+		var validationErrs validator.ValidationErrors
+		switch {
+		case err == domain.ErrPatientNotFound:
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Patient not found", "")
+		case errors.Is(err, jsonpatch.ErrTestFailed):
+			return utils.ErrorResponse(c, fiber.StatusConflict, "PATCH_TEST_FAILED", "Patch test operation failed", err.Error())
+		case errors.As(err, &validationErrs):
+			return utils.ValidationErrorResponse(c, err)
+		}
+		if customErr, ok := err.(*domain.CustomError); ok {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "UPDATE_FAILED", "Failed to update patient", err.Error())
+	}
+
+	return c.JSON(dto.ToPatientResponse(patched))
+}
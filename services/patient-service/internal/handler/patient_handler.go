@@ -8,6 +8,7 @@ import (
 	"patient-service/internal/dto"
 	"patient-service/internal/service"
 	"patient-service/pkg/utils"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -16,13 +17,17 @@ import (
 type PatientHandler struct {
 	patientService service.PatientService
 	validator      *validator.Validate
+	expansions     map[string]Expansion
 }
 
 func NewPatientHandler(patientService service.PatientService, validator *validator.Validate) *PatientHandler {
-	return &PatientHandler{
+	h := &PatientHandler{
 		patientService: patientService,
 		validator:      validator,
+		expansions:     make(map[string]Expansion),
 	}
+	h.RegisterExpansion("audit", AuditExpansion(nil))
+	return h
 }
 
 // CreatePatient godoc
@@ -80,7 +85,10 @@ func (h *PatientHandler) CreatePatient(c *fiber.Ctx) error {
 // @Produce json
 // @Param Authorization header string true "Bearer token"
 // @Param id path string true "Patient ID"
+// @Param fields query string false "Comma separated fields to return, e.g. id,first_name,last_name,medical_record_no"
+// @Param expand query string false "Comma separated sub-resources to include (audit)"
 // @Success 200 {object} dto.PatientResponse
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -91,7 +99,22 @@ func (h *PatientHandler) GetPatient(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Patient ID is required", "")
 	}
 
-	patient, err := h.patientService.GetPatient(c.Context(), id)
+	var req dto.GetPatientRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	opts, err := h.parseReadOptions(req.Fields, req.Expand)
+	if err != nil {
+		customErr := err.(*domain.CustomError)
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+
+	patient, err := h.patientService.GetPatientFields(c.Context(), id, opts.selectFields(h.expansions))
 	if err != nil {
 		if err == domain.ErrPatientNotFound {
 			return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Patient not found", "")
@@ -99,7 +122,127 @@ func (h *PatientHandler) GetPatient(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "GET_FAILED", "Failed to get patient", err.Error())
 	}
 
-	return c.JSON(dto.ToPatientResponse(patient))
+	rendered, err := h.renderPatients(c.Context(), []*domain.Patient{patient}, opts)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "GET_FAILED", "Failed to get patient", err.Error())
+	}
+
+	return c.JSON(rendered[0])
+}
+
+// BatchGetPatients godoc
+// @Summary Get patients by IDs, NIKs or medical record numbers
+// @Description Look up to 100 patients in one request. The body carries exactly one of ids, niks or medical_record_nos; keys without an active patient are listed in not_found.
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.BatchGetPatientsRequest true "Keys to look up"
+// @Param fields query string false "Comma separated fields to return, e.g. id,first_name,last_name,medical_record_no"
+// @Param expand query string false "Comma separated sub-resources to include (audit)"
+// @Success 200 {object} dto.BatchGetPatientsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients:batchGet [post]
+func (h *PatientHandler) BatchGetPatients(c *fiber.Ctx) error {
+	var req dto.BatchGetPatientsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	var query dto.GetPatientRequest
+	if err := c.QueryParser(&query); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+	if err := h.validator.Struct(&query); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	key, values, ok := batchKeys(&req)
+	if !ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Provide exactly one of ids, niks or medical_record_nos", "")
+	}
+
+	opts, err := h.parseReadOptions(query.Fields, query.Expand)
+	if err != nil {
+		customErr := err.(*domain.CustomError)
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+
+	patients, err := h.patientService.GetPatientsByKeys(c.Context(), key, values, opts.selectFields(h.expansions))
+	if err != nil {
+		if customErr, ok := err.(*domain.CustomError); ok {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "GET_FAILED", "Failed to get patients", err.Error())
+	}
+
+	// Return patients in request order
+	byKey := make(map[string]*domain.Patient, len(patients))
+	for _, p := range patients {
+		byKey[p.Key(key)] = p
+	}
+	found := make([]*domain.Patient, 0, len(patients))
+	notFound := []string{}
+	for _, value := range values {
+		if p, ok := byKey[value]; ok {
+			found = append(found, p)
+		} else {
+			notFound = append(notFound, value)
+		}
+	}
+
+	rendered, err := h.renderPatients(c.Context(), found, opts)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "GET_FAILED", "Failed to get patients", err.Error())
+	}
+
+	return c.JSON(dto.BatchGetPatientsResponse{
+		Data:     rendered,
+		NotFound: notFound,
+	})
+}
+
+// batchKeys returns the kind of key requested and its values, trimmed and
+// without duplicates. It reports false unless exactly one kind is given.
+func batchKeys(req *dto.BatchGetPatientsRequest) (domain.PatientKey, []string, bool) {
+	var (
+		key    domain.PatientKey
+		values []string
+		kinds  int
+	)
+	for _, candidate := range []struct {
+		key    domain.PatientKey
+		values []string
+	}{
+		{domain.KeyID, req.IDs},
+		{domain.KeyNIK, req.NIKs},
+		{domain.KeyMedicalRecordNo, req.MedicalRecordNos},
+	} {
+		if len(candidate.values) > 0 {
+			key, values = candidate.key, candidate.values
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return "", nil, false
+	}
+
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return key, unique, true
 }
 
 // UpdatePatient godoc
@@ -196,13 +339,32 @@ func (h *PatientHandler) DeletePatient(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer token"
-// @Param search query string false "Search by name, NIK, or medical record number"
-// @Param city query string false "Filter by city"
-// @Param province query string false "Filter by province"
+// @Param search query string false "Search by name or medical record number, or exact NIK"
+// @Param city query string false "Filter by city, comma separated for multiple"
+// @Param province query string false "Filter by province, comma separated for multiple"
+// @Param gender query string false "Filter by gender (MALE, FEMALE), comma separated"
+// @Param blood_type query string false "Filter by blood type, comma separated"
+// @Param insurance_provider query string false "Filter by insurance provider, comma separated"
+// @Param created_by query string false "Filter by creating user ID, comma separated"
+// @Param dob query string false "Exact date of birth (YYYY-MM-DD)"
+// @Param age_min query int false "Minimum age in years"
+// @Param age_max query int false "Maximum age in years"
+// @Param created_from query string false "Created on or after (YYYY-MM-DD or RFC 3339)"
+// @Param created_to query string false "Created on or before (YYYY-MM-DD inclusive, or RFC 3339 exclusive)"
+// @Param updated_from query string false "Updated on or after (YYYY-MM-DD or RFC 3339)"
+// @Param updated_to query string false "Updated on or before (YYYY-MM-DD inclusive, or RFC 3339 exclusive)"
+// @Param deceased query bool false "Only deceased (true) or living (false) patients"
+// @Param deceased_from query string false "Died on or after (YYYY-MM-DD or RFC 3339)"
+// @Param deceased_to query string false "Died on or before (YYYY-MM-DD inclusive, or RFC 3339 exclusive)"
+// @Param enterprise_id query string false "Enterprise ID shared by the person's records at all facilities"
 // @Param page query int false "Page number (default: 1)"
 // @Param limit query int false "Items per page (default: 10, max: 100)"
-// @Param sort query string false "Sort field (created_at, updated_at, first_name, last_name, nik)"
+// @Param sort query string false "Sort field (created_at, updated_at, first_name, last_name)"
 // @Param order query string false "Sort order (ASC, DESC)"
+// @Param cursor query string false "Opaque cursor from next_cursor/prev_cursor; replaces page"
+// @Param include_total query bool false "Count total records (default: true)"
+// @Param fields query string false "Comma separated fields to return"
+// @Param expand query string false "Comma separated sub-resources to include (audit)"
 // @Success 200 {object} dto.ListPatientsResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
@@ -224,67 +386,72 @@ func (h *PatientHandler) ListPatients(c *fiber.Ctx) error {
 		req.Limit = 10
 	}
 
+	// "+" in an unencoded query string arrives as a space (?blood_type=A+)
+	req.BloodType = strings.ReplaceAll(req.BloodType, " ", "+")
+
 	// Validate request
 	if err := h.validator.Struct(&req); err != nil {
 		return utils.ValidationErrorResponse(c, err)
 	}
 
 	// Create filter
-	filter := domain.PatientFilter{
-		Search:   req.Search,
-		City:     req.City,
-		Province: req.Province,
-		IsActive: req.IsActive,
-		Page:     req.Page,
-		Limit:    req.Limit,
-		Sort:     req.Sort,
-		Order:    req.Order,
+	filter, err := dto.ToPatientFilter(&req)
+	if err != nil {
+		customErr := err.(*domain.CustomError)
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+
+	opts, err := h.parseReadOptions(req.Fields, req.Expand)
+	if err != nil {
+		customErr := err.(*domain.CustomError)
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	filter.Fields = opts.selectFields(h.expansions)
+
+	if req.Cursor != "" {
+		cursor, err := domain.DecodeCursor(req.Cursor)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_CURSOR", "Invalid pagination cursor", "")
+		}
+		filter.Cursor = cursor
 	}
 
 	// Get patients
-	patients, total, err := h.patientService.ListPatients(c.Context(), filter)
+	page, err := h.patientService.ListPatients(c.Context(), filter)
 	if err != nil {
+		if customErr, ok := err.(*domain.CustomError); ok {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+		}
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "LIST_FAILED", "Failed to list patients", err.Error())
 	}
 
 	// Convert to response
-	patientResponses := make([]*dto.PatientResponse, len(patients))
-	for i, patient := range patients {
-		patientResponses[i] = dto.ToPatientResponse(patient)
+	patientResponses, err := h.renderPatients(c.Context(), page.Patients, opts)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "LIST_FAILED", "Failed to list patients", err.Error())
 	}
 
 	// Create pagination response
-	totalPages := int(math.Ceil(float64(total) / float64(req.Limit)))
-
-	response := dto.ListPatientsResponse{
-		Data: patientResponses,
-		Pagination: dto.PaginationResponse{
-			Page:       req.Page,
-			Limit:      req.Limit,
-			Total:      total,
-			TotalPages: totalPages,
-		},
+	pagination := dto.PaginationResponse{
+		Limit:      req.Limit,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
 	}
 
-	return c.JSON(response)
-}
-
-// GetPatientPublicInfo - Handler function tanpa struct
-func GetPatientPublicInfo(patientService service.PatientService) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		id := c.Params("id")
-		if id == "" {
-			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Patient ID is required", "")
-		}
+	if filter.Cursor == nil {
+		pagination.Page = req.Page
+	}
 
-		patient, err := patientService.GetPatientPublicInfo(c.Context(), id)
-		if err != nil {
-			if err == domain.ErrPatientNotFound {
-				return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Patient not found", "")
-			}
-			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "GET_FAILED", "Failed to get patient info", err.Error())
-		}
+	if page.Total != nil {
+		totalPages := int(math.Ceil(float64(*page.Total) / float64(req.Limit)))
+		pagination.TotalPages = &totalPages
+	}
 
-		return c.JSON(patient)
+	response := dto.ListPatientsResponse{
+		Data:       patientResponses,
+		Pagination: pagination,
 	}
+
+	return c.JSON(response)
 }
@@ -0,0 +1,193 @@
+// Public patient profile token handlers
+// internal/handler/qr_token_handler.go
+package handler
+
+import (
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/internal/service"
+	"patient-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PublicProfilePath is the unauthenticated path tokens resolve on.
+const PublicProfilePath = "/api/v1/public/patients/"
+
+type QRTokenHandler struct {
+	tokenService service.QRTokenService
+	validator    *validator.Validate
+}
+
+func NewQRTokenHandler(tokenService service.QRTokenService, validator *validator.Validate) *QRTokenHandler {
+	return &QRTokenHandler{
+		tokenService: tokenService,
+		validator:    validator,
+	}
+}
+
+// IssueToken godoc
+// @Summary Issue a public profile token
+// @Description Create a signed, expiring token resolving to the patient's minimal profile, e.g. to print as a QR code on a wristband.
+// @Tags qr-tokens
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param request body dto.IssueQRTokenRequest false "Validity"
+// @Success 201 {object} dto.QRTokenResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/qr-tokens [post]
+func (h *QRTokenHandler) IssueToken(c *fiber.Ctx) error {
+	var req dto.IssueQRTokenRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+		}
+	}
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	ttl := time.Duration(req.TTLHours) * time.Hour
+	token, tokenString, err := h.tokenService.IssueToken(c.Context(), c.Params("id"), ttl, c.Locals("userID").(string))
+	if err != nil {
+		return h.error(c, err, "ISSUE_FAILED", "Failed to issue token")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToQRTokenResponse(token, tokenString, h.url(c, tokenString), time.Now()))
+}
+
+// ListTokens godoc
+// @Summary List a patient's public profile tokens
+// @Description List issued tokens, expired and revoked ones included, with their scan counts.
+// @Tags qr-tokens
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Success 200 {object} dto.ListQRTokensResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/qr-tokens [get]
+func (h *QRTokenHandler) ListTokens(c *fiber.Ctx) error {
+	tokens, err := h.tokenService.ListTokens(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list tokens")
+	}
+
+	now := time.Now()
+	resp := dto.ListQRTokensResponse{Data: make([]*dto.QRTokenResponse, 0, len(tokens))}
+	for _, token := range tokens {
+		tokenString := h.tokenService.TokenString(token)
+		resp.Data = append(resp.Data, dto.ToQRTokenResponse(token, tokenString, h.url(c, tokenString), now))
+	}
+	return c.JSON(resp)
+}
+
+// RevokeToken godoc
+// @Summary Revoke a public profile token
+// @Description The token no longer resolves, e.g. after the patient is discharged or a wristband is lost.
+// @Tags qr-tokens
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param tokenId path string true "Token ID"
+// @Success 200 {object} dto.QRTokenResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/qr-tokens/{tokenId}/revoke [post]
+func (h *QRTokenHandler) RevokeToken(c *fiber.Ctx) error {
+	token, err := h.tokenService.RevokeToken(c.Context(), c.Params("id"), c.Params("tokenId"), c.Locals("userID").(string))
+	if err != nil {
+		return h.error(c, err, "REVOKE_FAILED", "Failed to revoke token")
+	}
+
+	tokenString := h.tokenService.TokenString(token)
+	return c.JSON(dto.ToQRTokenResponse(token, tokenString, h.url(c, tokenString), time.Now()))
+}
+
+// ListScans godoc
+// @Summary List the scans of a public profile token
+// @Description Audit trail of the token's latest scans, failed ones included.
+// @Tags qr-tokens
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Patient ID"
+// @Param tokenId path string true "Token ID"
+// @Success 200 {object} dto.ListQRScansResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/patients/{id}/qr-tokens/{tokenId}/scans [get]
+func (h *QRTokenHandler) ListScans(c *fiber.Ctx) error {
+	scans, err := h.tokenService.ListScans(c.Context(), c.Params("id"), c.Params("tokenId"))
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list scans")
+	}
+
+	resp := dto.ListQRScansResponse{Data: make([]*dto.QRScanResponse, 0, len(scans))}
+	for _, scan := range scans {
+		resp.Data = append(resp.Data, dto.ToQRScanResponse(scan))
+	}
+	return c.JSON(resp)
+}
+
+// ResolveToken godoc
+// @Summary Get a patient's public profile
+// @Description Resolve a token from a QR code to the patient's minimal profile. No bearer token is needed; every scan is audited and requests are rate limited per client.
+// @Tags qr-tokens
+// @Produce json
+// @Param token path string true "Public profile token"
+// @Success 200 {object} dto.PublicPatientResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/public/patients/{token} [get]
+func (h *QRTokenHandler) ResolveToken(c *fiber.Ctx) error {
+	scan := &domain.QRScan{IP: c.IP(), UserAgent: c.Get(fiber.HeaderUserAgent)}
+	patient, err := h.tokenService.ResolveToken(c.Context(), c.Params("token"), scan)
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get patient info")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.JSON(dto.ToPublicPatientResponse(patient))
+}
+
+func (h *QRTokenHandler) url(c *fiber.Ctx, tokenString string) string {
+	return c.BaseURL() + PublicProfilePath + tokenString
+}
+
+func (h *QRTokenHandler) error(c *fiber.Ctx, err error, code, message string) error {
+	switch err {
+	case domain.ErrPatientNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Patient not found", "")
+	case domain.ErrPatientDeceased:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "PATIENT_DECEASED", "Patient is recorded as deceased", "")
+	case domain.ErrQRTokenNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Token not found", "")
+	case domain.ErrInvalidQRToken:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "INVALID_TOKEN", "Token is invalid, expired or revoked", "")
+	case domain.ErrQRTokenRevoked:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "ALREADY_REVOKED", "Token was already revoked", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Patient and token IDs are required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, code, message, err.Error())
+}
@@ -0,0 +1,78 @@
+// Search handlers
+// internal/handler/search_handler.go
+package handler
+
+import (
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/internal/service"
+	"patient-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchHandler struct {
+	searchService service.PatientSearchService
+	validator     *validator.Validate
+}
+
+func NewSearchHandler(searchService service.PatientSearchService, validator *validator.Validate) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+		validator:     validator,
+	}
+}
+
+// SearchPatients godoc
+// @Summary Search patients by name
+// @Description Fuzzy full-name search (any word order, typos and spelling variants) or exact medical record number, ranked by relevance
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param q query string true "Name or medical record number"
+// @Param limit query int false "Maximum results (default: 20, max: 50)"
+// @Success 200 {object} dto.SearchPatientsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/patients/search [get]
+func (h *SearchHandler) SearchPatients(c *fiber.Ctx) error {
+	var req dto.SearchPatientsRequest
+
+	// Parse query parameters
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters", err.Error())
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	// Validate request
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	matches, err := h.searchService.SearchPatients(c.Context(), req.Query, req.Limit)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidInput:
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_QUERY", "Search query must not be blank", "")
+		case domain.ErrSearchUnavailable:
+			return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "SEARCH_UNAVAILABLE", "Search index is still loading", "")
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "SEARCH_FAILED", "Failed to search patients", err.Error())
+	}
+
+	results := make([]*dto.PatientSearchResult, len(matches))
+	for i, m := range matches {
+		results[i] = &dto.PatientSearchResult{
+			Score:           m.Score,
+			PatientResponse: dto.ToPatientResponse(m.Patient),
+		}
+	}
+
+	return c.JSON(dto.SearchPatientsResponse{Data: results})
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/service"
+)
+
+func TestSearchPatientsBlankQuery(t *testing.T) {
+	// The real service, which rejects a query that is blank once trimmed
+	// before it needs the index
+	svc := service.NewPatientSearchService(nil, nil)
+	app := fiber.New()
+	app.Get("/search", NewSearchHandler(svc, validator.New()).SearchPatients)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/search?q="+url.QueryEscape("   "), nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if body.Error.Code != "INVALID_QUERY" {
+		t.Errorf("Expected INVALID_QUERY, got %q", body.Error.Code)
+	}
+}
+
+// stubSearch fails every search with err.
+type stubSearch struct {
+	err error
+}
+
+func (s stubSearch) SearchPatients(ctx context.Context, query string, limit int) ([]*domain.PatientMatch, error) {
+	return nil, s.err
+}
+
+func TestSearchPatientsErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{domain.ErrInvalidInput, fiber.StatusBadRequest},
+		{domain.ErrSearchUnavailable, fiber.StatusServiceUnavailable},
+		{context.DeadlineExceeded, fiber.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		app := fiber.New()
+		app.Get("/search", NewSearchHandler(stubSearch{tt.err}, validator.New()).SearchPatients)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/search?q=budi", nil))
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("%v: got %d, want %d", tt.err, resp.StatusCode, tt.want)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+// Webhook subscription handlers
+// internal/handler/webhook_handler.go
+package handler
+
+import (
+	"math"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/internal/service"
+	"patient-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+	validator      *validator.Validate
+}
+
+func NewWebhookHandler(webhookService service.WebhookService, validator *validator.Validate) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		validator:      validator,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook subscription
+// @Description Register a URL for patient events. The signing secret is only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.CreateWebhookRequest true "Subscription"
+// @Success 201 {object} dto.WebhookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	var req dto.CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	userID := c.Locals("userID").(string)
+	sub := dto.ToWebhookDomain(&req)
+	sub.CreatedBy = userID
+	sub.UpdatedBy = userID
+
+	created, err := h.webhookService.CreateWebhook(c.Context(), sub)
+	if err != nil {
+		return h.error(c, err, "CREATE_FAILED", "Failed to create webhook")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToWebhookResponse(created, true))
+}
+
+// ListWebhooks godoc
+// @Summary List webhook subscriptions
+// @Tags webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.ListWebhooksResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	subs, err := h.webhookService.ListWebhooks(c.Context())
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list webhooks")
+	}
+
+	resp := dto.ListWebhooksResponse{Data: make([]*dto.WebhookResponse, 0, len(subs))}
+	for _, sub := range subs {
+		resp.Data = append(resp.Data, dto.ToWebhookResponse(sub, false))
+	}
+	return c.JSON(resp)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook subscription
+// @Tags webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	sub, err := h.webhookService.GetWebhook(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get webhook")
+	}
+	return c.JSON(dto.ToWebhookResponse(sub, false))
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook subscription
+// @Description Replace the URL, event types and active state. Setting is_active re-enables a disabled subscription.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Webhook ID"
+// @Param request body dto.UpdateWebhookRequest true "Subscription"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	var req dto.UpdateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	sub := dto.ToUpdateWebhookDomain(c.Params("id"), &req)
+	sub.UpdatedBy = c.Locals("userID").(string)
+
+	updated, err := h.webhookService.UpdateWebhook(c.Context(), sub)
+	if err != nil {
+		return h.error(c, err, "UPDATE_FAILED", "Failed to update webhook")
+	}
+	return c.JSON(dto.ToWebhookResponse(updated, false))
+}
+
+// RotateWebhookSecret godoc
+// @Summary Rotate a webhook signing secret
+// @Description Generate a new signing secret, returned only in this response
+// @Tags webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id}/rotate-secret [post]
+func (h *WebhookHandler) RotateWebhookSecret(c *fiber.Ctx) error {
+	sub, err := h.webhookService.RotateWebhookSecret(c.Context(), c.Params("id"), c.Locals("userID").(string))
+	if err != nil {
+		return h.error(c, err, "ROTATE_FAILED", "Failed to rotate webhook secret")
+	}
+	return c.JSON(dto.ToWebhookResponse(sub, true))
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook subscription
+// @Description Delete the subscription and its delivery log
+// @Tags webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	if err := h.webhookService.DeleteWebhook(c.Context(), c.Params("id")); err != nil {
+		return h.error(c, err, "DELETE_FAILED", "Failed to delete webhook")
+	}
+	return c.JSON(dto.SuccessResponse{
+		Message: "Webhook deleted successfully",
+	})
+}
+
+// ListDeliveries godoc
+// @Summary List deliveries of a webhook
+// @Tags webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Filter by status (pending, delivered, dead)"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} dto.ListDeliveriesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	return h.listDeliveries(c, c.Params("id"), "")
+}
+
+// ListDeadLetters godoc
+// @Summary List dead-lettered deliveries
+// @Description Deliveries of all webhooks that failed every attempt
+// @Tags webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} dto.ListDeliveriesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/dead-letters [get]
+func (h *WebhookHandler) ListDeadLetters(c *fiber.Ctx) error {
+	return h.listDeliveries(c, "", domain.DeliveryDead)
+}
+
+func (h *WebhookHandler) listDeliveries(c *fiber.Ctx, subscriptionID, status string) error {
+	var req dto.ListDeliveriesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters", err.Error())
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	if status != "" {
+		req.Status = status
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	deliveries, total, err := h.webhookService.ListDeliveries(c.Context(), domain.DeliveryFilter{
+		SubscriptionID: subscriptionID,
+		Status:         req.Status,
+		Page:           req.Page,
+		Limit:          req.Limit,
+	})
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list deliveries")
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(req.Limit)))
+	resp := dto.ListDeliveriesResponse{
+		Data: make([]*dto.WebhookDeliveryResponse, 0, len(deliveries)),
+		Pagination: dto.PaginationResponse{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			Total:      &total,
+			TotalPages: &totalPages,
+		},
+	}
+	for _, d := range deliveries {
+		resp.Data = append(resp.Data, dto.ToDeliveryResponse(d, false))
+	}
+	return c.JSON(resp)
+}
+
+// GetDelivery godoc
+// @Summary Get a delivery with its payload and attempt log
+// @Tags webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 200 {object} dto.WebhookDeliveryResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id}/deliveries/{deliveryId} [get]
+func (h *WebhookHandler) GetDelivery(c *fiber.Ctx) error {
+	delivery, err := h.webhookService.GetDelivery(c.Context(), c.Params("id"), c.Params("deliveryId"))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get delivery")
+	}
+	return c.JSON(dto.ToDeliveryResponse(delivery, true))
+}
+
+// ReplayDelivery godoc
+// @Summary Replay a delivery
+// @Description Queue the delivery again with a fresh retry budget
+// @Tags webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} dto.WebhookDeliveryResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id}/deliveries/{deliveryId}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(c *fiber.Ctx) error {
+	delivery, err := h.webhookService.ReplayDelivery(c.Context(), c.Params("id"), c.Params("deliveryId"))
+	if err != nil {
+		return h.error(c, err, "REPLAY_FAILED", "Failed to replay delivery")
+	}
+	return c.Status(fiber.StatusAccepted).JSON(dto.ToDeliveryResponse(delivery, false))
+}
+
+func (h *WebhookHandler) error(c *fiber.Ctx, err error, code, message string) error {
+	switch err {
+	case domain.ErrWebhookNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Webhook not found", "")
+	case domain.ErrDeliveryNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Delivery not found", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Webhook ID is required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, code, message, err.Error())
+}
@@ -0,0 +1,95 @@
+// HL7 v2 acknowledgements
+// internal/hl7/ack.go
+package hl7
+
+import (
+	"fmt"
+	"time"
+)
+
+// Acknowledgement codes (MSA-1)
+const (
+	AckAccept           = "AA"
+	AckApplicationError = "AE" // processing failed, do not resend unchanged
+	AckReject           = "AR" // message rejected (unsupported or malformed)
+)
+
+// Error codes from HL7 table 0357 (ERR-3)
+const (
+	ErrSegmentSequence      = "100"
+	ErrRequiredFieldMissing = "101"
+	ErrDataType             = "102"
+	ErrTableValueNotFound   = "103"
+	ErrUnsupportedMessage   = "200"
+	ErrUnsupportedEvent     = "201"
+	ErrUnknownKey           = "204"
+	ErrDuplicateKey         = "205"
+	ErrRecordLocked         = "206"
+	ErrApplicationInternal  = "207"
+)
+
+// AckError is a processing error reported back in the ACK.
+type AckError struct {
+	Code string // table 0357
+	Text string
+}
+
+func (e *AckError) Error() string {
+	return fmt.Sprintf("HL7 error %s: %s", e.Code, e.Text)
+}
+
+// BuildACK builds the acknowledgement of msg. A nil err accepts it;
+// unsupported messages are rejected (AR) and other errors are application
+// errors (AE).
+func BuildACK(msg *Message, app Application, controlID string, err error) *Message {
+	_, event := msg.Type()
+
+	// The ACK goes back to the sender, so the applications swap places
+	var sendingApp, sendingFacility string
+	if msh := msg.Segment("MSH"); msh != nil {
+		sendingApp, sendingFacility = msh.Field(3), msh.Field(4)
+	}
+
+	version := "2.5"
+	if msh := msg.Segment("MSH"); msh != nil && msh.Field(12) != "" {
+		version = msh.Field(12)
+	}
+
+	ack := NewMessage(
+		app.SendingApplication, app.SendingFacility,
+		sendingApp, sendingFacility,
+		Timestamp(time.Now()), "",
+		msg.Delims.Components("ACK", event, "ACK"), controlID, "P", version,
+	)
+
+	if err == nil {
+		ack.Add("MSA", AckAccept, msg.Delims.EscapeText(msg.ControlID()))
+		return ack
+	}
+
+	ackErr, ok := err.(*AckError)
+	if !ok {
+		ackErr = &AckError{Code: ErrApplicationInternal, Text: err.Error()}
+	}
+
+	code := AckApplicationError
+	if ackErr.Code == ErrUnsupportedMessage || ackErr.Code == ErrUnsupportedEvent {
+		code = AckReject
+	}
+
+	d := ack.Delims
+	ack.Add("MSA", code, d.EscapeText(msg.ControlID()), d.EscapeText(ackErr.Text))
+	errSeg := ack.Add("ERR")
+	errSeg.Set(3, d.Components(ackErr.Code, ackErr.Text, "HL70357"))
+	errSeg.Set(4, "E")
+	return ack
+}
+
+// AckCode returns MSA-1 and MSA-3 of an acknowledgement.
+func AckCode(ack *Message) (string, string) {
+	msa := ack.Segment("MSA")
+	if msa == nil {
+		return "", ""
+	}
+	return msa.Component(1, 1), msa.Component(3, 1)
+}
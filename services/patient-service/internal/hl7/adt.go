@@ -0,0 +1,312 @@
+// ADT message mapping
+// internal/hl7/adt.go
+package hl7
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+// Identifier type codes (HL7 table 0203) used in PID-3 and MRG-1
+const (
+	IdentifierNIK = "NNIDN" // national person identifier
+	IdentifierMRN = "MR"
+)
+
+// Application identifies a system in MSH-3/4 and MSH-5/6.
+type Application struct {
+	SendingApplication   string
+	SendingFacility      string
+	ReceivingApplication string
+	ReceivingFacility    string
+}
+
+// Identifiers are the patient identifiers found in a CX list.
+type Identifiers struct {
+	NIK string
+	MRN string
+}
+
+// ParseIdentifiers reads the NIK and MRN from a CX field (PID-3, MRG-1).
+// The NIK is recognised by type code NNIDN or assigning authority NIK.
+func ParseIdentifiers(seg *Segment, field int) Identifiers {
+	var ids Identifiers
+	for _, rep := range seg.Repetitions(field) {
+		value := seg.ComponentOf(rep, 1)
+		authority := seg.ComponentOf(rep, 4)
+		switch typeCode := seg.ComponentOf(rep, 5); {
+		case typeCode == IdentifierNIK || strings.EqualFold(authority, "NIK"):
+			ids.NIK = value
+		case typeCode == IdentifierMRN:
+			ids.MRN = value
+		}
+	}
+	return ids
+}
+
+// PatientUpdate holds the patient fields an ADT message carries. Fields the
+// message leaves empty are absent; fields sent as "" are present and empty,
+// which clears the stored value.
+type PatientUpdate struct {
+	Identifiers
+	fields map[string]string
+	dob    *time.Time
+}
+
+// ParsePatient reads the PID segment and the optional NK1, IN1 and AL1
+// segments of an ADT message.
+func ParsePatient(msg *Message) (*PatientUpdate, error) {
+	pid := msg.Segment("PID")
+	if pid == nil {
+		return nil, &AckError{Code: ErrRequiredFieldMissing, Text: "PID segment is missing"}
+	}
+
+	u := &PatientUpdate{Identifiers: ParseIdentifiers(pid, 3), fields: make(map[string]string)}
+
+	u.set(pid, 5, "last_name", func() string { return familyName(pid, pid.Field(5)) })
+	u.set(pid, 5, "first_name", func() string {
+		return strings.TrimSpace(pid.Component(5, 2) + " " + pid.Component(5, 3))
+	})
+
+	if pid.Field(7) != "" && !pid.IsNull(7) {
+		dob, err := ParseDate(pid.Component(7, 1))
+		if err != nil {
+			return nil, &AckError{Code: ErrDataType, Text: "PID-7 must be a date (YYYYMMDD)"}
+		}
+		u.dob = &dob
+	}
+
+	if pid.Field(8) != "" {
+		switch pid.Component(8, 1) {
+		case "M":
+			u.fields["gender"] = "MALE"
+		case "F":
+			u.fields["gender"] = "FEMALE"
+		default:
+			return nil, &AckError{Code: ErrTableValueNotFound, Text: "PID-8 must be M or F"}
+		}
+	}
+
+	u.set(pid, 11, "address", func() string {
+		return strings.TrimSpace(pid.Component(11, 1) + " " + pid.Component(11, 2))
+	})
+	u.set(pid, 11, "city", func() string { return pid.Component(11, 3) })
+	u.set(pid, 11, "province", func() string { return pid.Component(11, 4) })
+	u.set(pid, 11, "postal_code", func() string { return pid.Component(11, 5) })
+
+	// PID-13 repeats; e-mail addresses use telecom use code NET
+	if pid.Field(13) != "" {
+		phone, email := "", ""
+		for _, rep := range pid.Repetitions(13) {
+			if rep == `""` {
+				continue
+			}
+			if pid.ComponentOf(rep, 2) == "NET" || pid.ComponentOf(rep, 3) == "Internet" {
+				if email == "" {
+					email = pid.ComponentOf(rep, 4)
+				}
+			} else if phone == "" {
+				phone = telephone(pid, rep)
+			}
+		}
+		u.fields["phone"] = phone
+		u.fields["email"] = email
+	}
+
+	if nk1 := msg.Segment("NK1"); nk1 != nil {
+		u.set(nk1, 2, "emergency_contact", func() string {
+			return strings.TrimSpace(nk1.Component(2, 2) + " " + familyName(nk1, nk1.Field(2)))
+		})
+		u.set(nk1, 5, "emergency_phone", func() string {
+			reps := nk1.Repetitions(5)
+			return telephone(nk1, reps[0])
+		})
+	}
+
+	if in1 := msg.Segment("IN1"); in1 != nil {
+		u.set(in1, 4, "insurance_provider", func() string { return in1.Component(4, 1) })
+		u.set(in1, 36, "insurance_number", func() string { return in1.Component(36, 1) })
+	}
+
+	// Each AL1 segment is one allergy; together they replace the list
+	if al1s := msg.All("AL1"); len(al1s) > 0 {
+		var allergies []string
+		for _, al1 := range al1s {
+			name := al1.Component(3, 2)
+			if name == "" {
+				name = al1.Component(3, 1)
+			}
+			if name != "" {
+				allergies = append(allergies, name)
+			}
+		}
+		u.fields["allergies"] = strings.Join(allergies, "; ")
+	}
+
+	return u, nil
+}
+
+// set records a field when the segment sends it: empty means absent and
+// "" means clear.
+func (u *PatientUpdate) set(seg *Segment, field int, name string, value func() string) {
+	switch {
+	case seg.IsNull(field):
+		u.fields[name] = ""
+	case seg.Field(field) != "":
+		u.fields[name] = value()
+	}
+}
+
+// Apply copies the fields the message carries onto the patient. The
+// medical record number is only applied to patients without one.
+func (u *PatientUpdate) Apply(patient *domain.Patient) {
+	if u.NIK != "" {
+		patient.NIK = u.NIK
+	}
+	if u.MRN != "" && patient.MedicalRecordNo == "" {
+		patient.MedicalRecordNo = u.MRN
+	}
+	if u.dob != nil {
+		patient.DateOfBirth = *u.dob
+	}
+
+	targets := map[string]*string{
+		"first_name":         &patient.FirstName,
+		"last_name":          &patient.LastName,
+		"gender":             &patient.Gender,
+		"phone":              &patient.Phone,
+		"email":              &patient.Email,
+		"address":            &patient.Address,
+		"city":               &patient.City,
+		"province":           &patient.Province,
+		"postal_code":        &patient.PostalCode,
+		"emergency_contact":  &patient.EmergencyContact,
+		"emergency_phone":    &patient.EmergencyPhone,
+		"insurance_provider": &patient.InsuranceProvider,
+		"insurance_number":   &patient.InsuranceNumber,
+		"allergies":          &patient.Allergies,
+	}
+	for name, value := range u.fields {
+		*targets[name] = value
+	}
+}
+
+// familyName returns the surname of an XPN field, whose first component
+// (FN) may carry subcomponents.
+func familyName(seg *Segment, raw string) string {
+	fn := strings.Split(strings.Split(raw, string(seg.delims.Component))[0], string(seg.delims.Subcomponent))[0]
+	return seg.delims.UnescapeText(fn)
+}
+
+// telephone returns the number of an XTN repetition, from XTN-1 or the
+// unformatted XTN-12.
+func telephone(seg *Segment, rep string) string {
+	if number := seg.ComponentOf(rep, 1); number != "" {
+		return number
+	}
+	return seg.ComponentOf(rep, 12)
+}
+
+// ADTOptions configures ADT messages built by BuildADT.
+type ADTOptions struct {
+	Application
+	ControlID string
+	Time      time.Time
+	// MergedMRN is the medical record number of the retired patient (A40)
+	MergedMRN string
+}
+
+// BuildADT builds an ADT^A04, A08 or A40 message for the patient.
+func BuildADT(event string, patient *domain.Patient, opts ADTOptions) *Message {
+	if opts.Time.IsZero() {
+		opts.Time = time.Now()
+	}
+	structure := "ADT_A01"
+	if event == "A40" {
+		structure = "ADT_A39"
+	}
+
+	msg := NewMessage(
+		opts.SendingApplication, opts.SendingFacility,
+		opts.ReceivingApplication, opts.ReceivingFacility,
+		Timestamp(opts.Time), "",
+		"ADT^"+event+"^"+structure, opts.ControlID, "P", "2.5",
+	)
+	d := msg.Delims
+
+	msg.Add("EVN", event, Timestamp(opts.Time))
+
+	var ids []string
+	if patient.MedicalRecordNo != "" {
+		ids = append(ids, d.Components(patient.MedicalRecordNo, "", "", opts.SendingFacility, IdentifierMRN))
+	}
+	if patient.NIK != "" {
+		ids = append(ids, d.Components(patient.NIK, "", "", "NIK", IdentifierNIK))
+	}
+
+	var telecom []string
+	if patient.Phone != "" {
+		telecom = append(telecom, d.Components(patient.Phone, "PRN", "PH"))
+	}
+	if patient.Email != "" {
+		telecom = append(telecom, d.Components("", "NET", "Internet", patient.Email))
+	}
+
+	gender := ""
+	switch patient.Gender {
+	case "MALE":
+		gender = "M"
+	case "FEMALE":
+		gender = "F"
+	}
+
+	dob := ""
+	if !patient.DateOfBirth.IsZero() {
+		dob = patient.DateOfBirth.Format("20060102")
+	}
+
+	pid := msg.Add("PID", "1")
+	pid.Set(3, strings.Join(ids, string(d.Repetition)))
+	pid.Set(5, d.Components(patient.LastName, patient.FirstName))
+	pid.Set(7, dob)
+	pid.Set(8, gender)
+	pid.Set(11, d.Components(patient.Address, "", patient.City, patient.Province, patient.PostalCode, "IDN"))
+	pid.Set(13, strings.Join(telecom, string(d.Repetition)))
+	if patient.IsDeceased() {
+		pid.Set(29, Timestamp(*patient.DeceasedAt))
+		pid.Set(30, "Y")
+	}
+
+	if event == "A40" {
+		msg.Add("MRG", d.Components(opts.MergedMRN, "", "", opts.SendingFacility, IdentifierMRN))
+		return msg
+	}
+
+	msg.Add("PV1", "1", "N")
+
+	if patient.EmergencyContact != "" || patient.EmergencyPhone != "" {
+		nk1 := msg.Add("NK1", "1")
+		nk1.Set(2, d.Components(patient.EmergencyContact))
+		nk1.Set(3, d.Components("C", "Emergency Contact", "HL70063"))
+		nk1.Set(5, d.Components(patient.EmergencyPhone))
+	}
+
+	if patient.InsuranceProvider != "" || patient.InsuranceNumber != "" {
+		in1 := msg.Add("IN1", "1")
+		in1.Set(4, d.Components(patient.InsuranceProvider))
+		in1.Set(36, d.EscapeText(patient.InsuranceNumber))
+	}
+
+	setID := 0
+	for _, allergy := range strings.Split(patient.Allergies, ";") {
+		if allergy = strings.TrimSpace(allergy); allergy != "" {
+			setID++
+			msg.Add("AL1", strconv.Itoa(setID), "", d.Components("", allergy))
+		}
+	}
+
+	return msg
+}
@@ -0,0 +1,158 @@
+// Outbound ADT emission
+// internal/hl7/emitter.go
+package hl7
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/mllp"
+	"patient-service/internal/service"
+	"patient-service/internal/tenant"
+)
+
+// Emitter decorates a PatientService and sends ADT^A04, A08 and A40
+// messages to a downstream MLLP receiver after successful writes. Messages
+// are queued and delivered in order by Run; when the queue is full new
+// events are dropped and logged so requests never block on the receiver.
+type Emitter struct {
+	service.PatientService
+	client  *mllp.Client
+	app     Application
+	tenants *tenant.Registry
+	queue   chan *Message
+}
+
+// NewEmitter creates an emitter. Messages name the HL7 facility of the
+// patient's tenant in MSH-4, or app's sending facility when it has none.
+func NewEmitter(next service.PatientService, client *mllp.Client, app Application, tenants *tenant.Registry, queueSize int) *Emitter {
+	return &Emitter{
+		PatientService: next,
+		client:         client,
+		app:            app,
+		tenants:        tenants,
+		queue:          make(chan *Message, queueSize),
+	}
+}
+
+func (e *Emitter) CreatePatient(ctx context.Context, patient *domain.Patient) (*domain.Patient, error) {
+	created, err := e.PatientService.CreatePatient(ctx, patient)
+	if err == nil {
+		e.emit("A04", created, "")
+	}
+	return created, err
+}
+
+func (e *Emitter) UpdatePatient(ctx context.Context, patient *domain.Patient) (*domain.Patient, error) {
+	updated, err := e.PatientService.UpdatePatient(ctx, patient)
+	if err == nil {
+		e.emit("A08", updated, "")
+	}
+	return updated, err
+}
+
+func (e *Emitter) PatchPatient(ctx context.Context, id string, patch domain.PatientPatch) (*domain.Patient, error) {
+	updated, err := e.PatientService.PatchPatient(ctx, id, patch)
+	if err == nil {
+		e.emit("A08", updated, "")
+	}
+	return updated, err
+}
+
+func (e *Emitter) RecordDeath(ctx context.Context, id string, deceasedAt time.Time, cause, recordedBy string) (*domain.Patient, error) {
+	updated, err := e.PatientService.RecordDeath(ctx, id, deceasedAt, cause, recordedBy)
+	if err == nil {
+		e.emit("A08", updated, "")
+	}
+	return updated, err
+}
+
+func (e *Emitter) ClearDeath(ctx context.Context, id, clearedBy string) (*domain.Patient, error) {
+	updated, err := e.PatientService.ClearDeath(ctx, id, clearedBy)
+	if err == nil {
+		e.emit("A08", updated, "")
+	}
+	return updated, err
+}
+
+func (e *Emitter) MergePatients(ctx context.Context, survivorID, mergedID, mergedBy string) (*domain.Patient, error) {
+	// The merged record is inactive afterwards, so read its MRN first
+	merged, err := e.PatientService.GetPatient(ctx, mergedID)
+	if err != nil {
+		return nil, err
+	}
+
+	survivor, err := e.PatientService.MergePatients(ctx, survivorID, mergedID, mergedBy)
+	if err == nil {
+		e.emit("A40", survivor, merged.MedicalRecordNo)
+	}
+	return survivor, err
+}
+
+func (e *Emitter) emit(event string, patient *domain.Patient, mergedMRN string) {
+	app := e.app
+	if t, ok := e.tenants.Get(patient.TenantID); ok && t.HL7Facility != "" {
+		app.SendingFacility = t.HL7Facility
+	}
+
+	msg := BuildADT(event, patient, ADTOptions{
+		Application: app,
+		ControlID:   NewControlID(),
+		MergedMRN:   mergedMRN,
+	})
+
+	select {
+	case e.queue <- msg:
+	default:
+		log.Printf("hl7: emit queue full, dropping ADT^%s %s for patient %s", event, msg.ControlID(), patient.ID)
+	}
+}
+
+// Run delivers queued messages until ctx is cancelled.
+func (e *Emitter) Run(ctx context.Context) {
+	defer e.client.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-e.queue:
+			e.deliver(ctx, msg)
+		}
+	}
+}
+
+// deliver sends msg until it is acknowledged, backing off between attempts.
+// Messages the receiver rejects are logged and dropped since resending them
+// unchanged would fail again.
+func (e *Emitter) deliver(ctx context.Context, msg *Message) {
+	backoff := time.Second
+	for {
+		reply, err := e.client.Send(ctx, msg.Bytes())
+		if err == nil {
+			ack, parseErr := Parse(reply)
+			if parseErr != nil {
+				log.Printf("hl7: invalid ACK for %s: %v", msg.ControlID(), parseErr)
+				return
+			}
+			switch code, text := AckCode(ack); code {
+			case AckAccept, "CA":
+			default:
+				log.Printf("hl7: %s rejected by receiver (%s): %s", msg.ControlID(), code, text)
+			}
+			return
+		}
+
+		log.Printf("hl7: sending %s failed, retrying in %s: %v", msg.ControlID(), backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
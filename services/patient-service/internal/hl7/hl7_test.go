@@ -0,0 +1,188 @@
+package hl7
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/service"
+	"patient-service/internal/tenant"
+)
+
+const sampleA08 = "MSH|^~\\&|HIS|RSUD|PATIENT-SERVICE|HOSPITAL|20240501120000||ADT^A08^ADT_A01|MSG0001|P|2.5\r" +
+	"EVN|A08|20240501120000\r" +
+	"PID|1||MR0001^^^RSUD^MR~3171234567890001^^^NIK^NNIDN||Santoso&Van^Budi^Hartono||19850312|M|||Jl. Merdeka 10^RT 01\\S\\RW 02^Bandung^Jawa Barat^40111^IDN||\"\"~^NET^Internet^budi@example.com\r" +
+	"PV1|1|N\r"
+
+func TestParse(t *testing.T) {
+	msg, err := Parse([]byte(sampleA08))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if code, event := msg.Type(); code != "ADT" || event != "A08" {
+		t.Errorf("Expected ADT^A08, got %s^%s", code, event)
+	}
+	if msg.ControlID() != "MSG0001" {
+		t.Errorf("Expected control ID MSG0001, got %s", msg.ControlID())
+	}
+
+	pid := msg.Segment("PID")
+	if got := pid.Component(11, 2); got != "RT 01^RW 02" {
+		t.Errorf("Expected escaped component to be resolved, got %q", got)
+	}
+
+	if string(msg.Bytes()) != sampleA08 {
+		t.Errorf("Expected message to encode unchanged, got %q", msg.Bytes())
+	}
+
+	if _, err := Parse([]byte("PID|1")); !errors.Is(err, ErrInvalidMessage) {
+		t.Errorf("Expected ErrInvalidMessage without MSH, got %v", err)
+	}
+}
+
+func TestParsePatientKeepsAbsentFields(t *testing.T) {
+	msg, _ := Parse([]byte(sampleA08))
+
+	update, err := ParsePatient(msg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if update.NIK != "3171234567890001" || update.MRN != "MR0001" {
+		t.Errorf("Unexpected identifiers %+v", update.Identifiers)
+	}
+
+	patient := &domain.Patient{
+		MedicalRecordNo:   "MR0001",
+		Phone:             "081234567890",
+		InsuranceProvider: "BPJS",
+		Allergies:         "Penicillin",
+	}
+	update.Apply(patient)
+
+	if patient.FirstName != "Budi Hartono" || patient.LastName != "Santoso" {
+		t.Errorf("Unexpected name %q %q", patient.FirstName, patient.LastName)
+	}
+	if !patient.DateOfBirth.Equal(time.Date(1985, 3, 12, 0, 0, 0, 0, time.UTC)) || patient.Gender != "MALE" {
+		t.Errorf("Unexpected birth date or gender: %s %s", patient.DateOfBirth, patient.Gender)
+	}
+	if patient.Phone != "" || patient.Email != "budi@example.com" {
+		t.Errorf("Expected phone cleared and email set, got %q, %q", patient.Phone, patient.Email)
+	}
+	if patient.InsuranceProvider != "BPJS" || patient.Allergies != "Penicillin" {
+		t.Error("Expected fields without segments to be kept")
+	}
+}
+
+func TestBuildADTRoundTrip(t *testing.T) {
+	original := &domain.Patient{
+		MedicalRecordNo:   "MR0002",
+		NIK:               "3171234567890002",
+		FirstName:         "Siti",
+		LastName:          "Aminah",
+		DateOfBirth:       time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Gender:            "FEMALE",
+		Phone:             "081234567890",
+		Email:             "siti@example.com",
+		Address:           "Jl. Sudirman No. 1 | Blok A",
+		City:              "Jakarta",
+		Province:          "DKI Jakarta",
+		PostalCode:        "10220",
+		EmergencyContact:  "Budi Santoso",
+		EmergencyPhone:    "081298765432",
+		InsuranceProvider: "BPJS",
+		InsuranceNumber:   "0001234567890",
+		Allergies:         "Penicillin; Seafood",
+	}
+
+	msg := BuildADT("A04", original, ADTOptions{ControlID: "X1"})
+	parsed, err := Parse(msg.Bytes())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	update, err := ParsePatient(parsed)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	patient := &domain.Patient{}
+	update.Apply(patient)
+
+	if *patient != *original {
+		t.Errorf("Round trip mismatch:\n got %+v\nwant %+v", patient, original)
+	}
+}
+
+func TestBuildACK(t *testing.T) {
+	msg, _ := Parse([]byte(sampleA08))
+
+	ack, _ := Parse(BuildACK(msg, Application{SendingApplication: "PS"}, "A1", nil).Bytes())
+	if code, _ := AckCode(ack); code != AckAccept {
+		t.Errorf("Expected AA, got %s", code)
+	}
+	if msa := ack.Segment("MSA"); msa.Field(2) != "MSG0001" {
+		t.Errorf("Expected MSA-2 to echo the control ID, got %s", msa.Field(2))
+	}
+	if msh := ack.Segment("MSH"); msh.Field(5) != "HIS" || msh.Field(9) != "ACK^A08^ACK" {
+		t.Errorf("Unexpected ACK header %q", strings.Join(msh.Fields, "|"))
+	}
+
+	ack, _ = Parse(BuildACK(msg, Application{}, "A2", &AckError{Code: ErrUnsupportedEvent, Text: "no"}).Bytes())
+	if code, _ := AckCode(ack); code != AckReject {
+		t.Errorf("Expected AR for unsupported events, got %s", code)
+	}
+
+	ack, _ = Parse(BuildACK(msg, Application{}, "A3", errors.New("db down")).Bytes())
+	if code, text := AckCode(ack); code != AckApplicationError || text != "db down" {
+		t.Errorf("Expected AE with error text, got %s %q", code, text)
+	}
+	if got := ack.Segment("ERR").Component(3, 1); got != ErrApplicationInternal {
+		t.Errorf("Expected ERR-3 %s, got %s", ErrApplicationInternal, got)
+	}
+}
+
+// tenantRecorder records the tenant patients are looked up in; other calls
+// panic on the nil embedded interface.
+type tenantRecorder struct {
+	service.PatientService
+	tenantID string
+}
+
+func (r *tenantRecorder) GetPatientByMedicalRecordNo(ctx context.Context, mrNo string) (*domain.Patient, error) {
+	r.tenantID, _ = tenant.FromContext(ctx)
+	return &domain.Patient{ID: "p1"}, nil
+}
+
+func (r *tenantRecorder) PatchPatient(ctx context.Context, id string, patch domain.PatientPatch) (*domain.Patient, error) {
+	return &domain.Patient{ID: id}, nil
+}
+
+func TestProcessorTenant(t *testing.T) {
+	msg, _ := Parse([]byte(sampleA08))
+
+	tenants, _ := tenant.NewRegistry(tenant.Default, &tenant.Tenant{ID: "north", HL7Facility: "hospital"})
+	recorder := &tenantRecorder{}
+	if err := NewProcessor(recorder, Application{}, tenants, "hl7").Process(context.Background(), msg); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if recorder.tenantID != "north" {
+		t.Errorf("Expected the tenant of the receiving facility, got %q", recorder.tenantID)
+	}
+
+	tenants, _ = tenant.NewRegistry(tenant.Default)
+	if err := NewProcessor(recorder, Application{}, tenants, "hl7").Process(context.Background(), msg); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if recorder.tenantID != tenant.Default {
+		t.Errorf("Expected the default tenant for other facilities, got %q", recorder.tenantID)
+	}
+
+	tenants, _ = tenant.NewRegistry("")
+	err := NewProcessor(recorder, Application{}, tenants, "hl7").Process(context.Background(), msg)
+	if ackErr, ok := err.(*AckError); !ok || ackErr.Code != ErrTableValueNotFound {
+		t.Errorf("Expected an unknown facility error without a default tenant, got %v", err)
+	}
+}
@@ -0,0 +1,312 @@
+// HL7 v2 message parsing and encoding
+// internal/hl7/message.go
+package hl7
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var ErrInvalidMessage = errors.New("invalid HL7 message")
+
+// Delimiters are the separators declared in MSH-1 and MSH-2.
+type Delimiters struct {
+	Field        byte
+	Component    byte
+	Repetition   byte
+	Escape       byte
+	Subcomponent byte
+}
+
+// DefaultDelimiters are the recommended "|^~\&" separators.
+var DefaultDelimiters = Delimiters{Field: '|', Component: '^', Repetition: '~', Escape: '\\', Subcomponent: '&'}
+
+// Segment is one line of a message. Fields hold the raw (escaped) values,
+// indexed by HL7 field number.
+type Segment struct {
+	Name   string
+	Fields []string
+	delims Delimiters
+}
+
+// Message is a parsed HL7 v2 message.
+type Message struct {
+	Segments []*Segment
+	Delims   Delimiters
+}
+
+// Parse parses a message whose segments are separated by CR (LF and CRLF
+// are accepted as well).
+func Parse(data []byte) (*Message, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\r")
+	text = strings.ReplaceAll(text, "\n", "\r")
+	text = strings.Trim(text, "\r")
+
+	if len(text) < 8 || !strings.HasPrefix(text, "MSH") {
+		return nil, fmt.Errorf("%w: message must start with MSH", ErrInvalidMessage)
+	}
+
+	delims := Delimiters{Field: text[3]}
+	encoding := text[4 : strings.IndexByte(text[4:]+string(delims.Field), delims.Field)+4]
+	if len(encoding) < 4 {
+		return nil, fmt.Errorf("%w: MSH-2 must declare four encoding characters", ErrInvalidMessage)
+	}
+	delims.Component, delims.Repetition, delims.Escape, delims.Subcomponent = encoding[0], encoding[1], encoding[2], encoding[3]
+
+	msg := &Message{Delims: delims}
+	for _, line := range strings.Split(text, "\r") {
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, string(delims.Field))
+		if len(parts[0]) != 3 {
+			return nil, fmt.Errorf("%w: bad segment name %q", ErrInvalidMessage, parts[0])
+		}
+
+		seg := &Segment{Name: parts[0], delims: delims}
+		if seg.Name == "MSH" {
+			// MSH-1 is the field separator itself
+			seg.Fields = append([]string{"", string(delims.Field)}, parts[1:]...)
+		} else {
+			seg.Fields = parts
+		}
+		msg.Segments = append(msg.Segments, seg)
+	}
+
+	return msg, nil
+}
+
+// NewMessage starts a message with the default delimiters and an MSH
+// segment; fields are MSH-3 onwards.
+func NewMessage(msh ...string) *Message {
+	msg := &Message{Delims: DefaultDelimiters}
+	seg := msg.Add("MSH")
+	seg.Fields = append([]string{"", "|", `^~\&`}, msh...)
+	return msg
+}
+
+// Add appends a segment with the given raw field values (field 1 onwards).
+func (m *Message) Add(name string, fields ...string) *Segment {
+	seg := &Segment{Name: name, Fields: append([]string{""}, fields...), delims: m.Delims}
+	m.Segments = append(m.Segments, seg)
+	return seg
+}
+
+// Segment returns the first segment with the given name, or nil.
+func (m *Message) Segment(name string) *Segment {
+	for _, s := range m.Segments {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// All returns every segment with the given name.
+func (m *Message) All(name string) []*Segment {
+	var segs []*Segment
+	for _, s := range m.Segments {
+		if s.Name == name {
+			segs = append(segs, s)
+		}
+	}
+	return segs
+}
+
+// Type returns the message code and trigger event from MSH-9, e.g. ADT, A04.
+func (m *Message) Type() (string, string) {
+	msh := m.Segment("MSH")
+	if msh == nil {
+		return "", ""
+	}
+	return msh.Component(9, 1), msh.Component(9, 2)
+}
+
+// ControlID returns MSH-10.
+func (m *Message) ControlID() string {
+	if msh := m.Segment("MSH"); msh != nil {
+		return msh.Component(10, 1)
+	}
+	return ""
+}
+
+// Bytes encodes the message with CR segment terminators.
+func (m *Message) Bytes() []byte {
+	var b strings.Builder
+	for _, s := range m.Segments {
+		b.WriteString(s.Name)
+		start := 1
+		if s.Name == "MSH" {
+			start = 2
+		}
+		for _, f := range s.Fields[start:] {
+			b.WriteByte(m.Delims.Field)
+			b.WriteString(f)
+		}
+		b.WriteByte('\r')
+	}
+	return []byte(b.String())
+}
+
+func (m *Message) String() string {
+	return strings.ReplaceAll(string(m.Bytes()), "\r", "\n")
+}
+
+// Field returns the raw value of field n.
+func (s *Segment) Field(n int) string {
+	if n < 1 || n >= len(s.Fields) {
+		return ""
+	}
+	return s.Fields[n]
+}
+
+// Repetitions returns the raw repetitions of field n.
+func (s *Segment) Repetitions(n int) []string {
+	f := s.Field(n)
+	if f == "" {
+		return nil
+	}
+	if s.Name == "MSH" && n <= 2 {
+		return []string{f}
+	}
+	return strings.Split(f, string(s.delims.Repetition))
+}
+
+// Component returns component c of the first repetition of field n, with
+// escape sequences resolved. Subcomponents are returned as is.
+func (s *Segment) Component(n, c int) string {
+	reps := s.Repetitions(n)
+	if len(reps) == 0 {
+		return ""
+	}
+	return s.ComponentOf(reps[0], c)
+}
+
+// ComponentOf returns component c of a raw field or repetition, unescaped.
+func (s *Segment) ComponentOf(raw string, c int) string {
+	parts := strings.Split(raw, string(s.delims.Component))
+	if c < 1 || c > len(parts) {
+		return ""
+	}
+	return s.delims.UnescapeText(parts[c-1])
+}
+
+// IsNull reports whether field n holds the explicit null value "", which
+// asks the receiver to delete the stored value.
+func (s *Segment) IsNull(n int) bool {
+	return s.Field(n) == `""`
+}
+
+// Set stores a raw value in field n, growing the segment as needed.
+func (s *Segment) Set(n int, raw string) {
+	for len(s.Fields) <= n {
+		s.Fields = append(s.Fields, "")
+	}
+	s.Fields[n] = raw
+}
+
+// EscapeText replaces delimiter characters in a value by escape sequences.
+func (d Delimiters) EscapeText(value string) string {
+	if value == "" {
+		return ""
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case d.Escape:
+			b.WriteString(string(d.Escape) + "E" + string(d.Escape))
+		case d.Field:
+			b.WriteString(string(d.Escape) + "F" + string(d.Escape))
+		case d.Component:
+			b.WriteString(string(d.Escape) + "S" + string(d.Escape))
+		case d.Subcomponent:
+			b.WriteString(string(d.Escape) + "T" + string(d.Escape))
+		case d.Repetition:
+			b.WriteString(string(d.Escape) + "R" + string(d.Escape))
+		case '\r':
+			b.WriteString(string(d.Escape) + "X0D" + string(d.Escape))
+		case '\n':
+			b.WriteString(string(d.Escape) + "X0A" + string(d.Escape))
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// UnescapeText resolves escape sequences. Unknown sequences are dropped.
+func (d Delimiters) UnescapeText(value string) string {
+	if strings.IndexByte(value, d.Escape) < 0 {
+		return value
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != d.Escape {
+			b.WriteByte(value[i])
+			continue
+		}
+		end := strings.IndexByte(value[i+1:], d.Escape)
+		if end < 0 {
+			b.WriteString(value[i:])
+			break
+		}
+		seq := value[i+1 : i+1+end]
+		i += end + 1
+
+		switch {
+		case seq == "F":
+			b.WriteByte(d.Field)
+		case seq == "S":
+			b.WriteByte(d.Component)
+		case seq == "T":
+			b.WriteByte(d.Subcomponent)
+		case seq == "R":
+			b.WriteByte(d.Repetition)
+		case seq == "E":
+			b.WriteByte(d.Escape)
+		case seq == "X0D":
+			b.WriteByte('\r')
+		case seq == "X0A", seq == ".br":
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// Components joins escaped components into a raw field value, dropping
+// trailing empty components.
+func (d Delimiters) Components(values ...string) string {
+	for len(values) > 0 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = d.EscapeText(v)
+	}
+	return strings.Join(escaped, string(d.Component))
+}
+
+var controlSeq uint32
+
+// NewControlID returns a message control ID (MSH-10) that is unique within
+// the process and fits the 20 character limit.
+func NewControlID() string {
+	return fmt.Sprintf("%s%05d", time.Now().UTC().Format("20060102150405"), atomic.AddUint32(&controlSeq, 1)%100000)
+}
+
+// Timestamp formats t as an HL7 DTM value.
+func Timestamp(t time.Time) string {
+	return t.Format("20060102150405")
+}
+
+// ParseDate parses an HL7 DT/DTM value, ignoring the time of day.
+func ParseDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return time.Parse("20060102", value[:8])
+}
@@ -0,0 +1,176 @@
+// Inbound ADT processing
+// internal/hl7/processor.go
+package hl7
+
+import (
+	"context"
+	"log"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/service"
+	"patient-service/internal/tenant"
+)
+
+// Processor applies inbound ADT^A04 (register), A08 (update) and A40 (merge)
+// messages through the PatientService and acknowledges them. It implements
+// mllp.Handler.
+type Processor struct {
+	patients service.PatientService
+	app      Application
+	tenants  *tenant.Registry
+	user     string
+}
+
+// NewProcessor creates a processor that records changes as made by user.
+// Messages are applied to the tenant whose HL7 facility is the receiving
+// facility in MSH-6, or to the default tenant.
+func NewProcessor(patients service.PatientService, app Application, tenants *tenant.Registry, user string) *Processor {
+	return &Processor{patients: patients, app: app, tenants: tenants, user: user}
+}
+
+func (p *Processor) ServeMLLP(ctx context.Context, data []byte) []byte {
+	msg, err := Parse(data)
+	if err != nil {
+		log.Printf("hl7: rejecting unparseable message: %v", err)
+		empty := &Message{Delims: DefaultDelimiters}
+		return BuildACK(empty, p.app, NewControlID(), &AckError{Code: ErrSegmentSequence, Text: err.Error()}).Bytes()
+	}
+
+	err = p.Process(ctx, msg)
+	if err != nil {
+		code, event := msg.Type()
+		log.Printf("hl7: %s^%s %s: %v", code, event, msg.ControlID(), err)
+	}
+
+	return BuildACK(msg, p.app, NewControlID(), err).Bytes()
+}
+
+// Process applies a parsed message. Errors are *AckError values or
+// unexpected failures, reported as application internal errors.
+func (p *Processor) Process(ctx context.Context, msg *Message) error {
+	code, event := msg.Type()
+	if code != "ADT" {
+		return &AckError{Code: ErrUnsupportedMessage, Text: "Unsupported message type " + code}
+	}
+
+	ctx, err := p.withTenant(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	switch event {
+	case "A04":
+		return p.register(ctx, msg)
+	case "A08":
+		return p.update(ctx, msg)
+	case "A40":
+		return p.merge(ctx, msg)
+	}
+	return &AckError{Code: ErrUnsupportedEvent, Text: "Unsupported event " + event}
+}
+
+func (p *Processor) register(ctx context.Context, msg *Message) error {
+	update, err := ParsePatient(msg)
+	if err != nil {
+		return err
+	}
+
+	patient := &domain.Patient{IsActive: true, CreatedBy: p.user, UpdatedBy: p.user}
+	update.Apply(patient)
+
+	_, err = p.patients.CreatePatient(ctx, patient)
+	return serviceError(err)
+}
+
+func (p *Processor) update(ctx context.Context, msg *Message) error {
+	update, err := ParsePatient(msg)
+	if err != nil {
+		return err
+	}
+
+	existing, err := p.find(ctx, update.Identifiers)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.patients.PatchPatient(ctx, existing.ID, func(patient *domain.Patient) error {
+		update.Apply(patient)
+		patient.UpdatedBy = p.user
+		return nil
+	})
+	return serviceError(err)
+}
+
+// merge retires the patient in MRG-1 in favour of the one in PID-3.
+func (p *Processor) merge(ctx context.Context, msg *Message) error {
+	pid, mrg := msg.Segment("PID"), msg.Segment("MRG")
+	if pid == nil || mrg == nil {
+		return &AckError{Code: ErrRequiredFieldMissing, Text: "A40 requires PID and MRG segments"}
+	}
+
+	survivor, err := p.find(ctx, ParseIdentifiers(pid, 3))
+	if err != nil {
+		return err
+	}
+	merged, err := p.find(ctx, ParseIdentifiers(mrg, 1))
+	if err != nil {
+		return err
+	}
+
+	_, err = p.patients.MergePatients(ctx, survivor.ID, merged.ID, p.user)
+	return serviceError(err)
+}
+
+// withTenant returns ctx acting for the tenant the message is sent to.
+func (p *Processor) withTenant(ctx context.Context, msg *Message) (context.Context, error) {
+	var facility string
+	if msh := msg.Segment("MSH"); msh != nil {
+		facility = msh.Component(6, 1)
+	}
+
+	t, ok := p.tenants.ByHL7Facility(facility)
+	if !ok {
+		t, ok = p.tenants.Resolve("")
+	}
+	if !ok {
+		return nil, &AckError{Code: ErrTableValueNotFound, Text: "Unknown receiving facility " + facility}
+	}
+	return tenant.NewContext(ctx, t.ID), nil
+}
+
+// find looks a patient up by MRN, falling back to NIK.
+func (p *Processor) find(ctx context.Context, ids Identifiers) (*domain.Patient, error) {
+	if ids.MRN == "" && ids.NIK == "" {
+		return nil, &AckError{Code: ErrRequiredFieldMissing, Text: "No MR or NNIDN identifier"}
+	}
+
+	if ids.MRN != "" {
+		patient, err := p.patients.GetPatientByMedicalRecordNo(ctx, ids.MRN)
+		if err == nil || err != domain.ErrPatientNotFound || ids.NIK == "" {
+			return patient, serviceError(err)
+		}
+	}
+
+	patient, err := p.patients.GetPatientByNIK(ctx, ids.NIK)
+	return patient, serviceError(err)
+}
+
+// serviceError converts service errors to acknowledgement errors.
+func serviceError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if err == domain.ErrPatientNotFound {
+		return &AckError{Code: ErrUnknownKey, Text: "Patient not found"}
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		switch customErr.Code {
+		case "PATIENT_EXISTS", "NIK_EXISTS":
+			return &AckError{Code: ErrDuplicateKey, Text: customErr.Message}
+		case "PATIENT_DECEASED":
+			return &AckError{Code: ErrRecordLocked, Text: customErr.Message}
+		}
+		return &AckError{Code: ErrDataType, Text: customErr.Message}
+	}
+	return err
+}
@@ -0,0 +1,266 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+	"patient-service/pkg/validator"
+)
+
+// memoryJobs holds a single job; calls the tests do not make panic on the
+// nil embedded interface.
+type memoryJobs struct {
+	repository.ImportRepository
+	job       *domain.ImportJob
+	content   []byte
+	claimed   bool
+	rowErrors []*domain.ImportRowError
+	saves     int
+}
+
+func (m *memoryJobs) ClaimJob(ctx context.Context, lease time.Duration) (*domain.ImportJob, []byte, error) {
+	if m.claimed {
+		return nil, nil, nil
+	}
+	m.claimed = true
+	m.job.Status = domain.ImportRunning
+	return m.job, m.content, nil
+}
+
+func (m *memoryJobs) SaveProgress(ctx context.Context, job *domain.ImportJob, rowErrors []*domain.ImportRowError, lease time.Duration) error {
+	m.rowErrors = append(m.rowErrors, rowErrors...)
+	m.saves++
+	return nil
+}
+
+func (m *memoryJobs) FinishJob(ctx context.Context, job *domain.ImportJob) error {
+	return nil
+}
+
+type memoryPatients struct {
+	repository.PatientRepository
+	niks    map[string]bool
+	created []*domain.Patient
+	failMRN string // CreateBatch and Create fail for a patient with this number
+}
+
+func (m *memoryPatients) ExistingNIKs(ctx context.Context, niks []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for _, nik := range niks {
+		if m.niks[nik] {
+			existing[nik] = true
+		}
+	}
+	return existing, nil
+}
+
+func (m *memoryPatients) CreateBatch(ctx context.Context, patients []*domain.Patient) error {
+	for _, patient := range patients {
+		if patient.MedicalRecordNo == m.failMRN {
+			return errors.New("unique constraint violated")
+		}
+	}
+	m.created = append(m.created, patients...)
+	return nil
+}
+
+func (m *memoryPatients) Create(ctx context.Context, patient *domain.Patient) error {
+	return m.CreateBatch(ctx, []*domain.Patient{patient})
+}
+
+const testCSV = "\xef\xbb\xbfNo KTP;Nama Depan;Tanggal Lahir;Jenis Kelamin;No HP;no_rm\n" +
+	"3171234567890001;Budi;17/05/1990;L;081234567890;\n" +
+	"3171234567890002;Siti;1985-01-02;P;081234567891;RM-1\n" +
+	"\n" +
+	"3171234567890001;Budi;17/05/1990;L;081234567890;\n" +
+	"3171234567890003;Andi;2090-01-01;L;081234567892;\n" +
+	"3171234567890004;Rina;1992-03-04;X;081234567893;\n" +
+	"3171234567890005;Dewi;1993-03-04;P;081234567894;\n"
+
+func startJob(t *testing.T, content string, dryRun bool) *domain.ImportJob {
+	t.Helper()
+	src, err := Parse(domain.ImportFormatCSV, []byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	mapping, err := ResolveMapping(src.Header, map[string]string{"nik": "No KTP"})
+	if err != nil {
+		t.Fatalf("ResolveMapping failed: %v", err)
+	}
+	return &domain.ImportJob{ID: "job1", Format: domain.ImportFormatCSV, DryRun: dryRun, Mapping: mapping, CreatedBy: "u1"}
+}
+
+func TestParseCSV(t *testing.T) {
+	src, err := Parse(domain.ImportFormatCSV, []byte(testCSV))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if src.Header[0] != "No KTP" || len(src.Header) != 6 {
+		t.Errorf("Unexpected header: %q", src.Header)
+	}
+	if len(src.Rows) != 6 {
+		t.Fatalf("Expected 6 data rows, got %d", len(src.Rows))
+	}
+	// The blank line is skipped but keeps its row number
+	if src.Rows[2].Number != 5 || src.Rows[2].Values[1] != "Budi" {
+		t.Errorf("Unexpected third row: %+v", src.Rows[2])
+	}
+}
+
+func TestResolveMapping(t *testing.T) {
+	header := []string{"NIK", "Nama Depan", "Tanggal Lahir", "Jenis Kelamin", "HP", "Catatan"}
+
+	_, err := ResolveMapping(header, nil)
+	var customErr *domain.CustomError
+	if !errors.As(err, &customErr) || customErr.Code != "INVALID_MAPPING" || customErr.Details != "phone" {
+		t.Errorf("Expected phone to be reported unmapped, got %v", err)
+	}
+
+	mapping, err := ResolveMapping(header, map[string]string{"phone": "HP"})
+	if err != nil {
+		t.Fatalf("ResolveMapping failed: %v", err)
+	}
+	want := map[string]string{
+		"nik":           "NIK",
+		"first_name":    "Nama Depan",
+		"date_of_birth": "Tanggal Lahir",
+		"gender":        "Jenis Kelamin",
+		"phone":         "HP",
+	}
+	if !reflect.DeepEqual(mapping, want) {
+		t.Errorf("Expected %v, got %v", want, mapping)
+	}
+
+	if _, err := ResolveMapping(header, map[string]string{"phone": "Telepon"}); err == nil {
+		t.Error("Expected an error for a column not in the header")
+	}
+}
+
+func TestConverterParsesExcelDates(t *testing.T) {
+	header := []string{"nik", "first_name", "date_of_birth", "gender", "phone", "blood_type"}
+	mapping, _ := ResolveMapping(header, nil)
+	conv := newConverter(validator.New(), domain.ImportFormatXLSX, header, mapping)
+
+	patient, rowErr := conv.Patient([]string{"3171234567890001", "Budi", "32874", "Laki-laki", "081234567890", "ab +"})
+	if rowErr != nil {
+		t.Fatalf("Unexpected row error: %+v", rowErr)
+	}
+	if !patient.DateOfBirth.Equal(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)) || patient.Gender != "MALE" || patient.BloodType != "AB+" {
+		t.Errorf("Unexpected patient: %+v", patient)
+	}
+
+	_, rowErr = conv.Patient([]string{"317123", "Budi", "32874", "L", "081234567890", ""})
+	if rowErr == nil || rowErr.Field != "nik" || rowErr.Code != CodeInvalidField {
+		t.Errorf("Expected a nik error, got %+v", rowErr)
+	}
+}
+
+func TestWorkerImportsValidRows(t *testing.T) {
+	jobs := &memoryJobs{job: startJob(t, testCSV, false), content: []byte(testCSV)}
+	patients := &memoryPatients{niks: map[string]bool{"3171234567890005": true}}
+	worker := NewWorker(jobs, patients, nil, validator.New(), WorkerConfig{BatchSize: 2})
+
+	claimed, err := worker.ProcessNext(context.Background())
+	if !claimed || err != nil {
+		t.Fatalf("ProcessNext = %v, %v", claimed, err)
+	}
+
+	job := jobs.job
+	if job.Status != domain.ImportCompleted || job.TotalRows != 6 || job.ProcessedRows != 6 {
+		t.Errorf("Unexpected job: %+v", job)
+	}
+	if job.ImportedRows != 2 || job.ValidRows != 2 || job.RejectedRows != 4 {
+		t.Errorf("Expected 2 imported and 4 rejected, got %+v", job)
+	}
+	if jobs.saves != 3 {
+		t.Errorf("Expected progress saved per batch, got %d saves", jobs.saves)
+	}
+
+	if len(patients.created) != 2 || patients.created[0].MedicalRecordNo == "" || patients.created[1].MedicalRecordNo != "RM-1" {
+		t.Errorf("Unexpected created patients: %+v", patients.created)
+	}
+	if patients.created[0].CreatedBy != "u1" {
+		t.Errorf("Expected patients created by the job's user, got %q", patients.created[0].CreatedBy)
+	}
+
+	want := []struct {
+		row   int
+		code  string
+		field string
+	}{
+		{5, CodeDuplicateNIK, "nik"},
+		{6, "INVALID_DOB", "date_of_birth"},
+		{7, CodeInvalidField, "gender"},
+		{8, CodeDuplicateNIK, "nik"},
+	}
+	if len(jobs.rowErrors) != len(want) {
+		t.Fatalf("Expected %d row errors, got %+v", len(want), jobs.rowErrors)
+	}
+	for i, w := range want {
+		got := jobs.rowErrors[i]
+		if got.Row != w.row || got.Code != w.code || got.Field != w.field {
+			t.Errorf("Row error %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+	if jobs.rowErrors[0].Message != "NIK already appears in row 2" || jobs.rowErrors[0].Values[1] != "Budi" {
+		t.Errorf("Unexpected duplicate row error: %+v", jobs.rowErrors[0])
+	}
+}
+
+func TestWorkerDryRunInsertsNothing(t *testing.T) {
+	jobs := &memoryJobs{job: startJob(t, testCSV, true), content: []byte(testCSV)}
+	patients := &memoryPatients{}
+	worker := NewWorker(jobs, patients, nil, validator.New(), WorkerConfig{BatchSize: 500})
+
+	if _, err := worker.ProcessNext(context.Background()); err != nil {
+		t.Fatalf("ProcessNext failed: %v", err)
+	}
+	if len(patients.created) != 0 {
+		t.Errorf("Expected no inserts in a dry run, got %d", len(patients.created))
+	}
+	if jobs.job.ValidRows != 3 || jobs.job.ImportedRows != 0 || jobs.job.RejectedRows != 3 {
+		t.Errorf("Unexpected counters: %+v", jobs.job)
+	}
+}
+
+func TestWorkerFallsBackToSingleInserts(t *testing.T) {
+	jobs := &memoryJobs{job: startJob(t, testCSV, false), content: []byte(testCSV)}
+	patients := &memoryPatients{failMRN: "RM-1"}
+	worker := NewWorker(jobs, patients, nil, validator.New(), WorkerConfig{BatchSize: 500})
+
+	if _, err := worker.ProcessNext(context.Background()); err != nil {
+		t.Fatalf("ProcessNext failed: %v", err)
+	}
+	if jobs.job.ImportedRows != 2 || len(patients.created) != 2 {
+		t.Errorf("Expected the other rows imported, got %+v", jobs.job)
+	}
+
+	var insertFailed []int
+	for _, rowErr := range jobs.rowErrors {
+		if rowErr.Code == CodeInsertFailed {
+			insertFailed = append(insertFailed, rowErr.Row)
+		}
+	}
+	if !reflect.DeepEqual(insertFailed, []int{3}) {
+		t.Errorf("Expected row 3 rejected as INSERT_FAILED, got %v", insertFailed)
+	}
+}
+
+func TestWorkerFailsUnreadableFile(t *testing.T) {
+	job := &domain.ImportJob{ID: "job1", Format: domain.ImportFormatXLSX, Mapping: map[string]string{}}
+	jobs := &memoryJobs{job: job, content: []byte("not a workbook")}
+	worker := NewWorker(jobs, &memoryPatients{}, nil, validator.New(), WorkerConfig{})
+
+	if _, err := worker.ProcessNext(context.Background()); err != nil {
+		t.Fatalf("ProcessNext failed: %v", err)
+	}
+	if job.Status != domain.ImportFailed || job.Error == "" {
+		t.Errorf("Expected a failed job with an error, got %+v", job)
+	}
+}
@@ -0,0 +1,131 @@
+// Column mapping
+// internal/importer/mapping.go
+package importer
+
+import (
+	"strings"
+
+	"patient-service/internal/domain"
+)
+
+// Fields are the patient fields a column can be mapped to, by JSON name.
+var Fields = []string{
+	"medical_record_no", "nik", "first_name", "last_name", "date_of_birth", "gender",
+	"blood_type", "phone", "email", "address", "city", "province", "postal_code",
+	"emergency_contact", "emergency_phone", "insurance_provider", "insurance_number",
+	"allergies", "chronic_conditions",
+}
+
+// RequiredFields must be mapped to a column.
+var RequiredFields = []string{"nik", "first_name", "date_of_birth", "gender", "phone"}
+
+// aliases maps normalized headers other than the field names themselves,
+// mostly the Indonesian ones found in registration spreadsheets.
+var aliases = map[string]string{
+	"no_rm":            "medical_record_no",
+	"nomor_rm":         "medical_record_no",
+	"no_rekam_medis":   "medical_record_no",
+	"mrn":              "medical_record_no",
+	"no_ktp":           "nik",
+	"nama":             "first_name",
+	"nama_depan":       "first_name",
+	"nama_belakang":    "last_name",
+	"tanggal_lahir":    "date_of_birth",
+	"tgl_lahir":        "date_of_birth",
+	"dob":              "date_of_birth",
+	"jenis_kelamin":    "gender",
+	"jk":               "gender",
+	"golongan_darah":   "blood_type",
+	"gol_darah":        "blood_type",
+	"telepon":          "phone",
+	"no_telepon":       "phone",
+	"no_hp":            "phone",
+	"alamat":           "address",
+	"kota":             "city",
+	"provinsi":         "province",
+	"kode_pos":         "postal_code",
+	"kontak_darurat":   "emergency_contact",
+	"telepon_darurat":  "emergency_phone",
+	"asuransi":         "insurance_provider",
+	"no_asuransi":      "insurance_number",
+	"alergi":           "allergies",
+	"riwayat_penyakit": "chronic_conditions",
+}
+
+func isField(name string) bool {
+	for _, field := range Fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize lowercases a header and joins its words with underscores,
+// so "Tanggal Lahir" and "tanggal-lahir" both read "tanggal_lahir".
+func normalize(header string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(header), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), "_")
+}
+
+// ResolveMapping maps patient fields to header columns. Fields in explicit
+// (field to header name) are mapped as given; the other fields are matched
+// to headers by name or alias. The result maps fields to header names.
+func ResolveMapping(header []string, explicit map[string]string) (map[string]string, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if name == "" {
+			continue
+		}
+		if _, dup := columns[name]; dup {
+			return nil, domain.NewCustomError("INVALID_MAPPING", "Header has a duplicate column", name)
+		}
+		columns[name] = i
+	}
+
+	mapping := make(map[string]string)
+	used := make(map[string]bool)
+	for field, name := range explicit {
+		if !isField(field) {
+			return nil, domain.NewCustomError("INVALID_MAPPING", "Unknown patient field", field)
+		}
+		if _, ok := columns[name]; !ok {
+			return nil, domain.NewCustomError("INVALID_MAPPING", "Column not found in header", name)
+		}
+		if used[name] {
+			return nil, domain.NewCustomError("INVALID_MAPPING", "Column is mapped to more than one field", name)
+		}
+		mapping[field] = name
+		used[name] = true
+	}
+
+	for _, name := range header {
+		if name == "" || used[name] {
+			continue
+		}
+		field := normalize(name)
+		if alias, ok := aliases[field]; ok {
+			field = alias
+		}
+		if !isField(field) {
+			continue
+		}
+		if _, mapped := mapping[field]; !mapped {
+			mapping[field] = name
+			used[name] = true
+		}
+	}
+
+	var missing []string
+	for _, field := range RequiredFields {
+		if _, ok := mapping[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, domain.NewCustomError("INVALID_MAPPING", "Required fields are not mapped to a column", strings.Join(missing, ", "))
+	}
+
+	return mapping, nil
+}
@@ -0,0 +1,166 @@
+// Row validation
+// internal/importer/row.go
+package importer
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/dto"
+	"patient-service/pkg/utils"
+)
+
+// Row error codes, besides the patient validation codes of the domain
+const (
+	CodeInvalidField = "INVALID_FIELD"
+	CodeDuplicateNIK = "DUPLICATE_NIK"
+	CodeDuplicateMRN = "DUPLICATE_MRN"
+	CodeInsertFailed = "INSERT_FAILED"
+)
+
+// excelEpoch is day zero of Excel serial dates (1900 date system, taking
+// the fictitious 29 February 1900 into account for later dates).
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+var dateLayouts = []string{"2006-01-02", "02/01/2006", "2/1/2006", "02-01-2006", "2-1-2006"}
+
+var genders = map[string]string{
+	"MALE": "MALE", "M": "MALE", "L": "MALE", "LAKI-LAKI": "MALE", "LAKI LAKI": "MALE", "PRIA": "MALE",
+	"FEMALE": "FEMALE", "F": "FEMALE", "P": "FEMALE", "PEREMPUAN": "FEMALE", "WANITA": "FEMALE",
+}
+
+// ruleFields names the field each domain validation code is about.
+var ruleFields = map[string]string{
+	"INVALID_NIK":        "nik",
+	"INVALID_NAME":       "first_name",
+	"INVALID_DOB":        "date_of_birth",
+	"INVALID_GENDER":     "gender",
+	"INVALID_PHONE":      "phone",
+	"INVALID_BLOOD_TYPE": "blood_type",
+}
+
+// rowError rejects a row for one of its fields.
+func rowError(field, code, message string) *domain.ImportRowError {
+	return &domain.ImportRowError{Field: field, Code: code, Message: message}
+}
+
+// converter turns mapped rows into patients, applying the same validator
+// tags as the create endpoint and then the domain rules.
+type converter struct {
+	validate *validator.Validate
+	format   string
+	columns  map[string]int // field to column index
+}
+
+func newConverter(validate *validator.Validate, format string, header []string, mapping map[string]string) *converter {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[name] = i
+	}
+	columns := make(map[string]int, len(mapping))
+	for field, name := range mapping {
+		if i, ok := index[name]; ok {
+			columns[field] = i
+		}
+	}
+	return &converter{validate: validate, format: format, columns: columns}
+}
+
+func (c *converter) value(values []string, field string) string {
+	i, ok := c.columns[field]
+	if !ok || i >= len(values) {
+		return ""
+	}
+	return strings.TrimSpace(values[i])
+}
+
+// Patient returns the patient of a row, or the first problem found in it.
+func (c *converter) Patient(values []string) (*domain.Patient, *domain.ImportRowError) {
+	req := dto.CreatePatientRequest{
+		NIK:               c.value(values, "nik"),
+		FirstName:         c.value(values, "first_name"),
+		LastName:          c.value(values, "last_name"),
+		Gender:            c.value(values, "gender"),
+		BloodType:         strings.ToUpper(strings.ReplaceAll(c.value(values, "blood_type"), " ", "")),
+		Phone:             c.value(values, "phone"),
+		Email:             c.value(values, "email"),
+		Address:           c.value(values, "address"),
+		City:              c.value(values, "city"),
+		Province:          c.value(values, "province"),
+		PostalCode:        c.value(values, "postal_code"),
+		EmergencyContact:  c.value(values, "emergency_contact"),
+		EmergencyPhone:    c.value(values, "emergency_phone"),
+		InsuranceProvider: c.value(values, "insurance_provider"),
+		InsuranceNumber:   c.value(values, "insurance_number"),
+		Allergies:         c.value(values, "allergies"),
+		ChronicConditions: c.value(values, "chronic_conditions"),
+	}
+
+	if gender, ok := genders[strings.ToUpper(req.Gender)]; ok {
+		req.Gender = gender
+	}
+
+	if dob := c.value(values, "date_of_birth"); dob != "" {
+		t, ok := c.parseDate(dob)
+		if !ok {
+			return nil, rowError("date_of_birth", CodeInvalidField, "date_of_birth must be a date such as 2006-01-02 or 02/01/2006")
+		}
+		req.DateOfBirth = t
+	}
+
+	if err := c.validate.Struct(&req); err != nil {
+		var errs validator.ValidationErrors
+		if errors.As(err, &errs) && len(errs) > 0 {
+			return nil, rowError(jsonName(errs[0].StructField()), CodeInvalidField, utils.FormatValidationError(errs[0]))
+		}
+		return nil, rowError("", CodeInvalidField, err.Error())
+	}
+
+	patient := dto.ToPatientDomain(&req)
+	patient.MedicalRecordNo = c.value(values, "medical_record_no")
+	if len(patient.MedicalRecordNo) > 50 {
+		return nil, rowError("medical_record_no", CodeInvalidField, "medical_record_no must be at most 50 characters")
+	}
+
+	if err := patient.Validate(); err != nil {
+		var customErr *domain.CustomError
+		if errors.As(err, &customErr) {
+			return nil, rowError(ruleFields[customErr.Code], customErr.Code, customErr.Message)
+		}
+		return nil, rowError("", CodeInvalidField, err.Error())
+	}
+
+	return patient, nil
+}
+
+// parseDate accepts ISO and day-first dates, and serial dates in workbooks
+// whose date cells are not formatted as text.
+func (c *converter) parseDate(value string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	if c.format == domain.ImportFormatXLSX {
+		if serial, err := strconv.ParseFloat(value, 64); err == nil && serial >= 1 && serial < 2958466 {
+			return excelEpoch.AddDate(0, 0, int(math.Floor(serial))), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// jsonName returns the JSON name of a CreatePatientRequest field.
+func jsonName(structField string) string {
+	f, ok := reflect.TypeOf(dto.CreatePatientRequest{}).FieldByName(structField)
+	if !ok {
+		return structField
+	}
+	return strings.Split(f.Tag.Get("json"), ",")[0]
+}
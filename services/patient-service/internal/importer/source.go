@@ -0,0 +1,142 @@
+// Import file parsing
+// internal/importer/source.go
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"patient-service/internal/domain"
+	"patient-service/pkg/xlsx"
+)
+
+// Row is a data row of an import file.
+type Row struct {
+	Number int // spreadsheet row number, the header being row 1
+	Values []string
+}
+
+// Source is a parsed import file: the header and the non-blank data rows.
+type Source struct {
+	Header []string
+	Rows   []Row
+}
+
+// FormatOf returns the import format of a file name, or "" when the
+// extension is not supported.
+func FormatOf(fileName string) string {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv":
+		return domain.ImportFormatCSV
+	case ".xlsx":
+		return domain.ImportFormatXLSX
+	}
+	return ""
+}
+
+// Parse reads an import file. The first non-blank row is the header.
+func Parse(format string, content []byte) (*Source, error) {
+	var (
+		rows []Row
+		err  error
+	)
+	switch format {
+	case domain.ImportFormatCSV:
+		rows, err = readCSV(content)
+	case domain.ImportFormatXLSX:
+		rows, err = readXLSX(content)
+	default:
+		return nil, domain.NewCustomError("INVALID_FORMAT", "File must be a CSV or XLSX file", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, domain.NewCustomError("EMPTY_FILE", "File has no header row", "")
+	}
+
+	header := make([]string, len(rows[0].Values))
+	for i, name := range rows[0].Values {
+		header[i] = strings.TrimSpace(name)
+	}
+	return &Source{Header: header, Rows: rows[1:]}, nil
+}
+
+// readCSV reads a CSV file delimited by commas, semicolons (as written by
+// spreadsheets in locales with a decimal comma) or tabs.
+func readCSV(content []byte) ([]Row, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	r := csv.NewReader(bytes.NewReader(content))
+	r.Comma = detectDelimiter(content)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	var rows []Row
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, domain.NewCustomError("INVALID_FILE", "File is not valid CSV", parseErr.Error())
+			}
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		if !blank(record) {
+			rows = append(rows, Row{Number: line, Values: record})
+		}
+	}
+	return rows, nil
+}
+
+// detectDelimiter picks the most frequent candidate in the first line.
+func detectDelimiter(content []byte) rune {
+	line := content
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		line = content[:i]
+	}
+
+	delimiter, most := ',', 0
+	for _, candidate := range []rune{',', ';', '\t'} {
+		if n := bytes.Count(line, []byte(string(candidate))); n > most {
+			delimiter, most = candidate, n
+		}
+	}
+	return delimiter
+}
+
+func readXLSX(content []byte) ([]Row, error) {
+	cells, err := xlsx.ReadFirstSheet(content)
+	if errors.Is(err, xlsx.ErrInvalidWorkbook) || errors.Is(err, xlsx.ErrTooLarge) {
+		return nil, domain.NewCustomError("INVALID_FILE", "File is not a valid XLSX workbook", err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for i, values := range cells {
+		if !blank(values) {
+			rows = append(rows, Row{Number: i + 1, Values: values})
+		}
+	}
+	return rows, nil
+}
+
+func blank(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
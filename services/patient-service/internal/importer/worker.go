@@ -0,0 +1,330 @@
+// Import worker
+// internal/importer/worker.go
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+	"patient-service/internal/tenant"
+)
+
+const pruneInterval = time.Hour
+
+// WorkerConfig configures a Worker.
+type WorkerConfig struct {
+	Interval  time.Duration // poll interval
+	BatchSize int           // rows inserted per transaction
+	Lease     time.Duration // how long a job is held without progress
+
+	// Retention is how long finished jobs and their error reports are kept
+	Retention time.Duration
+}
+
+// Worker processes import jobs. Each instance claims its own jobs, so
+// several may run at once.
+//
+// Progress is saved after each batch and a job whose worker stopped is
+// resumed after its lease expires. A batch inserted just before a crash,
+// with its progress not saved yet, is reported on resume as DUPLICATE_NIK
+// rows rather than imported twice.
+type Worker struct {
+	jobs     repository.ImportRepository
+	patients repository.PatientRepository
+	tenants  *tenant.Registry
+	validate *validator.Validate
+	cfg      WorkerConfig
+	wake     chan struct{}
+}
+
+// NewWorker creates a worker; generated medical record numbers use the
+// prefix of the job's tenant in tenants.
+func NewWorker(jobs repository.ImportRepository, patients repository.PatientRepository, tenants *tenant.Registry, validate *validator.Validate, cfg WorkerConfig) *Worker {
+	return &Worker{
+		jobs:     jobs,
+		patients: patients,
+		tenants:  tenants,
+		validate: validate,
+		cfg:      cfg,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Wake makes Run check for pending jobs now.
+func (w *Worker) Wake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes jobs until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+
+	for {
+		for {
+			claimed, err := w.ProcessNext(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Patient import failed: %v", err)
+			}
+			if err != nil || !claimed {
+				break
+			}
+		}
+
+		if time.Since(lastPrune) >= pruneInterval {
+			lastPrune = time.Now()
+			if n, err := w.jobs.PruneJobs(ctx, time.Now().Add(-w.cfg.Retention)); err != nil {
+				log.Printf("Import job prune failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Pruned %d import jobs", n)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+// ProcessNext claims a job and processes it to the end, acting for the
+// job's tenant, and reports whether there was one. A file that cannot be
+// read fails the job; storage errors are returned and the job is resumed
+// once its lease expires.
+func (w *Worker) ProcessNext(ctx context.Context) (bool, error) {
+	job, content, err := w.jobs.ClaimJob(ctx, w.cfg.Lease)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	err = w.process(tenant.NewContext(ctx, job.TenantID), job, content)
+
+	var customErr *domain.CustomError
+	switch {
+	case errors.As(err, &customErr):
+		job.Status = domain.ImportFailed
+		job.Error = customErr.Message
+		if customErr.Details != "" {
+			job.Error += ": " + customErr.Details
+		}
+	case err != nil:
+		return true, fmt.Errorf("import %s: %w", job.ID, err)
+	default:
+		job.Status = domain.ImportCompleted
+	}
+
+	if err := w.jobs.FinishJob(ctx, job); err != nil {
+		return true, fmt.Errorf("import %s: %w", job.ID, err)
+	}
+	log.Printf("Import %s %s: %d rows, %d imported, %d rejected",
+		job.ID, job.Status, job.TotalRows, job.ImportedRows, job.RejectedRows)
+	return true, nil
+}
+
+// candidate is a valid row waiting to be inserted.
+type candidate struct {
+	row          Row
+	patient      *domain.Patient
+	generatedMRN bool
+}
+
+// importRun holds the state of one pass over a job's rows.
+type importRun struct {
+	job       *domain.ImportJob
+	conv      *converter
+	mrnPrefix string
+
+	// First accepted row of each NIK and medical record number
+	niks map[string]int
+	mrns map[string]int
+}
+
+func (w *Worker) process(ctx context.Context, job *domain.ImportJob, content []byte) error {
+	src, err := Parse(job.Format, content)
+	if err != nil {
+		return err
+	}
+	job.Columns = src.Header
+	job.TotalRows = len(src.Rows)
+
+	run := &importRun{
+		job:  job,
+		conv: newConverter(w.validate, job.Format, src.Header, job.Mapping),
+		niks: make(map[string]int),
+		mrns: make(map[string]int),
+	}
+	if t, ok := w.tenants.Get(job.TenantID); ok {
+		run.mrnPrefix = t.MRNPrefix
+	}
+
+	// On resume, rows already processed still count for duplicates
+	if job.ProcessedRows > len(src.Rows) {
+		job.ProcessedRows = len(src.Rows)
+	}
+	for _, row := range src.Rows[:job.ProcessedRows] {
+		if patient, rowErr := run.conv.Patient(row.Values); rowErr == nil {
+			run.accept(row, patient)
+		}
+	}
+
+	batchSize := w.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	for start := job.ProcessedRows; start < len(src.Rows); start += batchSize {
+		end := start + batchSize
+		if end > len(src.Rows) {
+			end = len(src.Rows)
+		}
+
+		rowErrors, err := w.processBatch(ctx, run, src.Rows[start:end])
+		if err != nil {
+			return err
+		}
+
+		job.ProcessedRows = end
+		job.RejectedRows += len(rowErrors)
+		if err := w.jobs.SaveProgress(ctx, job, rowErrors, w.cfg.Lease); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// accept records the keys of a row that passed the file's duplicate checks.
+func (r *importRun) accept(row Row, patient *domain.Patient) *domain.ImportRowError {
+	if first, ok := r.niks[patient.NIK]; ok {
+		return rowError("nik", CodeDuplicateNIK, fmt.Sprintf("NIK already appears in row %d", first))
+	}
+	if patient.MedicalRecordNo != "" {
+		if first, ok := r.mrns[patient.MedicalRecordNo]; ok {
+			return rowError("medical_record_no", CodeDuplicateMRN, fmt.Sprintf("Medical record number already appears in row %d", first))
+		}
+		r.mrns[patient.MedicalRecordNo] = row.Number
+	}
+	r.niks[patient.NIK] = row.Number
+	return nil
+}
+
+// processBatch validates the rows and, unless the job is a dry run, inserts
+// the valid ones. It returns the rejected rows.
+func (w *Worker) processBatch(ctx context.Context, run *importRun, rows []Row) ([]*domain.ImportRowError, error) {
+	var (
+		rowErrors  []*domain.ImportRowError
+		candidates []*candidate
+	)
+	reject := func(row Row, rowErr *domain.ImportRowError) {
+		rowErr.Row = row.Number
+		rowErr.Values = row.Values
+		rowErrors = append(rowErrors, rowErr)
+	}
+
+	for _, row := range rows {
+		patient, rowErr := run.conv.Patient(row.Values)
+		if rowErr == nil {
+			rowErr = run.accept(row, patient)
+		}
+		if rowErr != nil {
+			reject(row, rowErr)
+			continue
+		}
+		candidates = append(candidates, &candidate{row: row, patient: patient})
+	}
+
+	niks := make([]string, len(candidates))
+	for i, c := range candidates {
+		niks[i] = c.patient.NIK
+	}
+	existing, err := w.patients.ExistingNIKs(ctx, niks)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := candidates[:0]
+	for _, c := range candidates {
+		if existing[c.patient.NIK] {
+			reject(c.row, rowError("nik", CodeDuplicateNIK, "A patient with this NIK already exists"))
+			continue
+		}
+		valid = append(valid, c)
+	}
+
+	if run.job.DryRun {
+		run.job.ValidRows += len(valid)
+		return rowErrors, nil
+	}
+
+	patients := make([]*domain.Patient, len(valid))
+	for i, c := range valid {
+		c.patient.CreatedBy = run.job.CreatedBy
+		c.patient.UpdatedBy = run.job.CreatedBy
+		if c.patient.MedicalRecordNo == "" {
+			c.patient.MedicalRecordNo = run.newMRN(c.row)
+			c.generatedMRN = true
+		}
+		patients[i] = c.patient
+	}
+
+	if err := w.patients.CreateBatch(ctx, patients); err == nil {
+		run.job.ValidRows += len(valid)
+		run.job.ImportedRows += len(valid)
+		return rowErrors, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// One row spoiled the batch, most likely a medical record number in
+	// use. Insert the rows one by one to find it, with a fresh number for
+	// generated ones.
+	for _, c := range valid {
+		err := w.patients.Create(ctx, c.patient)
+		if err != nil && c.generatedMRN {
+			c.patient.MedicalRecordNo = run.newMRN(c.row)
+			err = w.patients.Create(ctx, c.patient)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			message := "Patient could not be stored"
+			if !c.generatedMRN {
+				message += "; the medical record number may already be in use"
+			}
+			reject(c.row, rowError("", CodeInsertFailed, message))
+			continue
+		}
+		run.job.ValidRows++
+		run.job.ImportedRows++
+	}
+
+	return rowErrors, nil
+}
+
+// newMRN generates a medical record number not used by another row of the
+// job.
+func (r *importRun) newMRN(row Row) string {
+	for {
+		mrn := domain.NewMedicalRecordNo(r.mrnPrefix)
+		if _, taken := r.mrns[mrn]; !taken {
+			r.mrns[mrn] = row.Number
+			return mrn
+		}
+	}
+}
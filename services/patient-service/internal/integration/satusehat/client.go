@@ -0,0 +1,163 @@
+// SATUSEHAT (Kemenkes) FHIR API client
+// internal/integration/satusehat/client.go
+package satusehat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"patient-service/internal/fhir"
+)
+
+var (
+	// ErrNotFound means SATUSEHAT has no patient with the NIK.
+	ErrNotFound = errors.New("satusehat: patient not found")
+
+	// ErrAmbiguous means the NIK matched more than one IHS number.
+	ErrAmbiguous = errors.New("satusehat: NIK matches several patients")
+)
+
+// APIError is a non-2xx response from SATUSEHAT.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("satusehat: HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// Temporary reports whether the request may succeed when retried.
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Retryable reports whether a failed lookup should be retried. Transport
+// errors, rate limiting and server errors are retryable; unknown or
+// ambiguous NIKs and rejected requests are not.
+func Retryable(err error) bool {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrAmbiguous) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	return true
+}
+
+type Config struct {
+	AuthURL      string // e.g. https://api-satusehat.kemkes.go.id/oauth2/v1
+	BaseURL      string // e.g. https://api-satusehat.kemkes.go.id/fhir-r4/v1
+	ClientID     string
+	ClientSecret string
+	HTTPClient   *http.Client // defaults to a client with a 30s timeout
+}
+
+// Client calls the SATUSEHAT FHIR API with an OAuth2 client-credentials
+// token that is cached until shortly before it expires.
+type Client struct {
+	cfg    Config
+	http   *http.Client
+	tokens *tokenSource
+}
+
+func NewClient(cfg Config) *Client {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	cfg.AuthURL = strings.TrimRight(cfg.AuthURL, "/")
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+
+	return &Client{
+		cfg:    cfg,
+		http:   httpClient,
+		tokens: &tokenSource{cfg: cfg, http: httpClient},
+	}
+}
+
+// FindPatientByNIK returns the IHS number of the patient with the NIK.
+func (c *Client) FindPatientByNIK(ctx context.Context, nik string) (string, error) {
+	query := url.Values{"identifier": {fhir.SystemNIK + "|" + nik}}
+
+	var bundle struct {
+		Total *int `json:"total"`
+		Entry []struct {
+			Resource fhir.Patient `json:"resource"`
+		} `json:"entry"`
+	}
+	if err := c.get(ctx, "/Patient?"+query.Encode(), &bundle); err != nil {
+		return "", err
+	}
+
+	var ihsNumbers []string
+	for _, entry := range bundle.Entry {
+		if entry.Resource.ResourceType != "Patient" {
+			continue
+		}
+		// The NIK search returns the IHS number as the resource id
+		ihsNumbers = append(ihsNumbers, entry.Resource.ID)
+	}
+
+	switch len(ihsNumbers) {
+	case 0:
+		return "", ErrNotFound
+	case 1:
+		return ihsNumbers[0], nil
+	}
+	return "", ErrAmbiguous
+}
+
+// get sends an authorized GET request and decodes the JSON response. A 401
+// invalidates the cached token and the request is retried once.
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	for attempt := 0; ; attempt++ {
+		token, err := c.tokens.token(ctx)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.BaseURL+path, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", fhir.ContentType)
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			resp.Body.Close()
+			c.tokens.invalidate(token)
+			continue
+		}
+
+		return decodeResponse(resp, out)
+	}
+}
+
+func decodeResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("satusehat: invalid response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package satusehat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/fhir"
+	"patient-service/internal/integration/satusehat/satusehattest"
+)
+
+func newTestClient(server *satusehattest.Server) *Client {
+	return NewClient(Config{
+		AuthURL:      server.AuthURL(),
+		BaseURL:      server.BaseURL(),
+		ClientID:     server.ClientID,
+		ClientSecret: server.ClientSecret,
+	})
+}
+
+func TestFindPatientByNIK(t *testing.T) {
+	server := satusehattest.NewServer()
+	defer server.Close()
+	server.AddPatient("3171234567890001", "P02478375538")
+
+	client := newTestClient(server)
+	ctx := context.Background()
+
+	ihs, err := client.FindPatientByNIK(ctx, "3171234567890001")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ihs != "P02478375538" {
+		t.Errorf("Expected IHS number P02478375538, got %s", ihs)
+	}
+
+	if _, err := client.FindPatientByNIK(ctx, "3171234567890999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if n := server.TokenRequests(); n != 1 {
+		t.Errorf("Expected the token to be reused, got %d token requests", n)
+	}
+}
+
+func TestClientRenewsRejectedToken(t *testing.T) {
+	server := satusehattest.NewServer()
+	defer server.Close()
+	server.AddPatient("3171234567890001", "P02478375538")
+
+	client := newTestClient(server)
+	ctx := context.Background()
+
+	if _, err := client.FindPatientByNIK(ctx, "3171234567890001"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	server.RevokeTokens()
+	if _, err := client.FindPatientByNIK(ctx, "3171234567890001"); err != nil {
+		t.Fatalf("Expected the request to succeed with a new token, got %v", err)
+	}
+	if n := server.TokenRequests(); n != 2 {
+		t.Errorf("Expected 2 token requests, got %d", n)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	server := satusehattest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(server)
+	ctx := context.Background()
+
+	server.FailNext(http.StatusServiceUnavailable, http.StatusBadRequest)
+
+	_, err := client.FindPatientByNIK(ctx, "3171234567890001")
+	if !Retryable(err) {
+		t.Errorf("Expected 503 to be retryable, got %v", err)
+	}
+
+	_, err = client.FindPatientByNIK(ctx, "3171234567890001")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || Retryable(err) {
+		t.Errorf("Expected non-retryable 400, got %v", err)
+	}
+
+	bad := NewClient(Config{AuthURL: server.AuthURL(), BaseURL: server.BaseURL(), ClientID: "wrong"})
+	if _, err := bad.FindPatientByNIK(ctx, "3171234567890001"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for wrong credentials, got %v", err)
+	}
+}
+
+type memoryStore struct {
+	patients    map[string]*domain.Patient
+	identifiers map[string]string
+	tasks       map[string]*domain.IdentitySyncTask
+	denied      map[string]bool // patients who do not allow sharing with SATUSEHAT
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		patients:    make(map[string]*domain.Patient),
+		identifiers: make(map[string]string),
+		tasks:       make(map[string]*domain.IdentitySyncTask),
+		denied:      make(map[string]bool),
+	}
+}
+
+func (m *memoryStore) GetByID(ctx context.Context, id string) (*domain.Patient, error) {
+	if p, ok := m.patients[id]; ok {
+		return p, nil
+	}
+	return nil, domain.ErrPatientNotFound
+}
+
+func (m *memoryStore) Upsert(ctx context.Context, identifier *domain.Identifier) error {
+	m.identifiers[identifier.PatientID+" "+identifier.System] = identifier.Value
+	return nil
+}
+
+func (m *memoryStore) Delete(ctx context.Context, patientID, system string) error {
+	delete(m.identifiers, patientID+" "+system)
+	return nil
+}
+
+func (m *memoryStore) ListByPatients(ctx context.Context, patientIDs []string) ([]*domain.Identifier, error) {
+	return nil, nil
+}
+
+func (m *memoryStore) Enqueue(ctx context.Context, patientID string) error {
+	now := time.Now()
+	m.tasks[patientID] = &domain.IdentitySyncTask{PatientID: patientID, EnqueuedAt: now, NextAttemptAt: now}
+	return nil
+}
+
+func (m *memoryStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.IdentitySyncTask, error) {
+	var tasks []*domain.IdentitySyncTask
+	for _, task := range m.tasks {
+		if len(tasks) < limit && !task.NextAttemptAt.After(time.Now()) {
+			task.NextAttemptAt = time.Now().Add(lease)
+			claimed := *task
+			tasks = append(tasks, &claimed)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *memoryStore) Reschedule(ctx context.Context, task *domain.IdentitySyncTask) error {
+	if queued, ok := m.tasks[task.PatientID]; ok && queued.EnqueuedAt.Equal(task.EnqueuedAt) {
+		*queued = *task
+	}
+	return nil
+}
+
+func (m *memoryStore) Remove(ctx context.Context, task *domain.IdentitySyncTask) error {
+	if queued, ok := m.tasks[task.PatientID]; ok && queued.EnqueuedAt.Equal(task.EnqueuedAt) {
+		delete(m.tasks, task.PatientID)
+	}
+	return nil
+}
+
+func (m *memoryStore) Allowed(ctx context.Context, patientID, consentType string) (bool, error) {
+	return !m.denied[patientID], nil
+}
+
+func (m *memoryStore) AllowedAll(ctx context.Context, patientIDs []string, consentType string) (map[string]bool, error) {
+	allowed := make(map[string]bool)
+	for _, id := range patientIDs {
+		allowed[id] = !m.denied[id]
+	}
+	return allowed, nil
+}
+
+func TestSyncerStoresIHSNumberAndRetries(t *testing.T) {
+	server := satusehattest.NewServer()
+	defer server.Close()
+	server.AddPatient("3171234567890001", "P02478375538")
+	server.FailNext(http.StatusBadGateway)
+
+	store := newMemoryStore()
+	store.patients["p1"] = &domain.Patient{ID: "p1", NIK: "3171234567890001"}
+
+	syncer := NewSyncer(newTestClient(server), store, store, store, store, time.Minute, 3)
+	ctx := context.Background()
+
+	if err := syncer.Enqueue(ctx, "p1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := syncer.ProcessDue(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	task := store.tasks["p1"]
+	if task == nil || task.Attempts != 1 || task.LastError == "" || task.NextAttemptAt.Before(time.Now().Add(50*time.Second)) {
+		t.Fatalf("Expected the failed lookup to be rescheduled, got %+v", task)
+	}
+
+	// Due again
+	task.NextAttemptAt = time.Now()
+	if _, err := syncer.ProcessDue(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := store.identifiers["p1 "+fhir.SystemIHSNumber]; got != "P02478375538" {
+		t.Errorf("Expected IHS number to be stored, got %q", got)
+	}
+	if len(store.tasks) != 0 {
+		t.Errorf("Expected queue to be empty, got %d tasks", len(store.tasks))
+	}
+}
+
+func TestSyncerDropsUnknownNIK(t *testing.T) {
+	server := satusehattest.NewServer()
+	defer server.Close()
+
+	store := newMemoryStore()
+	store.patients["p1"] = &domain.Patient{ID: "p1", NIK: "3171234567890001"}
+	store.identifiers["p1 "+fhir.SystemIHSNumber] = "P-STALE"
+
+	syncer := NewSyncer(newTestClient(server), store, store, store, store, time.Minute, 3)
+	ctx := context.Background()
+
+	syncer.Enqueue(ctx, "p1")
+	if _, err := syncer.ProcessDue(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(store.tasks) != 0 {
+		t.Error("Expected unknown NIK not to be retried")
+	}
+	if _, ok := store.identifiers["p1 "+fhir.SystemIHSNumber]; ok {
+		t.Error("Expected stale IHS number to be removed")
+	}
+}
+
+func TestSyncerSkipsWithoutConsent(t *testing.T) {
+	server := satusehattest.NewServer()
+	defer server.Close()
+	server.AddPatient("3171234567890001", "P02478375538")
+
+	store := newMemoryStore()
+	store.patients["p1"] = &domain.Patient{ID: "p1", NIK: "3171234567890001"}
+	store.denied["p1"] = true
+
+	syncer := NewSyncer(newTestClient(server), store, store, store, store, time.Minute, 3)
+	ctx := context.Background()
+
+	syncer.Enqueue(ctx, "p1")
+	if _, err := syncer.ProcessDue(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(store.tasks) != 0 {
+		t.Error("Expected the task to be dropped")
+	}
+	if _, ok := store.identifiers["p1 "+fhir.SystemIHSNumber]; ok {
+		t.Error("Expected no lookup without consent")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 4: 8 * time.Minute, 20: maxBackoff}
+	for attempt, want := range cases {
+		if got := backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
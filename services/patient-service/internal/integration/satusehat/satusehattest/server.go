@@ -0,0 +1,215 @@
+// Package satusehattest provides a fake SATUSEHAT API for tests and local
+// development. It implements the client-credentials token endpoint and
+// Patient search by NIK.
+package satusehattest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	systemNIK = "https://fhir.kemkes.go.id/id/nik"
+
+	// Path prefixes of the real API
+	AuthPath = "/oauth2/v1"
+	FHIRPath = "/fhir-r4/v1"
+)
+
+// Fake is the fake API as an http.Handler.
+type Fake struct {
+	ClientID     string
+	ClientSecret string
+	TokenTTL     time.Duration
+
+	mu              sync.Mutex
+	patients        map[string]string // NIK -> IHS number
+	tokens          map[string]time.Time
+	failures        []int
+	tokenRequests   int
+	patientRequests int
+}
+
+// NewFake returns a fake accepting the given client credentials.
+func NewFake(clientID, clientSecret string) *Fake {
+	return &Fake{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenTTL:     time.Hour,
+		patients:     make(map[string]string),
+		tokens:       make(map[string]time.Time),
+	}
+}
+
+// AddPatient registers a patient that Patient search will find by NIK.
+func (f *Fake) AddPatient(nik, ihsNumber string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.patients[nik] = ihsNumber
+}
+
+// FailNext makes the next Patient requests fail with the given statuses,
+// one per request.
+func (f *Fake) FailNext(statuses ...int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = append(f.failures, statuses...)
+}
+
+// RevokeTokens invalidates all issued tokens, as if they had expired.
+func (f *Fake) RevokeTokens() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens = make(map[string]time.Time)
+}
+
+// TokenRequests returns the number of token requests served.
+func (f *Fake) TokenRequests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tokenRequests
+}
+
+// PatientRequests returns the number of Patient requests served.
+func (f *Fake) PatientRequests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.patientRequests
+}
+
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == AuthPath+"/accesstoken":
+		f.serveToken(w, r)
+	case r.Method == http.MethodGet && r.URL.Path == FHIRPath+"/Patient":
+		f.servePatientSearch(w, r)
+	default:
+		writeOutcome(w, http.StatusNotFound, "not-found", "Unknown endpoint "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func (f *Fake) serveToken(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokenRequests++
+
+	if r.URL.Query().Get("grant_type") != "client_credentials" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	if r.PostFormValue("client_id") != f.ClientID || r.PostFormValue("client_secret") != f.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	token := hex.EncodeToString(buf)
+	f.tokens[token] = time.Now().Add(f.TokenTTL)
+
+	// Like the real API, expires_in is a string
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": token,
+		"token_type":   "BearerToken",
+		"expires_in":   strconv.Itoa(int(f.TokenTTL.Seconds())),
+		"client_id":    f.ClientID,
+	})
+}
+
+func (f *Fake) servePatientSearch(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.patientRequests++
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if expiry, ok := f.tokens[token]; !ok || time.Now().After(expiry) {
+		writeOutcome(w, http.StatusUnauthorized, "login", "Invalid access token")
+		return
+	}
+
+	if len(f.failures) > 0 {
+		status := f.failures[0]
+		f.failures = f.failures[1:]
+		writeOutcome(w, status, "exception", http.StatusText(status))
+		return
+	}
+
+	system, nik, _ := strings.Cut(r.URL.Query().Get("identifier"), "|")
+	if system != systemNIK || nik == "" {
+		writeOutcome(w, http.StatusBadRequest, "invalid", "identifier must be "+systemNIK+"|<nik>")
+		return
+	}
+
+	bundle := map[string]interface{}{
+		"resourceType": "Bundle",
+		"type":         "searchset",
+		"total":        0,
+		"entry":        []interface{}{},
+	}
+	if ihsNumber, ok := f.patients[nik]; ok {
+		bundle["total"] = 1
+		bundle["entry"] = []interface{}{map[string]interface{}{
+			"fullUrl": "http://" + r.Host + FHIRPath + "/Patient/" + ihsNumber,
+			"resource": map[string]interface{}{
+				"resourceType": "Patient",
+				"id":           ihsNumber,
+				"identifier": []map[string]string{
+					{"system": "https://fhir.kemkes.go.id/id/ihs-number", "value": ihsNumber},
+				},
+			},
+		}}
+	}
+	writeJSON(w, http.StatusOK, bundle)
+}
+
+func writeOutcome(w http.ResponseWriter, status int, code, diagnostics string) {
+	writeJSON(w, status, map[string]interface{}{
+		"resourceType": "OperationOutcome",
+		"issue": []map[string]string{
+			{"severity": "error", "code": code, "diagnostics": diagnostics},
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Server is a Fake listening on a local port.
+type Server struct {
+	*Fake
+	URL string
+
+	server *httptest.Server
+}
+
+// NewServer starts a fake with client credentials "client-id" and
+// "client-secret". Close it when done.
+func NewServer() *Server {
+	fake := NewFake("client-id", "client-secret")
+	server := httptest.NewServer(fake)
+	return &Server{Fake: fake, URL: server.URL, server: server}
+}
+
+// AuthURL returns the value for SATUSEHAT_AUTH_URL.
+func (s *Server) AuthURL() string {
+	return s.URL + AuthPath
+}
+
+// BaseURL returns the value for SATUSEHAT_BASE_URL.
+func (s *Server) BaseURL() string {
+	return s.URL + FHIRPath
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
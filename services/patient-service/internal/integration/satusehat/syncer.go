@@ -0,0 +1,192 @@
+// Background IHS number resolution
+// internal/integration/satusehat/syncer.go
+package satusehat
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"patient-service/internal/consent"
+	"patient-service/internal/domain"
+	"patient-service/internal/fhir"
+	"patient-service/internal/repository"
+	"patient-service/internal/tenant"
+)
+
+// Retry schedule: the delay doubles from minBackoff up to maxBackoff.
+const (
+	minBackoff = time.Minute
+	maxBackoff = 6 * time.Hour
+
+	claimBatch = 20
+	claimLease = 5 * time.Minute
+)
+
+// Resolver looks up IHS numbers; *Client implements it.
+type Resolver interface {
+	FindPatientByNIK(ctx context.Context, nik string) (string, error)
+}
+
+// PatientSource loads the patient whose NIK is looked up.
+type PatientSource interface {
+	GetByID(ctx context.Context, id string) (*domain.Patient, error)
+}
+
+// Syncer resolves queued patients' IHS numbers and stores them as
+// identifiers. Failed lookups stay queued and are retried with backoff.
+// Patients who do not allow sharing with SATUSEHAT are not looked up.
+type Syncer struct {
+	resolver    Resolver
+	patients    PatientSource
+	identifiers repository.IdentifierRepository
+	queue       repository.IdentitySyncQueue
+	consents    consent.ConsentChecker
+	interval    time.Duration
+	maxAttempts int
+	wake        chan struct{}
+}
+
+// NewSyncer creates a syncer that polls the queue every interval and gives
+// up on a patient after maxAttempts retryable failures.
+func NewSyncer(resolver Resolver, patients PatientSource, identifiers repository.IdentifierRepository, queue repository.IdentitySyncQueue, consents consent.ConsentChecker, interval time.Duration, maxAttempts int) *Syncer {
+	return &Syncer{
+		resolver:    resolver,
+		patients:    patients,
+		identifiers: identifiers,
+		queue:       queue,
+		consents:    consents,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Enqueue schedules a lookup for the patient and wakes the worker.
+func (s *Syncer) Enqueue(ctx context.Context, patientID string) error {
+	if err := s.queue.Enqueue(ctx, patientID); err != nil {
+		return err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run processes due tasks until ctx is cancelled.
+func (s *Syncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := s.ProcessDue(ctx)
+			if err != nil {
+				log.Printf("SATUSEHAT sync failed: %v", err)
+			}
+			if err != nil || n < claimBatch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// ProcessDue claims one batch of due tasks and processes it, returning the
+// number of tasks claimed.
+func (s *Syncer) ProcessDue(ctx context.Context) (int, error) {
+	tasks, err := s.queue.Claim(ctx, claimBatch, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, task := range tasks {
+		if err := s.process(ctx, task); err != nil {
+			return len(tasks), err
+		}
+	}
+	return len(tasks), nil
+}
+
+// process resolves one task, acting for the patient's tenant. Only queue
+// and storage errors are returned; lookup failures are recorded on the task.
+func (s *Syncer) process(ctx context.Context, task *domain.IdentitySyncTask) error {
+	ctx = tenant.NewContext(ctx, task.TenantID)
+	patient, err := s.patients.GetByID(ctx, task.PatientID)
+	if err == domain.ErrPatientNotFound {
+		return s.queue.Remove(ctx, task)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The lookup sends the NIK to SATUSEHAT
+	allowed, err := s.consents.Allowed(ctx, patient.ID, domain.ConsentSatuSehat)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		log.Printf("SATUSEHAT lookup for patient %s skipped: no consent", patient.ID)
+		return s.queue.Remove(ctx, task)
+	}
+
+	ihsNumber, err := s.resolver.FindPatientByNIK(ctx, patient.NIK)
+	if err == nil {
+		err = s.identifiers.Upsert(ctx, &domain.Identifier{
+			PatientID: patient.ID,
+			System:    fhir.SystemIHSNumber,
+			Value:     ihsNumber,
+		})
+		if err != nil {
+			return err
+		}
+		return s.queue.Remove(ctx, task)
+	}
+
+	if !Retryable(err) {
+		// The NIK no longer resolves, so a stored IHS number is stale
+		log.Printf("SATUSEHAT lookup for patient %s failed permanently: %v", patient.ID, err)
+		if err := s.identifiers.Delete(ctx, patient.ID, fhir.SystemIHSNumber); err != nil {
+			return err
+		}
+		return s.queue.Remove(ctx, task)
+	}
+
+	task.Attempts++
+	if task.Attempts >= s.maxAttempts {
+		log.Printf("SATUSEHAT lookup for patient %s abandoned after %d attempt(s): %v", patient.ID, task.Attempts, err)
+		return s.queue.Remove(ctx, task)
+	}
+
+	task.LastError = truncate(err.Error(), 1000)
+	task.NextAttemptAt = time.Now().Add(backoff(task.Attempts))
+	log.Printf("SATUSEHAT lookup for patient %s failed, retrying at %s: %v", patient.ID, task.NextAttemptAt.Format(time.RFC3339), err)
+	return s.queue.Reschedule(ctx, task)
+}
+
+// backoff returns the delay before the retry following attempt n (1-based).
+func backoff(n int) time.Duration {
+	delay := minBackoff
+	for i := 1; i < n && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
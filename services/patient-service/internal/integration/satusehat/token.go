@@ -0,0 +1,81 @@
+// OAuth2 client-credentials token cache
+// internal/integration/satusehat/token.go
+package satusehat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// expiryMargin renews tokens before they expire so requests in flight do
+// not fail with 401.
+const expiryMargin = time.Minute
+
+type tokenSource struct {
+	cfg  Config
+	http *http.Client
+
+	mu     sync.Mutex
+	value  string
+	expiry time.Time
+}
+
+// token returns the cached token or requests a new one. Concurrent callers
+// wait for a single request.
+func (s *tokenSource) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.value != "" && time.Now().Before(s.expiry.Add(-expiryMargin)) {
+		return s.value, nil
+	}
+
+	form := url.Values{
+		"client_id":     {s.cfg.ClientID},
+		"client_secret": {s.cfg.ClientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		s.cfg.AuthURL+"/accesstoken?grant_type=client_credentials", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		AccessToken string          `json:"access_token"`
+		ExpiresIn   json.RawMessage `json:"expires_in"` // SATUSEHAT sends a string
+	}
+	if err := decodeResponse(resp, &body); err != nil {
+		return "", err
+	}
+
+	seconds, err := strconv.Atoi(strings.Trim(string(body.ExpiresIn), `"`))
+	if err != nil || body.AccessToken == "" {
+		return "", &APIError{StatusCode: resp.StatusCode, Body: "token response without access_token or expires_in"}
+	}
+
+	s.value = body.AccessToken
+	s.expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	return s.value, nil
+}
+
+// invalidate drops the cached token if it is still the rejected one.
+func (s *tokenSource) invalidate(rejected string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.value == rejected {
+		s.value = ""
+	}
+}
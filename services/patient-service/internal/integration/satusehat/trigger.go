@@ -0,0 +1,62 @@
+// Queues IHS lookups after patient writes
+// internal/integration/satusehat/trigger.go
+package satusehat
+
+import (
+	"context"
+	"log"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/service"
+)
+
+// Trigger decorates a PatientService and queues an IHS number lookup when
+// a patient is created or their NIK changes. Queueing failures are logged;
+// they never fail the write.
+type Trigger struct {
+	service.PatientService
+	syncer *Syncer
+}
+
+func NewTrigger(next service.PatientService, syncer *Syncer) *Trigger {
+	return &Trigger{PatientService: next, syncer: syncer}
+}
+
+func (t *Trigger) CreatePatient(ctx context.Context, patient *domain.Patient) (*domain.Patient, error) {
+	created, err := t.PatientService.CreatePatient(ctx, patient)
+	if err == nil {
+		t.enqueue(ctx, created.ID)
+	}
+	return created, err
+}
+
+func (t *Trigger) UpdatePatient(ctx context.Context, patient *domain.Patient) (*domain.Patient, error) {
+	existing, err := t.PatientService.GetPatient(ctx, patient.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := t.PatientService.UpdatePatient(ctx, patient)
+	if err == nil && updated.NIK != existing.NIK {
+		t.enqueue(ctx, updated.ID)
+	}
+	return updated, err
+}
+
+func (t *Trigger) PatchPatient(ctx context.Context, id string, patch domain.PatientPatch) (*domain.Patient, error) {
+	var before string
+	updated, err := t.PatientService.PatchPatient(ctx, id, func(patient *domain.Patient) error {
+		before = patient.NIK
+		return patch(patient)
+	})
+	if err == nil && updated.NIK != before {
+		t.enqueue(ctx, updated.ID)
+	}
+	return updated, err
+}
+
+func (t *Trigger) enqueue(ctx context.Context, patientID string) {
+	if err := t.syncer.Enqueue(ctx, patientID); err != nil {
+		log.Printf("Failed to queue SATUSEHAT lookup for patient %s: %v", patientID, err)
+	}
+}
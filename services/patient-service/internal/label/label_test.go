@@ -0,0 +1,123 @@
+package label
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+func testPatient() *domain.Patient {
+	return &domain.Patient{
+		MedicalRecordNo: "MR202401010001",
+		FirstName:       "Siti Nurhaliza",
+		LastName:        "Binti Abdurrahman Wahid Kusumawardhani",
+		DateOfBirth:     time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Gender:          "FEMALE",
+		BloodType:       "O+",
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.json")
+	os.WriteFile(path, []byte(`{
+		"card": {"default": {"subtitle": "Jl. Mayjen Prof. Dr. Moestopo No. 6-8", "labels": {"mrn": "MRN"}}},
+		"wristband": {"infant": {"width_mm": 150, "height_mm": 20, "fields": ["name", "mrn", "dob"], "code": "code128"}}
+	}`), 0o600)
+
+	templates, err := LoadTemplates(path, "RSUD Sehat")
+	if err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	card := templates[KindCard][DefaultTemplate]
+	if card.HospitalName != "RSUD Sehat" || card.Subtitle == "" || card.WidthMM != 85.6 {
+		t.Errorf("Expected the card to keep the defaults it does not change, got %+v", card)
+	}
+	if card.Labels[FieldMRN] != "MRN" || card.Labels[FieldDOB] != "Tgl. Lahir" {
+		t.Errorf("Expected labels to be merged, got %v", card.Labels)
+	}
+	if templates[KindWristband][DefaultTemplate].Labels[FieldMRN] != "No. RM" {
+		t.Error("Expected the built-in labels to be left unchanged")
+	}
+	infant := templates[KindWristband]["infant"]
+	if infant.WidthMM != 150 || infant.Code != CodeCode128 || !infant.Rotate {
+		t.Errorf("Unexpected infant template %+v", infant)
+	}
+
+	os.WriteFile(path, []byte(`{"card": {"default": {"fields": ["name", "nik"]}}}`), 0o600)
+	if _, err := LoadTemplates(path, ""); err == nil || !strings.Contains(err.Error(), "nik") {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+}
+
+func TestLayoutFitsLabel(t *testing.T) {
+	for kind, template := range DefaultTemplates("RSUD Sehat") {
+		for _, code := range []string{CodeQR, CodeCode128} {
+			tpl := template[DefaultTemplate]
+			tpl.Code = code
+			l, err := NewLayout(tpl, testPatient())
+			if err != nil {
+				t.Fatalf("NewLayout failed: %v", err)
+			}
+
+			for _, text := range l.Texts {
+				width := textWidth(text.Value, text.Size, text.Bold)
+				if text.X+width > tpl.WidthMM || text.Y+text.Size*mmPerPoint > tpl.HeightMM {
+					t.Errorf("%s/%s: %q does not fit", kind, code, text.Value)
+				}
+				overlaps := text.X+width > l.Code.X && text.X < l.Code.X+l.Code.Width &&
+					text.Y+text.Size*mmPerPoint > l.Code.Y && text.Y < l.Code.Y+l.Code.Height
+				if overlaps {
+					t.Errorf("%s/%s: %q overlaps the code", kind, code, text.Value)
+				}
+			}
+			if l.Code.X+l.Code.Width > tpl.WidthMM || l.Code.Y+l.Code.Height > tpl.HeightMM {
+				t.Errorf("%s/%s: code does not fit", kind, code)
+			}
+		}
+	}
+
+	card, _ := NewLayout(DefaultTemplates("")[KindCard][DefaultTemplate], testPatient())
+	if name := card.Texts[0].Value; !strings.HasSuffix(name, "…") || !card.Texts[0].Bold {
+		t.Errorf("Expected the long name to be shortened, got %q", name)
+	}
+	if card.Texts[3].Value != "Jenis Kelamin: Perempuan" {
+		t.Errorf("Expected the gender caption, got %q", card.Texts[3].Value)
+	}
+}
+
+func TestRender(t *testing.T) {
+	templates := DefaultTemplates("RSUD ^Sehat~")
+	card, _ := NewLayout(templates[KindCard][DefaultTemplate], testPatient())
+	wristband, _ := NewLayout(templates[KindWristband][DefaultTemplate], testPatient())
+
+	pdf, err := Render(card, FormatPDF)
+	if err != nil || !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Fatalf("Expected a PDF, got %v", err)
+	}
+
+	data, err := Render(card, FormatPNG)
+	if err != nil {
+		t.Fatalf("Render PNG failed: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Invalid PNG: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 1011 || size.Y != 638 {
+		t.Errorf("Expected 85.6x54mm at 300dpi, got %v", size)
+	}
+
+	zpl, _ := Render(wristband, FormatZPL)
+	for _, want := range []string{"^XA", "^PW200\n^LL1439", "^A0R,", "^FDRSUD _5ESehat_7E^FS", "^FDMA,MR202401010001^FS", "^XZ"} {
+		if !strings.Contains(string(zpl), want) {
+			t.Errorf("Expected %q in\n%s", want, zpl)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Card and wristband layout
+// internal/label/layout.go
+package label
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+
+	"patient-service/internal/domain"
+)
+
+// mmPerPoint converts font sizes to millimetres
+const mmPerPoint = 25.4 / 72
+
+// Layout sizes relative to the text size
+const (
+	nameScale   = 1.35 // the patient name is printed larger
+	lineSpacing = 1.25
+	maxTextSize = 10.0 // points
+)
+
+// Fonts are the Go fonts, embedded in PDFs and used for PNGs, so both
+// print with the metrics the layout was measured with.
+var (
+	regularFont = mustParse(goregular.TTF)
+	boldFont    = mustParse(gobold.TTF)
+	ascent      = fontAscent(regularFont)
+)
+
+func mustParse(ttf []byte) *sfnt.Font {
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Text is a line of text; X and Y are its top left corner in millimetres
+// and Size is in points.
+type Text struct {
+	X, Y  float64
+	Size  float64
+	Bold  bool
+	Value string
+}
+
+// Baseline returns the Y of the text's baseline in millimetres.
+func (t Text) Baseline() float64 {
+	return t.Y + t.Size*mmPerPoint*ascent
+}
+
+// Rule is a horizontal line under the header.
+type Rule struct {
+	X, Y, Width float64
+}
+
+// Code is the QR code or barcode of the MRN and the box it is drawn in.
+type Code struct {
+	X, Y, Width, Height float64
+	Type                string
+	Data                string
+	Modules             barcode.Barcode
+}
+
+// Layout is a card or wristband with everything placed, in millimetres
+// from the top left corner.
+type Layout struct {
+	Template Template
+	Texts    []Text
+	Rules    []Rule
+	Code     Code
+}
+
+// NewLayout places the template's fields for the patient.
+func NewLayout(t Template, patient *domain.Patient) (*Layout, error) {
+	if patient.MedicalRecordNo == "" {
+		return nil, fmt.Errorf("patient has no medical record number")
+	}
+	l := &Layout{Template: t}
+	w, h := t.WidthMM, t.HeightMM
+	margin := math.Min(3, h*0.08)
+	y := margin
+
+	// Header across the full width
+	if t.HospitalName != "" || t.Subtitle != "" {
+		headerSize := math.Max(5, math.Min(maxTextSize, h*0.3))
+		if t.HospitalName != "" {
+			l.Texts = append(l.Texts, fitText(margin, y, headerSize, true, t.HospitalName, w-2*margin))
+			y += headerSize * mmPerPoint * lineSpacing
+		}
+		if t.Subtitle != "" {
+			size := headerSize * 0.7
+			l.Texts = append(l.Texts, fitText(margin, y, size, false, t.Subtitle, w-2*margin))
+			y += size * mmPerPoint * lineSpacing
+		}
+		l.Rules = append(l.Rules, Rule{X: margin, Y: y, Width: w - 2*margin})
+		y += 1.5
+	}
+
+	// The name, when first, spans the text width; the other fields fill
+	// the columns top to bottom. The first column, which has the most
+	// lines, sets the text size.
+	top, bottom := y, h-margin
+	fields := t.Fields
+	withName := fields[0] == FieldName
+	if withName {
+		fields = fields[1:]
+	}
+	rows := (len(fields) + t.Columns - 1) / t.Columns
+	weight := float64(rows)
+	if withName {
+		weight += nameScale
+	}
+	size := math.Min(maxTextSize, (bottom-top)/(weight*lineSpacing*mmPerPoint))
+	textWidth := w - 2*margin
+	code, err := encode(t.Code, patient.MedicalRecordNo)
+	if err != nil {
+		return nil, err
+	}
+	l.Code = Code{Type: t.Code, Data: patient.MedicalRecordNo, Modules: code}
+
+	// A QR code takes the right of the body. On long labels such as
+	// wristbands it runs beside the name too, to be large enough to scan.
+	if t.Code == CodeQR && w >= 3*h {
+		side := bottom - top
+		l.Code.X, l.Code.Y, l.Code.Width, l.Code.Height = w-margin-side, top, side, side
+		textWidth -= side + margin
+	}
+	if withName {
+		l.Texts = append(l.Texts, fitText(margin, top, size*nameScale, true, fieldText(t, FieldName, patient), textWidth))
+		top += size * nameScale * mmPerPoint * lineSpacing
+	}
+
+	// Otherwise the code is placed under the name: QR codes at the right,
+	// barcodes at the bottom
+	if t.Code == CodeQR && w < 3*h {
+		side := math.Min(bottom-top, textWidth*0.35)
+		l.Code.X, l.Code.Y, l.Code.Width, l.Code.Height = w-margin-side, top, side, side
+		textWidth -= side + margin
+	} else if t.Code == CodeCode128 {
+		height := math.Min((bottom-top)/2, math.Max(8, (bottom-top)*0.3))
+		width := math.Min(textWidth, float64(code.Bounds().Dx())*0.5)
+		l.Code.X, l.Code.Y, l.Code.Width, l.Code.Height = margin, bottom-height, width, height
+		bottom -= height + 1
+		size = math.Min(size, (bottom-top)/(float64(rows)*lineSpacing*mmPerPoint))
+	}
+
+	columnWidth := (textWidth - float64(t.Columns-1)*margin) / float64(t.Columns)
+	for i, field := range fields {
+		column, row := i/rows, i%rows
+		if row == 0 {
+			y = top
+		}
+		x := margin + float64(column)*(columnWidth+margin)
+		l.Texts = append(l.Texts, fitText(x, y, size, false, fieldText(t, field, patient), columnWidth))
+		y += size * mmPerPoint * lineSpacing
+	}
+	return l, nil
+}
+
+func fieldText(t Template, field string, patient *domain.Patient) string {
+	var value string
+	switch field {
+	case FieldName:
+		return strings.TrimSpace(patient.FirstName + " " + patient.LastName)
+	case FieldMRN:
+		value = patient.MedicalRecordNo
+	case FieldDOB:
+		value = patient.DateOfBirth.Format(t.DateFormat)
+	case FieldGender:
+		value = patient.Gender
+		if caption, ok := t.Labels[strings.ToLower(patient.Gender)]; ok {
+			value = caption
+		}
+	case FieldBloodType:
+		value = patient.BloodType
+	}
+	if value == "" {
+		value = "-"
+	}
+	if caption := t.Labels[field]; caption != "" {
+		return caption + ": " + value
+	}
+	return value
+}
+
+func encode(codeType, data string) (barcode.Barcode, error) {
+	if codeType == CodeQR {
+		return qr.Encode(data, qr.M, qr.Auto)
+	}
+	return code128.Encode(data)
+}
+
+// fitText shortens the value with an ellipsis until it fits the width.
+func fitText(x, y, size float64, bold bool, value string, width float64) Text {
+	text := Text{X: x, Y: y, Size: size, Bold: bold, Value: value}
+	if textWidth(value, size, bold) <= width {
+		return text
+	}
+	runes := []rune(value)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		text.Value = strings.TrimSpace(string(runes)) + "…"
+		if textWidth(text.Value, size, bold) <= width {
+			break
+		}
+	}
+	return text
+}
+
+// textWidth measures a line in millimetres.
+func textWidth(s string, size float64, bold bool) float64 {
+	f := regularFont
+	if bold {
+		f = boldFont
+	}
+	var buf sfnt.Buffer
+	ppem := fixed.I(1000)
+	var total fixed.Int26_6
+	for _, r := range s {
+		glyph, err := f.GlyphIndex(&buf, r)
+		if err != nil {
+			continue
+		}
+		advance, err := f.GlyphAdvance(&buf, glyph, ppem, font.HintingNone)
+		if err == nil {
+			total += advance
+		}
+	}
+	return float64(total) / 64 / 1000 * size * mmPerPoint
+}
+
+func fontAscent(f *sfnt.Font) float64 {
+	var buf sfnt.Buffer
+	metrics, err := f.Metrics(&buf, fixed.I(1000), font.HintingNone)
+	if err != nil {
+		panic(err)
+	}
+	return float64(metrics.Ascent) / 64 / 1000
+}
+
+// darkRuns calls fn for each horizontal run of dark modules of the code,
+// in module coordinates.
+func darkRuns(code barcode.Barcode, fn func(x, y, n int)) {
+	bounds := code.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		start := -1
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			dark := false
+			if x < bounds.Max.X {
+				r, _, _, _ := code.At(x, y).RGBA()
+				dark = r < 0x8000
+			}
+			if dark && start < 0 {
+				start = x
+			}
+			if !dark && start >= 0 {
+				fn(start-bounds.Min.X, y-bounds.Min.Y, x-start)
+				start = -1
+			}
+		}
+	}
+}
+
+// moduleSize returns the size of a module and the offset of the first
+// one, leaving a quiet zone around QR codes; barcodes are placed in the
+// label's margin, which serves as theirs.
+func (c Code) moduleSize() (mw, mh, offsetX, offsetY float64) {
+	bounds := c.Modules.Bounds()
+	if c.Type == CodeQR {
+		quiet := 2
+		mw = c.Width / float64(bounds.Dx()+2*quiet)
+		return mw, mw, mw * float64(quiet), mw * float64(quiet)
+	}
+	return c.Width / float64(bounds.Dx()), c.Height / float64(bounds.Dy()), 0, 0
+}
@@ -0,0 +1,200 @@
+// Card and wristband rendering
+// internal/label/render.go
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// ContentTypes of the output formats
+var ContentTypes = map[string]string{
+	FormatPDF: "application/pdf",
+	FormatPNG: "image/png",
+	FormatZPL: "application/x-zpl",
+}
+
+// Render writes the layout in the format.
+func Render(l *Layout, format string) ([]byte, error) {
+	switch format {
+	case FormatPDF:
+		return renderPDF(l)
+	case FormatPNG:
+		return renderPNG(l)
+	case FormatZPL:
+		return renderZPL(l), nil
+	}
+	return nil, fmt.Errorf("unknown label format %q", format)
+}
+
+// renderPDF draws a single page of the label's size, with the fonts
+// embedded and the code as vector rectangles.
+func renderPDF(l *Layout) ([]byte, error) {
+	t := l.Template
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: t.WidthMM, Ht: t.HeightMM},
+	})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddUTF8FontFromBytes("go", "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes("go", "B", gobold.TTF)
+	pdf.AddPage()
+
+	for _, text := range l.Texts {
+		style := ""
+		if text.Bold {
+			style = "B"
+		}
+		pdf.SetFont("go", style, text.Size)
+		pdf.Text(text.X, text.Baseline(), text.Value)
+	}
+
+	pdf.SetLineWidth(0.2)
+	for _, rule := range l.Rules {
+		pdf.Line(rule.X, rule.Y, rule.X+rule.Width, rule.Y)
+	}
+
+	mw, mh, offsetX, offsetY := l.Code.moduleSize()
+	darkRuns(l.Code.Modules, func(x, y, n int) {
+		pdf.Rect(l.Code.X+offsetX+float64(x)*mw, l.Code.Y+offsetY+float64(y)*mh, float64(n)*mw, mh, "F")
+	})
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderPNG rasterizes the label at the template's DPI.
+func renderPNG(l *Layout) ([]byte, error) {
+	t := l.Template
+	scale := float64(t.DPI) / 25.4 // pixels per millimetre
+	px := func(mm float64) int { return int(math.Round(mm * scale)) }
+
+	img := image.NewGray(image.Rect(0, 0, px(t.WidthMM), px(t.HeightMM)))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	for _, text := range l.Texts {
+		f := regularFont
+		if text.Bold {
+			f = boldFont
+		}
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: text.Size, DPI: float64(t.DPI), Hinting: font.HintingFull})
+		if err != nil {
+			return nil, err
+		}
+		drawer := font.Drawer{
+			Dst:  img,
+			Src:  image.Black,
+			Face: face,
+			Dot:  fixed.P(px(text.X), px(text.Baseline())),
+		}
+		drawer.DrawString(text.Value)
+		face.Close()
+	}
+
+	thickness := int(math.Max(1, math.Round(0.2*scale)))
+	for _, rule := range l.Rules {
+		r := image.Rect(px(rule.X), px(rule.Y), px(rule.X+rule.Width), px(rule.Y)+thickness)
+		draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+	}
+
+	// Module edges are rounded to whole pixels, so modules stay sharp
+	mw, mh, offsetX, offsetY := l.Code.moduleSize()
+	x0, y0 := l.Code.X+offsetX, l.Code.Y+offsetY
+	darkRuns(l.Code.Modules, func(x, y, n int) {
+		r := image.Rect(px(x0+float64(x)*mw), px(y0+float64(y)*mh), px(x0+float64(x+n)*mw), px(y0+float64(y+1)*mh))
+		draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+	})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to render PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderZPL writes the label for Zebra printers with the printer's
+// scalable font and barcode commands. Rotated labels are printed along
+// the feed direction, as wristbands are.
+func renderZPL(l *Layout) []byte {
+	t := l.Template
+	dots := func(mm float64) int { return int(math.Round(mm * float64(t.DPI) / 25.4)) }
+	width, height := dots(t.WidthMM), dots(t.HeightMM)
+
+	// place returns the field origin and orientation of a box of the
+	// layout; rotated boxes are turned 90 degrees clockwise
+	place := func(x, y, w, h float64) (int, int, string) {
+		if !t.Rotate {
+			return dots(x), dots(y), "N"
+		}
+		return height - dots(y) - dots(h), dots(x), "R"
+	}
+
+	var b strings.Builder
+	b.WriteString("^XA\n^CI28\n")
+	if t.Rotate {
+		fmt.Fprintf(&b, "^PW%d\n^LL%d\n", height, width)
+	} else {
+		fmt.Fprintf(&b, "^PW%d\n^LL%d\n", width, height)
+	}
+	b.WriteString("^LH0,0\n")
+
+	for _, text := range l.Texts {
+		size := text.Size * mmPerPoint
+		x, y, orientation := place(text.X, text.Y, 0, size)
+		fmt.Fprintf(&b, "^FO%d,%d^A0%s,%d,%d^FH^FD%s^FS\n", x, y, orientation, dots(size), dots(size), zplEscape(text.Value))
+	}
+
+	for _, rule := range l.Rules {
+		x, y, _ := place(rule.X, rule.Y, rule.Width, 0.2)
+		thickness := dots(0.2)
+		if thickness < 1 {
+			thickness = 1
+		}
+		if t.Rotate {
+			fmt.Fprintf(&b, "^FO%d,%d^GB%d,%d,%d^FS\n", x, y, thickness, dots(rule.Width), thickness)
+		} else {
+			fmt.Fprintf(&b, "^FO%d,%d^GB%d,%d,%d^FS\n", x, y, dots(rule.Width), thickness, thickness)
+		}
+	}
+
+	code := l.Code
+	bounds := code.Modules.Bounds()
+	if code.Type == CodeQR {
+		// The printer encodes the data itself; the magnification fits
+		// the modules of the same code, quiet zone included
+		magnification := dots(code.Width) / (bounds.Dx() + 4)
+		magnification = int(math.Max(1, math.Min(10, float64(magnification))))
+		x, y, _ := place(code.X, code.Y, code.Width, code.Height)
+		fmt.Fprintf(&b, "^FO%d,%d^BQN,2,%d^FH^FDMA,%s^FS\n", x, y, magnification, zplEscape(code.Data))
+	} else {
+		moduleWidth := int(math.Max(1, float64(dots(code.Width)/bounds.Dx())))
+		x, y, orientation := place(code.X, code.Y, code.Width, code.Height)
+		fmt.Fprintf(&b, "^BY%d^FO%d,%d^BC%s,%d,N,N,N^FH^FD%s^FS\n", moduleWidth, x, y, orientation, dots(code.Height), zplEscape(code.Data))
+	}
+
+	b.WriteString("^PQ1\n^XZ\n")
+	return []byte(b.String())
+}
+
+// zplEscape hex-escapes the characters ZPL treats as commands, for use
+// after ^FH.
+func zplEscape(s string) string {
+	return strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E").Replace(s)
+}
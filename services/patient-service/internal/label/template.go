@@ -0,0 +1,180 @@
+// Patient card and wristband templates
+// internal/label/template.go
+package label
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Label kinds
+const (
+	KindCard      = "card"
+	KindWristband = "wristband"
+)
+
+// Output formats
+const (
+	FormatPDF = "pdf"
+	FormatPNG = "png"
+	FormatZPL = "zpl"
+)
+
+// Formats lists the formats each kind may be rendered in: cards go to
+// card or office printers, wristbands to Zebra label printers.
+var Formats = map[string][]string{
+	KindCard:      {FormatPDF, FormatPNG},
+	KindWristband: {FormatZPL, FormatPDF},
+}
+
+// Printed fields
+const (
+	FieldName      = "name"
+	FieldMRN       = "mrn"
+	FieldDOB       = "dob"
+	FieldGender    = "gender"
+	FieldBloodType = "blood_type"
+)
+
+// Codes encoding the MRN
+const (
+	CodeQR      = "qr"
+	CodeCode128 = "code128"
+)
+
+// DefaultTemplate is the name of the template used when none is requested.
+const DefaultTemplate = "default"
+
+// Template describes one card or wristband design. Sizes are of the
+// printed area in landscape, with the long side as the width.
+type Template struct {
+	HospitalName string            `json:"hospital_name"`
+	Subtitle     string            `json:"subtitle"` // e.g. address or phone, printed under the name
+	WidthMM      float64           `json:"width_mm"`
+	HeightMM     float64           `json:"height_mm"`
+	Fields       []string          `json:"fields"`  // printed top to bottom, name first if present
+	Columns      int               `json:"columns"` // fields are spread over columns on long labels
+	Code         string            `json:"code"`    // qr or code128
+	Labels       map[string]string `json:"labels"`  // captions and gender values, by field or "male"/"female"
+	DateFormat   string            `json:"date_format"`
+	DPI          int               `json:"dpi"`    // PNG resolution and ZPL printer density
+	Rotate       bool              `json:"rotate"` // ZPL only: the printer feeds along the label's width, as for wristbands
+}
+
+// Templates holds the templates of each kind by name.
+type Templates map[string]map[string]Template
+
+var defaultLabels = map[string]string{
+	FieldMRN:       "No. RM",
+	FieldDOB:       "Tgl. Lahir",
+	FieldGender:    "Jenis Kelamin",
+	FieldBloodType: "Gol. Darah",
+	"male":         "Laki-laki",
+	"female":       "Perempuan",
+}
+
+// DefaultTemplates returns the built-in templates: an ID-1 sized card
+// printed at 300 dpi and an adult wristband for 203 dpi Zebra printers.
+func DefaultTemplates(hospitalName string) Templates {
+	fields := []string{FieldName, FieldMRN, FieldDOB, FieldGender, FieldBloodType}
+	return Templates{
+		KindCard: {DefaultTemplate: {
+			HospitalName: hospitalName,
+			WidthMM:      85.6,
+			HeightMM:     54,
+			Fields:       fields,
+			Columns:      1,
+			Code:         CodeQR,
+			Labels:       cloneLabels(defaultLabels),
+			DateFormat:   "02-01-2006",
+			DPI:          300,
+		}},
+		KindWristband: {DefaultTemplate: {
+			HospitalName: hospitalName,
+			WidthMM:      180,
+			HeightMM:     25,
+			Fields:       fields,
+			Columns:      2,
+			Code:         CodeQR,
+			Labels:       cloneLabels(defaultLabels),
+			DateFormat:   "02-01-2006",
+			DPI:          203,
+			Rotate:       true,
+		}},
+	}
+}
+
+// LoadTemplates reads templates from a JSON file of the form
+// {"card": {"default": {...}}, "wristband": {"infant": {...}}}. Each
+// template starts from the built-in default of its kind, so a file only
+// needs the settings it changes. An empty path returns the built-ins.
+func LoadTemplates(path, hospitalName string) (Templates, error) {
+	templates := DefaultTemplates(hospitalName)
+	if path == "" {
+		return templates, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read label templates: %w", err)
+	}
+	var file map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid label templates: %w", err)
+	}
+
+	for kind, named := range file {
+		base, ok := templates[kind][DefaultTemplate]
+		if !ok {
+			return nil, fmt.Errorf("invalid label templates: unknown kind %q", kind)
+		}
+		for name, raw := range named {
+			t := base
+			t.Labels = cloneLabels(base.Labels)
+			if err := json.Unmarshal(raw, &t); err != nil {
+				return nil, fmt.Errorf("invalid label template %s/%s: %w", kind, name, err)
+			}
+			if err := t.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid label template %s/%s: %w", kind, name, err)
+			}
+			templates[kind][name] = t
+		}
+	}
+	return templates, nil
+}
+
+// Validate checks that the template can be laid out.
+func (t Template) Validate() error {
+	if t.WidthMM <= 0 || t.HeightMM <= 0 || t.WidthMM < t.HeightMM {
+		return fmt.Errorf("width_mm and height_mm must be positive, width the longer side")
+	}
+	if len(t.Fields) == 0 {
+		return fmt.Errorf("at least one field is required")
+	}
+	for _, field := range t.Fields {
+		switch field {
+		case FieldName, FieldMRN, FieldDOB, FieldGender, FieldBloodType:
+		default:
+			return fmt.Errorf("unknown field %q", field)
+		}
+	}
+	if t.Columns < 1 {
+		return fmt.Errorf("columns must be at least 1")
+	}
+	if t.Code != CodeQR && t.Code != CodeCode128 {
+		return fmt.Errorf("code must be qr or code128")
+	}
+	if t.DPI < 72 || t.DPI > 1200 {
+		return fmt.Errorf("dpi must be between 72 and 1200")
+	}
+	return nil
+}
+
+func cloneLabels(labels map[string]string) map[string]string {
+	clone := make(map[string]string, len(labels))
+	for k, v := range labels {
+		clone[k] = v
+	}
+	return clone
+}
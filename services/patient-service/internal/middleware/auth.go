@@ -2,21 +2,28 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+
+	"patient-service/internal/practitioners"
+	"patient-service/internal/tenant"
 )
 
 type Claims struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	TenantID string `json:"tenant_id,omitempty"` // facility the token acts for; empty for the default tenant
 	jwt.RegisteredClaims
 }
 
-func JWTAuth(secret string) fiber.Handler {
+// JWTAuth validates the bearer token and stores the caller and the tenant
+// it acts for in the request locals.
+func JWTAuth(secret string, tenants *tenant.Registry) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Get token from header
 		authHeader := c.Get("Authorization")
@@ -43,10 +50,15 @@ func JWTAuth(secret string) fiber.Handler {
 		tokenString := tokenParts[1]
 
 		// Parse and validate token
-		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
-
+		claims, err := ParseToken(tokenString, secret)
+		if err == errInvalidClaims {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": fiber.Map{
+					"code":    "INVALID_CLAIMS",
+					"message": "Invalid token claims",
+				},
+			})
+		}
 		if err != nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": fiber.Map{
@@ -56,19 +68,87 @@ func JWTAuth(secret string) fiber.Handler {
 			})
 		}
 
-		// Extract claims
-		if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-			// Store user info in context
-			c.Locals("userID", claims.UserID)
-			c.Locals("username", claims.Username)
-			c.Locals("role", claims.Role)
-			return c.Next()
+		tenantID, err := ResolveTenant(claims, tenants)
+		if err == ErrNoTenant {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": fiber.Map{
+					"code":    "NO_TENANT",
+					"message": "Token has no tenant",
+				},
+			})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": fiber.Map{
+					"code":    "UNKNOWN_TENANT",
+					"message": "Token acts for an unknown tenant",
+				},
+			})
+		}
+
+		// Store user info in context
+		c.Locals("userID", claims.UserID)
+		c.Locals("username", claims.Username)
+		c.Locals("role", claims.Role)
+		c.Locals(tenant.ContextKey, tenantID)
+		c.Locals(practitioners.TokenKey, tokenString)
+		return c.Next()
+	}
+}
+
+var errInvalidClaims = errors.New("invalid token claims")
+
+// ParseToken validates a JWT issued with secret and returns its claims. It
+// is shared by the REST and gRPC APIs.
+func ParseToken(tokenString, secret string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errInvalidClaims
+	}
+	return claims, nil
+}
+
+var (
+	ErrNoTenant      = errors.New("token has no tenant")
+	ErrUnknownTenant = errors.New("unknown tenant")
+)
+
+// ResolveTenant returns the ID of the tenant the claims act for: the
+// claimed one, or the default tenant for tokens without the claim. Like
+// ParseToken it is shared by the REST and gRPC APIs.
+func ResolveTenant(claims *Claims, tenants *tenant.Registry) (string, error) {
+	t, ok := tenants.Resolve(claims.TenantID)
+	if !ok && claims.TenantID == "" {
+		return "", ErrNoTenant
+	}
+	if !ok {
+		return "", ErrUnknownTenant
+	}
+	return t.ID, nil
+}
+
+// RequireRole allows requests whose token carries one of the roles. It
+// must run after JWTAuth.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals("role").(string)
+		for _, r := range roles {
+			if role == r {
+				return c.Next()
+			}
 		}
 
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"error": fiber.Map{
-				"code":    "INVALID_CLAIMS",
-				"message": "Invalid token claims",
+				"code":    "FORBIDDEN",
+				"message": "Insufficient role for this operation",
 			},
 		})
 	}
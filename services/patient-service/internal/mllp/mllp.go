@@ -0,0 +1,284 @@
+// Minimal Lower Layer Protocol (MLLP) framing, server and client
+// internal/mllp/mllp.go
+package mllp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// Frame delimiters: <VT> message <FS><CR>
+const (
+	startBlock = 0x0b
+	endBlock   = 0x1c
+	carriage   = 0x0d
+)
+
+// MaxMessageSize bounds a single framed message.
+const MaxMessageSize = 1 << 20
+
+var (
+	ErrMessageTooLarge = errors.New("mllp: message too large")
+	ErrBadFrame        = errors.New("mllp: malformed frame")
+)
+
+// Reader reads framed messages from a stream.
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// ReadMessage returns the next message without its frame. Bytes before the
+// start block are skipped.
+func (r *Reader) ReadMessage() ([]byte, error) {
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == startBlock {
+			break
+		}
+	}
+
+	var msg []byte
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		if b == endBlock {
+			next, err := r.r.ReadByte()
+			if err != nil {
+				return nil, io.ErrUnexpectedEOF
+			}
+			if next != carriage {
+				return nil, ErrBadFrame
+			}
+			return msg, nil
+		}
+
+		if len(msg) >= MaxMessageSize {
+			return nil, ErrMessageTooLarge
+		}
+		msg = append(msg, b)
+	}
+}
+
+// WriteMessage writes msg in an MLLP frame.
+func WriteMessage(w io.Writer, msg []byte) error {
+	frame := make([]byte, 0, len(msg)+3)
+	frame = append(frame, startBlock)
+	frame = append(frame, msg...)
+	frame = append(frame, endBlock, carriage)
+	_, err := w.Write(frame)
+	return err
+}
+
+// Handler processes one message and returns the acknowledgement to send.
+type Handler interface {
+	ServeMLLP(ctx context.Context, msg []byte) []byte
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(ctx context.Context, msg []byte) []byte
+
+func (f HandlerFunc) ServeMLLP(ctx context.Context, msg []byte) []byte {
+	return f(ctx, msg)
+}
+
+// Server accepts MLLP connections. Messages on a connection are handled in
+// order, each one acknowledged before the next is read.
+type Server struct {
+	Addr        string
+	Handler     Handler
+	IdleTimeout time.Duration // closes idle connections; 0 means 5 minutes
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// ListenAndServe listens on s.Addr and serves until ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is cancelled, then closes open
+// connections and waits for their handlers to finish.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	s.mu.Lock()
+	s.listener = ln
+	s.conns = make(map[net.Conn]struct{})
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+		s.mu.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mu.Unlock()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.wg.Wait()
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		// Accepted while shutting down, after open connections were closed
+		if ctx.Err() != nil {
+			conn.Close()
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(ctx, conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// ListenerAddr returns the listener address once serving, e.g. to find the port
+// chosen for ":0".
+func (s *Server) ListenerAddr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	idle := s.IdleTimeout
+	if idle <= 0 {
+		idle = 5 * time.Minute
+	}
+
+	reader := NewReader(conn)
+	for {
+		conn.SetReadDeadline(time.Now().Add(idle))
+		msg, err := reader.ReadMessage()
+		if err != nil {
+			if err != io.EOF && ctx.Err() == nil {
+				log.Printf("mllp: %s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+
+		ack := s.Handler.ServeMLLP(ctx, msg)
+		if ack == nil {
+			continue
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(30 * time.Second))
+		if err := WriteMessage(conn, ack); err != nil {
+			log.Printf("mllp: %s: %v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// Client sends messages over a persistent connection, reconnecting after
+// errors. It is safe for concurrent use; sends are serialized.
+type Client struct {
+	Addr    string
+	Timeout time.Duration // dial and acknowledgement timeout; 0 means 30s
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *Reader
+}
+
+func NewClient(addr string) *Client {
+	return &Client{Addr: addr}
+}
+
+// Send writes msg and waits for the acknowledgement.
+func (c *Client) Send(ctx context.Context, msg []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	if c.conn == nil {
+		dialer := net.Dialer{Timeout: timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", c.Addr)
+		if err != nil {
+			return nil, err
+		}
+		c.conn, c.reader = conn, NewReader(conn)
+	}
+
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	c.conn.SetDeadline(deadline)
+
+	if err := WriteMessage(c.conn, msg); err != nil {
+		c.closeLocked()
+		return nil, err
+	}
+
+	ack, err := c.reader.ReadMessage()
+	if err != nil {
+		c.closeLocked()
+		return nil, err
+	}
+	return ack, nil
+}
+
+// Close closes the connection; the next Send reconnects.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeLocked()
+}
+
+func (c *Client) closeLocked() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.reader = nil, nil
+	return err
+}
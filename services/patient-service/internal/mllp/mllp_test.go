@@ -0,0 +1,63 @@
+package mllp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestReaderSkipsNoiseAndRejectsBadFrames(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("noise")
+	WriteMessage(&buf, []byte("MSH|one"))
+	WriteMessage(&buf, []byte("MSH|two"))
+	buf.Write([]byte{startBlock, 'x', endBlock, 'y'})
+
+	r := NewReader(&buf)
+	for _, want := range []string{"MSH|one", "MSH|two"} {
+		msg, err := r.ReadMessage()
+		if err != nil || string(msg) != want {
+			t.Fatalf("Expected %q, got %q (%v)", want, msg, err)
+		}
+	}
+	if _, err := r.ReadMessage(); !errors.Is(err, ErrBadFrame) {
+		t.Errorf("Expected ErrBadFrame, got %v", err)
+	}
+}
+
+func TestClientServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	server := &Server{Handler: HandlerFunc(func(ctx context.Context, msg []byte) []byte {
+		return []byte("ACK " + strings.ToUpper(string(msg)))
+	})}
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx, ln) }()
+
+	client := NewClient(ln.Addr().String())
+	defer client.Close()
+
+	for _, msg := range []string{"first", "second"} {
+		ack, err := client.Send(ctx, []byte(msg))
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		if want := "ACK " + strings.ToUpper(msg); string(ack) != want {
+			t.Errorf("Expected %q, got %q", want, ack)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected clean shutdown, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+// practitioner-service client
+// internal/practitioners/client.go
+package practitioners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLookup is the most user IDs practitioner-service resolves per request.
+const maxLookup = 100
+
+// tokenKey is the type of TokenKey.
+type tokenKey struct{}
+
+// TokenKey holds the caller's bearer token, which requests to
+// practitioner-service are made with. The REST middleware stores it as a
+// request local.
+var TokenKey = tokenKey{}
+
+// NewContext returns a context calling practitioner-service with the token.
+func NewContext(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, TokenKey, token)
+}
+
+// Client resolves user IDs, such as created_by and updated_by, to the
+// names of the practitioners they belong to. Names, and user IDs that
+// belong to no practitioner, are cached for the TTL.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedName
+	now   func() time.Time
+}
+
+type cachedName struct {
+	name    string // empty for user IDs of no practitioner
+	expires time.Time
+}
+
+// NewClient returns a client of the practitioner-service at baseURL, e.g.
+// http://practitioner-service:3003.
+func NewClient(baseURL string, timeout, ttl time.Duration) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: timeout},
+		ttl:        ttl,
+		cache:      make(map[string]cachedName),
+		now:        time.Now,
+	}
+}
+
+// lookupResponse is the response of GET /api/v1/practitioners/lookup.
+type lookupResponse struct {
+	Data map[string]struct {
+		DisplayName string `json:"display_name"`
+	} `json:"data"`
+}
+
+// Names returns the display names of the user IDs, e.g.
+// "dr. Budi Santoso, Sp.PD". User IDs of no practitioner are left out.
+func (c *Client) Names(ctx context.Context, userIDs []string) (map[string]string, error) {
+	names := make(map[string]string, len(userIDs))
+	var missing []string
+
+	c.mu.Lock()
+	now := c.now()
+	seen := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if cached, ok := c.cache[id]; ok && now.Before(cached.expires) {
+			if cached.name != "" {
+				names[id] = cached.name
+			}
+			continue
+		}
+		missing = append(missing, id)
+	}
+	c.mu.Unlock()
+
+	for start := 0; start < len(missing); start += maxLookup {
+		end := start + maxLookup
+		if end > len(missing) {
+			end = len(missing)
+		}
+		batch := missing[start:end]
+
+		resolved, err := c.lookup(ctx, batch)
+		if err != nil {
+			return names, err
+		}
+
+		c.mu.Lock()
+		expires := c.now().Add(c.ttl)
+		for _, id := range batch {
+			c.cache[id] = cachedName{name: resolved[id], expires: expires}
+			if name := resolved[id]; name != "" {
+				names[id] = name
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	return names, nil
+}
+
+func (c *Client) lookup(ctx context.Context, userIDs []string) (map[string]string, error) {
+	endpoint := c.baseURL + "/api/v1/practitioners/lookup?user_ids=" + url.QueryEscape(strings.Join(userIDs, ","))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token, _ := ctx.Value(TokenKey).(string); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("practitioner lookup: status %d", resp.StatusCode)
+	}
+
+	var body lookupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("practitioner lookup: invalid response: %w", err)
+	}
+
+	names := make(map[string]string, len(body.Data))
+	for id, user := range body.Data {
+		names[id] = user.DisplayName
+	}
+	return names, nil
+}
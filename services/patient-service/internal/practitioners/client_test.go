@@ -0,0 +1,89 @@
+package practitioners
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNames(t *testing.T) {
+	var gotAuth string
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		requested = append(requested, r.URL.Query().Get("user_ids"))
+		w.Write([]byte(`{"data": {"u-budi": {"user_id": "u-budi", "display_name": "dr. Budi Santoso, Sp.PD"}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Second, time.Minute)
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+	ctx := NewContext(context.Background(), "token-1")
+
+	names, err := client.Names(ctx, []string{"u-budi", "system", "u-budi", ""})
+	if err != nil {
+		t.Fatalf("Names failed: %v", err)
+	}
+	if len(names) != 1 || names["u-budi"] != "dr. Budi Santoso, Sp.PD" {
+		t.Errorf("Unexpected names %v", names)
+	}
+	if gotAuth != "Bearer token-1" {
+		t.Errorf("Expected the caller's token to be forwarded, got %q", gotAuth)
+	}
+	if len(requested) != 1 || requested[0] != "u-budi,system" {
+		t.Fatalf("Expected one lookup of u-budi and system, got %v", requested)
+	}
+
+	// Both the name and the user ID of no practitioner are cached
+	if names, _ := client.Names(ctx, []string{"u-budi", "system"}); names["u-budi"] == "" {
+		t.Errorf("Expected the cached name, got %v", names)
+	}
+	if len(requested) != 1 {
+		t.Errorf("Expected cached user IDs not to be looked up again, got %v", requested)
+	}
+
+	now = now.Add(2 * time.Minute)
+	client.Names(ctx, []string{"system"})
+	if len(requested) != 2 {
+		t.Errorf("Expected an expired user ID to be looked up again, got %v", requested)
+	}
+}
+
+func TestNamesBatches(t *testing.T) {
+	var batches []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		batches = append(batches, len(strings.Split(r.URL.Query().Get("user_ids"), ",")))
+		w.Write([]byte(`{"data": {}}`))
+	}))
+	defer server.Close()
+
+	userIDs := make([]string, maxLookup+1)
+	for i := range userIDs {
+		userIDs[i] = "u" + strings.Repeat("x", i+1)
+	}
+	if _, err := NewClient(server.URL, time.Second, time.Minute).Names(context.Background(), userIDs); err != nil {
+		t.Fatalf("Names failed: %v", err)
+	}
+	if len(batches) != 2 || batches[0] != maxLookup || batches[1] != 1 {
+		t.Errorf("Expected batches of %d and 1, got %v", maxLookup, batches)
+	}
+}
+
+func TestNamesUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Second, time.Minute)
+	if _, err := client.Names(context.Background(), []string{"u-budi"}); err == nil {
+		t.Fatal("Expected an error when practitioner-service is unavailable")
+	}
+	if _, ok := client.cache["u-budi"]; ok {
+		t.Error("Expected a failed lookup not to be cached")
+	}
+}
@@ -0,0 +1,74 @@
+// Signed public profile tokens
+// internal/qrtoken/token.go
+package qrtoken
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalid is returned for tokens that are malformed or not signed with
+// the key.
+var ErrInvalid = errors.New("invalid token")
+
+// signatureLen is the length of the truncated HMAC; tokens are kept short
+// so the QR codes they are printed in stay small.
+const signatureLen = 16
+
+var encoding = base64.RawURLEncoding
+
+// Signer makes tokens of the form <id>.<expiry>.<signature>. The signature
+// lets forged tokens be rejected without a database lookup; whether a
+// token was revoked is kept with its ID.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// NewID returns a random token ID, unrelated to the patient's ID.
+func NewID() (string, error) {
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(id), nil
+}
+
+// Sign returns the token for the ID, valid until expires.
+func (s *Signer) Sign(id string, expires time.Time) string {
+	payload := id + "." + strconv.FormatInt(expires.Unix(), 36)
+	return payload + "." + s.signature(payload)
+}
+
+// Parse verifies the token's signature and returns its ID and expiry; it
+// does not check the expiry.
+func (s *Signer) Parse(token string) (string, time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", time.Time{}, ErrInvalid
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.signature(payload))) {
+		return "", time.Time{}, ErrInvalid
+	}
+	expires, err := strconv.ParseInt(parts[1], 36, 64)
+	if err != nil {
+		return "", time.Time{}, ErrInvalid
+	}
+	return parts[0], time.Unix(expires, 0), nil
+}
+
+func (s *Signer) signature(payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+	return encoding.EncodeToString(mac.Sum(nil)[:signatureLen])
+}
@@ -0,0 +1,42 @@
+package qrtoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndParse(t *testing.T) {
+	signer := NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	id, err := NewID()
+	if err != nil {
+		t.Fatalf("NewID failed: %v", err)
+	}
+	expires := time.Unix(1900000000, 0)
+	token := signer.Sign(id, expires)
+
+	gotID, gotExpires, err := signer.Parse(token)
+	if err != nil || gotID != id || !gotExpires.Equal(expires) {
+		t.Fatalf("Parse(%q) = %q, %v, %v", token, gotID, gotExpires, err)
+	}
+	if len(token) > 48 {
+		t.Errorf("Expected a short token, got %d characters", len(token))
+	}
+
+	parts := strings.Split(token, ".")
+	forged := []string{
+		"",
+		id,
+		parts[0] + "." + "zzzzzz" + "." + parts[2], // extended expiry
+		"other." + parts[1] + "." + parts[2],
+		token + "x",
+	}
+	for _, tok := range forged {
+		if _, _, err := signer.Parse(tok); err != ErrInvalid {
+			t.Errorf("Parse(%q): expected ErrInvalid, got %v", tok, err)
+		}
+	}
+	if _, _, err := NewSigner([]byte("another key of at least 32 bytes")).Parse(token); err != ErrInvalid {
+		t.Errorf("Expected a token of another key to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+// Patient consent repository
+// internal/repository/consent_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"patient-service/internal/domain"
+)
+
+type consentRepository struct {
+	db *sql.DB
+}
+
+func NewConsentRepository(db *sql.DB) ConsentRepository {
+	return &consentRepository{db: db}
+}
+
+func (r *consentRepository) Create(ctx context.Context, consent *domain.Consent) error {
+	consent.ID = uuid.New().String()
+	consent.CreatedAt = time.Now()
+	consent.UpdatedAt = consent.CreatedAt
+
+	query := `
+		INSERT INTO patient_consents (
+			id, patient_id, type, decision, scope, grantor_name, grantor_relationship,
+			valid_from, valid_until, document_ref, created_by, created_at, updated_at
+		) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10, @p11, @p12, @p12)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		consent.ID, consent.PatientID, consent.Type, consent.Decision, nullString(consent.Scope),
+		nullString(consent.GrantorName), consent.GrantorRelationship, consent.ValidFrom, consent.ValidUntil,
+		nullString(consent.DocumentRef), consent.CreatedBy, consent.CreatedAt,
+	)
+	return err
+}
+
+const consentColumns = `id, patient_id, type, decision, scope, grantor_name, grantor_relationship,
+	valid_from, valid_until, document_ref, revoked_at, revoked_by, revocation_reason,
+	created_by, created_at, updated_at`
+
+func scanConsent(row rowScanner) (*domain.Consent, error) {
+	var (
+		consent          domain.Consent
+		scope            sql.NullString
+		grantorName      sql.NullString
+		validUntil       sql.NullTime
+		documentRef      sql.NullString
+		revokedAt        sql.NullTime
+		revokedBy        sql.NullString
+		revocationReason sql.NullString
+		createdBy        sql.NullString
+	)
+
+	err := row.Scan(&consent.ID, &consent.PatientID, &consent.Type, &consent.Decision, &scope,
+		&grantorName, &consent.GrantorRelationship, &consent.ValidFrom, &validUntil, &documentRef,
+		&revokedAt, &revokedBy, &revocationReason, &createdBy, &consent.CreatedAt, &consent.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrConsentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	consent.Scope = scope.String
+	consent.GrantorName = grantorName.String
+	if validUntil.Valid {
+		consent.ValidUntil = &validUntil.Time
+	}
+	consent.DocumentRef = documentRef.String
+	if revokedAt.Valid {
+		consent.RevokedAt = &revokedAt.Time
+	}
+	consent.RevokedBy = revokedBy.String
+	consent.RevocationReason = revocationReason.String
+	consent.CreatedBy = createdBy.String
+
+	return &consent, nil
+}
+
+func (r *consentRepository) GetByID(ctx context.Context, patientID, id string) (*domain.Consent, error) {
+	query := `SELECT ` + consentColumns + ` FROM patient_consents WHERE id = @p1 AND patient_id = @p2`
+
+	return scanConsent(r.db.QueryRowContext(ctx, query, id, patientID))
+}
+
+func (r *consentRepository) ListByPatient(ctx context.Context, patientID string) ([]*domain.Consent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+consentColumns+`
+		FROM patient_consents
+		WHERE patient_id = @p1
+		ORDER BY type, valid_from DESC, created_at DESC
+	`, patientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var consents []*domain.Consent
+	for rows.Next() {
+		consent, err := scanConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+		consents = append(consents, consent)
+	}
+
+	return consents, rows.Err()
+}
+
+func (r *consentRepository) Revoke(ctx context.Context, consent *domain.Consent) error {
+	now := time.Now()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE patient_consents SET
+			revoked_at = @p3, revoked_by = @p4, revocation_reason = @p5, updated_at = @p3
+		WHERE id = @p1 AND patient_id = @p2 AND revoked_at IS NULL
+	`, consent.ID, consent.PatientID, now, consent.RevokedBy, nullString(consent.RevocationReason))
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrConsentRevoked
+	}
+
+	consent.RevokedAt = &now
+	consent.UpdatedAt = now
+	return nil
+}
+
+func (r *consentRepository) Decisions(ctx context.Context, patientIDs []string, consentType string, at time.Time) (map[string]string, error) {
+	decisions := make(map[string]string)
+	if len(patientIDs) == 0 {
+		return decisions, nil
+	}
+
+	q := &queryBuilder{}
+	now := q.arg(at)
+	q.where("type = " + q.arg(consentType))
+	q.where("revoked_at IS NULL")
+	q.where("valid_from <= " + now)
+	q.where("(valid_until IS NULL OR valid_until > " + now + ")")
+	q.in("patient_id", patientIDs)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT patient_id, decision FROM (
+			SELECT patient_id, decision, ROW_NUMBER() OVER (
+				PARTITION BY patient_id ORDER BY valid_from DESC, created_at DESC
+			) AS rn
+			FROM patient_consents
+			WHERE `+q.conditions()+`
+		) c
+		WHERE rn = 1
+	`, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var patientID, decision string
+		if err := rows.Scan(&patientID, &decision); err != nil {
+			return nil, err
+		}
+		decisions[patientID] = decision
+	}
+
+	return decisions, rows.Err()
+}
@@ -0,0 +1,113 @@
+// Patient document repository
+// internal/repository/document_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+type documentRepository struct {
+	db *sql.DB
+}
+
+func NewDocumentRepository(db *sql.DB) DocumentRepository {
+	return &documentRepository{db: db}
+}
+
+// Create stores the document's metadata. The ID is set by the caller,
+// since the content is stored under it first.
+func (r *documentRepository) Create(ctx context.Context, doc *domain.Document) error {
+	doc.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO patient_documents (
+			id, patient_id, type, description, issued_date, file_name, content_type,
+			size, sha256, storage_key, scan_status, uploaded_by, created_at
+		) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10, @p11, @p12, @p13)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		doc.ID, doc.PatientID, doc.Type, nullString(doc.Description), doc.IssuedDate, doc.FileName, doc.ContentType,
+		doc.Size, doc.SHA256, doc.StorageKey, doc.ScanStatus, doc.UploadedBy, doc.CreatedAt,
+	)
+	return err
+}
+
+const documentColumns = `id, patient_id, type, description, issued_date, file_name, content_type,
+	size, sha256, storage_key, scan_status, uploaded_by, created_at`
+
+func scanDocument(row rowScanner) (*domain.Document, error) {
+	var (
+		doc         domain.Document
+		description sql.NullString
+		issuedDate  sql.NullTime
+		uploadedBy  sql.NullString
+	)
+
+	err := row.Scan(&doc.ID, &doc.PatientID, &doc.Type, &description, &issuedDate, &doc.FileName, &doc.ContentType,
+		&doc.Size, &doc.SHA256, &doc.StorageKey, &doc.ScanStatus, &uploadedBy, &doc.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description = description.String
+	if issuedDate.Valid {
+		doc.IssuedDate = &issuedDate.Time
+	}
+	doc.UploadedBy = uploadedBy.String
+
+	return &doc, nil
+}
+
+func (r *documentRepository) GetByID(ctx context.Context, patientID, id string) (*domain.Document, error) {
+	query := `SELECT ` + documentColumns + ` FROM patient_documents WHERE id = @p1 AND patient_id = @p2`
+
+	return scanDocument(r.db.QueryRowContext(ctx, query, id, patientID))
+}
+
+func (r *documentRepository) ListByPatient(ctx context.Context, patientID string) ([]*domain.Document, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+documentColumns+`
+		FROM patient_documents
+		WHERE patient_id = @p1
+		ORDER BY created_at DESC
+	`, patientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []*domain.Document
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, rows.Err()
+}
+
+func (r *documentRepository) Delete(ctx context.Context, patientID, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM patient_documents WHERE id = @p1 AND patient_id = @p2`, id, patientID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrDocumentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Export job repository
+// internal/repository/export_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"patient-service/internal/domain"
+)
+
+type exportRepository struct {
+	db *sql.DB
+}
+
+func NewExportRepository(db *sql.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+func (r *exportRepository) CreateJob(ctx context.Context, job *domain.ExportJob) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+	job.ID = uuid.New().String()
+	job.TenantID = tenantID
+	job.Status = domain.ExportPending
+	job.CreatedAt = time.Now()
+
+	filter, err := json.Marshal(job.Filter)
+	if err != nil {
+		return err
+	}
+	var ruleset sql.NullString
+	if job.Ruleset != nil {
+		data, err := json.Marshal(job.Ruleset)
+		if err != nil {
+			return err
+		}
+		ruleset = sql.NullString{String: string(data), Valid: true}
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO export_jobs (id, format, status, filter, consent_type, ruleset, created_by, created_at, tenant_id)
+		VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9)
+	`, job.ID, job.Format, job.Status, string(filter), nullString(job.ConsentType), ruleset, job.CreatedBy, job.CreatedAt, job.TenantID)
+	return err
+}
+
+const exportJobColumns = `id, tenant_id, format, status, filter, consent_type, ruleset, row_count, suppressed_rows,
+	file_size, error, created_by, created_at, started_at, finished_at`
+
+func scanExportJob(row rowScanner) (*domain.ExportJob, error) {
+	var (
+		job         domain.ExportJob
+		filter      string
+		consentType sql.NullString
+		ruleset     sql.NullString
+		jobError    sql.NullString
+		createdBy   sql.NullString
+		startedAt   sql.NullTime
+		finishedAt  sql.NullTime
+	)
+
+	err := row.Scan(&job.ID, &job.TenantID, &job.Format, &job.Status, &filter, &consentType, &ruleset, &job.RowCount, &job.Suppressed,
+		&job.FileSize, &jobError, &createdBy, &job.CreatedAt, &startedAt, &finishedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(filter), &job.Filter); err != nil {
+		return nil, err
+	}
+	job.ConsentType = consentType.String
+	if ruleset.Valid {
+		if err := json.Unmarshal([]byte(ruleset.String), &job.Ruleset); err != nil {
+			return nil, err
+		}
+	}
+	job.Error = jobError.String
+	job.CreatedBy = createdBy.String
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return &job, nil
+}
+
+func (r *exportRepository) GetJob(ctx context.Context, id string) (*domain.ExportJob, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + exportJobColumns + ` FROM export_jobs WHERE id = @p1 AND tenant_id = @p2`
+	return scanExportJob(r.db.QueryRowContext(ctx, query, id, tenantID))
+}
+
+func (r *exportRepository) ClaimJob(ctx context.Context, lease time.Duration) (*domain.ExportJob, error) {
+	now := time.Now()
+
+	// A job whose worker stopped is started over; its rows are counted again
+	query := `
+		WITH next AS (
+			SELECT TOP (1) * FROM export_jobs WITH (READPAST, UPDLOCK, ROWLOCK)
+			WHERE status = @p3 OR (status = @p4 AND lease_until <= @p1)
+			ORDER BY created_at
+		)
+		UPDATE next SET status = @p4, lease_until = @p2, row_count = 0, suppressed_rows = 0, started_at = ISNULL(started_at, @p1)
+		OUTPUT ` + prefixColumns("inserted.", exportJobColumns) + `
+	`
+
+	row := r.db.QueryRowContext(ctx, query, now, now.Add(lease), domain.ExportPending, domain.ExportRunning)
+	job, err := scanExportJob(row)
+	if err == domain.ErrExportNotFound {
+		return nil, nil
+	}
+	return job, err
+}
+
+func (r *exportRepository) SaveProgress(ctx context.Context, job *domain.ExportJob, lease time.Duration) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE export_jobs SET row_count = @p2, lease_until = @p3
+		WHERE id = @p1
+	`, job.ID, job.RowCount, time.Now().Add(lease))
+	return err
+}
+
+func (r *exportRepository) FinishJob(ctx context.Context, job *domain.ExportJob) error {
+	now := time.Now()
+	job.FinishedAt = &now
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE export_jobs SET
+			status = @p2, error = @p3, row_count = @p4, suppressed_rows = @p5, file_size = @p6,
+			finished_at = @p7, lease_until = NULL
+		WHERE id = @p1
+	`, job.ID, job.Status, nullString(truncate(job.Error, 1000)), job.RowCount, job.Suppressed, job.FileSize, now)
+	return err
+}
+
+func (r *exportRepository) ListFinishedBefore(ctx context.Context, before time.Time) ([]*domain.ExportJob, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+exportJobColumns+`
+		FROM export_jobs
+		WHERE finished_at < @p1
+		ORDER BY finished_at
+	`, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*domain.ExportJob
+	for rows.Next() {
+		job, err := scanExportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *exportRepository) DeleteJob(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM export_jobs WHERE id = @p1`, id)
+	return err
+}
@@ -0,0 +1,155 @@
+// Patient identifier and identity sync queue repositories
+// internal/repository/identifier_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+type identifierRepository struct {
+	db *sql.DB
+}
+
+func NewIdentifierRepository(db *sql.DB) IdentifierRepository {
+	return &identifierRepository{db: db}
+}
+
+func (r *identifierRepository) Upsert(ctx context.Context, identifier *domain.Identifier) error {
+	identifier.UpdatedAt = time.Now()
+
+	query := `
+		MERGE patient_identifiers WITH (HOLDLOCK) AS t
+		USING (SELECT @p1 AS patient_id, @p2 AS system_uri) AS s
+		ON t.patient_id = s.patient_id AND t.system_uri = s.system_uri
+		WHEN MATCHED THEN
+			UPDATE SET value = @p3, updated_at = @p4
+		WHEN NOT MATCHED THEN
+			INSERT (patient_id, system_uri, value, created_at, updated_at)
+			VALUES (@p1, @p2, @p3, @p4, @p4);
+	`
+
+	_, err := r.db.ExecContext(ctx, query, identifier.PatientID, identifier.System, identifier.Value, identifier.UpdatedAt)
+	return err
+}
+
+func (r *identifierRepository) Delete(ctx context.Context, patientID, system string) error {
+	query := `DELETE FROM patient_identifiers WHERE patient_id = @p1 AND system_uri = @p2`
+
+	_, err := r.db.ExecContext(ctx, query, patientID, system)
+	return err
+}
+
+func (r *identifierRepository) ListByPatients(ctx context.Context, patientIDs []string) ([]*domain.Identifier, error) {
+	if len(patientIDs) == 0 {
+		return nil, nil
+	}
+
+	q := &queryBuilder{}
+	q.in("patient_id", patientIDs)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT patient_id, system_uri, value, updated_at
+		FROM patient_identifiers
+		WHERE `+q.conditions()+`
+		ORDER BY patient_id, system_uri
+	`, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identifiers []*domain.Identifier
+	for rows.Next() {
+		identifier := &domain.Identifier{}
+		if err := rows.Scan(&identifier.PatientID, &identifier.System, &identifier.Value, &identifier.UpdatedAt); err != nil {
+			return nil, err
+		}
+		identifiers = append(identifiers, identifier)
+	}
+
+	return identifiers, rows.Err()
+}
+
+type identitySyncQueue struct {
+	db *sql.DB
+}
+
+func NewIdentitySyncQueue(db *sql.DB) IdentitySyncQueue {
+	return &identitySyncQueue{db: db}
+}
+
+func (q *identitySyncQueue) Enqueue(ctx context.Context, patientID string) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		MERGE satusehat_sync_queue WITH (HOLDLOCK) AS t
+		USING (SELECT @p1 AS patient_id) AS s
+		ON t.patient_id = s.patient_id
+		WHEN MATCHED THEN
+			UPDATE SET attempts = 0, last_error = NULL, enqueued_at = @p2, next_attempt_at = @p2
+		WHEN NOT MATCHED THEN
+			INSERT (patient_id, tenant_id, attempts, enqueued_at, next_attempt_at)
+			VALUES (@p1, @p3, 0, @p2, @p2);
+	`
+
+	_, err = q.db.ExecContext(ctx, query, patientID, time.Now(), tenantID)
+	return err
+}
+
+// Claim leases up to limit due tasks by moving their next attempt past the
+// lease. Rows locked by another instance are skipped; tasks of a worker that
+// dies are retried once the lease expires.
+func (q *identitySyncQueue) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.IdentitySyncTask, error) {
+	now := time.Now()
+
+	query := `
+		UPDATE TOP (@p1) satusehat_sync_queue WITH (READPAST, UPDLOCK, ROWLOCK)
+		SET next_attempt_at = @p3
+		OUTPUT inserted.patient_id, inserted.tenant_id, inserted.attempts, inserted.last_error, inserted.enqueued_at, inserted.next_attempt_at
+		WHERE next_attempt_at <= @p2
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, limit, now, now.Add(lease))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*domain.IdentitySyncTask
+	for rows.Next() {
+		task := &domain.IdentitySyncTask{}
+		var lastError sql.NullString
+		if err := rows.Scan(&task.PatientID, &task.TenantID, &task.Attempts, &lastError, &task.EnqueuedAt, &task.NextAttemptAt); err != nil {
+			return nil, err
+		}
+		task.LastError = lastError.String
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+func (q *identitySyncQueue) Reschedule(ctx context.Context, task *domain.IdentitySyncTask) error {
+	query := `
+		UPDATE satusehat_sync_queue
+		SET attempts = @p3, last_error = @p4, next_attempt_at = @p5
+		WHERE patient_id = @p1 AND enqueued_at = @p2
+	`
+
+	_, err := q.db.ExecContext(ctx, query, task.PatientID, task.EnqueuedAt, task.Attempts, task.LastError, task.NextAttemptAt)
+	return err
+}
+
+func (q *identitySyncQueue) Remove(ctx context.Context, task *domain.IdentitySyncTask) error {
+	query := `DELETE FROM satusehat_sync_queue WHERE patient_id = @p1 AND enqueued_at = @p2`
+
+	_, err := q.db.ExecContext(ctx, query, task.PatientID, task.EnqueuedAt)
+	return err
+}
@@ -0,0 +1,334 @@
+// Bulk import job repository
+// internal/repository/import_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"patient-service/internal/domain"
+	"patient-service/pkg/envelope"
+)
+
+type importRepository struct {
+	db     *sql.DB
+	cipher *envelope.Cipher
+}
+
+// NewImportRepository creates the repository. The uploaded file and the
+// values of rejected rows hold patient identity data, so they are stored
+// encrypted with a per-job data key.
+func NewImportRepository(db *sql.DB, cipher *envelope.Cipher) ImportRepository {
+	return &importRepository{db: db, cipher: cipher}
+}
+
+func importContentAAD(jobID string) string {
+	return "import|" + jobID + "|content"
+}
+
+func importRowAAD(jobID string, row int) string {
+	return "import|" + jobID + "|row|" + strconv.Itoa(row)
+}
+
+func (r *importRepository) CreateJob(ctx context.Context, job *domain.ImportJob, content []byte) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+	job.ID = uuid.New().String()
+	job.TenantID = tenantID
+	job.Status = domain.ImportPending
+	job.CreatedAt = time.Now()
+
+	dataKey, err := r.cipher.NewDataKey(ctx)
+	if err != nil {
+		return err
+	}
+	sealed, err := dataKey.Encrypt(string(content), importContentAAD(job.ID))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt import file: %w", err)
+	}
+
+	mapping, err := json.Marshal(job.Mapping)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO import_jobs (
+			id, file_name, format, status, dry_run, mapping, columns, content,
+			data_key, data_key_id, created_by, created_at, tenant_id
+		) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10, @p11, @p12, @p13)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		job.ID, job.FileName, job.Format, job.Status, job.DryRun, string(mapping), columnsJSON(job.Columns), sealed,
+		dataKey.Wrapped, dataKey.KeyID, job.CreatedBy, job.CreatedAt, job.TenantID,
+	)
+	return err
+}
+
+const importJobColumns = `id, tenant_id, file_name, format, status, dry_run, mapping, columns,
+	total_rows, processed_rows, valid_rows, imported_rows, rejected_rows,
+	error, created_by, created_at, started_at, finished_at`
+
+func scanImportJob(row rowScanner, extra ...interface{}) (*domain.ImportJob, error) {
+	var (
+		job        domain.ImportJob
+		mapping    string
+		columns    sql.NullString
+		jobError   sql.NullString
+		createdBy  sql.NullString
+		startedAt  sql.NullTime
+		finishedAt sql.NullTime
+	)
+
+	dest := []interface{}{&job.ID, &job.TenantID, &job.FileName, &job.Format, &job.Status, &job.DryRun, &mapping, &columns,
+		&job.TotalRows, &job.ProcessedRows, &job.ValidRows, &job.ImportedRows, &job.RejectedRows,
+		&jobError, &createdBy, &job.CreatedAt, &startedAt, &finishedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrImportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(mapping), &job.Mapping); err != nil {
+		return nil, err
+	}
+	if columns.Valid {
+		if err := json.Unmarshal([]byte(columns.String), &job.Columns); err != nil {
+			return nil, err
+		}
+	}
+	job.Error = jobError.String
+	job.CreatedBy = createdBy.String
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return &job, nil
+}
+
+func (r *importRepository) GetJob(ctx context.Context, id string) (*domain.ImportJob, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + importJobColumns + ` FROM import_jobs WHERE id = @p1 AND tenant_id = @p2`
+	return scanImportJob(r.db.QueryRowContext(ctx, query, id, tenantID))
+}
+
+func (r *importRepository) ClaimJob(ctx context.Context, lease time.Duration) (*domain.ImportJob, []byte, error) {
+	now := time.Now()
+
+	query := `
+		WITH next AS (
+			SELECT TOP (1) * FROM import_jobs WITH (READPAST, UPDLOCK, ROWLOCK)
+			WHERE status = @p3 OR (status = @p4 AND lease_until <= @p1)
+			ORDER BY created_at
+		)
+		UPDATE next SET status = @p4, lease_until = @p2, started_at = ISNULL(started_at, @p1)
+		OUTPUT ` + prefixColumns("inserted.", importJobColumns) + `,
+			inserted.content, inserted.data_key, inserted.data_key_id
+	`
+
+	var (
+		sealed  sql.NullString
+		wrapped []byte
+		keyID   string
+	)
+	row := r.db.QueryRowContext(ctx, query, now, now.Add(lease), domain.ImportPending, domain.ImportRunning)
+	job, err := scanImportJob(row, &sealed, &wrapped, &keyID)
+	if err == domain.ErrImportNotFound {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataKey, err := r.cipher.OpenDataKey(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := dataKey.Decrypt(sealed.String, importContentAAD(job.ID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt import file: %w", err)
+	}
+
+	return job, []byte(content), nil
+}
+
+// rowQuerier is a *sql.DB or *sql.Tx.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// jobKey opens the data key of a job.
+func (r *importRepository) jobKey(ctx context.Context, q rowQuerier, jobID string) (*envelope.DataKey, error) {
+	var (
+		wrapped []byte
+		keyID   string
+	)
+	err := q.QueryRowContext(ctx, `SELECT data_key, data_key_id FROM import_jobs WHERE id = @p1`, jobID).Scan(&wrapped, &keyID)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrImportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.cipher.OpenDataKey(ctx, keyID, wrapped)
+}
+
+func (r *importRepository) SaveProgress(ctx context.Context, job *domain.ImportJob, rowErrors []*domain.ImportRowError, lease time.Duration) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if len(rowErrors) > 0 {
+			dataKey, err := r.jobKey(ctx, tx, job.ID)
+			if err != nil {
+				return err
+			}
+
+			for _, rowErr := range rowErrors {
+				values, err := json.Marshal(rowErr.Values)
+				if err != nil {
+					return err
+				}
+				sealed, err := dataKey.Encrypt(string(values), importRowAAD(job.ID, rowErr.Row))
+				if err != nil {
+					return fmt.Errorf("failed to encrypt import row: %w", err)
+				}
+
+				_, err = tx.ExecContext(ctx, `
+					INSERT INTO import_row_errors (job_id, row_no, field, code, message, row_values)
+					VALUES (@p1, @p2, @p3, @p4, @p5, @p6)
+				`, job.ID, rowErr.Row, nullString(rowErr.Field), rowErr.Code, truncate(rowErr.Message, 500), sealed)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			UPDATE import_jobs SET
+				columns = @p2, total_rows = @p3, processed_rows = @p4, valid_rows = @p5,
+				imported_rows = @p6, rejected_rows = @p7, lease_until = @p8
+			WHERE id = @p1
+		`, job.ID, columnsJSON(job.Columns), job.TotalRows, job.ProcessedRows, job.ValidRows,
+			job.ImportedRows, job.RejectedRows, time.Now().Add(lease))
+		return err
+	})
+}
+
+func (r *importRepository) FinishJob(ctx context.Context, job *domain.ImportJob) error {
+	now := time.Now()
+	job.FinishedAt = &now
+
+	// The uploaded file is dropped once it is no longer needed
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE import_jobs SET
+			status = @p2, error = @p3, columns = @p4, total_rows = @p5, processed_rows = @p6,
+			valid_rows = @p7, imported_rows = @p8, rejected_rows = @p9,
+			finished_at = @p10, lease_until = NULL, content = NULL
+		WHERE id = @p1
+	`, job.ID, job.Status, nullString(truncate(job.Error, 1000)), columnsJSON(job.Columns), job.TotalRows,
+		job.ProcessedRows, job.ValidRows, job.ImportedRows, job.RejectedRows, now)
+	return err
+}
+
+func (r *importRepository) ListRowErrors(ctx context.Context, jobID string, fn func(*domain.ImportRowError) error) error {
+	dataKey, err := r.jobKey(ctx, r.db, jobID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT row_no, field, code, message, row_values
+		FROM import_row_errors
+		WHERE job_id = @p1
+		ORDER BY row_no
+	`, jobID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rowErr domain.ImportRowError
+			field  sql.NullString
+			sealed sql.NullString
+		)
+		if err := rows.Scan(&rowErr.Row, &field, &rowErr.Code, &rowErr.Message, &sealed); err != nil {
+			return err
+		}
+		rowErr.Field = field.String
+
+		values, err := dataKey.Decrypt(sealed.String, importRowAAD(jobID, rowErr.Row))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt import row: %w", err)
+		}
+		if values != "" {
+			if err := json.Unmarshal([]byte(values), &rowErr.Values); err != nil {
+				return err
+			}
+		}
+
+		if err := fn(&rowErr); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *importRepository) PruneJobs(ctx context.Context, before time.Time) (int64, error) {
+	var pruned int64
+
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			DELETE e FROM import_row_errors e
+			JOIN import_jobs j ON j.id = e.job_id
+			WHERE j.finished_at < @p1
+		`, before)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM import_jobs WHERE finished_at < @p1`, before)
+		if err != nil {
+			return err
+		}
+		pruned, err = result.RowsAffected()
+		return err
+	})
+
+	return pruned, err
+}
+
+func columnsJSON(columns []string) sql.NullString {
+	if columns == nil {
+		return sql.NullString{}
+	}
+	data, _ := json.Marshal(columns)
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+// truncate shortens s to at most max characters to fit its column.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -5,15 +5,225 @@ package repository
 import (
 	"context"
 	"patient-service/internal/domain"
+	"time"
 )
 
 type PatientRepository interface {
 	Create(ctx context.Context, patient *domain.Patient) error
+
+	// CreateBatch inserts the patients in a single transaction
+	CreateBatch(ctx context.Context, patients []*domain.Patient) error
 	GetByID(ctx context.Context, id string) (*domain.Patient, error)
+	GetByIDFields(ctx context.Context, id string, fields []string) (*domain.Patient, error)
 	GetByNIK(ctx context.Context, nik string) (*domain.Patient, error)
 	GetByMedicalRecordNo(ctx context.Context, mrNo string) (*domain.Patient, error)
+
+	// GetLinks returns the patient's active records at other tenants,
+	// sharing its enterprise ID, with identity fields only
+	GetLinks(ctx context.Context, id string) ([]*domain.Patient, error)
 	Update(ctx context.Context, patient *domain.Patient) error
+	UpdateFields(ctx context.Context, patient *domain.Patient, fields []string) error
 	Delete(ctx context.Context, id string) error
-	List(ctx context.Context, filter domain.PatientFilter) ([]*domain.Patient, int, error)
+
+	// Merge deactivates the merged patient and links it to the survivor
+	Merge(ctx context.Context, survivorID, mergedID, mergedBy string) error
+
+	// RecordDeath stores the patient's death fields with a deceased event;
+	// ErrPatientDeceased if a death is already recorded
+	RecordDeath(ctx context.Context, patient *domain.Patient) error
+
+	// ClearDeath removes a death recorded in error; ErrPatientNotDeceased
+	// if none is recorded
+	ClearDeath(ctx context.Context, patient *domain.Patient) error
+	List(ctx context.Context, filter domain.PatientFilter) (*domain.PatientPage, error)
 	Exists(ctx context.Context, id string) (bool, error)
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.Patient, error)
+
+	// GetByKeys loads the active patients whose key is one of values in a
+	// single query, with the given fields (nil for all) plus the key
+	GetByKeys(ctx context.Context, key domain.PatientKey, values []string, fields []string) ([]*domain.Patient, error)
+
+	// Scan calls fn for each patient matching the filter, in its order,
+	// streaming from the database; pagination is ignored
+	Scan(ctx context.Context, filter domain.PatientFilter, fn func(*domain.Patient) error) error
+
+	// ExistingNIKs returns which of niks are taken, including by inactive
+	// patients
+	ExistingNIKs(ctx context.Context, niks []string) (map[string]bool, error)
+
+	// ScanNames streams id, tenant, medical record number, names, is_active
+	// and updated_at of patients of every tenant updated at or after since,
+	// for the search index
+	ScanNames(ctx context.Context, since time.Time, fn func(*domain.Patient) error) error
+}
+
+type IdentifierRepository interface {
+	// Upsert stores the identifier, replacing the patient's value for the system
+	Upsert(ctx context.Context, identifier *domain.Identifier) error
+	Delete(ctx context.Context, patientID, system string) error
+	ListByPatients(ctx context.Context, patientIDs []string) ([]*domain.Identifier, error)
+}
+
+type DocumentRepository interface {
+	Create(ctx context.Context, doc *domain.Document) error
+	GetByID(ctx context.Context, patientID, id string) (*domain.Document, error)
+	ListByPatient(ctx context.Context, patientID string) ([]*domain.Document, error)
+	Delete(ctx context.Context, patientID, id string) error
+}
+
+type QRTokenRepository interface {
+	Create(ctx context.Context, token *domain.QRToken) error
+	GetByID(ctx context.Context, id string) (*domain.QRToken, error)
+	ListByPatient(ctx context.Context, patientID string) ([]*domain.QRToken, error)
+
+	// Revoke records the revocation; ErrQRTokenRevoked when it already was
+	Revoke(ctx context.Context, token *domain.QRToken) error
+
+	// RecordScan stores the audit record of a scan and counts it on the
+	// token it names
+	RecordScan(ctx context.Context, scan *domain.QRScan) error
+	ListScans(ctx context.Context, tokenID string, limit int) ([]*domain.QRScan, error)
+}
+
+type ConsentRepository interface {
+	Create(ctx context.Context, consent *domain.Consent) error
+	GetByID(ctx context.Context, patientID, id string) (*domain.Consent, error)
+	ListByPatient(ctx context.Context, patientID string) ([]*domain.Consent, error)
+
+	// Revoke records the revocation; ErrConsentRevoked when it already was
+	Revoke(ctx context.Context, consent *domain.Consent) error
+
+	// Decisions returns, by patient, the decision of the consent of the
+	// type in effect at the given time; patients without one are absent
+	Decisions(ctx context.Context, patientIDs []string, consentType string, at time.Time) (map[string]string, error)
+}
+
+// IdentitySyncQueue holds pending identifier lookups. Tasks are leased by
+// Claim so several instances can share the queue.
+type IdentitySyncQueue interface {
+	// Enqueue schedules a lookup now, resetting the attempts of a queued task
+	Enqueue(ctx context.Context, patientID string) error
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.IdentitySyncTask, error)
+
+	// Reschedule and Remove do nothing if the task was enqueued again after
+	// it was claimed
+	Reschedule(ctx context.Context, task *domain.IdentitySyncTask) error
+	Remove(ctx context.Context, task *domain.IdentitySyncTask) error
+}
+
+// OutboxRepository reads and maintains the event outbox. Events are
+// appended by PatientRepository writes.
+type OutboxRepository interface {
+	// Pending returns unpublished events in outbox order, skipping patients
+	// whose failed event is not yet due for another attempt
+	Pending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, ids []int64) error
+
+	// MarkFailed stores the event's Attempts, LastError and NextAttemptAt
+	MarkFailed(ctx context.Context, event *domain.OutboxEvent) error
+
+	// Prune deletes published events that occurred before the given time,
+	// and unpublished ones too if unpublished is set
+	Prune(ctx context.Context, before time.Time, unpublished bool) (int64, error)
+
+	// TryLock returns nil if the lock is held by another instance
+	TryLock(ctx context.Context, resource string) (Lease, error)
+}
+
+// Lease is an exclusive lock shared by all instances of the service.
+type Lease interface {
+	Valid(ctx context.Context) bool
+	Release()
+}
+
+// ChangeRepository reads the outbox as an ordered change feed.
+type ChangeRepository interface {
+	// Since returns up to limit events after the position, in order. An
+	// event is only returned once every earlier position has committed.
+	Since(ctx context.Context, after int64, limit int) ([]*domain.OutboxEvent, error)
+
+	// Bounds returns the oldest retained position (0 when empty) and the
+	// latest committed one
+	Bounds(ctx context.Context) (oldest, latest int64, err error)
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, activeOnly bool) ([]*domain.WebhookSubscription, error)
+
+	// UpdateSubscription writes the URL, description, event types, secret
+	// and active state
+	UpdateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+
+	// DeleteSubscription removes the subscription and its delivery log
+	DeleteSubscription(ctx context.Context, id string) error
+
+	// EnqueueDeliveries stores new deliveries, skipping any already stored
+	// for the same subscription and event
+	EnqueueDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error
+
+	// ClaimDeliveries leases due pending deliveries of active subscriptions
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)
+
+	// RecordAttempt logs an attempt and saves the delivery's new state. The
+	// subscription's failure streak is reset on success; on failure it grows
+	// and the subscription is disabled once it reaches disableAfter, which
+	// is reported by the returned bool.
+	RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt *domain.WebhookAttempt, disableAfter int) (bool, error)
+
+	ListDeliveries(ctx context.Context, filter domain.DeliveryFilter) ([]*domain.WebhookDelivery, int, error)
+
+	// GetDelivery returns a delivery of the subscription with its attempt log
+	GetDelivery(ctx context.Context, subscriptionID, id string) (*domain.WebhookDelivery, error)
+
+	// ReplayDelivery makes a delivery pending again with no attempts
+	ReplayDelivery(ctx context.Context, subscriptionID, id string) error
+
+	// PruneDeliveries removes delivered deliveries created before the time
+	PruneDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ImportRepository stores bulk import jobs. Jobs are leased by ClaimJob so
+// several instances can share them; a job whose lease runs out is resumed
+// by another instance.
+type ImportRepository interface {
+	// CreateJob stores a pending job with the uploaded file
+	CreateJob(ctx context.Context, job *domain.ImportJob, content []byte) error
+	GetJob(ctx context.Context, id string) (*domain.ImportJob, error)
+
+	// ClaimJob leases the oldest pending job, or a running job whose lease
+	// expired, and returns it with its file; nil when there is none
+	ClaimJob(ctx context.Context, lease time.Duration) (*domain.ImportJob, []byte, error)
+
+	// SaveProgress stores the job's counters and the rows rejected since the
+	// last call, and extends the lease
+	SaveProgress(ctx context.Context, job *domain.ImportJob, rowErrors []*domain.ImportRowError, lease time.Duration) error
+
+	// FinishJob stores the final status and counters and drops the file
+	FinishJob(ctx context.Context, job *domain.ImportJob) error
+
+	// ListRowErrors calls fn for each rejected row in row order
+	ListRowErrors(ctx context.Context, jobID string, fn func(*domain.ImportRowError) error) error
+
+	// PruneJobs deletes jobs that finished before the given time
+	PruneJobs(ctx context.Context, before time.Time) (int64, error)
+}
+
+type ExportRepository interface {
+	CreateJob(ctx context.Context, job *domain.ExportJob) error
+	GetJob(ctx context.Context, id string) (*domain.ExportJob, error)
+
+	// ClaimJob leases the oldest pending job, or a running job whose lease
+	// expired; nil when there is none
+	ClaimJob(ctx context.Context, lease time.Duration) (*domain.ExportJob, error)
+
+	// SaveProgress stores the row count and extends the lease
+	SaveProgress(ctx context.Context, job *domain.ExportJob, lease time.Duration) error
+	FinishJob(ctx context.Context, job *domain.ExportJob) error
+
+	// ListFinishedBefore returns jobs that finished before the given time,
+	// for their files to be removed before DeleteJob
+	ListFinishedBefore(ctx context.Context, before time.Time) ([]*domain.ExportJob, error)
+	DeleteJob(ctx context.Context, id string) error
 }
@@ -0,0 +1,137 @@
+// Re-encryption of patient identifiers under the active key
+// internal/repository/key_rotation.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"patient-service/pkg/envelope"
+)
+
+// KeyRotator re-encrypts patient rows whose data key is wrapped by an old
+// key-encryption key, or which are still stored in plaintext.
+type KeyRotator struct {
+	db     *sql.DB
+	cipher *envelope.Cipher
+}
+
+func NewKeyRotator(db *sql.DB, cipher *envelope.Cipher) *KeyRotator {
+	return &KeyRotator{db: db, cipher: cipher}
+}
+
+// Pending returns the number of rows not yet encrypted under the active key.
+func (k *KeyRotator) Pending(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM patients WHERE data_key_id IS NULL OR data_key_id <> @p1`
+
+	var count int
+	err := k.db.QueryRowContext(ctx, query, k.cipher.ActiveKeyID()).Scan(&count)
+	return count, err
+}
+
+// Unencrypted returns the number of rows still stored in plaintext. They
+// have no blind index, so lookups and duplicate checks by NIK miss them.
+func (k *KeyRotator) Unencrypted(ctx context.Context) (int, error) {
+	var count int
+	err := k.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM patients WHERE data_key_id IS NULL`).Scan(&count)
+	return count, err
+}
+
+// RotateBatch re-encrypts up to batchSize rows with an ID greater than
+// afterID in a single transaction. It returns the last ID examined so the
+// caller can continue from there, and the number of rows rewritten.
+// An empty lastID means there is nothing left to rotate.
+func (k *KeyRotator) RotateBatch(ctx context.Context, afterID string, batchSize int) (string, int, error) {
+	return k.rotateBatch(ctx, afterID, batchSize, `data_key_id IS NULL OR data_key_id <> @p2`)
+}
+
+// EncryptBatch is RotateBatch for plaintext rows only; rows under an older
+// key are left for the next rotation.
+func (k *KeyRotator) EncryptBatch(ctx context.Context, afterID string, batchSize int) (string, int, error) {
+	return k.rotateBatch(ctx, afterID, batchSize, `data_key_id IS NULL`)
+}
+
+// rotateBatch re-encrypts the rows matching condition, which may refer to
+// the active key ID as @p2.
+func (k *KeyRotator) rotateBatch(ctx context.Context, afterID string, batchSize int, condition string) (string, int, error) {
+	tx, err := k.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT TOP (@p3) ` + patientColumns + `
+		FROM patients WITH (UPDLOCK, ROWLOCK)
+		WHERE id > @p1 AND (` + condition + `)
+		ORDER BY id
+	`
+
+	rows, err := tx.QueryContext(ctx, query, afterID, k.cipher.ActiveKeyID(), batchSize)
+	if err != nil {
+		return "", 0, err
+	}
+
+	type pendingRow struct {
+		id     string
+		sealed *sealedPatient
+	}
+
+	var pending []pendingRow
+	for rows.Next() {
+		patient, wrapped, keyID, err := scanSealedPatient(rows)
+		if err != nil {
+			rows.Close()
+			return "", 0, err
+		}
+
+		if err := openPatient(ctx, k.cipher, patient, wrapped, keyID); err != nil {
+			rows.Close()
+			return "", 0, fmt.Errorf("patient %s: %w", patient.ID, err)
+		}
+
+		sealed, err := sealPatient(ctx, k.cipher, patient)
+		if err != nil {
+			rows.Close()
+			return "", 0, fmt.Errorf("patient %s: %w", patient.ID, err)
+		}
+
+		pending = append(pending, pendingRow{id: patient.ID, sealed: sealed})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return "", 0, err
+	}
+
+	if len(pending) == 0 {
+		return "", 0, nil
+	}
+
+	update := `
+		UPDATE patients SET
+			nik = @p2,
+			phone = @p3,
+			email = @p4,
+			insurance_number = @p5,
+			nik_bidx = @p6,
+			data_key = @p7,
+			data_key_id = @p8
+		WHERE id = @p1
+	`
+
+	for _, p := range pending {
+		_, err := tx.ExecContext(ctx, update,
+			p.id, p.sealed.nik, p.sealed.phone, p.sealed.email, p.sealed.insuranceNumber,
+			p.sealed.nikIndex, p.sealed.dataKey.Wrapped, p.sealed.dataKey.KeyID,
+		)
+		if err != nil {
+			return "", 0, fmt.Errorf("patient %s: %w", p.id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", 0, err
+	}
+
+	return pending[len(pending)-1].id, len(pending), nil
+}
@@ -0,0 +1,225 @@
+// Transactional outbox
+// internal/repository/outbox_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+// appendEvent writes an event to the outbox as part of tx, so it is
+// published if and only if the change commits. It also bumps the patient's
+// version, which the event carries along with the patient's tenant and
+// enterprise ID.
+func appendEvent(ctx context.Context, tx *sql.Tx, eventType string, data domain.PatientEventData) error {
+	err := tx.QueryRowContext(ctx,
+		`UPDATE patients SET version = version + 1 OUTPUT inserted.version, inserted.tenant_id, inserted.enterprise_id WHERE id = @p1`,
+		data.ID,
+	).Scan(&data.Version, &data.TenantID, &data.EnterpriseID)
+	if err != nil {
+		return err
+	}
+
+	event, err := domain.NewOutboxEvent(eventType, data)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO outbox (event_id, event_type, aggregate_id, data, occurred_at, tenant_id)
+		VALUES (@p1, @p2, @p3, @p4, @p5, @p6)
+	`
+
+	_, err = tx.ExecContext(ctx, query, event.EventID, event.Type, event.PatientID, string(event.Data), event.OccurredAt, event.TenantID)
+	return err
+}
+
+// withTx runs fn in a transaction, committing if it returns nil.
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type outboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+const outboxColumns = `id, event_id, event_type, aggregate_id, tenant_id, data, occurred_at, attempts, last_error, next_attempt_at`
+
+func (r *outboxRepository) Pending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	// Patients waiting to retry a failed event are skipped entirely so they
+	// cannot fill the batch and hold back everyone else
+	query := `SELECT TOP (@p1) ` + outboxColumns + `
+		FROM outbox o
+		WHERE published_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox b
+				WHERE b.aggregate_id = o.aggregate_id AND b.published_at IS NULL AND b.next_attempt_at > @p2
+			)
+		ORDER BY id
+	`
+
+	return r.query(ctx, query, limit, time.Now())
+}
+
+func (r *outboxRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.OutboxEvent, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.OutboxEvent
+	for rows.Next() {
+		var (
+			event       domain.OutboxEvent
+			data        string
+			lastError   sql.NullString
+			nextAttempt sql.NullTime
+		)
+		err := rows.Scan(&event.ID, &event.EventID, &event.Type, &event.PatientID, &event.TenantID, &data,
+			&event.OccurredAt, &event.Attempts, &lastError, &nextAttempt)
+		if err != nil {
+			return nil, err
+		}
+		event.Data = []byte(data)
+		event.LastError = lastError.String
+		event.NextAttemptAt = nextAttempt.Time
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	q := &queryBuilder{}
+	publishedAt := q.arg(time.Now())
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		placeholders[i] = q.arg(id)
+	}
+
+	query := `UPDATE outbox SET published_at = ` + publishedAt + `, last_error = NULL WHERE id IN (` + strings.Join(placeholders, ", ") + `)`
+	_, err := r.db.ExecContext(ctx, query, q.args...)
+	return err
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, event *domain.OutboxEvent) error {
+	query := `UPDATE outbox SET attempts = @p2, last_error = @p3, next_attempt_at = @p4 WHERE id = @p1`
+
+	_, err := r.db.ExecContext(ctx, query, event.ID, event.Attempts, event.LastError, event.NextAttemptAt)
+	return err
+}
+
+func (r *outboxRepository) Prune(ctx context.Context, before time.Time, unpublished bool) (int64, error) {
+	query := `DELETE FROM outbox WHERE occurred_at < @p1 AND (published_at IS NOT NULL OR @p2 = 1)`
+
+	result, err := r.db.ExecContext(ctx, query, before, unpublished)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// TryLock takes an exclusive session application lock on a dedicated
+// connection. It returns nil if another session holds the lock.
+func (r *outboxRepository) TryLock(ctx context.Context, resource string) (Lease, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result int
+	err = conn.QueryRowContext(ctx, `
+		DECLARE @result INT;
+		EXEC @result = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = 0;
+		SELECT @result;
+	`, resource).Scan(&result)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if result < 0 {
+		conn.Close()
+		return nil, nil
+	}
+	return &appLock{conn: conn, resource: resource}, nil
+}
+
+type appLock struct {
+	conn     *sql.Conn
+	resource string
+}
+
+// Valid checks the lock is still held, e.g. after the connection dropped.
+func (l *appLock) Valid(ctx context.Context) bool {
+	var mode string
+	err := l.conn.QueryRowContext(ctx, `SELECT APPLOCK_MODE('public', @p1, 'Session')`, l.resource).Scan(&mode)
+	return err == nil && mode == "Exclusive"
+}
+
+func (l *appLock) Release() {
+	if _, err := l.conn.ExecContext(context.Background(), `EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'`, l.resource); err != nil {
+		log.Printf("Failed to release lock %s: %v", l.resource, err)
+	}
+	l.conn.Close()
+}
+
+// NewChangeRepository reads the outbox as a change feed.
+func NewChangeRepository(db *sql.DB) ChangeRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Since(ctx context.Context, after int64, limit int) ([]*domain.OutboxEvent, error) {
+	// Positions are handed out when a row is inserted, not when it commits,
+	// so a committed event may follow one still in flight. The locking read
+	// waits for in-flight rows instead of skipping them (as it would under
+	// snapshot isolation), so readers never move past an uncommitted event.
+	query := `SELECT TOP (@p1) ` + outboxColumns + `
+		FROM outbox WITH (READCOMMITTEDLOCK)
+		WHERE id > @p2
+		ORDER BY id
+	`
+
+	return r.query(ctx, query, limit, after)
+}
+
+func (r *outboxRepository) Bounds(ctx context.Context) (int64, int64, error) {
+	// The latest position is read with a locking read, like Since, so it is
+	// never past an event still in flight. The identity value only counts
+	// once every event has been pruned.
+	query := `
+		SELECT
+			ISNULL((SELECT MIN(id) FROM outbox), 0),
+			ISNULL(
+				(SELECT MAX(id) FROM outbox WITH (READCOMMITTEDLOCK)),
+				ISNULL((SELECT CAST(last_value AS BIGINT) FROM sys.identity_columns WHERE object_id = OBJECT_ID('outbox')), 0)
+			)
+	`
+
+	var oldest, latest int64
+	err := r.db.QueryRowContext(ctx, query).Scan(&oldest, &latest)
+	return oldest, latest, err
+}
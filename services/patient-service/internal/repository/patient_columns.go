@@ -0,0 +1,137 @@
+// Patient column registry
+// internal/repository/patient_columns.go
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"patient-service/internal/domain"
+)
+
+// patientColumn maps a column to the Patient field it is scanned into.
+// Column names equal the API field names in domain.PatientFields.
+type patientColumn struct {
+	name string
+	dest func(p *domain.Patient) interface{}
+}
+
+var patientColumnList = []patientColumn{
+	{"id", func(p *domain.Patient) interface{} { return &p.ID }},
+	{"tenant_id", func(p *domain.Patient) interface{} { return &p.TenantID }},
+	{"enterprise_id", func(p *domain.Patient) interface{} { return &p.EnterpriseID }},
+	{"medical_record_no", func(p *domain.Patient) interface{} { return &p.MedicalRecordNo }},
+	{"nik", func(p *domain.Patient) interface{} { return &p.NIK }},
+	{"first_name", func(p *domain.Patient) interface{} { return &p.FirstName }},
+	{"last_name", func(p *domain.Patient) interface{} { return &p.LastName }},
+	{"date_of_birth", func(p *domain.Patient) interface{} { return &p.DateOfBirth }},
+	{"gender", func(p *domain.Patient) interface{} { return &p.Gender }},
+	{"blood_type", func(p *domain.Patient) interface{} { return &p.BloodType }},
+	{"phone", func(p *domain.Patient) interface{} { return &p.Phone }},
+	{"email", func(p *domain.Patient) interface{} { return &p.Email }},
+	{"address", func(p *domain.Patient) interface{} { return &p.Address }},
+	{"city", func(p *domain.Patient) interface{} { return &p.City }},
+	{"province", func(p *domain.Patient) interface{} { return &p.Province }},
+	{"postal_code", func(p *domain.Patient) interface{} { return &p.PostalCode }},
+	{"emergency_contact", func(p *domain.Patient) interface{} { return &p.EmergencyContact }},
+	{"emergency_phone", func(p *domain.Patient) interface{} { return &p.EmergencyPhone }},
+	{"insurance_provider", func(p *domain.Patient) interface{} { return &p.InsuranceProvider }},
+	{"insurance_number", func(p *domain.Patient) interface{} { return &p.InsuranceNumber }},
+	{"allergies", func(p *domain.Patient) interface{} { return &p.Allergies }},
+	{"chronic_conditions", func(p *domain.Patient) interface{} { return &p.ChronicConditions }},
+	{"is_active", func(p *domain.Patient) interface{} { return &p.IsActive }},
+	{"created_at", func(p *domain.Patient) interface{} { return &p.CreatedAt }},
+	{"updated_at", func(p *domain.Patient) interface{} { return &p.UpdatedAt }},
+	{"created_by", func(p *domain.Patient) interface{} { return &p.CreatedBy }},
+	{"updated_by", func(p *domain.Patient) interface{} { return &p.UpdatedBy }},
+	{"deceased_at", func(p *domain.Patient) interface{} { return &p.DeceasedAt }},
+	{"cause_of_death", func(p *domain.Patient) interface{} { return &p.CauseOfDeath }},
+	{"death_recorded_by", func(p *domain.Patient) interface{} { return &p.DeathRecordedBy }},
+}
+
+// encryptedColumns need the row's data key to be selected as well
+var encryptedColumns = map[string]bool{
+	"nik":              true,
+	"phone":            true,
+	"email":            true,
+	"insurance_number": true,
+}
+
+// columnSet is the projection of a patient query.
+type columnSet struct {
+	columns   []patientColumn
+	encrypted bool
+}
+
+var allPatientColumns = newColumnSet(nil)
+
+// patientColumns is the column list of a full patient SELECT.
+var patientColumns = allPatientColumns.sql()
+
+// newColumnSet returns the columns for the given field names, in registry
+// order. Unknown names are ignored, id is always included and nil selects
+// every column. Names must come from domain.ParseFields or the repository
+// itself since they end up in the SQL text.
+func newColumnSet(fields []string, required ...string) columnSet {
+	if fields == nil {
+		return columnSet{columns: patientColumnList, encrypted: true}
+	}
+
+	wanted := map[string]bool{"id": true}
+	for _, f := range append(fields, required...) {
+		wanted[f] = true
+	}
+
+	var set columnSet
+	for _, c := range patientColumnList {
+		if wanted[c.name] {
+			set.columns = append(set.columns, c)
+			if encryptedColumns[c.name] {
+				set.encrypted = true
+			}
+		}
+	}
+	return set
+}
+
+// has reports whether the set includes the column.
+func (s columnSet) has(name string) bool {
+	for _, c := range s.columns {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s columnSet) sql() string {
+	names := make([]string, 0, len(s.columns)+2)
+	for _, c := range s.columns {
+		names = append(names, c.name)
+	}
+	if s.encrypted {
+		names = append(names, "data_key", "data_key_id")
+	}
+	return strings.Join(names, ", ")
+}
+
+// scan reads a row selected with s.sql() without decrypting it, returning
+// the wrapped data key and the ID of its key-encryption key as well.
+func (s columnSet) scan(row rowScanner) (*domain.Patient, []byte, sql.NullString, error) {
+	var (
+		wrapped []byte
+		keyID   sql.NullString
+	)
+
+	patient := &domain.Patient{}
+	dest := make([]interface{}, 0, len(s.columns)+2)
+	for _, c := range s.columns {
+		dest = append(dest, c.dest(patient))
+	}
+	if s.encrypted {
+		dest = append(dest, &wrapped, &keyID)
+	}
+
+	err := row.Scan(dest...)
+	return patient, wrapped, keyID, err
+}
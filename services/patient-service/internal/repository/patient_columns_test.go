@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"testing"
+
+	"patient-service/internal/domain"
+)
+
+func TestNewColumnSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []string
+		required []string
+		want     string
+	}{
+		{"id only", []string{}, nil, "id"},
+		{"registry order", []string{"last_name", "first_name"}, nil, "id, first_name, last_name"},
+		{"duplicates", []string{"city", "city", "id"}, nil, "id, city"},
+		{"sort column", []string{"first_name"}, []string{"created_at"}, "id, first_name, created_at"},
+		{"encrypted", []string{"nik"}, nil, "id, nik, data_key, data_key_id"},
+		{"unknown names", []string{"first_name", "id;drop", "nik_bidx", "data_key", "1=1 --"}, nil, "id, first_name"},
+	}
+	for _, tt := range tests {
+		if got := newColumnSet(tt.fields, tt.required...).sql(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	all := newColumnSet(nil)
+	if len(all.columns) != len(patientColumnList) || !all.encrypted {
+		t.Errorf("Expected nil to select every column with the data key")
+	}
+}
+
+func TestPatientFieldsHaveColumns(t *testing.T) {
+	// Every selectable field needs a column, or ?fields= would silently
+	// drop it
+	for _, field := range domain.PatientFields {
+		if !newColumnSet([]string{field}).has(field) {
+			t.Errorf("Field %s has no column", field)
+		}
+	}
+}
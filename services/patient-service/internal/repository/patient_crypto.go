@@ -0,0 +1,130 @@
+// Encryption of patient identifiers
+// internal/repository/patient_crypto.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"patient-service/internal/domain"
+	"patient-service/pkg/envelope"
+)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// sealedPatient holds the ciphertext of a patient's encrypted columns.
+type sealedPatient struct {
+	nik             string
+	phone           string
+	email           string
+	insuranceNumber string
+	nikIndex        string
+	dataKey         *envelope.DataKey
+}
+
+// fieldAAD binds a ciphertext to the row and column it was written to.
+func fieldAAD(patientID, column string) string {
+	return patientID + "|" + column
+}
+
+// seal encrypts the patient's identifiers with a new per-record data key.
+func (r *patientRepository) seal(ctx context.Context, patient *domain.Patient) (*sealedPatient, error) {
+	return sealPatient(ctx, r.cipher, patient)
+}
+
+func sealPatient(ctx context.Context, cipher *envelope.Cipher, patient *domain.Patient) (*sealedPatient, error) {
+	dataKey, err := cipher.NewDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := &sealedPatient{
+		nikIndex: cipher.BlindIndex("nik", patient.NIK),
+		dataKey:  dataKey,
+	}
+
+	fields := []struct {
+		column string
+		value  string
+		dest   *string
+	}{
+		{"nik", patient.NIK, &sealed.nik},
+		{"phone", patient.Phone, &sealed.phone},
+		{"email", patient.Email, &sealed.email},
+		{"insurance_number", patient.InsuranceNumber, &sealed.insuranceNumber},
+	}
+
+	for _, f := range fields {
+		ciphertext, err := dataKey.Encrypt(f.value, fieldAAD(patient.ID, f.column))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %w", f.column, err)
+		}
+		*f.dest = ciphertext
+	}
+
+	return sealed, nil
+}
+
+// openPatient decrypts the identifiers of a scanned patient in place.
+// Rows without a data key predate encryption and are returned as stored.
+func openPatient(ctx context.Context, cipher *envelope.Cipher, patient *domain.Patient, wrapped []byte, keyID sql.NullString) error {
+	if !keyID.Valid || keyID.String == "" {
+		return nil
+	}
+
+	dataKey, err := cipher.OpenDataKey(ctx, keyID.String, wrapped)
+	if err != nil {
+		return err
+	}
+
+	fields := []struct {
+		column string
+		value  *string
+	}{
+		{"nik", &patient.NIK},
+		{"phone", &patient.Phone},
+		{"email", &patient.Email},
+		{"insurance_number", &patient.InsuranceNumber},
+	}
+
+	for _, f := range fields {
+		plaintext, err := dataKey.Decrypt(*f.value, fieldAAD(patient.ID, f.column))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", f.column, err)
+		}
+		*f.value = plaintext
+	}
+
+	return nil
+}
+
+// scanPatient scans a row selected with patientColumns and decrypts it.
+func (r *patientRepository) scanPatient(ctx context.Context, row rowScanner) (*domain.Patient, error) {
+	return r.scanPatientColumns(ctx, row, allPatientColumns)
+}
+
+// scanPatientColumns scans a row selected with set.sql() and decrypts it.
+func (r *patientRepository) scanPatientColumns(ctx context.Context, row rowScanner, set columnSet) (*domain.Patient, error) {
+	patient, wrapped, keyID, err := set.scan(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPatientNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := openPatient(ctx, r.cipher, patient, wrapped, keyID); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// scanSealedPatient scans a full patient row without decrypting it.
+func scanSealedPatient(row rowScanner) (*domain.Patient, []byte, sql.NullString, error) {
+	return allPatientColumns.scan(row)
+}
@@ -6,27 +6,75 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
 	"patient-service/internal/domain"
+	"patient-service/pkg/envelope"
 
 	"github.com/google/uuid"
 )
 
 type patientRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	cipher *envelope.Cipher
 }
 
-func NewPatientRepository(db *sql.DB) PatientRepository {
-	return &patientRepository{db: db}
+func NewPatientRepository(db *sql.DB, cipher *envelope.Cipher) PatientRepository {
+	return &patientRepository{db: db, cipher: cipher}
 }
 
 func (r *patientRepository) Create(ctx context.Context, patient *domain.Patient) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return r.insert(ctx, tx, tenantID, patient)
+	})
+}
+
+// CreateBatch inserts the patients in one transaction, so either all of
+// them are stored or none is.
+func (r *patientRepository) CreateBatch(ctx context.Context, patients []*domain.Patient) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, patient := range patients {
+			if err := r.insert(ctx, tx, tenantID, patient); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// insert stores a new patient of the tenant and its created event. A
+// person already registered at another tenant keeps their enterprise ID.
+func (r *patientRepository) insert(ctx context.Context, tx *sql.Tx, tenantID string, patient *domain.Patient) error {
 	patient.ID = uuid.New().String()
+	patient.TenantID = tenantID
 	patient.CreatedAt = time.Now()
 	patient.UpdatedAt = time.Now()
 
+	sealed, err := r.seal(ctx, patient)
+	if err != nil {
+		return err
+	}
+
+	patient.EnterpriseID, err = linkedEnterpriseID(ctx, tx, tenantID, sealed.nikIndex)
+	if err != nil {
+		return err
+	}
+	if patient.EnterpriseID == "" {
+		patient.EnterpriseID = patient.ID
+	}
+
 	query := `
 		INSERT INTO patients (
 			id, medical_record_no, nik, first_name, last_name,
@@ -35,128 +83,196 @@ func (r *patientRepository) Create(ctx context.Context, patient *domain.Patient)
 			emergency_contact, emergency_phone,
 			insurance_provider, insurance_number,
 			allergies, chronic_conditions,
-			is_active, created_at, updated_at, created_by, updated_by
+			is_active, created_at, updated_at, created_by, updated_by,
+			nik_bidx, data_key, data_key_id, tenant_id, enterprise_id
 		) VALUES (
 			@p1, @p2, @p3, @p4, @p5,
 			@p6, @p7, @p8, @p9, @p10,
 			@p11, @p12, @p13, @p14,
 			@p15, @p16, @p17, @p18,
-			@p19, @p20, @p21, @p22, @p23, @p24, @p25
+			@p19, @p20, @p21, @p22, @p23, @p24, @p25,
+			@p26, @p27, @p28, @p29, @p30
 		)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		patient.ID, patient.MedicalRecordNo, patient.NIK, patient.FirstName, patient.LastName,
-		patient.DateOfBirth, patient.Gender, patient.BloodType, patient.Phone, patient.Email,
+	_, err = tx.ExecContext(ctx, query,
+		patient.ID, patient.MedicalRecordNo, sealed.nik, patient.FirstName, patient.LastName,
+		patient.DateOfBirth, patient.Gender, patient.BloodType, sealed.phone, sealed.email,
 		patient.Address, patient.City, patient.Province, patient.PostalCode,
 		patient.EmergencyContact, patient.EmergencyPhone,
-		patient.InsuranceProvider, patient.InsuranceNumber,
+		patient.InsuranceProvider, sealed.insuranceNumber,
 		patient.Allergies, patient.ChronicConditions,
 		patient.IsActive, patient.CreatedAt, patient.UpdatedAt, patient.CreatedBy, patient.UpdatedBy,
+		sealed.nikIndex, sealed.dataKey.Wrapped, sealed.dataKey.KeyID, patient.TenantID, patient.EnterpriseID,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return appendEvent(ctx, tx, domain.EventPatientCreated, domain.NewPatientEventData(patient))
 }
 
-func (r *patientRepository) GetByID(ctx context.Context, id string) (*domain.Patient, error) {
+// linkedEnterpriseID returns the enterprise ID of the person with the NIK
+// blind index at another tenant, or "" if no other tenant registered them.
+// The range lock keeps two tenants registering the same person at once
+// from creating two identities.
+func linkedEnterpriseID(ctx context.Context, tx *sql.Tx, tenantID, nikIndex string) (string, error) {
 	query := `
-		SELECT 
-			id, medical_record_no, nik, first_name, last_name,
-			date_of_birth, gender, blood_type, phone, email,
-			address, city, province, postal_code,
-			emergency_contact, emergency_phone,
-			insurance_provider, insurance_number,
-			allergies, chronic_conditions,
-			is_active, created_at, updated_at, created_by, updated_by
+		SELECT TOP 1 enterprise_id
+		FROM patients WITH (UPDLOCK, HOLDLOCK)
+		WHERE nik_bidx = @p1 AND tenant_id <> @p2
+		ORDER BY is_active DESC, created_at
+	`
+
+	var enterpriseID string
+	err := tx.QueryRowContext(ctx, query, nikIndex, tenantID).Scan(&enterpriseID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return enterpriseID, err
+}
+
+// relink returns the enterprise ID of a patient whose NIK blind index
+// changed from the one stored: that of the person at another tenant, else
+// the current one unless records elsewhere share it, as they belong to the
+// person the NIK used to identify.
+func relink(ctx context.Context, tx *sql.Tx, patient *domain.Patient, current, nikIndex string) (string, error) {
+	linked, err := linkedEnterpriseID(ctx, tx, patient.TenantID, nikIndex)
+	if err != nil || linked != "" {
+		return linked, err
+	}
+
+	var shared int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM patients WHERE enterprise_id = @p1 AND id <> @p2`,
+		current, patient.ID,
+	).Scan(&shared)
+	if err != nil {
+		return "", err
+	}
+	if shared > 0 {
+		return uuid.New().String(), nil
+	}
+	return current, nil
+}
+
+func (r *patientRepository) GetByID(ctx context.Context, id string) (*domain.Patient, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + patientColumns + `
 		FROM patients
-		WHERE id = @p1 AND is_active = 1
+		WHERE id = @p1 AND tenant_id = @p2 AND is_active = 1
 	`
 
-	patient := &domain.Patient{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&patient.ID, &patient.MedicalRecordNo, &patient.NIK, &patient.FirstName, &patient.LastName,
-		&patient.DateOfBirth, &patient.Gender, &patient.BloodType, &patient.Phone, &patient.Email,
-		&patient.Address, &patient.City, &patient.Province, &patient.PostalCode,
-		&patient.EmergencyContact, &patient.EmergencyPhone,
-		&patient.InsuranceProvider, &patient.InsuranceNumber,
-		&patient.Allergies, &patient.ChronicConditions,
-		&patient.IsActive, &patient.CreatedAt, &patient.UpdatedAt, &patient.CreatedBy, &patient.UpdatedBy,
-	)
+	return r.scanPatient(ctx, r.db.QueryRowContext(ctx, query, id, tenantID))
+}
 
-	if err == sql.ErrNoRows {
-		return nil, domain.ErrPatientNotFound
+// GetByIDFields loads only the given fields (see domain.PatientFields).
+func (r *patientRepository) GetByIDFields(ctx context.Context, id string, fields []string) (*domain.Patient, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return patient, err
+	set := newColumnSet(fields)
+
+	query := `SELECT ` + set.sql() + `
+		FROM patients
+		WHERE id = @p1 AND tenant_id = @p2 AND is_active = 1
+	`
+
+	return r.scanPatientColumns(ctx, r.db.QueryRowContext(ctx, query, id, tenantID), set)
 }
 
 func (r *patientRepository) GetByNIK(ctx context.Context, nik string) (*domain.Patient, error) {
-	query := `
-		SELECT 
-			id, medical_record_no, nik, first_name, last_name,
-			date_of_birth, gender, blood_type, phone, email,
-			address, city, province, postal_code,
-			emergency_contact, emergency_phone,
-			insurance_provider, insurance_number,
-			allergies, chronic_conditions,
-			is_active, created_at, updated_at, created_by, updated_by
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// NIK is encrypted, so it is looked up through its blind index
+	query := `SELECT ` + patientColumns + `
 		FROM patients
-		WHERE nik = @p1 AND is_active = 1
+		WHERE nik_bidx = @p1 AND tenant_id = @p2 AND is_active = 1
 	`
 
-	patient := &domain.Patient{}
-	err := r.db.QueryRowContext(ctx, query, nik).Scan(
-		&patient.ID, &patient.MedicalRecordNo, &patient.NIK, &patient.FirstName, &patient.LastName,
-		&patient.DateOfBirth, &patient.Gender, &patient.BloodType, &patient.Phone, &patient.Email,
-		&patient.Address, &patient.City, &patient.Province, &patient.PostalCode,
-		&patient.EmergencyContact, &patient.EmergencyPhone,
-		&patient.InsuranceProvider, &patient.InsuranceNumber,
-		&patient.Allergies, &patient.ChronicConditions,
-		&patient.IsActive, &patient.CreatedAt, &patient.UpdatedAt, &patient.CreatedBy, &patient.UpdatedBy,
-	)
+	return r.scanPatient(ctx, r.db.QueryRowContext(ctx, query, r.cipher.BlindIndex("nik", nik), tenantID))
+}
 
-	if err == sql.ErrNoRows {
-		return nil, domain.ErrPatientNotFound
+func (r *patientRepository) GetByMedicalRecordNo(ctx context.Context, mrNo string) (*domain.Patient, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return patient, err
+	query := `SELECT ` + patientColumns + `
+		FROM patients
+		WHERE medical_record_no = @p1 AND tenant_id = @p2 AND is_active = 1
+	`
+
+	return r.scanPatient(ctx, r.db.QueryRowContext(ctx, query, mrNo, tenantID))
 }
 
-func (r *patientRepository) GetByMedicalRecordNo(ctx context.Context, mrNo string) (*domain.Patient, error) {
-	query := `
-		SELECT 
-			id, medical_record_no, nik, first_name, last_name,
-			date_of_birth, gender, blood_type, phone, email,
-			address, city, province, postal_code,
-			emergency_contact, emergency_phone,
-			insurance_provider, insurance_number,
-			allergies, chronic_conditions,
-			is_active, created_at, updated_at, created_by, updated_by
+// GetLinks returns the patient's active records at other tenants, with
+// their identity fields only.
+func (r *patientRepository) GetLinks(ctx context.Context, id string) ([]*domain.Patient, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	set := newColumnSet(linkFields)
+	query := `SELECT ` + set.sql() + `
 		FROM patients
-		WHERE medical_record_no = @p1 AND is_active = 1
+		WHERE enterprise_id = (SELECT enterprise_id FROM patients WHERE id = @p1 AND tenant_id = @p2 AND is_active = 1)
+			AND tenant_id <> @p2 AND is_active = 1
+		ORDER BY created_at
 	`
 
-	patient := &domain.Patient{}
-	err := r.db.QueryRowContext(ctx, query, mrNo).Scan(
-		&patient.ID, &patient.MedicalRecordNo, &patient.NIK, &patient.FirstName, &patient.LastName,
-		&patient.DateOfBirth, &patient.Gender, &patient.BloodType, &patient.Phone, &patient.Email,
-		&patient.Address, &patient.City, &patient.Province, &patient.PostalCode,
-		&patient.EmergencyContact, &patient.EmergencyPhone,
-		&patient.InsuranceProvider, &patient.InsuranceNumber,
-		&patient.Allergies, &patient.ChronicConditions,
-		&patient.IsActive, &patient.CreatedAt, &patient.UpdatedAt, &patient.CreatedBy, &patient.UpdatedBy,
-	)
+	rows, err := r.db.QueryContext(ctx, query, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	if err == sql.ErrNoRows {
-		return nil, domain.ErrPatientNotFound
+	var patients []*domain.Patient
+	for rows.Next() {
+		patient, err := r.scanPatientColumns(ctx, rows, set)
+		if err != nil {
+			return nil, err
+		}
+		patients = append(patients, patient)
 	}
 
-	return patient, err
+	return patients, rows.Err()
+}
+
+// linkFields are the fields of other tenants' records returned by
+// GetLinks: enough to tell the person and their record there apart, none
+// of the facility's contact or clinical data.
+var linkFields = []string{
+	"tenant_id", "enterprise_id", "medical_record_no",
+	"first_name", "last_name", "date_of_birth", "gender",
+	"is_active", "created_at", "deceased_at",
 }
 
 func (r *patientRepository) Update(ctx context.Context, patient *domain.Patient) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+	patient.TenantID = tenantID
 	patient.UpdatedAt = time.Now()
 
+	// Every encrypted column is rewritten, so a fresh data key is used
+	sealed, err := r.seal(ctx, patient)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE patients SET
 			medical_record_no = @p2,
@@ -179,156 +295,683 @@ func (r *patientRepository) Update(ctx context.Context, patient *domain.Patient)
 			allergies = @p19,
 			chronic_conditions = @p20,
 			updated_at = @p21,
-			updated_by = @p22
-		WHERE id = @p1
+			updated_by = @p22,
+			nik_bidx = @p23,
+			data_key = @p24,
+			data_key_id = @p25,
+			enterprise_id = @p26
+		OUTPUT inserted.is_active
+		WHERE id = @p1 AND tenant_id = @p27
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
-		patient.ID, patient.MedicalRecordNo, patient.NIK, patient.FirstName, patient.LastName,
-		patient.DateOfBirth, patient.Gender, patient.BloodType, patient.Phone, patient.Email,
-		patient.Address, patient.City, patient.Province, patient.PostalCode,
-		patient.EmergencyContact, patient.EmergencyPhone,
-		patient.InsuranceProvider, patient.InsuranceNumber,
-		patient.Allergies, patient.ChronicConditions,
-		patient.UpdatedAt, patient.UpdatedBy,
-	)
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var current, currentIndex sql.NullString
+		err := tx.QueryRowContext(ctx,
+			`SELECT enterprise_id, nik_bidx FROM patients WITH (UPDLOCK) WHERE id = @p1 AND tenant_id = @p2`,
+			patient.ID, tenantID,
+		).Scan(&current, &currentIndex)
+		if err == sql.ErrNoRows {
+			return domain.ErrPatientNotFound
+		}
+		if err != nil {
+			return err
+		}
 
+		patient.EnterpriseID = current.String
+		if currentIndex.String != sealed.nikIndex {
+			patient.EnterpriseID, err = relink(ctx, tx, patient, current.String, sealed.nikIndex)
+			if err != nil {
+				return err
+			}
+		}
+
+		var isActive bool
+		err = tx.QueryRowContext(ctx, query,
+			patient.ID, patient.MedicalRecordNo, sealed.nik, patient.FirstName, patient.LastName,
+			patient.DateOfBirth, patient.Gender, patient.BloodType, sealed.phone, sealed.email,
+			patient.Address, patient.City, patient.Province, patient.PostalCode,
+			patient.EmergencyContact, patient.EmergencyPhone,
+			patient.InsuranceProvider, sealed.insuranceNumber,
+			patient.Allergies, patient.ChronicConditions,
+			patient.UpdatedAt, patient.UpdatedBy,
+			sealed.nikIndex, sealed.dataKey.Wrapped, sealed.dataKey.KeyID,
+			patient.EnterpriseID, tenantID,
+		).Scan(&isActive)
+		if err == sql.ErrNoRows {
+			return domain.ErrPatientNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		data := domain.NewPatientEventData(patient)
+		data.IsActive = isActive
+		return appendEvent(ctx, tx, domain.EventPatientUpdated, data)
+	})
+}
+
+// UpdateFields writes only the given fields of the patient (see
+// domain.ChangedFields) along with updated_at and updated_by. Changed
+// encrypted fields are re-encrypted with the row's existing data key.
+func (r *patientRepository) UpdateFields(ctx context.Context, patient *domain.Patient, fields []string) error {
+	tenantID, err := tenantOf(ctx)
 	if err != nil {
 		return err
 	}
+	patient.TenantID = tenantID
+	patient.UpdatedAt = time.Now()
 
-	rowsAffected, err := result.RowsAffected()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-
-	if rowsAffected == 0 {
+	defer tx.Rollback()
+
+	// Lock the row so a concurrent key rotation cannot swap its data key
+	var (
+		wrapped      []byte
+		keyID        sql.NullString
+		current      sql.NullString
+		currentIndex sql.NullString
+	)
+	err = tx.QueryRowContext(ctx,
+		`SELECT data_key, data_key_id, enterprise_id, nik_bidx FROM patients WITH (UPDLOCK) WHERE id = @p1 AND tenant_id = @p2 AND is_active = 1`,
+		patient.ID, tenantID,
+	).Scan(&wrapped, &keyID, &current, &currentIndex)
+	if err == sql.ErrNoRows {
 		return domain.ErrPatientNotFound
 	}
+	if err != nil {
+		return err
+	}
 
-	return nil
+	q := &queryBuilder{}
+	idParam := q.arg(patient.ID)
+	assignments := []string{
+		"updated_at = " + q.arg(patient.UpdatedAt),
+		"updated_by = " + q.arg(patient.UpdatedBy),
+	}
+
+	set := newColumnSet(fields)
+	switch {
+	case !set.encrypted:
+	case !keyID.Valid || keyID.String == "":
+		// Legacy plaintext row: encrypt all identifiers with a new data key
+		sealed, err := r.seal(ctx, patient)
+		if err != nil {
+			return err
+		}
+		assignments = append(assignments,
+			"nik = "+q.arg(sealed.nik),
+			"phone = "+q.arg(sealed.phone),
+			"email = "+q.arg(sealed.email),
+			"insurance_number = "+q.arg(sealed.insuranceNumber),
+			"nik_bidx = "+q.arg(sealed.nikIndex),
+			"data_key = "+q.arg(sealed.dataKey.Wrapped),
+			"data_key_id = "+q.arg(sealed.dataKey.KeyID),
+		)
+	default:
+		dataKey, err := r.cipher.OpenDataKey(ctx, keyID.String, wrapped)
+		if err != nil {
+			return err
+		}
+		for _, c := range set.columns {
+			if !encryptedColumns[c.name] {
+				continue
+			}
+			plaintext := *c.dest(patient).(*string)
+			ciphertext, err := dataKey.Encrypt(plaintext, fieldAAD(patient.ID, c.name))
+			if err != nil {
+				return fmt.Errorf("failed to encrypt %s: %w", c.name, err)
+			}
+			assignments = append(assignments, c.name+" = "+q.arg(ciphertext))
+			if c.name == "nik" {
+				assignments = append(assignments, "nik_bidx = "+q.arg(r.cipher.BlindIndex("nik", plaintext)))
+			}
+		}
+	}
+
+	for _, c := range set.columns {
+		if c.name == "id" || encryptedColumns[c.name] {
+			continue
+		}
+		assignments = append(assignments, c.name+" = "+q.arg(reflect.ValueOf(c.dest(patient)).Elem().Interface()))
+	}
+
+	patient.EnterpriseID = current.String
+	if set.has("nik") {
+		if nikIndex := r.cipher.BlindIndex("nik", patient.NIK); nikIndex != currentIndex.String {
+			patient.EnterpriseID, err = relink(ctx, tx, patient, current.String, nikIndex)
+			if err != nil {
+				return err
+			}
+			assignments = append(assignments, "enterprise_id = "+q.arg(patient.EnterpriseID))
+		}
+	}
+
+	query := `UPDATE patients SET ` + strings.Join(assignments, ", ") + ` WHERE id = ` + idParam
+	if _, err := tx.ExecContext(ctx, query, q.args...); err != nil {
+		return err
+	}
+
+	data := domain.NewPatientEventData(patient)
+	data.IsActive = true
+	data.ChangedFields = fields
+	if err := appendEvent(ctx, tx, domain.EventPatientUpdated, data); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *patientRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Soft delete
-	query := `UPDATE patients SET is_active = 0, updated_at = @p2 WHERE id = @p1`
+	query := `UPDATE patients SET is_active = 0, updated_at = @p2 OUTPUT inserted.medical_record_no WHERE id = @p1 AND tenant_id = @p3`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		data := domain.PatientEventData{ID: id}
+		err := tx.QueryRowContext(ctx, query, id, time.Now(), tenantID).Scan(&data.MedicalRecordNo)
+		if err == sql.ErrNoRows {
+			return domain.ErrPatientNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, tx, domain.EventPatientDeleted, data)
+	})
+}
 
-	result, err := r.db.ExecContext(ctx, query, id, time.Now())
+func (r *patientRepository) Merge(ctx context.Context, survivorID, mergedID, mergedBy string) error {
+	tenantID, err := tenantOf(ctx)
 	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	// Both records must belong to the tenant
+	query := `
+		UPDATE patients SET is_active = 0, merged_into = @p2, updated_at = @p3, updated_by = @p4
+		OUTPUT inserted.medical_record_no
+		WHERE id = @p1 AND tenant_id = @p5 AND is_active = 1
+			AND EXISTS (SELECT 1 FROM patients WHERE id = @p2 AND tenant_id = @p5 AND is_active = 1)
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		data := domain.PatientEventData{ID: mergedID, MergedInto: survivorID, Actor: mergedBy}
+		err := tx.QueryRowContext(ctx, query, mergedID, survivorID, time.Now(), mergedBy, tenantID).Scan(&data.MedicalRecordNo)
+		if err == sql.ErrNoRows {
+			return domain.ErrPatientNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, tx, domain.EventPatientMerged, data)
+	})
+}
+
+// deathFields are the columns written by RecordDeath and ClearDeath
+var deathFields = []string{"deceased_at", "cause_of_death", "death_recorded_by"}
+
+func (r *patientRepository) RecordDeath(ctx context.Context, patient *domain.Patient) error {
+	return r.setDeath(ctx, patient, "deceased_at IS NULL", domain.EventPatientDeceased, domain.ErrPatientDeceased)
+}
+
+func (r *patientRepository) ClearDeath(ctx context.Context, patient *domain.Patient) error {
+	return r.setDeath(ctx, patient, "deceased_at IS NOT NULL", domain.EventPatientUpdated, domain.ErrPatientNotDeceased)
+}
+
+// setDeath writes the patient's death fields if the row meets condition,
+// returning conflict if it exists but does not.
+func (r *patientRepository) setDeath(ctx context.Context, patient *domain.Patient, condition, eventType string, conflict error) error {
+	tenantID, err := tenantOf(ctx)
 	if err != nil {
 		return err
 	}
+	patient.UpdatedAt = time.Now()
 
-	if rowsAffected == 0 {
-		return domain.ErrPatientNotFound
-	}
+	query := `
+		UPDATE patients SET deceased_at = @p2, cause_of_death = @p3, death_recorded_by = @p4, updated_at = @p5, updated_by = @p6
+		WHERE id = @p1 AND tenant_id = @p7 AND is_active = 1 AND ` + condition
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, patient.ID, patient.DeceasedAt, patient.CauseOfDeath,
+			patient.DeathRecordedBy, patient.UpdatedAt, patient.UpdatedBy, tenantID)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			var exists int
+			err := tx.QueryRowContext(ctx, `SELECT 1 FROM patients WHERE id = @p1 AND tenant_id = @p2 AND is_active = 1`, patient.ID, tenantID).Scan(&exists)
+			if err == sql.ErrNoRows {
+				return domain.ErrPatientNotFound
+			}
+			if err != nil {
+				return err
+			}
+			return conflict
+		}
+
+		data := domain.NewPatientEventData(patient)
+		data.IsActive = true
+		data.ChangedFields = deathFields
+		return appendEvent(ctx, tx, eventType, data)
+	})
 }
 
-func (r *patientRepository) List(ctx context.Context, filter domain.PatientFilter) ([]*domain.Patient, int, error) {
-	// Build dynamic query
-	var conditions []string
-	var args []interface{}
-	argCount := 1
+func (r *patientRepository) List(ctx context.Context, filter domain.PatientFilter) (*domain.PatientPage, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q := r.filterQuery(tenantID, filter)
+
+	page := &domain.PatientPage{}
+
+	// Count total records; the keyset condition below is not part of it
+	if !filter.SkipTotal {
+		countQuery := "SELECT COUNT(*) FROM patients WHERE " + q.conditions()
+		var total int
+		err := r.db.QueryRowContext(ctx, countQuery, q.args...).Scan(&total)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
 
-	baseQuery := `FROM patients WHERE is_active = 1`
+	sort, order := filter.Sort, filter.Order
+	if sort == "" {
+		sort, order = "created_at", "DESC"
+	}
+
+	// Keyset pagination: continue after (or before) the cursor row
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	orderBy, err := keyset(q, sort, order, filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add pagination, fetching one extra row to know whether more exist
+	offset := 0
+	if filter.Cursor == nil {
+		offset = (filter.Page - 1) * filter.Limit
+	}
+	paginationQuery := fmt.Sprintf(" OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, filter.Limit+1)
+
+	// Final query, selecting only the requested fields plus the sort column
+	// needed for cursors
+	set := newColumnSet(filter.Fields, sort)
+	selectQuery := `SELECT ` + set.sql() + ` FROM patients WHERE ` + q.conditions() + orderBy + paginationQuery
+
+	rows, err := r.db.QueryContext(ctx, selectQuery, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var patients []*domain.Patient
+	for rows.Next() {
+		patient, err := r.scanPatientColumns(ctx, rows, set)
+		if err != nil {
+			return nil, err
+		}
+		patients = append(patients, patient)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hasMore := len(patients) > filter.Limit
+	if hasMore {
+		patients = patients[:filter.Limit]
+	}
+
+	if backward {
+		for i, j := 0, len(patients)-1; i < j; i, j = i+1, j-1 {
+			patients[i], patients[j] = patients[j], patients[i]
+		}
+	}
+
+	page.Patients = patients
+	if len(patients) == 0 {
+		return page, nil
+	}
+
+	first, last := patients[0], patients[len(patients)-1]
+
+	// The extra row tells whether more rows exist in the paging direction;
+	// in the opposite direction there is always the page we came from
+	if hasMore || backward {
+		page.NextCursor = domain.NewCursor(last, sort, order, false).Encode()
+	}
+	if (hasMore && backward) || (!backward && (filter.Cursor != nil || offset > 0)) {
+		page.PrevCursor = domain.NewCursor(first, sort, order, true).Encode()
+	}
+
+	return page, nil
+}
+
+// filterQuery builds the conditions of a patient filter on the tenant's
+// patients, without the keyset cursor.
+func (r *patientRepository) filterQuery(tenantID string, filter domain.PatientFilter) *queryBuilder {
+	q := &queryBuilder{}
+	q.where("tenant_id = " + q.arg(tenantID))
+	q.where("is_active = 1")
 
 	if filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf(
-			"(first_name LIKE @p%d OR last_name LIKE @p%d OR nik LIKE @p%d OR medical_record_no LIKE @p%d)",
-			argCount, argCount, argCount, argCount,
+		// NIK is encrypted and can only be matched exactly via its blind index
+		search := q.arg("%" + filter.Search + "%")
+		q.where(fmt.Sprintf(
+			"(first_name LIKE %s OR last_name LIKE %s OR medical_record_no LIKE %s OR nik_bidx = %s)",
+			search, search, search, q.arg(r.cipher.BlindIndex("nik", filter.Search)),
 		))
-		searchParam := "%" + filter.Search + "%"
-		args = append(args, searchParam)
-		argCount++
+	}
+	if filter.GivenName != "" {
+		q.wordPrefix("first_name", filter.GivenName)
+	}
+	if filter.FamilyName != "" {
+		q.wordPrefix("last_name", filter.FamilyName)
 	}
 
-	if filter.City != "" {
-		conditions = append(conditions, fmt.Sprintf("city = @p%d", argCount))
-		args = append(args, filter.City)
-		argCount++
+	q.in("city", filter.Cities)
+	q.in("province", filter.Provinces)
+	q.in("gender", filter.Genders)
+	q.in("blood_type", filter.BloodTypes)
+	q.in("insurance_provider", filter.InsuranceProviders)
+	q.in("created_by", filter.CreatedBy)
+
+	if filter.EnterpriseID != "" {
+		q.where("enterprise_id = " + q.arg(filter.EnterpriseID))
 	}
 
-	if filter.Province != "" {
-		conditions = append(conditions, fmt.Sprintf("province = @p%d", argCount))
-		args = append(args, filter.Province)
-		argCount++
+	if filter.DateOfBirth != nil {
+		q.where("date_of_birth = " + q.arg(*filter.DateOfBirth))
 	}
 
-	if len(conditions) > 0 {
-		baseQuery += " AND " + strings.Join(conditions, " AND ")
+	// Age range is translated to a date of birth range so the index is used
+	year, month, day := time.Now().Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if filter.AgeMin != nil {
+		q.where("date_of_birth <= " + q.arg(today.AddDate(-*filter.AgeMin, 0, 0)))
+	}
+	if filter.AgeMax != nil {
+		q.where("date_of_birth > " + q.arg(today.AddDate(-(*filter.AgeMax+1), 0, 0)))
 	}
 
-	// Count total records
-	countQuery := "SELECT COUNT(*) " + baseQuery
-	var total int
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, err
+	if filter.CreatedFrom != nil {
+		q.where("created_at >= " + q.arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		q.where("created_at < " + q.arg(*filter.CreatedTo))
+	}
+	if filter.UpdatedFrom != nil {
+		q.where("updated_at >= " + q.arg(*filter.UpdatedFrom))
+	}
+	if filter.UpdatedTo != nil {
+		q.where("updated_at < " + q.arg(*filter.UpdatedTo))
+	}
+
+	if filter.Deceased != nil {
+		if *filter.Deceased {
+			q.where("deceased_at IS NOT NULL")
+		} else {
+			q.where("deceased_at IS NULL")
+		}
+	}
+	if filter.DeceasedFrom != nil {
+		q.where("deceased_at >= " + q.arg(*filter.DeceasedFrom))
 	}
+	if filter.DeceasedTo != nil {
+		q.where("deceased_at < " + q.arg(*filter.DeceasedTo))
+	}
+
+	return q
+}
 
-	// Add sorting
-	orderBy := " ORDER BY created_at DESC"
-	if filter.Sort != "" {
-		orderBy = fmt.Sprintf(" ORDER BY %s %s", filter.Sort, filter.Order)
+// Scan calls fn for every patient matching the filter, in the filter's
+// order, reading them one by one from the database cursor. Pagination is
+// ignored.
+func (r *patientRepository) Scan(ctx context.Context, filter domain.PatientFilter, fn func(*domain.Patient) error) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
 	}
+	q := r.filterQuery(tenantID, filter)
 
-	// Add pagination
-	offset := (filter.Page - 1) * filter.Limit
-	paginationQuery := fmt.Sprintf(" OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, filter.Limit)
+	sort, order := filter.Sort, filter.Order
+	if sort == "" {
+		sort, order = "created_at", "DESC"
+	}
 
-	// Final query
-	selectQuery := `
-		SELECT 
-			id, medical_record_no, nik, first_name, last_name,
-			date_of_birth, gender, blood_type, phone, email,
-			address, city, province, postal_code,
-			emergency_contact, emergency_phone,
-			insurance_provider, insurance_number,
-			allergies, chronic_conditions,
-			is_active, created_at, updated_at, created_by, updated_by
-		` + baseQuery + orderBy + paginationQuery
+	set := newColumnSet(filter.Fields, sort)
+	query := fmt.Sprintf("SELECT %s FROM patients WHERE %s ORDER BY %s %s, id %s",
+		set.sql(), q.conditions(), sortColumn(sort), order, order)
 
-	rows, err := r.db.QueryContext(ctx, selectQuery, args...)
+	rows, err := r.db.QueryContext(ctx, query, q.args...)
 	if err != nil {
-		return nil, 0, err
+		return err
 	}
 	defer rows.Close()
 
-	var patients []*domain.Patient
 	for rows.Next() {
-		patient := &domain.Patient{}
-		err := rows.Scan(
-			&patient.ID, &patient.MedicalRecordNo, &patient.NIK, &patient.FirstName, &patient.LastName,
-			&patient.DateOfBirth, &patient.Gender, &patient.BloodType, &patient.Phone, &patient.Email,
-			&patient.Address, &patient.City, &patient.Province, &patient.PostalCode,
-			&patient.EmergencyContact, &patient.EmergencyPhone,
-			&patient.InsuranceProvider, &patient.InsuranceNumber,
-			&patient.Allergies, &patient.ChronicConditions,
-			&patient.IsActive, &patient.CreatedAt, &patient.UpdatedAt, &patient.CreatedBy, &patient.UpdatedBy,
-		)
+		patient, err := r.scanPatientColumns(ctx, rows, set)
 		if err != nil {
-			return nil, 0, err
+			return err
+		}
+		if err := fn(patient); err != nil {
+			return err
 		}
-		patients = append(patients, patient)
 	}
 
-	return patients, total, nil
+	return rows.Err()
+}
+
+// sortColumn returns the column a sort field is ordered by. last_name is
+// optional, and NULL matches no keyset comparison, so it is ordered by
+// last_name_key, its non-NULL copy; cursors hold "" for a missing last name.
+func sortColumn(sort string) string {
+	if sort == "last_name" {
+		return "last_name_key"
+	}
+	return sort
+}
+
+// keyset adds the condition continuing after (or before) the cursor, using
+// id as a tie-breaker so rows with equal sort values are not skipped, and
+// returns the ORDER BY clause. A backward page is read in reverse and
+// flipped afterwards.
+func keyset(q *queryBuilder, sort, order string, cursor *domain.Cursor) (string, error) {
+	column := sortColumn(sort)
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		value, err := cursorValue(cursor)
+		if err != nil {
+			return "", err
+		}
+
+		op := ">"
+		if (order == "DESC") != backward {
+			op = "<"
+		}
+
+		v, id := q.arg(value), q.arg(cursor.ID)
+		q.where(fmt.Sprintf("(%s %s %s OR (%s = %s AND id %s %s))", column, op, v, column, v, op, id))
+	}
+
+	scanOrder := order
+	if backward {
+		scanOrder = reverseOrder(order)
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", column, scanOrder, scanOrder), nil
+}
+
+// cursorValue converts the cursor's sort value to the column's type.
+func cursorValue(cursor *domain.Cursor) (interface{}, error) {
+	switch cursor.Sort {
+	case "created_at", "updated_at":
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		return t, nil
+	default:
+		return cursor.Value, nil
+	}
+}
+
+func reverseOrder(order string) string {
+	if order == "DESC" {
+		return "ASC"
+	}
+	return "DESC"
 }
 
 func (r *patientRepository) Exists(ctx context.Context, id string) (bool, error) {
-	query := `SELECT COUNT(*) FROM patients WHERE id = @p1 AND is_active = 1`
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	query := `SELECT COUNT(*) FROM patients WHERE id = @p1 AND tenant_id = @p2 AND is_active = 1`
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&count)
+	err = r.db.QueryRowContext(ctx, query, id, tenantID).Scan(&count)
 	if err != nil {
 		return false, err
 	}
 
 	return count > 0, nil
 }
+
+func (r *patientRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Patient, error) {
+	return r.GetByKeys(ctx, domain.KeyID, ids, nil)
+}
+
+func (r *patientRepository) GetByKeys(ctx context.Context, key domain.PatientKey, values []string, fields []string) ([]*domain.Patient, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	set := newColumnSet(fields, string(key))
+
+	q := &queryBuilder{}
+	q.where("tenant_id = " + q.arg(tenantID))
+	switch key {
+	case domain.KeyNIK:
+		// NIK is encrypted, so it is matched through its blind index
+		indexes := make([]string, len(values))
+		for i, nik := range values {
+			indexes[i] = r.cipher.BlindIndex("nik", nik)
+		}
+		q.in("nik_bidx", indexes)
+	case domain.KeyMedicalRecordNo:
+		q.in("medical_record_no", values)
+	default:
+		q.in("id", values)
+	}
+	q.where("is_active = 1")
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+set.sql()+` FROM patients WHERE `+q.conditions(), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var patients []*domain.Patient
+	for rows.Next() {
+		patient, err := r.scanPatientColumns(ctx, rows, set)
+		if err != nil {
+			return nil, err
+		}
+		patients = append(patients, patient)
+	}
+
+	return patients, rows.Err()
+}
+
+// ExistingNIKs returns which of niks belong to a stored patient of the
+// tenant, active or not.
+func (r *patientRepository) ExistingNIKs(ctx context.Context, niks []string) (map[string]bool, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool)
+	if len(niks) == 0 {
+		return existing, nil
+	}
+
+	byIndex := make(map[string]string, len(niks))
+	indexes := make([]string, 0, len(niks))
+	for _, nik := range niks {
+		index := r.cipher.BlindIndex("nik", nik)
+		byIndex[index] = nik
+		indexes = append(indexes, index)
+	}
+
+	q := &queryBuilder{}
+	q.where("tenant_id = " + q.arg(tenantID))
+	q.in("nik_bidx", indexes)
+
+	rows, err := r.db.QueryContext(ctx, `SELECT nik_bidx FROM patients WHERE `+q.conditions(), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			return nil, err
+		}
+		existing[byIndex[index]] = true
+	}
+
+	return existing, rows.Err()
+}
+
+// ScanNames reads the patients of every tenant; the search index keeps
+// them apart.
+func (r *patientRepository) ScanNames(ctx context.Context, since time.Time, fn func(*domain.Patient) error) error {
+	query := `
+		SELECT id, tenant_id, medical_record_no, first_name, ISNULL(last_name, ''), is_active, updated_at
+		FROM patients
+		WHERE updated_at >= @p1
+		ORDER BY updated_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		patient := &domain.Patient{}
+		err := rows.Scan(&patient.ID, &patient.TenantID, &patient.MedicalRecordNo, &patient.FirstName, &patient.LastName,
+			&patient.IsActive, &patient.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		if err := fn(patient); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"patient-service/internal/domain"
+)
+
+func TestKeysetLastNameCondition(t *testing.T) {
+	cursor := domain.NewCursor(&domain.Patient{ID: "p2"}, "last_name", "ASC", false)
+
+	q := &queryBuilder{}
+	orderBy, err := keyset(q, "last_name", "ASC", &cursor)
+	if err != nil {
+		t.Fatalf("keyset failed: %v", err)
+	}
+	if want := "(last_name_key > @p1 OR (last_name_key = @p1 AND id > @p2))"; q.conditions() != want {
+		t.Errorf("Expected %s, got %s", want, q.conditions())
+	}
+	if want := " ORDER BY last_name_key ASC, id ASC"; orderBy != want {
+		t.Errorf("Expected %q, got %q", want, orderBy)
+	}
+	if q.args[0] != "" {
+		t.Errorf("Expected a missing last name to be compared as \"\", got %v", q.args[0])
+	}
+
+	cursor.Backward = true
+	q = &queryBuilder{}
+	orderBy, _ = keyset(q, "last_name", "ASC", &cursor)
+	if !strings.Contains(q.conditions(), "last_name_key < @p1") || orderBy != " ORDER BY last_name_key DESC, id DESC" {
+		t.Errorf("Unexpected backward keyset %s%s", q.conditions(), orderBy)
+	}
+}
+
+// TestKeysetPagesThroughNullLastNames pages through patients, some without a
+// last name, the way SQL Server evaluates the keyset condition: a NULL in
+// the compared column matches neither comparison.
+func TestKeysetPagesThroughNullLastNames(t *testing.T) {
+	lastNames := map[string]*string{"p1": nil, "p2": strPtr("Santoso"), "p3": nil, "p4": strPtr("Ali"), "p5": nil}
+
+	// value returns the row's value of the sort column, false for NULL
+	value := func(id, column string) (string, bool) {
+		lastName := lastNames[id]
+		switch {
+		case column == "last_name_key" && lastName == nil:
+			return "", true
+		case lastName == nil:
+			return "", false
+		}
+		return *lastName, true
+	}
+
+	column := sortColumn("last_name")
+	var (
+		seen   []string
+		cursor *domain.Cursor
+	)
+	for page := 0; page < 5; page++ {
+		if _, err := keyset(&queryBuilder{}, "last_name", "ASC", cursor); err != nil {
+			t.Fatalf("keyset failed: %v", err)
+		}
+
+		var rows []string
+		for id := range lastNames {
+			v, ok := value(id, column)
+			if cursor != nil && (!ok || !(v > cursor.Value || (v == cursor.Value && id > cursor.ID))) {
+				continue
+			}
+			rows = append(rows, id)
+		}
+		sort.Slice(rows, func(i, j int) bool {
+			vi, _ := value(rows[i], column)
+			vj, _ := value(rows[j], column)
+			return vi < vj || (vi == vj && rows[i] < rows[j])
+		})
+		if len(rows) == 0 {
+			break
+		}
+		if len(rows) > 2 {
+			rows = rows[:2]
+		}
+		seen = append(seen, rows...)
+
+		last := &domain.Patient{ID: rows[len(rows)-1]}
+		if lastName := lastNames[last.ID]; lastName != nil {
+			last.LastName = *lastName
+		}
+		next := domain.NewCursor(last, "last_name", "ASC", false)
+		cursor = &next
+	}
+
+	if got := strings.Join(seen, ","); got != "p1,p3,p5,p4,p2" {
+		t.Errorf("Expected every patient once, those without a last name first, got %s", got)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestFilterQueryNameParts(t *testing.T) {
+	r := &patientRepository{}
+	q := r.filterQuery("t1", domain.PatientFilter{FamilyName: "Santoso", GivenName: "50%_b"})
+
+	want := "tenant_id = @p1 AND is_active = 1 AND (first_name LIKE @p2 OR first_name LIKE @p3) AND (last_name LIKE @p4 OR last_name LIKE @p5)"
+	if q.conditions() != want {
+		t.Errorf("Expected given to match first_name and family last_name only, got %s", q.conditions())
+	}
+
+	args := []interface{}{"t1", "50[%][_]b%", "% 50[%][_]b%", "Santoso%", "% Santoso%"}
+	if !reflect.DeepEqual(q.args, args) {
+		t.Errorf("Expected args %v, got %v", args, q.args)
+	}
+}
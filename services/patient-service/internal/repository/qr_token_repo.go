@@ -0,0 +1,181 @@
+// Public profile token repository
+// internal/repository/qr_token_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+type qrTokenRepository struct {
+	db *sql.DB
+}
+
+func NewQRTokenRepository(db *sql.DB) QRTokenRepository {
+	return &qrTokenRepository{db: db}
+}
+
+func (r *qrTokenRepository) Create(ctx context.Context, token *domain.QRToken) error {
+	token.CreatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO patient_qr_tokens (id, patient_id, expires_at, created_by, created_at)
+		VALUES (@p1, @p2, @p3, @p4, @p5)
+	`, token.ID, token.PatientID, token.ExpiresAt, nullString(token.CreatedBy), token.CreatedAt)
+	return err
+}
+
+const qrTokenColumns = `id, patient_id, expires_at, revoked_at, revoked_by, last_scanned_at, scan_count, created_by, created_at`
+
+func scanQRToken(row rowScanner, extra ...interface{}) (*domain.QRToken, error) {
+	var (
+		token         domain.QRToken
+		revokedAt     sql.NullTime
+		revokedBy     sql.NullString
+		lastScannedAt sql.NullTime
+		createdBy     sql.NullString
+	)
+
+	dest := []interface{}{&token.ID, &token.PatientID, &token.ExpiresAt, &revokedAt, &revokedBy,
+		&lastScannedAt, &token.ScanCount, &createdBy, &token.CreatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrQRTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	token.RevokedBy = revokedBy.String
+	if lastScannedAt.Valid {
+		token.LastScannedAt = &lastScannedAt.Time
+	}
+	token.CreatedBy = createdBy.String
+
+	return &token, nil
+}
+
+// GetByID reads the token with its patient's tenant, as tokens are
+// resolved without one.
+func (r *qrTokenRepository) GetByID(ctx context.Context, id string) (*domain.QRToken, error) {
+	query := `SELECT ` + qrTokenColumns + `,
+			ISNULL((SELECT p.tenant_id FROM patients p WHERE p.id = patient_qr_tokens.patient_id), '')
+		FROM patient_qr_tokens
+		WHERE id = @p1
+	`
+
+	var tenantID string
+	token, err := scanQRToken(r.db.QueryRowContext(ctx, query, id), &tenantID)
+	if err != nil {
+		return nil, err
+	}
+	token.TenantID = tenantID
+	return token, nil
+}
+
+func (r *qrTokenRepository) ListByPatient(ctx context.Context, patientID string) ([]*domain.QRToken, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+qrTokenColumns+`
+		FROM patient_qr_tokens
+		WHERE patient_id = @p1
+		ORDER BY created_at DESC
+	`, patientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*domain.QRToken
+	for rows.Next() {
+		token, err := scanQRToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (r *qrTokenRepository) Revoke(ctx context.Context, token *domain.QRToken) error {
+	now := time.Now()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE patient_qr_tokens SET revoked_at = @p3, revoked_by = @p4
+		WHERE id = @p1 AND patient_id = @p2 AND revoked_at IS NULL
+	`, token.ID, token.PatientID, now, token.RevokedBy)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrQRTokenRevoked
+	}
+
+	token.RevokedAt = &now
+	return nil
+}
+
+func (r *qrTokenRepository) RecordScan(ctx context.Context, scan *domain.QRScan) error {
+	scan.ScannedAt = time.Now()
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO patient_qr_scans (token_id, patient_id, result, ip, user_agent, scanned_at)
+			OUTPUT INSERTED.id
+			VALUES (@p1, @p2, @p3, @p4, @p5, @p6)
+		`, nullString(scan.TokenID), nullString(scan.PatientID), scan.Result,
+			nullString(scan.IP), nullString(truncate(scan.UserAgent, 255)), scan.ScannedAt).Scan(&scan.ID)
+		if err != nil || scan.TokenID == "" {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE patient_qr_tokens SET last_scanned_at = @p2, scan_count = scan_count + 1
+			WHERE id = @p1
+		`, scan.TokenID, scan.ScannedAt)
+		return err
+	})
+}
+
+func (r *qrTokenRepository) ListScans(ctx context.Context, tokenID string, limit int) ([]*domain.QRScan, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT TOP (@p2) id, token_id, patient_id, result, ip, user_agent, scanned_at
+		FROM patient_qr_scans
+		WHERE token_id = @p1
+		ORDER BY scanned_at DESC
+	`, tokenID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scans []*domain.QRScan
+	for rows.Next() {
+		var (
+			scan               domain.QRScan
+			tokenID, patientID sql.NullString
+			ip, userAgent      sql.NullString
+		)
+		if err := rows.Scan(&scan.ID, &tokenID, &patientID, &scan.Result, &ip, &userAgent, &scan.ScannedAt); err != nil {
+			return nil, err
+		}
+		scan.TokenID = tokenID.String
+		scan.PatientID = patientID.String
+		scan.IP = ip.String
+		scan.UserAgent = userAgent.String
+		scans = append(scans, &scan)
+	}
+
+	return scans, rows.Err()
+}
@@ -0,0 +1,56 @@
+// Dynamic WHERE clause builder
+// internal/repository/query_builder.go
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queryBuilder collects conditions and their positional parameters.
+// Values are always passed as parameters; only column names chosen by the
+// repository itself are interpolated.
+type queryBuilder struct {
+	clauses []string
+	args    []interface{}
+}
+
+// arg registers a parameter and returns its placeholder.
+func (q *queryBuilder) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("@p%d", len(q.args))
+}
+
+func (q *queryBuilder) where(condition string) {
+	q.clauses = append(q.clauses, condition)
+}
+
+// in adds "column IN (...)" when values is not empty.
+func (q *queryBuilder) in(column string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = q.arg(v)
+	}
+	q.where(fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")))
+}
+
+// wordPrefix adds a condition matching values of column with a word
+// starting with prefix, ignoring case under the default collation.
+func (q *queryBuilder) wordPrefix(column, prefix string) {
+	escaped := likeEscaper.Replace(prefix)
+	q.where(fmt.Sprintf("(%s LIKE %s OR %s LIKE %s)", column, q.arg(escaped+"%"), column, q.arg("% "+escaped+"%")))
+}
+
+// likeEscaper makes LIKE wildcards in a value match literally.
+var likeEscaper = strings.NewReplacer("[", "[[]", "%", "[%]", "_", "[_]")
+
+func (q *queryBuilder) conditions() string {
+	if len(q.clauses) == 0 {
+		return "1 = 1"
+	}
+	return strings.Join(q.clauses, " AND ")
+}
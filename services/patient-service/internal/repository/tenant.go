@@ -0,0 +1,20 @@
+// Tenant scoping of queries
+// internal/repository/tenant.go
+package repository
+
+import (
+	"context"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/tenant"
+)
+
+// tenantOf returns the tenant the context acts for. Queries on tenant data
+// refuse to run without one rather than reading across facilities.
+func tenantOf(ctx context.Context) (string, error) {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return "", domain.ErrTenantRequired
+	}
+	return id, nil
+}
@@ -0,0 +1,552 @@
+// Webhook subscription and delivery repository
+// internal/repository/webhook_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/pkg/envelope"
+
+	"github.com/google/uuid"
+)
+
+type webhookRepository struct {
+	db     *sql.DB
+	cipher *envelope.Cipher
+}
+
+// NewWebhookRepository creates the repository. Signing secrets are stored
+// encrypted with a per-subscription data key.
+func NewWebhookRepository(db *sql.DB, cipher *envelope.Cipher) WebhookRepository {
+	return &webhookRepository{db: db, cipher: cipher}
+}
+
+func secretAAD(subscriptionID string) string {
+	return "webhook|" + subscriptionID + "|secret"
+}
+
+func (r *webhookRepository) sealSecret(ctx context.Context, sub *domain.WebhookSubscription) (string, *envelope.DataKey, error) {
+	dataKey, err := r.cipher.NewDataKey(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	ciphertext, err := dataKey.Encrypt(sub.Secret, secretAAD(sub.ID))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+	return ciphertext, dataKey, nil
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+	sub.ID = uuid.New().String()
+	sub.TenantID = tenantID
+	sub.CreatedAt = time.Now()
+	sub.UpdatedAt = sub.CreatedAt
+
+	secret, dataKey, err := r.sealSecret(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO webhook_subscriptions (
+			id, url, description, event_types, consent_type, secret, data_key, data_key_id,
+			is_active, consecutive_failures, created_at, updated_at, created_by, updated_by, tenant_id
+		) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, 0, @p10, @p10, @p11, @p11, @p12)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		sub.ID, sub.URL, sub.Description, strings.Join(sub.EventTypes, ","), nullString(sub.ConsentType),
+		secret, dataKey.Wrapped, dataKey.KeyID, sub.IsActive, sub.CreatedAt, sub.CreatedBy, sub.TenantID,
+	)
+	return err
+}
+
+const subscriptionColumns = `id, tenant_id, url, description, event_types, consent_type, secret, data_key, data_key_id,
+	is_active, consecutive_failures, disabled_at, disabled_reason, created_at, updated_at, created_by, updated_by`
+
+func (r *webhookRepository) scanSubscription(ctx context.Context, row rowScanner) (*domain.WebhookSubscription, error) {
+	var (
+		sub            domain.WebhookSubscription
+		description    sql.NullString
+		eventTypes     string
+		consentType    sql.NullString
+		wrapped        []byte
+		keyID          string
+		disabledAt     sql.NullTime
+		disabledReason sql.NullString
+		createdBy      sql.NullString
+		updatedBy      sql.NullString
+	)
+
+	err := row.Scan(&sub.ID, &sub.TenantID, &sub.URL, &description, &eventTypes, &consentType, &sub.Secret, &wrapped, &keyID,
+		&sub.IsActive, &sub.ConsecutiveFailures, &disabledAt, &disabledReason,
+		&sub.CreatedAt, &sub.UpdatedAt, &createdBy, &updatedBy)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := r.cipher.OpenDataKey(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Secret, err = dataKey.Decrypt(sub.Secret, secretAAD(sub.ID)); err != nil {
+		return nil, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+
+	sub.Description = description.String
+	sub.ConsentType = consentType.String
+	if eventTypes != "" {
+		sub.EventTypes = strings.Split(eventTypes, ",")
+	}
+	if disabledAt.Valid {
+		sub.DisabledAt = &disabledAt.Time
+	}
+	sub.DisabledReason = disabledReason.String
+	sub.CreatedBy = createdBy.String
+	sub.UpdatedBy = updatedBy.String
+
+	return &sub, nil
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id = @p1 AND tenant_id = @p2`
+
+	return r.scanSubscription(ctx, r.db.QueryRowContext(ctx, query, id, tenantID))
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context, activeOnly bool) ([]*domain.WebhookSubscription, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE tenant_id = @p1`
+	if activeOnly {
+		query += ` AND is_active = 1`
+	}
+	query += ` ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []*domain.WebhookSubscription
+	for rows.Next() {
+		sub, err := r.scanSubscription(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+	sub.UpdatedAt = time.Now()
+
+	secret, dataKey, err := r.sealSecret(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	// Re-enabling clears the failure streak
+	query := `
+		UPDATE webhook_subscriptions SET
+			url = @p2,
+			description = @p3,
+			event_types = @p4,
+			consent_type = @p5,
+			secret = @p6,
+			data_key = @p7,
+			data_key_id = @p8,
+			consecutive_failures = CASE WHEN @p9 = 1 AND is_active = 0 THEN 0 ELSE consecutive_failures END,
+			disabled_at = CASE WHEN @p9 = 1 THEN NULL ELSE disabled_at END,
+			disabled_reason = CASE WHEN @p9 = 1 THEN NULL ELSE disabled_reason END,
+			is_active = @p9,
+			updated_at = @p10,
+			updated_by = @p11
+		WHERE id = @p1 AND tenant_id = @p12
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		sub.ID, sub.URL, sub.Description, strings.Join(sub.EventTypes, ","), nullString(sub.ConsentType),
+		secret, dataKey.Wrapped, dataKey.KeyID, sub.IsActive, sub.UpdatedAt, sub.UpdatedBy, tenantID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = @p1 AND tenant_id = @p2`, id, tenantID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrWebhookNotFound
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE a FROM webhook_delivery_attempts a
+			JOIN webhook_deliveries d ON d.id = a.delivery_id
+			WHERE d.subscription_id = @p1
+		`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE subscription_id = @p1`, id)
+		return err
+	})
+}
+
+func (r *webhookRepository) EnqueueDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	query := `
+		IF NOT EXISTS (SELECT 1 FROM webhook_deliveries WITH (UPDLOCK, HOLDLOCK) WHERE subscription_id = @p2 AND event_id = @p3)
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at
+		) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, 0, @p7, @p7, @p7)
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, d := range deliveries {
+			d.ID = uuid.New().String()
+			d.Status = domain.DeliveryPending
+			d.CreatedAt = time.Now()
+			d.NextAttemptAt = d.CreatedAt
+
+			_, err := tx.ExecContext(ctx, query,
+				d.ID, d.SubscriptionID, d.EventID, d.EventType, string(d.Payload), d.Status, d.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, last_status_code, last_error, created_at, delivered_at`
+
+func scanDelivery(row rowScanner) (*domain.WebhookDelivery, error) {
+	var (
+		d           domain.WebhookDelivery
+		payload     string
+		statusCode  sql.NullInt64
+		lastError   sql.NullString
+		deliveredAt sql.NullTime
+	)
+
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &statusCode, &lastError, &d.CreatedAt, &deliveredAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.Payload = []byte(payload)
+	d.LastStatusCode = int(statusCode.Int64)
+	d.LastError = lastError.String
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
+
+func prefixColumns(prefix, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, p := range parts {
+		parts[i] = prefix + strings.TrimSpace(p)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (r *webhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	now := time.Now()
+
+	query := `
+		UPDATE TOP (@p1) d SET next_attempt_at = @p3
+		OUTPUT ` + prefixColumns("inserted.", deliveryColumns) + `
+		FROM webhook_deliveries d WITH (READPAST, UPDLOCK, ROWLOCK)
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = @p4 AND d.next_attempt_at <= @p2 AND s.is_active = 1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, now, now.Add(lease), domain.DeliveryPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *webhookRepository) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt *domain.WebhookAttempt, disableAfter int) (bool, error) {
+	var disabled bool
+
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_delivery_attempts (delivery_id, attempted_at, status_code, error, duration_ms)
+			VALUES (@p1, @p2, @p3, @p4, @p5)
+		`, delivery.ID, attempt.AttemptedAt, nullInt(attempt.StatusCode), nullString(attempt.Error), attempt.Duration.Milliseconds())
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE webhook_deliveries SET
+				status = @p2, attempts = @p3, next_attempt_at = @p4,
+				last_status_code = @p5, last_error = @p6, delivered_at = @p7, updated_at = @p8
+			WHERE id = @p1
+		`, delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+			nullInt(delivery.LastStatusCode), nullString(delivery.LastError), delivery.DeliveredAt, attempt.AttemptedAt)
+		if err != nil {
+			return err
+		}
+
+		if attempt.Succeeded() {
+			_, err = tx.ExecContext(ctx, `UPDATE webhook_subscriptions SET consecutive_failures = 0 WHERE id = @p1`, delivery.SubscriptionID)
+			return err
+		}
+
+		var wasActive, isActive bool
+		err = tx.QueryRowContext(ctx, `
+			UPDATE webhook_subscriptions SET
+				consecutive_failures = consecutive_failures + 1,
+				is_active = CASE WHEN consecutive_failures + 1 >= @p2 THEN 0 ELSE is_active END,
+				disabled_at = CASE WHEN is_active = 1 AND consecutive_failures + 1 >= @p2 THEN @p3 ELSE disabled_at END,
+				disabled_reason = CASE WHEN is_active = 1 AND consecutive_failures + 1 >= @p2 THEN @p4 ELSE disabled_reason END
+			OUTPUT deleted.is_active, inserted.is_active
+			WHERE id = @p1
+		`, delivery.SubscriptionID, disableAfter, attempt.AttemptedAt,
+			fmt.Sprintf("Disabled after %d consecutive failed deliveries", disableAfter),
+		).Scan(&wasActive, &isActive)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		disabled = wasActive && !isActive
+		return nil
+	})
+
+	return disabled, err
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, filter domain.DeliveryFilter) ([]*domain.WebhookDelivery, int, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q := &queryBuilder{}
+	q.where(ofTenant(q.arg(tenantID)))
+	if filter.SubscriptionID != "" {
+		q.where("subscription_id = " + q.arg(filter.SubscriptionID))
+	}
+	if filter.Status != "" {
+		q.where("status = " + q.arg(filter.Status))
+	}
+	where := q.conditions()
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE `+where, q.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset := q.arg((filter.Page - 1) * filter.Limit)
+	limit := q.arg(filter.Limit)
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE ` + where + `
+		ORDER BY created_at DESC, id
+		OFFSET ` + offset + ` ROWS FETCH NEXT ` + limit + ` ROWS ONLY`
+
+	rows, err := r.db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, total, rows.Err()
+}
+
+// ofTenant limits deliveries to the subscriptions of the tenant parameter.
+func ofTenant(param string) string {
+	return "subscription_id IN (SELECT id FROM webhook_subscriptions WHERE tenant_id = " + param + ")"
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, subscriptionID, id string) (*domain.WebhookDelivery, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = @p1 AND subscription_id = @p2 AND ` + ofTenant("@p3")
+
+	d, err := scanDelivery(r.db.QueryRowContext(ctx, query, id, subscriptionID, tenantID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT attempted_at, status_code, error, duration_ms
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = @p1
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			attempt    domain.WebhookAttempt
+			statusCode sql.NullInt64
+			errText    sql.NullString
+			durationMS int64
+		)
+		if err := rows.Scan(&attempt.AttemptedAt, &statusCode, &errText, &durationMS); err != nil {
+			return nil, err
+		}
+		attempt.StatusCode = int(statusCode.Int64)
+		attempt.Error = errText.String
+		attempt.Duration = time.Duration(durationMS) * time.Millisecond
+		d.Log = append(d.Log, &attempt)
+	}
+
+	return d, rows.Err()
+}
+
+func (r *webhookRepository) ReplayDelivery(ctx context.Context, subscriptionID, id string) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE webhook_deliveries SET
+			status = @p3, attempts = 0, next_attempt_at = @p4, delivered_at = NULL, updated_at = @p4
+		WHERE id = @p1 AND subscription_id = @p2 AND ` + ofTenant("@p5")
+
+	result, err := r.db.ExecContext(ctx, query, id, subscriptionID, domain.DeliveryPending, time.Now(), tenantID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) PruneDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	var pruned int64
+
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			DELETE a FROM webhook_delivery_attempts a
+			JOIN webhook_deliveries d ON d.id = a.delivery_id
+			WHERE d.status = @p1 AND d.created_at < @p2
+		`, domain.DeliveryDelivered, before)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE status = @p1 AND created_at < @p2`, domain.DeliveryDelivered, before)
+		if err != nil {
+			return err
+		}
+		pruned, err = result.RowsAffected()
+		return err
+	})
+
+	return pruned, err
+}
+
+func nullInt(n int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(n), Valid: n != 0}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,298 @@
+// In-process patient name index
+// internal/search/index.go
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Match scores for a single query token against a name token
+const (
+	scoreExact    = 1.0
+	scorePrefix   = 0.85
+	scorePhonetic = 0.7
+	scoreEdit1    = 0.75
+	scoreEdit2    = 0.6
+
+	// MinScore is the lowest relevance returned by Search
+	MinScore = 0.5
+)
+
+// Document is the indexed projection of a patient.
+type Document struct {
+	ID              string
+	MedicalRecordNo string
+	FirstName       string
+	LastName        string
+}
+
+// Result is a matching document ID with its relevance in (0, 1].
+type Result struct {
+	ID    string
+	Score float64
+}
+
+type docEntry struct {
+	tokens []string
+	mrn    string
+	name   string
+}
+
+// Index is a token, phonetic and trigram index over patient names. It is
+// safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]*docEntry
+	postings map[string]map[string]struct{} // token -> doc IDs
+	phonetic map[string]map[string]struct{} // phonetic key -> tokens
+	trigrams map[string]map[string]struct{} // trigram -> tokens
+	mrns     map[string]string              // normalized MRN -> doc ID
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]*docEntry),
+		postings: make(map[string]map[string]struct{}),
+		phonetic: make(map[string]map[string]struct{}),
+		trigrams: make(map[string]map[string]struct{}),
+		mrns:     make(map[string]string),
+	}
+}
+
+// Len returns the number of indexed documents.
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.docs)
+}
+
+// Upsert adds or replaces a document.
+func (x *Index) Upsert(doc Document) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.remove(doc.ID)
+
+	entry := &docEntry{
+		tokens: uniqueTokens(Tokenize(doc.FirstName + " " + doc.LastName)),
+		mrn:    strings.ToLower(doc.MedicalRecordNo),
+		name:   strings.ToLower(strings.TrimSpace(doc.FirstName + " " + doc.LastName)),
+	}
+	x.docs[doc.ID] = entry
+
+	for _, token := range entry.tokens {
+		if x.postings[token] == nil {
+			x.postings[token] = make(map[string]struct{})
+
+			// First occurrence of the token: add it to the vocabulary indexes
+			addToSet(x.phonetic, PhoneticKey(token), token)
+			for _, gram := range trigramsOf(token) {
+				addToSet(x.trigrams, gram, token)
+			}
+		}
+		x.postings[token][doc.ID] = struct{}{}
+	}
+
+	if entry.mrn != "" {
+		x.mrns[entry.mrn] = doc.ID
+	}
+}
+
+// Remove deletes a document from the index.
+func (x *Index) Remove(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(id)
+}
+
+func (x *Index) remove(id string) {
+	entry, ok := x.docs[id]
+	if !ok {
+		return
+	}
+	delete(x.docs, id)
+
+	for _, token := range entry.tokens {
+		delete(x.postings[token], id)
+		if len(x.postings[token]) > 0 {
+			continue
+		}
+
+		// Last document with this token: drop it from the vocabulary
+		delete(x.postings, token)
+		removeFromSet(x.phonetic, PhoneticKey(token), token)
+		for _, gram := range trigramsOf(token) {
+			removeFromSet(x.trigrams, gram, token)
+		}
+	}
+
+	if entry.mrn != "" && x.mrns[entry.mrn] == id {
+		delete(x.mrns, entry.mrn)
+	}
+}
+
+// Search returns up to limit documents ranked by relevance. Every query
+// token is matched against the document's name tokens independently of
+// order, with prefix, phonetic and typo-tolerant matching.
+func (x *Index) Search(query string, limit int) []Result {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	// An exact medical record number wins outright
+	if id, ok := x.mrns[strings.ToLower(strings.TrimSpace(query))]; ok {
+		return []Result{{ID: id, Score: scoreExact}}
+	}
+
+	queryTokens := uniqueTokens(Tokenize(query))
+	if len(queryTokens) == 0 {
+		return nil
+	}
+
+	// best[docID][i] is the best score of query token i in that document
+	best := make(map[string][]float64)
+	for i, qt := range queryTokens {
+		for token, score := range x.candidates(qt) {
+			for id := range x.postings[token] {
+				scores := best[id]
+				if scores == nil {
+					scores = make([]float64, len(queryTokens))
+					best[id] = scores
+				}
+				if score > scores[i] {
+					scores[i] = score
+				}
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(best))
+	for id, scores := range best {
+		var sum float64
+		matched := 0
+		for _, s := range scores {
+			sum += s
+			if s > 0 {
+				matched++
+			}
+		}
+
+		// Average over query tokens, slightly favoring documents whose
+		// whole name was matched (no unmatched extra tokens)
+		score := sum / float64(len(queryTokens))
+		coverage := float64(matched) / float64(len(x.docs[id].tokens))
+		if coverage > 1 {
+			coverage = 1
+		}
+		score *= 0.9 + 0.1*coverage
+
+		if score >= MinScore {
+			results = append(results, Result{ID: id, Score: round(score)})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return x.docs[results[i].ID].name < x.docs[results[j].ID].name
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// candidates returns vocabulary tokens similar to the query token together
+// with their match score.
+func (x *Index) candidates(qt string) map[string]float64 {
+	found := make(map[string]float64)
+	consider := func(token string, score float64) {
+		if score > found[token] {
+			found[token] = score
+		}
+	}
+
+	if _, ok := x.postings[qt]; ok {
+		consider(qt, scoreExact)
+	}
+
+	for token := range x.phonetic[PhoneticKey(qt)] {
+		consider(token, scorePhonetic)
+	}
+
+	// Tokens sharing a trigram are checked for prefix and edit distance
+	edits := maxEdits(len([]rune(qt)))
+	for _, gram := range trigramsOf(qt) {
+		for token := range x.trigrams[gram] {
+			if found[token] >= scorePrefix {
+				continue
+			}
+
+			if len(qt) >= 3 && strings.HasPrefix(token, qt) {
+				consider(token, scorePrefix)
+				continue
+			}
+
+			switch editDistance(qt, token, edits) {
+			case 1:
+				consider(token, scoreEdit1)
+			case 2:
+				consider(token, scoreEdit2)
+			}
+		}
+	}
+
+	return found
+}
+
+// trigramsOf returns the padded trigrams of a token ("siti" -> " si", "sit", "iti", "ti ").
+func trigramsOf(token string) []string {
+	runes := []rune(" " + token + " ")
+	if len(runes) < 3 {
+		return nil
+	}
+
+	grams := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+3]))
+	}
+	return grams
+}
+
+func uniqueTokens(tokens []string) []string {
+	seen := make(map[string]struct{}, len(tokens))
+	out := tokens[:0]
+	for _, t := range tokens {
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out
+}
+
+func addToSet(m map[string]map[string]struct{}, key, value string) {
+	if key == "" {
+		return
+	}
+	if m[key] == nil {
+		m[key] = make(map[string]struct{})
+	}
+	m[key][value] = struct{}{}
+}
+
+func removeFromSet(m map[string]map[string]struct{}, key, value string) {
+	delete(m[key], value)
+	if len(m[key]) == 0 {
+		delete(m, key)
+	}
+}
+
+func round(score float64) float64 {
+	return float64(int(score*1000+0.5)) / 1000
+}
@@ -0,0 +1,81 @@
+package search
+
+import "testing"
+
+func newTestIndex() *Index {
+	x := NewIndex()
+	x.Upsert(Document{ID: "1", MedicalRecordNo: "RM-20240101-00001", FirstName: "Siti", LastName: "Nurhaliza"})
+	x.Upsert(Document{ID: "2", MedicalRecordNo: "RM-20240101-00002", FirstName: "Muhammad", LastName: "Rizki"})
+	x.Upsert(Document{ID: "3", MedicalRecordNo: "RM-20240101-00003", FirstName: "Siti", LastName: "Aminah"})
+	x.Upsert(Document{ID: "4", MedicalRecordNo: "RM-20240101-00004", FirstName: "Soekarno", LastName: ""})
+	return x
+}
+
+func TestSearchMatchesNameVariants(t *testing.T) {
+	x := newTestIndex()
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"Siti Nurhaliza", "1"},
+		{"Nurhaliza, Siti", "1"},
+		{"Sitti Nurhalisa", "1"},
+		{"siti nurhaliz", "1"},
+		{"Mohamad Rizky", "2"},
+		{"Sukarno", "4"},
+		{"rm-20240101-00003", "3"},
+	}
+
+	for _, tt := range tests {
+		results := x.Search(tt.query, 10)
+		if len(results) == 0 || results[0].ID != tt.want {
+			t.Errorf("Search(%q): expected top result %s, got %v", tt.query, tt.want, results)
+		}
+	}
+}
+
+func TestSearchRanksExactMatchFirst(t *testing.T) {
+	x := newTestIndex()
+	x.Upsert(Document{ID: "5", FirstName: "Sitti", LastName: "Nurhalisa"})
+
+	results := x.Search("Siti Nurhaliza", 10)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %v", results)
+	}
+	if results[0].ID != "1" || results[0].Score != 1 {
+		t.Errorf("Expected exact match first with score 1, got %v", results[0])
+	}
+	if results[1].ID != "5" || results[1].Score >= results[0].Score {
+		t.Errorf("Expected fuzzy match ranked second, got %v", results)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	x := newTestIndex()
+	x.Remove("1")
+
+	for _, r := range x.Search("Nurhaliza", 10) {
+		if r.ID == "1" {
+			t.Error("Expected removed document not to be returned")
+		}
+	}
+	if _, ok := x.postings["nurhaliza"]; ok {
+		t.Error("Expected unused token to be dropped from the vocabulary")
+	}
+}
+
+func TestPhoneticKey(t *testing.T) {
+	pairs := [][2]string{
+		{"nurhaliza", "nurhalisa"},
+		{"siti", "sitti"},
+		{"muhammad", "mohamad"},
+		{"soekarno", "sukarno"},
+		{"djoko", "joko"},
+	}
+	for _, p := range pairs {
+		if PhoneticKey(p[0]) != PhoneticKey(p[1]) {
+			t.Errorf("Expected %s and %s to share a key, got %s and %s", p[0], p[1], PhoneticKey(p[0]), PhoneticKey(p[1]))
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Name normalization and tokenization
+// internal/search/normalize.go
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// diacritics maps accented letters found in names to their base letter.
+var diacritics = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ä': 'a', 'ã': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'ö': 'o', 'õ': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+// Tokenize lowercases a name, strips punctuation and splits it into words,
+// so "Nurhaliza, Siti" and "siti nurhaliza" yield the same tokens.
+func Tokenize(s string) []string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if base, ok := diacritics[r]; ok {
+			r = base
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '\'' || r == '`':
+			// Apostrophes join the word: Ma'ruf -> maruf
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
@@ -0,0 +1,80 @@
+// Phonetic key for Indonesian names
+// internal/search/phonetic.go
+package search
+
+import "strings"
+
+// spellingVariants rewrites old (Van Ophuijsen / Soewandi) spellings and
+// common transliterations to a single form before building the key.
+var spellingVariants = strings.NewReplacer(
+	"oe", "u",
+	"dj", "j",
+	"tj", "c",
+	"sj", "sy",
+	"nj", "ny",
+	"ch", "k",
+	"kh", "k",
+	"ph", "f",
+	"th", "t",
+	"dh", "d",
+	"sy", "s",
+	"z", "s",
+	"v", "f",
+	"q", "k",
+	"x", "ks",
+)
+
+// PhoneticKey returns a coarse sound-alike key for a single token:
+// spelling variants are unified, repeated letters collapsed, a trailing or
+// post-consonant "h" dropped and vowels after the first letter removed.
+// "Nurhaliza" and "Nurhalisa" share key "nrls", as do "Siti" and "Sitti".
+func PhoneticKey(token string) string {
+	if token == "" {
+		return ""
+	}
+
+	s := spellingVariants.Replace(token)
+
+	var out []byte
+	var prev byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == prev {
+			continue
+		}
+		prev = c
+
+		if len(out) == 0 {
+			out = append(out, c)
+			continue
+		}
+
+		if isVowel(c) || c == 'y' || c == 'w' {
+			continue
+		}
+
+		// Silent h: "Muhammad"/"Muhamad"/"Mohamad" all drop it
+		if c == 'h' {
+			continue
+		}
+
+		if out[len(out)-1] != c {
+			out = append(out, c)
+		}
+	}
+
+	// Vowel-initial names often vary in the first vowel (Iskandar/Eskandar)
+	if isVowel(out[0]) {
+		out[0] = 'a'
+	}
+
+	return string(out)
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+// Keeps the index in sync with the database
+// internal/search/syncer.go
+package search
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"patient-service/internal/domain"
+)
+
+// Source streams the name fields of patients of every tenant updated at or
+// after since, including deactivated ones so they can be removed from the
+// index.
+type Source interface {
+	ScanNames(ctx context.Context, since time.Time, fn func(*domain.Patient) error) error
+}
+
+// overlap re-reads recently updated rows on every refresh so that
+// transactions committing slightly out of updated_at order are not missed.
+const overlap = time.Minute
+
+// Syncer loads one index per tenant from the database and then polls for
+// changes, so writes made by any instance of the service become searchable.
+type Syncer struct {
+	source   Source
+	interval time.Duration
+
+	mu       sync.RWMutex
+	indexes  map[string]*Index // by tenant
+	ready    bool
+	lastSeen time.Time
+}
+
+func NewSyncer(source Source, interval time.Duration) *Syncer {
+	return &Syncer{source: source, interval: interval, indexes: make(map[string]*Index)}
+}
+
+// Index returns the index of the tenant's patients.
+func (s *Syncer) Index(tenantID string) *Index {
+	s.mu.RLock()
+	index, ok := s.indexes[tenantID]
+	s.mu.RUnlock()
+	if ok {
+		return index
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index, ok = s.indexes[tenantID]; !ok {
+		index = NewIndex()
+		s.indexes[tenantID] = index
+	}
+	return index
+}
+
+// Len returns the number of indexed patients of all tenants.
+func (s *Syncer) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, index := range s.indexes {
+		n += index.Len()
+	}
+	return n
+}
+
+// Ready reports whether the initial load has completed.
+func (s *Syncer) Ready() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ready
+}
+
+// Run performs the initial load and refreshes until ctx is cancelled.
+func (s *Syncer) Run(ctx context.Context) {
+	start := time.Now()
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			log.Printf("Search index load failed, retrying: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(s.interval):
+				continue
+			}
+		}
+		break
+	}
+	log.Printf("Search index loaded: %d patients in %s", s.Len(), time.Since(start).Round(time.Millisecond))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				log.Printf("Search index refresh failed: %v", err)
+			}
+		}
+	}
+}
+
+// Refresh applies all patient changes since the previous refresh.
+func (s *Syncer) Refresh(ctx context.Context) error {
+	s.mu.RLock()
+	since := s.lastSeen
+	s.mu.RUnlock()
+
+	if !since.IsZero() {
+		since = since.Add(-overlap)
+	}
+
+	latest := since
+	err := s.source.ScanNames(ctx, since, func(p *domain.Patient) error {
+		index := s.Index(p.TenantID)
+		if p.IsActive {
+			index.Upsert(Document{
+				ID:              p.ID,
+				MedicalRecordNo: p.MedicalRecordNo,
+				FirstName:       p.FirstName,
+				LastName:        p.LastName,
+			})
+		} else {
+			index.Remove(p.ID)
+		}
+
+		if p.UpdatedAt.After(latest) {
+			latest = p.UpdatedAt
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if latest.After(s.lastSeen) {
+		s.lastSeen = latest
+	}
+	s.ready = true
+	s.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Patient change feed
+// internal/service/change_service.go
+package service
+
+import (
+	"context"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+	"patient-service/internal/tenant"
+)
+
+type changeService struct {
+	changeRepo repository.ChangeRepository
+}
+
+func NewChangeService(changeRepo repository.ChangeRepository) ChangeService {
+	return &changeService{changeRepo: changeRepo}
+}
+
+// ListChanges resumes the feed after since. Tokens are outbox positions;
+// a token older than the outbox retention returns ErrChangesExpired and
+// the caller has to reload the patients it tracks. Only changes of the
+// caller's tenant are returned, so a page may hold fewer than limit
+// changes while HasMore is set.
+func (s *changeService) ListChanges(ctx context.Context, since string, limit int) (*domain.ChangePage, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, domain.ErrTenantRequired
+	}
+
+	oldest, latest, err := s.changeRepo.Bounds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if since == "" {
+		return &domain.ChangePage{Next: domain.ChangeToken(latest)}, nil
+	}
+
+	after, err := domain.ParseChangeToken(since)
+	if err != nil || after < 0 || after > latest {
+		return nil, domain.NewCustomError("INVALID_TOKEN", "Invalid change feed token", "")
+	}
+
+	// Everything up to the oldest retained event, or to the end when the
+	// outbox is empty, may have been pruned
+	retainedFrom := latest
+	if oldest > 0 {
+		retainedFrom = oldest - 1
+	}
+	if after < retainedFrom {
+		return nil, domain.ErrChangesExpired
+	}
+
+	events, err := s.changeRepo.Since(ctx, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.ChangePage{Next: since, HasMore: len(events) > limit}
+	if page.HasMore {
+		events = events[:limit]
+	}
+	for _, event := range events {
+		change, err := domain.NewPatientChange(event)
+		if err != nil {
+			return nil, err
+		}
+		page.Next = domain.ChangeToken(change.Sequence)
+		if change.TenantID == tenantID {
+			page.Changes = append(page.Changes, change)
+		}
+	}
+
+	return page, nil
+}
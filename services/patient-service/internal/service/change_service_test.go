@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/tenant"
+)
+
+// mockChangeRepository holds events at the given positions.
+type mockChangeRepository struct {
+	events []*domain.OutboxEvent
+	latest int64
+}
+
+func newMockChangeRepository(ids ...int64) *mockChangeRepository {
+	m := &mockChangeRepository{}
+	for _, id := range ids {
+		event, _ := domain.NewOutboxEvent(domain.EventPatientUpdated, domain.PatientEventData{ID: "p1", TenantID: tenant.Default, Version: id})
+		event.ID = id
+		m.events = append(m.events, event)
+		m.latest = id
+	}
+	return m
+}
+
+func (m *mockChangeRepository) Since(ctx context.Context, after int64, limit int) ([]*domain.OutboxEvent, error) {
+	var events []*domain.OutboxEvent
+	for _, e := range m.events {
+		if e.ID > after && len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (m *mockChangeRepository) Bounds(ctx context.Context) (int64, int64, error) {
+	if len(m.events) == 0 {
+		return 0, m.latest, nil
+	}
+	return m.events[0].ID, m.latest, nil
+}
+
+func TestListChanges(t *testing.T) {
+	// Positions 1-4 were pruned
+	svc := NewChangeService(newMockChangeRepository(5, 6, 8))
+	ctx := tenant.NewContext(context.Background(), tenant.Default)
+
+	page, err := svc.ListChanges(ctx, "", 10)
+	if err != nil || len(page.Changes) != 0 || page.Next != "8" {
+		t.Fatalf("Expected an empty page at the end of the feed, got %+v, %v", page, err)
+	}
+
+	page, err = svc.ListChanges(ctx, "4", 2)
+	if err != nil {
+		t.Fatalf("ListChanges failed: %v", err)
+	}
+	if len(page.Changes) != 2 || page.Changes[0].Sequence != 5 || page.Next != "6" || !page.HasMore {
+		t.Errorf("Unexpected first page: %+v", page)
+	}
+	if page.Changes[1].Version != 6 || page.Changes[1].Operation != "updated" {
+		t.Errorf("Unexpected change: %+v", page.Changes[1])
+	}
+
+	page, err = svc.ListChanges(ctx, page.Next, 2)
+	if err != nil || len(page.Changes) != 1 || page.Next != "8" || page.HasMore {
+		t.Errorf("Unexpected last page: %+v, %v", page, err)
+	}
+
+	page, err = svc.ListChanges(ctx, "8", 2)
+	if err != nil || len(page.Changes) != 0 || page.Next != "8" {
+		t.Errorf("Expected the token back when there are no changes, got %+v, %v", page, err)
+	}
+
+	if _, err := svc.ListChanges(ctx, "3", 2); err != domain.ErrChangesExpired {
+		t.Errorf("Expected ErrChangesExpired for a pruned position, got %v", err)
+	}
+
+	for _, token := range []string{"abc", "-1", "9"} {
+		_, err := svc.ListChanges(ctx, token, 2)
+		if customErr, ok := err.(*domain.CustomError); !ok || customErr.Code != "INVALID_TOKEN" {
+			t.Errorf("Expected INVALID_TOKEN for %q, got %v", token, err)
+		}
+	}
+}
+
+func TestListChangesAfterFullPrune(t *testing.T) {
+	repo := newMockChangeRepository()
+	repo.latest = 12
+	svc := NewChangeService(repo)
+	ctx := tenant.NewContext(context.Background(), tenant.Default)
+
+	if page, err := svc.ListChanges(ctx, "12", 10); err != nil || page.Next != "12" {
+		t.Errorf("Expected the current token to stay valid, got %+v, %v", page, err)
+	}
+	if _, err := svc.ListChanges(ctx, "11", 10); err != domain.ErrChangesExpired {
+		t.Errorf("Expected ErrChangesExpired, got %v", err)
+	}
+}
+
+func TestListChangesOfTenant(t *testing.T) {
+	repo := newMockChangeRepository(1, 2, 3)
+	repo.events[1].TenantID = "north"
+	svc := NewChangeService(repo)
+
+	page, err := svc.ListChanges(tenant.NewContext(context.Background(), "north"), "0", 10)
+	if err != nil || len(page.Changes) != 1 || page.Changes[0].Sequence != 2 || page.Next != "3" {
+		t.Errorf("Expected only the tenant's change and the token past the page, got %+v, %v", page, err)
+	}
+
+	page, err = svc.ListChanges(tenant.NewContext(context.Background(), tenant.Default), "0", 2)
+	if err != nil || len(page.Changes) != 1 || page.Next != "2" || !page.HasMore {
+		t.Errorf("Expected other tenants' changes to be skipped, got %+v, %v", page, err)
+	}
+
+	if _, err := svc.ListChanges(context.Background(), "0", 10); err != domain.ErrTenantRequired {
+		t.Errorf("Expected ErrTenantRequired without a tenant, got %v", err)
+	}
+}
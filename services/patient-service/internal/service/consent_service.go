@@ -0,0 +1,130 @@
+// Patient consent management
+// internal/service/consent_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"patient-service/internal/consent"
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+)
+
+type consentService struct {
+	patientRepo repository.PatientRepository
+	repo        repository.ConsentRepository
+	checker     consent.ConsentChecker
+}
+
+func NewConsentService(patientRepo repository.PatientRepository, repo repository.ConsentRepository, checker consent.ConsentChecker) ConsentService {
+	return &consentService{patientRepo: patientRepo, repo: repo, checker: checker}
+}
+
+func (s *consentService) CreateConsent(ctx context.Context, c *domain.Consent) (*domain.Consent, error) {
+	if err := checkPatient(ctx, s.patientRepo, c.PatientID); err != nil {
+		return nil, err
+	}
+
+	if c.Decision == "" {
+		c.Decision = domain.ConsentPermit
+	}
+	if c.GrantorRelationship == "" {
+		c.GrantorRelationship = "self"
+	}
+	if c.ValidFrom.IsZero() {
+		c.ValidFrom = time.Now()
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to create consent: %w", err)
+	}
+	return c, nil
+}
+
+func (s *consentService) ListConsents(ctx context.Context, patientID string) ([]*domain.Consent, error) {
+	if err := checkPatient(ctx, s.patientRepo, patientID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByPatient(ctx, patientID)
+}
+
+func (s *consentService) GetConsent(ctx context.Context, patientID, id string) (*domain.Consent, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := checkPatient(ctx, s.patientRepo, patientID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, patientID, id)
+}
+
+// RevokeConsent ends the consent from now on. Data released while it was
+// in effect is not recalled.
+func (s *consentService) RevokeConsent(ctx context.Context, patientID, id, revokedBy, reason string) (*domain.Consent, error) {
+	c, err := s.GetConsent(ctx, patientID, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.RevokedAt != nil {
+		return nil, domain.ErrConsentRevoked
+	}
+
+	c.RevokedBy = revokedBy
+	c.RevocationReason = reason
+	if err := s.repo.Revoke(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *consentService) ConsentStatus(ctx context.Context, patientID string) (map[string]bool, error) {
+	if err := checkPatient(ctx, s.patientRepo, patientID); err != nil {
+		return nil, err
+	}
+
+	status := make(map[string]bool, len(domain.ConsentTypes))
+	for _, consentType := range domain.ConsentTypes {
+		allowed, err := s.checker.Allowed(ctx, patientID, consentType)
+		if err != nil {
+			return nil, err
+		}
+		status[consentType] = allowed
+	}
+	return status, nil
+}
+
+// checkLiving returns ErrPatientNotFound unless the patient exists and
+// ErrPatientDeceased if their death is recorded.
+func checkLiving(ctx context.Context, patientRepo repository.PatientRepository, patientID string) error {
+	if patientID == "" {
+		return domain.ErrInvalidInput
+	}
+	patient, err := patientRepo.GetByIDFields(ctx, patientID, []string{"deceased_at"})
+	if err != nil {
+		return err
+	}
+	if patient.IsDeceased() {
+		return domain.ErrPatientDeceased
+	}
+	return nil
+}
+
+// checkPatient returns ErrPatientNotFound unless the patient exists.
+func checkPatient(ctx context.Context, patientRepo repository.PatientRepository, patientID string) error {
+	if patientID == "" {
+		return domain.ErrInvalidInput
+	}
+	exists, err := patientRepo.Exists(ctx, patientID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrPatientNotFound
+	}
+	return nil
+}
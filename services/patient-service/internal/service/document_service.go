@@ -0,0 +1,144 @@
+// Patient documents and photos
+// internal/service/document_service.go
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"patient-service/internal/document"
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+	"patient-service/pkg/blobstore"
+)
+
+type documentService struct {
+	patientRepo repository.PatientRepository
+	repo        repository.DocumentRepository
+	blobs       blobstore.BlobStore
+	scanner     document.Scanner
+	signer      *document.URLSigner
+}
+
+// NewDocumentService creates the service; scanner may be nil, in which
+// case uploads are stored unscanned.
+func NewDocumentService(patientRepo repository.PatientRepository, repo repository.DocumentRepository, blobs blobstore.BlobStore, scanner document.Scanner, signer *document.URLSigner) DocumentService {
+	return &documentService{patientRepo: patientRepo, repo: repo, blobs: blobs, scanner: scanner, signer: signer}
+}
+
+func (s *documentService) UploadDocument(ctx context.Context, doc *domain.Document, content io.ReadSeeker) (*domain.Document, error) {
+	if err := checkPatient(ctx, s.patientRepo, doc.PatientID); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, document.SniffLen)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, domain.NewCustomError("EMPTY_FILE", "File is empty", "")
+	}
+	doc.ContentType = document.Sniff(head[:n])
+	if !document.Accepts(doc.Type, doc.ContentType) {
+		return nil, domain.ErrUnsupportedMediaType
+	}
+
+	doc.ScanStatus = domain.ScanNotScanned
+	if s.scanner != nil {
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		err := s.scanner.Scan(ctx, content)
+		var infected *document.Infected
+		if errors.As(err, &infected) {
+			return nil, domain.ErrDocumentInfected
+		}
+		if err != nil {
+			return nil, fmt.Errorf("virus scan failed: %w", err)
+		}
+		doc.ScanStatus = domain.ScanClean
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	doc.ID = uuid.New().String()
+	doc.StorageKey = "patients/" + doc.PatientID + "/" + doc.ID
+	hash := sha256.New()
+	if err := s.blobs.Put(ctx, doc.StorageKey, io.TeeReader(content, hash), doc.Size, doc.ContentType); err != nil {
+		return nil, fmt.Errorf("failed to store document: %w", err)
+	}
+	doc.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	if err := s.repo.Create(ctx, doc); err != nil {
+		s.blobs.Delete(ctx, doc.StorageKey)
+		return nil, fmt.Errorf("failed to create document: %w", err)
+	}
+	return doc, nil
+}
+
+func (s *documentService) ListDocuments(ctx context.Context, patientID string) ([]*domain.Document, error) {
+	if err := checkPatient(ctx, s.patientRepo, patientID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByPatient(ctx, patientID)
+}
+
+func (s *documentService) GetDocument(ctx context.Context, patientID, id string) (*domain.Document, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := checkPatient(ctx, s.patientRepo, patientID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, patientID, id)
+}
+
+// DeleteDocument removes the record first, so a document whose content
+// could not be deleted is no longer listed.
+func (s *documentService) DeleteDocument(ctx context.Context, patientID, id string) error {
+	doc, err := s.GetDocument(ctx, patientID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, patientID, id); err != nil {
+		return err
+	}
+	if err := s.blobs.Delete(ctx, doc.StorageKey); err != nil {
+		return fmt.Errorf("document deleted, but not its content: %w", err)
+	}
+	return nil
+}
+
+func (s *documentService) SignDownload(doc *domain.Document) (int64, string) {
+	return s.signer.Sign(doc.PatientID, doc.ID, time.Now())
+}
+
+func (s *documentService) OpenDocument(ctx context.Context, patientID, id string, expires int64, signature string) (*domain.Document, io.ReadCloser, error) {
+	if !s.signer.Verify(patientID, id, expires, signature, time.Now()) {
+		return nil, nil, domain.ErrInvalidSignature
+	}
+
+	// Signed links work without a token, so without a tenant; the
+	// signature already binds the document to its patient
+	doc, err := s.repo.GetByID(ctx, patientID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, _, err := s.blobs.Get(ctx, doc.StorageKey)
+	if err == blobstore.ErrNotFound {
+		return nil, nil, domain.ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, content, nil
+}
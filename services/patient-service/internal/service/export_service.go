@@ -0,0 +1,107 @@
+// Patient exports
+// internal/service/export_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"patient-service/internal/consent"
+	"patient-service/internal/deident"
+	"patient-service/internal/domain"
+	"patient-service/internal/export"
+	"patient-service/internal/repository"
+	"patient-service/pkg/filestore"
+)
+
+type exportService struct {
+	patientRepo repository.PatientRepository
+	repo        repository.ExportRepository
+	store       filestore.Store
+	worker      *export.Worker
+	consents    consent.ConsentChecker
+	deident     deident.Config
+}
+
+// NewExportService creates the service; worker, when set, is woken when a
+// job is queued.
+func NewExportService(patientRepo repository.PatientRepository, repo repository.ExportRepository, store filestore.Store, worker *export.Worker, consents consent.ConsentChecker, deidentCfg deident.Config) ExportService {
+	return &exportService{patientRepo: patientRepo, repo: repo, store: store, worker: worker, consents: consents, deident: deidentCfg}
+}
+
+func (s *exportService) ExportPatients(ctx context.Context, filter domain.PatientFilter, consentType string, fn func(*domain.Patient) error) error {
+	if consentType == "" {
+		return s.patientRepo.Scan(ctx, filter, fn)
+	}
+	add, flush := consent.Filter(ctx, s.consents, consentType, fn)
+	if err := s.patientRepo.Scan(ctx, filter, add); err != nil {
+		return err
+	}
+	return flush()
+}
+
+func (s *exportService) StartExport(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+	if len(job.Filter.Fields) == 0 {
+		return nil, domain.NewCustomError("INVALID_FIELDS", "No exportable fields selected", "")
+	}
+	return s.queue(ctx, job)
+}
+
+func (s *exportService) StartDeidentifiedExport(ctx context.Context, job *domain.ExportJob, rules domain.DeidentRuleset) (*domain.ExportJob, error) {
+	ruleset, err := s.deident.Ruleset(rules, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	job.Ruleset = ruleset
+	job.ConsentType = domain.ConsentResearch
+	job.Filter.Fields = deident.Columns(*ruleset)
+	return s.queue(ctx, job)
+}
+
+func (s *exportService) queue(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+	job.Filter.Cursor = nil
+
+	if err := s.repo.CreateJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+	if s.worker != nil {
+		s.worker.Wake()
+	}
+	return job, nil
+}
+
+func (s *exportService) GetExport(ctx context.Context, id, userID string) (*domain.ExportJob, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	job, err := s.repo.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// The file holds what the requester's role may read
+	if job.CreatedBy != userID {
+		return nil, domain.ErrExportNotFound
+	}
+	return job, nil
+}
+
+func (s *exportService) OpenExport(ctx context.Context, id, userID string) (*domain.ExportJob, io.ReadCloser, error) {
+	job, err := s.GetExport(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != domain.ExportCompleted {
+		return nil, nil, domain.ErrExportNotReady
+	}
+
+	file, _, err := s.store.Open(ctx, job.FileName())
+	if err == filestore.ErrNotFound {
+		return nil, nil, domain.ErrExportNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, file, nil
+}
@@ -0,0 +1,91 @@
+// Bulk patient import
+// internal/service/import_service.go
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/importer"
+	"patient-service/internal/repository"
+)
+
+type importService struct {
+	repo   repository.ImportRepository
+	worker *importer.Worker
+}
+
+// NewImportService creates the service; worker, when set, is woken when a
+// job is queued.
+func NewImportService(repo repository.ImportRepository, worker *importer.Worker) ImportService {
+	return &importService{repo: repo, worker: worker}
+}
+
+func (s *importService) StartImport(ctx context.Context, job *domain.ImportJob, content []byte) (*domain.ImportJob, error) {
+	job.Format = importer.FormatOf(job.FileName)
+	if job.Format == "" {
+		return nil, domain.NewCustomError("INVALID_FORMAT", "File must be a CSV or XLSX file", job.FileName)
+	}
+
+	// Reject unreadable files and bad mappings now rather than in the job
+	src, err := importer.Parse(job.Format, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(src.Rows) == 0 {
+		return nil, domain.NewCustomError("EMPTY_FILE", "File has no data rows", "")
+	}
+
+	mapping, err := importer.ResolveMapping(src.Header, job.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	job.Mapping = mapping
+	job.Columns = src.Header
+	job.TotalRows = len(src.Rows)
+
+	if err := s.repo.CreateJob(ctx, job, content); err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+	if s.worker != nil {
+		s.worker.Wake()
+	}
+	return job, nil
+}
+
+func (s *importService) GetImport(ctx context.Context, id string) (*domain.ImportJob, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.GetJob(ctx, id)
+}
+
+func (s *importService) WriteErrorReport(ctx context.Context, id string, w io.Writer) error {
+	job, err := s.GetImport(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	out := csv.NewWriter(w)
+	header := append(append([]string{}, job.Columns...), "error_row", "error_code", "error_field", "error_message")
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	err = s.repo.ListRowErrors(ctx, job.ID, func(rowErr *domain.ImportRowError) error {
+		record := make([]string, len(job.Columns), len(header))
+		copy(record, rowErr.Values)
+		record = append(record, strconv.Itoa(rowErr.Row), rowErr.Code, rowErr.Field, rowErr.Message)
+		return out.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	out.Flush()
+	return out.Error()
+}
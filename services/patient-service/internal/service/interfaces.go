@@ -4,15 +4,139 @@ package service
 
 import (
 	"context"
+	"io"
 	"patient-service/internal/domain"
+	"time"
 )
 
 type PatientService interface {
 	CreatePatient(ctx context.Context, patient *domain.Patient) (*domain.Patient, error)
 	GetPatient(ctx context.Context, id string) (*domain.Patient, error)
+	GetPatientFields(ctx context.Context, id string, fields []string) (*domain.Patient, error)
 	GetPatientByNIK(ctx context.Context, nik string) (*domain.Patient, error)
+	GetPatientByMedicalRecordNo(ctx context.Context, mrNo string) (*domain.Patient, error)
+
+	// GetPatientLinks returns the patient's active records at other
+	// tenants, which share its enterprise ID
+	GetPatientLinks(ctx context.Context, id string) ([]*domain.Patient, error)
+	GetPatientsByKeys(ctx context.Context, key domain.PatientKey, values []string, fields []string) ([]*domain.Patient, error)
 	UpdatePatient(ctx context.Context, patient *domain.Patient) (*domain.Patient, error)
+	PatchPatient(ctx context.Context, id string, patch domain.PatientPatch) (*domain.Patient, error)
 	DeletePatient(ctx context.Context, id string) error
-	ListPatients(ctx context.Context, filter domain.PatientFilter) ([]*domain.Patient, int, error)
-	GetPatientPublicInfo(ctx context.Context, id string) (*domain.Patient, error)
+	MergePatients(ctx context.Context, survivorID, mergedID, mergedBy string) (*domain.Patient, error)
+
+	// RecordDeath marks the patient deceased. Deceased patients stay
+	// readable and editable but cannot be registered again or issued
+	// wristbands and public profile tokens
+	RecordDeath(ctx context.Context, id string, deceasedAt time.Time, cause, recordedBy string) (*domain.Patient, error)
+
+	// ClearDeath removes a death recorded in error
+	ClearDeath(ctx context.Context, id, clearedBy string) (*domain.Patient, error)
+	ListPatients(ctx context.Context, filter domain.PatientFilter) (*domain.PatientPage, error)
+}
+
+type PatientSearchService interface {
+	SearchPatients(ctx context.Context, query string, limit int) ([]*domain.PatientMatch, error)
+}
+
+type ChangeService interface {
+	// ListChanges returns up to limit changes after the since token. An
+	// empty token returns no changes and the token of the current end of
+	// the feed.
+	ListChanges(ctx context.Context, since string, limit int) (*domain.ChangePage, error)
+}
+
+type WebhookService interface {
+	// CreateWebhook stores the subscription with a generated signing
+	// secret, which is returned only here and by RotateWebhookSecret
+	CreateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	GetWebhook(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	ListWebhooks(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	UpdateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	RotateWebhookSecret(ctx context.Context, id, updatedBy string) (*domain.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, filter domain.DeliveryFilter) ([]*domain.WebhookDelivery, int, error)
+	GetDelivery(ctx context.Context, subscriptionID, id string) (*domain.WebhookDelivery, error)
+	ReplayDelivery(ctx context.Context, subscriptionID, id string) (*domain.WebhookDelivery, error)
+}
+
+type ImportService interface {
+	// StartImport checks the file and the column mapping and queues the
+	// job; rows are validated and imported in the background
+	StartImport(ctx context.Context, job *domain.ImportJob, content []byte) (*domain.ImportJob, error)
+	GetImport(ctx context.Context, id string) (*domain.ImportJob, error)
+
+	// WriteErrorReport writes the rows rejected so far as CSV: the uploaded
+	// columns followed by the row number and the problem found
+	WriteErrorReport(ctx context.Context, id string, w io.Writer) error
+}
+
+type ExportService interface {
+	// ExportPatients calls fn for every patient matching the filter,
+	// streamed from the database; with a consent type, only for those
+	// whose consent allows it
+	ExportPatients(ctx context.Context, filter domain.PatientFilter, consentType string, fn func(*domain.Patient) error) error
+
+	// StartExport queues an export written to the file store in the
+	// background
+	StartExport(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error)
+
+	// StartDeidentifiedExport queues a de-identified export of the job's
+	// filter, limited to patients who consent to research; rules override
+	// the configured ruleset defaults
+	StartDeidentifiedExport(ctx context.Context, job *domain.ExportJob, rules domain.DeidentRuleset) (*domain.ExportJob, error)
+
+	// GetExport and OpenExport only return jobs of the user who started them
+	GetExport(ctx context.Context, id, userID string) (*domain.ExportJob, error)
+	OpenExport(ctx context.Context, id, userID string) (*domain.ExportJob, io.ReadCloser, error)
+}
+
+type ConsentService interface {
+	// CreateConsent records a consent for the patient. A consent does not
+	// replace earlier ones; the one valid from the latest date applies.
+	CreateConsent(ctx context.Context, consent *domain.Consent) (*domain.Consent, error)
+	ListConsents(ctx context.Context, patientID string) ([]*domain.Consent, error)
+	GetConsent(ctx context.Context, patientID, id string) (*domain.Consent, error)
+	RevokeConsent(ctx context.Context, patientID, id, revokedBy, reason string) (*domain.Consent, error)
+
+	// ConsentStatus returns, per consent type, whether the patient's data
+	// may currently be released for it
+	ConsentStatus(ctx context.Context, patientID string) (map[string]bool, error)
+}
+
+type DocumentService interface {
+	// UploadDocument checks the content's type, scans it for malware and
+	// stores it, then records the document
+	UploadDocument(ctx context.Context, doc *domain.Document, content io.ReadSeeker) (*domain.Document, error)
+	ListDocuments(ctx context.Context, patientID string) ([]*domain.Document, error)
+	GetDocument(ctx context.Context, patientID, id string) (*domain.Document, error)
+	DeleteDocument(ctx context.Context, patientID, id string) error
+
+	// SignDownload returns the expiry and signature of a short-lived
+	// download link; OpenDocument only opens documents with a valid link
+	SignDownload(doc *domain.Document) (int64, string)
+	OpenDocument(ctx context.Context, patientID, id string, expires int64, signature string) (*domain.Document, io.ReadCloser, error)
+}
+
+type LabelService interface {
+	// RenderLabel renders the patient's card or wristband in the format
+	// with the named template, the default one when name is empty
+	RenderLabel(ctx context.Context, patientID, kind, templateName, format string) ([]byte, error)
+}
+
+type QRTokenService interface {
+	// IssueToken creates a public profile token for the patient, valid for
+	// ttl or the configured default when zero, and returns its token string
+	IssueToken(ctx context.Context, patientID string, ttl time.Duration, createdBy string) (*domain.QRToken, string, error)
+	ListTokens(ctx context.Context, patientID string) ([]*domain.QRToken, error)
+	RevokeToken(ctx context.Context, patientID, id, revokedBy string) (*domain.QRToken, error)
+	ListScans(ctx context.Context, patientID, id string) ([]*domain.QRScan, error)
+
+	// TokenString returns the signed token string of an issued token
+	TokenString(token *domain.QRToken) string
+
+	// ResolveToken returns the minimal profile of the token's patient. Each
+	// call is audited with the result and the scan's IP and user agent;
+	// invalid, expired and revoked tokens all return ErrInvalidQRToken
+	ResolveToken(ctx context.Context, token string, scan *domain.QRScan) (*domain.Patient, error)
 }
@@ -0,0 +1,66 @@
+// Patient card and wristband printing
+// internal/service/label_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/label"
+	"patient-service/internal/repository"
+	"patient-service/internal/tenant"
+)
+
+// labelFields are the patient fields read for printing; contact and
+// insurance details are not decrypted.
+var labelFields = []string{"id", "medical_record_no", "first_name", "last_name", "date_of_birth", "gender", "blood_type", "deceased_at"}
+
+type labelService struct {
+	patientRepo repository.PatientRepository
+	templates   label.Templates
+	tenants     *tenant.Registry
+}
+
+// NewLabelService creates the service; labels of tenants with a name print
+// it instead of the template's hospital name.
+func NewLabelService(patientRepo repository.PatientRepository, templates label.Templates, tenants *tenant.Registry) LabelService {
+	return &labelService{patientRepo: patientRepo, templates: templates, tenants: tenants}
+}
+
+func (s *labelService) RenderLabel(ctx context.Context, patientID, kind, templateName, format string) ([]byte, error) {
+	if patientID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if !slices.Contains(label.Formats[kind], format) {
+		return nil, domain.NewCustomError("INVALID_FORMAT", "Format must be one of: "+strings.Join(label.Formats[kind], ", "), "")
+	}
+	if templateName == "" {
+		templateName = label.DefaultTemplate
+	}
+	template, ok := s.templates[kind][templateName]
+	if !ok {
+		return nil, domain.NewCustomError("INVALID_TEMPLATE", fmt.Sprintf("Unknown %s template %q", kind, templateName), "")
+	}
+
+	patient, err := s.patientRepo.GetByIDFields(ctx, patientID, labelFields)
+	if err != nil {
+		return nil, err
+	}
+	// Cards and wristbands are issued on registration and admission
+	if patient.IsDeceased() {
+		return nil, domain.ErrPatientDeceased
+	}
+	if id, ok := tenant.FromContext(ctx); ok {
+		if t, ok := s.tenants.Get(id); ok && t.Name != "" {
+			template.HospitalName = t.Name
+		}
+	}
+	layout, err := label.NewLayout(template, patient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lay out %s: %w", kind, err)
+	}
+	return label.Render(layout, format)
+}
@@ -10,33 +10,41 @@ import (
 
 	"patient-service/internal/domain"
 	"patient-service/internal/repository"
+	"patient-service/internal/tenant"
 )
 
 type patientService struct {
 	patientRepo repository.PatientRepository
+	tenants     *tenant.Registry
 }
 
-func NewPatientService(patientRepo repository.PatientRepository) PatientService {
+// NewPatientService creates the service; medical record numbers are
+// generated with the prefix of the caller's tenant in tenants.
+func NewPatientService(patientRepo repository.PatientRepository, tenants *tenant.Registry) PatientService {
 	return &patientService{
 		patientRepo: patientRepo,
+		tenants:     tenants,
 	}
 }
 
 func (s *patientService) CreatePatient(ctx context.Context, patient *domain.Patient) (*domain.Patient, error) {
 	// Validate required fields
-	if err := s.validatePatient(patient); err != nil {
+	if err := patient.Validate(); err != nil {
 		return nil, err
 	}
 
 	// Check if patient with NIK already exists
 	existingPatient, _ := s.patientRepo.GetByNIK(ctx, patient.NIK)
+	if existingPatient != nil && existingPatient.IsDeceased() {
+		return nil, domain.NewCustomError("PATIENT_DECEASED", "Patient with this NIK is recorded as deceased", existingPatient.MedicalRecordNo)
+	}
 	if existingPatient != nil {
 		return nil, domain.NewCustomError("PATIENT_EXISTS", "Patient with this NIK already exists", "")
 	}
 
 	// Generate medical record number if not provided
 	if patient.MedicalRecordNo == "" {
-		patient.MedicalRecordNo = s.generateMedicalRecordNo()
+		patient.MedicalRecordNo = s.generateMedicalRecordNo(ctx)
 	}
 
 	// Set default values
@@ -66,6 +74,15 @@ func (s *patientService) GetPatient(ctx context.Context, id string) (*domain.Pat
 	return patient, nil
 }
 
+// GetPatientFields loads only the given fields; nil loads all of them.
+func (s *patientService) GetPatientFields(ctx context.Context, id string, fields []string) (*domain.Patient, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return s.patientRepo.GetByIDFields(ctx, id, fields)
+}
+
 func (s *patientService) GetPatientByNIK(ctx context.Context, nik string) (*domain.Patient, error) {
 	if nik == "" {
 		return nil, domain.ErrInvalidInput
@@ -79,6 +96,43 @@ func (s *patientService) GetPatientByNIK(ctx context.Context, nik string) (*doma
 	return patient, nil
 }
 
+func (s *patientService) GetPatientByMedicalRecordNo(ctx context.Context, mrNo string) (*domain.Patient, error) {
+	if mrNo == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return s.patientRepo.GetByMedicalRecordNo(ctx, mrNo)
+}
+
+// GetPatientLinks returns the patient's records at other tenants.
+func (s *patientService) GetPatientLinks(ctx context.Context, id string) ([]*domain.Patient, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	exists, err := s.patientRepo.Exists(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	return s.patientRepo.GetLinks(ctx, id)
+}
+
+// GetPatientsByKeys loads the active patients matching values of key in
+// one query; values that are not found are left out.
+func (s *patientService) GetPatientsByKeys(ctx context.Context, key domain.PatientKey, values []string, fields []string) ([]*domain.Patient, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if len(values) > domain.MaxBatchGet {
+		return nil, domain.NewCustomError("TOO_MANY_KEYS", fmt.Sprintf("At most %d keys per request", domain.MaxBatchGet), "")
+	}
+	return s.patientRepo.GetByKeys(ctx, key, values, fields)
+}
+
 func (s *patientService) UpdatePatient(ctx context.Context, patient *domain.Patient) (*domain.Patient, error) {
 	if patient.ID == "" {
 		return nil, domain.ErrInvalidInput
@@ -90,8 +144,17 @@ func (s *patientService) UpdatePatient(ctx context.Context, patient *domain.Pati
 		return nil, err
 	}
 
+	// The medical record number is assigned once and cannot be replaced;
+	// a recorded death is only changed through RecordDeath and ClearDeath
+	patient.TenantID = existing.TenantID
+	patient.EnterpriseID = existing.EnterpriseID
+	patient.MedicalRecordNo = existing.MedicalRecordNo
+	patient.DeceasedAt = existing.DeceasedAt
+	patient.CauseOfDeath = existing.CauseOfDeath
+	patient.DeathRecordedBy = existing.DeathRecordedBy
+
 	// Validate update data
-	if err := s.validatePatient(patient); err != nil {
+	if err := patient.Validate(); err != nil {
 		return nil, err
 	}
 
@@ -112,6 +175,59 @@ func (s *patientService) UpdatePatient(ctx context.Context, patient *domain.Pati
 	return s.patientRepo.GetByID(ctx, patient.ID)
 }
 
+// PatchPatient applies a partial update to the stored patient, validates the
+// merged result and writes only the fields that changed.
+func (s *patientService) PatchPatient(ctx context.Context, id string, patch domain.PatientPatch) (*domain.Patient, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	existing, err := s.patientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	patched := *existing
+	if err := patch(&patched); err != nil {
+		return nil, err
+	}
+
+	// Identity and bookkeeping fields are not patchable; a recorded death is
+	// only changed through RecordDeath and ClearDeath
+	patched.ID = existing.ID
+	patched.TenantID = existing.TenantID
+	patched.EnterpriseID = existing.EnterpriseID
+	patched.MedicalRecordNo = existing.MedicalRecordNo
+	patched.IsActive = existing.IsActive
+	patched.CreatedAt = existing.CreatedAt
+	patched.CreatedBy = existing.CreatedBy
+	patched.DeceasedAt = existing.DeceasedAt
+	patched.CauseOfDeath = existing.CauseOfDeath
+	patched.DeathRecordedBy = existing.DeathRecordedBy
+
+	if err := patched.Validate(); err != nil {
+		return nil, err
+	}
+
+	changed := domain.ChangedFields(existing, &patched)
+	if len(changed) == 0 {
+		return existing, nil
+	}
+
+	if patched.NIK != existing.NIK {
+		existingWithNIK, _ := s.patientRepo.GetByNIK(ctx, patched.NIK)
+		if existingWithNIK != nil && existingWithNIK.ID != id {
+			return nil, domain.NewCustomError("NIK_EXISTS", "NIK already used by another patient", "")
+		}
+	}
+
+	if err := s.patientRepo.UpdateFields(ctx, &patched, changed); err != nil {
+		return nil, fmt.Errorf("failed to update patient: %w", err)
+	}
+
+	return s.patientRepo.GetByID(ctx, id)
+}
+
 func (s *patientService) DeletePatient(ctx context.Context, id string) error {
 	if id == "" {
 		return domain.ErrInvalidInput
@@ -131,49 +247,53 @@ func (s *patientService) DeletePatient(ctx context.Context, id string) error {
 	return s.patientRepo.Delete(ctx, id)
 }
 
-func (s *patientService) ListPatients(ctx context.Context, filter domain.PatientFilter) ([]*domain.Patient, int, error) {
-	// Set default pagination
-	if filter.Page <= 0 {
-		filter.Page = 1
-	}
-	if filter.Limit <= 0 {
-		filter.Limit = 10
+// MergePatients retires a duplicate record in favour of the survivor and
+// returns the survivor.
+func (s *patientService) MergePatients(ctx context.Context, survivorID, mergedID, mergedBy string) (*domain.Patient, error) {
+	if survivorID == "" || mergedID == "" {
+		return nil, domain.ErrInvalidInput
 	}
-	if filter.Limit > 100 {
-		filter.Limit = 100 // Max limit
+	if survivorID == mergedID {
+		return nil, domain.NewCustomError("INVALID_MERGE", "A patient cannot be merged into itself", "")
 	}
 
-	// Set default sorting
-	if filter.Sort == "" {
-		filter.Sort = "created_at"
+	survivor, err := s.patientRepo.GetByID(ctx, survivorID)
+	if err != nil {
+		return nil, err
 	}
-	if filter.Order == "" {
-		filter.Order = "DESC"
+
+	if err := s.patientRepo.Merge(ctx, survivorID, mergedID, mergedBy); err != nil {
+		return nil, err
 	}
 
-	// Validate sort field to prevent SQL injection
-	allowedSortFields := map[string]bool{
-		"created_at": true,
-		"updated_at": true,
-		"first_name": true,
-		"last_name":  true,
-		"nik":        true,
+	return survivor, nil
+}
+
+func (s *patientService) RecordDeath(ctx context.Context, id string, deceasedAt time.Time, cause, recordedBy string) (*domain.Patient, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
 	}
 
-	if !allowedSortFields[filter.Sort] {
-		filter.Sort = "created_at"
+	patient, err := s.patientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if patient.IsDeceased() {
+		return nil, domain.ErrPatientDeceased
 	}
 
-	// Validate order
-	filter.Order = strings.ToUpper(filter.Order)
-	if filter.Order != "ASC" && filter.Order != "DESC" {
-		filter.Order = "DESC"
+	if err := patient.RecordDeath(deceasedAt, cause, recordedBy); err != nil {
+		return nil, err
 	}
+	patient.UpdatedBy = recordedBy
 
-	return s.patientRepo.List(ctx, filter)
+	if err := s.patientRepo.RecordDeath(ctx, patient); err != nil {
+		return nil, err
+	}
+	return s.patientRepo.GetByID(ctx, id)
 }
 
-func (s *patientService) GetPatientPublicInfo(ctx context.Context, id string) (*domain.Patient, error) {
+func (s *patientService) ClearDeath(ctx context.Context, id, clearedBy string) (*domain.Patient, error) {
 	if id == "" {
 		return nil, domain.ErrInvalidInput
 	}
@@ -182,67 +302,93 @@ func (s *patientService) GetPatientPublicInfo(ctx context.Context, id string) (*
 	if err != nil {
 		return nil, err
 	}
-
-	// Return only public information
-	publicInfo := &domain.Patient{
-		ID:              patient.ID,
-		MedicalRecordNo: patient.MedicalRecordNo,
-		FirstName:       patient.FirstName,
-		LastName:        patient.LastName,
-		Gender:          patient.Gender,
-		// Hide sensitive information
+	if !patient.IsDeceased() {
+		return nil, domain.ErrPatientNotDeceased
 	}
 
-	return publicInfo, nil
-}
-
-// Helper methods
+	patient.DeceasedAt = nil
+	patient.CauseOfDeath = ""
+	patient.DeathRecordedBy = ""
+	patient.UpdatedBy = clearedBy
 
-func (s *patientService) validatePatient(patient *domain.Patient) error {
-	if patient.NIK == "" || len(patient.NIK) != 16 {
-		return domain.NewCustomError("INVALID_NIK", "NIK must be 16 characters", "")
+	if err := s.patientRepo.ClearDeath(ctx, patient); err != nil {
+		return nil, err
 	}
+	return s.patientRepo.GetByID(ctx, id)
+}
 
-	if patient.FirstName == "" {
-		return domain.NewCustomError("INVALID_NAME", "First name is required", "")
+func (s *patientService) ListPatients(ctx context.Context, filter domain.PatientFilter) (*domain.PatientPage, error) {
+	// Set default pagination
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100 // Max limit
 	}
 
-	if patient.DateOfBirth.IsZero() {
-		return domain.NewCustomError("INVALID_DOB", "Date of birth is required", "")
+	// A cursor carries the sort it was created with
+	if filter.Cursor != nil {
+		filter.Sort = filter.Cursor.Sort
+		filter.Order = filter.Cursor.Order
 	}
 
-	if patient.DateOfBirth.After(time.Now()) {
-		return domain.NewCustomError("INVALID_DOB", "Date of birth cannot be in the future", "")
+	// Set default sorting
+	if filter.Sort == "" {
+		filter.Sort = "created_at"
+	}
+	if filter.Order == "" {
+		filter.Order = "DESC"
 	}
 
-	if patient.Gender != "MALE" && patient.Gender != "FEMALE" {
-		return domain.NewCustomError("INVALID_GENDER", "Gender must be MALE or FEMALE", "")
+	// A date of death range only matches deceased patients
+	if filter.Deceased != nil && !*filter.Deceased && (filter.DeceasedFrom != nil || filter.DeceasedTo != nil) {
+		return nil, domain.NewCustomError("INVALID_FILTER", "deceased=false cannot be combined with a date of death range", "")
 	}
 
-	if patient.Phone == "" {
-		return domain.NewCustomError("INVALID_PHONE", "Phone number is required", "")
+	// Validate sort field to prevent SQL injection.
+	// NIK is encrypted at rest and therefore cannot be sorted on.
+	allowedSortFields := map[string]bool{
+		"created_at": true,
+		"updated_at": true,
+		"first_name": true,
+		"last_name":  true,
 	}
 
-	// Validate blood type if provided
-	if patient.BloodType != "" {
-		validBloodTypes := map[string]bool{
-			"A+": true, "A-": true,
-			"B+": true, "B-": true,
-			"AB+": true, "AB-": true,
-			"O+": true, "O-": true,
+	if !allowedSortFields[filter.Sort] {
+		if filter.Cursor != nil {
+			return nil, domain.NewCustomError("INVALID_CURSOR", "Invalid pagination cursor", "")
 		}
+		filter.Sort = "created_at"
+	}
 
-		if !validBloodTypes[patient.BloodType] {
-			return domain.NewCustomError("INVALID_BLOOD_TYPE", "Invalid blood type", "")
+	// Validate order
+	filter.Order = strings.ToUpper(filter.Order)
+	if filter.Order != "ASC" && filter.Order != "DESC" {
+		if filter.Cursor != nil {
+			return nil, domain.NewCustomError("INVALID_CURSOR", "Invalid pagination cursor", "")
 		}
+		filter.Order = "DESC"
 	}
 
-	return nil
+	page, err := s.patientRepo.List(ctx, filter)
+	if err == domain.ErrInvalidCursor {
+		return nil, domain.NewCustomError("INVALID_CURSOR", "Invalid pagination cursor", "")
+	}
+	return page, err
 }
 
-func (s *patientService) generateMedicalRecordNo() string {
-	// Generate medical record number with format: RM-YYYYMMDD-XXXXX
-	timestamp := time.Now().Format("20060102")
-	random := time.Now().UnixNano() % 100000
-	return fmt.Sprintf("RM-%s-%05d", timestamp, random)
+// Helper methods
+
+// generateMedicalRecordNo uses the prefix of the caller's tenant.
+func (s *patientService) generateMedicalRecordNo(ctx context.Context) string {
+	var prefix string
+	if id, ok := tenant.FromContext(ctx); ok {
+		if t, ok := s.tenants.Get(id); ok {
+			prefix = t.MRNPrefix
+		}
+	}
+	return domain.NewMedicalRecordNo(prefix)
 }
@@ -3,11 +3,15 @@ package service
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"patient-service/internal/domain"
+	"patient-service/internal/dto"
 	"patient-service/internal/repository"
+	"patient-service/internal/search"
+	"patient-service/internal/tenant"
 )
 
 // MockPatientRepository for testing
@@ -26,6 +30,13 @@ func (m *mockPatientRepository) Create(ctx context.Context, patient *domain.Pati
 	return nil
 }
 
+func (m *mockPatientRepository) CreateBatch(ctx context.Context, patients []*domain.Patient) error {
+	for _, patient := range patients {
+		m.patients[patient.ID] = patient
+	}
+	return nil
+}
+
 func (m *mockPatientRepository) GetByID(ctx context.Context, id string) (*domain.Patient, error) {
 	patient, exists := m.patients[id]
 	if !exists {
@@ -34,6 +45,10 @@ func (m *mockPatientRepository) GetByID(ctx context.Context, id string) (*domain
 	return patient, nil
 }
 
+func (m *mockPatientRepository) GetByIDFields(ctx context.Context, id string, fields []string) (*domain.Patient, error) {
+	return m.GetByID(ctx, id)
+}
+
 func (m *mockPatientRepository) GetByNIK(ctx context.Context, nik string) (*domain.Patient, error) {
 	for _, patient := range m.patients {
 		if patient.NIK == nik {
@@ -52,6 +67,21 @@ func (m *mockPatientRepository) GetByMedicalRecordNo(ctx context.Context, mrNo s
 	return nil, domain.ErrPatientNotFound
 }
 
+func (m *mockPatientRepository) GetLinks(ctx context.Context, id string) ([]*domain.Patient, error) {
+	patient, exists := m.patients[id]
+	if !exists {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	var links []*domain.Patient
+	for _, other := range m.patients {
+		if other.EnterpriseID == patient.EnterpriseID && other.TenantID != patient.TenantID {
+			links = append(links, other)
+		}
+	}
+	return links, nil
+}
+
 func (m *mockPatientRepository) Update(ctx context.Context, patient *domain.Patient) error {
 	if _, exists := m.patients[patient.ID]; !exists {
 		return domain.ErrPatientNotFound
@@ -60,6 +90,10 @@ func (m *mockPatientRepository) Update(ctx context.Context, patient *domain.Pati
 	return nil
 }
 
+func (m *mockPatientRepository) UpdateFields(ctx context.Context, patient *domain.Patient, fields []string) error {
+	return m.Update(ctx, patient)
+}
+
 func (m *mockPatientRepository) Delete(ctx context.Context, id string) error {
 	if _, exists := m.patients[id]; !exists {
 		return domain.ErrPatientNotFound
@@ -68,12 +102,30 @@ func (m *mockPatientRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (m *mockPatientRepository) List(ctx context.Context, filter domain.PatientFilter) ([]*domain.Patient, int, error) {
+func (m *mockPatientRepository) Merge(ctx context.Context, survivorID, mergedID, mergedBy string) error {
+	merged, exists := m.patients[mergedID]
+	if !exists || !merged.IsActive {
+		return domain.ErrPatientNotFound
+	}
+	merged.IsActive = false
+	return nil
+}
+
+func (m *mockPatientRepository) RecordDeath(ctx context.Context, patient *domain.Patient) error {
+	return m.Update(ctx, patient)
+}
+
+func (m *mockPatientRepository) ClearDeath(ctx context.Context, patient *domain.Patient) error {
+	return m.Update(ctx, patient)
+}
+
+func (m *mockPatientRepository) List(ctx context.Context, filter domain.PatientFilter) (*domain.PatientPage, error) {
 	var result []*domain.Patient
 	for _, patient := range m.patients {
 		result = append(result, patient)
 	}
-	return result, len(result), nil
+	total := len(result)
+	return &domain.PatientPage{Patients: result, Total: &total}, nil
 }
 
 func (m *mockPatientRepository) Exists(ctx context.Context, id string) (bool, error) {
@@ -81,9 +133,66 @@ func (m *mockPatientRepository) Exists(ctx context.Context, id string) (bool, er
 	return exists, nil
 }
 
+func (m *mockPatientRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Patient, error) {
+	var result []*domain.Patient
+	for _, id := range ids {
+		if patient, exists := m.patients[id]; exists {
+			result = append(result, patient)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockPatientRepository) GetByKeys(ctx context.Context, key domain.PatientKey, values []string, fields []string) ([]*domain.Patient, error) {
+	wanted := make(map[string]bool, len(values))
+	for _, v := range values {
+		wanted[v] = true
+	}
+
+	var result []*domain.Patient
+	for _, patient := range m.patients {
+		if patient.IsActive && wanted[patient.Key(key)] {
+			result = append(result, patient)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockPatientRepository) ExistingNIKs(ctx context.Context, niks []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for _, patient := range m.patients {
+		for _, nik := range niks {
+			if patient.NIK == nik {
+				existing[nik] = true
+			}
+		}
+	}
+	return existing, nil
+}
+
+func (m *mockPatientRepository) ScanNames(ctx context.Context, since time.Time, fn func(*domain.Patient) error) error {
+	for _, patient := range m.patients {
+		if !patient.UpdatedAt.Before(since) {
+			if err := fn(patient); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockPatientRepository) Scan(ctx context.Context, filter domain.PatientFilter, fn func(*domain.Patient) error) error {
+	for _, patient := range m.patients {
+		if err := fn(patient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func TestCreatePatient(t *testing.T) {
 	repo := NewMockPatientRepository()
-	service := NewPatientService(repo)
+	service := NewPatientService(repo, nil)
 
 	patient := &domain.Patient{
 		NIK:         "1234567890123456",
@@ -110,3 +219,327 @@ func TestCreatePatient(t *testing.T) {
 		t.Error("Expected medical record number to be generated")
 	}
 }
+
+func TestListPatientsRejectsCursorWithUnknownSort(t *testing.T) {
+	repo := NewMockPatientRepository()
+	service := NewPatientService(repo, nil)
+
+	filter := domain.PatientFilter{
+		Cursor: &domain.Cursor{Sort: "nik; DROP TABLE patients", Order: "ASC", ID: "x"},
+	}
+
+	_, err := service.ListPatients(context.Background(), filter)
+	customErr, ok := err.(*domain.CustomError)
+	if !ok || customErr.Code != "INVALID_CURSOR" {
+		t.Errorf("Expected INVALID_CURSOR error, got %v", err)
+	}
+}
+
+func TestSearchPatients(t *testing.T) {
+	ctx := tenant.NewContext(context.Background(), tenant.Default)
+	repo := NewMockPatientRepository()
+	repo.Create(ctx, &domain.Patient{ID: "p1", TenantID: tenant.Default, FirstName: "Siti", LastName: "Nurhaliza", IsActive: true})
+	repo.Create(ctx, &domain.Patient{ID: "p2", TenantID: tenant.Default, FirstName: "Budi", LastName: "Santoso", IsActive: true})
+	repo.Create(ctx, &domain.Patient{ID: "p3", TenantID: "north", FirstName: "Siti", LastName: "Nurhaliza", IsActive: true})
+
+	syncer := search.NewSyncer(repo, time.Minute)
+	service := NewPatientSearchService(repo, syncer)
+
+	if _, err := service.SearchPatients(ctx, "Siti", 10); err != domain.ErrSearchUnavailable {
+		t.Errorf("Expected ErrSearchUnavailable before the index is loaded, got %v", err)
+	}
+
+	if err := syncer.Refresh(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	matches, err := service.SearchPatients(ctx, "Nurhalisa, Sitti", 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(matches) != 1 || matches[0].Patient.ID != "p1" {
+		t.Fatalf("Expected only p1 of the caller's tenant to match, got %v", matches)
+	}
+
+	if matches[0].Score <= 0 || matches[0].Score >= 1 {
+		t.Errorf("Expected a fuzzy score between 0 and 1, got %v", matches[0].Score)
+	}
+}
+
+func TestCreatePatientUsesTenantMRNPrefix(t *testing.T) {
+	tenants, err := tenant.NewRegistry(tenant.Default, &tenant.Tenant{ID: "north", MRNPrefix: "RSN"})
+	if err != nil {
+		t.Fatalf("NewRegistry failed: %v", err)
+	}
+	service := NewPatientService(NewMockPatientRepository(), tenants)
+
+	newPatient := func(nik string) *domain.Patient {
+		return &domain.Patient{
+			NIK: nik, FirstName: "Siti", DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+			Gender: "FEMALE", Phone: "081234567890", IsActive: true,
+		}
+	}
+
+	created, err := service.CreatePatient(tenant.NewContext(context.Background(), "north"), newPatient("1234567890123456"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(created.MedicalRecordNo, "RSN-") {
+		t.Errorf("Expected the tenant's MRN prefix, got %q", created.MedicalRecordNo)
+	}
+
+	created, err = service.CreatePatient(tenant.NewContext(context.Background(), tenant.Default), newPatient("1234567890123457"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(created.MedicalRecordNo, domain.DefaultMRNPrefix+"-") {
+		t.Errorf("Expected the default MRN prefix, got %q", created.MedicalRecordNo)
+	}
+}
+
+func TestGetPatientLinks(t *testing.T) {
+	repo := NewMockPatientRepository()
+	service := NewPatientService(repo, nil)
+	ctx := context.Background()
+	repo.Create(ctx, &domain.Patient{ID: "p1", TenantID: tenant.Default, EnterpriseID: "e1", IsActive: true})
+	repo.Create(ctx, &domain.Patient{ID: "p2", TenantID: "north", EnterpriseID: "e1", IsActive: true})
+	repo.Create(ctx, &domain.Patient{ID: "p3", TenantID: "north", EnterpriseID: "e3", IsActive: true})
+
+	links, err := service.GetPatientLinks(ctx, "p1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(links) != 1 || links[0].ID != "p2" {
+		t.Errorf("Expected the record at the other tenant, got %v", links)
+	}
+
+	if _, err := service.GetPatientLinks(ctx, "missing"); err != domain.ErrPatientNotFound {
+		t.Errorf("Expected ErrPatientNotFound, got %v", err)
+	}
+}
+
+func newStoredPatient(repo repository.PatientRepository) *domain.Patient {
+	patient := &domain.Patient{
+		ID:              "p1",
+		MedicalRecordNo: "MR202401010001",
+		NIK:             "1234567890123456",
+		FirstName:       "John",
+		LastName:        "Doe",
+		DateOfBirth:     time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Gender:          "MALE",
+		Phone:           "081234567890",
+		Email:           "john@example.com",
+		IsActive:        true,
+	}
+	repo.Create(context.Background(), patient)
+	return patient
+}
+
+func TestUpdatePatientKeepsMedicalRecordNo(t *testing.T) {
+	repo := NewMockPatientRepository()
+	service := NewPatientService(repo, nil)
+	stored := newStoredPatient(repo)
+
+	update := *stored
+	update.MedicalRecordNo = ""
+	update.FirstName = "Johnny"
+
+	updated, err := service.UpdatePatient(context.Background(), &update)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if updated.MedicalRecordNo != "MR202401010001" {
+		t.Errorf("Expected medical record number to be kept, got %q", updated.MedicalRecordNo)
+	}
+}
+
+func TestGetPatientsByKeys(t *testing.T) {
+	repo := NewMockPatientRepository()
+	service := NewPatientService(repo, nil)
+	newStoredPatient(repo)
+
+	patients, err := service.GetPatientsByKeys(context.Background(), domain.KeyMedicalRecordNo, []string{"MR202401010001", "MR-UNKNOWN"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(patients) != 1 || patients[0].ID != "p1" {
+		t.Errorf("Expected patient p1, got %v", patients)
+	}
+
+	tooMany := make([]string, domain.MaxBatchGet+1)
+	_, err = service.GetPatientsByKeys(context.Background(), domain.KeyID, tooMany, nil)
+	customErr, ok := err.(*domain.CustomError)
+	if !ok || customErr.Code != "TOO_MANY_KEYS" {
+		t.Errorf("Expected TOO_MANY_KEYS error, got %v", err)
+	}
+}
+
+func TestPatchPatient(t *testing.T) {
+	repo := NewMockPatientRepository()
+	service := NewPatientService(repo, nil)
+	newStoredPatient(repo)
+
+	patched, err := service.PatchPatient(context.Background(), "p1", func(p *domain.Patient) error {
+		p.City = "Bandung"
+		p.Email = ""
+		p.MedicalRecordNo = "MR-OVERRIDE"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if patched.City != "Bandung" || patched.Email != "" {
+		t.Errorf("Expected city and email to be patched, got %q, %q", patched.City, patched.Email)
+	}
+	if patched.FirstName != "John" || patched.NIK != "1234567890123456" {
+		t.Error("Expected fields outside the patch to be kept")
+	}
+	if patched.MedicalRecordNo != "MR202401010001" {
+		t.Errorf("Expected medical record number to be read-only, got %q", patched.MedicalRecordNo)
+	}
+}
+
+func TestPatchPatientValidatesMergedResult(t *testing.T) {
+	repo := NewMockPatientRepository()
+	service := NewPatientService(repo, nil)
+	newStoredPatient(repo)
+
+	_, err := service.PatchPatient(context.Background(), "p1", func(p *domain.Patient) error {
+		p.Phone = ""
+		return nil
+	})
+
+	customErr, ok := err.(*domain.CustomError)
+	if !ok || customErr.Code != "INVALID_PHONE" {
+		t.Fatalf("Expected INVALID_PHONE, got %v", err)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), "p1")
+	if stored.Phone != "081234567890" {
+		t.Error("Expected stored patient to be unchanged")
+	}
+}
+
+// eventRepository records the event data UpdateFields emits, the way the
+// SQL Server repository builds it.
+type eventRepository struct {
+	repository.PatientRepository
+	events []domain.PatientEventData
+}
+
+func (r *eventRepository) UpdateFields(ctx context.Context, patient *domain.Patient, fields []string) error {
+	r.events = append(r.events, domain.NewPatientEventData(patient))
+	return r.PatientRepository.UpdateFields(ctx, patient, fields)
+}
+
+func TestPatchPatientKeepsDeath(t *testing.T) {
+	repo := &eventRepository{PatientRepository: NewMockPatientRepository()}
+	service := NewPatientService(repo, nil)
+	stored := newStoredPatient(repo)
+	deceasedAt := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	stored.DeceasedAt = &deceasedAt
+	stored.CauseOfDeath = "Cardiac arrest"
+	stored.DeathRecordedBy = "u-budi"
+
+	// A patch document carries no death details
+	patched, err := service.PatchPatient(context.Background(), "p1", func(p *domain.Patient) error {
+		doc := dto.ToPatchDocument(p)
+		doc.City = "Bandung"
+		dto.ApplyPatchDocument(p, doc)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if patched.DeceasedAt == nil || !patched.DeceasedAt.Equal(deceasedAt) || patched.CauseOfDeath != "Cardiac arrest" || patched.DeathRecordedBy != "u-budi" {
+		t.Errorf("Expected the recorded death to be kept, got %v, %q, %q", patched.DeceasedAt, patched.CauseOfDeath, patched.DeathRecordedBy)
+	}
+	if len(repo.events) != 1 {
+		t.Fatalf("Expected one patient.updated event, got %d", len(repo.events))
+	}
+	if data := repo.events[0]; data.DeceasedAt != "2024-05-01T08:30:00Z" {
+		t.Errorf("Expected the event to keep deceased_at, got %q", data.DeceasedAt)
+	}
+}
+
+func TestMergePatients(t *testing.T) {
+	repo := NewMockPatientRepository()
+	service := NewPatientService(repo, nil)
+	newStoredPatient(repo)
+	repo.Create(context.Background(), &domain.Patient{ID: "p2", MedicalRecordNo: "MR202401010002", IsActive: true})
+
+	if _, err := service.MergePatients(context.Background(), "p1", "p1", "hl7"); err == nil {
+		t.Error("Expected merging a patient into itself to fail")
+	}
+
+	survivor, err := service.MergePatients(context.Background(), "p1", "p2", "hl7")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if survivor.ID != "p1" {
+		t.Errorf("Expected survivor p1, got %s", survivor.ID)
+	}
+
+	merged, _ := repo.GetByID(context.Background(), "p2")
+	if merged.IsActive {
+		t.Error("Expected merged patient to be deactivated")
+	}
+
+	if _, err := service.MergePatients(context.Background(), "p1", "p2", "hl7"); err != domain.ErrPatientNotFound {
+		t.Errorf("Expected ErrPatientNotFound for an already merged patient, got %v", err)
+	}
+}
+
+func TestRecordDeath(t *testing.T) {
+	repo := NewMockPatientRepository()
+	service := NewPatientService(repo, nil)
+	newStoredPatient(repo)
+	ctx := context.Background()
+
+	if _, err := service.RecordDeath(ctx, "p1", time.Now().Add(time.Hour), "I21.9", "dr-1"); err == nil {
+		t.Error("Expected a time of death in the future to be rejected")
+	}
+	if _, err := service.RecordDeath(ctx, "p1", time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC), "I21.9", "dr-1"); err == nil {
+		t.Error("Expected a time of death before birth to be rejected")
+	}
+
+	deceasedAt := time.Date(2024, 6, 9, 23, 15, 0, 0, time.UTC)
+	patient, err := service.RecordDeath(ctx, "p1", deceasedAt, "I21.9", "dr-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !patient.IsDeceased() || !patient.DeceasedAt.Equal(deceasedAt) || patient.CauseOfDeath != "I21.9" || patient.DeathRecordedBy != "dr-1" {
+		t.Errorf("Unexpected death record: %v %q %q", patient.DeceasedAt, patient.CauseOfDeath, patient.DeathRecordedBy)
+	}
+	if !patient.IsActive {
+		t.Error("Expected deceased patient to stay active")
+	}
+
+	if _, err := service.RecordDeath(ctx, "p1", deceasedAt, "", "dr-2"); err != domain.ErrPatientDeceased {
+		t.Errorf("Expected ErrPatientDeceased, got %v", err)
+	}
+
+	// Registering the patient again is refused
+	_, err = service.CreatePatient(ctx, &domain.Patient{
+		NIK: "1234567890123456", FirstName: "John", DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Gender: "MALE", Phone: "081234567890", IsActive: true,
+	})
+	if customErr, ok := err.(*domain.CustomError); !ok || customErr.Code != "PATIENT_DECEASED" {
+		t.Errorf("Expected PATIENT_DECEASED, got %v", err)
+	}
+
+	cleared, err := service.ClearDeath(ctx, "p1", "admin")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cleared.IsDeceased() || cleared.CauseOfDeath != "" {
+		t.Error("Expected the death record to be cleared")
+	}
+	if _, err := service.ClearDeath(ctx, "p1", "admin"); err != domain.ErrPatientNotDeceased {
+		t.Errorf("Expected ErrPatientNotDeceased, got %v", err)
+	}
+}
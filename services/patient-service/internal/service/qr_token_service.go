@@ -0,0 +1,157 @@
+// Public patient profile tokens
+// internal/service/qr_token_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/qrtoken"
+	"patient-service/internal/repository"
+	"patient-service/internal/tenant"
+)
+
+// publicFields are the only patient fields a token resolves to.
+var publicFields = []string{"id", "medical_record_no", "first_name", "last_name", "gender"}
+
+// maxScans is how many of a token's latest scans are listed.
+const maxScans = 500
+
+type qrTokenService struct {
+	patientRepo repository.PatientRepository
+	repo        repository.QRTokenRepository
+	signer      *qrtoken.Signer
+	ttl         time.Duration
+	maxTTL      time.Duration
+}
+
+// NewQRTokenService creates the service; tokens are valid for ttl unless
+// issued for another duration, at most maxTTL.
+func NewQRTokenService(patientRepo repository.PatientRepository, repo repository.QRTokenRepository, signer *qrtoken.Signer, ttl, maxTTL time.Duration) QRTokenService {
+	return &qrTokenService{patientRepo: patientRepo, repo: repo, signer: signer, ttl: ttl, maxTTL: maxTTL}
+}
+
+func (s *qrTokenService) IssueToken(ctx context.Context, patientID string, ttl time.Duration, createdBy string) (*domain.QRToken, string, error) {
+	if err := checkLiving(ctx, s.patientRepo, patientID); err != nil {
+		return nil, "", err
+	}
+	if ttl == 0 {
+		ttl = s.ttl
+	}
+	if ttl < 0 || ttl > s.maxTTL {
+		return nil, "", domain.NewCustomError("INVALID_TTL", fmt.Sprintf("Tokens are valid for at most %d hours", int(s.maxTTL.Hours())), "")
+	}
+
+	id, err := qrtoken.NewID()
+	if err != nil {
+		return nil, "", err
+	}
+	token := &domain.QRToken{
+		ID:        id,
+		PatientID: patientID,
+		// Stored to the second, as signed
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+		CreatedBy: createdBy,
+	}
+	if err := s.repo.Create(ctx, token); err != nil {
+		return nil, "", fmt.Errorf("failed to create token: %w", err)
+	}
+	return token, s.TokenString(token), nil
+}
+
+func (s *qrTokenService) ListTokens(ctx context.Context, patientID string) ([]*domain.QRToken, error) {
+	if err := checkPatient(ctx, s.patientRepo, patientID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByPatient(ctx, patientID)
+}
+
+func (s *qrTokenService) RevokeToken(ctx context.Context, patientID, id, revokedBy string) (*domain.QRToken, error) {
+	token, err := s.getToken(ctx, patientID, id)
+	if err != nil {
+		return nil, err
+	}
+	token.RevokedBy = revokedBy
+	if err := s.repo.Revoke(ctx, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *qrTokenService) ListScans(ctx context.Context, patientID, id string) ([]*domain.QRScan, error) {
+	if _, err := s.getToken(ctx, patientID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListScans(ctx, id, maxScans)
+}
+
+func (s *qrTokenService) TokenString(token *domain.QRToken) string {
+	return s.signer.Sign(token.ID, token.ExpiresAt)
+}
+
+func (s *qrTokenService) ResolveToken(ctx context.Context, tokenString string, scan *domain.QRScan) (*domain.Patient, error) {
+	scan.Result = domain.QRScanInvalid
+	patient, err := s.resolve(ctx, tokenString, scan)
+
+	if auditErr := s.repo.RecordScan(ctx, scan); auditErr != nil {
+		// Profiles are not released without an audit record
+		return nil, fmt.Errorf("failed to record scan: %w", auditErr)
+	}
+	return patient, err
+}
+
+// resolve looks the token up, setting the scan's token, patient and
+// result as far as they are known.
+func (s *qrTokenService) resolve(ctx context.Context, tokenString string, scan *domain.QRScan) (*domain.Patient, error) {
+	id, _, err := s.signer.Parse(tokenString)
+	if err != nil {
+		return nil, domain.ErrInvalidQRToken
+	}
+	token, err := s.repo.GetByID(ctx, id)
+	if err == domain.ErrQRTokenNotFound {
+		return nil, domain.ErrInvalidQRToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	scan.TokenID, scan.PatientID = token.ID, token.PatientID
+
+	switch token.Status(time.Now()) {
+	case domain.QRTokenRevoked:
+		scan.Result = domain.QRScanRevoked
+		return nil, domain.ErrInvalidQRToken
+	case domain.QRTokenExpired:
+		scan.Result = domain.QRScanExpired
+		return nil, domain.ErrInvalidQRToken
+	}
+
+	// The public endpoint has no tenant; the token's patient decides it
+	ctx = tenant.NewContext(ctx, token.TenantID)
+	patient, err := s.patientRepo.GetByIDFields(ctx, token.PatientID, publicFields)
+	if err == domain.ErrPatientNotFound {
+		return nil, domain.ErrInvalidQRToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	scan.Result = domain.QRScanOK
+	return patient, nil
+}
+
+func (s *qrTokenService) getToken(ctx context.Context, patientID, id string) (*domain.QRToken, error) {
+	if patientID == "" || id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	token, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Tokens of other tenants' patients are not found either
+	tenantID, _ := tenant.FromContext(ctx)
+	if token.PatientID != patientID || token.TenantID != tenantID {
+		return nil, domain.ErrQRTokenNotFound
+	}
+	return token, nil
+}
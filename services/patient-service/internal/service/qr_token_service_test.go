@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/qrtoken"
+)
+
+// mockQRTokenRepository keeps tokens and scans in memory.
+type mockQRTokenRepository struct {
+	tokens map[string]*domain.QRToken
+	scans  []*domain.QRScan
+}
+
+func (m *mockQRTokenRepository) Create(ctx context.Context, token *domain.QRToken) error {
+	m.tokens[token.ID] = token
+	return nil
+}
+
+func (m *mockQRTokenRepository) GetByID(ctx context.Context, id string) (*domain.QRToken, error) {
+	token, ok := m.tokens[id]
+	if !ok {
+		return nil, domain.ErrQRTokenNotFound
+	}
+	return token, nil
+}
+
+func (m *mockQRTokenRepository) ListByPatient(ctx context.Context, patientID string) ([]*domain.QRToken, error) {
+	var tokens []*domain.QRToken
+	for _, token := range m.tokens {
+		if token.PatientID == patientID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *mockQRTokenRepository) Revoke(ctx context.Context, token *domain.QRToken) error {
+	if token.RevokedAt != nil {
+		return domain.ErrQRTokenRevoked
+	}
+	now := time.Now()
+	token.RevokedAt = &now
+	return nil
+}
+
+func (m *mockQRTokenRepository) RecordScan(ctx context.Context, scan *domain.QRScan) error {
+	m.scans = append(m.scans, scan)
+	return nil
+}
+
+func (m *mockQRTokenRepository) ListScans(ctx context.Context, tokenID string, limit int) ([]*domain.QRScan, error) {
+	return m.scans, nil
+}
+
+func TestResolveToken(t *testing.T) {
+	ctx := context.Background()
+	patients := NewMockPatientRepository()
+	patients.Create(ctx, &domain.Patient{ID: "p1", MedicalRecordNo: "MR1", FirstName: "Budi"})
+	repo := &mockQRTokenRepository{tokens: make(map[string]*domain.QRToken)}
+	svc := NewQRTokenService(patients, repo, qrtoken.NewSigner([]byte("0123456789abcdef0123456789abcdef")), time.Hour, 24*time.Hour)
+
+	if _, _, err := svc.IssueToken(ctx, "p1", 48*time.Hour, "u1"); err == nil {
+		t.Error("Expected a TTL above the maximum to be rejected")
+	}
+	token, tokenString, err := svc.IssueToken(ctx, "p1", 0, "u1")
+	if err != nil {
+		t.Fatalf("IssueToken failed: %v", err)
+	}
+
+	patient, err := svc.ResolveToken(ctx, tokenString, &domain.QRScan{IP: "10.0.0.1"})
+	if err != nil || patient.MedicalRecordNo != "MR1" {
+		t.Fatalf("Expected the patient, got %v, %v", patient, err)
+	}
+
+	forged := qrtoken.NewSigner([]byte("another key of at least 32 bytes")).Sign(token.ID, token.ExpiresAt)
+	if _, err := svc.ResolveToken(ctx, forged, &domain.QRScan{}); err != domain.ErrInvalidQRToken {
+		t.Errorf("Expected a forged token to be rejected, got %v", err)
+	}
+
+	if _, err := svc.RevokeToken(ctx, "p2", token.ID, "u2"); err != domain.ErrQRTokenNotFound {
+		t.Errorf("Expected the token of another patient not to be found, got %v", err)
+	}
+	if _, err := svc.RevokeToken(ctx, "p1", token.ID, "u2"); err != nil {
+		t.Fatalf("RevokeToken failed: %v", err)
+	}
+	if _, err := svc.ResolveToken(ctx, tokenString, &domain.QRScan{}); err != domain.ErrInvalidQRToken {
+		t.Errorf("Expected a revoked token to be rejected, got %v", err)
+	}
+
+	want := []struct{ tokenID, result string }{
+		{token.ID, domain.QRScanOK},
+		{"", domain.QRScanInvalid},
+		{token.ID, domain.QRScanRevoked},
+	}
+	if len(repo.scans) != len(want) {
+		t.Fatalf("Expected every scan to be audited, got %d", len(repo.scans))
+	}
+	for i, w := range want {
+		if scan := repo.scans[i]; scan.TokenID != w.tokenID || scan.Result != w.result {
+			t.Errorf("Scan %d: got %s/%s, want %s/%s", i, scan.TokenID, scan.Result, w.tokenID, w.result)
+		}
+	}
+	if repo.scans[0].IP != "10.0.0.1" || repo.scans[0].PatientID != "p1" {
+		t.Errorf("Unexpected audit record %+v", repo.scans[0])
+	}
+}
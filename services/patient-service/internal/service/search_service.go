@@ -0,0 +1,78 @@
+// Patient name search
+// internal/service/search_service.go
+package service
+
+import (
+	"context"
+	"strings"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+	"patient-service/internal/search"
+	"patient-service/internal/tenant"
+)
+
+type patientSearchService struct {
+	patientRepo repository.PatientRepository
+	syncer      *search.Syncer
+}
+
+func NewPatientSearchService(patientRepo repository.PatientRepository, syncer *search.Syncer) PatientSearchService {
+	return &patientSearchService{
+		patientRepo: patientRepo,
+		syncer:      syncer,
+	}
+}
+
+func (s *patientSearchService) SearchPatients(ctx context.Context, query string, limit int) ([]*domain.PatientMatch, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, domain.ErrTenantRequired
+	}
+
+	if !s.syncer.Ready() {
+		return nil, domain.ErrSearchUnavailable
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50 // Max limit
+	}
+
+	results := s.syncer.Index(tenantID).Search(query, limit)
+	if len(results) == 0 {
+		return []*domain.PatientMatch{}, nil
+	}
+
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+
+	patients, err := s.patientRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*domain.Patient, len(patients))
+	for _, p := range patients {
+		byID[p.ID] = p
+	}
+
+	// Keep relevance order; patients deactivated since the last index
+	// refresh are skipped
+	matches := make([]*domain.PatientMatch, 0, len(results))
+	for _, r := range results {
+		if patient, ok := byID[r.ID]; ok {
+			matches = append(matches, &domain.PatientMatch{Patient: patient, Score: r.Score})
+		}
+	}
+
+	return matches, nil
+}
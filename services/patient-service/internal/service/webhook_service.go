@@ -0,0 +1,163 @@
+// Webhook subscription management
+// internal/service/webhook_service.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/repository"
+)
+
+const secretPrefix = "whsec_"
+
+type webhookService struct {
+	repo repository.WebhookRepository
+}
+
+func NewWebhookService(repo repository.WebhookRepository) WebhookService {
+	return &webhookService{repo: repo}
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	if err := validateWebhook(sub); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = secret
+	sub.IsActive = true
+
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return sub, nil
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.GetSubscription(ctx, id)
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx, false)
+}
+
+// UpdateWebhook replaces the URL, description, event types, consent type
+// and active state. Re-activating a disabled subscription resets its failure count;
+// its dead letters are not resent unless replayed.
+func (s *webhookService) UpdateWebhook(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	if err := validateWebhook(sub); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetSubscription(ctx, sub.ID)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = existing.Secret
+
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSubscription(ctx, sub.ID)
+}
+
+// RotateWebhookSecret replaces the signing secret. Deliveries are signed
+// with the new secret from the next attempt on.
+func (s *webhookService) RotateWebhookSecret(ctx context.Context, id, updatedBy string) (*domain.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if sub.Secret, err = generateSecret(); err != nil {
+		return nil, err
+	}
+	sub.UpdatedBy = updatedBy
+
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.ErrInvalidInput
+	}
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, filter domain.DeliveryFilter) ([]*domain.WebhookDelivery, int, error) {
+	if filter.SubscriptionID != "" {
+		if _, err := s.repo.GetSubscription(ctx, filter.SubscriptionID); err != nil {
+			return nil, 0, err
+		}
+	}
+	return s.repo.ListDeliveries(ctx, filter)
+}
+
+func (s *webhookService) GetDelivery(ctx context.Context, subscriptionID, id string) (*domain.WebhookDelivery, error) {
+	return s.repo.GetDelivery(ctx, subscriptionID, id)
+}
+
+// ReplayDelivery queues the delivery again with a fresh attempt budget. It
+// is sent once the subscription is active.
+func (s *webhookService) ReplayDelivery(ctx context.Context, subscriptionID, id string) (*domain.WebhookDelivery, error) {
+	if err := s.repo.ReplayDelivery(ctx, subscriptionID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDelivery(ctx, subscriptionID, id)
+}
+
+func validateWebhook(sub *domain.WebhookSubscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.NewCustomError("INVALID_URL", "Webhook URL must be an absolute http or https URL", sub.URL)
+	}
+	if u.User != nil {
+		return domain.NewCustomError("INVALID_URL", "Webhook URL must not contain credentials", "")
+	}
+
+	seen := make(map[string]bool)
+	types := sub.EventTypes[:0]
+	for _, t := range sub.EventTypes {
+		if !isEventType(t) {
+			return domain.NewCustomError("INVALID_EVENT_TYPE", "Unknown event type", t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	sub.EventTypes = types
+
+	return nil
+}
+
+func isEventType(t string) bool {
+	for _, known := range domain.EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
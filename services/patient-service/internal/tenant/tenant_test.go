@@ -0,0 +1,82 @@
+package tenant
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewRegistry(t *testing.T) {
+	for _, id := range []string{"", "has space", "a.b", "a/b", "a:b"} {
+		if _, err := NewRegistry(Default, &Tenant{ID: id}); err == nil {
+			t.Errorf("Expected tenant ID %q to be rejected", id)
+		}
+	}
+	if _, err := NewRegistry(Default, &Tenant{ID: "north"}, &Tenant{ID: "north"}); err == nil {
+		t.Error("Expected duplicate tenants to be rejected")
+	}
+
+	r, err := NewRegistry(Default, &Tenant{ID: "north", HL7Facility: "RSN"})
+	if err != nil {
+		t.Fatalf("NewRegistry failed: %v", err)
+	}
+	if _, ok := r.Get(Default); !ok {
+		t.Error("Expected the default tenant to be added")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r, _ := NewRegistry(Default, &Tenant{ID: "north", HL7Facility: "RSN"})
+
+	if got, ok := r.Resolve(""); !ok || got.ID != Default {
+		t.Errorf("Expected an empty claim to resolve to the default tenant, got %v", got)
+	}
+	if got, ok := r.Resolve("north"); !ok || got.ID != "north" {
+		t.Errorf("Expected the claimed tenant, got %v", got)
+	}
+	if _, ok := r.Resolve("south"); ok {
+		t.Error("Expected an unknown tenant not to resolve")
+	}
+	if got, ok := r.ByHL7Facility("rsn"); !ok || got.ID != "north" {
+		t.Errorf("Expected the facility to match case-insensitively, got %v", got)
+	}
+
+	strict, _ := NewRegistry("", &Tenant{ID: "north"})
+	if _, ok := strict.Resolve(""); ok {
+		t.Error("Expected the claim to be required without a default tenant")
+	}
+
+	var none *Registry
+	if _, ok := none.Resolve("north"); ok {
+		t.Error("Expected a nil registry to hold no tenants")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	data := `[{"id": "north", "name": "RS North", "mrn_prefix": "RSN"}]`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Load(path, Default)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got, ok := r.Get("north"); !ok || got.Name != "RS North" || got.MRNPrefix != "RSN" {
+		t.Errorf("Unexpected tenant %v", got)
+	}
+	if _, ok := r.Get(Default); !ok {
+		t.Error("Expected the default tenant alongside the configured ones")
+	}
+}
+
+func TestContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("Expected no tenant in an empty context")
+	}
+	if id, ok := FromContext(NewContext(context.Background(), "north")); !ok || id != "north" {
+		t.Errorf("Expected north, got %q", id)
+	}
+}
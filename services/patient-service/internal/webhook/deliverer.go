@@ -0,0 +1,223 @@
+// Webhook delivery worker
+// internal/webhook/deliverer.go
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/events"
+	"patient-service/internal/repository"
+)
+
+// Retry schedule: the delay doubles from minBackoff up to maxBackoff.
+const (
+	minBackoff = 30 * time.Second
+	maxBackoff = 6 * time.Hour
+
+	claimBatch    = 20
+	claimLease    = time.Minute
+	pruneInterval = time.Hour
+
+	userAgent = "patient-service-webhooks/1.0"
+)
+
+// DelivererConfig configures a Deliverer.
+type DelivererConfig struct {
+	Interval time.Duration // poll interval
+	Timeout  time.Duration // per request
+
+	// MaxAttempts moves a delivery to the dead letters after that many
+	// failed attempts.
+	MaxAttempts int
+
+	// DisableAfter disables a subscription after that many consecutive
+	// failed attempts across its deliveries.
+	DisableAfter int
+
+	// Retention is how long delivered deliveries are kept; dead letters
+	// are kept until replayed or the subscription is deleted.
+	Retention time.Duration
+}
+
+// Deliverer sends queued deliveries to subscribers. Each instance claims
+// its own batches, so several may run at once.
+type Deliverer struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	cfg    DelivererConfig
+	wake   chan struct{}
+}
+
+func NewDeliverer(repo repository.WebhookRepository, cfg DelivererConfig) *Deliverer {
+	return &Deliverer{
+		repo: repo,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			// A redirect is reported as a failed delivery rather than followed
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		cfg:  cfg,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// Wake makes Run check for due deliveries now.
+func (d *Deliverer) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due deliveries until ctx is cancelled.
+func (d *Deliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+
+	for {
+		for {
+			n, err := d.DeliverDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Webhook delivery failed: %v", err)
+			}
+			if err != nil || n < claimBatch {
+				break
+			}
+		}
+
+		if time.Since(lastPrune) >= pruneInterval {
+			lastPrune = time.Now()
+			if n, err := d.repo.PruneDeliveries(ctx, time.Now().Add(-d.cfg.Retention)); err != nil {
+				log.Printf("Webhook delivery prune failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Pruned %d webhook deliveries", n)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DeliverDue claims one batch of due deliveries and sends them, returning
+// the number claimed. Only storage errors are returned; failed requests
+// are recorded on the delivery.
+func (d *Deliverer) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimDeliveries(ctx, claimBatch, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	subs := make(map[string]*domain.WebhookSubscription)
+	for _, delivery := range deliveries {
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			sub, err = d.repo.GetSubscription(ctx, delivery.SubscriptionID)
+			if err == domain.ErrWebhookNotFound {
+				continue // deleted along with its deliveries
+			}
+			if err != nil {
+				return len(deliveries), err
+			}
+			subs[sub.ID] = sub
+		}
+		if !sub.IsActive {
+			continue // disabled earlier in this batch
+		}
+
+		attempt := d.send(ctx, sub, delivery)
+		d.advance(delivery, attempt)
+
+		disabled, err := d.repo.RecordAttempt(ctx, delivery, attempt, d.cfg.DisableAfter)
+		if err != nil {
+			return len(deliveries), err
+		}
+		if disabled {
+			sub.IsActive = false
+			log.Printf("Disabled webhook %s (%s) after %d consecutive failures", sub.ID, sub.URL, d.cfg.DisableAfter)
+		}
+	}
+	return len(deliveries), nil
+}
+
+// send posts the delivery's payload to the subscription URL.
+func (d *Deliverer) send(ctx context.Context, sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery) *domain.WebhookAttempt {
+	start := time.Now()
+	attempt := &domain.WebhookAttempt{AttemptedAt: start}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", events.ContentType)
+	req.Header.Set("User-Agent", userAgent)
+	// The event ID stays the same across retries so receivers can deduplicate
+	req.Header.Set(HeaderID, delivery.EventID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(start.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, start, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	attempt.Duration = time.Since(start)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	return attempt
+}
+
+// advance sets the delivery's state after an attempt: delivered, retried
+// with backoff, or dead after the last allowed attempt.
+func (d *Deliverer) advance(delivery *domain.WebhookDelivery, attempt *domain.WebhookAttempt) {
+	delivery.Attempts++
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+
+	if attempt.Succeeded() {
+		delivery.Status = domain.DeliveryDelivered
+		delivery.DeliveredAt = &attempt.AttemptedAt
+		delivery.LastError = ""
+		return
+	}
+
+	if delivery.LastError == "" {
+		delivery.LastError = fmt.Sprintf("unexpected status %d", attempt.StatusCode)
+	}
+	if len(delivery.LastError) > 1000 {
+		delivery.LastError = delivery.LastError[:1000]
+	}
+	if len(attempt.Error) > 1000 {
+		attempt.Error = attempt.Error[:1000]
+	}
+
+	if delivery.Attempts >= d.cfg.MaxAttempts {
+		delivery.Status = domain.DeliveryDead
+		return
+	}
+
+	delay := minBackoff << uint(min(delivery.Attempts-1, 16))
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	delivery.NextAttemptAt = time.Now().Add(delay)
+}
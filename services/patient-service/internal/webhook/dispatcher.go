@@ -0,0 +1,98 @@
+// Webhook fan-out from the outbox relay
+// internal/webhook/dispatcher.go
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"patient-service/internal/consent"
+	"patient-service/internal/domain"
+	"patient-service/internal/events"
+	"patient-service/internal/repository"
+	"patient-service/internal/tenant"
+)
+
+// Dispatcher is an events.EventPublisher that queues a delivery for every
+// active subscription accepting the event. Queuing is idempotent, so the
+// relay may publish an event again.
+type Dispatcher struct {
+	repo           repository.WebhookRepository
+	deliverer      *Deliverer
+	consents       consent.ConsentChecker
+	defaultConsent string
+}
+
+// NewDispatcher creates a dispatcher; deliverer, when set, is woken after
+// deliveries are queued. Subscriptions only receive the events of patients
+// who allow release for the subscription's consent type, or defaultConsent
+// for subscriptions without one.
+func NewDispatcher(repo repository.WebhookRepository, deliverer *Deliverer, consents consent.ConsentChecker, defaultConsent string) *Dispatcher {
+	return &Dispatcher{repo: repo, deliverer: deliverer, consents: consents, defaultConsent: defaultConsent}
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, event *events.CloudEvent) error {
+	ctx = tenant.NewContext(ctx, event.TenantID)
+	subs, err := d.repo.ListSubscriptions(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	eventType := strings.TrimPrefix(event.Type, events.TypePrefix)
+
+	var deliveries []*domain.WebhookDelivery
+	var payload []byte
+	allowed := make(map[string]bool)
+	for _, sub := range subs {
+		if !sub.Accepts(eventType) {
+			continue
+		}
+		consentType := sub.ConsentType
+		if consentType == "" {
+			consentType = d.defaultConsent
+		}
+		ok, checked := allowed[consentType]
+		if !checked {
+			if ok, err = d.allowed(ctx, event, consentType); err != nil {
+				return err
+			}
+			allowed[consentType] = ok
+		}
+		if !ok {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, &domain.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      eventType,
+			Payload:        payload,
+		})
+	}
+
+	if err := d.repo.EnqueueDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+	if len(deliveries) > 0 && d.deliverer != nil {
+		d.deliverer.Wake()
+	}
+	return nil
+}
+
+// allowed reports whether the event's patient allows release for the
+// consent type. Events without a patient are not released.
+func (d *Dispatcher) allowed(ctx context.Context, event *events.CloudEvent, consentType string) (bool, error) {
+	if event.Subject == "" || d.consents == nil {
+		return false, nil
+	}
+	return d.consents.Allowed(ctx, event.Subject, consentType)
+}
+
+func (d *Dispatcher) Close() error {
+	return nil
+}
@@ -0,0 +1,399 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"patient-service/internal/domain"
+	"patient-service/internal/events"
+)
+
+// memoryRepo implements repository.WebhookRepository.
+type memoryRepo struct {
+	subs       map[string]*domain.WebhookSubscription
+	deliveries []*domain.WebhookDelivery
+	attempts   map[string][]*domain.WebhookAttempt
+}
+
+func newMemoryRepo(subs ...*domain.WebhookSubscription) *memoryRepo {
+	r := &memoryRepo{subs: make(map[string]*domain.WebhookSubscription), attempts: make(map[string][]*domain.WebhookAttempt)}
+	for _, sub := range subs {
+		sub.IsActive = true
+		r.subs[sub.ID] = sub
+	}
+	return r
+}
+
+func (r *memoryRepo) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	r.subs[sub.ID] = sub
+	return nil
+}
+
+func (r *memoryRepo) GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	sub, ok := r.subs[id]
+	if !ok {
+		return nil, domain.ErrWebhookNotFound
+	}
+	copied := *sub
+	return &copied, nil
+}
+
+func (r *memoryRepo) ListSubscriptions(ctx context.Context, activeOnly bool) ([]*domain.WebhookSubscription, error) {
+	var subs []*domain.WebhookSubscription
+	for _, sub := range r.subs {
+		if sub.IsActive || !activeOnly {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (r *memoryRepo) UpdateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	r.subs[sub.ID] = sub
+	return nil
+}
+
+func (r *memoryRepo) DeleteSubscription(ctx context.Context, id string) error {
+	delete(r.subs, id)
+	return nil
+}
+
+func (r *memoryRepo) EnqueueDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+next:
+	for _, d := range deliveries {
+		for _, existing := range r.deliveries {
+			if existing.SubscriptionID == d.SubscriptionID && existing.EventID == d.EventID {
+				continue next
+			}
+		}
+		d.ID = d.SubscriptionID + "/" + d.EventID
+		d.Status = domain.DeliveryPending
+		r.deliveries = append(r.deliveries, d)
+	}
+	return nil
+}
+
+func (r *memoryRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	var claimed []*domain.WebhookDelivery
+	now := time.Now()
+	for _, d := range r.deliveries {
+		if d.Status == domain.DeliveryPending && !d.NextAttemptAt.After(now) && r.subs[d.SubscriptionID].IsActive && len(claimed) < limit {
+			copied := *d
+			claimed = append(claimed, &copied)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *memoryRepo) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt *domain.WebhookAttempt, disableAfter int) (bool, error) {
+	r.attempts[delivery.ID] = append(r.attempts[delivery.ID], attempt)
+	for _, d := range r.deliveries {
+		if d.ID == delivery.ID {
+			*d = *delivery
+		}
+	}
+
+	sub := r.subs[delivery.SubscriptionID]
+	if attempt.Succeeded() {
+		sub.ConsecutiveFailures = 0
+		return false, nil
+	}
+	sub.ConsecutiveFailures++
+	if sub.IsActive && sub.ConsecutiveFailures >= disableAfter {
+		sub.IsActive = false
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *memoryRepo) ListDeliveries(ctx context.Context, filter domain.DeliveryFilter) ([]*domain.WebhookDelivery, int, error) {
+	return r.deliveries, len(r.deliveries), nil
+}
+
+func (r *memoryRepo) GetDelivery(ctx context.Context, subscriptionID, id string) (*domain.WebhookDelivery, error) {
+	return nil, domain.ErrDeliveryNotFound
+}
+
+func (r *memoryRepo) ReplayDelivery(ctx context.Context, subscriptionID, id string) error {
+	return nil
+}
+
+func (r *memoryRepo) PruneDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// due makes every pending delivery due now.
+func (r *memoryRepo) due() {
+	for _, d := range r.deliveries {
+		d.NextAttemptAt = time.Time{}
+	}
+}
+
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int // responses to send, then 200
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func testEvent(id, eventType string) *events.CloudEvent {
+	return &events.CloudEvent{
+		SpecVersion: events.SpecVersion,
+		ID:          id,
+		Source:      "/test",
+		Type:        events.TypePrefix + eventType,
+		Subject:     "patient-1",
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"1"}`)
+	sig := Sign("whsec_test", now, body)
+
+	if err := Verify("whsec_test", "1700000000", sig, body, DefaultTolerance, now); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	cases := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      string
+		want      error
+	}{
+		{"wrong secret", "other", "1700000000", sig, `{"id":"1"}`, ErrInvalidSignature},
+		{"tampered body", "whsec_test", "1700000000", sig, `{"id":"2"}`, ErrInvalidSignature},
+		{"changed timestamp", "whsec_test", "1700000001", sig, `{"id":"1"}`, ErrInvalidSignature},
+		{"missing prefix", "whsec_test", "1700000000", sig[len("sha256="):], `{"id":"1"}`, ErrInvalidSignature},
+		{"stale", "whsec_test", "1699999000", Sign("whsec_test", time.Unix(1699999000, 0), body), `{"id":"1"}`, ErrInvalidTimestamp},
+		{"bad timestamp", "whsec_test", "yesterday", sig, `{"id":"1"}`, ErrInvalidTimestamp},
+	}
+	for _, tc := range cases {
+		err := Verify(tc.secret, tc.timestamp, tc.signature, []byte(tc.body), DefaultTolerance, now)
+		if err != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestDispatcherFiltersByEventType(t *testing.T) {
+	repo := newMemoryRepo(
+		&domain.WebhookSubscription{ID: "all"},
+		&domain.WebhookSubscription{ID: "deletes", EventTypes: []string{domain.EventPatientDeleted}},
+	)
+	repo.subs["disabled"] = &domain.WebhookSubscription{ID: "disabled"}
+
+	dispatcher := NewDispatcher(repo, nil, consents{"patient-1/" + domain.ConsentSatuSehat: true}, domain.ConsentSatuSehat)
+	ctx := context.Background()
+
+	if err := dispatcher.Publish(ctx, testEvent("e1", domain.EventPatientCreated)); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if err := dispatcher.Publish(ctx, testEvent("e2", domain.EventPatientDeleted)); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	// The relay may publish an event again
+	if err := dispatcher.Publish(ctx, testEvent("e2", domain.EventPatientDeleted)); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	got := map[string]bool{}
+	for _, d := range repo.deliveries {
+		got[d.ID] = true
+		if d.EventType != domain.EventPatientCreated && d.EventType != domain.EventPatientDeleted {
+			t.Errorf("Expected unprefixed event type, got %s", d.EventType)
+		}
+	}
+	if len(repo.deliveries) != 3 || !got["all/e1"] || !got["all/e2"] || !got["deletes/e2"] {
+		t.Errorf("Unexpected deliveries: %v", got)
+	}
+}
+
+// consents implements consent.ConsentChecker with a fixed set of
+// patients allowed.
+type consents map[string]bool
+
+func (c consents) Allowed(ctx context.Context, patientID, consentType string) (bool, error) {
+	return c[patientID+"/"+consentType], nil
+}
+
+func (c consents) AllowedAll(ctx context.Context, patientIDs []string, consentType string) (map[string]bool, error) {
+	allowed := make(map[string]bool)
+	for _, id := range patientIDs {
+		allowed[id] = c[id+"/"+consentType]
+	}
+	return allowed, nil
+}
+
+func TestDispatcherChecksConsent(t *testing.T) {
+	repo := newMemoryRepo(
+		&domain.WebhookSubscription{ID: "default"},
+		&domain.WebhookSubscription{ID: "family", ConsentType: domain.ConsentFamilySharing},
+		&domain.WebhookSubscription{ID: "sms", ConsentType: domain.ConsentSMSReminder},
+	)
+	dispatcher := NewDispatcher(repo, nil, consents{
+		"patient-1/" + domain.ConsentSMSReminder: true,
+		"patient-1/" + domain.ConsentSatuSehat:   true,
+	}, domain.ConsentSatuSehat)
+	ctx := context.Background()
+
+	if err := dispatcher.Publish(ctx, testEvent("e1", domain.EventPatientUpdated)); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	// Not released without a patient to check
+	event := testEvent("e2", domain.EventPatientUpdated)
+	event.Subject = ""
+	if err := dispatcher.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	// Subscriptions without a consent type check the default type
+	event = testEvent("e3", domain.EventPatientUpdated)
+	event.Subject = "patient-2"
+	if err := dispatcher.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	got := map[string]bool{}
+	for _, d := range repo.deliveries {
+		got[d.ID] = true
+	}
+	if len(repo.deliveries) != 2 || !got["default/e1"] || !got["sms/e1"] {
+		t.Errorf("Unexpected deliveries: %v", got)
+	}
+}
+
+func TestDelivererSignsAndRetries(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusFound}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	repo := newMemoryRepo(&domain.WebhookSubscription{ID: "s1", URL: server.URL, Secret: "whsec_test"})
+	repo.EnqueueDeliveries(context.Background(), []*domain.WebhookDelivery{
+		{SubscriptionID: "s1", EventID: "e1", EventType: domain.EventPatientCreated, Payload: []byte(`{"id":"e1"}`)},
+	})
+
+	deliverer := NewDeliverer(repo, DelivererConfig{Interval: time.Second, Timeout: time.Second, MaxAttempts: 5, DisableAfter: 10})
+	ctx := context.Background()
+
+	if _, err := deliverer.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue failed: %v", err)
+	}
+	d := repo.deliveries[0]
+	if d.Status != domain.DeliveryPending || d.Attempts != 1 || d.LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a pending retry after 503, got %+v", d)
+	}
+	if wait := time.Until(d.NextAttemptAt); wait < 25*time.Second || wait > minBackoff {
+		t.Errorf("Expected first retry in %s, got %s", minBackoff, wait)
+	}
+
+	// Not due yet
+	if n, _ := deliverer.DeliverDue(ctx); n != 0 {
+		t.Errorf("Expected no due deliveries, got %d", n)
+	}
+
+	// Redirects are not followed
+	repo.due()
+	deliverer.DeliverDue(ctx)
+	if d.Status != domain.DeliveryPending || d.LastStatusCode != http.StatusFound {
+		t.Fatalf("Expected a redirect to fail, got %+v", d)
+	}
+
+	repo.due()
+	deliverer.DeliverDue(ctx)
+	if d.Status != domain.DeliveryDelivered || d.DeliveredAt == nil || d.Attempts != 3 {
+		t.Fatalf("Expected delivered on third attempt, got %+v", d)
+	}
+	if len(repo.attempts[d.ID]) != 3 || repo.subs["s1"].ConsecutiveFailures != 0 {
+		t.Errorf("Expected 3 logged attempts and a reset failure count")
+	}
+
+	for i, req := range rc.requests {
+		if req.Header.Get(HeaderID) != "e1" {
+			t.Errorf("Request %d: expected %s e1, got %q", i, HeaderID, req.Header.Get(HeaderID))
+		}
+		if req.Header.Get("Content-Type") != events.ContentType {
+			t.Errorf("Request %d: unexpected content type %q", i, req.Header.Get("Content-Type"))
+		}
+		err := Verify("whsec_test", req.Header.Get(HeaderTimestamp), req.Header.Get(HeaderSignature), rc.bodies[i], DefaultTolerance, time.Now())
+		if err != nil {
+			t.Errorf("Request %d: signature does not verify: %v", i, err)
+		}
+	}
+}
+
+func TestDelivererDeadLettersAndDisables(t *testing.T) {
+	rc := &receiver{statuses: []int{500, 500, 500, 500, 500}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	repo := newMemoryRepo(&domain.WebhookSubscription{ID: "s1", URL: server.URL, Secret: "whsec_test"})
+	repo.EnqueueDeliveries(context.Background(), []*domain.WebhookDelivery{
+		{SubscriptionID: "s1", EventID: "e1", Payload: []byte(`{}`)},
+		{SubscriptionID: "s1", EventID: "e2", Payload: []byte(`{}`)},
+	})
+
+	deliverer := NewDeliverer(repo, DelivererConfig{Interval: time.Second, Timeout: time.Second, MaxAttempts: 2, DisableAfter: 3})
+	ctx := context.Background()
+
+	deliverer.DeliverDue(ctx) // e1 and e2 fail
+	repo.due()
+	deliverer.DeliverDue(ctx) // e1 fails for the last time, then the subscription is disabled
+
+	if d := repo.deliveries[0]; d.Status != domain.DeliveryDead || d.Attempts != 2 {
+		t.Errorf("Expected e1 dead after 2 attempts, got %+v", d)
+	}
+	if d := repo.deliveries[1]; d.Status != domain.DeliveryPending || d.Attempts != 1 {
+		t.Errorf("Expected e2 left pending when the subscription was disabled, got %+v", d)
+	}
+	if sub := repo.subs["s1"]; sub.IsActive || sub.ConsecutiveFailures != 3 {
+		t.Errorf("Expected subscription disabled after 3 failures, got %+v", sub)
+	}
+	if len(rc.requests) != 3 {
+		t.Errorf("Expected 3 requests, got %d", len(rc.requests))
+	}
+
+	// Disabled subscriptions get no deliveries
+	repo.due()
+	if n, _ := deliverer.DeliverDue(ctx); n != 0 {
+		t.Errorf("Expected no deliveries for a disabled subscription, got %d", n)
+	}
+}
+
+func TestDelivererReportsConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	repo := newMemoryRepo(&domain.WebhookSubscription{ID: "s1", URL: url, Secret: "whsec_test"})
+	repo.EnqueueDeliveries(context.Background(), []*domain.WebhookDelivery{{SubscriptionID: "s1", EventID: "e1", Payload: []byte(`{}`)}})
+
+	deliverer := NewDeliverer(repo, DelivererConfig{Interval: time.Second, Timeout: time.Second, MaxAttempts: 3, DisableAfter: 10})
+	deliverer.DeliverDue(context.Background())
+
+	d := repo.deliveries[0]
+	if d.Status != domain.DeliveryPending || d.LastStatusCode != 0 || d.LastError == "" {
+		t.Errorf("Expected a retry with the connection error recorded, got %+v", d)
+	}
+}
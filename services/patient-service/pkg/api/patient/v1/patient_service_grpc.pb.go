@@ -0,0 +1,419 @@
+// Patient service gRPC API
+// api/patient/v1/patient_service.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: patient/v1/patient_service.proto
+
+package patientv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	PatientService_GetPatient_FullMethodName       = "/hospital.patient.v1.PatientService/GetPatient"
+	PatientService_GetPatientByNIK_FullMethodName  = "/hospital.patient.v1.PatientService/GetPatientByNIK"
+	PatientService_GetPatientByMRN_FullMethodName  = "/hospital.patient.v1.PatientService/GetPatientByMRN"
+	PatientService_BatchGetPatients_FullMethodName = "/hospital.patient.v1.PatientService/BatchGetPatients"
+	PatientService_ListPatients_FullMethodName     = "/hospital.patient.v1.PatientService/ListPatients"
+	PatientService_CreatePatient_FullMethodName    = "/hospital.patient.v1.PatientService/CreatePatient"
+	PatientService_UpdatePatient_FullMethodName    = "/hospital.patient.v1.PatientService/UpdatePatient"
+	PatientService_WatchPatients_FullMethodName    = "/hospital.patient.v1.PatientService/WatchPatients"
+)
+
+// PatientServiceClient is the client API for PatientService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PatientService exposes the patient registry to internal services. Calls
+// carry a JWT in the "authorization" metadata ("Bearer <token>"), as the
+// REST API does.
+type PatientServiceClient interface {
+	GetPatient(ctx context.Context, in *GetPatientRequest, opts ...grpc.CallOption) (*Patient, error)
+	GetPatientByNIK(ctx context.Context, in *GetPatientByNIKRequest, opts ...grpc.CallOption) (*Patient, error)
+	GetPatientByMRN(ctx context.Context, in *GetPatientByMRNRequest, opts ...grpc.CallOption) (*Patient, error)
+	BatchGetPatients(ctx context.Context, in *BatchGetPatientsRequest, opts ...grpc.CallOption) (*BatchGetPatientsResponse, error)
+	ListPatients(ctx context.Context, in *ListPatientsRequest, opts ...grpc.CallOption) (*ListPatientsResponse, error)
+	CreatePatient(ctx context.Context, in *CreatePatientRequest, opts ...grpc.CallOption) (*Patient, error)
+	UpdatePatient(ctx context.Context, in *UpdatePatientRequest, opts ...grpc.CallOption) (*Patient, error)
+	// WatchPatients streams the change feed after since, or from now when
+	// since is empty. Resume with the token of the last change received.
+	WatchPatients(ctx context.Context, in *WatchPatientsRequest, opts ...grpc.CallOption) (PatientService_WatchPatientsClient, error)
+}
+
+type patientServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPatientServiceClient(cc grpc.ClientConnInterface) PatientServiceClient {
+	return &patientServiceClient{cc}
+}
+
+func (c *patientServiceClient) GetPatient(ctx context.Context, in *GetPatientRequest, opts ...grpc.CallOption) (*Patient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Patient)
+	err := c.cc.Invoke(ctx, PatientService_GetPatient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientServiceClient) GetPatientByNIK(ctx context.Context, in *GetPatientByNIKRequest, opts ...grpc.CallOption) (*Patient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Patient)
+	err := c.cc.Invoke(ctx, PatientService_GetPatientByNIK_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientServiceClient) GetPatientByMRN(ctx context.Context, in *GetPatientByMRNRequest, opts ...grpc.CallOption) (*Patient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Patient)
+	err := c.cc.Invoke(ctx, PatientService_GetPatientByMRN_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientServiceClient) BatchGetPatients(ctx context.Context, in *BatchGetPatientsRequest, opts ...grpc.CallOption) (*BatchGetPatientsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetPatientsResponse)
+	err := c.cc.Invoke(ctx, PatientService_BatchGetPatients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientServiceClient) ListPatients(ctx context.Context, in *ListPatientsRequest, opts ...grpc.CallOption) (*ListPatientsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPatientsResponse)
+	err := c.cc.Invoke(ctx, PatientService_ListPatients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientServiceClient) CreatePatient(ctx context.Context, in *CreatePatientRequest, opts ...grpc.CallOption) (*Patient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Patient)
+	err := c.cc.Invoke(ctx, PatientService_CreatePatient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientServiceClient) UpdatePatient(ctx context.Context, in *UpdatePatientRequest, opts ...grpc.CallOption) (*Patient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Patient)
+	err := c.cc.Invoke(ctx, PatientService_UpdatePatient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *patientServiceClient) WatchPatients(ctx context.Context, in *WatchPatientsRequest, opts ...grpc.CallOption) (PatientService_WatchPatientsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PatientService_ServiceDesc.Streams[0], PatientService_WatchPatients_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &patientServiceWatchPatientsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PatientService_WatchPatientsClient interface {
+	Recv() (*PatientChange, error)
+	grpc.ClientStream
+}
+
+type patientServiceWatchPatientsClient struct {
+	grpc.ClientStream
+}
+
+func (x *patientServiceWatchPatientsClient) Recv() (*PatientChange, error) {
+	m := new(PatientChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PatientServiceServer is the server API for PatientService service.
+// All implementations must embed UnimplementedPatientServiceServer
+// for forward compatibility
+//
+// PatientService exposes the patient registry to internal services. Calls
+// carry a JWT in the "authorization" metadata ("Bearer <token>"), as the
+// REST API does.
+type PatientServiceServer interface {
+	GetPatient(context.Context, *GetPatientRequest) (*Patient, error)
+	GetPatientByNIK(context.Context, *GetPatientByNIKRequest) (*Patient, error)
+	GetPatientByMRN(context.Context, *GetPatientByMRNRequest) (*Patient, error)
+	BatchGetPatients(context.Context, *BatchGetPatientsRequest) (*BatchGetPatientsResponse, error)
+	ListPatients(context.Context, *ListPatientsRequest) (*ListPatientsResponse, error)
+	CreatePatient(context.Context, *CreatePatientRequest) (*Patient, error)
+	UpdatePatient(context.Context, *UpdatePatientRequest) (*Patient, error)
+	// WatchPatients streams the change feed after since, or from now when
+	// since is empty. Resume with the token of the last change received.
+	WatchPatients(*WatchPatientsRequest, PatientService_WatchPatientsServer) error
+	mustEmbedUnimplementedPatientServiceServer()
+}
+
+// UnimplementedPatientServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPatientServiceServer struct {
+}
+
+func (UnimplementedPatientServiceServer) GetPatient(context.Context, *GetPatientRequest) (*Patient, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPatient not implemented")
+}
+func (UnimplementedPatientServiceServer) GetPatientByNIK(context.Context, *GetPatientByNIKRequest) (*Patient, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPatientByNIK not implemented")
+}
+func (UnimplementedPatientServiceServer) GetPatientByMRN(context.Context, *GetPatientByMRNRequest) (*Patient, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPatientByMRN not implemented")
+}
+func (UnimplementedPatientServiceServer) BatchGetPatients(context.Context, *BatchGetPatientsRequest) (*BatchGetPatientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetPatients not implemented")
+}
+func (UnimplementedPatientServiceServer) ListPatients(context.Context, *ListPatientsRequest) (*ListPatientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPatients not implemented")
+}
+func (UnimplementedPatientServiceServer) CreatePatient(context.Context, *CreatePatientRequest) (*Patient, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePatient not implemented")
+}
+func (UnimplementedPatientServiceServer) UpdatePatient(context.Context, *UpdatePatientRequest) (*Patient, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePatient not implemented")
+}
+func (UnimplementedPatientServiceServer) WatchPatients(*WatchPatientsRequest, PatientService_WatchPatientsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPatients not implemented")
+}
+func (UnimplementedPatientServiceServer) mustEmbedUnimplementedPatientServiceServer() {}
+
+// UnsafePatientServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PatientServiceServer will
+// result in compilation errors.
+type UnsafePatientServiceServer interface {
+	mustEmbedUnimplementedPatientServiceServer()
+}
+
+func RegisterPatientServiceServer(s grpc.ServiceRegistrar, srv PatientServiceServer) {
+	s.RegisterService(&PatientService_ServiceDesc, srv)
+}
+
+func _PatientService_GetPatient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPatientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientServiceServer).GetPatient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatientService_GetPatient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientServiceServer).GetPatient(ctx, req.(*GetPatientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientService_GetPatientByNIK_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPatientByNIKRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientServiceServer).GetPatientByNIK(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatientService_GetPatientByNIK_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientServiceServer).GetPatientByNIK(ctx, req.(*GetPatientByNIKRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientService_GetPatientByMRN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPatientByMRNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientServiceServer).GetPatientByMRN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatientService_GetPatientByMRN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientServiceServer).GetPatientByMRN(ctx, req.(*GetPatientByMRNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientService_BatchGetPatients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetPatientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientServiceServer).BatchGetPatients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatientService_BatchGetPatients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientServiceServer).BatchGetPatients(ctx, req.(*BatchGetPatientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientService_ListPatients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPatientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientServiceServer).ListPatients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatientService_ListPatients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientServiceServer).ListPatients(ctx, req.(*ListPatientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientService_CreatePatient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePatientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientServiceServer).CreatePatient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatientService_CreatePatient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientServiceServer).CreatePatient(ctx, req.(*CreatePatientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientService_UpdatePatient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePatientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PatientServiceServer).UpdatePatient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PatientService_UpdatePatient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PatientServiceServer).UpdatePatient(ctx, req.(*UpdatePatientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PatientService_WatchPatients_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPatientsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PatientServiceServer).WatchPatients(m, &patientServiceWatchPatientsServer{ServerStream: stream})
+}
+
+type PatientService_WatchPatientsServer interface {
+	Send(*PatientChange) error
+	grpc.ServerStream
+}
+
+type patientServiceWatchPatientsServer struct {
+	grpc.ServerStream
+}
+
+func (x *patientServiceWatchPatientsServer) Send(m *PatientChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// PatientService_ServiceDesc is the grpc.ServiceDesc for PatientService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PatientService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hospital.patient.v1.PatientService",
+	HandlerType: (*PatientServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPatient",
+			Handler:    _PatientService_GetPatient_Handler,
+		},
+		{
+			MethodName: "GetPatientByNIK",
+			Handler:    _PatientService_GetPatientByNIK_Handler,
+		},
+		{
+			MethodName: "GetPatientByMRN",
+			Handler:    _PatientService_GetPatientByMRN_Handler,
+		},
+		{
+			MethodName: "BatchGetPatients",
+			Handler:    _PatientService_BatchGetPatients_Handler,
+		},
+		{
+			MethodName: "ListPatients",
+			Handler:    _PatientService_ListPatients_Handler,
+		},
+		{
+			MethodName: "CreatePatient",
+			Handler:    _PatientService_CreatePatient_Handler,
+		},
+		{
+			MethodName: "UpdatePatient",
+			Handler:    _PatientService_UpdatePatient_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPatients",
+			Handler:       _PatientService_WatchPatients_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "patient/v1/patient_service.proto",
+}
@@ -0,0 +1,120 @@
+// Envelope encryption for sensitive columns
+// pkg/envelope/envelope.go
+package envelope
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// Cipher encrypts field values with per-record data keys (DEK) that are
+// wrapped by a key-encryption key from the KMS, and computes HMAC blind
+// indexes so encrypted values can still be looked up by equality.
+type Cipher struct {
+	kms           KMS
+	blindIndexKey []byte
+}
+
+func NewCipher(kms KMS, blindIndexKey []byte) (*Cipher, error) {
+	if kms == nil {
+		return nil, errors.New("kms is required")
+	}
+	if len(blindIndexKey) < 32 {
+		return nil, errors.New("blind index key must be at least 32 bytes")
+	}
+	return &Cipher{kms: kms, blindIndexKey: blindIndexKey}, nil
+}
+
+// ActiveKeyID returns the KEK that new data keys are wrapped with.
+func (c *Cipher) ActiveKeyID() string {
+	return c.kms.ActiveKeyID()
+}
+
+// DataKey is an unwrapped per-record key together with its wrapped form.
+type DataKey struct {
+	KeyID   string
+	Wrapped []byte
+	key     []byte
+}
+
+// NewDataKey generates a fresh DEK wrapped with the active KEK.
+func (c *Cipher) NewDataKey(ctx context.Context) (*DataKey, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	keyID := c.kms.ActiveKeyID()
+	wrapped, err := c.kms.WrapKey(ctx, keyID, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	return &DataKey{KeyID: keyID, Wrapped: wrapped, key: key}, nil
+}
+
+// OpenDataKey unwraps a stored DEK.
+func (c *Cipher) OpenDataKey(ctx context.Context, keyID string, wrapped []byte) (*DataKey, error) {
+	key, err := c.kms.UnwrapKey(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return &DataKey{KeyID: keyID, Wrapped: wrapped, key: key}, nil
+}
+
+// Encrypt returns the base64 encoded AES-GCM ciphertext of value. The aad
+// binds the ciphertext to its row and column so values cannot be swapped.
+// Empty values stay empty.
+func (k *DataKey) Encrypt(value, aad string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	sealed, err := seal(k.key, []byte(value), []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (k *DataKey) Decrypt(value, aad string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	plaintext, err := open(k.key, sealed, []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex returns a deterministic keyed hash of value for the given
+// field. The field name is mixed in so equal values in different columns
+// produce different indexes.
+func (c *Cipher) BlindIndex(field, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, c.blindIndexKey)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
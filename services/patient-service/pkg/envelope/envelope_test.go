@@ -0,0 +1,152 @@
+package envelope
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestCipher(t *testing.T) (*Cipher, *LocalKMS) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	if err := GenerateKeyring(path, "k1"); err != nil {
+		t.Fatalf("Failed to generate keyring: %v", err)
+	}
+
+	kms, err := NewLocalKMS(path)
+	if err != nil {
+		t.Fatalf("Failed to load keyring: %v", err)
+	}
+
+	bidxKey := make([]byte, 32)
+	rand.Read(bidxKey)
+
+	c, err := NewCipher(kms, bidxKey)
+	if err != nil {
+		t.Fatalf("Failed to create cipher: %v", err)
+	}
+	return c, kms
+}
+
+func TestDataKeyRoundTrip(t *testing.T) {
+	c, _ := newTestCipher(t)
+	ctx := context.Background()
+
+	dk, err := c.NewDataKey(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ciphertext, err := dk.Encrypt("1234567890123456", "p1|nik")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ciphertext == "1234567890123456" {
+		t.Fatal("Expected value to be encrypted")
+	}
+
+	opened, err := c.OpenDataKey(ctx, dk.KeyID, dk.Wrapped)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	plaintext, err := opened.Decrypt(ciphertext, "p1|nik")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if plaintext != "1234567890123456" {
+		t.Errorf("Expected 1234567890123456, got %s", plaintext)
+	}
+
+	if _, err := opened.Decrypt(ciphertext, "p2|nik"); err != ErrInvalidCiphertext {
+		t.Errorf("Expected ErrInvalidCiphertext for mismatched aad, got %v", err)
+	}
+}
+
+func TestRotateKeepsOldKeys(t *testing.T) {
+	c, kms := newTestCipher(t)
+	ctx := context.Background()
+
+	old, err := c.NewDataKey(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := kms.Rotate("k2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if c.ActiveKeyID() != "k2" {
+		t.Errorf("Expected active key k2, got %s", c.ActiveKeyID())
+	}
+
+	if _, err := c.OpenDataKey(ctx, old.KeyID, old.Wrapped); err != nil {
+		t.Errorf("Expected old data key to unwrap after rotation, got %v", err)
+	}
+
+	reloaded, err := NewLocalKMS(kms.path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(reloaded.KeyIDs()) != 2 {
+		t.Errorf("Expected 2 keys after reload, got %d", len(reloaded.KeyIDs()))
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	c, _ := newTestCipher(t)
+
+	a := c.BlindIndex("nik", "1234567890123456")
+	b := c.BlindIndex("nik", " 1234567890123456 ")
+	if a != b {
+		t.Error("Expected blind index to ignore surrounding whitespace")
+	}
+
+	if a == c.BlindIndex("phone", "1234567890123456") {
+		t.Error("Expected blind index to differ per field")
+	}
+
+	if c.BlindIndex("nik", "") != "" {
+		t.Error("Expected empty blind index for empty value")
+	}
+}
+
+func TestReloadPicksUpRotatedKey(t *testing.T) {
+	tool, toolKMS := newTestCipher(t)
+	ctx := context.Background()
+
+	// A running service loaded the keyring before the rotation
+	runningKMS, err := NewLocalKMS(toolKMS.path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	running, _ := NewCipher(runningKMS, tool.blindIndexKey)
+
+	if err := toolKMS.Rotate("k2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	rotated, err := tool.NewDataKey(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Within minReloadInterval of loading, an unknown key is not reloaded
+	if _, err := running.OpenDataKey(ctx, rotated.KeyID, rotated.Wrapped); err == nil {
+		t.Fatal("Expected k2 to be unknown right after loading")
+	}
+
+	runningKMS.lastReload = time.Time{}
+	if _, err := running.OpenDataKey(ctx, rotated.KeyID, rotated.Wrapped); err != nil {
+		t.Errorf("Expected an unknown key to be reloaded, got %v", err)
+	}
+	if running.ActiveKeyID() != "k2" {
+		t.Errorf("Expected the reload to activate k2, got %s", running.ActiveKeyID())
+	}
+
+	if _, err := running.OpenDataKey(ctx, "k9", rotated.Wrapped); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey for a key that does not exist, got %v", err)
+	}
+}
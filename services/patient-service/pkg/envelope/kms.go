@@ -0,0 +1,290 @@
+// Key management
+// pkg/envelope/kms.go
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownKey  = errors.New("unknown key-encryption key")
+	ErrNoActiveKey = errors.New("no active key-encryption key")
+)
+
+// KMS wraps and unwraps per-record data keys with a key-encryption key (KEK).
+// Implementations may be backed by a cloud KMS, an HSM or a local keyring.
+type KMS interface {
+	// ActiveKeyID returns the ID of the KEK used to wrap new data keys.
+	ActiveKeyID() string
+	WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Keyring is the on-disk format of the local KMS.
+type Keyring struct {
+	ActiveKeyID string            `json:"active_key_id"`
+	Keys        map[string]string `json:"keys"` // key ID -> base64 encoded 32 byte key
+}
+
+// LocalKMS is a file based KMS intended for development and single-node
+// deployments. Keys are kept in memory after loading and reloaded from the
+// file when an unknown key ID is seen, or by Watch, so running instances
+// pick up keys added by cmd/keyrotate.
+type LocalKMS struct {
+	mu         sync.RWMutex
+	path       string
+	active     string
+	keys       map[string][]byte
+	lastReload time.Time
+}
+
+// minReloadInterval limits the reloads unknown key IDs cause.
+const minReloadInterval = time.Second
+
+func NewLocalKMS(path string) (*LocalKMS, error) {
+	active, keys, err := readKeyring(path)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalKMS{path: path, active: active, keys: keys, lastReload: time.Now()}, nil
+}
+
+// readKeyring loads and validates the keyring file.
+func readKeyring(path string) (string, map[string][]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read keyring: %w", err)
+	}
+
+	var ring Keyring
+	if err := json.Unmarshal(data, &ring); err != nil {
+		return "", nil, fmt.Errorf("failed to parse keyring: %w", err)
+	}
+
+	keys := make(map[string][]byte, len(ring.Keys))
+	for id, encoded := range ring.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid key %q: %w", id, err)
+		}
+		if len(key) != 32 {
+			return "", nil, fmt.Errorf("key %q must be 32 bytes, got %d", id, len(key))
+		}
+		keys[id] = key
+	}
+
+	if _, ok := keys[ring.ActiveKeyID]; !ok {
+		return "", nil, ErrNoActiveKey
+	}
+	return ring.ActiveKeyID, keys, nil
+}
+
+// Reload reads the keyring file again, taking over its active key and any
+// keys added since. Keys already loaded are kept.
+func (k *LocalKMS) Reload() error {
+	active, keys, err := readKeyring(k.path)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for id, key := range keys {
+		k.keys[id] = key
+	}
+	k.active = active
+	k.lastReload = time.Now()
+	return nil
+}
+
+// Watch reloads the keyring every interval until ctx is done, so new data
+// keys are wrapped with a key made active by another process.
+func (k *LocalKMS) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Reload(); err != nil {
+				log.Printf("Keyring reload failed: %v", err)
+			}
+		}
+	}
+}
+
+func (k *LocalKMS) ActiveKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// KeyIDs returns all known KEK IDs in sorted order.
+func (k *LocalKMS) KeyIDs() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (k *LocalKMS) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	kek, err := k.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return seal(kek, dataKey, []byte(keyID))
+}
+
+func (k *LocalKMS) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	kek, err := k.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return open(kek, wrapped, []byte(keyID))
+}
+
+// Rotate generates a new KEK, makes it active and persists the keyring.
+// Older keys are kept so existing data keys can still be unwrapped.
+func (k *LocalKMS) Rotate(newKeyID string) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, exists := k.keys[newKeyID]; exists {
+		return fmt.Errorf("key %q already exists", newKeyID)
+	}
+
+	k.keys[newKeyID] = key
+	k.active = newKeyID
+
+	return k.save()
+}
+
+func (k *LocalKMS) save() error {
+	ring := Keyring{
+		ActiveKeyID: k.active,
+		Keys:        make(map[string]string, len(k.keys)),
+	}
+	for id, key := range k.keys {
+		ring.Keys[id] = base64.StdEncoding.EncodeToString(key)
+	}
+
+	data, err := json.MarshalIndent(ring, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := k.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, k.path)
+}
+
+func (k *LocalKMS) key(keyID string) ([]byte, error) {
+	k.mu.RLock()
+	key, ok := k.keys[keyID]
+	recent := time.Since(k.lastReload) < minReloadInterval
+	k.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	// The key may have been added by cmd/keyrotate since the last load
+	if !recent {
+		if err := k.Reload(); err != nil {
+			return nil, err
+		}
+		k.mu.RLock()
+		key, ok = k.keys[keyID]
+		k.mu.RUnlock()
+		if ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+}
+
+// GenerateKeyring creates a new keyring file with a single active key.
+func GenerateKeyring(path, keyID string) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+
+	ring := Keyring{
+		ActiveKeyID: keyID,
+		Keys:        map[string]string{keyID: base64.StdEncoding.EncodeToString(key)},
+	}
+
+	data, err := json.MarshalIndent(ring, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// seal encrypts plaintext with AES-256-GCM, returning nonce || ciphertext.
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(key, sealed, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
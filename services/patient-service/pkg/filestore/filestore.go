@@ -0,0 +1,126 @@
+// File store for generated files
+// pkg/filestore/filestore.go
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrNotFound    = errors.New("file not found")
+	ErrInvalidName = errors.New("invalid file name")
+)
+
+// Store keeps named files. Names are flat: no directories.
+type Store interface {
+	// Put writes a file from what write produces and returns its size.
+	// The file only becomes visible once write succeeded.
+	Put(ctx context.Context, name string, write func(w io.Writer) error) (int64, error)
+
+	// Open returns the file and its size.
+	Open(ctx context.Context, name string) (io.ReadCloser, int64, error)
+
+	// Delete removes the file; a missing file is not an error.
+	Delete(ctx context.Context, name string) error
+}
+
+// Local is a Store in a directory of the local file system.
+type Local struct {
+	dir string
+}
+
+// NewLocal creates the directory if needed. Files are readable by the
+// service user only.
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create file store: %w", err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+func (s *Local) path(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", ErrInvalidName
+	}
+	return filepath.Join(s.dir, name), nil
+}
+
+func (s *Local) Put(ctx context.Context, name string, write func(w io.Writer) error) (int64, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return 0, err
+	}
+
+	// Written under a hidden temporary name and renamed when complete
+	tmp, err := os.CreateTemp(s.dir, ".tmp-"+name+"-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	counter := &countingWriter{w: tmp}
+	err = write(counter)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+	return counter.n, nil
+}
+
+func (s *Local) Open(ctx context.Context, name string) (io.ReadCloser, int64, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, ErrNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
+
+func (s *Local) Delete(ctx context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,69 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestLocalPutOpenDelete(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+
+	size, err := store.Put(ctx, "export.csv", func(w io.Writer) error {
+		_, err := io.WriteString(w, "id\np1\n")
+		return err
+	})
+	if err != nil || size != 6 {
+		t.Fatalf("Put = %d, %v", size, err)
+	}
+
+	r, size, err := store.Open(ctx, "export.csv")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "id\np1\n" || size != 6 {
+		t.Errorf("Unexpected file: %q (%d bytes)", data, size)
+	}
+
+	if err := store.Delete(ctx, "export.csv"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, _, err := store.Open(ctx, "export.csv"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestLocalPutFailureLeavesNoFile(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewLocal(dir)
+
+	_, err := store.Put(context.Background(), "export.csv", func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return errors.New("scan failed")
+	})
+	if err == nil {
+		t.Fatal("Expected the write error")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("Expected no files, got %v", entries)
+	}
+}
+
+func TestLocalRejectsPaths(t *testing.T) {
+	store, _ := NewLocal(t.TempDir())
+	for _, name := range []string{"", "../secret", "a/b", ".hidden"} {
+		if _, _, err := store.Open(context.Background(), name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Open(%q): expected ErrInvalidName, got %v", name, err)
+		}
+	}
+}
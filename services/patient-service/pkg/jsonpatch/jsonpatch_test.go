@@ -0,0 +1,96 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	// Examples from RFC 7396 appendix A
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+	}
+
+	for _, tt := range tests {
+		got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+		if err != nil {
+			t.Fatalf("MergePatch(%s, %s): %v", tt.doc, tt.patch, err)
+		}
+		assertJSONEqual(t, got, tt.want)
+	}
+}
+
+func TestApply(t *testing.T) {
+	doc := `{"first_name":"Budi","tags":["a","b"],"a/b":1}`
+
+	tests := []struct {
+		patch, want string
+	}{
+		{`[{"op":"replace","path":"/first_name","value":"Andi"}]`, `{"first_name":"Andi","tags":["a","b"],"a/b":1}`},
+		{`[{"op":"add","path":"/tags/1","value":"x"}]`, `{"first_name":"Budi","tags":["a","x","b"],"a/b":1}`},
+		{`[{"op":"add","path":"/tags/-","value":"c"}]`, `{"first_name":"Budi","tags":["a","b","c"],"a/b":1}`},
+		{`[{"op":"remove","path":"/tags/0"}]`, `{"first_name":"Budi","tags":["b"],"a/b":1}`},
+		{`[{"op":"remove","path":"/a~1b"}]`, `{"first_name":"Budi","tags":["a","b"]}`},
+		{`[{"op":"move","from":"/first_name","path":"/name"}]`, `{"name":"Budi","tags":["a","b"],"a/b":1}`},
+		{`[{"op":"copy","from":"/tags","path":"/copy"}]`, `{"first_name":"Budi","tags":["a","b"],"copy":["a","b"],"a/b":1}`},
+		{`[{"op":"test","path":"/first_name","value":"Budi"},{"op":"remove","path":"/tags"}]`, `{"first_name":"Budi","a/b":1}`},
+	}
+
+	for _, tt := range tests {
+		got, err := Apply([]byte(doc), []byte(tt.patch))
+		if err != nil {
+			t.Fatalf("Apply(%s): %v", tt.patch, err)
+		}
+		assertJSONEqual(t, got, tt.want)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	doc := []byte(`{"first_name":"Budi","tags":["a"]}`)
+
+	tests := []struct {
+		patch string
+		want  error
+	}{
+		{`[{"op":"test","path":"/first_name","value":"Andi"}]`, ErrTestFailed},
+		{`[{"op":"replace","path":"/missing","value":1}]`, ErrInvalidPatch},
+		{`[{"op":"remove","path":"/tags/5"}]`, ErrInvalidPatch},
+		{`[{"op":"add","path":"/tags/01","value":"x"}]`, ErrInvalidPatch},
+		{`[{"op":"frobnicate","path":"/first_name"}]`, ErrInvalidPatch},
+		{`[{"op":"add","path":"first_name","value":"x"}]`, ErrInvalidPatch},
+		{`{"op":"add"}`, ErrInvalidPatch},
+	}
+
+	for _, tt := range tests {
+		if _, err := Apply(doc, []byte(tt.patch)); !errors.Is(err, tt.want) {
+			t.Errorf("Apply(%s): expected %v, got %v", tt.patch, tt.want, err)
+		}
+	}
+}
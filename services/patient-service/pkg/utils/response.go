@@ -34,7 +34,7 @@ func ValidationErrorResponse(c *fiber.Ctx, err error) error {
 
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		for _, e := range validationErrors {
-			errors = append(errors, formatValidationError(e))
+			errors = append(errors, FormatValidationError(e))
 		}
 	}
 
@@ -47,7 +47,8 @@ func ValidationErrorResponse(c *fiber.Ctx, err error) error {
 	})
 }
 
-func formatValidationError(e validator.FieldError) string {
+// FormatValidationError describes a failed validation rule of a field.
+func FormatValidationError(e validator.FieldError) string {
 	field := e.Field()
 	tag := e.Tag()
 
@@ -64,6 +65,14 @@ func formatValidationError(e validator.FieldError) string {
 		return field + " must be a valid email"
 	case "oneof":
 		return field + " must be one of: " + e.Param()
+	case "csvoneof":
+		return field + " must be a comma separated list of: " + e.Param()
+	case "csvmax":
+		return field + " must have at most " + e.Param() + " values"
+	case "isodate":
+		return field + " must be a date (YYYY-MM-DD) or RFC 3339 timestamp"
+	case "datetime":
+		return field + " must be a date in format " + e.Param()
 	default:
 		return field + " is invalid"
 	}
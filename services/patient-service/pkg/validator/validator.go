@@ -4,6 +4,10 @@
 package validator
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -11,9 +15,67 @@ import (
 func New() *validator.Validate {
 	validate := validator.New()
 
-	// Register custom validations here if needed
-	// Example:
-	// validate.RegisterValidation("customTag", customValidationFunc)
+	// Comma separated query values, e.g. ?province=A,B
+	validate.RegisterValidation("csvoneof", validateCSVOneOf)
+	validate.RegisterValidation("csvmax", validateCSVMax)
+
+	// Date (2006-01-02) or RFC 3339 timestamp
+	validate.RegisterValidation("isodate", validateISODate)
 
 	return validate
 }
+
+// SplitCSV splits a comma separated value, dropping empty items.
+func SplitCSV(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ParseISODate parses a date or an RFC 3339 timestamp. dateOnly reports
+// whether the value had no time component.
+func ParseISODate(value string) (t time.Time, dateOnly bool, err error) {
+	if t, err = time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+func validateCSVOneOf(fl validator.FieldLevel) bool {
+	allowed := strings.Fields(fl.Param())
+	for _, item := range SplitCSV(fl.Field().String()) {
+		found := false
+		for _, a := range allowed {
+			if strings.EqualFold(item, a) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func validateCSVMax(fl validator.FieldLevel) bool {
+	max, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return false
+	}
+	return len(SplitCSV(fl.Field().String())) <= max
+}
+
+func validateISODate(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" {
+		return true
+	}
+	_, _, err := ParseISODate(value)
+	return err == nil
+}
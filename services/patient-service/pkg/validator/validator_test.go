@@ -0,0 +1,40 @@
+package validator
+
+import "testing"
+
+type listRequest struct {
+	BloodType string `validate:"omitempty,csvoneof=A+ A- B+ B- AB+ AB- O+ O-"`
+	Province  string `validate:"csvmax=2"`
+	From      string `validate:"omitempty,isodate"`
+}
+
+func TestCustomValidations(t *testing.T) {
+	validate := New()
+
+	tests := []struct {
+		name  string
+		req   listRequest
+		valid bool
+	}{
+		{"multiple blood types", listRequest{BloodType: "A+, O-"}, true},
+		{"unknown blood type", listRequest{BloodType: "A+,C"}, false},
+		{"too many provinces", listRequest{Province: "Bali,Aceh,Papua"}, false},
+		{"date only", listRequest{From: "2024-01-31"}, true},
+		{"rfc3339", listRequest{From: "2024-01-31T10:00:00+07:00"}, true},
+		{"invalid date", listRequest{From: "31-01-2024"}, false},
+	}
+
+	for _, tt := range tests {
+		err := validate.Struct(&tt.req)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: expected valid=%v, got %v", tt.name, tt.valid, err)
+		}
+	}
+}
+
+func TestSplitCSV(t *testing.T) {
+	items := SplitCSV(" Jakarta, ,Bandung,")
+	if len(items) != 2 || items[0] != "Jakarta" || items[1] != "Bandung" {
+		t.Errorf("Expected [Jakarta Bandung], got %v", items)
+	}
+}
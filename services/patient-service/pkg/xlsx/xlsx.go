@@ -0,0 +1,237 @@
+// Minimal XLSX (Office Open XML spreadsheet) reader
+// pkg/xlsx/xlsx.go
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidWorkbook = errors.New("invalid xlsx workbook")
+	ErrTooLarge        = errors.New("xlsx part exceeds size limit")
+)
+
+// maxPartSize bounds the uncompressed size of each part read, so a small
+// upload cannot expand into gigabytes of XML.
+const maxPartSize = 256 << 20
+
+// maxRows is the row limit of an Excel worksheet.
+const maxRows = 1 << 20
+
+// ReadFirstSheet returns the cell values of the first worksheet as text.
+// rows[i] is spreadsheet row i+1; missing rows are empty and missing cells
+// are "". Numbers are returned as stored, so dates are Excel serial day
+// numbers, and booleans as TRUE or FALSE. Styles and formulas are ignored.
+func ReadFirstSheet(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidWorkbook
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if shared, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrInvalidWorkbook
+	}
+	var sheet struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				R  string `xml:"r,attr"`
+				T  string `xml:"t,attr"`
+				V  string `xml:"v"`
+				Is rich   `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodePart(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		number := row.R
+		if number == 0 {
+			number = len(rows) + 1
+		}
+		if number > maxRows {
+			return nil, fmt.Errorf("%w: row %d beyond the sheet limit", ErrInvalidWorkbook, number)
+		}
+		for len(rows) < number {
+			rows = append(rows, nil)
+		}
+
+		var cells []string
+		for _, c := range row.Cells {
+			col := len(cells)
+			if c.R != "" {
+				if col, err = columnIndex(c.R); err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+
+			switch c.T {
+			case "s":
+				i, err := strconv.Atoi(strings.TrimSpace(c.V))
+				if err != nil || i < 0 || i >= len(shared) {
+					return nil, fmt.Errorf("%w: bad shared string in %s", ErrInvalidWorkbook, c.R)
+				}
+				cells[col] = shared[i]
+			case "inlineStr":
+				cells[col] = c.Is.text()
+			case "b":
+				if strings.TrimSpace(c.V) == "1" {
+					cells[col] = "TRUE"
+				} else {
+					cells[col] = "FALSE"
+				}
+			default:
+				cells[col] = c.V
+			}
+		}
+		rows[number-1] = cells
+	}
+
+	return rows, nil
+}
+
+// rich is a string item: plain text or runs of formatted text.
+type rich struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r rich) text() string {
+	if len(r.Runs) == 0 {
+		return r.T
+	}
+	var b strings.Builder
+	for _, run := range r.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []rich `xml:"si"`
+	}
+	if err := decodePart(f, &sst); err != nil {
+		return nil, err
+	}
+	shared := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		shared[i] = item.text()
+	}
+	return shared, nil
+}
+
+// firstSheetPath resolves the first sheet of the workbook to its part name.
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	wb, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", ErrInvalidWorkbook
+	}
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodePart(wb, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("%w: no sheets", ErrInvalidWorkbook)
+	}
+
+	rels, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "", ErrInvalidWorkbook
+	}
+	var relationships struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(rels, &relationships); err != nil {
+		return "", err
+	}
+
+	for _, rel := range relationships.Items {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("%w: first sheet not found", ErrInvalidWorkbook)
+}
+
+func decodePart(f *zip.File, v interface{}) error {
+	if f.UncompressedSize64 > maxPartSize {
+		return ErrTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return ErrInvalidWorkbook
+	}
+	defer rc.Close()
+
+	// The header size can lie; the limit is enforced on the stream too
+	limited := &io.LimitedReader{R: rc, N: maxPartSize + 1}
+	if err := xml.NewDecoder(limited).Decode(v); err != nil {
+		if limited.N <= 0 {
+			return ErrTooLarge
+		}
+		return fmt.Errorf("%w: %s: %v", ErrInvalidWorkbook, f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex returns the zero-based column of a cell reference ("C7" is 2).
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+		n++
+	}
+	if n == 0 || n > 3 {
+		return 0, fmt.Errorf("%w: bad cell reference %q", ErrInvalidWorkbook, ref)
+	}
+	return col - 1, nil
+}
@@ -0,0 +1,89 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func workbook(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const (
+	workbookXML = `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+  <sheets><sheet name="Pasien" sheetId="1" r:id="rId2"/><sheet name="Other" sheetId="2" r:id="rId1"/></sheets>
+</workbook>`
+
+	relsXML = `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/>
+  <Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet1.xml"/>
+</Relationships>`
+
+	sharedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <si><t>NIK</t></si>
+  <si><t>Nama</t></si>
+  <si><r><t>Budi </t></r><r><rPr><b/></rPr><t>Santoso</t></r></si>
+</sst>`
+
+	sheetXML = `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <sheetData>
+    <row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>Lahir</t></is></c></row>
+    <row r="3"><c r="A3" t="str"><v>3171234567890123</v></c><c r="B3" t="s"><v>2</v></c><c r="D3" t="b"><v>1</v></c><c r="C3"><v>32874</v></c></row>
+  </sheetData>
+</worksheet>`
+)
+
+func TestReadFirstSheet(t *testing.T) {
+	data := workbook(t, map[string]string{
+		"xl/workbook.xml":            workbookXML,
+		"xl/_rels/workbook.xml.rels": relsXML,
+		"xl/sharedStrings.xml":       sharedXML,
+		"xl/worksheets/sheet1.xml":   sheetXML,
+		"xl/worksheets/sheet2.xml":   `<worksheet><sheetData/></worksheet>`,
+	})
+
+	rows, err := ReadFirstSheet(data)
+	if err != nil {
+		t.Fatalf("ReadFirstSheet failed: %v", err)
+	}
+
+	want := [][]string{
+		{"NIK", "Nama", "Lahir"},
+		nil,
+		{"3171234567890123", "Budi Santoso", "32874", "TRUE"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Expected %q, got %q", want, rows)
+	}
+}
+
+func TestReadFirstSheetRejectsNonWorkbooks(t *testing.T) {
+	if _, err := ReadFirstSheet([]byte("nik,first_name\n")); !errors.Is(err, ErrInvalidWorkbook) {
+		t.Errorf("Expected ErrInvalidWorkbook for CSV data, got %v", err)
+	}
+
+	data := workbook(t, map[string]string{"xl/workbook.xml": workbookXML})
+	if _, err := ReadFirstSheet(data); !errors.Is(err, ErrInvalidWorkbook) {
+		t.Errorf("Expected ErrInvalidWorkbook without relationships, got %v", err)
+	}
+}
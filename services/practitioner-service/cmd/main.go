@@ -0,0 +1,147 @@
+// Entry point aplikasi
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/joho/godotenv"
+
+	"practitioner-service/internal/config"
+	"practitioner-service/internal/database"
+	"practitioner-service/internal/handler"
+	"practitioner-service/internal/middleware"
+	"practitioner-service/internal/repository"
+	"practitioner-service/internal/service"
+	"practitioner-service/internal/tenant"
+)
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize database
+	db, err := database.NewConnection(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	tenants, err := tenant.Load(cfg.Tenants.File, cfg.Tenants.Default)
+	if err != nil {
+		log.Fatalf("Failed to load tenants: %v", err)
+	}
+
+	// Initialize validator
+	validate := validator.New()
+
+	// Initialize repositories
+	organizationRepo := repository.NewOrganizationRepository(db)
+	practitionerRepo := repository.NewPractitionerRepository(db)
+
+	// Initialize services
+	organizationService := service.NewOrganizationService(organizationRepo, practitionerRepo)
+	practitionerService := service.NewPractitionerService(practitionerRepo, organizationRepo)
+
+	// Initialize Fiber app
+	app := fiber.New(fiber.Config{
+		ErrorHandler: customErrorHandler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	})
+
+	// Global middleware
+	app.Use(recover.New())
+	app.Use(middleware.Logger())
+	app.Use(middleware.CORS())
+	app.Use(middleware.Metrics())
+
+	// Health check endpoint
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"status":  "healthy",
+			"service": "practitioner-service",
+			"version": cfg.App.Version,
+		})
+	})
+
+	// API routes
+	api := app.Group("/api/v1")
+
+	// Protected routes
+	protected := api.Group("/", middleware.JWTAuth(cfg.JWT.Secret, tenants))
+
+	// Organization routes; the hierarchy is managed by admins
+	organizationHandler := handler.NewOrganizationHandler(organizationService, validate)
+	protected.Post("/organizations", middleware.RequireRole("admin"), organizationHandler.CreateOrganization)
+	protected.Get("/organizations", organizationHandler.ListOrganizations)
+	protected.Get("/organizations/tree", organizationHandler.GetOrganizationTree)
+	protected.Get("/organizations/:id", organizationHandler.GetOrganization)
+	protected.Put("/organizations/:id", middleware.RequireRole("admin"), organizationHandler.UpdateOrganization)
+	protected.Delete("/organizations/:id", middleware.RequireRole("admin"), organizationHandler.DeleteOrganization)
+
+	// Practitioner routes
+	licenceWarning := time.Duration(cfg.Licences.WarningDays) * 24 * time.Hour
+	practitionerHandler := handler.NewPractitionerHandler(practitionerService, validate, licenceWarning)
+	protected.Post("/practitioners", middleware.RequireRole("admin"), practitionerHandler.CreatePractitioner)
+	protected.Get("/practitioners", practitionerHandler.ListPractitioners)
+	protected.Get("/practitioners/lookup", practitionerHandler.LookupUsers)
+	protected.Get("/practitioners/:id", practitionerHandler.GetPractitioner)
+	protected.Put("/practitioners/:id", middleware.RequireRole("admin"), practitionerHandler.UpdatePractitioner)
+	protected.Post("/practitioners/:id/licences", middleware.RequireRole("admin"), practitionerHandler.AddLicence)
+	protected.Delete("/practitioners/:id/licences/:licenceId", middleware.RequireRole("admin"), practitionerHandler.RemoveLicence)
+	protected.Post("/practitioners/:id/assignments", middleware.RequireRole("admin"), practitionerHandler.AssignPractitioner)
+	protected.Post("/practitioners/:id/assignments/:assignmentId/end", middleware.RequireRole("admin"), practitionerHandler.EndAssignment)
+	protected.Get("/licences/expiring", middleware.RequireRole("admin"), practitionerHandler.ListExpiringLicences)
+
+	// Metrics endpoint (untuk Prometheus)
+	app.Get("/metrics", middleware.PrometheusHandler())
+
+	// Graceful shutdown
+	go func() {
+		if err := app.Listen(":" + cfg.App.Port); err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+	if err := app.Shutdown(); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+
+	log.Println("Server exited")
+}
+
+func customErrorHandler(c *fiber.Ctx, err error) error {
+	code := fiber.StatusInternalServerError
+	message := "Internal Server Error"
+
+	if e, ok := err.(*fiber.Error); ok {
+		code = e.Code
+		message = e.Message
+	}
+
+	return c.Status(code).JSON(fiber.Map{
+		"error": fiber.Map{
+			"message": message,
+			"code":    code,
+		},
+	})
+}
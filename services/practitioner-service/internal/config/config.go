@@ -0,0 +1,94 @@
+// Konfigurasi aplikasi
+// internal/config/config.go
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+type Config struct {
+	App      AppConfig
+	Database DatabaseConfig
+	JWT      JWTConfig
+	Licences LicenceConfig
+	Tenants  TenantConfig
+}
+
+type AppConfig struct {
+	Name    string
+	Version string
+	Port    string
+	Env     string
+}
+
+type DatabaseConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+}
+
+type JWTConfig struct {
+	Secret     string // shared with patient-service, whose tokens are accepted
+	ExpireTime int    // in hours
+}
+
+// LicenceConfig configures the SIP/STR expiry warnings
+type LicenceConfig struct {
+	WarningDays int // licences expiring within this many days are reported as expiring
+}
+
+// TenantConfig configures the hospitals and clinics sharing the service
+type TenantConfig struct {
+	File    string // JSON list of tenants; empty configures the default tenant only
+	Default string // tenant of tokens without a tenant_id claim
+}
+
+func Load() *Config {
+	return &Config{
+		App: AppConfig{
+			Name:    getEnv("APP_NAME", "practitioner-service"),
+			Version: getEnv("APP_VERSION", "1.0.0"),
+			Port:    getEnv("APP_PORT", "3003"),
+			Env:     getEnv("APP_ENV", "development"),
+		},
+		Database: DatabaseConfig{
+			Host:     getEnv("DB_HOST", "localhost"),
+			Port:     getEnv("DB_PORT", "1433"),
+			User:     getEnv("DB_USER", "sa"),
+			Password: getEnv("DB_PASSWORD", "YourStrong@Passw0rd"),
+			DBName:   getEnv("DB_NAME", "hospital_practitioner_db"),
+			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+		},
+		JWT: JWTConfig{
+			Secret:     getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			ExpireTime: getEnvAsInt("JWT_EXPIRE_HOURS", 24),
+		},
+		Licences: LicenceConfig{
+			WarningDays: getEnvAsInt("LICENCE_WARNING_DAYS", 90),
+		},
+		Tenants: TenantConfig{
+			File:    getEnv("TENANT_CONFIG_FILE", ""),
+			Default: getEnv("TENANT_DEFAULT", "default"),
+		},
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
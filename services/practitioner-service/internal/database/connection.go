@@ -0,0 +1,165 @@
+// Database connection
+// internal/database/connection.go
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"practitioner-service/internal/config"
+
+	_ "github.com/denisenkom/go-mssqldb"
+)
+
+func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
+	// Connection string untuk SQL Server
+	connString := fmt.Sprintf("server=%s;port=%s;user id=%s;password=%s;database=%s;encrypt=disable",
+		cfg.Host,
+		cfg.Port,
+		cfg.User,
+		cfg.Password,
+		cfg.DBName,
+	)
+
+	db, err := sql.Open("sqlserver", connString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Configure connection pool
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	// Test connection
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	// Create tables if not exists
+	if err := createTables(db); err != nil {
+		return nil, fmt.Errorf("failed to create tables: %w", err)
+	}
+
+	return db, nil
+}
+
+func createTables(db *sql.DB) error {
+	// Each statement runs in its own batch so later statements can reference
+	// columns added by earlier ones.
+	for _, query := range migrations {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var migrations = []string{
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='organizations' AND xtype='U')
+	CREATE TABLE organizations (
+		id NVARCHAR(50) PRIMARY KEY,
+		tenant_id NVARCHAR(50) NOT NULL,
+		parent_id NVARCHAR(50),
+		code NVARCHAR(50) NOT NULL,
+		name NVARCHAR(200) NOT NULL,
+		type NVARCHAR(20) NOT NULL,
+		is_active BIT DEFAULT 1,
+		created_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE(),
+		updated_by NVARCHAR(50),
+		updated_at DATETIME2 DEFAULT GETDATE(),
+		CONSTRAINT uq_organizations_code UNIQUE (tenant_id, code)
+	)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='idx_organizations_parent')
+	CREATE INDEX idx_organizations_parent ON organizations(tenant_id, parent_id)
+	`,
+	// Practitioners are shared by the facilities of the group; assignments
+	// place them in a facility
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='practitioners' AND xtype='U')
+	CREATE TABLE practitioners (
+		id NVARCHAR(50) PRIMARY KEY,
+		user_id NVARCHAR(50),
+		role NVARCHAR(20) NOT NULL,
+		prefix_title NVARCHAR(50),
+		first_name NVARCHAR(100) NOT NULL,
+		last_name NVARCHAR(100),
+		suffix_title NVARCHAR(100),
+		email NVARCHAR(100),
+		phone NVARCHAR(20),
+		is_active BIT DEFAULT 1,
+		created_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE(),
+		updated_by NVARCHAR(50),
+		updated_at DATETIME2 DEFAULT GETDATE()
+	)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='uq_practitioners_user_id')
+	CREATE UNIQUE INDEX uq_practitioners_user_id ON practitioners(user_id) WHERE user_id IS NOT NULL
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='practitioner_specialties' AND xtype='U')
+	CREATE TABLE practitioner_specialties (
+		practitioner_id NVARCHAR(50) NOT NULL,
+		code NVARCHAR(20) NOT NULL,
+		name NVARCHAR(100) NOT NULL,
+		CONSTRAINT pk_practitioner_specialties PRIMARY KEY (practitioner_id, code)
+	)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='idx_practitioner_specialties_code')
+	CREATE INDEX idx_practitioner_specialties_code ON practitioner_specialties(code)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='practitioner_licences' AND xtype='U')
+	CREATE TABLE practitioner_licences (
+		id NVARCHAR(50) PRIMARY KEY,
+		practitioner_id NVARCHAR(50) NOT NULL,
+		type NVARCHAR(10) NOT NULL,
+		number NVARCHAR(100) NOT NULL,
+		tenant_id NVARCHAR(50),
+		issued_at DATE NOT NULL,
+		expires_at DATE,
+		created_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE(),
+		CONSTRAINT uq_practitioner_licences_number UNIQUE (type, number)
+	)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='idx_practitioner_licences_practitioner')
+	CREATE INDEX idx_practitioner_licences_practitioner ON practitioner_licences(practitioner_id)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='idx_practitioner_licences_expires')
+	CREATE INDEX idx_practitioner_licences_expires ON practitioner_licences(expires_at) WHERE expires_at IS NOT NULL
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='practitioner_assignments' AND xtype='U')
+	CREATE TABLE practitioner_assignments (
+		id NVARCHAR(50) PRIMARY KEY,
+		practitioner_id NVARCHAR(50) NOT NULL,
+		tenant_id NVARCHAR(50) NOT NULL,
+		organization_id NVARCHAR(50) NOT NULL,
+		position NVARCHAR(100),
+		is_primary BIT DEFAULT 0,
+		start_date DATE NOT NULL,
+		end_date DATE,
+		created_by NVARCHAR(50),
+		created_at DATETIME2 DEFAULT GETDATE()
+	)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='idx_practitioner_assignments_tenant')
+	CREATE INDEX idx_practitioner_assignments_tenant ON practitioner_assignments(tenant_id, organization_id, practitioner_id)
+	`,
+	`
+	IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name='idx_practitioner_assignments_practitioner')
+	CREATE INDEX idx_practitioner_assignments_practitioner ON practitioner_assignments(practitioner_id)
+	`,
+}
@@ -0,0 +1,48 @@
+// Custom error types
+// internal/domain/errors.go
+package domain
+
+import "errors"
+
+var (
+	// Practitioner errors
+	ErrPractitionerNotFound = errors.New("practitioner not found")
+	ErrPractitionerExists   = errors.New("practitioner with this user ID already exists")
+	ErrLicenceNotFound      = errors.New("licence not found")
+	ErrLicenceExists        = errors.New("licence with this number already exists")
+	ErrAssignmentNotFound   = errors.New("assignment not found")
+
+	// Organization errors
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrOrganizationExists   = errors.New("organization with this code already exists")
+	ErrOrganizationCycle    = errors.New("organization cannot be placed under itself or its descendants")
+	ErrOrganizationInUse    = errors.New("organization has active sub-organizations or assignments")
+
+	// Tenant errors
+	ErrTenantRequired = errors.New("no tenant in context")
+
+	// General errors
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrInternalServerError = errors.New("internal server error")
+)
+
+// CustomError untuk error yang lebih detail
+type CustomError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
+}
+
+func (e *CustomError) Error() string {
+	return e.Message
+}
+
+func NewCustomError(code, message, details string) *CustomError {
+	return &CustomError{
+		Code:    code,
+		Message: message,
+		Details: details,
+	}
+}
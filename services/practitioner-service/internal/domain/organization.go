@@ -0,0 +1,79 @@
+// Department and organization hierarchy
+// internal/domain/organization.go
+package domain
+
+import "time"
+
+// Organization types
+const (
+	OrganizationDivision   = "division"   // e.g. Bidang Pelayanan Medis
+	OrganizationDepartment = "department" // e.g. Departemen Penyakit Dalam, Instalasi Rawat Jalan
+	OrganizationUnit       = "unit"       // e.g. a clinic (poli) or ward
+)
+
+// Organization is a division, department or unit of a facility. Parents
+// make up the facility's hierarchy; top level organizations have none.
+type Organization struct {
+	ID       string
+	TenantID string
+	ParentID string
+	Code     string // unique within the facility
+	Name     string
+	Type     string
+	IsActive bool
+
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+// OrganizationNode is an organization with its sub-organizations.
+type OrganizationNode struct {
+	*Organization
+	Children []*OrganizationNode
+}
+
+// OrganizationTree arranges the organizations of a facility as a tree, in
+// the order given. Organizations whose parent is not among them are placed
+// at the top level.
+func OrganizationTree(orgs []*Organization) []*OrganizationNode {
+	nodes := make(map[string]*OrganizationNode, len(orgs))
+	for _, org := range orgs {
+		nodes[org.ID] = &OrganizationNode{Organization: org, Children: []*OrganizationNode{}}
+	}
+
+	roots := []*OrganizationNode{}
+	for _, org := range orgs {
+		node := nodes[org.ID]
+		if parent, ok := nodes[org.ParentID]; ok && org.ParentID != org.ID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// Descendants returns the ID of the organization and those of all
+// organizations below it.
+func Descendants(orgs []*Organization, id string) []string {
+	children := make(map[string][]string)
+	for _, org := range orgs {
+		if org.ParentID != "" {
+			children[org.ParentID] = append(children[org.ParentID], org.ID)
+		}
+	}
+
+	ids := []string{id}
+	seen := map[string]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
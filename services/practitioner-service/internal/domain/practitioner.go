@@ -0,0 +1,184 @@
+// Practitioners, their licences and facility assignments
+// internal/domain/practitioner.go
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Practitioner roles
+const (
+	RoleDoctor     = "doctor"
+	RoleNurse      = "nurse"
+	RoleMidwife    = "midwife"
+	RolePharmacist = "pharmacist"
+	RoleStaff      = "staff"
+)
+
+// Licence types
+const (
+	// LicenceSTR is the registration certificate (Surat Tanda Registrasi),
+	// valid nationwide. STRs issued since UU 17/2023 do not expire.
+	LicenceSTR = "STR"
+	// LicenceSIP is the practice permit (Surat Izin Praktik), issued for
+	// practice at one facility.
+	LicenceSIP = "SIP"
+)
+
+// Licence statuses, see Licence.Status
+const (
+	LicenceActive   = "active"
+	LicenceExpiring = "expiring"
+	LicenceExpired  = "expired"
+)
+
+// Practitioner is a doctor, nurse or other member of staff of the group.
+// Practitioners are shared by all facilities; Assignments place them in
+// the departments of a facility.
+type Practitioner struct {
+	ID          string
+	UserID      string // login used in created_by/updated_by of other services; empty without one
+	Role        string
+	PrefixTitle string // e.g. "dr." or "Ns."
+	FirstName   string
+	LastName    string
+	SuffixTitle string // e.g. "Sp.PD" or "S.Kep"
+	Email       string
+	Phone       string
+	IsActive    bool
+
+	Specialties []Specialty
+	Licences    []*Licence
+	Assignments []*Assignment
+
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+// Name returns the practitioner's name without titles.
+func (p *Practitioner) Name() string {
+	return strings.TrimSpace(p.FirstName + " " + p.LastName)
+}
+
+// DisplayName returns the name with titles as written in Indonesia, e.g.
+// "dr. Budi Santoso, Sp.PD".
+func (p *Practitioner) DisplayName() string {
+	name := p.Name()
+	if p.PrefixTitle != "" {
+		name = p.PrefixTitle + " " + name
+	}
+	if p.SuffixTitle != "" {
+		name += ", " + p.SuffixTitle
+	}
+	return name
+}
+
+// Specialty is a practitioner's specialty, e.g. Sp.PD (penyakit dalam).
+type Specialty struct {
+	Code string
+	Name string
+}
+
+// Licence is a practitioner's STR or SIP.
+type Licence struct {
+	ID             string
+	PractitionerID string
+	Type           string
+	Number         string
+	TenantID       string // facility a SIP is issued for; empty for STRs
+	IssuedAt       time.Time
+	ExpiresAt      *time.Time // date, inclusive; nil for licences that do not expire
+	CreatedBy      string
+	CreatedAt      time.Time
+}
+
+// Validate checks the licence's values.
+func (l *Licence) Validate() error {
+	if l.Type != LicenceSTR && l.Type != LicenceSIP {
+		return NewCustomError("INVALID_LICENCE_TYPE", "type must be STR or SIP", "")
+	}
+	if l.Type == LicenceSIP && l.TenantID == "" {
+		return NewCustomError("INVALID_LICENCE", "A SIP is issued for a facility", "")
+	}
+	if l.ExpiresAt != nil && l.ExpiresAt.Before(l.IssuedAt) {
+		return NewCustomError("INVALID_LICENCE", "expires_at must not be before issued_at", "")
+	}
+	return nil
+}
+
+// Status returns whether the licence is active, expired, or expires within
+// warning of now.
+func (l *Licence) Status(now time.Time, warning time.Duration) string {
+	if l.ExpiresAt == nil {
+		return LicenceActive
+	}
+	today := civilDate(now)
+	expires := civilDate(*l.ExpiresAt)
+	switch {
+	case expires.Before(today):
+		return LicenceExpired
+	case !expires.After(civilDate(now.Add(warning))):
+		return LicenceExpiring
+	}
+	return LicenceActive
+}
+
+// ExpiringLicence is a licence about to expire, with its holder.
+type ExpiringLicence struct {
+	*Licence
+	PractitionerName string // display name
+	Role             string
+}
+
+// Assignment places a practitioner in an organization of a facility.
+type Assignment struct {
+	ID             string
+	PractitionerID string
+	TenantID       string
+	OrganizationID string
+	Position       string // e.g. "Kepala Ruangan"
+	IsPrimary      bool
+	StartDate      time.Time  // date
+	EndDate        *time.Time // date, inclusive; nil while ongoing
+	CreatedBy      string
+	CreatedAt      time.Time
+}
+
+// ActiveOn reports whether the assignment covers the date.
+func (a *Assignment) ActiveOn(date time.Time) bool {
+	day := civilDate(date)
+	if day.Before(civilDate(a.StartDate)) {
+		return false
+	}
+	return a.EndDate == nil || !day.After(civilDate(*a.EndDate))
+}
+
+// PractitionerFilter selects the practitioners assigned to the tenant of
+// the context.
+type PractitionerFilter struct {
+	Role            string
+	Specialty       string   // specialty code
+	OrganizationIDs []string // any of them; empty for all
+	Query           string   // part of the name
+	IncludeInactive bool
+	Page            int
+	Limit           int
+}
+
+// UserName is a practitioner's name, as resolved from their user ID.
+type UserName struct {
+	UserID         string
+	PractitionerID string
+	Name           string
+	DisplayName    string
+	Role           string
+}
+
+// civilDate returns the date of t at midnight UTC, so dates compare
+// regardless of the timezone they were parsed in.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,60 @@
+// internal/dto/request.go
+package dto
+
+type SpecialtyRequest struct {
+	Code string `json:"code" validate:"required,max=20"`
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type PractitionerRequest struct {
+	UserID      string             `json:"user_id" validate:"max=50"`
+	Role        string             `json:"role" validate:"required,oneof=doctor nurse midwife pharmacist staff"`
+	PrefixTitle string             `json:"prefix_title" validate:"max=50"`
+	FirstName   string             `json:"first_name" validate:"required,min=1,max=100"`
+	LastName    string             `json:"last_name" validate:"max=100"`
+	SuffixTitle string             `json:"suffix_title" validate:"max=100"`
+	Email       string             `json:"email" validate:"omitempty,email,max=100"`
+	Phone       string             `json:"phone" validate:"omitempty,max=20"`
+	Specialties []SpecialtyRequest `json:"specialties" validate:"max=20,dive"`
+}
+
+type UpdatePractitionerRequest struct {
+	PractitionerRequest
+	IsActive *bool `json:"is_active"`
+}
+
+type ListPractitionersRequest struct {
+	Role            string `query:"role" validate:"omitempty,oneof=doctor nurse midwife pharmacist staff"`
+	Specialty       string `query:"specialty" validate:"max=20"`
+	OrganizationID  string `query:"organization_id" validate:"max=50"`
+	Query           string `query:"q" validate:"max=100"`
+	IncludeInactive bool   `query:"include_inactive"`
+	Page            int    `query:"page" validate:"omitempty,min=1"`
+	Limit           int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type LicenceRequest struct {
+	Type      string `json:"type" validate:"required,oneof=STR SIP"`
+	Number    string `json:"number" validate:"required,max=100"`
+	IssuedAt  string `json:"issued_at" validate:"required,datetime=2006-01-02"`
+	ExpiresAt string `json:"expires_at" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type AssignmentRequest struct {
+	OrganizationID string `json:"organization_id" validate:"required,max=50"`
+	Position       string `json:"position" validate:"max=100"`
+	IsPrimary      bool   `json:"is_primary"`
+	StartDate      string `json:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate        string `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type EndAssignmentRequest struct {
+	EndDate string `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type OrganizationRequest struct {
+	ParentID string `json:"parent_id" validate:"max=50"`
+	Code     string `json:"code" validate:"required,max=50"`
+	Name     string `json:"name" validate:"required,max=200"`
+	Type     string `json:"type" validate:"required,oneof=division department unit"`
+}
@@ -0,0 +1,312 @@
+// internal/dto/response.go
+package dto
+
+import (
+	"math"
+	"time"
+
+	"practitioner-service/internal/domain"
+)
+
+const dateLayout = "2006-01-02"
+
+type SpecialtyResponse struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type LicenceResponse struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Number    string    `json:"number"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	IssuedAt  string    `json:"issued_at"`
+	ExpiresAt string    `json:"expires_at,omitempty"`
+	Status    string    `json:"status"` // active, expiring or expired
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AssignmentResponse struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	OrganizationID string    `json:"organization_id"`
+	Position       string    `json:"position"`
+	IsPrimary      bool      `json:"is_primary"`
+	StartDate      string    `json:"start_date"`
+	EndDate        string    `json:"end_date,omitempty"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type PractitionerResponse struct {
+	ID          string                `json:"id"`
+	UserID      string                `json:"user_id,omitempty"`
+	Role        string                `json:"role"`
+	PrefixTitle string                `json:"prefix_title"`
+	FirstName   string                `json:"first_name"`
+	LastName    string                `json:"last_name"`
+	SuffixTitle string                `json:"suffix_title"`
+	DisplayName string                `json:"display_name"`
+	Email       string                `json:"email"`
+	Phone       string                `json:"phone"`
+	IsActive    bool                  `json:"is_active"`
+	Specialties []SpecialtyResponse   `json:"specialties"`
+	Licences    []*LicenceResponse    `json:"licences,omitempty"`
+	Assignments []*AssignmentResponse `json:"assignments,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+type ListPractitionersResponse struct {
+	Data       []*PractitionerResponse `json:"data"`
+	Pagination PaginationResponse      `json:"pagination"`
+}
+
+type ExpiringLicenceResponse struct {
+	LicenceResponse
+	PractitionerID   string `json:"practitioner_id"`
+	PractitionerName string `json:"practitioner_name"`
+	Role             string `json:"role"`
+}
+
+type ListExpiringLicencesResponse struct {
+	Data []*ExpiringLicenceResponse `json:"data"`
+}
+
+type UserNameResponse struct {
+	UserID         string `json:"user_id"`
+	PractitionerID string `json:"practitioner_id"`
+	Name           string `json:"name"`
+	DisplayName    string `json:"display_name"`
+	Role           string `json:"role"`
+}
+
+// LookupUsersResponse maps the user IDs of practitioners to their names;
+// other user IDs are left out
+type LookupUsersResponse struct {
+	Data map[string]*UserNameResponse `json:"data"`
+}
+
+type OrganizationResponse struct {
+	ID        string    `json:"id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListOrganizationsResponse struct {
+	Data []*OrganizationResponse `json:"data"`
+}
+
+type OrganizationNodeResponse struct {
+	OrganizationResponse
+	Children []*OrganizationNodeResponse `json:"children"`
+}
+
+type OrganizationTreeResponse struct {
+	Data []*OrganizationNodeResponse `json:"data"`
+}
+
+type PaginationResponse struct {
+	Page       int `json:"page"`
+	Limit      int `json:"limit"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
+}
+
+type SuccessResponse struct {
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Converter functions
+func ToPractitionerDomain(req *PractitionerRequest) *domain.Practitioner {
+	practitioner := &domain.Practitioner{
+		UserID:      req.UserID,
+		Role:        req.Role,
+		PrefixTitle: req.PrefixTitle,
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		SuffixTitle: req.SuffixTitle,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Specialties: []domain.Specialty{},
+	}
+	seen := make(map[string]bool)
+	for _, specialty := range req.Specialties {
+		if !seen[specialty.Code] {
+			seen[specialty.Code] = true
+			practitioner.Specialties = append(practitioner.Specialties, domain.Specialty{Code: specialty.Code, Name: specialty.Name})
+		}
+	}
+	return practitioner
+}
+
+// ToPractitionerResponse converts the practitioner; licence statuses are
+// as of now, with licences expiring within warning reported as expiring.
+func ToPractitionerResponse(practitioner *domain.Practitioner, now time.Time, warning time.Duration) *PractitionerResponse {
+	resp := &PractitionerResponse{
+		ID:          practitioner.ID,
+		UserID:      practitioner.UserID,
+		Role:        practitioner.Role,
+		PrefixTitle: practitioner.PrefixTitle,
+		FirstName:   practitioner.FirstName,
+		LastName:    practitioner.LastName,
+		SuffixTitle: practitioner.SuffixTitle,
+		DisplayName: practitioner.DisplayName(),
+		Email:       practitioner.Email,
+		Phone:       practitioner.Phone,
+		IsActive:    practitioner.IsActive,
+		Specialties: make([]SpecialtyResponse, 0, len(practitioner.Specialties)),
+		CreatedAt:   practitioner.CreatedAt,
+		UpdatedAt:   practitioner.UpdatedAt,
+	}
+	for _, specialty := range practitioner.Specialties {
+		resp.Specialties = append(resp.Specialties, SpecialtyResponse{Code: specialty.Code, Name: specialty.Name})
+	}
+	for _, licence := range practitioner.Licences {
+		resp.Licences = append(resp.Licences, ToLicenceResponse(licence, now, warning))
+	}
+	for _, assignment := range practitioner.Assignments {
+		resp.Assignments = append(resp.Assignments, ToAssignmentResponse(assignment, now))
+	}
+	return resp
+}
+
+func ToLicenceDomain(req *LicenceRequest) *domain.Licence {
+	licence := &domain.Licence{
+		Type:   req.Type,
+		Number: req.Number,
+	}
+	licence.IssuedAt, _ = time.Parse(dateLayout, req.IssuedAt)
+	if expires, err := time.Parse(dateLayout, req.ExpiresAt); err == nil {
+		licence.ExpiresAt = &expires
+	}
+	return licence
+}
+
+func ToLicenceResponse(licence *domain.Licence, now time.Time, warning time.Duration) *LicenceResponse {
+	resp := &LicenceResponse{
+		ID:        licence.ID,
+		Type:      licence.Type,
+		Number:    licence.Number,
+		TenantID:  licence.TenantID,
+		IssuedAt:  licence.IssuedAt.Format(dateLayout),
+		Status:    licence.Status(now, warning),
+		CreatedAt: licence.CreatedAt,
+	}
+	if licence.ExpiresAt != nil {
+		resp.ExpiresAt = licence.ExpiresAt.Format(dateLayout)
+	}
+	return resp
+}
+
+func ToExpiringLicenceResponse(licence *domain.ExpiringLicence, now time.Time, warning time.Duration) *ExpiringLicenceResponse {
+	return &ExpiringLicenceResponse{
+		LicenceResponse:  *ToLicenceResponse(licence.Licence, now, warning),
+		PractitionerID:   licence.PractitionerID,
+		PractitionerName: licence.PractitionerName,
+		Role:             licence.Role,
+	}
+}
+
+func ToAssignmentDomain(req *AssignmentRequest) *domain.Assignment {
+	assignment := &domain.Assignment{
+		OrganizationID: req.OrganizationID,
+		Position:       req.Position,
+		IsPrimary:      req.IsPrimary,
+	}
+	if start, err := time.Parse(dateLayout, req.StartDate); err == nil {
+		assignment.StartDate = start
+	} else {
+		now := time.Now()
+		assignment.StartDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if end, err := time.Parse(dateLayout, req.EndDate); err == nil {
+		assignment.EndDate = &end
+	}
+	return assignment
+}
+
+func ToAssignmentResponse(assignment *domain.Assignment, now time.Time) *AssignmentResponse {
+	resp := &AssignmentResponse{
+		ID:             assignment.ID,
+		TenantID:       assignment.TenantID,
+		OrganizationID: assignment.OrganizationID,
+		Position:       assignment.Position,
+		IsPrimary:      assignment.IsPrimary,
+		StartDate:      assignment.StartDate.Format(dateLayout),
+		IsActive:       assignment.ActiveOn(now),
+		CreatedAt:      assignment.CreatedAt,
+	}
+	if assignment.EndDate != nil {
+		resp.EndDate = assignment.EndDate.Format(dateLayout)
+	}
+	return resp
+}
+
+func ToUserNameResponse(name *domain.UserName) *UserNameResponse {
+	return &UserNameResponse{
+		UserID:         name.UserID,
+		PractitionerID: name.PractitionerID,
+		Name:           name.Name,
+		DisplayName:    name.DisplayName,
+		Role:           name.Role,
+	}
+}
+
+func ToOrganizationDomain(req *OrganizationRequest) *domain.Organization {
+	return &domain.Organization{
+		ParentID: req.ParentID,
+		Code:     req.Code,
+		Name:     req.Name,
+		Type:     req.Type,
+	}
+}
+
+func ToOrganizationResponse(org *domain.Organization) *OrganizationResponse {
+	return &OrganizationResponse{
+		ID:        org.ID,
+		ParentID:  org.ParentID,
+		Code:      org.Code,
+		Name:      org.Name,
+		Type:      org.Type,
+		IsActive:  org.IsActive,
+		CreatedAt: org.CreatedAt,
+		UpdatedAt: org.UpdatedAt,
+	}
+}
+
+func ToOrganizationNodeResponse(node *domain.OrganizationNode) *OrganizationNodeResponse {
+	resp := &OrganizationNodeResponse{
+		OrganizationResponse: *ToOrganizationResponse(node.Organization),
+		Children:             make([]*OrganizationNodeResponse, 0, len(node.Children)),
+	}
+	for _, child := range node.Children {
+		resp.Children = append(resp.Children, ToOrganizationNodeResponse(child))
+	}
+	return resp
+}
+
+func NewPaginationResponse(page, limit, total int) PaginationResponse {
+	return PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}
+}
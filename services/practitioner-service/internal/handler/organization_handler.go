@@ -0,0 +1,205 @@
+// Department and organization handlers
+// internal/handler/organization_handler.go
+package handler
+
+import (
+	"practitioner-service/internal/domain"
+	"practitioner-service/internal/dto"
+	"practitioner-service/internal/service"
+	"practitioner-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrganizationHandler struct {
+	organizationService service.OrganizationService
+	validator           *validator.Validate
+}
+
+func NewOrganizationHandler(organizationService service.OrganizationService, validator *validator.Validate) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+		validator:           validator,
+	}
+}
+
+// CreateOrganization godoc
+// @Summary Create an organization
+// @Description Add a division, department or unit to the caller's facility, optionally below another one.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.OrganizationRequest true "Organization"
+// @Success 201 {object} dto.OrganizationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c *fiber.Ctx) error {
+	var req dto.OrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	org := dto.ToOrganizationDomain(&req)
+	org.CreatedBy = c.Locals("userID").(string)
+
+	created, err := h.organizationService.CreateOrganization(c.Context(), org)
+	if err != nil {
+		return h.error(c, err, "CREATE_FAILED", "Failed to create organization")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToOrganizationResponse(created))
+}
+
+// ListOrganizations godoc
+// @Summary List organizations
+// @Description The organizations of the caller's facility ordered by name; parent_id links them into the hierarchy.
+// @Tags organizations
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param include_inactive query bool false "Include deactivated organizations"
+// @Success 200 {object} dto.ListOrganizationsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/organizations [get]
+func (h *OrganizationHandler) ListOrganizations(c *fiber.Ctx) error {
+	orgs, err := h.organizationService.ListOrganizations(c.Context(), c.QueryBool("include_inactive"))
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list organizations")
+	}
+
+	resp := dto.ListOrganizationsResponse{Data: make([]*dto.OrganizationResponse, 0, len(orgs))}
+	for _, org := range orgs {
+		resp.Data = append(resp.Data, dto.ToOrganizationResponse(org))
+	}
+	return c.JSON(resp)
+}
+
+// GetOrganizationTree godoc
+// @Summary Get the organization hierarchy
+// @Description The active organizations of the caller's facility as a tree.
+// @Tags organizations
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.OrganizationTreeResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/organizations/tree [get]
+func (h *OrganizationHandler) GetOrganizationTree(c *fiber.Ctx) error {
+	roots, err := h.organizationService.OrganizationTree(c.Context())
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to get organization tree")
+	}
+
+	resp := dto.OrganizationTreeResponse{Data: make([]*dto.OrganizationNodeResponse, 0, len(roots))}
+	for _, root := range roots {
+		resp.Data = append(resp.Data, dto.ToOrganizationNodeResponse(root))
+	}
+	return c.JSON(resp)
+}
+
+// GetOrganization godoc
+// @Summary Get an organization
+// @Tags organizations
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Organization ID"
+// @Success 200 {object} dto.OrganizationResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/organizations/{id} [get]
+func (h *OrganizationHandler) GetOrganization(c *fiber.Ctx) error {
+	org, err := h.organizationService.GetOrganization(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get organization")
+	}
+	return c.JSON(dto.ToOrganizationResponse(org))
+}
+
+// UpdateOrganization godoc
+// @Summary Update an organization
+// @Description Rename an organization or move it below another one. An organization cannot be moved below itself or its sub-organizations.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Organization ID"
+// @Param request body dto.OrganizationRequest true "Organization"
+// @Success 200 {object} dto.OrganizationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/organizations/{id} [put]
+func (h *OrganizationHandler) UpdateOrganization(c *fiber.Ctx) error {
+	var req dto.OrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	org := dto.ToOrganizationDomain(&req)
+	org.ID = c.Params("id")
+	org.UpdatedBy = c.Locals("userID").(string)
+
+	updated, err := h.organizationService.UpdateOrganization(c.Context(), org)
+	if err != nil {
+		return h.error(c, err, "UPDATE_FAILED", "Failed to update organization")
+	}
+	return c.JSON(dto.ToOrganizationResponse(updated))
+}
+
+// DeleteOrganization godoc
+// @Summary Deactivate an organization
+// @Description Organizations with active sub-organizations or practitioners assigned cannot be deactivated.
+// @Tags organizations
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Organization ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/organizations/{id} [delete]
+func (h *OrganizationHandler) DeleteOrganization(c *fiber.Ctx) error {
+	if err := h.organizationService.DeactivateOrganization(c.Context(), c.Params("id"), c.Locals("userID").(string)); err != nil {
+		return h.error(c, err, "DELETE_FAILED", "Failed to delete organization")
+	}
+	return utils.SuccessResponse(c, "Organization deactivated successfully", nil)
+}
+
+func (h *OrganizationHandler) error(c *fiber.Ctx, err error, code, message string) error {
+	switch err {
+	case domain.ErrOrganizationNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Organization not found", "")
+	case domain.ErrOrganizationExists:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "ORGANIZATION_EXISTS", "Organization with this code already exists", "")
+	case domain.ErrOrganizationCycle:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_PARENT", "Organization cannot be placed below itself or its sub-organizations", "")
+	case domain.ErrOrganizationInUse:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "ORGANIZATION_IN_USE", "Organization has active sub-organizations or assignments", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Organization ID is required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, code, message, err.Error())
+}
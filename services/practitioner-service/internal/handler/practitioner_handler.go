@@ -0,0 +1,424 @@
+// Practitioner directory handlers
+// internal/handler/practitioner_handler.go
+package handler
+
+import (
+	"strings"
+	"time"
+
+	"practitioner-service/internal/domain"
+	"practitioner-service/internal/dto"
+	"practitioner-service/internal/service"
+	"practitioner-service/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PractitionerHandler struct {
+	practitionerService service.PractitionerService
+	validator           *validator.Validate
+	licenceWarning      time.Duration
+}
+
+// NewPractitionerHandler creates the handler; licences expiring within
+// licenceWarning are reported as expiring.
+func NewPractitionerHandler(practitionerService service.PractitionerService, validator *validator.Validate, licenceWarning time.Duration) *PractitionerHandler {
+	return &PractitionerHandler{
+		practitionerService: practitionerService,
+		validator:           validator,
+		licenceWarning:      licenceWarning,
+	}
+}
+
+// CreatePractitioner godoc
+// @Summary Create a practitioner
+// @Description Add a doctor, nurse or other member of staff to the directory. user_id links the practitioner to their login, so created_by and updated_by of other services resolve to their name.
+// @Tags practitioners
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.PractitionerRequest true "Practitioner"
+// @Success 201 {object} dto.PractitionerResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/practitioners [post]
+func (h *PractitionerHandler) CreatePractitioner(c *fiber.Ctx) error {
+	var req dto.PractitionerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	practitioner := dto.ToPractitionerDomain(&req)
+	practitioner.CreatedBy = c.Locals("userID").(string)
+
+	created, err := h.practitionerService.CreatePractitioner(c.Context(), practitioner)
+	if err != nil {
+		return h.error(c, err, "CREATE_FAILED", "Failed to create practitioner")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToPractitionerResponse(created, time.Now(), h.licenceWarning))
+}
+
+// ListPractitioners godoc
+// @Summary List practitioners
+// @Description Practitioners assigned to the caller's facility, ordered by name. Filtering by an organization includes the organizations below it.
+// @Tags practitioners
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param role query string false "doctor, nurse, midwife, pharmacist or staff"
+// @Param specialty query string false "Specialty code, e.g. Sp.PD"
+// @Param organization_id query string false "Organization ID"
+// @Param q query string false "Part of the name"
+// @Param include_inactive query bool false "Include deactivated practitioners"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} dto.ListPractitionersResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/practitioners [get]
+func (h *PractitionerHandler) ListPractitioners(c *fiber.Ctx) error {
+	var req dto.ListPractitionersRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	filter := domain.PractitionerFilter{
+		Role:            req.Role,
+		Specialty:       req.Specialty,
+		Query:           strings.TrimSpace(req.Query),
+		IncludeInactive: req.IncludeInactive,
+		Page:            req.Page,
+		Limit:           req.Limit,
+	}
+	if req.OrganizationID != "" {
+		filter.OrganizationIDs = []string{req.OrganizationID}
+	}
+
+	practitioners, total, err := h.practitionerService.ListPractitioners(c.Context(), filter)
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list practitioners")
+	}
+
+	page, limit := filter.Page, filter.Limit
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	now := time.Now()
+	resp := dto.ListPractitionersResponse{
+		Data:       make([]*dto.PractitionerResponse, 0, len(practitioners)),
+		Pagination: dto.NewPaginationResponse(page, limit, total),
+	}
+	for _, practitioner := range practitioners {
+		resp.Data = append(resp.Data, dto.ToPractitionerResponse(practitioner, now, h.licenceWarning))
+	}
+	return c.JSON(resp)
+}
+
+// LookupUsers godoc
+// @Summary Resolve user IDs to names
+// @Description Map user IDs, such as created_by and updated_by of other services, to the names of the practitioners they belong to. User IDs of no practitioner are left out. Practitioners of all facilities are included.
+// @Tags practitioners
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param user_ids query string true "Comma separated user IDs, at most 100"
+// @Success 200 {object} dto.LookupUsersResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/practitioners/lookup [get]
+func (h *PractitionerHandler) LookupUsers(c *fiber.Ctx) error {
+	var userIDs []string
+	for _, id := range strings.Split(c.Query("user_ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			userIDs = append(userIDs, id)
+		}
+	}
+	if len(userIDs) == 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "user_ids is required", "")
+	}
+
+	names, err := h.practitionerService.LookupUsers(c.Context(), userIDs)
+	if err != nil {
+		return h.error(c, err, "LOOKUP_FAILED", "Failed to look up users")
+	}
+
+	resp := dto.LookupUsersResponse{Data: make(map[string]*dto.UserNameResponse, len(names))}
+	for _, name := range names {
+		resp.Data[name.UserID] = dto.ToUserNameResponse(name)
+	}
+	return c.JSON(resp)
+}
+
+// GetPractitioner godoc
+// @Summary Get a practitioner
+// @Description The practitioner with their specialties, licences and assignments at all facilities.
+// @Tags practitioners
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Practitioner ID"
+// @Success 200 {object} dto.PractitionerResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/practitioners/{id} [get]
+func (h *PractitionerHandler) GetPractitioner(c *fiber.Ctx) error {
+	practitioner, err := h.practitionerService.GetPractitioner(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "GET_FAILED", "Failed to get practitioner")
+	}
+	return c.JSON(dto.ToPractitionerResponse(practitioner, time.Now(), h.licenceWarning))
+}
+
+// UpdatePractitioner godoc
+// @Summary Update a practitioner
+// @Description Replace the practitioner's profile and specialties. is_active may be left out to keep it unchanged.
+// @Tags practitioners
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Practitioner ID"
+// @Param request body dto.UpdatePractitionerRequest true "Practitioner"
+// @Success 200 {object} dto.PractitionerResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/practitioners/{id} [put]
+func (h *PractitionerHandler) UpdatePractitioner(c *fiber.Ctx) error {
+	var req dto.UpdatePractitionerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	existing, err := h.practitionerService.GetPractitioner(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "UPDATE_FAILED", "Failed to update practitioner")
+	}
+
+	practitioner := dto.ToPractitionerDomain(&req.PractitionerRequest)
+	practitioner.ID = existing.ID
+	practitioner.IsActive = existing.IsActive
+	if req.IsActive != nil {
+		practitioner.IsActive = *req.IsActive
+	}
+	practitioner.UpdatedBy = c.Locals("userID").(string)
+
+	updated, err := h.practitionerService.UpdatePractitioner(c.Context(), practitioner)
+	if err != nil {
+		return h.error(c, err, "UPDATE_FAILED", "Failed to update practitioner")
+	}
+	return c.JSON(dto.ToPractitionerResponse(updated, time.Now(), h.licenceWarning))
+}
+
+// AddLicence godoc
+// @Summary Add a licence
+// @Description Record an STR or SIP. A SIP is recorded for the caller's facility. expires_at may be left out for STRs that do not expire.
+// @Tags practitioners
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Practitioner ID"
+// @Param request body dto.LicenceRequest true "Licence"
+// @Success 201 {object} dto.LicenceResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/practitioners/{id}/licences [post]
+func (h *PractitionerHandler) AddLicence(c *fiber.Ctx) error {
+	var req dto.LicenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	licence := dto.ToLicenceDomain(&req)
+	licence.PractitionerID = c.Params("id")
+	licence.CreatedBy = c.Locals("userID").(string)
+
+	added, err := h.practitionerService.AddLicence(c.Context(), licence)
+	if err != nil {
+		return h.error(c, err, "ADD_FAILED", "Failed to add licence")
+	}
+	return c.Status(fiber.StatusCreated).JSON(dto.ToLicenceResponse(added, time.Now(), h.licenceWarning))
+}
+
+// RemoveLicence godoc
+// @Summary Remove a licence
+// @Description Remove a licence recorded in error. SIPs of other facilities cannot be removed.
+// @Tags practitioners
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Practitioner ID"
+// @Param licenceId path string true "Licence ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/practitioners/{id}/licences/{licenceId} [delete]
+func (h *PractitionerHandler) RemoveLicence(c *fiber.Ctx) error {
+	if err := h.practitionerService.RemoveLicence(c.Context(), c.Params("id"), c.Params("licenceId")); err != nil {
+		return h.error(c, err, "DELETE_FAILED", "Failed to remove licence")
+	}
+	return utils.SuccessResponse(c, "Licence removed successfully", nil)
+}
+
+// ListExpiringLicences godoc
+// @Summary List expiring licences
+// @Description STRs and this facility's SIPs of the practitioners assigned to the caller's facility that have expired or expire within days, soonest first.
+// @Tags licences
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param days query int false "Days ahead; defaults to LICENCE_WARNING_DAYS"
+// @Success 200 {object} dto.ListExpiringLicencesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/licences/expiring [get]
+func (h *PractitionerHandler) ListExpiringLicences(c *fiber.Ctx) error {
+	within := h.licenceWarning
+	if days := c.QueryInt("days", -1); days >= 0 {
+		within = time.Duration(days) * 24 * time.Hour
+	}
+
+	licences, err := h.practitionerService.ExpiringLicences(c.Context(), within)
+	if err != nil {
+		return h.error(c, err, "LIST_FAILED", "Failed to list expiring licences")
+	}
+
+	now := time.Now()
+	resp := dto.ListExpiringLicencesResponse{Data: make([]*dto.ExpiringLicenceResponse, 0, len(licences))}
+	for _, licence := range licences {
+		resp.Data = append(resp.Data, dto.ToExpiringLicenceResponse(licence, now, h.licenceWarning))
+	}
+	return c.JSON(resp)
+}
+
+// AssignPractitioner godoc
+// @Summary Assign a practitioner
+// @Description Place the practitioner in an organization of the caller's facility. start_date defaults to today.
+// @Tags practitioners
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Practitioner ID"
+// @Param request body dto.AssignmentRequest true "Assignment"
+// @Success 201 {object} dto.AssignmentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/practitioners/{id}/assignments [post]
+func (h *PractitionerHandler) AssignPractitioner(c *fiber.Ctx) error {
+	var req dto.AssignmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	assignment := dto.ToAssignmentDomain(&req)
+	assignment.PractitionerID = c.Params("id")
+	assignment.CreatedBy = c.Locals("userID").(string)
+
+	added, err := h.practitionerService.Assign(c.Context(), assignment)
+	if err != nil {
+		return h.error(c, err, "ASSIGN_FAILED", "Failed to assign practitioner")
+	}
+	return c.Status(fiber.StatusCreated).JSON(dto.ToAssignmentResponse(added, time.Now()))
+}
+
+// EndAssignment godoc
+// @Summary End an assignment
+// @Description End an assignment at the caller's facility on end_date, which defaults to today.
+// @Tags practitioners
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "Practitioner ID"
+// @Param assignmentId path string true "Assignment ID"
+// @Param request body dto.EndAssignmentRequest false "End date"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/practitioners/{id}/assignments/{assignmentId}/end [post]
+func (h *PractitionerHandler) EndAssignment(c *fiber.Ctx) error {
+	var req dto.EndAssignmentRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+		}
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		now := time.Now()
+		endDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	if err := h.practitionerService.EndAssignment(c.Context(), c.Params("id"), c.Params("assignmentId"), endDate); err != nil {
+		return h.error(c, err, "END_FAILED", "Failed to end assignment")
+	}
+	return utils.SuccessResponse(c, "Assignment ended successfully", nil)
+}
+
+func (h *PractitionerHandler) error(c *fiber.Ctx, err error, code, message string) error {
+	switch err {
+	case domain.ErrPractitionerNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "NOT_FOUND", "Practitioner not found", "")
+	case domain.ErrPractitionerExists:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "PRACTITIONER_EXISTS", "Practitioner with this user ID already exists", "")
+	case domain.ErrLicenceNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "LICENCE_NOT_FOUND", "Licence not found", "")
+	case domain.ErrLicenceExists:
+		return utils.ErrorResponse(c, fiber.StatusConflict, "LICENCE_EXISTS", "Licence with this number already exists", "")
+	case domain.ErrAssignmentNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ASSIGNMENT_NOT_FOUND", "Assignment not found", "")
+	case domain.ErrInvalidInput:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "INVALID_ID", "Practitioner, licence and assignment IDs are required", "")
+	}
+	if customErr, ok := err.(*domain.CustomError); ok {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, customErr.Code, customErr.Message, customErr.Details)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, code, message, err.Error())
+}
@@ -0,0 +1,66 @@
+// Repository interfaces
+// internal/repository/interfaces.go
+package repository
+
+import (
+	"context"
+	"practitioner-service/internal/domain"
+	"time"
+)
+
+// OrganizationRepository stores the organizations of the tenant of the
+// context.
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *domain.Organization) error
+	GetByID(ctx context.Context, id string) (*domain.Organization, error)
+	GetByCode(ctx context.Context, code string) (*domain.Organization, error)
+
+	// List returns the facility's organizations ordered by name
+	List(ctx context.Context, includeInactive bool) ([]*domain.Organization, error)
+	Update(ctx context.Context, org *domain.Organization) error
+	Deactivate(ctx context.Context, id, updatedBy string) error
+}
+
+// PractitionerRepository stores practitioners, who are shared by all
+// tenants, and their licences and assignments.
+type PractitionerRepository interface {
+	// Create stores the practitioner with their specialties
+	Create(ctx context.Context, practitioner *domain.Practitioner) error
+
+	// GetByID returns the practitioner with their specialties, licences and
+	// assignments at all facilities
+	GetByID(ctx context.Context, id string) (*domain.Practitioner, error)
+	GetByUserID(ctx context.Context, userID string) (*domain.Practitioner, error)
+
+	// Update stores the practitioner's profile and replaces their
+	// specialties
+	Update(ctx context.Context, practitioner *domain.Practitioner) error
+
+	// List returns the practitioners with an active assignment at the
+	// tenant of the context, with their specialties
+	List(ctx context.Context, filter domain.PractitionerFilter) ([]*domain.Practitioner, int, error)
+
+	// ListByUserIDs returns the practitioners with the user IDs, active or
+	// not, without specialties, licences or assignments
+	ListByUserIDs(ctx context.Context, userIDs []string) ([]*domain.Practitioner, error)
+
+	AddLicence(ctx context.Context, licence *domain.Licence) error
+	GetLicenceByNumber(ctx context.Context, licenceType, number string) (*domain.Licence, error)
+	DeleteLicence(ctx context.Context, practitionerID, id string) error
+
+	// ExpiringLicences returns the licences expiring on or before until of
+	// the practitioners assigned to the tenant of the context: their STRs
+	// and the SIPs issued for the tenant, soonest first
+	ExpiringLicences(ctx context.Context, until time.Time) ([]*domain.ExpiringLicence, error)
+
+	// AddAssignment assigns the practitioner at the tenant of the context
+	AddAssignment(ctx context.Context, assignment *domain.Assignment) error
+
+	// EndAssignment ends an assignment of the tenant of the context on
+	// endDate
+	EndAssignment(ctx context.Context, practitionerID, id string, endDate time.Time) error
+
+	// HasActiveAssignments reports whether anyone is assigned to the
+	// organization today
+	HasActiveAssignments(ctx context.Context, organizationID string) (bool, error)
+}
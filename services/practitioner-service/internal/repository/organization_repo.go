@@ -0,0 +1,183 @@
+// Organization repository
+// internal/repository/organization_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"practitioner-service/internal/domain"
+)
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+type organizationRepository struct {
+	db *sql.DB
+}
+
+func NewOrganizationRepository(db *sql.DB) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *domain.Organization) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	org.ID = uuid.New().String()
+	org.TenantID = tenantID
+	org.IsActive = true
+	org.CreatedAt = time.Now()
+	org.UpdatedAt = org.CreatedAt
+	org.UpdatedBy = org.CreatedBy
+
+	query := `
+		INSERT INTO organizations (
+			id, tenant_id, parent_id, code, name, type, is_active,
+			created_by, created_at, updated_by, updated_at
+		) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, 1, @p7, @p8, @p7, @p8)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		org.ID, org.TenantID, nullString(org.ParentID), org.Code, org.Name, org.Type,
+		nullString(org.CreatedBy), org.CreatedAt,
+	)
+	return err
+}
+
+const organizationColumns = `id, tenant_id, parent_id, code, name, type, is_active,
+	created_by, created_at, updated_by, updated_at`
+
+func scanOrganization(row rowScanner) (*domain.Organization, error) {
+	var (
+		org       domain.Organization
+		parentID  sql.NullString
+		createdBy sql.NullString
+		updatedBy sql.NullString
+	)
+
+	err := row.Scan(&org.ID, &org.TenantID, &parentID, &org.Code, &org.Name, &org.Type, &org.IsActive,
+		&createdBy, &org.CreatedAt, &updatedBy, &org.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	org.ParentID = parentID.String
+	org.CreatedBy = createdBy.String
+	org.UpdatedBy = updatedBy.String
+	return &org, nil
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id string) (*domain.Organization, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + organizationColumns + ` FROM organizations WHERE id = @p1 AND tenant_id = @p2`
+	return scanOrganization(r.db.QueryRowContext(ctx, query, id, tenantID))
+}
+
+func (r *organizationRepository) GetByCode(ctx context.Context, code string) (*domain.Organization, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + organizationColumns + ` FROM organizations WHERE code = @p1 AND tenant_id = @p2`
+	return scanOrganization(r.db.QueryRowContext(ctx, query, code, tenantID))
+}
+
+func (r *organizationRepository) List(ctx context.Context, includeInactive bool) ([]*domain.Organization, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + organizationColumns + ` FROM organizations WHERE tenant_id = @p1`
+	if !includeInactive {
+		query += ` AND is_active = 1`
+	}
+	query += ` ORDER BY name, code`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgs []*domain.Organization
+	for rows.Next() {
+		org, err := scanOrganization(rows)
+		if err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, rows.Err()
+}
+
+func (r *organizationRepository) Update(ctx context.Context, org *domain.Organization) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	org.UpdatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE organizations SET
+			parent_id = @p1, code = @p2, name = @p3, type = @p4,
+			updated_by = @p5, updated_at = @p6
+		WHERE id = @p7 AND tenant_id = @p8
+	`, nullString(org.ParentID), org.Code, org.Name, org.Type,
+		nullString(org.UpdatedBy), org.UpdatedAt, org.ID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrOrganizationNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) Deactivate(ctx context.Context, id, updatedBy string) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE organizations SET is_active = 0, updated_by = @p1, updated_at = GETDATE()
+		WHERE id = @p2 AND tenant_id = @p3 AND is_active = 1
+	`, nullString(updatedBy), id, tenantID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrOrganizationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,573 @@
+// Practitioner repository
+// internal/repository/practitioner_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"practitioner-service/internal/domain"
+)
+
+// activeAssignment is the condition on practitioner_assignments a for
+// assignments covering today.
+const activeAssignment = `a.start_date <= CAST(GETDATE() AS DATE) AND (a.end_date IS NULL OR a.end_date >= CAST(GETDATE() AS DATE))`
+
+// withTx runs fn in a transaction, committed if fn succeeds.
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// placeholders appends values to args and returns their placeholders for
+// an IN list.
+func placeholders(args *[]interface{}, values []string) string {
+	params := make([]string, len(values))
+	for i, value := range values {
+		*args = append(*args, value)
+		params[i] = "@p" + strconv.Itoa(len(*args))
+	}
+	return strings.Join(params, ", ")
+}
+
+type practitionerRepository struct {
+	db *sql.DB
+}
+
+func NewPractitionerRepository(db *sql.DB) PractitionerRepository {
+	return &practitionerRepository{db: db}
+}
+
+func (r *practitionerRepository) Create(ctx context.Context, practitioner *domain.Practitioner) error {
+	practitioner.ID = uuid.New().String()
+	practitioner.IsActive = true
+	practitioner.CreatedAt = time.Now()
+	practitioner.UpdatedAt = practitioner.CreatedAt
+	practitioner.UpdatedBy = practitioner.CreatedBy
+
+	query := `
+		INSERT INTO practitioners (
+			id, user_id, role, prefix_title, first_name, last_name, suffix_title, email, phone,
+			is_active, created_by, created_at, updated_by, updated_at
+		) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, 1, @p10, @p11, @p10, @p11)
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			practitioner.ID, nullString(practitioner.UserID), practitioner.Role,
+			nullString(practitioner.PrefixTitle), practitioner.FirstName, nullString(practitioner.LastName),
+			nullString(practitioner.SuffixTitle), nullString(practitioner.Email), nullString(practitioner.Phone),
+			nullString(practitioner.CreatedBy), practitioner.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		return insertSpecialties(ctx, tx, practitioner)
+	})
+}
+
+func insertSpecialties(ctx context.Context, tx *sql.Tx, practitioner *domain.Practitioner) error {
+	for _, specialty := range practitioner.Specialties {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO practitioner_specialties (practitioner_id, code, name) VALUES (@p1, @p2, @p3)
+		`, practitioner.ID, specialty.Code, specialty.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const practitionerColumns = `p.id, p.user_id, p.role, p.prefix_title, p.first_name, p.last_name, p.suffix_title,
+	p.email, p.phone, p.is_active, p.created_by, p.created_at, p.updated_by, p.updated_at`
+
+func scanPractitioner(row rowScanner) (*domain.Practitioner, error) {
+	var (
+		practitioner domain.Practitioner
+		userID       sql.NullString
+		prefixTitle  sql.NullString
+		lastName     sql.NullString
+		suffixTitle  sql.NullString
+		email        sql.NullString
+		phone        sql.NullString
+		createdBy    sql.NullString
+		updatedBy    sql.NullString
+	)
+
+	err := row.Scan(&practitioner.ID, &userID, &practitioner.Role, &prefixTitle, &practitioner.FirstName,
+		&lastName, &suffixTitle, &email, &phone, &practitioner.IsActive, &createdBy,
+		&practitioner.CreatedAt, &updatedBy, &practitioner.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPractitionerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	practitioner.UserID = userID.String
+	practitioner.PrefixTitle = prefixTitle.String
+	practitioner.LastName = lastName.String
+	practitioner.SuffixTitle = suffixTitle.String
+	practitioner.Email = email.String
+	practitioner.Phone = phone.String
+	practitioner.CreatedBy = createdBy.String
+	practitioner.UpdatedBy = updatedBy.String
+	return &practitioner, nil
+}
+
+func (r *practitionerRepository) GetByID(ctx context.Context, id string) (*domain.Practitioner, error) {
+	return r.get(ctx, "p.id", id)
+}
+
+func (r *practitionerRepository) GetByUserID(ctx context.Context, userID string) (*domain.Practitioner, error) {
+	return r.get(ctx, "p.user_id", userID)
+}
+
+// get returns the practitioner whose column has the value, with their
+// specialties, licences and assignments.
+func (r *practitionerRepository) get(ctx context.Context, column, value string) (*domain.Practitioner, error) {
+	query := `SELECT ` + practitionerColumns + ` FROM practitioners p WHERE ` + column + ` = @p1`
+	practitioner, err := scanPractitioner(r.db.QueryRowContext(ctx, query, value))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadSpecialties(ctx, []*domain.Practitioner{practitioner}); err != nil {
+		return nil, err
+	}
+	if practitioner.Licences, err = r.licences(ctx, practitioner.ID); err != nil {
+		return nil, err
+	}
+	if practitioner.Assignments, err = r.assignments(ctx, practitioner.ID); err != nil {
+		return nil, err
+	}
+	return practitioner, nil
+}
+
+// loadSpecialties sets the specialties of the practitioners with one query.
+func (r *practitionerRepository) loadSpecialties(ctx context.Context, practitioners []*domain.Practitioner) error {
+	if len(practitioners) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*domain.Practitioner, len(practitioners))
+	ids := make([]string, len(practitioners))
+	for i, practitioner := range practitioners {
+		practitioner.Specialties = []domain.Specialty{}
+		byID[practitioner.ID] = practitioner
+		ids[i] = practitioner.ID
+	}
+
+	var args []interface{}
+	query := `
+		SELECT practitioner_id, code, name FROM practitioner_specialties
+		WHERE practitioner_id IN (` + placeholders(&args, ids) + `)
+		ORDER BY code
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			practitionerID string
+			specialty      domain.Specialty
+		)
+		if err := rows.Scan(&practitionerID, &specialty.Code, &specialty.Name); err != nil {
+			return err
+		}
+		practitioner := byID[practitionerID]
+		practitioner.Specialties = append(practitioner.Specialties, specialty)
+	}
+	return rows.Err()
+}
+
+const licenceColumns = `l.id, l.practitioner_id, l.type, l.number, l.tenant_id, l.issued_at, l.expires_at,
+	l.created_by, l.created_at`
+
+func scanLicence(row rowScanner, extra ...interface{}) (*domain.Licence, error) {
+	var (
+		licence   domain.Licence
+		tenantID  sql.NullString
+		expiresAt sql.NullTime
+		createdBy sql.NullString
+	)
+
+	dest := []interface{}{&licence.ID, &licence.PractitionerID, &licence.Type, &licence.Number, &tenantID,
+		&licence.IssuedAt, &expiresAt, &createdBy, &licence.CreatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrLicenceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	licence.TenantID = tenantID.String
+	if expiresAt.Valid {
+		licence.ExpiresAt = &expiresAt.Time
+	}
+	licence.CreatedBy = createdBy.String
+	return &licence, nil
+}
+
+func (r *practitionerRepository) licences(ctx context.Context, practitionerID string) ([]*domain.Licence, error) {
+	query := `
+		SELECT ` + licenceColumns + ` FROM practitioner_licences l
+		WHERE l.practitioner_id = @p1
+		ORDER BY l.type DESC, l.issued_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, practitionerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	licences := []*domain.Licence{}
+	for rows.Next() {
+		licence, err := scanLicence(rows)
+		if err != nil {
+			return nil, err
+		}
+		licences = append(licences, licence)
+	}
+	return licences, rows.Err()
+}
+
+const assignmentColumns = `id, practitioner_id, tenant_id, organization_id, position, is_primary,
+	start_date, end_date, created_by, created_at`
+
+func scanAssignment(row rowScanner) (*domain.Assignment, error) {
+	var (
+		assignment domain.Assignment
+		position   sql.NullString
+		endDate    sql.NullTime
+		createdBy  sql.NullString
+	)
+
+	err := row.Scan(&assignment.ID, &assignment.PractitionerID, &assignment.TenantID,
+		&assignment.OrganizationID, &position, &assignment.IsPrimary, &assignment.StartDate, &endDate,
+		&createdBy, &assignment.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAssignmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	assignment.Position = position.String
+	if endDate.Valid {
+		assignment.EndDate = &endDate.Time
+	}
+	assignment.CreatedBy = createdBy.String
+	return &assignment, nil
+}
+
+func (r *practitionerRepository) assignments(ctx context.Context, practitionerID string) ([]*domain.Assignment, error) {
+	query := `
+		SELECT ` + assignmentColumns + ` FROM practitioner_assignments
+		WHERE practitioner_id = @p1
+		ORDER BY start_date DESC, id
+	`
+	rows, err := r.db.QueryContext(ctx, query, practitionerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []*domain.Assignment{}
+	for rows.Next() {
+		assignment, err := scanAssignment(rows)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, rows.Err()
+}
+
+func (r *practitionerRepository) Update(ctx context.Context, practitioner *domain.Practitioner) error {
+	practitioner.UpdatedAt = time.Now()
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE practitioners SET
+				user_id = @p1, role = @p2, prefix_title = @p3, first_name = @p4, last_name = @p5,
+				suffix_title = @p6, email = @p7, phone = @p8, is_active = @p9,
+				updated_by = @p10, updated_at = @p11
+			WHERE id = @p12
+		`, nullString(practitioner.UserID), practitioner.Role, nullString(practitioner.PrefixTitle),
+			practitioner.FirstName, nullString(practitioner.LastName), nullString(practitioner.SuffixTitle),
+			nullString(practitioner.Email), nullString(practitioner.Phone), practitioner.IsActive,
+			nullString(practitioner.UpdatedBy), practitioner.UpdatedAt, practitioner.ID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return domain.ErrPractitionerNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM practitioner_specialties WHERE practitioner_id = @p1`, practitioner.ID); err != nil {
+			return err
+		}
+		return insertSpecialties(ctx, tx, practitioner)
+	})
+}
+
+func (r *practitionerRepository) List(ctx context.Context, filter domain.PractitionerFilter) ([]*domain.Practitioner, int, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args := []interface{}{tenantID}
+	assigned := `a.practitioner_id = p.id AND a.tenant_id = @p1 AND ` + activeAssignment
+	if len(filter.OrganizationIDs) > 0 {
+		assigned += ` AND a.organization_id IN (` + placeholders(&args, filter.OrganizationIDs) + `)`
+	}
+	conditions := []string{`EXISTS (SELECT 1 FROM practitioner_assignments a WHERE ` + assigned + `)`}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, strings.Replace(condition, "?", "@p"+strconv.Itoa(len(args)), -1))
+	}
+	if !filter.IncludeInactive {
+		conditions = append(conditions, "p.is_active = 1")
+	}
+	if filter.Role != "" {
+		add("p.role = ?", filter.Role)
+	}
+	if filter.Specialty != "" {
+		add("EXISTS (SELECT 1 FROM practitioner_specialties s WHERE s.practitioner_id = p.id AND s.code = ?)", filter.Specialty)
+	}
+	if filter.Query != "" {
+		add("(p.first_name + ' ' + ISNULL(p.last_name, '')) LIKE ?", "%"+escapeLike(filter.Query)+"%")
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM practitioners p`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	query := `SELECT ` + practitionerColumns + ` FROM practitioners p` + where +
+		` ORDER BY p.first_name, p.last_name, p.id OFFSET ` + strconv.Itoa(offset) + ` ROWS FETCH NEXT ` +
+		strconv.Itoa(filter.Limit) + ` ROWS ONLY`
+
+	practitioners, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadSpecialties(ctx, practitioners); err != nil {
+		return nil, 0, err
+	}
+	return practitioners, total, nil
+}
+
+// escapeLike escapes the LIKE wildcards of s.
+func escapeLike(s string) string {
+	return strings.NewReplacer("[", "[[]", "%", "[%]", "_", "[_]").Replace(s)
+}
+
+func (r *practitionerRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.Practitioner, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var practitioners []*domain.Practitioner
+	for rows.Next() {
+		practitioner, err := scanPractitioner(rows)
+		if err != nil {
+			return nil, err
+		}
+		practitioners = append(practitioners, practitioner)
+	}
+	return practitioners, rows.Err()
+}
+
+func (r *practitionerRepository) ListByUserIDs(ctx context.Context, userIDs []string) ([]*domain.Practitioner, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var args []interface{}
+	query := `SELECT ` + practitionerColumns + ` FROM practitioners p WHERE p.user_id IN (` + placeholders(&args, userIDs) + `)`
+	return r.query(ctx, query, args...)
+}
+
+func (r *practitionerRepository) AddLicence(ctx context.Context, licence *domain.Licence) error {
+	licence.ID = uuid.New().String()
+	licence.CreatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO practitioner_licences (
+			id, practitioner_id, type, number, tenant_id, issued_at, expires_at, created_by, created_at
+		) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9)
+	`, licence.ID, licence.PractitionerID, licence.Type, licence.Number, nullString(licence.TenantID),
+		licence.IssuedAt, licence.ExpiresAt, nullString(licence.CreatedBy), licence.CreatedAt)
+	return err
+}
+
+func (r *practitionerRepository) GetLicenceByNumber(ctx context.Context, licenceType, number string) (*domain.Licence, error) {
+	query := `SELECT ` + licenceColumns + ` FROM practitioner_licences l WHERE l.type = @p1 AND l.number = @p2`
+	return scanLicence(r.db.QueryRowContext(ctx, query, licenceType, number))
+}
+
+func (r *practitionerRepository) DeleteLicence(ctx context.Context, practitionerID, id string) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	// SIPs of other facilities are theirs to manage
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM practitioner_licences
+		WHERE id = @p1 AND practitioner_id = @p2 AND (tenant_id IS NULL OR tenant_id = @p3)
+	`, id, practitionerID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrLicenceNotFound
+	}
+	return nil
+}
+
+func (r *practitionerRepository) ExpiringLicences(ctx context.Context, until time.Time) ([]*domain.ExpiringLicence, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + licenceColumns + `, p.prefix_title, p.first_name, p.last_name, p.suffix_title, p.role
+		FROM practitioner_licences l
+		JOIN practitioners p ON p.id = l.practitioner_id
+		WHERE l.expires_at IS NOT NULL AND l.expires_at <= @p2
+			AND (l.tenant_id IS NULL OR l.tenant_id = @p1)
+			AND p.is_active = 1
+			AND EXISTS (
+				SELECT 1 FROM practitioner_assignments a
+				WHERE a.practitioner_id = p.id AND a.tenant_id = @p1 AND ` + activeAssignment + `
+			)
+		ORDER BY l.expires_at, l.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, tenantID, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var licences []*domain.ExpiringLicence
+	for rows.Next() {
+		var (
+			holder      domain.Practitioner
+			prefixTitle sql.NullString
+			lastName    sql.NullString
+			suffixTitle sql.NullString
+		)
+		licence, err := scanLicence(rows, &prefixTitle, &holder.FirstName, &lastName, &suffixTitle, &holder.Role)
+		if err != nil {
+			return nil, err
+		}
+		holder.PrefixTitle = prefixTitle.String
+		holder.LastName = lastName.String
+		holder.SuffixTitle = suffixTitle.String
+
+		licences = append(licences, &domain.ExpiringLicence{
+			Licence:          licence,
+			PractitionerName: holder.DisplayName(),
+			Role:             holder.Role,
+		})
+	}
+	return licences, rows.Err()
+}
+
+func (r *practitionerRepository) AddAssignment(ctx context.Context, assignment *domain.Assignment) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	assignment.ID = uuid.New().String()
+	assignment.TenantID = tenantID
+	assignment.CreatedAt = time.Now()
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO practitioner_assignments (
+			id, practitioner_id, tenant_id, organization_id, position, is_primary,
+			start_date, end_date, created_by, created_at
+		) VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10)
+	`, assignment.ID, assignment.PractitionerID, assignment.TenantID, assignment.OrganizationID,
+		nullString(assignment.Position), assignment.IsPrimary, assignment.StartDate, assignment.EndDate,
+		nullString(assignment.CreatedBy), assignment.CreatedAt)
+	return err
+}
+
+func (r *practitionerRepository) EndAssignment(ctx context.Context, practitionerID, id string, endDate time.Time) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Ending never extends an assignment that ends earlier already
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE practitioner_assignments SET end_date = @p4
+		WHERE id = @p1 AND practitioner_id = @p2 AND tenant_id = @p3
+			AND (end_date IS NULL OR end_date > @p4)
+	`, id, practitionerID, tenantID, endDate)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrAssignmentNotFound
+	}
+	return nil
+}
+
+func (r *practitionerRepository) HasActiveAssignments(ctx context.Context, organizationID string) (bool, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = r.db.QueryRowContext(ctx, `
+		SELECT CASE WHEN EXISTS (
+			SELECT 1 FROM practitioner_assignments a
+			WHERE a.tenant_id = @p1 AND a.organization_id = @p2 AND `+activeAssignment+`
+		) THEN 1 ELSE 0 END
+	`, tenantID, organizationID).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,58 @@
+// Service interfaces
+// internal/service/interfaces.go
+package service
+
+import (
+	"context"
+	"practitioner-service/internal/domain"
+	"time"
+)
+
+type OrganizationService interface {
+	// CreateOrganization adds an organization to the facility, under an
+	// active parent of the facility if it has one
+	CreateOrganization(ctx context.Context, org *domain.Organization) (*domain.Organization, error)
+	GetOrganization(ctx context.Context, id string) (*domain.Organization, error)
+	ListOrganizations(ctx context.Context, includeInactive bool) ([]*domain.Organization, error)
+
+	// OrganizationTree returns the facility's active organizations as a tree
+	OrganizationTree(ctx context.Context) ([]*domain.OrganizationNode, error)
+
+	// UpdateOrganization renames or moves an organization; it cannot be
+	// moved below itself
+	UpdateOrganization(ctx context.Context, org *domain.Organization) (*domain.Organization, error)
+
+	// DeactivateOrganization deactivates an organization without active
+	// sub-organizations or assignments
+	DeactivateOrganization(ctx context.Context, id, updatedBy string) error
+}
+
+type PractitionerService interface {
+	CreatePractitioner(ctx context.Context, practitioner *domain.Practitioner) (*domain.Practitioner, error)
+	GetPractitioner(ctx context.Context, id string) (*domain.Practitioner, error)
+	UpdatePractitioner(ctx context.Context, practitioner *domain.Practitioner) (*domain.Practitioner, error)
+
+	// ListPractitioners returns the practitioners assigned to the facility.
+	// Filtering by an organization includes the organizations below it.
+	ListPractitioners(ctx context.Context, filter domain.PractitionerFilter) ([]*domain.Practitioner, int, error)
+
+	// AddLicence records an STR or SIP; SIPs without a facility are issued
+	// for the facility of the context
+	AddLicence(ctx context.Context, licence *domain.Licence) (*domain.Licence, error)
+	RemoveLicence(ctx context.Context, practitionerID, licenceID string) error
+
+	// ExpiringLicences returns the licences of the facility's practitioners
+	// that have expired or expire within the given time
+	ExpiringLicences(ctx context.Context, within time.Duration) ([]*domain.ExpiringLicence, error)
+
+	// Assign places the practitioner in an active organization of the
+	// facility
+	Assign(ctx context.Context, assignment *domain.Assignment) (*domain.Assignment, error)
+
+	// EndAssignment ends an assignment at the facility on endDate
+	EndAssignment(ctx context.Context, practitionerID, assignmentID string, endDate time.Time) error
+
+	// LookupUsers resolves user IDs, such as created_by of other services,
+	// to names. User IDs of no practitioner are left out.
+	LookupUsers(ctx context.Context, userIDs []string) ([]*domain.UserName, error)
+}
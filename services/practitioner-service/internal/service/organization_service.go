@@ -0,0 +1,156 @@
+// Department and organization hierarchy
+// internal/service/organization_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"practitioner-service/internal/domain"
+	"practitioner-service/internal/repository"
+)
+
+type organizationService struct {
+	organizationRepo repository.OrganizationRepository
+	practitionerRepo repository.PractitionerRepository
+}
+
+func NewOrganizationService(organizationRepo repository.OrganizationRepository, practitionerRepo repository.PractitionerRepository) OrganizationService {
+	return &organizationService{
+		organizationRepo: organizationRepo,
+		practitionerRepo: practitionerRepo,
+	}
+}
+
+func (s *organizationService) CreateOrganization(ctx context.Context, org *domain.Organization) (*domain.Organization, error) {
+	if err := s.checkCode(ctx, org); err != nil {
+		return nil, err
+	}
+	if org.ParentID != "" {
+		if err := s.checkParent(ctx, org.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.organizationRepo.Create(ctx, org); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+	return org, nil
+}
+
+// checkCode returns ErrOrganizationExists if another organization of the
+// facility has the organization's code.
+func (s *organizationService) checkCode(ctx context.Context, org *domain.Organization) error {
+	existing, err := s.organizationRepo.GetByCode(ctx, org.Code)
+	if err == domain.ErrOrganizationNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != org.ID {
+		return domain.ErrOrganizationExists
+	}
+	return nil
+}
+
+// checkParent returns an error unless the parent is an active organization
+// of the facility.
+func (s *organizationService) checkParent(ctx context.Context, parentID string) error {
+	parent, err := s.organizationRepo.GetByID(ctx, parentID)
+	if err == domain.ErrOrganizationNotFound {
+		return domain.NewCustomError("INVALID_PARENT", "Parent organization not found", "")
+	}
+	if err != nil {
+		return err
+	}
+	if !parent.IsActive {
+		return domain.NewCustomError("INVALID_PARENT", "Parent organization is inactive", "")
+	}
+	return nil
+}
+
+func (s *organizationService) GetOrganization(ctx context.Context, id string) (*domain.Organization, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.organizationRepo.GetByID(ctx, id)
+}
+
+func (s *organizationService) ListOrganizations(ctx context.Context, includeInactive bool) ([]*domain.Organization, error) {
+	return s.organizationRepo.List(ctx, includeInactive)
+}
+
+func (s *organizationService) OrganizationTree(ctx context.Context) ([]*domain.OrganizationNode, error) {
+	orgs, err := s.organizationRepo.List(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return domain.OrganizationTree(orgs), nil
+}
+
+func (s *organizationService) UpdateOrganization(ctx context.Context, org *domain.Organization) (*domain.Organization, error) {
+	if org.ID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	existing, err := s.organizationRepo.GetByID(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCode(ctx, org); err != nil {
+		return nil, err
+	}
+
+	if org.ParentID != "" && org.ParentID != existing.ParentID {
+		if err := s.checkParent(ctx, org.ParentID); err != nil {
+			return nil, err
+		}
+
+		orgs, err := s.organizationRepo.List(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range domain.Descendants(orgs, org.ID) {
+			if id == org.ParentID {
+				return nil, domain.ErrOrganizationCycle
+			}
+		}
+	}
+
+	existing.ParentID = org.ParentID
+	existing.Code = org.Code
+	existing.Name = org.Name
+	existing.Type = org.Type
+	existing.UpdatedBy = org.UpdatedBy
+	if err := s.organizationRepo.Update(ctx, existing); err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+	return existing, nil
+}
+
+func (s *organizationService) DeactivateOrganization(ctx context.Context, id, updatedBy string) error {
+	if id == "" {
+		return domain.ErrInvalidInput
+	}
+
+	orgs, err := s.organizationRepo.List(ctx, false)
+	if err != nil {
+		return err
+	}
+	for _, org := range orgs {
+		if org.ParentID == id {
+			return domain.ErrOrganizationInUse
+		}
+	}
+
+	assigned, err := s.practitionerRepo.HasActiveAssignments(ctx, id)
+	if err != nil {
+		return err
+	}
+	if assigned {
+		return domain.ErrOrganizationInUse
+	}
+
+	return s.organizationRepo.Deactivate(ctx, id, updatedBy)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"practitioner-service/internal/domain"
+)
+
+// mockOrganizations keeps the organizations of one facility in memory.
+type mockOrganizations struct {
+	orgs map[string]*domain.Organization
+}
+
+func newMockOrganizations(orgs ...*domain.Organization) *mockOrganizations {
+	m := &mockOrganizations{orgs: make(map[string]*domain.Organization)}
+	for _, org := range orgs {
+		org.IsActive = true
+		m.orgs[org.ID] = org
+	}
+	return m
+}
+
+func (m *mockOrganizations) Create(ctx context.Context, org *domain.Organization) error {
+	org.ID = org.Code
+	org.IsActive = true
+	m.orgs[org.ID] = org
+	return nil
+}
+
+func (m *mockOrganizations) GetByID(ctx context.Context, id string) (*domain.Organization, error) {
+	org, ok := m.orgs[id]
+	if !ok {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	copied := *org
+	return &copied, nil
+}
+
+func (m *mockOrganizations) GetByCode(ctx context.Context, code string) (*domain.Organization, error) {
+	for _, org := range m.orgs {
+		if org.Code == code {
+			copied := *org
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrOrganizationNotFound
+}
+
+func (m *mockOrganizations) List(ctx context.Context, includeInactive bool) ([]*domain.Organization, error) {
+	var orgs []*domain.Organization
+	for _, org := range m.orgs {
+		if includeInactive || org.IsActive {
+			copied := *org
+			orgs = append(orgs, &copied)
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].Name < orgs[j].Name })
+	return orgs, nil
+}
+
+func (m *mockOrganizations) Update(ctx context.Context, org *domain.Organization) error {
+	copied := *org
+	m.orgs[org.ID] = &copied
+	return nil
+}
+
+func (m *mockOrganizations) Deactivate(ctx context.Context, id, updatedBy string) error {
+	m.orgs[id].IsActive = false
+	return nil
+}
+
+// medicalServices is Bidang Pelayanan Medis with Departemen Penyakit Dalam
+// and its clinic below it.
+func medicalServices() *mockOrganizations {
+	return newMockOrganizations(
+		&domain.Organization{ID: "yanmed", Code: "yanmed", Name: "Bidang Pelayanan Medis", Type: domain.OrganizationDivision},
+		&domain.Organization{ID: "ipd", ParentID: "yanmed", Code: "ipd", Name: "Departemen Penyakit Dalam", Type: domain.OrganizationDepartment},
+		&domain.Organization{ID: "poli-pd", ParentID: "ipd", Code: "poli-pd", Name: "Poli Penyakit Dalam", Type: domain.OrganizationUnit},
+		&domain.Organization{ID: "igd", Code: "igd", Name: "Instalasi Gawat Darurat", Type: domain.OrganizationDepartment},
+	)
+}
+
+func TestOrganizationTree(t *testing.T) {
+	svc := NewOrganizationService(medicalServices(), newMockPractitioners())
+
+	roots, err := svc.OrganizationTree(context.Background())
+	if err != nil {
+		t.Fatalf("OrganizationTree failed: %v", err)
+	}
+	if len(roots) != 2 || roots[0].ID != "yanmed" || roots[1].ID != "igd" {
+		t.Fatalf("Expected yanmed and igd at the top level, got %d roots", len(roots))
+	}
+	ipd := roots[0].Children
+	if len(ipd) != 1 || ipd[0].ID != "ipd" || len(ipd[0].Children) != 1 || ipd[0].Children[0].ID != "poli-pd" {
+		t.Errorf("Expected yanmed > ipd > poli-pd")
+	}
+}
+
+func TestUpdateOrganizationRejectsCycles(t *testing.T) {
+	orgs := medicalServices()
+	svc := NewOrganizationService(orgs, newMockPractitioners())
+	ctx := context.Background()
+
+	for _, parentID := range []string{"ipd", "poli-pd"} {
+		_, err := svc.UpdateOrganization(ctx, &domain.Organization{
+			ID: "ipd", ParentID: parentID, Code: "ipd", Name: "Departemen Penyakit Dalam", Type: domain.OrganizationDepartment,
+		})
+		if err != domain.ErrOrganizationCycle {
+			t.Errorf("Moving ipd below %s: expected a cycle error, got %v", parentID, err)
+		}
+	}
+
+	moved, err := svc.UpdateOrganization(ctx, &domain.Organization{
+		ID: "poli-pd", ParentID: "igd", Code: "poli-pd", Name: "Poli Penyakit Dalam", Type: domain.OrganizationUnit,
+	})
+	if err != nil || moved.ParentID != "igd" {
+		t.Fatalf("Expected poli-pd to move below igd, got %v, %v", moved, err)
+	}
+
+	if _, err := svc.UpdateOrganization(ctx, &domain.Organization{
+		ID: "igd", Code: "ipd", Name: "Instalasi Gawat Darurat", Type: domain.OrganizationDepartment,
+	}); err != domain.ErrOrganizationExists {
+		t.Errorf("Expected a duplicate code to be rejected, got %v", err)
+	}
+}
+
+func TestDeactivateOrganizationInUse(t *testing.T) {
+	orgs := medicalServices()
+	practitioners := newMockPractitioners()
+	svc := NewOrganizationService(orgs, practitioners)
+	ctx := context.Background()
+
+	if err := svc.DeactivateOrganization(ctx, "ipd", "u1"); err != domain.ErrOrganizationInUse {
+		t.Errorf("Expected an organization with sub-organizations to stay active, got %v", err)
+	}
+
+	practitioners.assignments = append(practitioners.assignments, &domain.Assignment{
+		ID: "as1", PractitionerID: "p1", TenantID: "default", OrganizationID: "poli-pd", StartDate: time.Now().AddDate(0, -1, 0),
+	})
+	if err := svc.DeactivateOrganization(ctx, "poli-pd", "u1"); err != domain.ErrOrganizationInUse {
+		t.Errorf("Expected an organization with assignments to stay active, got %v", err)
+	}
+
+	if err := svc.DeactivateOrganization(ctx, "igd", "u1"); err != nil {
+		t.Fatalf("DeactivateOrganization failed: %v", err)
+	}
+	if _, err := svc.CreateOrganization(ctx, &domain.Organization{
+		ParentID: "igd", Code: "triage", Name: "Triase", Type: domain.OrganizationUnit,
+	}); err == nil {
+		t.Error("Expected an inactive parent to be rejected")
+	}
+}
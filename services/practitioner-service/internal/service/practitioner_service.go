@@ -0,0 +1,235 @@
+// Practitioner directory
+// internal/service/practitioner_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"practitioner-service/internal/domain"
+	"practitioner-service/internal/repository"
+	"practitioner-service/internal/tenant"
+)
+
+// MaxLookupUsers caps the user IDs LookupUsers resolves at once.
+const MaxLookupUsers = 100
+
+type practitionerService struct {
+	practitionerRepo repository.PractitionerRepository
+	organizationRepo repository.OrganizationRepository
+}
+
+func NewPractitionerService(practitionerRepo repository.PractitionerRepository, organizationRepo repository.OrganizationRepository) PractitionerService {
+	return &practitionerService{
+		practitionerRepo: practitionerRepo,
+		organizationRepo: organizationRepo,
+	}
+}
+
+func (s *practitionerService) CreatePractitioner(ctx context.Context, practitioner *domain.Practitioner) (*domain.Practitioner, error) {
+	if err := s.checkUserID(ctx, practitioner); err != nil {
+		return nil, err
+	}
+
+	if err := s.practitionerRepo.Create(ctx, practitioner); err != nil {
+		return nil, fmt.Errorf("failed to create practitioner: %w", err)
+	}
+	return s.practitionerRepo.GetByID(ctx, practitioner.ID)
+}
+
+// checkUserID returns ErrPractitionerExists if another practitioner has the
+// practitioner's user ID.
+func (s *practitionerService) checkUserID(ctx context.Context, practitioner *domain.Practitioner) error {
+	if practitioner.UserID == "" {
+		return nil
+	}
+
+	existing, err := s.practitionerRepo.GetByUserID(ctx, practitioner.UserID)
+	if err == domain.ErrPractitionerNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != practitioner.ID {
+		return domain.ErrPractitionerExists
+	}
+	return nil
+}
+
+func (s *practitionerService) GetPractitioner(ctx context.Context, id string) (*domain.Practitioner, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.practitionerRepo.GetByID(ctx, id)
+}
+
+func (s *practitionerService) UpdatePractitioner(ctx context.Context, practitioner *domain.Practitioner) (*domain.Practitioner, error) {
+	if practitioner.ID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := s.checkUserID(ctx, practitioner); err != nil {
+		return nil, err
+	}
+
+	if err := s.practitionerRepo.Update(ctx, practitioner); err != nil {
+		if err == domain.ErrPractitionerNotFound {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update practitioner: %w", err)
+	}
+	return s.practitionerRepo.GetByID(ctx, practitioner.ID)
+}
+
+func (s *practitionerService) ListPractitioners(ctx context.Context, filter domain.PractitionerFilter) ([]*domain.Practitioner, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 10
+	}
+
+	// An organization stands for itself and everything below it
+	if len(filter.OrganizationIDs) == 1 {
+		orgs, err := s.organizationRepo.List(ctx, true)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.OrganizationIDs = domain.Descendants(orgs, filter.OrganizationIDs[0])
+	}
+
+	return s.practitionerRepo.List(ctx, filter)
+}
+
+func (s *practitionerService) AddLicence(ctx context.Context, licence *domain.Licence) (*domain.Licence, error) {
+	if licence.Type == domain.LicenceSIP && licence.TenantID == "" {
+		licence.TenantID, _ = tenant.FromContext(ctx)
+	}
+	if licence.Type == domain.LicenceSTR {
+		licence.TenantID = ""
+	}
+	if err := licence.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.practitionerRepo.GetByID(ctx, licence.PractitionerID); err != nil {
+		return nil, err
+	}
+
+	_, err := s.practitionerRepo.GetLicenceByNumber(ctx, licence.Type, licence.Number)
+	if err == nil {
+		return nil, domain.ErrLicenceExists
+	}
+	if err != domain.ErrLicenceNotFound {
+		return nil, err
+	}
+
+	if err := s.practitionerRepo.AddLicence(ctx, licence); err != nil {
+		return nil, fmt.Errorf("failed to add licence: %w", err)
+	}
+	return licence, nil
+}
+
+func (s *practitionerService) RemoveLicence(ctx context.Context, practitionerID, licenceID string) error {
+	if practitionerID == "" || licenceID == "" {
+		return domain.ErrInvalidInput
+	}
+	return s.practitionerRepo.DeleteLicence(ctx, practitionerID, licenceID)
+}
+
+func (s *practitionerService) ExpiringLicences(ctx context.Context, within time.Duration) ([]*domain.ExpiringLicence, error) {
+	if within < 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.practitionerRepo.ExpiringLicences(ctx, time.Now().Add(within))
+}
+
+func (s *practitionerService) Assign(ctx context.Context, assignment *domain.Assignment) (*domain.Assignment, error) {
+	if assignment.EndDate != nil && assignment.EndDate.Before(assignment.StartDate) {
+		return nil, domain.NewCustomError("INVALID_DATES", "end_date must not be before start_date", "")
+	}
+
+	practitioner, err := s.practitionerRepo.GetByID(ctx, assignment.PractitionerID)
+	if err != nil {
+		return nil, err
+	}
+	if !practitioner.IsActive {
+		return nil, domain.NewCustomError("PRACTITIONER_INACTIVE", "Inactive practitioners cannot be assigned", "")
+	}
+
+	org, err := s.organizationRepo.GetByID(ctx, assignment.OrganizationID)
+	if err == domain.ErrOrganizationNotFound {
+		return nil, domain.NewCustomError("INVALID_ORGANIZATION", "Organization not found", "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !org.IsActive {
+		return nil, domain.NewCustomError("INVALID_ORGANIZATION", "Organization is inactive", "")
+	}
+
+	if err := s.practitionerRepo.AddAssignment(ctx, assignment); err != nil {
+		return nil, fmt.Errorf("failed to add assignment: %w", err)
+	}
+	return assignment, nil
+}
+
+func (s *practitionerService) EndAssignment(ctx context.Context, practitionerID, assignmentID string, endDate time.Time) error {
+	if practitionerID == "" || assignmentID == "" {
+		return domain.ErrInvalidInput
+	}
+
+	practitioner, err := s.practitionerRepo.GetByID(ctx, practitionerID)
+	if err != nil {
+		return err
+	}
+
+	// Assignments of other facilities are theirs to end
+	tenantID, _ := tenant.FromContext(ctx)
+	for _, assignment := range practitioner.Assignments {
+		if assignment.ID != assignmentID || assignment.TenantID != tenantID {
+			continue
+		}
+		if endDate.Before(assignment.StartDate) {
+			return domain.NewCustomError("INVALID_DATES", "end_date must not be before start_date", "")
+		}
+		if assignment.EndDate != nil && !assignment.EndDate.After(endDate) {
+			// Ends on or before endDate already
+			return nil
+		}
+		return s.practitionerRepo.EndAssignment(ctx, practitionerID, assignmentID, endDate)
+	}
+	return domain.ErrAssignmentNotFound
+}
+
+func (s *practitionerService) LookupUsers(ctx context.Context, userIDs []string) ([]*domain.UserName, error) {
+	seen := make(map[string]bool, len(userIDs))
+	unique := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > MaxLookupUsers {
+		return nil, domain.NewCustomError("TOO_MANY_USERS", fmt.Sprintf("At most %d user IDs can be looked up at once", MaxLookupUsers), "")
+	}
+
+	practitioners, err := s.practitionerRepo.ListByUserIDs(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]*domain.UserName, 0, len(practitioners))
+	for _, p := range practitioners {
+		names = append(names, &domain.UserName{
+			UserID:         p.UserID,
+			PractitionerID: p.ID,
+			Name:           p.Name(),
+			DisplayName:    p.DisplayName(),
+			Role:           p.Role,
+		})
+	}
+	return names, nil
+}
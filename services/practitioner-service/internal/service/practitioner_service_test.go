@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"practitioner-service/internal/domain"
+	"practitioner-service/internal/tenant"
+)
+
+// mockPractitioners keeps practitioners, licences and assignments in
+// memory.
+type mockPractitioners struct {
+	practitioners map[string]*domain.Practitioner
+	licences      []*domain.Licence
+	assignments   []*domain.Assignment
+	lastFilter    domain.PractitionerFilter
+	nextID        int
+}
+
+func newMockPractitioners(practitioners ...*domain.Practitioner) *mockPractitioners {
+	m := &mockPractitioners{practitioners: make(map[string]*domain.Practitioner)}
+	for _, p := range practitioners {
+		p.IsActive = true
+		m.practitioners[p.ID] = p
+	}
+	return m
+}
+
+func (m *mockPractitioners) id() string {
+	m.nextID++
+	return fmt.Sprintf("id%d", m.nextID)
+}
+
+func (m *mockPractitioners) Create(ctx context.Context, practitioner *domain.Practitioner) error {
+	practitioner.ID = m.id()
+	practitioner.IsActive = true
+	m.practitioners[practitioner.ID] = practitioner
+	return nil
+}
+
+func (m *mockPractitioners) GetByID(ctx context.Context, id string) (*domain.Practitioner, error) {
+	p, ok := m.practitioners[id]
+	if !ok {
+		return nil, domain.ErrPractitionerNotFound
+	}
+	copied := *p
+	copied.Licences = nil
+	for _, licence := range m.licences {
+		if licence.PractitionerID == id {
+			copied.Licences = append(copied.Licences, licence)
+		}
+	}
+	copied.Assignments = nil
+	for _, assignment := range m.assignments {
+		if assignment.PractitionerID == id {
+			copied.Assignments = append(copied.Assignments, assignment)
+		}
+	}
+	return &copied, nil
+}
+
+func (m *mockPractitioners) GetByUserID(ctx context.Context, userID string) (*domain.Practitioner, error) {
+	for _, p := range m.practitioners {
+		if p.UserID == userID {
+			return m.GetByID(ctx, p.ID)
+		}
+	}
+	return nil, domain.ErrPractitionerNotFound
+}
+
+func (m *mockPractitioners) Update(ctx context.Context, practitioner *domain.Practitioner) error {
+	if _, ok := m.practitioners[practitioner.ID]; !ok {
+		return domain.ErrPractitionerNotFound
+	}
+	m.practitioners[practitioner.ID] = practitioner
+	return nil
+}
+
+func (m *mockPractitioners) List(ctx context.Context, filter domain.PractitionerFilter) ([]*domain.Practitioner, int, error) {
+	m.lastFilter = filter
+	return nil, 0, nil
+}
+
+func (m *mockPractitioners) ListByUserIDs(ctx context.Context, userIDs []string) ([]*domain.Practitioner, error) {
+	var found []*domain.Practitioner
+	for _, id := range userIDs {
+		for _, p := range m.practitioners {
+			if p.UserID == id {
+				found = append(found, p)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (m *mockPractitioners) AddLicence(ctx context.Context, licence *domain.Licence) error {
+	licence.ID = m.id()
+	m.licences = append(m.licences, licence)
+	return nil
+}
+
+func (m *mockPractitioners) GetLicenceByNumber(ctx context.Context, licenceType, number string) (*domain.Licence, error) {
+	for _, licence := range m.licences {
+		if licence.Type == licenceType && licence.Number == number {
+			return licence, nil
+		}
+	}
+	return nil, domain.ErrLicenceNotFound
+}
+
+func (m *mockPractitioners) DeleteLicence(ctx context.Context, practitionerID, id string) error {
+	return nil
+}
+
+func (m *mockPractitioners) ExpiringLicences(ctx context.Context, until time.Time) ([]*domain.ExpiringLicence, error) {
+	return nil, nil
+}
+
+func (m *mockPractitioners) AddAssignment(ctx context.Context, assignment *domain.Assignment) error {
+	assignment.ID = m.id()
+	assignment.TenantID, _ = tenant.FromContext(ctx)
+	m.assignments = append(m.assignments, assignment)
+	return nil
+}
+
+func (m *mockPractitioners) EndAssignment(ctx context.Context, practitionerID, id string, endDate time.Time) error {
+	for _, assignment := range m.assignments {
+		if assignment.ID == id {
+			assignment.EndDate = &endDate
+			return nil
+		}
+	}
+	return domain.ErrAssignmentNotFound
+}
+
+func (m *mockPractitioners) HasActiveAssignments(ctx context.Context, organizationID string) (bool, error) {
+	for _, assignment := range m.assignments {
+		if assignment.OrganizationID == organizationID && assignment.ActiveOn(time.Now()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newTestPractitionerService() (PractitionerService, *mockPractitioners) {
+	practitioners := newMockPractitioners(&domain.Practitioner{
+		ID: "p1", UserID: "u-budi", Role: domain.RoleDoctor, PrefixTitle: "dr.",
+		FirstName: "Budi", LastName: "Santoso", SuffixTitle: "Sp.PD",
+	}, &domain.Practitioner{
+		ID: "p2", UserID: "u-sari", Role: domain.RoleNurse, PrefixTitle: "Ns.", FirstName: "Sari",
+	})
+	return NewPractitionerService(practitioners, medicalServices()), practitioners
+}
+
+func TestLookupUsers(t *testing.T) {
+	svc, _ := newTestPractitionerService()
+	ctx := context.Background()
+
+	names, err := svc.LookupUsers(ctx, []string{"u-budi", "unknown", "u-budi", "u-sari", ""})
+	if err != nil {
+		t.Fatalf("LookupUsers failed: %v", err)
+	}
+	if len(names) != 2 {
+		t.Fatalf("Expected two names, got %d", len(names))
+	}
+	if got := names[0]; got.UserID != "u-budi" || got.PractitionerID != "p1" || got.Name != "Budi Santoso" || got.DisplayName != "dr. Budi Santoso, Sp.PD" {
+		t.Errorf("Unexpected name %+v", got)
+	}
+	if got := names[1].DisplayName; got != "Ns. Sari" {
+		t.Errorf("Expected Ns. Sari, got %q", got)
+	}
+
+	tooMany := make([]string, MaxLookupUsers+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("u%d", i)
+	}
+	if _, err := svc.LookupUsers(ctx, tooMany); err == nil {
+		t.Error("Expected too many user IDs to be rejected")
+	}
+}
+
+func TestCreatePractitionerRejectsDuplicateUserID(t *testing.T) {
+	svc, _ := newTestPractitionerService()
+
+	_, err := svc.CreatePractitioner(context.Background(), &domain.Practitioner{
+		UserID: "u-budi", Role: domain.RoleDoctor, FirstName: "Budi",
+	})
+	if err != domain.ErrPractitionerExists {
+		t.Errorf("Expected ErrPractitionerExists, got %v", err)
+	}
+
+	if _, err := svc.UpdatePractitioner(context.Background(), &domain.Practitioner{
+		ID: "p1", UserID: "u-budi", Role: domain.RoleDoctor, FirstName: "Budi", IsActive: true,
+	}); err != nil {
+		t.Errorf("Expected a practitioner to keep their own user ID, got %v", err)
+	}
+}
+
+func TestListPractitionersIncludesSubOrganizations(t *testing.T) {
+	svc, practitioners := newTestPractitionerService()
+
+	if _, _, err := svc.ListPractitioners(context.Background(), domain.PractitionerFilter{OrganizationIDs: []string{"yanmed"}}); err != nil {
+		t.Fatalf("ListPractitioners failed: %v", err)
+	}
+	got := practitioners.lastFilter
+	if len(got.OrganizationIDs) != 3 || got.OrganizationIDs[0] != "yanmed" {
+		t.Errorf("Expected yanmed and the two organizations below it, got %v", got.OrganizationIDs)
+	}
+	if got.Page != 1 || got.Limit != 10 {
+		t.Errorf("Expected default pagination, got page %d limit %d", got.Page, got.Limit)
+	}
+}
+
+func TestAddLicence(t *testing.T) {
+	svc, _ := newTestPractitionerService()
+	ctx := tenant.NewContext(context.Background(), "rs-pusat")
+	issued := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	expires := issued.AddDate(5, 0, 0)
+
+	sip, err := svc.AddLicence(ctx, &domain.Licence{
+		PractitionerID: "p1", Type: domain.LicenceSIP, Number: "503/SIP/001", IssuedAt: issued, ExpiresAt: &expires,
+	})
+	if err != nil {
+		t.Fatalf("AddLicence failed: %v", err)
+	}
+	if sip.TenantID != "rs-pusat" {
+		t.Errorf("Expected the SIP to be issued for the caller's facility, got %q", sip.TenantID)
+	}
+
+	tests := []struct {
+		name    string
+		licence *domain.Licence
+		want    error
+	}{
+		{"duplicate", &domain.Licence{PractitionerID: "p2", Type: domain.LicenceSIP, Number: "503/SIP/001", IssuedAt: issued}, domain.ErrLicenceExists},
+		{"unknown practitioner", &domain.Licence{PractitionerID: "p9", Type: domain.LicenceSTR, Number: "STR-9", IssuedAt: issued}, domain.ErrPractitionerNotFound},
+	}
+	for _, tt := range tests {
+		if _, err := svc.AddLicence(ctx, tt.licence); err != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	before := issued.AddDate(0, 0, -1)
+	if _, err := svc.AddLicence(ctx, &domain.Licence{
+		PractitionerID: "p1", Type: domain.LicenceSTR, Number: "STR-1", IssuedAt: issued, ExpiresAt: &before,
+	}); err == nil {
+		t.Error("Expected a licence expiring before it was issued to be rejected")
+	}
+}
+
+func TestLicenceStatus(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	warning := 30 * 24 * time.Hour
+	date := func(y int, m time.Month, d int) *time.Time {
+		t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		expires *time.Time
+		want    string
+	}{
+		{nil, domain.LicenceActive},
+		{date(2024, 5, 31), domain.LicenceExpired},
+		{date(2024, 6, 1), domain.LicenceExpiring},
+		{date(2024, 7, 1), domain.LicenceExpiring},
+		{date(2024, 7, 2), domain.LicenceActive},
+	}
+	for _, tt := range tests {
+		licence := &domain.Licence{Type: domain.LicenceSTR, ExpiresAt: tt.expires}
+		if got := licence.Status(now, warning); got != tt.want {
+			t.Errorf("Expires %v: got %s, want %s", tt.expires, got, tt.want)
+		}
+	}
+}
+
+func TestEndAssignmentOfOtherFacility(t *testing.T) {
+	svc, _ := newTestPractitionerService()
+	pusat := tenant.NewContext(context.Background(), "rs-pusat")
+	cabang := tenant.NewContext(context.Background(), "rs-cabang")
+
+	assignment, err := svc.Assign(pusat, &domain.Assignment{
+		PractitionerID: "p1", OrganizationID: "poli-pd", StartDate: time.Now().AddDate(0, -1, 0),
+	})
+	if err != nil {
+		t.Fatalf("Assign failed: %v", err)
+	}
+
+	today := time.Now()
+	if err := svc.EndAssignment(cabang, "p1", assignment.ID, today); err != domain.ErrAssignmentNotFound {
+		t.Errorf("Expected another facility's assignment not to be found, got %v", err)
+	}
+	if err := svc.EndAssignment(pusat, "p1", assignment.ID, today); err != nil {
+		t.Fatalf("EndAssignment failed: %v", err)
+	}
+	if assignment.EndDate == nil {
+		t.Error("Expected the assignment to have ended")
+	}
+
+	if _, err := svc.Assign(pusat, &domain.Assignment{PractitionerID: "p1", OrganizationID: "missing", StartDate: today}); err == nil {
+		t.Error("Expected an unknown organization to be rejected")
+	}
+}
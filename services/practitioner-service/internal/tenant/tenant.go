@@ -0,0 +1,116 @@
+// Tenants (facilities) sharing the service
+// internal/tenant/tenant.go
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Tenant is a hospital or clinic of the group. The same tenants file as
+// patient-service's can be used; settings only it knows about are ignored.
+type Tenant struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Registry holds the configured tenants. A nil Registry has none.
+type Registry struct {
+	tenants   map[string]*Tenant
+	defaultID string
+}
+
+// NewRegistry returns a registry of the tenants. Tokens without a tenant
+// claim act for defaultID, which is added if missing; an empty defaultID
+// makes the claim required.
+func NewRegistry(defaultID string, tenants ...*Tenant) (*Registry, error) {
+	r := &Registry{tenants: make(map[string]*Tenant), defaultID: defaultID}
+	for _, t := range tenants {
+		if err := validID(t.ID); err != nil {
+			return nil, err
+		}
+		if _, ok := r.tenants[t.ID]; ok {
+			return nil, fmt.Errorf("duplicate tenant %q", t.ID)
+		}
+		r.tenants[t.ID] = t
+	}
+	if defaultID != "" {
+		if err := validID(defaultID); err != nil {
+			return nil, err
+		}
+		if _, ok := r.tenants[defaultID]; !ok {
+			r.tenants[defaultID] = &Tenant{ID: defaultID}
+		}
+	}
+	return r, nil
+}
+
+// Load reads tenants from a JSON file holding a list of tenants. An empty
+// path configures the default tenant only.
+func Load(path, defaultID string) (*Registry, error) {
+	if path == "" {
+		return NewRegistry(defaultID)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants: %w", err)
+	}
+	var tenants []*Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("invalid tenants: %w", err)
+	}
+	return NewRegistry(defaultID, tenants...)
+}
+
+// validID keeps IDs short enough for the tenant_id columns and free of the
+// separators used in events and logs.
+func validID(id string) error {
+	if id == "" || len(id) > 50 || strings.ContainsAny(id, " ./:") {
+		return fmt.Errorf("invalid tenant ID %q", id)
+	}
+	return nil
+}
+
+// Get returns the tenant with the ID.
+func (r *Registry) Get(id string) (*Tenant, bool) {
+	if r == nil {
+		return nil, false
+	}
+	t, ok := r.tenants[id]
+	return t, ok
+}
+
+// Resolve returns the tenant a token's claim acts for: the claimed one, or
+// the default tenant when the claim is empty.
+func (r *Registry) Resolve(claim string) (*Tenant, bool) {
+	if claim == "" {
+		if r == nil || r.defaultID == "" {
+			return nil, false
+		}
+		claim = r.defaultID
+	}
+	return r.Get(claim)
+}
+
+// contextKey is the type of ContextKey.
+type contextKey struct{}
+
+// ContextKey holds the ID of the tenant a request acts for. The REST
+// middleware stores it as a request local, which fasthttp exposes through
+// the context handlers pass to services.
+var ContextKey = contextKey{}
+
+// NewContext returns a context acting for the tenant, e.g. in tests.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ContextKey, id)
+}
+
+// FromContext returns the tenant the context acts for.
+func FromContext(ctx context.Context) (string, bool) {
+	id, _ := ctx.Value(ContextKey).(string)
+	return id, id != ""
+}